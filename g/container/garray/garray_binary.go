@@ -0,0 +1,205 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package garray
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/internal/rwmutex"
+)
+
+// The binary encoding of arrays is:
+// version(1 byte) + flags(1 byte) + count(uvarint) + elements,
+// in which the int elements are zig-zag varints, and the string elements are uvarint length + bytes.
+const (
+	gBINARY_VERSION     = 1
+	gBINARY_FLAG_UNIQUE = 1 // The array is a unique SortedIntArray.
+)
+
+var errInvalidBinary = errors.New("invalid array binary data")
+
+// MarshalBinary implements the interface encoding.BinaryMarshaler,
+// which serializes the array to compact bytes. It's also used by encoding/gob.
+func (a *IntArray) MarshalBinary() ([]byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return encodeInts(a.array, 0), nil
+}
+
+// UnmarshalBinary implements the interface encoding.BinaryUnmarshaler,
+// which restores the array from bytes produced by MarshalBinary. It's also used by encoding/gob.
+// It can be called on a zero value IntArray, which is concurrent-safe.
+func (a *IntArray) UnmarshalBinary(data []byte) error {
+	array, _, err := decodeInts(data)
+	if err != nil {
+		return err
+	}
+	if a.mu == nil {
+		a.mu = rwmutex.New()
+	}
+	a.mu.Lock()
+	a.array = array
+	a.mu.Unlock()
+	return nil
+}
+
+// MarshalBinary implements the interface encoding.BinaryMarshaler,
+// which serializes the array to compact bytes. It's also used by encoding/gob.
+// The unique feature of the array is kept, but the comparator is not.
+func (a *SortedIntArray) MarshalBinary() ([]byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	flags := byte(0)
+	if a.unique.Val() {
+		flags |= gBINARY_FLAG_UNIQUE
+	}
+	return encodeInts(a.array, flags), nil
+}
+
+// UnmarshalBinary implements the interface encoding.BinaryUnmarshaler,
+// which restores the array from bytes produced by MarshalBinary. It's also used by encoding/gob.
+// It can be called on a zero value SortedIntArray, which is concurrent-safe and in increasing order.
+func (a *SortedIntArray) UnmarshalBinary(data []byte) error {
+	array, flags, err := decodeInts(data)
+	if err != nil {
+		return err
+	}
+	if a.mu == nil {
+		*a = *NewSortedIntArraySize(0)
+	}
+	sort.Ints(array)
+	if flags&gBINARY_FLAG_UNIQUE > 0 {
+		array = uniqueSortedInts(array)
+	}
+	a.mu.Lock()
+	a.array = array
+	a.unique = gtype.NewBool(flags&gBINARY_FLAG_UNIQUE > 0)
+	a.mu.Unlock()
+	return nil
+}
+
+// MarshalBinary implements the interface encoding.BinaryMarshaler,
+// which serializes the array to compact bytes. It's also used by encoding/gob.
+func (a *StringArray) MarshalBinary() ([]byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	size := 2 + binary.MaxVarintLen64
+	for _, v := range a.array {
+		size += binary.MaxVarintLen64 + len(v)
+	}
+	data := make([]byte, 2, size)
+	data[0] = gBINARY_VERSION
+	data = appendUvarint(data, uint64(len(a.array)))
+	for _, v := range a.array {
+		data = appendUvarint(data, uint64(len(v)))
+		data = append(data, v...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements the interface encoding.BinaryUnmarshaler,
+// which restores the array from bytes produced by MarshalBinary. It's also used by encoding/gob.
+// It can be called on a zero value StringArray, which is concurrent-safe.
+func (a *StringArray) UnmarshalBinary(data []byte) error {
+	count, offset, err := decodeHeader(data)
+	if err != nil {
+		return err
+	}
+	array := make([]string, count)
+	for i := range array {
+		length, n := binary.Uvarint(data[offset:])
+		if n <= 0 || length > uint64(len(data)-offset-n) {
+			return errInvalidBinary
+		}
+		offset += n
+		array[i] = string(data[offset : offset+int(length)])
+		offset += int(length)
+	}
+	if offset != len(data) {
+		return errInvalidBinary
+	}
+	if a.mu == nil {
+		a.mu = rwmutex.New()
+	}
+	a.mu.Lock()
+	a.array = array
+	a.mu.Unlock()
+	return nil
+}
+
+// encodeInts encodes int elements <array> with <flags>.
+func encodeInts(array []int, flags byte) []byte {
+	data := make([]byte, 2, 2+binary.MaxVarintLen64*(len(array)+1))
+	data[0] = gBINARY_VERSION
+	data[1] = flags
+	data = appendUvarint(data, uint64(len(array)))
+	buffer := make([]byte, binary.MaxVarintLen64)
+	for _, v := range array {
+		n := binary.PutVarint(buffer, int64(v))
+		data = append(data, buffer[:n]...)
+	}
+	return data
+}
+
+// decodeInts decodes int elements and flags from <data> produced by encodeInts.
+func decodeInts(data []byte) (array []int, flags byte, err error) {
+	count, offset, err := decodeHeader(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	array = make([]int, count)
+	for i := range array {
+		v, n := binary.Varint(data[offset:])
+		if n <= 0 {
+			return nil, 0, errInvalidBinary
+		}
+		array[i] = int(v)
+		offset += n
+	}
+	if offset != len(data) {
+		return nil, 0, errInvalidBinary
+	}
+	return array, data[1], nil
+}
+
+// decodeHeader checks the version of <data>, and returns the count of elements and the offset of the first element.
+func decodeHeader(data []byte) (count int, offset int, err error) {
+	if len(data) < 3 || data[0] != gBINARY_VERSION {
+		return 0, 0, errInvalidBinary
+	}
+	v, n := binary.Uvarint(data[2:])
+	// Each element takes at least 1 byte, which avoids huge allocation of corrupted data.
+	if n <= 0 || v > uint64(len(data)-2-n) {
+		return 0, 0, errInvalidBinary
+	}
+	return int(v), 2 + n, nil
+}
+
+// appendUvarint appends the uvarint encoding of <v> to <data>.
+func appendUvarint(data []byte, v uint64) []byte {
+	buffer := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buffer, v)
+	return append(data, buffer[:n]...)
+}
+
+// uniqueSortedInts removes the repeated items of sorted <array> in place.
+func uniqueSortedInts(array []int) []int {
+	if len(array) == 0 {
+		return array
+	}
+	i := 0
+	for _, v := range array[1:] {
+		if v != array[i] {
+			i++
+			array[i] = v
+		}
+	}
+	return array[:i+1]
+}
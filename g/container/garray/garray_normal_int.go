@@ -13,6 +13,7 @@ import (
 	"sort"
 
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 	"github.com/gf/g/util/grand"
 )
@@ -360,6 +361,17 @@ func (a *IntArray) Len() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the array to gmetrics, which is sampled on collecting.
+// It does nothing if the array is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the array can be counted by wrapping it with gmetrics.Wrap.
+func (a *IntArray) Instrument(name string) {
+	if a.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(a.Len())
+		})
+	}
+}
+
 // Slice returns the underlying data of array.
 // Notice, if in concurrent-safe usage, it returns a copy of slice;
 // else a pointer to the underlying data.
@@ -385,6 +397,11 @@ func (a *IntArray) Clone() (newArray *IntArray) {
 	return NewIntArrayFrom(array, !a.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (a *IntArray) DeepCopy() interface{} {
+	return a.Clone()
+}
+
 // Clear deletes all items of current array.
 func (a *IntArray) Clear() *IntArray {
 	a.mu.Lock()
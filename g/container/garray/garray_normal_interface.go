@@ -12,7 +12,9 @@ import (
 	"math"
 	"sort"
 
+	"github.com/gf/g/internal/deepcopy"
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 	"github.com/gf/g/util/grand"
 )
@@ -354,6 +356,17 @@ func (a *Array) Len() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the array to gmetrics, which is sampled on collecting.
+// It does nothing if the array is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the array can be counted by wrapping it with gmetrics.Wrap.
+func (a *Array) Instrument(name string) {
+	if a.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(a.Len())
+		})
+	}
+}
+
 // Slice returns the underlying data of array.
 // Notice, if in concurrent-safe usage, it returns a copy of slice;
 // else a pointer to the underlying data.
@@ -379,6 +392,17 @@ func (a *Array) Clone() (newArray *Array) {
 	return NewArrayFrom(array, !a.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (a *Array) DeepCopy() interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	newSlice := make([]interface{}, len(a.array))
+	for i, v := range a.array {
+		newSlice[i] = deepcopy.Copy(v)
+	}
+	return NewArrayFrom(newSlice, !a.mu.IsSafe())
+}
+
 // Clear deletes all items of current array.
 func (a *Array) Clear() *Array {
 	a.mu.Lock()
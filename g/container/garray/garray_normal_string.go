@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 	"github.com/gf/g/util/grand"
 )
@@ -361,6 +362,17 @@ func (a *StringArray) Len() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the array to gmetrics, which is sampled on collecting.
+// It does nothing if the array is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the array can be counted by wrapping it with gmetrics.Wrap.
+func (a *StringArray) Instrument(name string) {
+	if a.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(a.Len())
+		})
+	}
+}
+
 // Slice returns the underlying data of array.
 // Notice, if in concurrent-safe usage, it returns a copy of slice;
 // else a pointer to the underlying data.
@@ -386,6 +398,11 @@ func (a *StringArray) Clone() (newArray *StringArray) {
 	return NewStringArrayFrom(array, !a.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (a *StringArray) DeepCopy() interface{} {
+	return a.Clone()
+}
+
 // Clear deletes all items of current array.
 func (a *StringArray) Clear() *StringArray {
 	a.mu.Lock()
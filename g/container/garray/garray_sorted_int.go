@@ -14,6 +14,7 @@ import (
 
 	"github.com/gf/g/container/gtype"
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 	"github.com/gf/g/util/grand"
 )
@@ -302,6 +303,17 @@ func (a *SortedIntArray) Len() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the array to gmetrics, which is sampled on collecting.
+// It does nothing if the array is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the array can be counted by wrapping it with gmetrics.Wrap.
+func (a *SortedIntArray) Instrument(name string) {
+	if a.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(a.Len())
+		})
+	}
+}
+
 // Sum returns the sum of values in an array.
 func (a *SortedIntArray) Sum() (sum int) {
 	a.mu.RLock()
@@ -413,6 +425,20 @@ func (a *SortedIntArray) Clone() (newArray *SortedIntArray) {
 	return NewSortedIntArrayFrom(array, !a.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (a *SortedIntArray) DeepCopy() interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	newSlice := make([]int, len(a.array))
+	copy(newSlice, a.array)
+	return &SortedIntArray{
+		mu:         rwmutex.New(!a.mu.IsSafe()),
+		array:      newSlice,
+		unique:     gtype.NewBool(a.unique.Val()),
+		comparator: a.comparator,
+	}
+}
+
 // Clear deletes all items of current array.
 func (a *SortedIntArray) Clear() *SortedIntArray {
 	a.mu.Lock()
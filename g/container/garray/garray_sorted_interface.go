@@ -13,7 +13,9 @@ import (
 	"sort"
 
 	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/internal/deepcopy"
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 	"github.com/gf/g/util/grand"
 )
@@ -313,6 +315,17 @@ func (a *SortedArray) Len() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the array to gmetrics, which is sampled on collecting.
+// It does nothing if the array is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the array can be counted by wrapping it with gmetrics.Wrap.
+func (a *SortedArray) Instrument(name string) {
+	if a.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(a.Len())
+		})
+	}
+}
+
 // Slice returns the underlying data of array.
 // Notice, if in concurrent-safe usage, it returns a copy of slice;
 // else a pointer to the underlying data.
@@ -414,6 +427,22 @@ func (a *SortedArray) Clone() (newArray *SortedArray) {
 	return NewSortedArrayFrom(array, a.comparator, !a.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (a *SortedArray) DeepCopy() interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	newSlice := make([]interface{}, len(a.array))
+	for i, v := range a.array {
+		newSlice[i] = deepcopy.Copy(v)
+	}
+	return &SortedArray{
+		mu:         rwmutex.New(!a.mu.IsSafe()),
+		array:      newSlice,
+		unique:     gtype.NewBool(a.unique.Val()),
+		comparator: a.comparator,
+	}
+}
+
 // Clear deletes all items of current array.
 func (a *SortedArray) Clear() *SortedArray {
 	a.mu.Lock()
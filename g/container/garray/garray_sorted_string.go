@@ -15,6 +15,7 @@ import (
 
 	"github.com/gf/g/container/gtype"
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 	"github.com/gf/g/util/grand"
 )
@@ -307,6 +308,17 @@ func (a *SortedStringArray) Len() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the array to gmetrics, which is sampled on collecting.
+// It does nothing if the array is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the array can be counted by wrapping it with gmetrics.Wrap.
+func (a *SortedStringArray) Instrument(name string) {
+	if a.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(a.Len())
+		})
+	}
+}
+
 // Slice returns the underlying data of array.
 // Notice, if in concurrent-safe usage, it returns a copy of slice;
 // else a pointer to the underlying data.
@@ -408,6 +420,20 @@ func (a *SortedStringArray) Clone() (newArray *SortedStringArray) {
 	return NewSortedStringArrayFrom(array, !a.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (a *SortedStringArray) DeepCopy() interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	newSlice := make([]string, len(a.array))
+	copy(newSlice, a.array)
+	return &SortedStringArray{
+		mu:         rwmutex.New(!a.mu.IsSafe()),
+		array:      newSlice,
+		unique:     gtype.NewBool(a.unique.Val()),
+		comparator: a.comparator,
+	}
+}
+
 // Clear deletes all items of current array.
 func (a *SortedStringArray) Clear() *SortedStringArray {
 	a.mu.Lock()
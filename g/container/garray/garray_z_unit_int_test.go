@@ -9,6 +9,8 @@
 package garray_test
 
 import (
+	"bytes"
+	"encoding/gob"
 	"testing"
 
 	"github.com/gogf/gf/g/container/garray"
@@ -621,3 +623,52 @@ func TestIntArray_Remove(t *testing.T) {
 		gtest.Assert(array1.Len(), 2)
 	})
 }
+
+func TestIntArray_Binary(t *testing.T) {
+	gtest.Case(t, func() {
+		a1 := garray.NewIntArrayFrom([]int{3, -1, 0, 1 << 40, -300})
+		data, err := a1.MarshalBinary()
+		gtest.Assert(err, nil)
+		a2 := garray.NewIntArray()
+		gtest.Assert(a2.UnmarshalBinary(data), nil)
+		gtest.Assert(a2.Slice(), a1.Slice())
+
+		var a3 garray.IntArray
+		gtest.Assert(a3.UnmarshalBinary(data), nil)
+		gtest.Assert(a3.Slice(), a1.Slice())
+
+		gtest.AssertNE(a3.UnmarshalBinary(data[:len(data)-1]), nil)
+		gtest.AssertNE(a3.UnmarshalBinary(nil), nil)
+	})
+	// Gob.
+	gtest.Case(t, func() {
+		a1 := garray.NewIntArrayFrom([]int{1, 2, 3})
+		buffer := bytes.NewBuffer(nil)
+		gtest.Assert(gob.NewEncoder(buffer).Encode(a1), nil)
+		a2 := garray.NewIntArray()
+		gtest.Assert(gob.NewDecoder(buffer).Decode(a2), nil)
+		gtest.Assert(a2.Slice(), []int{1, 2, 3})
+	})
+}
+
+func TestSortedIntArray_Binary(t *testing.T) {
+	gtest.Case(t, func() {
+		a1 := garray.NewSortedIntArrayFrom([]int{3, 1, 2, 2})
+		data, err := a1.MarshalBinary()
+		gtest.Assert(err, nil)
+		var a2 garray.SortedIntArray
+		gtest.Assert(a2.UnmarshalBinary(data), nil)
+		gtest.Assert(a2.Slice(), []int{1, 2, 2, 3})
+		a2.Add(0)
+		gtest.Assert(a2.Slice(), []int{0, 1, 2, 2, 3})
+
+		a1.SetUnique(true)
+		data, err = a1.MarshalBinary()
+		gtest.Assert(err, nil)
+		a3 := garray.NewSortedIntArray()
+		gtest.Assert(a3.UnmarshalBinary(data), nil)
+		gtest.Assert(a3.Slice(), []int{1, 2, 3})
+		a3.Add(3)
+		gtest.Assert(a3.Slice(), []int{1, 2, 3})
+	})
+}
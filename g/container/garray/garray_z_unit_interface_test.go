@@ -10,6 +10,7 @@ package garray_test
 
 import (
 	"github.com/gogf/gf/g/container/garray"
+	"github.com/gogf/gf/g/container/gmap"
 	"github.com/gogf/gf/g/test/gtest"
 	"github.com/gogf/gf/g/util/gconv"
 	"strings"
@@ -676,3 +677,30 @@ func TestSortedArray_SetUnique(t *testing.T) {
 		gtest.Assert(array1, []interface{}{"a", "c", "d"})
 	})
 }
+
+func TestArray_DeepCopy(t *testing.T) {
+	gtest.Case(t, func() {
+		m := gmap.NewStrAnyMap()
+		m.Set("k", "v")
+		plain := map[string]interface{}{"list": []interface{}{1, 2}}
+		array1 := garray.NewArrayFrom([]interface{}{1, m, plain})
+		array2 := array1.DeepCopy().(*garray.Array)
+		gtest.Assert(array2.Len(), 3)
+		gtest.Assert(array2.Get(0), 1)
+
+		// Nested containers and plain maps/slices are copied.
+		m.Set("k", "v2")
+		plain["list"].([]interface{})[0] = 100
+		gtest.Assert(array2.Get(1).(*gmap.StrAnyMap).Get("k"), "v")
+		gtest.Assert(array2.Get(2).(map[string]interface{})["list"], []interface{}{1, 2})
+	})
+	gtest.Case(t, func() {
+		array1 := garray.NewSortedIntArrayFrom([]int{3, 1, 2})
+		array1.SetUnique(true)
+		array2 := array1.DeepCopy().(*garray.SortedIntArray)
+		array1.Add(4)
+		gtest.Assert(array2.Slice(), []int{1, 2, 3})
+		array2.Add(3)
+		gtest.Assert(array2.Slice(), []int{1, 2, 3})
+	})
+}
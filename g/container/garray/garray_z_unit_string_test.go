@@ -637,3 +637,17 @@ func TestStringArray_Remove(t *testing.T) {
 
 	})
 }
+
+func TestStringArray_Binary(t *testing.T) {
+	gtest.Case(t, func() {
+		a1 := garray.NewStringArrayFrom([]string{"a", "", "中文", strings.Repeat("x", 300)})
+		data, err := a1.MarshalBinary()
+		gtest.Assert(err, nil)
+		var a2 garray.StringArray
+		gtest.Assert(a2.UnmarshalBinary(data), nil)
+		gtest.Assert(a2.Slice(), a1.Slice())
+
+		gtest.AssertNE(a2.UnmarshalBinary(data[:len(data)-1]), nil)
+		gtest.AssertNE(a2.UnmarshalBinary([]byte{1, 0, 100}), nil)
+	})
+}
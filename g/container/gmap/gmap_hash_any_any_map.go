@@ -8,7 +8,9 @@ package gmap
 
 import (
 	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/deepcopy"
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 )
 
 type AnyAnyMap struct {
@@ -53,6 +55,20 @@ func (m *AnyAnyMap) Clone(unsafe ...bool) *AnyAnyMap {
 	return NewFrom(m.Map(), unsafe...)
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (m *AnyAnyMap) DeepCopy() interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[interface{}]interface{}, len(m.data))
+	for k, v := range m.data {
+		data[k] = deepcopy.Copy(v)
+	}
+	return &AnyAnyMap{
+		mu:   rwmutex.New(!m.mu.IsSafe()),
+		data: data,
+	}
+}
+
 // Map returns a copy of the data of the hash map.
 func (m *AnyAnyMap) Map() map[interface{}]interface{} {
 	m.mu.RLock()
@@ -274,6 +290,17 @@ func (m *AnyAnyMap) Size() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the map to gmetrics, which is sampled on collecting.
+// It does nothing if the map is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the map can be counted by wrapping it with gmetrics.Wrap.
+func (m *AnyAnyMap) Instrument(name string) {
+	if m.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(m.Size())
+		})
+	}
+}
+
 // IsEmpty checks whether the map is empty.
 // It returns true if map is empty, or else false.
 func (m *AnyAnyMap) IsEmpty() bool {
@@ -9,7 +9,9 @@ package gmap
 
 import (
 	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/deepcopy"
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 )
 
@@ -55,6 +57,20 @@ func (m *IntAnyMap) Clone() *IntAnyMap {
 	return NewIntAnyMapFrom(m.Map(), !m.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (m *IntAnyMap) DeepCopy() interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[int]interface{}, len(m.data))
+	for k, v := range m.data {
+		data[k] = deepcopy.Copy(v)
+	}
+	return &IntAnyMap{
+		mu:   rwmutex.New(!m.mu.IsSafe()),
+		data: data,
+	}
+}
+
 // Map returns a copy of the data of the hash map.
 func (m *IntAnyMap) Map() map[int]interface{} {
 	m.mu.RLock()
@@ -276,6 +292,17 @@ func (m *IntAnyMap) Size() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the map to gmetrics, which is sampled on collecting.
+// It does nothing if the map is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the map can be counted by wrapping it with gmetrics.Wrap.
+func (m *IntAnyMap) Instrument(name string) {
+	if m.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(m.Size())
+		})
+	}
+}
+
 // IsEmpty checks whether the map is empty.
 // It returns true if map is empty, or else false.
 func (m *IntAnyMap) IsEmpty() bool {
@@ -8,6 +8,7 @@ package gmap
 
 import (
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 )
 
 type IntIntMap struct {
@@ -52,6 +53,11 @@ func (m *IntIntMap) Clone() *IntIntMap {
 	return NewIntIntMapFrom(m.Map(), !m.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (m *IntIntMap) DeepCopy() interface{} {
+	return m.Clone()
+}
+
 // Map returns a copy of the data of the hash map.
 func (m *IntIntMap) Map() map[int]int {
 	m.mu.RLock()
@@ -252,6 +258,17 @@ func (m *IntIntMap) Size() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the map to gmetrics, which is sampled on collecting.
+// It does nothing if the map is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the map can be counted by wrapping it with gmetrics.Wrap.
+func (m *IntIntMap) Instrument(name string) {
+	if m.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(m.Size())
+		})
+	}
+}
+
 // IsEmpty checks whether the map is empty.
 // It returns true if map is empty, or else false.
 func (m *IntIntMap) IsEmpty() bool {
@@ -9,6 +9,7 @@ package gmap
 
 import (
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 )
 
@@ -54,6 +55,11 @@ func (m *StrIntMap) Clone() *StrIntMap {
 	return NewStrIntMapFrom(m.Map(), !m.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (m *StrIntMap) DeepCopy() interface{} {
+	return m.Clone()
+}
+
 // Map returns a copy of the data of the hash map.
 func (m *StrIntMap) Map() map[string]int {
 	m.mu.RLock()
@@ -256,6 +262,17 @@ func (m *StrIntMap) Size() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the map to gmetrics, which is sampled on collecting.
+// It does nothing if the map is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the map can be counted by wrapping it with gmetrics.Wrap.
+func (m *StrIntMap) Instrument(name string) {
+	if m.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(m.Size())
+		})
+	}
+}
+
 // IsEmpty checks whether the map is empty.
 // It returns true if map is empty, or else false.
 func (m *StrIntMap) IsEmpty() bool {
@@ -9,6 +9,7 @@ package gmap
 
 import (
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 )
 
 type StrStrMap struct {
@@ -53,6 +54,11 @@ func (m *StrStrMap) Clone() *StrStrMap {
 	return NewStrStrMapFrom(m.Map(), !m.mu.IsSafe())
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (m *StrStrMap) DeepCopy() interface{} {
+	return m.Clone()
+}
+
 // Map returns a copy of the data of the hash map.
 func (m *StrStrMap) Map() map[string]string {
 	m.mu.RLock()
@@ -255,6 +261,17 @@ func (m *StrStrMap) Size() int {
 	return length
 }
 
+// Instrument registers the "<name>.size" gauge of the map to gmetrics, which is sampled on collecting.
+// It does nothing if the map is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the map can be counted by wrapping it with gmetrics.Wrap.
+func (m *StrStrMap) Instrument(name string) {
+	if m.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(m.Size())
+		})
+	}
+}
+
 // IsEmpty checks whether the map is empty.
 // It returns true if map is empty, or else false.
 func (m *StrStrMap) IsEmpty() bool {
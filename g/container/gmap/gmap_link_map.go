@@ -9,7 +9,9 @@ package gmap
 import (
 	"github.com/gf/g/container/glist"
 	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/deepcopy"
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 )
 
 type ListMap struct {
@@ -78,6 +80,16 @@ func (m *ListMap) Clone(unsafe ...bool) *ListMap {
 	return NewListMapFrom(m.Map(), unsafe...)
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (m *ListMap) DeepCopy() interface{} {
+	newMap := NewListMap(!m.mu.IsSafe())
+	m.IteratorAsc(func(key, value interface{}) bool {
+		newMap.Set(key, deepcopy.Copy(value))
+		return true
+	})
+	return newMap
+}
+
 // Clear deletes all data of the map, it will remake a new underlying data map.
 func (m *ListMap) Clear() {
 	m.mu.Lock()
@@ -329,6 +341,17 @@ func (m *ListMap) Size() (size int) {
 	return
 }
 
+// Instrument registers the "<name>.size" gauge of the map to gmetrics, which is sampled on collecting.
+// It does nothing if the map is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the map can be counted by wrapping it with gmetrics.Wrap.
+func (m *ListMap) Instrument(name string) {
+	if m.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(m.Size())
+		})
+	}
+}
+
 // IsEmpty checks whether the map is empty.
 // It returns true if map is empty, or else false.
 func (m *ListMap) IsEmpty() bool {
@@ -118,3 +118,16 @@ func Test_List_Map_Order(t *testing.T) {
 	gtest.Assert(m.Keys(), g.Slice{"k1", "k2", "k3"})
 	gtest.Assert(m.Values(), g.Slice{"v1", "v2", "v3"})
 }
+
+func Test_List_Map_DeepCopy(t *testing.T) {
+	gtest.Case(t, func() {
+		m1 := gmap.NewListMap()
+		m1.Set("b", g.Slice{1})
+		m1.Set("a", g.Map{"k": "v"})
+		m2 := m1.DeepCopy().(*gmap.ListMap)
+		gtest.Assert(m2.Keys(), []interface{}{"b", "a"})
+
+		m1.Get("a").(g.Map)["k"] = "v2"
+		gtest.Assert(m2.Get("a"), g.Map{"k": "v"})
+	})
+}
@@ -126,3 +126,20 @@ func Test_StrAnyMap_Merge(t *testing.T) {
 	m1.Merge(m2)
 	gtest.Assert(m1.Map(), map[string]interface{}{"a": 1, "b": "2"})
 }
+
+func Test_StrAnyMap_DeepCopy(t *testing.T) {
+	gtest.Case(t, func() {
+		nested := gmap.NewIntStrMap()
+		nested.Set(1, "a")
+		m1 := gmap.NewStrAnyMap()
+		m1.Set("nested", nested)
+		m1.Set("slice", []int{1, 2, 3})
+		m2 := m1.DeepCopy().(*gmap.StrAnyMap)
+
+		nested.Set(1, "b")
+		m1.Get("slice").([]int)[0] = 100
+		gtest.Assert(m2.Get("nested").(*gmap.IntStrMap).Get(1), "a")
+		gtest.Assert(m2.Get("slice"), []int{1, 2, 3})
+		gtest.Assert(m1.Get("slice"), []int{100, 2, 3})
+	})
+}
@@ -8,9 +8,12 @@
 package gset
 
 import (
+	"encoding/json"
+	"errors"
 	"strings"
 
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 )
 
@@ -94,6 +97,17 @@ func (set *Set) Size() int {
 	return l
 }
 
+// Instrument registers the "<name>.size" gauge of the set to gmetrics, which is sampled on collecting.
+// It does nothing if the set is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the set can be counted by wrapping it with gmetrics.Wrap.
+func (set *Set) Instrument(name string) {
+	if set.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(set.Size())
+		})
+	}
+}
+
 // Clear deletes all items of the set.
 func (set *Set) Clear() *Set {
 	set.mu.Lock()
@@ -324,3 +338,50 @@ func (set *Set) Pops(size int) []interface{} {
 	}
 	return array
 }
+
+// DeepCopy implements interface for deep copy of current type.
+// The items are not deep copied, as they are the keys of the underlying map.
+func (set *Set) DeepCopy() interface{} {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	data := make(map[interface{}]struct{}, len(set.m))
+	for k, v := range set.m {
+		data[k] = v
+	}
+	return &Set{
+		mu: rwmutex.New(!set.mu.IsSafe()),
+		m:  data,
+	}
+}
+
+// MarshalJSON implements the interface json.Marshaler,
+// which serializes the items of the set to bytes as a json array.
+func (set *Set) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.Slice())
+}
+
+// UnmarshalJSON implements the interface json.Unmarshaler,
+// which restores the set from a json array, the repeated items are merged.
+// It can be called on a zero value Set, which is concurrent-safe.
+// Note that the numbers are unmarshaled as float64, just like json.Unmarshal into interface{}.
+func (set *Set) UnmarshalJSON(b []byte) error {
+	var items []interface{}
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	m := make(map[interface{}]struct{}, len(items))
+	for _, v := range items {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return errors.New("json objects and arrays cannot be items of set")
+		}
+		m[v] = struct{}{}
+	}
+	if set.mu == nil {
+		set.mu = rwmutex.New()
+	}
+	set.mu.Lock()
+	set.m = m
+	set.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gset
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+
+	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/util/gconv"
+)
+
+const (
+	// Version of the binary format of BloomFilter.
+	gBLOOM_FILTER_VERSION = 1
+	// Size in bytes of the binary header: version(1) + k(4) + m(8) + count(8).
+	gBLOOM_FILTER_HEADER_SIZE = 21
+)
+
+// BloomFilter is a probabilistic set which tells whether an item is definitely not in the set
+// or may be in the set. It uses much less memory than exact sets, and is suitable for
+// deduplication of huge amount of items which allows a small false positive rate.
+// Items cannot be removed from a BloomFilter.
+type BloomFilter struct {
+	mu    *rwmutex.RWMutex
+	bits  []uint64 // Bit array.
+	m     uint64   // Number of bits.
+	k     uint32   // Number of hash functions.
+	count uint64   // Number of added items, which counts repeated items.
+}
+
+// NewBloomFilter creates and returns a bloom filter which holds about <n> items with false positive
+// rate <fpRate>, eg: 0.01. The bit array size and the number of hash functions are calculated
+// from <n> and <fpRate>, and the false positive rate increases if more than <n> items are added.
+// The parameter <unsafe> used to specify whether using filter in un-concurrent-safety,
+// which is false in default.
+func NewBloomFilter(n int, fpRate float64, unsafe ...bool) *BloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = 0.01
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k := uint32(math.Max(1, math.Round(float64(m)/float64(n)*math.Ln2)))
+	return newBloomFilter(m, k, unsafe...)
+}
+
+// newBloomFilter creates a bloom filter with <m> bits and <k> hash functions.
+func newBloomFilter(m uint64, k uint32, unsafe ...bool) *BloomFilter {
+	// Round up to whole words, so all bits of the array are used.
+	m = (m + 63) / 64 * 64
+	return &BloomFilter{
+		mu:   rwmutex.New(unsafe...),
+		bits: make([]uint64, m/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// Add adds one or multiple items to the filter.
+// The items are converted to bytes using gconv.Bytes, so items with different types,
+// eg: int32(1) and int64(1), are different items.
+func (f *BloomFilter) Add(item ...interface{}) *BloomFilter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, v := range item {
+		h1, h2 := bloomHash(v)
+		for i := uint32(0); i < f.k; i++ {
+			pos := (h1 + uint64(i)*h2) % f.m
+			f.bits[pos/64] |= 1 << (pos % 64)
+		}
+		f.count++
+	}
+	return f
+}
+
+// MightContain checks whether the filter may contain <item>.
+// It returns false if <item> is definitely not in the filter, or true if it's possibly in the filter.
+func (f *BloomFilter) MightContain(item interface{}) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	h1, h2 := bloomHash(item)
+	for i := uint32(0); i < f.k; i++ {
+		pos := (h1 + uint64(i)*h2) % f.m
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of added items, in which repeated items are counted repeatedly.
+func (f *BloomFilter) Count() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return int(f.count)
+}
+
+// Clear deletes all items of the filter.
+func (f *BloomFilter) Clear() *BloomFilter {
+	f.mu.Lock()
+	f.bits = make([]uint64, len(f.bits))
+	f.count = 0
+	f.mu.Unlock()
+	return f
+}
+
+// FalsePositiveRate returns the estimated false positive rate with current number of added items.
+func (f *BloomFilter) FalsePositiveRate() float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return math.Pow(1-math.Exp(-float64(f.k)*float64(f.count)/float64(f.m)), float64(f.k))
+}
+
+// Union returns a new filter which contains the items of current filter and <others>.
+// The filters should be created with the same <n> and <fpRate>, or else it returns error.
+func (f *BloomFilter) Union(others ...*BloomFilter) (*BloomFilter, error) {
+	f.mu.RLock()
+	newFilter := newBloomFilter(f.m, f.k, !f.mu.IsSafe())
+	copy(newFilter.bits, f.bits)
+	newFilter.count = f.count
+	f.mu.RUnlock()
+	for _, other := range others {
+		if other == f {
+			continue
+		}
+		other.mu.RLock()
+		if other.m != newFilter.m || other.k != newFilter.k {
+			other.mu.RUnlock()
+			return nil, errors.New("cannot union bloom filters with different parameters")
+		}
+		for i, v := range other.bits {
+			newFilter.bits[i] |= v
+		}
+		newFilter.count += other.count
+		other.mu.RUnlock()
+	}
+	return newFilter, nil
+}
+
+// MarshalBinary implements the interface encoding.BinaryMarshaler,
+// which serializes the filter to bytes.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	data := make([]byte, gBLOOM_FILTER_HEADER_SIZE+len(f.bits)*8)
+	data[0] = gBLOOM_FILTER_VERSION
+	binary.BigEndian.PutUint32(data[1:], f.k)
+	binary.BigEndian.PutUint64(data[5:], f.m)
+	binary.BigEndian.PutUint64(data[13:], f.count)
+	for i, v := range f.bits {
+		binary.BigEndian.PutUint64(data[gBLOOM_FILTER_HEADER_SIZE+i*8:], v)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements the interface encoding.BinaryUnmarshaler,
+// which restores the filter from bytes produced by MarshalBinary.
+// It can be called on a zero value BloomFilter, which is concurrent-safe.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < gBLOOM_FILTER_HEADER_SIZE || data[0] != gBLOOM_FILTER_VERSION {
+		return errors.New("invalid bloom filter data")
+	}
+	k := binary.BigEndian.Uint32(data[1:])
+	m := binary.BigEndian.Uint64(data[5:])
+	count := binary.BigEndian.Uint64(data[13:])
+	if k == 0 || m == 0 || m%64 != 0 || uint64(len(data)-gBLOOM_FILTER_HEADER_SIZE) != m/8 {
+		return errors.New("invalid bloom filter data")
+	}
+	bits := make([]uint64, m/64)
+	for i := range bits {
+		bits[i] = binary.BigEndian.Uint64(data[gBLOOM_FILTER_HEADER_SIZE+i*8:])
+	}
+	if f.mu == nil {
+		f.mu = rwmutex.New()
+	}
+	f.mu.Lock()
+	f.bits, f.m, f.k, f.count = bits, m, k, count
+	f.mu.Unlock()
+	return nil
+}
+
+// bloomHash returns the two base hash values of <item> for double hashing.
+func bloomHash(item interface{}) (uint64, uint64) {
+	data := gconv.Bytes(item)
+	h1, h2 := fnv.New64a(), fnv.New64()
+	h1.Write(data)
+	h2.Write(data)
+	// The second hash value should be odd, so the probe positions are not all the same.
+	return h1.Sum64(), h2.Sum64() | 1
+}
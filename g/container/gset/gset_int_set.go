@@ -8,9 +8,11 @@
 package gset
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 )
 
@@ -88,6 +90,17 @@ func (set *IntSet) Size() int {
 	return l
 }
 
+// Instrument registers the "<name>.size" gauge of the set to gmetrics, which is sampled on collecting.
+// It does nothing if the set is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the set can be counted by wrapping it with gmetrics.Wrap.
+func (set *IntSet) Instrument(name string) {
+	if set.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(set.Size())
+		})
+	}
+}
+
 // Clear deletes all items of the set.
 func (set *IntSet) Clear() *IntSet {
 	set.mu.Lock()
@@ -318,3 +331,43 @@ func (set *IntSet) Pops(size int) []int {
 	}
 	return array
 }
+
+// DeepCopy implements interface for deep copy of current type.
+func (set *IntSet) DeepCopy() interface{} {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	data := make(map[int]struct{}, len(set.m))
+	for k, v := range set.m {
+		data[k] = v
+	}
+	return &IntSet{
+		mu: rwmutex.New(!set.mu.IsSafe()),
+		m:  data,
+	}
+}
+
+// MarshalJSON implements the interface json.Marshaler,
+// which serializes the items of the set to bytes as a json array.
+func (set *IntSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.Slice())
+}
+
+// UnmarshalJSON implements the interface json.Unmarshaler,
+// which restores the set from a json array, the repeated items are merged.
+// It can be called on a zero value IntSet, which is concurrent-safe.
+func (set *IntSet) UnmarshalJSON(b []byte) error {
+	var items []int
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	if set.mu == nil {
+		set.mu = rwmutex.New()
+	}
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.m = make(map[int]struct{}, len(items))
+	for _, v := range items {
+		set.m[v] = struct{}{}
+	}
+	return nil
+}
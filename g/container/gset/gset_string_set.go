@@ -8,9 +8,11 @@
 package gset
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/gf/g/internal/rwmutex"
+	"github.com/gf/g/os/gmetrics"
 	"github.com/gf/g/util/gconv"
 )
 
@@ -88,6 +90,17 @@ func (set *StringSet) Size() int {
 	return l
 }
 
+// Instrument registers the "<name>.size" gauge of the set to gmetrics, which is sampled on collecting.
+// It does nothing if the set is not concurrent-safe, as sampling its size would race with the operations.
+// The operations on the set can be counted by wrapping it with gmetrics.Wrap.
+func (set *StringSet) Instrument(name string) {
+	if set.mu.IsSafe() {
+		gmetrics.AddGauge(name+".size", func() float64 {
+			return float64(set.Size())
+		})
+	}
+}
+
 // Clear deletes all items of the set.
 func (set *StringSet) Clear() *StringSet {
 	set.mu.Lock()
@@ -319,3 +332,43 @@ func (set *StringSet) Pops(size int) []string {
 	}
 	return array
 }
+
+// DeepCopy implements interface for deep copy of current type.
+func (set *StringSet) DeepCopy() interface{} {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	data := make(map[string]struct{}, len(set.m))
+	for k, v := range set.m {
+		data[k] = v
+	}
+	return &StringSet{
+		mu: rwmutex.New(!set.mu.IsSafe()),
+		m:  data,
+	}
+}
+
+// MarshalJSON implements the interface json.Marshaler,
+// which serializes the items of the set to bytes as a json array.
+func (set *StringSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.Slice())
+}
+
+// UnmarshalJSON implements the interface json.Unmarshaler,
+// which restores the set from a json array, the repeated items are merged.
+// It can be called on a zero value StringSet, which is concurrent-safe.
+func (set *StringSet) UnmarshalJSON(b []byte) error {
+	var items []string
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	if set.mu == nil {
+		set.mu = rwmutex.New()
+	}
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.m = make(map[string]struct{}, len(items))
+	for _, v := range items {
+		set.m[v] = struct{}{}
+	}
+	return nil
+}
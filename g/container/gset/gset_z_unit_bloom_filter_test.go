@@ -0,0 +1,77 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package gset_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/g/container/gset"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func TestBloomFilter_Basic(t *testing.T) {
+	gtest.Case(t, func() {
+		f := gset.NewBloomFilter(1000, 0.01)
+		for i := 0; i < 1000; i++ {
+			f.Add(fmt.Sprintf("item-%d", i))
+		}
+		gtest.Assert(f.Count(), 1000)
+		for i := 0; i < 1000; i++ {
+			gtest.Assert(f.MightContain(fmt.Sprintf("item-%d", i)), true)
+		}
+		// 误判率应该在预期范围内
+		positive := 0
+		for i := 0; i < 10000; i++ {
+			if f.MightContain(fmt.Sprintf("other-%d", i)) {
+				positive++
+			}
+		}
+		gtest.AssertLT(positive, 300)
+		gtest.AssertLT(f.FalsePositiveRate(), 0.02)
+
+		f.Clear()
+		gtest.Assert(f.Count(), 0)
+		gtest.Assert(f.MightContain("item-1"), false)
+	})
+}
+
+func TestBloomFilter_Union(t *testing.T) {
+	gtest.Case(t, func() {
+		f1 := gset.NewBloomFilter(100, 0.01).Add("a", "b")
+		f2 := gset.NewBloomFilter(100, 0.01).Add("c")
+		f3, err := f1.Union(f2)
+		gtest.Assert(err, nil)
+		gtest.Assert(f3.Count(), 3)
+		gtest.Assert(f3.MightContain("a"), true)
+		gtest.Assert(f3.MightContain("c"), true)
+		gtest.Assert(f1.MightContain("c"), false)
+
+		_, err = f1.Union(gset.NewBloomFilter(1000, 0.01))
+		gtest.AssertNE(err, nil)
+	})
+}
+
+func TestBloomFilter_Binary(t *testing.T) {
+	gtest.Case(t, func() {
+		f1 := gset.NewBloomFilter(100, 0.01).Add("a", 1, []byte("b"))
+		b, err := f1.MarshalBinary()
+		gtest.Assert(err, nil)
+		f2 := new(gset.BloomFilter)
+		gtest.Assert(f2.UnmarshalBinary(b), nil)
+		gtest.Assert(f2.Count(), 3)
+		gtest.Assert(f2.MightContain("a"), true)
+		gtest.Assert(f2.MightContain(1), true)
+		gtest.Assert(f2.MightContain("b"), true)
+		gtest.Assert(f2.MightContain("c"), false)
+
+		gtest.AssertNE(f2.UnmarshalBinary(b[:len(b)-1]), nil)
+		gtest.AssertNE(f2.UnmarshalBinary(nil), nil)
+	})
+}
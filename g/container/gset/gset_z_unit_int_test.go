@@ -9,6 +9,7 @@
 package gset_test
 
 import (
+	"encoding/json"
 	"github.com/gogf/gf/g/container/garray"
 	"github.com/gogf/gf/g/container/gset"
 	"github.com/gogf/gf/g/test/gtest"
@@ -214,3 +215,17 @@ func TestIntSet_Pop(t *testing.T) {
 		gtest.Assert(s1.Size(), 3)
 	})
 }
+
+func TestIntSet_Json(t *testing.T) {
+	gtest.Case(t, func() {
+		s1 := gset.NewIntSetFrom([]int{1, 2, 3})
+		b, err := json.Marshal(s1)
+		gtest.Assert(err, nil)
+		s2 := new(gset.IntSet)
+		gtest.Assert(json.Unmarshal(b, s2), nil)
+		gtest.Assert(s2.Equal(s1), true)
+		s2.Add(4)
+		gtest.Assert(s2.Size(), 4)
+		gtest.AssertNE(json.Unmarshal([]byte(`["a"]`), s2), nil)
+	})
+}
@@ -9,6 +9,7 @@
 package gset_test
 
 import (
+	"encoding/json"
 	"github.com/gogf/gf/g/container/garray"
 	"github.com/gogf/gf/g/container/gset"
 	"github.com/gogf/gf/g/test/gtest"
@@ -254,3 +255,17 @@ func TestStringSet_Pops(t *testing.T) {
 		gtest.AssertIN(str2, []string{"a", "b", "c"})
 	})
 }
+
+func TestStringSet_Json(t *testing.T) {
+	gtest.Case(t, func() {
+		s1 := gset.NewStringSetFrom([]string{"a", "b", "c"})
+		b, err := json.Marshal(s1)
+		gtest.Assert(err, nil)
+		var s2 gset.StringSet
+		gtest.Assert(json.Unmarshal(b, &s2), nil)
+		gtest.Assert(s2.Equal(s1), true)
+		gtest.Assert(json.Unmarshal([]byte(`["a","a","d"]`), &s2), nil)
+		gtest.Assert(s2.Size(), 2)
+		gtest.Assert(s2.Contains("d"), true)
+	})
+}
@@ -9,6 +9,7 @@
 package gset_test
 
 import (
+	"encoding/json"
 	"github.com/gogf/gf/g/container/garray"
 	"github.com/gogf/gf/g/container/gset"
 	"github.com/gogf/gf/g/test/gtest"
@@ -266,3 +267,36 @@ func TestSet_Pops(t *testing.T) {
 		gtest.Assert(len(s1.Pops(2)), 2)
 	})
 }
+
+func TestSet_DeepCopy(t *testing.T) {
+	gtest.Case(t, func() {
+		s1 := gset.NewFrom([]interface{}{1, 2})
+		s2 := s1.DeepCopy().(*gset.Set)
+		s1.Add(3)
+		gtest.Assert(s2.Size(), 2)
+		gtest.Assert(s2.Contains(3), false)
+
+		i1 := gset.NewIntSetFrom([]int{1, 2})
+		i2 := i1.DeepCopy().(*gset.IntSet)
+		i1.Remove(1)
+		gtest.Assert(i2.Contains(1), true)
+
+		str1 := gset.NewStringSetFrom([]string{"a"})
+		str2 := str1.DeepCopy().(*gset.StringSet)
+		str1.Add("b")
+		gtest.Assert(str2.Size(), 1)
+	})
+}
+
+func TestSet_Json(t *testing.T) {
+	gtest.Case(t, func() {
+		s1 := gset.NewFrom([]interface{}{"a", 1.5, true})
+		b, err := json.Marshal(s1)
+		gtest.Assert(err, nil)
+		s2 := gset.New()
+		gtest.Assert(json.Unmarshal(b, s2), nil)
+		gtest.Assert(s2.Equal(s1), true)
+		gtest.AssertNE(json.Unmarshal([]byte(`[{"a":1}]`), s2), nil)
+		gtest.Assert(s2.Equal(s1), true)
+	})
+}
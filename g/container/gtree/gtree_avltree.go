@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/deepcopy"
 	"github.com/gf/g/internal/rwmutex"
 )
 
@@ -58,6 +59,17 @@ func (tree *AVLTree) Clone(unsafe ...bool) *AVLTree {
 	return newTree
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (tree *AVLTree) DeepCopy() interface{} {
+	data := tree.Map()
+	for k, v := range data {
+		data[k] = deepcopy.Copy(v)
+	}
+	newTree := NewAVLTree(tree.comparator, !tree.mu.IsSafe())
+	newTree.Sets(data)
+	return newTree
+}
+
 // Set inserts node into the tree.
 func (tree *AVLTree) Set(key interface{}, value interface{}) {
 	tree.mu.Lock()
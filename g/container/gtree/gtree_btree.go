@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/deepcopy"
 	"github.com/gf/g/internal/rwmutex"
 )
 
@@ -70,6 +71,17 @@ func (tree *BTree) Clone(unsafe ...bool) *BTree {
 	return newTree
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (tree *BTree) DeepCopy() interface{} {
+	data := tree.Map()
+	for k, v := range data {
+		data[k] = deepcopy.Copy(v)
+	}
+	newTree := NewBTree(tree.m, tree.comparator, !tree.mu.IsSafe())
+	newTree.Sets(data)
+	return newTree
+}
+
 // Set inserts key-value item into the tree.
 func (tree *BTree) Set(key interface{}, value interface{}) {
 	tree.mu.Lock()
@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/deepcopy"
 	"github.com/gf/g/internal/rwmutex"
 )
 
@@ -65,6 +66,17 @@ func (tree *RedBlackTree) Clone(unsafe ...bool) *RedBlackTree {
 	return newTree
 }
 
+// DeepCopy implements interface for deep copy of current type.
+func (tree *RedBlackTree) DeepCopy() interface{} {
+	data := tree.Map()
+	for k, v := range data {
+		data[k] = deepcopy.Copy(v)
+	}
+	newTree := NewRedBlackTree(tree.comparator, !tree.mu.IsSafe())
+	newTree.Sets(data)
+	return newTree
+}
+
 // Set inserts key-value item into the tree.
 func (tree *RedBlackTree) Set(key interface{}, value interface{}) {
 	tree.mu.Lock()
@@ -11,6 +11,7 @@
 package gdb
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"github.com/gf/g/container/gtype"
 	"github.com/gf/g/container/gvar"
 	"github.com/gf/g/os/gcache"
+	"github.com/gf/g/os/glog"
 	"github.com/gf/g/util/grand"
 )
 
@@ -32,6 +34,7 @@ type DB interface {
 	// SQL操作方法 API
 	Query(query string, args ...interface{}) (*sql.Rows, error)
 	Exec(sql string, args ...interface{}) (sql.Result, error)
+	ExecScript(script string) (int, error)
 	Prepare(sql string, execOnMaster ...bool) (*sql.Stmt, error)
 
 	// 内部实现API的方法(不同数据库可覆盖这些方法实现自定义的操作)
@@ -40,8 +43,10 @@ type DB interface {
 	doPrepare(link dbLink, query string) (*sql.Stmt, error)
 	doInsert(link dbLink, table string, data interface{}, option int, batch ...int) (result sql.Result, err error)
 	doBatchInsert(link dbLink, table string, list interface{}, option int, batch ...int) (result sql.Result, err error)
+	doSave(link dbLink, table string, list interface{}, conflict []string, insertOnly []string, batch ...int) (result sql.Result, err error)
 	doUpdate(link dbLink, table string, data interface{}, condition string, args ...interface{}) (result sql.Result, err error)
 	doDelete(link dbLink, table string, condition string, args ...interface{}) (result sql.Result, err error)
+	doInsertReturning(link dbLink, table string, list List, returning []string, batch int) (*InsertResult, error)
 
 	// 数据库查询
 	GetAll(query string, args ...interface{}) (Result, error)
@@ -62,6 +67,7 @@ type DB interface {
 
 	// 开启事务操作
 	Begin() (*TX, error)
+	Transaction(f func(tx *TX) error) error
 
 	// 数据表插入/更新/保存操作
 	Insert(table string, data interface{}, batch ...int) (sql.Result, error)
@@ -90,6 +96,46 @@ type DB interface {
 	SetMaxIdleConns(n int)
 	SetMaxOpenConns(n int)
 	SetConnMaxLifetime(n int)
+	SetTableFieldsTTL(n int)
+	SetTxMaxRetries(n int)
+	SetTxRetryHook(hook TxRetryHook)
+	SetBalance(policy string)
+	SetStickyMaster(n int)
+	SetHealthCheckInterval(n int)
+	GetDeadNodes() []ConfigNode
+	SetLogger(logger *glog.Logger)
+	GetLogger() *glog.Logger
+	SetSqlLogOption(option SqlLogOption)
+	GetSqlLogOption() SqlLogOption
+	SetStmtCacheSize(n int)
+	SetQueryTimeout(n int)
+	SetSlowThreshold(n int)
+	SetSlowExplain(enabled bool)
+	SetCancelStatement(enabled bool)
+	SetTimeFields(fields TimeFields)
+	Use(middleware ...func(next Handler) Handler)
+	GetTimeFields() TimeFields
+	SetVersionField(field string)
+	GetVersionField() string
+	SetQueryCache(cache QueryCache)
+	GetQueryCache() QueryCache
+	Stats() []PoolStats
+	SetEncryptKey(key string)
+	Encrypt(value interface{}) (string, error)
+	MustEncrypt(value interface{}) string
+	SetIdGenerator(generator string, workerId ...int)
+	GenerateId() (interface{}, error)
+
+	// 上下文管理
+	Ctx(ctx context.Context) DB
+	GetCtx() context.Context
+
+	// 数据表字段结构缓存管理
+	ClearTableFields(table ...string)
+
+	// 数据库结构信息
+	Tables(schema ...string) ([]string, error)
+	TableFields(table string, schema ...string) (map[string]*TableField, error)
 
 	// 内部方法接口
 	getCache() *gcache.Cache
@@ -98,8 +144,25 @@ type DB interface {
 	filterFields(table string, data map[string]interface{}) map[string]interface{}
 	convertValue(fieldValue interface{}, fieldType string) interface{}
 	getTableFields(table string) (map[string]string, error)
+	doTables(schema string) ([]string, error)
+	doTableFields(table string, schema string) (map[string]*TableField, error)
+	encryptValue(value interface{}, deterministic bool) (interface{}, error)
+	decryptValue(value interface{}) (interface{}, error)
+	getIdGenerator() (generator string, workerId int)
+	getGeneratedIdField(table string) (field string, err error)
+	getPrimaryKey(table string) (field string, err error)
+	getReturningClause(fields []string) (output string, returning string)
 	rowsToResult(rows *sql.Rows) (Result, error)
 	handleSqlBeforeExec(sql string) string
+	getExplainSql(query string) string
+	getCancelSql() (idSql string, cancelSql string)
+	doExecScript(link dbLink, script string) (int, error)
+	getSaveClause(fields []string, conflict []string) (string, error)
+	getJsonContainsSql(field string, path string, value string) (string, []interface{})
+	markWrite(link dbLink, ctx context.Context)
+	getAll(link dbLink, query string, args ...interface{}) (Result, error)
+	iterate(link dbLink, query string, args []interface{}, f func(record Record) bool) error
+	getCacheFlight() *cacheFlight
 }
 
 // 执行底层数据库操作的核心接口
@@ -107,6 +170,8 @@ type dbLink interface {
 	Query(query string, args ...interface{}) (*sql.Rows, error)
 	Exec(sql string, args ...interface{}) (sql.Result, error)
 	Prepare(sql string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, sql string, args ...interface{}) (sql.Result, error)
 }
 
 // 数据库链接对象
@@ -121,6 +186,30 @@ type dbBase struct {
 	maxIdleConnCount *gtype.Int                   // 连接池最大限制的连接数
 	maxOpenConnCount *gtype.Int                   // 连接池最大打开的连接数
 	maxConnLifetime  *gtype.Int                   // (单位秒)连接对象可重复使用的时间长度
+	tableFieldsTTL   *gtype.Int                   // (单位秒)数据表字段结构的缓存时间
+	txMaxRetries     *gtype.Int                   // 事务闭包操作遇到死锁/序列化失败时的最大重试次数
+	txRetryHook      *gtype.Interface             // 事务闭包操作重试时的回调函数(TxRetryHook)
+	balance          *gtype.String                // slave节点的负载均衡策略
+	balanceIndex     *gtype.Int                   // 轮询负载均衡的计数
+	stickyMaster     *gtype.Int                   // (单位毫秒)写操作后读操作仍然使用master节点的时间
+	healthInterval   *gtype.Int                   // (单位秒)slave节点的健康检查间隔
+	health           *gmap.StrAnyMap              // 节点的健康状态，键名为节点配置字符串
+	logger           *gtype.Interface             // 日志对象(*glog.Logger)，为空时使用glog默认的日志对象
+	sqlLogOption     *gtype.Interface             // SQL日志的格式化选项(SqlLogOption)
+	stmts            *stmtCache                   // 预处理语句缓存
+	queryTimeout     *gtype.Int                   // (单位毫秒)SQL操作的默认超时时间
+	slowThreshold    *gtype.Int                   // (单位毫秒)慢查询阈值
+	slowExplain      *gtype.Bool                  // 是否在debug模式下对慢查询自动执行EXPLAIN
+	ctx              context.Context              // SQL操作的上下文，为空时使用context.Background()
+	timeFields       *gtype.Interface             // 链式操作自动维护的数据表时间字段名称(TimeFields)
+	versionField     *gtype.Interface             // 链式操作乐观锁的版本字段名称(string)
+	queryCache       *gtype.Interface             // 链式操作的查询缓存(QueryCache)
+	cacheFlight      *cacheFlight                 // 查询缓存未命中时的并发查询合并
+	middlewares      *middlewares                 // SQL操作的中间件
+	encryptKey       *gtype.String                // orm:"encrypt"标签字段的加密密钥
+	idGenerator      *gtype.String                // 链式操作写入数据时的主键生成策略
+	workerId         *gtype.Int                   // 雪花算法的机器ID，小于0表示使用节点配置
+	cancelStatement  *gtype.Bool                  // 上下文取消或者超时时是否在服务端取消正在执行的语句
 }
 
 // 执行的SQL对象
@@ -186,6 +275,29 @@ func New(name ...string) (db DB, err error) {
 				maxIdleConnCount: gtype.NewInt(),
 				maxOpenConnCount: gtype.NewInt(),
 				maxConnLifetime:  gtype.NewInt(gDEFAULT_CONN_MAX_LIFE_TIME),
+				tableFieldsTTL:   gtype.NewInt(),
+				txMaxRetries:     gtype.NewInt(gDEFAULT_TX_MAX_RETRIES),
+				txRetryHook:      gtype.NewInterface(),
+				balance:          gtype.NewString(BALANCE_WEIGHT),
+				balanceIndex:     gtype.NewInt(),
+				stickyMaster:     gtype.NewInt(),
+				healthInterval:   gtype.NewInt(gDEFAULT_HEALTH_CHECK_INTERVAL),
+				health:           gmap.NewStrAnyMap(),
+				logger:           gtype.NewInterface(),
+				sqlLogOption:     gtype.NewInterface(),
+				stmts:            newStmtCache(node.StmtCacheSize),
+				queryTimeout:     gtype.NewInt(),
+				slowThreshold:    gtype.NewInt(),
+				slowExplain:      gtype.NewBool(),
+				timeFields:       gtype.NewInterface(),
+				versionField:     gtype.NewInterface(),
+				queryCache:       gtype.NewInterface(),
+				cacheFlight:      &cacheFlight{},
+				middlewares:      &middlewares{},
+				encryptKey:       gtype.NewString(),
+				idGenerator:      gtype.NewString(),
+				workerId:         gtype.NewInt(-1),
+				cancelStatement:  gtype.NewBool(),
 			}
 			switch node.Type {
 			case "mysql":
@@ -198,6 +310,8 @@ func New(name ...string) (db DB, err error) {
 				base.db = &dbSqlite{dbBase: base}
 			case "oracle":
 				base.db = &dbOracle{dbBase: base}
+			case "clickhouse":
+				base.db = &dbClickhouse{dbBase: base}
 			default:
 				return nil, errors.New(fmt.Sprintf(`unsupported database type "%s"`, node.Type))
 			}
@@ -300,10 +414,22 @@ func getConfigNodeByPriority(cg ConfigGroup) *ConfigNode {
 // 获得底层数据库链接对象
 func (bs *dbBase) getSqlDb(master bool) (sqlDb *sql.DB, err error) {
 	// 负载均衡
-	node, err := getConfigNodeByGroup(bs.group, master)
+	node, err := bs.selectNode(master)
 	if err != nil {
 		return nil, err
 	}
+	if sqlDb, err = bs.openSqlDb(node); err != nil {
+		return nil, err
+	}
+	// 是否手动选择数据库
+	if v := bs.schema.Val(); v != "" {
+		sqlDb.Exec("USE " + v)
+	}
+	return
+}
+
+// 获得指定节点的底层数据库链接对象(连接池)
+func (bs *dbBase) openSqlDb(node *ConfigNode) (sqlDb *sql.DB, err error) {
 	// 默认值设定
 	if node.Charset == "" {
 		node.Charset = "utf8"
@@ -336,10 +462,6 @@ func (bs *dbBase) getSqlDb(master bool) (sqlDb *sql.DB, err error) {
 	if v != nil && sqlDb == nil {
 		sqlDb = v.(*sql.DB)
 	}
-	// 是否手动选择数据库
-	if v := bs.schema.Val(); v != "" {
-		sqlDb.Exec("USE " + v)
-	}
 	return
 }
 
@@ -0,0 +1,189 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/os/gtime"
+)
+
+const (
+	BALANCE_WEIGHT      = "weight"     // 按照节点Priority权重随机选择(默认)
+	BALANCE_ROUND_ROBIN = "roundrobin" // 按照顺序轮询选择
+
+	gDEFAULT_HEALTH_CHECK_INTERVAL = 10 // (单位秒)默认的slave节点健康检查间隔
+)
+
+// 数据库节点的健康状态
+type nodeHealth struct {
+	dead     *gtype.Bool  // 节点是否不可用
+	checking *gtype.Bool  // 是否正在检查
+	checked  *gtype.Int64 // 上一次检查的时间(毫秒)
+}
+
+// 设置读操作在slave节点间的负载均衡策略：BALANCE_WEIGHT(默认)、BALANCE_ROUND_ROBIN
+func (bs *dbBase) SetBalance(policy string) {
+	bs.balance.Set(policy)
+}
+
+// 主节点粘滞范围的上下文键名类型
+type stickyMasterCtxKey struct{}
+
+// 主节点粘滞的范围，记录该范围内最近一次写操作的时间(毫秒)
+type stickyMasterScope struct {
+	lastWrite *gtype.Int64
+}
+
+// 创建并返回主节点粘滞范围的上下文，通过Ctx方法使用该上下文的写操作之后，
+// 使用同一上下文的读操作在粘滞时间内使用master节点，常用于一个HTTP请求或者一个用户会话内的读己之写，
+// 不同上下文之间互不影响，未使用该上下文的读写操作不进行粘滞。
+func WithStickyMaster(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, stickyMasterCtxKey{}, &stickyMasterScope{
+		lastWrite: gtype.NewInt64(),
+	})
+}
+
+// 获取上下文中的主节点粘滞范围，不存在时返回nil
+func getStickyMasterScope(ctx context.Context) *stickyMasterScope {
+	if ctx == nil {
+		return nil
+	}
+	if scope, ok := ctx.Value(stickyMasterCtxKey{}).(*stickyMasterScope); ok {
+		return scope
+	}
+	return nil
+}
+
+// 设置写操作后读操作仍然使用master节点的时间(单位毫秒)，用于避免主从复制延迟导致读取不到刚写入的数据，
+// 粘滞的范围为WithStickyMaster创建的上下文，事务中的写操作在事务提交后开始计时，n <= 0 表示不启用(默认)
+func (bs *dbBase) SetStickyMaster(n int) {
+	bs.stickyMaster.Set(n)
+}
+
+// 设置slave节点的健康检查间隔(单位秒)，不可用的slave节点将不会被选择，
+// 所有slave节点均不可用时读操作使用master节点，n <= 0 表示不进行健康检查
+func (bs *dbBase) SetHealthCheckInterval(n int) {
+	bs.healthInterval.Set(n)
+}
+
+// 获取当前不可用的slave节点列表
+func (bs *dbBase) GetDeadNodes() []ConfigNode {
+	nodes := make([]ConfigNode, 0)
+	if bs.healthInterval.Val() <= 0 {
+		return nodes
+	}
+	for _, node := range GetConfig(bs.group) {
+		if node.Role == "slave" && bs.getNodeHealth(&node).dead.Val() {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// 根据读写类型选择一个配置节点：写操作、同一粘滞范围内写操作后的粘滞时间内以及没有可用slave节点时选择master节点，
+// 否则按照负载均衡策略从可用的slave节点中选择。
+func (bs *dbBase) selectNode(master bool) (*ConfigNode, error) {
+	list := GetConfig(bs.group)
+	if list == nil {
+		return nil, errors.New(fmt.Sprintf("empty database configuration for item name '%s'", bs.group))
+	}
+	masterList := make(ConfigGroup, 0)
+	slaveList := make(ConfigGroup, 0)
+	for i := 0; i < len(list); i++ {
+		if list[i].Role == "slave" {
+			slaveList = append(slaveList, list[i])
+		} else {
+			masterList = append(masterList, list[i])
+		}
+	}
+	if len(masterList) < 1 {
+		return nil, errors.New("at least one master node configuration's need to make sense")
+	}
+	if !master && len(slaveList) > 0 && !bs.isStickyMaster() {
+		if aliveList := bs.aliveNodes(slaveList); len(aliveList) > 0 {
+			return bs.balanceNode(aliveList), nil
+		}
+	}
+	return bs.balanceNode(masterList), nil
+}
+
+// 按照负载均衡策略从节点列表中选择一个节点
+func (bs *dbBase) balanceNode(cg ConfigGroup) *ConfigNode {
+	if bs.balance.Val() == BALANCE_ROUND_ROBIN && len(cg) > 1 {
+		return &cg[(bs.balanceIndex.Add(1)-1)%len(cg)]
+	}
+	return getConfigNodeByPriority(cg)
+}
+
+// 过滤出可用的节点，并对超过检查间隔的节点异步进行健康检查
+func (bs *dbBase) aliveNodes(cg ConfigGroup) ConfigGroup {
+	interval := int64(bs.healthInterval.Val()) * 1000
+	if interval <= 0 {
+		return cg
+	}
+	aliveList := make(ConfigGroup, 0, len(cg))
+	for i := 0; i < len(cg); i++ {
+		health := bs.getNodeHealth(&cg[i])
+		if gtime.Millisecond()-health.checked.Val() >= interval && !health.checking.Set(true) {
+			go bs.checkNode(cg[i], health)
+		}
+		if !health.dead.Val() {
+			aliveList = append(aliveList, cg[i])
+		}
+	}
+	return aliveList
+}
+
+// 检查节点是否可用
+func (bs *dbBase) checkNode(node ConfigNode, health *nodeHealth) {
+	defer health.checking.Set(false)
+	sqlDb, err := bs.openSqlDb(&node)
+	if err == nil {
+		err = sqlDb.Ping()
+	}
+	health.dead.Set(err != nil)
+	health.checked.Set(gtime.Millisecond())
+}
+
+// 获取节点的健康状态，未检查过的节点默认为可用
+func (bs *dbBase) getNodeHealth(node *ConfigNode) *nodeHealth {
+	return bs.health.GetOrSetFuncLock(node.String(), func() interface{} {
+		return &nodeHealth{
+			dead:     gtype.NewBool(),
+			checking: gtype.NewBool(),
+			checked:  gtype.NewInt64(),
+		}
+	}).(*nodeHealth)
+}
+
+// 在上下文的粘滞范围内记录写操作时间，用于写操作后的master粘滞，
+// 事务中的写操作在提交之前对slave不可见，因此在事务提交时记录
+func (bs *dbBase) markWrite(link dbLink, ctx context.Context) {
+	if _, ok := link.(*txLink); ok || bs.stickyMaster.Val() <= 0 {
+		return
+	}
+	if scope := getStickyMasterScope(ctx); scope != nil {
+		scope.lastWrite.Set(gtime.Millisecond())
+	}
+}
+
+// 判断当前读操作是否应当使用master节点
+func (bs *dbBase) isStickyMaster() bool {
+	n := bs.stickyMaster.Val()
+	if n <= 0 {
+		return false
+	}
+	scope := getStickyMasterScope(bs.GetCtx())
+	return scope != nil && gtime.Millisecond()-scope.lastWrite.Val() < int64(n)
+}
@@ -79,26 +79,14 @@ func (bs *dbBase) Query(query string, args ...interface{}) (rows *sql.Rows, err
 // 数据库sql查询操作，主要执行查询
 func (bs *dbBase) doQuery(link dbLink, query string, args ...interface{}) (rows *sql.Rows, err error) {
 	query = bs.db.handleSqlBeforeExec(query)
-	if bs.db.getDebug() {
-		mTime1 := gtime.Millisecond()
-		rows, err = link.Query(query, args...)
-		mTime2 := gtime.Millisecond()
-		s := &Sql{
-			Sql:   query,
-			Args:  args,
-			Error: err,
-			Start: mTime1,
-			End:   mTime2,
-		}
-		bs.sqls.Put(s)
-		printSql(s)
-	} else {
-		rows, err = link.Query(query, args...)
-	}
+	op, err := bs.handleOperation(OPERATION_QUERY, link, query, args, bs.queryHandler)
 	if err == nil {
-		return rows, nil
+		return op.Rows, nil
 	} else {
-		err = formatError(err, query, args...)
+		if op.Rows != nil {
+			op.Rows.Close()
+		}
+		err = formatError(err, op.Sql, op.Args...)
 	}
 	return nil, err
 }
@@ -115,23 +103,12 @@ func (bs *dbBase) Exec(query string, args ...interface{}) (result sql.Result, er
 // 执行一条sql，并返回执行情况，主要用于非查询操作
 func (bs *dbBase) doExec(link dbLink, query string, args ...interface{}) (result sql.Result, err error) {
 	query = bs.db.handleSqlBeforeExec(query)
-	if bs.db.getDebug() {
-		mTime1 := gtime.Millisecond()
-		result, err = link.Exec(query, args...)
-		mTime2 := gtime.Millisecond()
-		s := &Sql{
-			Sql:   query,
-			Args:  args,
-			Error: err,
-			Start: mTime1,
-			End:   mTime2,
-		}
-		bs.sqls.Put(s)
-		printSql(s)
-	} else {
-		result, err = link.Exec(query, args...)
+	op, err := bs.handleOperation(OPERATION_EXEC, link, query, args, bs.execHandler)
+	result = op.Result
+	if err == nil && getDryRunCapture(op.Ctx) == nil {
+		bs.db.markWrite(link, op.Ctx)
 	}
-	return result, formatError(err, query, args...)
+	return result, formatError(err, op.Sql, op.Args...)
 }
 
 // SQL预处理，执行完成后调用返回值sql.Stmt.Exec完成sql操作; 默认执行在Slave上, 通过第二个参数指定执行在Master上
@@ -157,12 +134,11 @@ func (bs *dbBase) doPrepare(link dbLink, query string) (*sql.Stmt, error) {
 
 // 数据库查询，获取查询结果集，以列表结构返回
 func (bs *dbBase) GetAll(query string, args ...interface{}) (Result, error) {
-	rows, err := bs.Query(query, args...)
-	if err != nil || rows == nil {
+	link, err := bs.db.Slave()
+	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	return bs.db.rowsToResult(rows)
+	return bs.getAll(link, query, args...)
 }
 
 // 数据库查询，获取查询结果记录，以关联数组结构返回
@@ -265,7 +241,7 @@ func (bs *dbBase) Begin() (*TX, error) {
 	if master, err := bs.db.Master(); err != nil {
 		return nil, err
 	} else {
-		if tx, err := master.Begin(); err == nil {
+		if tx, err := master.BeginTx(bs.GetCtx(), nil); err == nil {
 			return &TX{
 				db:     bs.db,
 				tx:     tx,
@@ -330,8 +306,10 @@ func (bs *dbBase) doInsert(link dbLink, table string, data interface{}, option i
 	default:
 		return result, errors.New(fmt.Sprint("unsupported data type:", kind))
 	}
+	var keys []string
 	charL, charR := bs.db.getChars()
 	for k, v := range dataMap {
+		keys = append(keys, k)
 		fields = append(fields, charL+k+charR)
 		values = append(values, "?")
 		params = append(params, convertParam(v))
@@ -339,16 +317,9 @@ func (bs *dbBase) doInsert(link dbLink, table string, data interface{}, option i
 	operation := getInsertOperationByOption(option)
 	updateStr := ""
 	if option == OPTION_SAVE {
-		var updates []string
-		for k, _ := range dataMap {
-			updates = append(updates,
-				fmt.Sprintf("%s%s%s=VALUES(%s%s%s)",
-					charL, k, charR,
-					charL, k, charR,
-				),
-			)
+		if updateStr, err = bs.db.getSaveClause(keys, nil); err != nil {
+			return nil, err
 		}
-		updateStr = fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", strings.Join(updates, ","))
 	}
 	if link == nil {
 		if link, err = bs.db.Master(); err != nil {
@@ -378,42 +349,24 @@ func (bs *dbBase) BatchSave(table string, list interface{}, batch ...int) (sql.R
 
 // 批量写入数据, 参数list支持slice类型，例如: []map/[]struct/[]*struct。
 func (bs *dbBase) doBatchInsert(link dbLink, table string, list interface{}, option int, batch ...int) (result sql.Result, err error) {
+	return bs.batchInsert(link, table, list, option, nil, nil, batch...)
+}
+
+// 批量写入或者更新数据(upsert), 参数list支持map/struct/slice类型，
+// conflict为判断数据是否存在的冲突字段(主键或者唯一索引字段)，冲突字段不会被更新，
+// insertOnly为只在写入新记录时设置的字段(例如创建时间)，数据已存在时同样不会被更新。
+func (bs *dbBase) doSave(link dbLink, table string, list interface{}, conflict []string, insertOnly []string, batch ...int) (result sql.Result, err error) {
+	return bs.batchInsert(link, table, list, OPTION_SAVE, conflict, insertOnly, batch...)
+}
+
+// 按照批次量写入数据，conflict及insertOnly仅在save操作时有效
+func (bs *dbBase) batchInsert(link dbLink, table string, list interface{}, option int, conflict []string, insertOnly []string, batch ...int) (result sql.Result, err error) {
 	var keys []string
 	var values []string
 	var params []interface{}
-	listMap := (List)(nil)
-	switch v := list.(type) {
-	case Result:
-		listMap = v.ToList()
-	case Record:
-		listMap = List{v.ToMap()}
-	case List:
-		listMap = v
-	case Map:
-		listMap = List{v}
-	default:
-		rv := reflect.ValueOf(list)
-		kind := rv.Kind()
-		if kind == reflect.Ptr {
-			rv = rv.Elem()
-			kind = rv.Kind()
-		}
-		switch kind {
-		// 如果是slice，那么转换为List类型
-		case reflect.Slice:
-			fallthrough
-		case reflect.Array:
-			listMap = make(List, rv.Len())
-			for i := 0; i < rv.Len(); i++ {
-				listMap[i] = structToMap(rv.Index(i).Interface())
-			}
-		case reflect.Map:
-			fallthrough
-		case reflect.Struct:
-			listMap = List{Map(structToMap(list))}
-		default:
-			return result, errors.New(fmt.Sprint("unsupported list type:", kind))
-		}
+	listMap, err := convertListToMaps(list)
+	if err != nil {
+		return result, err
 	}
 	// 判断长度
 	if len(listMap) < 1 {
@@ -438,16 +391,23 @@ func (bs *dbBase) doBatchInsert(link dbLink, table string, list interface{}, opt
 	operation := getInsertOperationByOption(option)
 	updateStr := ""
 	if option == OPTION_SAVE {
-		var updates []string
-		for _, k := range keys {
-			updates = append(updates,
-				fmt.Sprintf("%s%s%s=VALUES(%s%s%s)",
-					charL, k, charR,
-					charL, k, charR,
-				),
-			)
+		updateKeys := keys
+		if len(insertOnly) > 0 {
+			isInsertOnly := make(map[string]bool, len(insertOnly))
+			for _, k := range insertOnly {
+				isInsertOnly[k] = true
+			}
+			updateKeys = make([]string, 0, len(keys))
+			for _, k := range keys {
+				if !isInsertOnly[k] {
+					updateKeys = append(updateKeys, k)
+				}
+			}
+		}
+		if updateStr, err = bs.db.getSaveClause(updateKeys, conflict); err != nil {
+			return nil, err
 		}
-		updateStr = fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s", strings.Join(updates, ","))
+		updateStr = " " + updateStr
 	}
 	// 构造批量写入数据格式(注意map的遍历是无序的)
 	batchNum := gDEFAULT_BATCH_NUM
@@ -496,6 +456,41 @@ func (bs *dbBase) doBatchInsert(link dbLink, table string, list interface{}, opt
 	return batchResult, nil
 }
 
+// 获取save操作(upsert)写入语句的冲突更新子句，fields为写入的字段，conflict为冲突字段，
+// MySQL使用ON DUPLICATE KEY UPDATE，由主键或者唯一索引判断冲突，因此忽略conflict参数。
+func (bs *dbBase) getSaveClause(fields []string, conflict []string) (string, error) {
+	charL, charR := bs.db.getChars()
+	updates := make([]string, 0, len(fields))
+	for _, k := range fields {
+		updates = append(updates, fmt.Sprintf("%s%s%s=VALUES(%s%s%s)", charL, k, charR, charL, k, charR))
+	}
+	return fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", strings.Join(updates, ",")), nil
+}
+
+// 获取ON CONFLICT语法(PostgreSQL/SQLite)的冲突更新子句，冲突字段不能为空，并且不会被更新
+func getOnConflictClause(db DB, fields []string, conflict []string) (string, error) {
+	if len(conflict) == 0 {
+		return "", errors.New("conflict columns are required for saving data")
+	}
+	charL, charR := db.getChars()
+	isConflict := make(map[string]bool, len(conflict))
+	columns := make([]string, len(conflict))
+	for i, k := range conflict {
+		isConflict[k] = true
+		columns[i] = charL + k + charR
+	}
+	updates := make([]string, 0, len(fields))
+	for _, k := range fields {
+		if !isConflict[k] {
+			updates = append(updates, fmt.Sprintf("%s%s%s=EXCLUDED.%s%s%s", charL, k, charR, charL, k, charR))
+		}
+	}
+	if len(updates) == 0 {
+		return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(columns, ",")), nil
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(columns, ","), strings.Join(updates, ",")), nil
+}
+
 // CURD操作:数据更新，统一采用sql预处理。
 // data参数支持string/map/struct/*struct类型。
 func (bs *dbBase) Update(table string, data interface{}, condition interface{}, args ...interface{}) (sql.Result, error) {
@@ -570,6 +565,16 @@ func (bs *dbBase) getCache() *gcache.Cache {
 
 // 将数据查询的列表数据*sql.Rows转换为Result类型
 func (bs *dbBase) rowsToResult(rows *sql.Rows) (Result, error) {
+	records := make(Result, 0)
+	err := bs.iterateRows(rows, func(record Record) bool {
+		records = append(records, record)
+		return true
+	})
+	return records, err
+}
+
+// 逐条读取*sql.Rows中的记录并转换为Record类型，回调函数f返回false时停止读取
+func (bs *dbBase) iterateRows(rows *sql.Rows, f func(record Record) bool) error {
 	// 列信息列表, 名称与类型
 	types := make([]string, 0)
 	columns := make([]string, 0)
@@ -581,13 +586,12 @@ func (bs *dbBase) rowsToResult(rows *sql.Rows) (Result, error) {
 	// 返回结构组装
 	values := make([]sql.RawBytes, len(columns))
 	scanArgs := make([]interface{}, len(values))
-	records := make(Result, 0)
 	for i := range values {
 		scanArgs[i] = &values[i]
 	}
 	for rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
-			return records, err
+			return err
 		}
 		row := make(Record)
 		// 注意col字段是一个[]byte类型(slice类型本身是一个指针)，多个记录循环时该变量指向的是同一个内存地址
@@ -601,7 +605,9 @@ func (bs *dbBase) rowsToResult(rows *sql.Rows) (Result, error) {
 				row[columns[i]] = gvar.New(bs.db.convertValue(v, types[i]), true)
 			}
 		}
-		records = append(records, row)
+		if !f(row) {
+			return nil
+		}
 	}
-	return records, nil
+	return rows.Err()
 }
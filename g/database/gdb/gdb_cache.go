@@ -0,0 +1,312 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gf/g/container/gmap"
+	"github.com/gf/g/container/gset"
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/database/gredis"
+	"github.com/gf/g/os/gcache"
+	"github.com/gf/g/os/gtime"
+	"github.com/gf/g/text/gregex"
+)
+
+const (
+	gQUERY_CACHE_PREFIX         = "gdb_query_cache:"        // 查询缓存键名前缀
+	gQUERY_CACHE_TABLE_PREFIX   = "gdb_query_cache_tables:" // 数据表关联的查询缓存键名集合的键名前缀(Redis)
+	gQUERY_CACHE_PRUNE_INTERVAL = 60000                     // (单位毫秒)清理内存查询缓存中数据表关联的失效键名的间隔
+)
+
+// 初始化默认查询缓存对象时的互斥锁
+var queryCacheMu sync.Mutex
+
+// 查询缓存接口，用于缓存链式操作的查询结果，可通过SetQueryCache设置自定义的缓存实现。
+// 缓存项关联查询的数据表，通过链式操作写入/更新/删除数据表时，自动清除与该数据表关联的缓存项。
+type QueryCache interface {
+	// 获取缓存的查询结果，ok为false表示缓存不存在
+	Get(key string) (result Result, ok bool, err error)
+	// 缓存查询结果，ttl为缓存时间(秒)，0表示不过期，tables为查询关联的数据表
+	Set(key string, result Result, ttl int, tables []string) error
+	// 删除缓存项
+	Remove(key string) error
+	// 删除与数据表关联的所有缓存项
+	RemoveTables(tables ...string) error
+}
+
+// 基于gcache的内存查询缓存
+type memQueryCache struct {
+	cache     *gcache.Cache
+	tables    *gmap.StrAnyMap // 数据表关联的缓存键名集合(*gset.StringSet)
+	lastPrune *gtype.Int64    // 上一次清理数据表关联的失效键名的时间(毫秒)
+}
+
+// 基于gredis的查询缓存，查询结果使用gob编码存储
+type redisQueryCache struct {
+	redis     *gredis.Redis
+	namespace string // 缓存键名的命名空间，用于多个数据库共享同一个Redis时区分缓存项
+}
+
+// 查询缓存未命中时的并发查询合并，同一个缓存键名同时只有一个查询执行，防止缓存击穿
+type cacheFlight struct {
+	mu    sync.Mutex
+	calls map[string]*cacheFlightCall
+}
+
+// 正在执行的查询
+type cacheFlightCall struct {
+	wg     sync.WaitGroup
+	result Result
+	err    error
+}
+
+// 创建基于gcache的内存查询缓存
+func NewMemQueryCache(cache ...*gcache.Cache) QueryCache {
+	c := &memQueryCache{
+		tables:    gmap.NewStrAnyMap(),
+		lastPrune: gtype.NewInt64(gtime.Millisecond()),
+	}
+	if len(cache) > 0 && cache[0] != nil {
+		c.cache = cache[0]
+	} else {
+		c.cache = gcache.New()
+	}
+	return c
+}
+
+// 创建基于gredis的查询缓存，多个进程可以通过同一个Redis共享查询缓存，并在写操作时同时失效，
+// 多个数据库共享同一个Redis时需要通过namespace区分缓存项(默认使用数据库配置分组名称)。
+func NewRedisQueryCache(redis *gredis.Redis, namespace ...string) QueryCache {
+	c := &redisQueryCache{
+		redis: redis,
+	}
+	if len(namespace) > 0 {
+		c.namespace = namespace[0] + ":"
+	}
+	return c
+}
+
+func (c *memQueryCache) Get(key string) (Result, bool, error) {
+	if v := c.cache.Get(gQUERY_CACHE_PREFIX + key); v != nil {
+		return v.(Result), true, nil
+	}
+	return nil, false, nil
+}
+
+func (c *memQueryCache) Set(key string, result Result, ttl int, tables []string) error {
+	c.cache.Set(gQUERY_CACHE_PREFIX+key, result, ttl*1000)
+	c.tables.LockFunc(func(m map[string]interface{}) {
+		for _, table := range tables {
+			set, ok := m[table].(*gset.StringSet)
+			if !ok {
+				set = gset.NewStringSet()
+				m[table] = set
+			}
+			set.Add(key)
+		}
+	})
+	c.prune()
+	return nil
+}
+
+// 定期清理数据表关联的缓存键名集合中已经过期、被淘汰或者被删除的键名，以及清理后为空的集合，
+// gcache不提供过期及淘汰的通知，因此在写入缓存时按照间隔检查，避免键名集合无限增长
+func (c *memQueryCache) prune() {
+	now := gtime.Millisecond()
+	last := c.lastPrune.Val()
+	if now-last < gQUERY_CACHE_PRUNE_INTERVAL || !c.lastPrune.Cas(last, now) {
+		return
+	}
+	c.tables.LockFunc(func(m map[string]interface{}) {
+		for table, v := range m {
+			set := v.(*gset.StringSet)
+			set.LockFunc(func(keys map[string]struct{}) {
+				for key := range keys {
+					if !c.cache.Contains(gQUERY_CACHE_PREFIX + key) {
+						delete(keys, key)
+					}
+				}
+			})
+			if set.Size() == 0 {
+				delete(m, table)
+			}
+		}
+	})
+}
+
+func (c *memQueryCache) Remove(key string) error {
+	c.cache.Remove(gQUERY_CACHE_PREFIX + key)
+	return nil
+}
+
+func (c *memQueryCache) RemoveTables(tables ...string) error {
+	for _, table := range tables {
+		if v := c.tables.Remove(table); v != nil {
+			v.(*gset.StringSet).Iterator(func(key string) bool {
+				c.cache.Remove(gQUERY_CACHE_PREFIX + key)
+				return true
+			})
+		}
+	}
+	return nil
+}
+
+func (c *redisQueryCache) Get(key string) (Result, bool, error) {
+	data, err := c.redis.GetBytes(gQUERY_CACHE_PREFIX + c.namespace + key)
+	if err != nil || len(data) == 0 {
+		return nil, false, err
+	}
+	list := make([]map[string]interface{}, 0)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&list); err != nil {
+		return nil, false, err
+	}
+	result := make(Result, len(list))
+	for i, m := range list {
+		record := make(Record, len(m))
+		for k, v := range m {
+			record[k] = gvar.New(v, true)
+		}
+		result[i] = record
+	}
+	return result, true, nil
+}
+
+func (c *redisQueryCache) Set(key string, result Result, ttl int, tables []string) error {
+	list := make([]map[string]interface{}, len(result))
+	for i, record := range result {
+		list[i] = record.ToMap()
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := gob.NewEncoder(buffer).Encode(list); err != nil {
+		return err
+	}
+	var err error
+	if ttl > 0 {
+		err = c.redis.SetEX(gQUERY_CACHE_PREFIX+c.namespace+key, buffer.Bytes(), time.Duration(ttl)*time.Second)
+	} else {
+		err = c.redis.Set(gQUERY_CACHE_PREFIX+c.namespace+key, buffer.Bytes())
+	}
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err := c.redis.SAdd(gQUERY_CACHE_TABLE_PREFIX+c.namespace+table, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *redisQueryCache) Remove(key string) error {
+	_, err := c.redis.Del(gQUERY_CACHE_PREFIX + c.namespace + key)
+	return err
+}
+
+func (c *redisQueryCache) RemoveTables(tables ...string) error {
+	for _, table := range tables {
+		keys, err := c.redis.SMembers(gQUERY_CACHE_TABLE_PREFIX + c.namespace + table)
+		if err != nil {
+			return err
+		}
+		for i, key := range keys {
+			keys[i] = gQUERY_CACHE_PREFIX + c.namespace + key
+		}
+		if _, err := c.redis.Del(append(keys, gQUERY_CACHE_TABLE_PREFIX+c.namespace+table)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 执行查询方法f，同一个键名的并发查询只执行一次，其他查询等待并共享查询结果
+func (f *cacheFlight) do(key string, fn func() (Result, error)) (Result, error) {
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = make(map[string]*cacheFlightCall)
+	}
+	if call, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		call.wg.Wait()
+		return call.result, call.err
+	}
+	call := &cacheFlightCall{}
+	call.wg.Add(1)
+	f.calls[key] = call
+	f.mu.Unlock()
+
+	call.result, call.err = fn()
+	call.wg.Done()
+
+	f.mu.Lock()
+	delete(f.calls, key)
+	f.mu.Unlock()
+	return call.result, call.err
+}
+
+// 设置链式操作的查询缓存实现，默认使用内存缓存，节点配置了CacheRedis时使用对应分组的gredis缓存
+func (bs *dbBase) SetQueryCache(cache QueryCache) {
+	bs.queryCache.Set(cache)
+}
+
+// 获取链式操作的查询缓存实现
+func (bs *dbBase) GetQueryCache() QueryCache {
+	if v := bs.queryCache.Val(); v != nil {
+		return v.(QueryCache)
+	}
+	queryCacheMu.Lock()
+	defer queryCacheMu.Unlock()
+	if v := bs.queryCache.Val(); v != nil {
+		return v.(QueryCache)
+	}
+	configs.RLock()
+	node, err := getConfigNodeByGroup(bs.group, true)
+	configs.RUnlock()
+	cache := (QueryCache)(nil)
+	if err == nil && node.CacheRedis != "" {
+		if redis := gredis.Instance(node.CacheRedis); redis != nil {
+			cache = NewRedisQueryCache(redis, bs.group)
+		}
+	}
+	if cache == nil {
+		cache = NewMemQueryCache(bs.cache)
+	}
+	bs.queryCache.Set(cache)
+	return cache
+}
+
+// 获取链式操作查询缓存的并发查询合并对象
+func (bs *dbBase) getCacheFlight() *cacheFlight {
+	return bs.cacheFlight
+}
+
+// 解析链式操作的数据表字符串，返回数据表名称列表，例如："user u LEFT JOIN user_detail ud ON ..." 返回 [user user_detail]
+func parseTableNames(tables string) []string {
+	names := make([]string, 0)
+	parts := gregex.Split(`(?i)\s+(?:(?:LEFT|RIGHT|INNER|OUTER|CROSS|FULL)\s+)*JOIN\s+|,`, tables)
+	for _, part := range parts {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.Trim(fields[0], "`\"[]")
+		// 带有数据库名称的数据表，例如：db.user
+		if pos := strings.LastIndex(name, "."); pos >= 0 {
+			name = strings.Trim(name[pos+1:], "`\"[]")
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
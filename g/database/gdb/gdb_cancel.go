@@ -0,0 +1,134 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gf/g/os/glog"
+)
+
+const (
+	gCANCEL_STATEMENT_TIMEOUT = 5 * time.Second // 执行服务端取消语句的超时时间
+)
+
+// 设置是否在SQL操作的上下文取消或者超时时，在数据库服务端取消正在执行的语句，
+// 例如MySQL执行 KILL QUERY，PostgreSQL执行 pg_cancel_backend，避免客户端放弃的慢查询在服务端继续执行。
+// 开启后可以取消的上下文(设置了超时时间或者可以取消)中的每个SQL操作需要额外查询一次连接ID，
+// 并且连接池中的操作将使用固定的连接执行(不使用预处理语句缓存)。
+// MSSQL/SQLite等驱动在上下文取消时会自行中断语句执行，因此不需要(也不支持)服务端取消。
+// 如果enabled为false表示使用节点配置，节点未配置时不开启。
+func (bs *dbBase) SetCancelStatement(enabled bool) {
+	bs.cancelStatement.Set(enabled)
+}
+
+// 判断是否在服务端取消上下文取消或者超时的语句
+func (bs *dbBase) getCancelStatement() bool {
+	if bs.cancelStatement.Val() {
+		return true
+	}
+	configs.RLock()
+	defer configs.RUnlock()
+	if node, err := getConfigNodeByGroup(bs.group, true); err == nil {
+		return node.CancelStatement
+	}
+	return false
+}
+
+// 获取查询当前连接ID的SQL，以及按照连接ID在服务端取消正在执行的语句的SQL(连接ID使用%d占位)，返回空表示不支持
+func (bs *dbBase) getCancelSql() (idSql string, cancelSql string) {
+	return "SELECT CONNECTION_ID()", "KILL QUERY %d"
+}
+
+// 在链接对象上执行SQL操作f，f返回的hold表示连接是否仍被结果集使用(结果集关闭之后才能释放连接)。
+// 开启了服务端取消并且上下文可以取消时，f执行期间上下文取消或者超时将在服务端取消正在执行的语句；
+// 查询结果集读取期间上下文取消或者超时时由驱动关闭连接中断查询。
+func (bs *dbBase) doCancelable(ctx context.Context, link dbLink, f func(link dbLink) (hold bool, err error)) error {
+	if ctx.Done() == nil || !bs.getCancelStatement() {
+		_, err := f(link)
+		return err
+	}
+	idSql, cancelSql := bs.db.getCancelSql()
+	if idSql == "" {
+		_, err := f(link)
+		return err
+	}
+	var (
+		pool *sql.DB   // 执行取消语句的连接池，与执行语句的连接属于同一个数据库服务
+		conn *sql.Conn // 从连接池中获取的固定连接
+		row  *sql.Row  // 连接ID查询结果
+	)
+	switch l := link.(type) {
+	case *sql.DB:
+		c, err := l.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		pool, conn, link = l, c, &connLink{c}
+		row = c.QueryRowContext(ctx, idSql)
+	case *txLink:
+		pool = l.master
+		row = l.QueryRowContext(ctx, idSql)
+	default:
+		_, err := f(link)
+		return err
+	}
+	hold := false
+	defer func() {
+		if conn == nil {
+			return
+		}
+		if hold {
+			// 等待结果集关闭后释放连接
+			go conn.Close()
+		} else {
+			conn.Close()
+		}
+	}()
+	id := int64(0)
+	if err := row.Scan(&id); err != nil {
+		// 获取连接ID失败时不影响SQL操作的执行
+		hold, err = f(link)
+		return err
+	}
+	// 上下文取消或者超时时驱动通常立即返回错误，但是语句仍然在服务端执行，因此f返回之后也需要判断是否取消，
+	// 取消语句执行完成之后才释放连接(或者继续执行事务中的其他语句)，避免取消后续的其他语句。
+	var (
+		done   = make(chan struct{})
+		exited = make(chan struct{})
+	)
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		if ctx.Err() != nil {
+			bs.execCancelSql(pool, fmt.Sprintf(cancelSql, id))
+		}
+	}()
+	hold, err := f(link)
+	close(done)
+	<-exited
+	return err
+}
+
+// 使用另外的连接在服务端执行取消语句
+func (bs *dbBase) execCancelSql(pool *sql.DB, cancelSql string) {
+	ctx, cancel := context.WithTimeout(context.Background(), gCANCEL_STATEMENT_TIMEOUT)
+	defer cancel()
+	if _, err := pool.ExecContext(ctx, cancelSql); err != nil {
+		if logger := bs.GetLogger(); logger != nil {
+			logger.Errorf("cancel statement failed: %s, %v", cancelSql, err)
+		} else {
+			glog.Errorf("cancel statement failed: %s, %v", cancelSql, err)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gf/g/os/gtime"
+	"github.com/gf/g/text/gregex"
+)
+
+// ClickHouse的适配，适用于分析型的数据查询及批量写入.
+// 使用原生协议(TCP)时需要import:
+// _ "github.com/ClickHouse/clickhouse-go"
+// 使用HTTP协议时需要import(LinkInfo以http://或者https://开头):
+// _ "github.com/mailru/go-clickhouse"
+//
+// 说明：
+//     1.写入操作按照批次量以数据块(block)的方式写入，每个批次在独立的事务中提交;
+//     2.UPDATE/DELETE语句自动转换为ALTER TABLE的mutation语句，mutation是异步执行的;
+//     3.不支持replace/save/ignore写入操作，也不支持LastInsertId方法;
+//     4.事务仅用于批量写入数据块，Rollback只能丢弃尚未提交的数据块，事务中执行的其他语句会立即生效且无法回滚.
+
+// 数据库链接对象
+type dbClickhouse struct {
+	*dbBase
+}
+
+// 创建SQL操作对象，内部采用了lazy link处理
+func (db *dbClickhouse) Open(config *ConfigNode) (*sql.DB, error) {
+	source := ""
+	if config.LinkInfo != "" {
+		source = config.LinkInfo
+	} else {
+		source = fmt.Sprintf("tcp://%s:%s?username=%s&password=%s&database=%s",
+			config.Host, config.Port, url.QueryEscape(config.User), url.QueryEscape(config.Pass), url.QueryEscape(config.Name))
+	}
+	driverName := "clickhouse"
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		driverName = "chhttp"
+	}
+	if db, err := sql.Open(driverName, source); err == nil {
+		return db, nil
+	} else {
+		return nil, err
+	}
+}
+
+// 获得关键字操作符
+func (db *dbClickhouse) getChars() (charLeft string, charRight string) {
+	return "`", "`"
+}
+
+// ClickHouse需要按照query_id取消查询(KILL QUERY WHERE query_id)，驱动不提供查询ID，这里不支持
+func (db *dbClickhouse) getCancelSql() (idSql string, cancelSql string) {
+	return "", ""
+}
+
+// 在执行sql之前对sql进行进一步处理，将UPDATE/DELETE语句转换为ALTER TABLE的mutation语句
+func (db *dbClickhouse) handleSqlBeforeExec(query string) string {
+	if match, _ := gregex.MatchString(`(?is)^\s*UPDATE\s+(.+?)\s+SET\s+(.+?)(\s+WHERE\s+(.+))?\s*$`, query); len(match) > 0 {
+		return fmt.Sprintf("ALTER TABLE %s UPDATE %s WHERE %s", match[1], match[2], clickhouseMutationWhere(match[4]))
+	}
+	if match, _ := gregex.MatchString(`(?is)^\s*DELETE\s+FROM\s+(.+?)(\s+WHERE\s+(.+))?\s*$`, query); len(match) > 0 {
+		return fmt.Sprintf("ALTER TABLE %s DELETE WHERE %s", match[1], clickhouseMutationWhere(match[3]))
+	}
+	return query
+}
+
+// mutation语句必须带有WHERE条件，没有条件时表示操作所有数据
+func clickhouseMutationWhere(condition string) string {
+	if condition == "" {
+		return "1=1"
+	}
+	return condition
+}
+
+// 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值为字段数据类型.
+func (db *dbClickhouse) getTableFields(table string) (fields map[string]string, err error) {
+	return db.getTableFieldsWithCache(table, func() (map[string]string, error) {
+		charL, charR := db.getChars()
+		result, err := db.GetAll(fmt.Sprintf(`DESCRIBE TABLE %s%s%s`, charL, table, charR))
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]string)
+		for _, m := range result {
+			fields[m["name"].String()] = strings.ToLower(m["type"].String())
+		}
+		return fields, nil
+	})
+}
+
+// 获取数据表名称列表，schema为空时表示当前数据库
+func (db *dbClickhouse) doTables(schema string) ([]string, error) {
+	result, err := db.GetAll(`SELECT name FROM system.tables WHERE database = if(? = '', currentDatabase(), ?) ORDER BY name`, schema, schema)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, len(result))
+	for i, m := range result {
+		tables[i] = m["name"].String()
+	}
+	return tables, nil
+}
+
+// 获取数据表的字段结构信息，主键(排序键)字段标记为PRI，Nullable类型的字段允许NULL
+func (db *dbClickhouse) doTableFields(table string, schema string) (map[string]*TableField, error) {
+	result, err := db.GetAll(`SELECT name, type, default_kind, default_expression, comment, is_in_primary_key
+	FROM system.columns WHERE database = if(? = '', currentDatabase(), ?) AND table = ? ORDER BY position`, schema, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf(`table "%s" does not exist`, table)
+	}
+	fields := make(map[string]*TableField, len(result))
+	for i, m := range result {
+		fieldType := m["type"].String()
+		field := &TableField{
+			Index:   i,
+			Name:    m["name"].String(),
+			Type:    strings.ToLower(fieldType),
+			Null:    strings.HasPrefix(fieldType, "Nullable("),
+			Comment: m["comment"].String(),
+			Enum:    parseEnumValues(fieldType),
+		}
+		if m["is_in_primary_key"].Int() == 1 {
+			field.Key = "PRI"
+		}
+		if m["default_kind"].String() != "" {
+			field.Default = m["default_expression"].String()
+		}
+		fields[field.Name] = field
+	}
+	return fields, nil
+}
+
+// 获取save操作(upsert)写入语句的冲突更新子句，ClickHouse不支持(可使用ReplacingMergeTree引擎实现数据去重)
+func (db *dbClickhouse) getSaveClause(fields []string, conflict []string) (string, error) {
+	return "", errors.New("save operation is not supported by clickhouse")
+}
+
+// 单条数据写入，统一使用数据块的方式写入，仅支持insert操作
+func (db *dbClickhouse) doInsert(link dbLink, table string, data interface{}, option int, batch ...int) (result sql.Result, err error) {
+	return db.doBatchInsert(link, table, data, option, batch...)
+}
+
+// 批量写入数据，每个批次的数据通过预处理语句写入同一个数据块，并在事务提交时发送到服务端。
+// 参数list支持map/struct/slice类型，例如: []map/[]struct/[]*struct。
+func (db *dbClickhouse) doBatchInsert(link dbLink, table string, list interface{}, option int, batch ...int) (result sql.Result, err error) {
+	if option != OPTION_INSERT {
+		return nil, fmt.Errorf(`%s operation is not supported by clickhouse`, strings.ToLower(getInsertOperationByOption(option)))
+	}
+	listMap, err := convertListToMaps(list)
+	if err != nil {
+		return nil, err
+	}
+	if len(listMap) < 1 {
+		return nil, errors.New("empty data list")
+	}
+	if link == nil {
+		if link, err = db.Master(); err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]string, 0, len(listMap[0]))
+	holders := make([]string, 0, len(listMap[0]))
+	for k, _ := range listMap[0] {
+		keys = append(keys, k)
+		holders = append(holders, "?")
+	}
+	charL, charR := db.getChars()
+	query := fmt.Sprintf("INSERT INTO %s(%s) VALUES(%s)",
+		table, charL+strings.Join(keys, charL+","+charR)+charR, strings.Join(holders, ","))
+	batchNum := gDEFAULT_BATCH_NUM
+	if len(batch) > 0 && batch[0] > 0 {
+		batchNum = batch[0]
+	}
+	rowsAffected := int64(0)
+	for i := 0; i < len(listMap); i += batchNum {
+		end := i + batchNum
+		if end > len(listMap) {
+			end = len(listMap)
+		}
+		rows := make([][]interface{}, 0, end-i)
+		for _, m := range listMap[i:end] {
+			params := make([]interface{}, len(keys))
+			for j, k := range keys {
+				params[j] = convertParam(m[k])
+			}
+			rows = append(rows, params)
+		}
+		op, err := db.handleOperation(OPERATION_EXEC, link, query, nil, func(op *Operation) error {
+			return db.insertBlock(op, rows)
+		})
+		if err != nil {
+			return nil, formatError(err, op.Sql)
+		}
+		rowsAffected += int64(len(rows))
+		if getDryRunCapture(op.Ctx) == nil {
+			db.markWrite(link, op.Ctx)
+		}
+	}
+	return driver.RowsAffected(rowsAffected), nil
+}
+
+// 将一个批次的数据写入数据块，链接对象为事务时写入该事务的数据块，由事务提交时发送
+func (db *dbClickhouse) insertBlock(op *Operation, rows [][]interface{}) (err error) {
+	if db.captureDryRun(op) {
+		return nil
+	}
+	op.Start = gtime.Millisecond()
+	defer func() {
+		op.End = gtime.Millisecond()
+		db.recordSql(op, err)
+		db.checkSlowSql(op, err)
+	}()
+	ctx, cancel := db.timeoutCtx(op.Ctx)
+	defer cancel()
+	var tx *sql.Tx
+	switch link := op.link.(type) {
+	case *txLink:
+		tx = link.Tx
+	case *sql.Tx:
+		tx = link
+	case *sql.DB:
+		if tx, err = link.BeginTx(ctx, nil); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+			} else {
+				err = tx.Commit()
+			}
+		}()
+	default:
+		return errors.New("unsupported link type for clickhouse batch insert")
+	}
+	stmt, err := tx.PrepareContext(ctx, op.Sql)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, params := range rows {
+		if _, err = stmt.ExecContext(ctx, params...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
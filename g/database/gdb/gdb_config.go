@@ -31,7 +31,7 @@ type ConfigNode struct {
 	User             string // 账号
 	Pass             string // 密码
 	Name             string // 数据库名称
-	Type             string // 数据库类型：mysql, sqlite, mssql, pgsql, oracle(目前仅支持mysql)
+	Type             string // 数据库类型：mysql, sqlite, mssql, pgsql, oracle, clickhouse(目前仅支持mysql)
 	Role             string // (可选，默认为master)数据库的角色，用于主从操作分离，至少需要有一个master，参数值：master, slave
 	Charset          string // (可选，默认为 utf8)编码，默认为 utf8
 	Priority         int    // (可选)用于负载均衡的权重计算，当集群中只有一个节点时，权重没有任何意义
@@ -39,6 +39,17 @@ type ConfigNode struct {
 	MaxIdleConnCount int    // (可选)连接池最大限制的连接数
 	MaxOpenConnCount int    // (可选)连接池最大打开的连接数
 	MaxConnLifetime  int    // (可选，单位秒)连接对象可重复使用的时间长度
+	TableFieldsTTL   int    // (可选，单位秒)数据表字段结构的缓存时间，默认为0表示不过期
+	QueryTimeout     int    // (可选，单位毫秒)SQL操作的默认超时时间，默认为0表示不限制
+	CacheRedis       string // (可选)链式操作查询缓存使用的gredis配置分组名称，默认为空表示使用内存缓存
+	SlowThreshold    int    // (可选，单位毫秒)慢查询阈值，执行时间超过阈值的SQL记录到日志，默认为0表示不记录
+	SlowExplain      bool   // (可选)debug模式下是否对慢查询自动执行EXPLAIN并记录执行计划
+	EncryptKey       string // (可选)orm:"encrypt"标签字段的加密密钥(AES，长度为16/24/32字节)，为空时使用环境变量GF_GDB_ENCRYPTKEY
+	IdGenerator      string // (可选)链式操作写入数据时的主键生成策略：uuidv4, uuidv7, snowflake，默认为空表示不生成主键值
+	WorkerId         int    // (可选)雪花算法的机器ID(0-1023)，同时运行的多个进程应当使用不同的机器ID
+	CancelStatement  bool   // (可选)SQL操作的上下文取消或者超时时是否在服务端取消正在执行的语句(MySQL/PostgreSQL)
+	StmtCacheSize    int    // (可选)每个数据库分组缓存的预处理语句数量，默认为0表示不缓存
+	TimeMaintain     bool   // (可选)是否启用链式操作的时间字段(created_at, updated_at)自动维护及软删除(deleted_at)特性，默认不启用
 }
 
 // 数据库配置包内对象
@@ -158,6 +169,12 @@ func (bs *dbBase) SetConnMaxLifetime(n int) {
 	bs.maxConnLifetime.Set(n)
 }
 
+// 设置数据表字段结构的缓存时间(单位秒)，新的缓存时间在字段结构下一次缓存时生效
+// 如果 n <= 0 表示使用节点配置，节点未配置时缓存不过期
+func (bs *dbBase) SetTableFieldsTTL(n int) {
+	bs.tableFieldsTTL.Set(n)
+}
+
 // 节点配置转换为字符串
 func (node *ConfigNode) String() string {
 	if node.LinkInfo != "" {
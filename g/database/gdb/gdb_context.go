@@ -0,0 +1,134 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"context"
+	"time"
+)
+
+// 带有上下文的链接对象，用于查询结果集读取完成之前保持查询的超时上下文
+type ctxLink struct {
+	dbLink
+	ctx context.Context
+}
+
+// 返回使用上下文ctx的数据库对象，该对象与当前对象共享配置、连接池及缓存，
+// 通过该对象执行的SQL操作及开启的事务将在ctx取消或者超时时中断，
+// 例如在ghttp请求处理中使用r.Context()，请求中断(客户端断开连接)时将取消正在执行的SQL操作。
+func (bs *dbBase) Ctx(ctx context.Context) DB {
+	base := *bs
+	base.ctx = ctx
+	switch bs.db.(type) {
+	case *dbPgsql:
+		base.db = &dbPgsql{dbBase: &base}
+	case *dbMssql:
+		base.db = &dbMssql{dbBase: &base}
+	case *dbSqlite:
+		base.db = &dbSqlite{dbBase: &base}
+	case *dbOracle:
+		base.db = &dbOracle{dbBase: &base}
+	case *dbClickhouse:
+		base.db = &dbClickhouse{dbBase: &base}
+	default:
+		base.db = &dbMysql{dbBase: &base}
+	}
+	return base.db
+}
+
+// 获取当前数据库对象的上下文，未设置时返回context.Background()
+func (bs *dbBase) GetCtx() context.Context {
+	if bs.ctx != nil {
+		return bs.ctx
+	}
+	return context.Background()
+}
+
+// 设置SQL操作的默认超时时间(单位毫秒)，超时的SQL操作将被取消并返回错误，
+// 查询操作的超时时间包含结果集的读取时间，注意Query方法返回的结果集由调用方读取，因此不受默认超时时间限制。
+// 如果 n <= 0 表示使用节点配置，节点未配置时不限制。
+func (bs *dbBase) SetQueryTimeout(n int) {
+	bs.queryTimeout.Set(n)
+}
+
+// 获得SQL操作的默认超时时间(单位毫秒)，0表示不限制
+func (bs *dbBase) getQueryTimeout() int {
+	if n := bs.queryTimeout.Val(); n > 0 {
+		return n
+	}
+	configs.RLock()
+	defer configs.RUnlock()
+	if node, err := getConfigNodeByGroup(bs.group, true); err == nil && node.QueryTimeout > 0 {
+		return node.QueryTimeout
+	}
+	return 0
+}
+
+// 返回带有默认超时时间的上下文，使用完成后需要调用返回的取消方法释放资源
+func (bs *dbBase) timeoutCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if n := bs.getQueryTimeout(); n > 0 {
+		return context.WithTimeout(ctx, time.Duration(n)*time.Millisecond)
+	}
+	return ctx, func() {}
+}
+
+// 获取链接对象的上下文及实际执行SQL操作的链接对象
+func (bs *dbBase) linkCtx(link dbLink) (context.Context, dbLink) {
+	if l, ok := link.(*ctxLink); ok {
+		return l.ctx, l.dbLink
+	}
+	return bs.GetCtx(), link
+}
+
+// 在链接对象上查询并读取结果集，查询及读取受默认超时时间限制
+func (bs *dbBase) getAll(link dbLink, query string, args ...interface{}) (Result, error) {
+	ctx, cancel := bs.timeoutCtx(bs.GetCtx())
+	defer cancel()
+	ctx, slow := bs.slowQueryCtx(ctx)
+	rows, err := bs.db.doQuery(&ctxLink{dbLink: link, ctx: ctx}, query, args...)
+	if err != nil || rows == nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result, err := bs.db.rowsToResult(rows)
+	if err == nil && slow != nil {
+		// 关闭结果集之后才能在同一个链接(事务)上执行EXPLAIN
+		rows.Close()
+		bs.recordSlowQuery(slow, len(result))
+	}
+	return result, err
+}
+
+// 在链接对象上查询并逐条读取结果集，回调函数f返回false时停止读取，
+// 结果集不会全部读取到内存中，因此查询不受默认超时时间限制，可以通过上下文控制超时。
+func (bs *dbBase) iterate(link dbLink, query string, args []interface{}, f func(record Record) bool) error {
+	rows, err := bs.db.doQuery(&ctxLink{dbLink: link, ctx: bs.GetCtx()}, query, args...)
+	if err != nil || rows == nil {
+		return err
+	}
+	defer rows.Close()
+	return bs.iterateRows(rows, f)
+}
+
+// 返回使用上下文ctx的事务对象，事务中的SQL操作将在ctx取消或者超时时中断
+func (tx *TX) Ctx(ctx context.Context) *TX {
+	return &TX{
+		db:     tx.db.Ctx(ctx),
+		tx:     tx.tx,
+		master: tx.master,
+	}
+}
+
+// 链式操作，设置SQL操作的上下文，SQL操作将在ctx取消或者超时时中断
+func (md *Model) Ctx(ctx context.Context) *Model {
+	model := md.getModel()
+	model.db = model.db.Ctx(ctx)
+	if model.tx != nil {
+		model.tx = model.tx.Ctx(ctx)
+	}
+	return model
+}
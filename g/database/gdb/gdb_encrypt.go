@@ -0,0 +1,268 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/cmdenv"
+	"github.com/gf/g/os/glog"
+	"github.com/gf/g/util/gconv"
+)
+
+const (
+	// 加密密钥的命令行参数/环境变量名称，例如：--gf.gdb.encryptkey=xxx 或者 GF_GDB_ENCRYPTKEY=xxx
+	gENCRYPT_KEY_NAME = "gf.gdb.encryptkey"
+	// 加密字段的orm标签选项，例如：orm:"encrypt"、orm:"encrypt:deterministic"
+	gORM_TAG_ENCRYPT = "encrypt"
+	// 确定性加密模式，相同的明文加密结果相同，可用于等值查询
+	gENCRYPT_DETERMINISTIC = "deterministic"
+	// 由加密密钥派生加密子密钥及确定性加密模式的nonce子密钥时使用的标签
+	gENCRYPT_LABEL_CIPHER = "gf.gdb.encrypt.cipher"
+	gENCRYPT_LABEL_NONCE  = "gf.gdb.encrypt.nonce"
+)
+
+var (
+	// 查询条件中使用LIKE的字段名称，字段名称可以带有表名前缀及引号
+	encryptedLikeRegex = regexp.MustCompile("(?i)(?:^|[^\\w])[`\"\\[]?(\\w+)[`\"\\]]?\\s+(?:NOT\\s+)?LIKE\\b")
+)
+
+// 设置orm:"encrypt"标签字段的加密密钥(AES，长度必须为16/24/32字节)，
+// 未设置时使用节点配置的EncryptKey，节点未配置时使用命令行参数gf.gdb.encryptkey或者环境变量GF_GDB_ENCRYPTKEY。
+func (bs *dbBase) SetEncryptKey(key string) {
+	bs.encryptKey.Set(key)
+}
+
+// 使用确定性加密模式加密value，用于查询orm:"encrypt:deterministic"标签字段的等值条件，例如：
+// db.Table("user").Where("phone", db.MustEncrypt(phone)).One()
+// 注意非确定性加密模式(orm:"encrypt")的字段每次加密结果不同，无法用于查询条件。
+func (bs *dbBase) Encrypt(value interface{}) (string, error) {
+	v, err := bs.encryptValue(value, true)
+	if err != nil {
+		return "", err
+	}
+	return gconv.String(v), nil
+}
+
+// 使用确定性加密模式加密value，失败时panic，参考Encrypt
+func (bs *dbBase) MustEncrypt(value interface{}) string {
+	s, err := bs.Encrypt(value)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// 获取加密密钥，未配置或者长度不合法时返回错误
+func (bs *dbBase) getEncryptKey() ([]byte, error) {
+	key := bs.encryptKey.Val()
+	if key == "" {
+		configs.RLock()
+		if node, err := getConfigNodeByGroup(bs.group, true); err == nil {
+			key = node.EncryptKey
+		}
+		configs.RUnlock()
+	}
+	if key == "" {
+		key = cmdenv.Get(gENCRYPT_KEY_NAME).String()
+	}
+	if key == "" {
+		return nil, errors.New("encryption key is not configured, use SetEncryptKey, configuration EncryptKey or environment GF_GDB_ENCRYPTKEY")
+	}
+	if n := len(key); n != 16 && n != 24 && n != 32 {
+		return nil, fmt.Errorf("invalid encryption key length %d, it should be 16, 24 or 32", n)
+	}
+	return []byte(key), nil
+}
+
+// 由加密密钥派生用途为label的子密钥(HMAC-SHA256)，长度为size字节，不同用途使用不同的子密钥
+func deriveEncryptKey(key []byte, label string, size int) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(label))
+	return mac.Sum(nil)[:size]
+}
+
+// 使用加密密钥派生的子密钥创建AES-GCM加密对象
+func getEncryptCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveEncryptKey(key, gENCRYPT_LABEL_CIPHER, len(key)))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// 加密字段值，使用AES-GCM加密，结果为base64编码的"nonce+密文+认证标签"，nil值不加密。
+// 确定性加密模式使用SIV方式：nonce由明文的HMAC(使用独立派生的子密钥)生成，因此相同的明文加密结果相同，
+// 并且只会暴露明文是否相同，否则使用随机nonce。
+func (bs *dbBase) encryptValue(value interface{}, deterministic bool) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	key, err := bs.getEncryptKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := getEncryptCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain := gconv.Bytes(value)
+	nonce := make([]byte, aead.NonceSize())
+	if deterministic {
+		mac := hmac.New(sha256.New, deriveEncryptKey(key, gENCRYPT_LABEL_NONCE, sha256.Size))
+		mac.Write(plain)
+		copy(nonce, mac.Sum(nil))
+	} else if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plain, nil)), nil
+}
+
+// 解密encryptValue加密的字段值，密文被篡改时返回错误，nil值不解密
+func (bs *dbBase) decryptValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	key, err := bs.getEncryptKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := getEncryptCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(gconv.String(value))
+	if err != nil || len(data) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("invalid encrypted value")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+	return string(plain), nil
+}
+
+// 获取结构体中orm:"encrypt"标签的字段，键名为转换为map后的键名，键值表示是否为确定性加密模式
+func getEncryptFields(obj interface{}) map[string]bool {
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := (map[string]bool)(nil)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		option, ok := parseOrmTag(field.Tag.Get("orm"))[gORM_TAG_ENCRYPT]
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]bool)
+		}
+		fields[getStructFieldMapKey(field)] = option == gENCRYPT_DETERMINISTIC
+	}
+	return fields
+}
+
+// 加密写入数据中的加密字段
+func (md *Model) encryptData(data Map, fields map[string]bool) error {
+	for k, deterministic := range fields {
+		if v, ok := data[k]; ok {
+			encrypted, err := md.db.encryptValue(v, deterministic)
+			if err != nil {
+				return err
+			}
+			data[k] = encrypted
+		}
+	}
+	return nil
+}
+
+// 解密查询结果中objPointer结构体的加密字段，返回新的记录，不修改原有记录(可能为查询缓存)
+func (md *Model) decryptRecord(record Record, objPointer interface{}) (Record, error) {
+	fields := getEncryptFields(objPointer)
+	if len(fields) == 0 || record == nil {
+		return record, nil
+	}
+	md.checkEncryptedLike(fields)
+	return md.doDecryptRecord(record, fields)
+}
+
+// 解密查询结果中objPointerSlice元素结构体的加密字段，参考decryptRecord
+func (md *Model) decryptResult(result Result, objPointerSlice interface{}) (Result, error) {
+	fields := getEncryptFields(objPointerSlice)
+	if len(fields) == 0 {
+		return result, nil
+	}
+	md.checkEncryptedLike(fields)
+	newResult := make(Result, len(result))
+	for i, record := range result {
+		r, err := md.doDecryptRecord(record, fields)
+		if err != nil {
+			return nil, err
+		}
+		newResult[i] = r
+	}
+	return newResult, nil
+}
+
+// 解密记录中的加密字段，记录的键名与结构体属性按照忽略大小写及下划线的方式匹配
+func (md *Model) doDecryptRecord(record Record, fields map[string]bool) (Record, error) {
+	names := make(map[string]struct{}, len(fields))
+	for k := range fields {
+		names[formatStructMatchName(k)] = struct{}{}
+	}
+	newRecord := make(Record, len(record))
+	for k, v := range record {
+		newRecord[k] = v
+		if _, ok := names[formatStructMatchName(k)]; !ok || v == nil || v.IsNil() {
+			continue
+		}
+		plain, err := md.db.decryptValue(v.Val())
+		if err != nil {
+			return nil, fmt.Errorf(`decrypt field "%s" failed: %v`, k, err)
+		}
+		newRecord[k] = gvar.New(plain, true)
+	}
+	return newRecord, nil
+}
+
+// 加密字段无法使用LIKE模糊查询，查询条件中对加密字段使用LIKE时输出警告日志
+func (md *Model) checkEncryptedLike(fields map[string]bool) {
+	if md.where == "" {
+		return
+	}
+	for _, match := range encryptedLikeRegex.FindAllStringSubmatch(md.where, -1) {
+		for k := range fields {
+			if !strings.EqualFold(match[1], k) {
+				continue
+			}
+			s := fmt.Sprintf(`encrypted field "%s" cannot be queried using LIKE: %s`, k, md.where)
+			if logger := md.db.GetLogger(); logger != nil {
+				logger.Warning(s)
+			} else {
+				glog.Warning(s)
+			}
+		}
+	}
+}
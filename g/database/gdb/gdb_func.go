@@ -8,13 +8,14 @@ package gdb
 
 import (
 	"bytes"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/gf/g/os/glog"
 	"github.com/gf/g/os/gtime"
 	"github.com/gf/g/text/gregex"
 	"github.com/gf/g/text/gstr"
@@ -26,8 +27,26 @@ type apiString interface {
 	String() string
 }
 
+// Type assert api for TableName().
+type apiTableName interface {
+	TableName() string
+}
+
+var (
+	// sql.Scanner接口类型，用于判断结构体属性是否自定义了数据库字段值的映射
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	// 不需要递归映射的结构体类型
+	timeType  = reflect.TypeOf(time.Time{})
+	gtimeType = reflect.TypeOf(gtime.Time{})
+)
+
 // 格式化SQL查询条件
 func formatCondition(where interface{}, args []interface{}) (newWhere string, newArgs []interface{}) {
+	// 嵌套的条件构造对象
+	if b, ok := where.(*WhereBuilder); ok {
+		newWhere, newArgs = b.Build()
+		return newWhere, append(newArgs, args...)
+	}
 	// 条件字符串处理
 	buffer := bytes.NewBuffer(nil)
 	// 使用反射进行类型判断
@@ -138,6 +157,14 @@ func formatCondition(where interface{}, args []interface{}) (newWhere string, ne
 // 将预处理参数转换为底层数据库引擎支持的格式。
 // 主要是判断参数是否为复杂数据类型，如果是，那么转换为基础类型。
 func convertParam(value interface{}) interface{} {
+	// 实现了driver.Valuer接口的参数由底层数据库引擎转换，例如：decimal/UUID/JSONB等自定义类型
+	if _, ok := value.(driver.Valuer); ok {
+		return value
+	}
+	// JSON类型的值(*gjson.Json/map/slice)编码为JSON字符串
+	if v, ok := encodeJsonValue(value, false); ok {
+		return v
+	}
 	rv := reflect.ValueOf(value)
 	kind := rv.Kind()
 	if kind == reflect.Ptr {
@@ -164,22 +191,6 @@ func convertParam(value interface{}) interface{} {
 	return value
 }
 
-// 打印SQL对象(仅在debug=true时有效)
-func printSql(v *Sql) {
-	s := fmt.Sprintf("%s, %v, %s, %s, %d ms, %s", v.Sql, v.Args,
-		gtime.NewFromTimeStamp(v.Start).Format("Y-m-d H:i:s.u"),
-		gtime.NewFromTimeStamp(v.End).Format("Y-m-d H:i:s.u"),
-		v.End-v.Start,
-		v.Func,
-	)
-	if v.Error != nil {
-		s += "\nError: " + v.Error.Error()
-		glog.Backtrace(true, 2).Error(s)
-	} else {
-		glog.Debug(s)
-	}
-}
-
 // 格式化错误信息
 func formatError(err error, query string, args ...interface{}) error {
 	if err != nil {
@@ -210,7 +221,22 @@ func getInsertOperationByOption(option int) string {
 // 该方法用于将变量传递给数据库执行之前。
 func structToMap(obj interface{}) map[string]interface{} {
 	data := gconv.Map(obj)
+	// 关联属性不作为数据表字段
+	for _, key := range getOrmWithKeys(obj) {
+		delete(data, key)
+	}
+	jsonFields := getJsonFields(obj)
 	for key, value := range data {
+		// 实现了driver.Valuer接口的属性由底层数据库引擎转换
+		if _, ok := value.(driver.Valuer); ok {
+			continue
+		}
+		// JSON类型的属性(*gjson.Json/map/slice，以及orm:"json"标签的属性)编码为JSON字符串
+		_, force := jsonFields[key]
+		if v, ok := encodeJsonValue(value, force); ok {
+			data[key] = v
+			continue
+		}
 		rv := reflect.ValueOf(value)
 		kind := rv.Kind()
 		if kind == reflect.Ptr {
@@ -240,7 +266,245 @@ func structToMap(obj interface{}) map[string]interface{} {
 	return data
 }
 
-// 使用递归的方式将map键值对映射到struct对象上，注意参数<pointer>是一个指向struct的指针。
+// 使用递归的方式将map键值对映射到struct对象上，注意参数<pointer>是一个指向struct的指针(或者struct的反射对象)。
+// 1. 匿名(继承)结构体及其指针使用同一个map递归映射，指针为空时自动创建;
+// 2. 属性类型实现了sql.Scanner接口时，使用Scan方法映射数据库字段值，例如：decimal/UUID/JSONB等自定义类型;
+// 3. 带有orm:"with:..."标签的关联属性不做映射，由Model.With关联查询后映射。
 func mapToStruct(data map[string]interface{}, pointer interface{}) error {
-	return gconv.StructDeep(data, pointer)
+	elem, ok := pointer.(reflect.Value)
+	if !ok {
+		elem = reflect.ValueOf(pointer)
+	}
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return errors.New("object pointer cannot be nil")
+		}
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("object pointer should be type of struct pointer, but got: %v", elem.Kind())
+	}
+	// 格式化后的键名映射，用于忽略大小写及下划线匹配属性名称
+	keys := make(map[string]string, len(data))
+	for k, _ := range data {
+		keys[formatStructMatchName(k)] = k
+	}
+	return doMapToStruct(data, keys, elem)
+}
+
+// 将map键值对映射到struct对象的属性上
+func doMapToStruct(data map[string]interface{}, keys map[string]string, elem reflect.Value) error {
+	elemType := elem.Type()
+	for i := 0; i < elem.NumField(); i++ {
+		field := elemType.Field(i)
+		// 只映射公开属性
+		if !elem.Field(i).CanSet() || isOrmWithField(field) {
+			continue
+		}
+		if field.Anonymous {
+			if err := mapToNestedStruct(data, keys, elem.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		key, ok := getStructFieldKey(field, data, keys)
+		if !ok {
+			// 没有对应键值的结构体属性，使用同一个map递归映射
+			if err := mapToNestedStruct(data, keys, elem.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := bindValueToStructField(elem, i, data[key]); err != nil {
+			return fmt.Errorf(`cannot bind value of "%s" to attribute "%s": %v`, key, field.Name, err)
+		}
+	}
+	return nil
+}
+
+// 递归映射结构体(指针)类型的属性，指针为空并且有属性被映射时自动创建
+func mapToNestedStruct(data map[string]interface{}, keys map[string]string, value reflect.Value) error {
+	t := value.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType || t == gtimeType || reflect.PtrTo(t).Implements(scannerType) {
+		return nil
+	}
+	if value.Kind() == reflect.Ptr {
+		if !value.IsNil() {
+			return doMapToStruct(data, keys, value.Elem())
+		}
+		pointer := reflect.New(t)
+		if err := doMapToStruct(data, keys, pointer.Elem()); err != nil {
+			return err
+		}
+		if !pointer.Elem().IsZero() {
+			value.Set(pointer)
+		}
+		return nil
+	}
+	return doMapToStruct(data, keys, value)
+}
+
+// 获取结构体属性对应的键名，优先使用gconv/json标签名称，其次忽略大小写及下划线匹配属性名称
+func getStructFieldKey(field reflect.StructField, data map[string]interface{}, keys map[string]string) (string, bool) {
+	for _, tag := range []string{"gconv", "json"} {
+		name := strings.TrimSpace(strings.Split(field.Tag.Get(tag), ",")[0])
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			if _, ok := data[name]; ok {
+				return name, true
+			}
+		}
+	}
+	key, ok := keys[formatStructMatchName(field.Name)]
+	return key, ok
+}
+
+// 格式化用于匹配属性名称的键名
+func formatStructMatchName(name string) string {
+	return strings.ToLower(gstr.ReplaceByMap(name, map[string]string{
+		"_": "",
+		"-": "",
+		" ": "",
+	}))
+}
+
+// 将键值绑定到对象指定索引位置的属性上，属性类型实现了sql.Scanner接口时使用Scan方法绑定
+func bindValueToStructField(elem reflect.Value, index int, value interface{}) error {
+	attr := elem.Field(index)
+	attrType := attr.Type()
+	if attrType.Kind() == reflect.Ptr && attrType.Implements(scannerType) {
+		if value == nil {
+			attr.Set(reflect.Zero(attrType))
+			return nil
+		}
+		pointer := reflect.New(attrType.Elem())
+		if err := pointer.Interface().(sql.Scanner).Scan(value); err != nil {
+			return err
+		}
+		attr.Set(pointer)
+		return nil
+	}
+	if attr.CanAddr() && reflect.PtrTo(attrType).Implements(scannerType) {
+		return attr.Addr().Interface().(sql.Scanner).Scan(value)
+	}
+	if value == nil {
+		return nil
+	}
+	// JSON类型的字段值解码到*gjson.Json/map/slice/struct类型的属性
+	if ok, err := bindJsonValue(attr, value); ok {
+		return err
+	}
+	if rv := reflect.ValueOf(gconv.Convert(value, attrType.String())); rv.IsValid() && rv.Type().AssignableTo(attrType) {
+		attr.Set(rv)
+		return nil
+	}
+	// 基础类型的自定义类型，例如：type Status int
+	if attrType.Kind() != reflect.Struct && attrType.Kind() != reflect.Ptr {
+		if rv := reflect.ValueOf(gconv.Convert(value, attrType.Kind().String())); rv.IsValid() && rv.Kind() == attrType.Kind() {
+			attr.Set(rv.Convert(attrType))
+			return nil
+		}
+	}
+	// 复杂类型使用gconv递归转换
+	return gconv.Struct(map[string]interface{}{elem.Type().Field(index).Name: value}, elem)
+}
+
+// 判断属性是否为带有orm:"with:..."标签的关联属性
+func isOrmWithField(field reflect.StructField) bool {
+	_, ok := parseOrmTag(field.Tag.Get("orm"))["with"]
+	return ok
+}
+
+// 解析orm标签，返回选项名称及值，例如：orm:"with:uid=id, table:user_detail"
+func parseOrmTag(tag string) map[string]string {
+	options := make(map[string]string)
+	if tag == "" {
+		return options
+	}
+	for _, item := range strings.Split(tag, ",") {
+		array := strings.SplitN(item, ":", 2)
+		if len(array) == 2 {
+			options[strings.TrimSpace(array[0])] = strings.TrimSpace(array[1])
+		} else {
+			options[strings.TrimSpace(array[0])] = ""
+		}
+	}
+	return options
+}
+
+// 获取结构体对象中关联属性转换为map后对应的键名
+func getOrmWithKeys(obj interface{}) []string {
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	keys := make([]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isOrmWithField(field) {
+			continue
+		}
+		keys = append(keys, getStructFieldMapKey(field))
+	}
+	return keys
+}
+
+// 获取结构体属性转换为map后对应的键名，优先使用gconv/json标签名称
+func getStructFieldMapKey(field reflect.StructField) string {
+	for _, tag := range []string{"gconv", "json"} {
+		if key := strings.TrimSpace(strings.Split(field.Tag.Get(tag), ",")[0]); key != "" {
+			return key
+		}
+	}
+	return field.Name
+}
+
+// 将map/struct/slice类型的批量数据转换为List类型，用于批量写入
+func convertListToMaps(list interface{}) (List, error) {
+	listMap := (List)(nil)
+	switch v := list.(type) {
+	case Result:
+		listMap = v.ToList()
+	case Record:
+		listMap = List{v.ToMap()}
+	case List:
+		listMap = v
+	case Map:
+		listMap = List{v}
+	default:
+		rv := reflect.ValueOf(list)
+		kind := rv.Kind()
+		if kind == reflect.Ptr {
+			rv = rv.Elem()
+			kind = rv.Kind()
+		}
+		switch kind {
+		// 如果是slice，那么转换为List类型
+		case reflect.Slice:
+			fallthrough
+		case reflect.Array:
+			listMap = make(List, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				listMap[i] = structToMap(rv.Index(i).Interface())
+			}
+		case reflect.Map:
+			fallthrough
+		case reflect.Struct:
+			listMap = List{Map(structToMap(list))}
+		default:
+			return nil, errors.New(fmt.Sprint("unsupported list type:", kind))
+		}
+	}
+	return listMap, nil
 }
@@ -0,0 +1,174 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gf/g/encoding/gjson"
+)
+
+const (
+	// JSON字段的orm标签选项，例如：orm:"json"，struct类型的属性写入时编码为JSON而不是展开为多个字段
+	gORM_TAG_JSON = "json"
+)
+
+var (
+	// JSON对象的反射类型
+	gjsonType = reflect.TypeOf((*gjson.Json)(nil))
+)
+
+// 链式操作，添加JSON字段包含条件(AND)，value为包含的JSON值(将被编码为JSON)，path为可选的JSON路径，例如："$.tags"。
+// MySQL使用JSON_CONTAINS函数，PostgreSQL使用jsonb的@>操作符，例如：
+// db.Table("user").WhereJsonContains("tags", []string{"vip"}).All()
+// db.Table("user").WhereJsonContains("profile", g.Map{"city": "chengdu"}, "$.address").All()
+func (md *Model) WhereJsonContains(field string, value interface{}, path ...string) *Model {
+	content, err := json.Marshal(value)
+	if err != nil {
+		content = []byte(fmt.Sprintf("%q", fmt.Sprint(value)))
+	}
+	jsonPath := ""
+	if len(path) > 0 {
+		jsonPath = path[0]
+	}
+	where, args := md.db.getJsonContainsSql(md.quoteJsonField(field), jsonPath, string(content))
+	return md.And(where, args...)
+}
+
+// 引用JSON字段名称，已经引用或者带有表名/函数的字段名称不处理
+func (md *Model) quoteJsonField(field string) string {
+	if strings.ContainsAny(field, "`\"[.( ") {
+		return field
+	}
+	charL, charR := md.db.getChars()
+	return charL + field + charR
+}
+
+// 获取JSON字段包含条件的SQL语句及参数，value为JSON编码的值
+func (bs *dbBase) getJsonContainsSql(field string, path string, value string) (string, []interface{}) {
+	if path == "" {
+		return fmt.Sprintf(`JSON_CONTAINS(%s, ?)`, field), []interface{}{value}
+	}
+	return fmt.Sprintf(`JSON_CONTAINS(%s, ?, ?)`, field), []interface{}{value, path}
+}
+
+// 获取结构体中orm:"json"标签的属性转换为map后的键名
+func getJsonFields(obj interface{}) map[string]struct{} {
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := (map[string]struct{})(nil)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := parseOrmTag(field.Tag.Get("orm"))[gORM_TAG_JSON]; !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]struct{})
+		}
+		fields[getStructFieldMapKey(field)] = struct{}{}
+	}
+	return fields
+}
+
+// 将JSON类型的写入值编码为JSON字符串，包括*gjson.Json、map以及slice(不包括[]byte)类型，
+// force为true时其他类型(例如orm:"json"标签的struct属性)也编码为JSON，返回值表示是否已编码。
+func encodeJsonValue(value interface{}, force bool) (interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	if j, ok := value.(*gjson.Json); ok {
+		if j == nil {
+			return nil, true
+		}
+		s, _ := j.ToJsonString()
+		return s, true
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, force
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return value, false
+		}
+	default:
+		if !force {
+			return value, false
+		}
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return value, false
+	}
+	return string(content), true
+}
+
+// 将JSON类型的字段值绑定到*gjson.Json、map、slice或者struct类型的属性上，返回值表示是否已绑定，
+// 字段值不是JSON对象/数组(或null)时不绑定，由其他方式处理。
+func bindJsonValue(attr reflect.Value, value interface{}) (bool, error) {
+	content := ([]byte)(nil)
+	switch v := value.(type) {
+	case []byte:
+		content = v
+	case string:
+		content = []byte(v)
+	default:
+		return false, nil
+	}
+	attrType := attr.Type()
+	if attrType == gjsonType {
+		j, err := gjson.DecodeToJson(content)
+		if err != nil {
+			return true, err
+		}
+		attr.Set(reflect.ValueOf(j))
+		return true, nil
+	}
+	t := attrType
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Struct:
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+	if t == timeType || t == gtimeType {
+		return false, nil
+	}
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 || (content[0] != '{' && content[0] != '[' && !bytes.Equal(content, []byte("null"))) {
+		return false, nil
+	}
+	pointer := reflect.New(attrType)
+	if err := json.Unmarshal(content, pointer.Interface()); err != nil {
+		return true, err
+	}
+	attr.Set(pointer.Elem())
+	return true, nil
+}
@@ -0,0 +1,322 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gf/g/os/glog"
+	"github.com/gf/g/os/gtime"
+	"github.com/gf/g/util/gconv"
+)
+
+const (
+	gSQL_LOG_REDACTED = "***" // 脱敏字段的参数输出值
+)
+
+// 上下文中记录查询操作的键名，用于在读取结果集之后记录慢查询
+type slowQueryCtxKey struct{}
+
+// 需要在读取结果集之后记录慢查询的查询操作
+type slowQueryOperation struct {
+	op *Operation
+}
+
+// SQL日志的格式化选项
+type SqlLogOption struct {
+	InlineArgs   bool     // 是否将预处理参数替换到SQL语句中输出，默认输出带有占位符的SQL语句及参数列表
+	RedactFields []string // 需要脱敏的字段名称(不区分大小写)，这些字段的参数值将输出为"***"
+	Pretty       bool     // 是否将SQL语句按照关键字格式化为多行输出，一般用于开发环境
+}
+
+var (
+	// INSERT/REPLACE语句的字段列表
+	sqlInsertFieldsRegex = regexp.MustCompile(`(?is)^\s*(?:INSERT|REPLACE)\b.*?\(([^)]*)\)\s*VALUES\b`)
+	// 占位符之前的字段名称，例如：`name`=?、u.id IN(?、age >= ?
+	sqlPlaceholderFieldRegex = regexp.MustCompile("(?i)([\\w\\.`\"]+)\\s*(?:=|<>|!=|<=|>=|<|>|\\s+LIKE|\\s+IN\\s*\\()\\s*$")
+	// 需要换行输出的SQL关键字
+	sqlPrettyLineRegex = regexp.MustCompile(`(?i)\s+(FROM|WHERE|GROUP\s+BY|HAVING|ORDER\s+BY|LIMIT|(?:LEFT\s+|RIGHT\s+|INNER\s+|CROSS\s+)?JOIN|SET|VALUES|ON\s+DUPLICATE\s+KEY\s+UPDATE|UNION(?:\s+ALL)?)\b`)
+	// 需要缩进换行输出的SQL条件关键字
+	sqlPrettyIndentRegex = regexp.MustCompile(`(?i)\s+(AND|OR)\s+`)
+	// 可以执行EXPLAIN的查询语句
+	sqlExplainRegex = regexp.MustCompile(`(?is)^\s*SELECT\b`)
+)
+
+// 设置当前数据库分组的日志对象，默认使用glog默认的日志对象
+func (bs *dbBase) SetLogger(logger *glog.Logger) {
+	bs.logger.Set(logger)
+}
+
+// 获取当前数据库分组的日志对象，未设置时返回nil，表示使用glog默认的日志对象
+func (bs *dbBase) GetLogger() *glog.Logger {
+	if v := bs.logger.Val(); v != nil {
+		return v.(*glog.Logger)
+	}
+	return nil
+}
+
+// 设置SQL日志(debug=true时输出)的格式化选项
+func (bs *dbBase) SetSqlLogOption(option SqlLogOption) {
+	bs.sqlLogOption.Set(option)
+}
+
+// 获取SQL日志的格式化选项
+func (bs *dbBase) GetSqlLogOption() SqlLogOption {
+	if v := bs.sqlLogOption.Val(); v != nil {
+		return v.(SqlLogOption)
+	}
+	return SqlLogOption{}
+}
+
+// 打印SQL对象(仅在debug=true时有效)
+func (bs *dbBase) printSql(v *Sql) {
+	s := fmt.Sprintf("%s, %s, %s, %d ms, %s", formatSqlLog(v.Sql, v.Args, bs.GetSqlLogOption()),
+		gtime.NewFromTimeStamp(v.Start).Format("Y-m-d H:i:s.u"),
+		gtime.NewFromTimeStamp(v.End).Format("Y-m-d H:i:s.u"),
+		v.End-v.Start,
+		v.Func,
+	)
+	logger := bs.GetLogger()
+	if v.Error != nil {
+		s += "\nError: " + v.Error.Error()
+		if logger != nil {
+			logger.Backtrace(true, 2).Error(s)
+		} else {
+			glog.Backtrace(true, 2).Error(s)
+		}
+	} else {
+		if logger != nil {
+			logger.Debug(s)
+		} else {
+			glog.Debug(s)
+		}
+	}
+}
+
+// 按照格式化选项将SQL语句及预处理参数格式化为日志内容
+func formatSqlLog(query string, args []interface{}, option SqlLogOption) string {
+	if len(option.RedactFields) > 0 && len(args) > 0 {
+		args = redactSqlArgs(query, args, option.RedactFields)
+	}
+	if option.Pretty {
+		query = sqlPrettyLineRegex.ReplaceAllString(strings.TrimSpace(query), "\n$1")
+		query = sqlPrettyIndentRegex.ReplaceAllString(query, "\n  $1 ")
+	}
+	if !option.InlineArgs {
+		return fmt.Sprintf("%s, %v", query, args)
+	}
+	buffer := strings.Builder{}
+	index := 0
+	for _, c := range query {
+		if c == '?' && index < len(args) {
+			buffer.WriteString(formatSqlArg(args[index]))
+			index++
+		} else {
+			buffer.WriteRune(c)
+		}
+	}
+	return buffer.String()
+}
+
+// 将脱敏字段对应的预处理参数替换为"***"，返回新的参数列表
+func redactSqlArgs(query string, args []interface{}, fields []string) []interface{} {
+	redacted := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		redacted[strings.ToLower(field)] = struct{}{}
+	}
+	newArgs := make([]interface{}, len(args))
+	copy(newArgs, args)
+	for i, field := range sqlPlaceholderFields(query, len(args)) {
+		if _, ok := redacted[field]; ok {
+			newArgs[i] = gSQL_LOG_REDACTED
+		}
+	}
+	return newArgs
+}
+
+// 获取SQL语句中每个占位符对应的字段名称(小写，不包含表名及引号)，无法识别的占位符字段名称为空
+func sqlPlaceholderFields(query string, count int) []string {
+	fields := make([]string, 0, count)
+	// INSERT/REPLACE语句按照字段列表的顺序对应，批量写入时循环对应
+	if match := sqlInsertFieldsRegex.FindStringSubmatchIndex(query); match != nil {
+		columns := strings.Split(query[match[2]:match[3]], ",")
+		for i := 0; i < count; i++ {
+			fields = append(fields, normalizeSqlField(columns[i%len(columns)]))
+		}
+		return fields
+	}
+	last := ""
+	for i := 0; i < len(query) && len(fields) < count; i++ {
+		if query[i] != '?' {
+			continue
+		}
+		field := ""
+		if match := sqlPlaceholderFieldRegex.FindStringSubmatch(query[:i]); match != nil {
+			field = normalizeSqlField(match[1])
+		} else if prefix := strings.TrimSpace(query[:i]); strings.HasSuffix(prefix, ",") {
+			// IN(?,?,?)中后续的占位符
+			field = last
+		}
+		fields = append(fields, field)
+		last = field
+	}
+	return fields
+}
+
+// 标准化字段名称，去掉表名及引号并转换为小写
+func normalizeSqlField(field string) string {
+	field = strings.Trim(strings.TrimSpace(field), "`\"")
+	if pos := strings.LastIndexByte(field, '.'); pos >= 0 {
+		field = strings.Trim(field[pos+1:], "`\"")
+	}
+	return strings.ToLower(field)
+}
+
+// 将预处理参数格式化为SQL语句中的值
+func formatSqlArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		return gconv.String(v)
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05") + "'"
+	case *time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05") + "'"
+	}
+	return "'" + strings.Replace(gconv.String(arg), "'", "''", -1) + "'"
+}
+
+// 设置慢查询阈值(单位毫秒)，执行时间(查询操作包含结果集的读取时间)超过阈值的SQL语句、参数、记录数及耗时
+// 将通过日志对象以WARNING级别输出，不受debug模式影响。如果 n <= 0 表示使用节点配置，节点未配置时不记录慢查询。
+func (bs *dbBase) SetSlowThreshold(n int) {
+	bs.slowThreshold.Set(n)
+}
+
+// 获得慢查询阈值(单位毫秒)，0表示不记录慢查询
+func (bs *dbBase) getSlowThreshold() int {
+	if n := bs.slowThreshold.Val(); n > 0 {
+		return n
+	}
+	configs.RLock()
+	defer configs.RUnlock()
+	if node, err := getConfigNodeByGroup(bs.group, true); err == nil && node.SlowThreshold > 0 {
+		return node.SlowThreshold
+	}
+	return 0
+}
+
+// 设置是否在debug模式下对慢查询的SELECT语句自动执行EXPLAIN，并将执行计划输出到慢查询日志中
+func (bs *dbBase) SetSlowExplain(enabled bool) {
+	bs.slowExplain.Set(enabled)
+}
+
+// 判断是否对慢查询自动执行EXPLAIN
+func (bs *dbBase) getSlowExplain() bool {
+	if bs.slowExplain.Val() {
+		return true
+	}
+	configs.RLock()
+	defer configs.RUnlock()
+	if node, err := getConfigNodeByGroup(bs.group, true); err == nil {
+		return node.SlowExplain
+	}
+	return false
+}
+
+// 获得查询语句的执行计划SQL，返回空表示不支持
+func (bs *dbBase) getExplainSql(query string) string {
+	return "EXPLAIN " + query
+}
+
+// 返回用于记录查询操作的上下文，通过该上下文执行的查询操作在读取结果集之后由recordSlowQuery记录慢查询
+func (bs *dbBase) slowQueryCtx(ctx context.Context) (context.Context, *slowQueryOperation) {
+	if bs.getSlowThreshold() <= 0 {
+		return ctx, nil
+	}
+	holder := &slowQueryOperation{}
+	return context.WithValue(ctx, slowQueryCtxKey{}, holder), holder
+}
+
+// 检查SQL操作是否为慢查询，查询操作的上下文带有记录对象时，延迟到读取结果集之后检查
+func (bs *dbBase) checkSlowSql(op *Operation, err error) {
+	if err != nil {
+		return
+	}
+	if op.Type == OPERATION_QUERY {
+		if holder, ok := op.Ctx.Value(slowQueryCtxKey{}).(*slowQueryOperation); ok {
+			holder.op = op
+			return
+		}
+		// 结果集由调用方读取，无法获取记录数
+		bs.logSlowSql(op, -1, op.End)
+		return
+	}
+	rows := int64(-1)
+	if op.Result != nil {
+		if n, err := op.Result.RowsAffected(); err == nil {
+			rows = n
+		}
+	}
+	bs.logSlowSql(op, rows, op.End)
+}
+
+// 读取结果集之后记录慢查询
+func (bs *dbBase) recordSlowQuery(holder *slowQueryOperation, rows int) {
+	if holder != nil && holder.op != nil {
+		bs.logSlowSql(holder.op, int64(rows), gtime.Millisecond())
+	}
+}
+
+// 执行时间超过慢查询阈值时输出慢查询日志，rows为-1表示记录数未知
+func (bs *dbBase) logSlowSql(op *Operation, rows int64, end int64) {
+	threshold := bs.getSlowThreshold()
+	if threshold <= 0 || end-op.Start < int64(threshold) {
+		return
+	}
+	s := fmt.Sprintf("[SLOW] %s, rows: %d, %d ms", formatSqlLog(op.Sql, op.Args, bs.GetSqlLogOption()), rows, end-op.Start)
+	if bs.db.getDebug() && bs.getSlowExplain() && sqlExplainRegex.MatchString(op.Sql) {
+		if plan, err := bs.explainSql(op); err != nil {
+			s += "\nExplain Error: " + err.Error()
+		} else if plan != "" {
+			s += "\nExplain:\n" + plan
+		}
+	}
+	if logger := bs.GetLogger(); logger != nil {
+		logger.Warning(s)
+	} else {
+		glog.Warning(s)
+	}
+}
+
+// 在执行操作的链接对象上执行EXPLAIN，返回格式化的执行计划
+func (bs *dbBase) explainSql(op *Operation) (string, error) {
+	query := bs.db.getExplainSql(op.Sql)
+	if query == "" || op.link == nil {
+		return "", nil
+	}
+	ctx, cancel := bs.timeoutCtx(op.Ctx)
+	defer cancel()
+	rows, err := op.link.QueryContext(ctx, query, op.Args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	result, err := bs.db.rowsToResult(rows)
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, len(result))
+	for i, record := range result {
+		lines[i] = record.ToJson()
+	}
+	return strings.Join(lines, "\n"), nil
+}
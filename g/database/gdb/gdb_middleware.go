@@ -0,0 +1,118 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/gf/g/os/gtime"
+)
+
+const (
+	OPERATION_QUERY = "query" // 查询操作
+	OPERATION_EXEC  = "exec"  // 执行操作
+)
+
+// 中间件处理的SQL操作
+type Operation struct {
+	Type   string          // 操作类型：OPERATION_QUERY, OPERATION_EXEC
+	Group  string          // 数据库配置分组名称
+	Ctx    context.Context // 操作的上下文，中间件可以替换
+	Sql    string          // SQL语句(可能带有预处理占位符)，中间件可以修改
+	Args   []interface{}   // 预处理参数值列表，中间件可以修改
+	Rows   *sql.Rows       // 查询操作的结果集(执行完成后有效)
+	Result sql.Result      // 执行操作的结果(执行完成后有效)
+	Start  int64           // 执行开始时间(毫秒，执行完成后有效)
+	End    int64           // 执行结束时间(毫秒，执行完成后有效)
+	link   dbLink          // 执行操作的链接对象
+}
+
+// SQL操作的处理方法
+type Handler func(op *Operation) error
+
+// 中间件列表
+type middlewares struct {
+	mu   sync.RWMutex
+	list []func(next Handler) Handler
+}
+
+// 添加SQL操作的中间件，中间件对所有的查询/执行操作(包括事务及链式操作)生效，
+// 按照添加顺序嵌套执行，先添加的中间件在最外层。
+// 中间件可以在调用next之前修改SQL语句及参数(例如按照租户过滤数据)，在调用next之后获取执行结果及耗时，
+// 也可以不调用next直接返回错误以拦截操作，例如：
+// db.Use(func(next gdb.Handler) gdb.Handler { return func(op *gdb.Operation) error { return next(op) } })
+func (bs *dbBase) Use(middleware ...func(next Handler) Handler) {
+	bs.middlewares.mu.Lock()
+	defer bs.middlewares.mu.Unlock()
+	list := make([]func(next Handler) Handler, 0, len(bs.middlewares.list)+len(middleware))
+	list = append(list, bs.middlewares.list...)
+	bs.middlewares.list = append(list, middleware...)
+}
+
+// 使用中间件执行SQL操作，handler为实际执行操作的处理方法
+func (bs *dbBase) handleOperation(opType string, link dbLink, query string, args []interface{}, handler Handler) (*Operation, error) {
+	ctx, link := bs.linkCtx(link)
+	op := &Operation{
+		Type:  opType,
+		Group: bs.group,
+		Ctx:   ctx,
+		Sql:   query,
+		Args:  args,
+		link:  link,
+	}
+	bs.middlewares.mu.RLock()
+	list := bs.middlewares.list
+	bs.middlewares.mu.RUnlock()
+	for i := len(list) - 1; i >= 0; i-- {
+		handler = list[i](handler)
+	}
+	return op, handler(op)
+}
+
+// 实际执行查询操作的处理方法
+func (bs *dbBase) queryHandler(op *Operation) (err error) {
+	if bs.captureDryRun(op) {
+		return nil
+	}
+	op.Start = gtime.Millisecond()
+	op.Rows, err = bs.linkQuery(&ctxLink{dbLink: op.link, ctx: op.Ctx}, op.Sql, op.Args...)
+	op.End = gtime.Millisecond()
+	bs.recordSql(op, err)
+	bs.checkSlowSql(op, err)
+	return err
+}
+
+// 实际执行操作的处理方法
+func (bs *dbBase) execHandler(op *Operation) (err error) {
+	if bs.captureDryRun(op) {
+		return nil
+	}
+	op.Start = gtime.Millisecond()
+	op.Result, err = bs.linkExec(&ctxLink{dbLink: op.link, ctx: op.Ctx}, op.Sql, op.Args...)
+	op.End = gtime.Millisecond()
+	bs.recordSql(op, err)
+	bs.checkSlowSql(op, err)
+	return err
+}
+
+// 记录并打印执行的SQL(仅在debug=true时有效)
+func (bs *dbBase) recordSql(op *Operation, err error) {
+	if !bs.db.getDebug() {
+		return
+	}
+	s := &Sql{
+		Sql:   op.Sql,
+		Args:  op.Args,
+		Error: err,
+		Start: op.Start,
+		End:   op.End,
+	}
+	bs.sqls.Put(s)
+	bs.printSql(s)
+}
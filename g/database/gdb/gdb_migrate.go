@@ -0,0 +1,314 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gf/g/os/gtime"
+)
+
+const (
+	gDEFAULT_MIGRATION_TABLE     = "schema_migrations"    // 默认的迁移版本记录表名称
+	gMIGRATION_LOCK_TABLE_SUFFIX = "_lock"                // 迁移锁表名称后缀
+	gMIGRATION_LOCK_INTERVAL     = 100 * time.Millisecond // 等待迁移锁时的重试间隔
+	gDEFAULT_MIGRATION_LOCK_TTL  = 10 * time.Minute       // 默认的迁移锁过期时间
+)
+
+var (
+	// 迁移锁被其他进程持有时Migrate返回的错误
+	ErrMigrationLocked = errors.New("migration is locked by another process")
+)
+
+// 数据库迁移，升级/回滚操作可以使用SQL语句或者方法，同时设置时优先使用方法。
+// 每个迁移在独立的事务中执行，并在同一事务中记录版本，
+// 注意MySQL等数据库的DDL语句会隐式提交事务，因此包含DDL的迁移失败时可能无法完全回滚。
+type Migration struct {
+	Version  string             // 版本号，迁移按照版本号的字符串顺序执行，例如："20190601120000"
+	Name     string             // 迁移名称(描述)
+	Up       string             // 升级SQL脚本(支持多条语句，参考SplitSqlScript)
+	Down     string             // 回滚SQL脚本(支持多条语句，参考SplitSqlScript)
+	UpFunc   func(tx *TX) error // 升级方法
+	DownFunc func(tx *TX) error // 回滚方法
+}
+
+// 数据库迁移选项
+type MigrateOption struct {
+	Table    string        // 版本记录表名称，默认为schema_migrations，迁移锁表名称为该名称加上"_lock"后缀
+	Down     bool          // 是否执行回滚操作，默认为升级操作
+	Steps    int           // 执行的迁移数量，升级时默认执行所有未执行的迁移，回滚时默认回滚最近执行的一个迁移
+	DryRun   bool          // 是否仅返回将要执行的迁移，而不实际执行(不会创建版本记录表，也不会获取迁移锁)
+	LockWait time.Duration // 迁移锁被其他进程持有时的最大等待时间，默认不等待，直接返回ErrMigrationLocked
+	LockTTL  time.Duration // 迁移锁的过期时间，默认为10分钟，持有迁移锁期间会定期续期，过期的迁移锁视为进程异常退出遗留的锁
+}
+
+// 执行数据库迁移，返回执行(DryRun时为将要执行)的迁移列表。
+// 已执行的迁移版本记录在版本记录表中，升级时按照版本号顺序执行未执行的迁移，回滚时按照版本号倒序回滚已执行的迁移。
+// 迁移执行期间通过迁移锁表防止多个进程同时执行迁移，如果进程异常退出导致迁移锁未释放，迁移锁在过期后被其他进程清除，
+// 注意迁移锁的过期基于各个进程的本地时间，因此各个进程之间的时间误差应当远小于过期时间。
+func Migrate(db DB, migrations []Migration, option ...MigrateOption) ([]Migration, error) {
+	opt := MigrateOption{}
+	if len(option) > 0 {
+		opt = option[0]
+	}
+	if opt.Table == "" {
+		opt.Table = gDEFAULT_MIGRATION_TABLE
+	}
+	if opt.LockTTL <= 0 {
+		opt.LockTTL = gDEFAULT_MIGRATION_LOCK_TTL
+	}
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i, m := range sorted {
+		if m.Version == "" {
+			return nil, errors.New("migration version cannot be empty")
+		}
+		if i > 0 && m.Version == sorted[i-1].Version {
+			return nil, fmt.Errorf(`duplicated migration version "%s"`, m.Version)
+		}
+	}
+	if !opt.DryRun {
+		if err := createMigrationTables(db, opt.Table); err != nil {
+			return nil, err
+		}
+		if err := lockMigration(db, opt.Table, opt.LockWait, opt.LockTTL); err != nil {
+			return nil, err
+		}
+		stop := renewMigrationLock(db, opt.Table, opt.LockTTL)
+		defer func() {
+			stop()
+			unlockMigration(db, opt.Table)
+		}()
+	}
+	applied, err := getMigrationVersions(db, opt.Table, opt.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Down {
+		return migrateDown(db, sorted, applied, opt)
+	}
+	return migrateUp(db, sorted, applied, opt)
+}
+
+// 执行未执行的迁移
+func migrateUp(db DB, migrations []Migration, applied map[string]bool, opt MigrateOption) ([]Migration, error) {
+	pending := make([]Migration, 0)
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if m.Up == "" && m.UpFunc == nil {
+			return nil, fmt.Errorf(`migration "%s" has no up operation`, m.Version)
+		}
+		pending = append(pending, m)
+	}
+	if opt.Steps > 0 && len(pending) > opt.Steps {
+		pending = pending[:opt.Steps]
+	}
+	if opt.DryRun {
+		return pending, nil
+	}
+	charL, charR := db.getChars()
+	record := fmt.Sprintf(`INSERT INTO %s%s%s(version,name,applied_at) VALUES(?,?,?)`, charL, opt.Table, charR)
+	for i, m := range pending {
+		err := db.Transaction(func(tx *TX) error {
+			if err := runMigration(tx, m.Up, m.UpFunc); err != nil {
+				return err
+			}
+			_, err := tx.Exec(record, m.Version, m.Name, gtime.Now().Format("Y-m-d H:i:s"))
+			return err
+		})
+		if err != nil {
+			return pending[:i], fmt.Errorf(`migration "%s" up failed: %v`, m.Version, err)
+		}
+	}
+	return pending, nil
+}
+
+// 回滚已执行的迁移
+func migrateDown(db DB, migrations []Migration, applied map[string]bool, opt MigrateOption) ([]Migration, error) {
+	steps := opt.Steps
+	if steps <= 0 {
+		steps = 1
+	}
+	versions := make([]string, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+	if len(versions) > steps {
+		versions = versions[:steps]
+	}
+	index := make(map[string]Migration, len(migrations))
+	for _, m := range migrations {
+		index[m.Version] = m
+	}
+	pending := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		m, ok := index[version]
+		if !ok {
+			return nil, fmt.Errorf(`migration "%s" is applied but not found`, version)
+		}
+		if m.Down == "" && m.DownFunc == nil {
+			return nil, fmt.Errorf(`migration "%s" has no down operation`, version)
+		}
+		pending = append(pending, m)
+	}
+	if opt.DryRun {
+		return pending, nil
+	}
+	charL, charR := db.getChars()
+	record := fmt.Sprintf(`DELETE FROM %s%s%s WHERE version=?`, charL, opt.Table, charR)
+	for i, m := range pending {
+		err := db.Transaction(func(tx *TX) error {
+			if err := runMigration(tx, m.Down, m.DownFunc); err != nil {
+				return err
+			}
+			_, err := tx.Exec(record, m.Version)
+			return err
+		})
+		if err != nil {
+			return pending[:i], fmt.Errorf(`migration "%s" down failed: %v`, m.Version, err)
+		}
+	}
+	return pending, nil
+}
+
+// 在事务中执行迁移的SQL脚本或者方法
+func runMigration(tx *TX, query string, f func(tx *TX) error) error {
+	if f != nil {
+		return f(tx)
+	}
+	_, err := tx.ExecScript(query)
+	return err
+}
+
+// 创建版本记录表及迁移锁表(如果不存在)
+func createMigrationTables(db DB, table string) error {
+	charL, charR := db.getChars()
+	tables := map[string]string{
+		table: fmt.Sprintf(
+			`CREATE TABLE %s%s%s(version VARCHAR(255) NOT NULL PRIMARY KEY, name VARCHAR(255), applied_at VARCHAR(19))`,
+			charL, table, charR,
+		),
+		table + gMIGRATION_LOCK_TABLE_SUFFIX: fmt.Sprintf(
+			`CREATE TABLE %s%s%s(id INT NOT NULL PRIMARY KEY, locked_at VARCHAR(19))`,
+			charL, table+gMIGRATION_LOCK_TABLE_SUFFIX, charR,
+		),
+	}
+	for name, query := range tables {
+		if migrationTableExists(db, name) {
+			continue
+		}
+		if _, err := db.Exec(query); err != nil {
+			// 可能被其他进程同时创建
+			if migrationTableExists(db, name) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// 判断数据表是否存在，通过查询数据表判断，以兼容不同类型的数据库
+func migrationTableExists(db DB, table string) bool {
+	charL, charR := db.getChars()
+	_, err := db.GetAll(fmt.Sprintf(`SELECT * FROM %s%s%s WHERE 1=0`, charL, table, charR))
+	return err == nil
+}
+
+// 获取迁移锁，通过向迁移锁表写入相同主键的记录实现，锁被持有时在wait时间内重试，
+// 锁记录的时间超过ttl时表示持有锁的进程已经异常退出，删除该记录后重新获取
+func lockMigration(db DB, table string, wait time.Duration, ttl time.Duration) error {
+	charL, charR := db.getChars()
+	query := fmt.Sprintf(
+		`INSERT INTO %s%s%s(id,locked_at) VALUES(?,?)`, charL, table+gMIGRATION_LOCK_TABLE_SUFFIX, charR,
+	)
+	check := fmt.Sprintf(`SELECT id FROM %s%s%s WHERE id=?`, charL, table+gMIGRATION_LOCK_TABLE_SUFFIX, charR)
+	expire := fmt.Sprintf(`DELETE FROM %s%s%s WHERE id=? AND locked_at<?`, charL, table+gMIGRATION_LOCK_TABLE_SUFFIX, charR)
+	deadline := time.Now().Add(wait)
+	for {
+		_, err := db.Exec(query, 1, gtime.Now().Format("Y-m-d H:i:s"))
+		if err == nil {
+			return nil
+		}
+		// 锁记录不存在表示写入失败的原因不是锁被持有
+		if result, e := db.GetAll(check, 1); e != nil || len(result) == 0 {
+			return err
+		}
+		// 清除过期的锁后立即重试，多个进程同时清除时只有一个进程能够写入成功
+		if r, e := db.Exec(expire, 1, gtime.Now().Add(-ttl).Format("Y-m-d H:i:s")); e == nil {
+			if n, _ := r.RowsAffected(); n > 0 {
+				continue
+			}
+		}
+		if time.Now().After(deadline) {
+			return ErrMigrationLocked
+		}
+		select {
+		case <-db.GetCtx().Done():
+			return db.GetCtx().Err()
+		case <-time.After(gMIGRATION_LOCK_INTERVAL):
+		}
+	}
+}
+
+// 持有迁移锁期间定期更新锁记录的时间，避免执行时间较长的迁移的锁被其他进程视为过期，返回停止续期的方法
+func renewMigrationLock(db DB, table string, ttl time.Duration) (stop func()) {
+	charL, charR := db.getChars()
+	query := fmt.Sprintf(`UPDATE %s%s%s SET locked_at=? WHERE id=?`, charL, table+gMIGRATION_LOCK_TABLE_SUFFIX, charR)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				db.Exec(query, gtime.Now().Format("Y-m-d H:i:s"), 1)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// 释放迁移锁
+func unlockMigration(db DB, table string) error {
+	charL, charR := db.getChars()
+	_, err := db.Exec(fmt.Sprintf(`DELETE FROM %s%s%s WHERE id=?`, charL, table+gMIGRATION_LOCK_TABLE_SUFFIX, charR), 1)
+	return err
+}
+
+// 获取已执行的迁移版本，allowMissing为true时版本记录表不存在表示没有执行过迁移
+func getMigrationVersions(db DB, table string, allowMissing bool) (map[string]bool, error) {
+	charL, charR := db.getChars()
+	result, err := db.GetAll(fmt.Sprintf(`SELECT version FROM %s%s%s`, charL, table, charR))
+	if err != nil {
+		if allowMissing && !migrationTableExists(db, table) {
+			return map[string]bool{}, nil
+		}
+		return nil, err
+	}
+	versions := make(map[string]bool, len(result))
+	for _, record := range result {
+		versions[record["version"].String()] = true
+	}
+	return versions, nil
+}
@@ -13,30 +13,45 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/gf/g/container/gvar"
 	"github.com/gf/g/util/gconv"
 )
 
 // 数据库链式操作模型对象
 type Model struct {
-	db           DB            // 数据库操作对象
-	tx           *TX           // 数据库事务对象
-	tablesInit   string        // 初始化Model时的表名称(可以是多个)
-	tables       string        // 数据库操作表
-	fields       string        // 操作字段
-	where        string        // 操作条件
-	whereArgs    []interface{} // 操作条件参数
-	groupBy      string        // 分组语句
-	orderBy      string        // 排序语句
-	start        int           // 分页开始
-	limit        int           // 分页条数
-	data         interface{}   // 操作记录(支持Map/List/string类型)
-	batch        int           // 批量操作条数
-	filter       bool          // 是否按照表字段过滤data参数
-	cacheEnabled bool          // 当前SQL操作是否开启查询缓存功能
-	cacheTime    int           // 查询缓存时间
-	cacheName    string        // 查询缓存名称
-	safe         bool          // 当前模型是否运行安全模式（可修改当前模型，否则每一次链式操作都是返回新的模型对象）
+	db           DB               // 数据库操作对象
+	tx           *TX              // 数据库事务对象
+	tablesInit   string           // 初始化Model时的表名称(可以是多个)
+	tables       string           // 数据库操作表
+	fields       string           // 操作字段
+	fieldsEx     string           // 排除字段
+	where        string           // 操作条件
+	whereArgs    []interface{}    // 操作条件参数
+	groupBy      string           // 分组语句
+	having       string           // 分组过滤条件
+	havingArgs   []interface{}    // 分组过滤条件参数
+	orderBy      string           // 排序语句
+	start        int              // 分页开始
+	limit        int              // 分页条数
+	data         interface{}      // 操作记录(支持Map/List/string类型)
+	batch        int              // 批量操作条数
+	filter       bool             // 是否按照表字段过滤data参数
+	cacheEnabled bool             // 当前SQL操作是否开启查询缓存功能
+	cacheTime    int              // 查询缓存时间
+	cacheName    string           // 查询缓存名称
+	safe         bool             // 当前模型是否运行安全模式（可修改当前模型，否则每一次链式操作都是返回新的模型对象）
+	unscoped     bool             // 是否不使用软删除特性
+	withEnabled  bool             // 查询结果映射到struct时是否关联查询关联属性
+	withAttrs    []string         // 需要关联查询的属性名称，为空表示所有关联属性
+	dryRun       *dryRunCapture   // 空跑模式下捕获的SQL语句，为nil表示未开启空跑模式
+	shardTable   ShardFunc        // 分表规则
+	shardDb      ShardFunc        // 分库规则
+	shardKeys    []interface{}    // 分片键值
+	strict       bool             // 是否严格模式，写入/更新不允许写入的字段时返回错误
+	writeRule    *writeFieldsRule // 结构体标签定义的写入字段规则
+	returning    []string         // Insert写入数据时返回的字段
 }
 
 // 链式操作，数据表字段，可支持多个表，以半角逗号连接
@@ -129,6 +144,34 @@ func (md *Model) InnerJoin(joinTable string, on string) *Model {
 	return model
 }
 
+// 链式操作，左联表，关联条件由两个字段及比较符组成，例如：
+// LeftJoinOnFields("user_detail ud", "id", "=", "uid")，
+// 未指定表名的字段将自动加上表名(或者别名)前缀，first为当前表字段，second为联表字段。
+func (md *Model) LeftJoinOnFields(joinTable string, first string, operator string, second string) *Model {
+	return md.LeftJoin(joinTable, md.joinOnFields(joinTable, first, operator, second))
+}
+
+// 链式操作，右联表，关联条件由两个字段及比较符组成，参考LeftJoinOnFields。
+func (md *Model) RightJoinOnFields(joinTable string, first string, operator string, second string) *Model {
+	return md.RightJoin(joinTable, md.joinOnFields(joinTable, first, operator, second))
+}
+
+// 链式操作，内联表，关联条件由两个字段及比较符组成，参考LeftJoinOnFields。
+func (md *Model) InnerJoinOnFields(joinTable string, first string, operator string, second string) *Model {
+	return md.InnerJoin(joinTable, md.joinOnFields(joinTable, first, operator, second))
+}
+
+// 生成联表的关联条件，未指定表名的字段自动加上表名(或者别名)前缀。
+func (md *Model) joinOnFields(joinTable string, first string, operator string, second string) string {
+	if !strings.Contains(first, ".") {
+		first = tableAlias(strings.Split(md.tablesInit, ",")[0]) + "." + first
+	}
+	if !strings.Contains(second, ".") {
+		second = tableAlias(joinTable) + "." + second
+	}
+	return fmt.Sprintf("%s %s %s", first, operator, second)
+}
+
 // 链式操作，查询字段
 func (md *Model) Fields(fields string) *Model {
 	model := md.getModel()
@@ -143,7 +186,7 @@ func (md *Model) Filter() *Model {
 	return model
 }
 
-// 链式操作，condition，支持string & gdb.Map.
+// 链式操作，condition，支持string & gdb.Map & *gdb.WhereBuilder.
 // 注意，多个Where调用时，会自动转换为And条件调用。
 func (md *Model) Where(where interface{}, args ...interface{}) *Model {
 	model := md.getModel()
@@ -160,11 +203,16 @@ func (md *Model) Where(where interface{}, args ...interface{}) *Model {
 func (md *Model) And(where interface{}, args ...interface{}) *Model {
 	model := md.getModel()
 	newWhere, newArgs := formatCondition(where, args)
-	if len(model.where) > 0 && model.where[0] == '(' {
-		model.where = fmt.Sprintf(`%s AND (%s)`, model.where, newWhere)
-	} else {
-		model.where = fmt.Sprintf(`(%s) AND (%s)`, model.where, newWhere)
+	if newWhere == "" {
+		return model
+	}
+	if model.where == "" {
+		model.where = newWhere
+		model.whereArgs = newArgs
+		return model
 	}
+	// 已有条件可能是多个条件的组合(例如"(a) OR (b)")，需要整体括起来以保证按照链式调用的顺序组合条件
+	model.where = fmt.Sprintf(`(%s) AND (%s)`, model.where, newWhere)
 	model.whereArgs = append(model.whereArgs, newArgs...)
 	return model
 }
@@ -173,11 +221,16 @@ func (md *Model) And(where interface{}, args ...interface{}) *Model {
 func (md *Model) Or(where interface{}, args ...interface{}) *Model {
 	model := md.getModel()
 	newWhere, newArgs := formatCondition(where, args)
-	if len(model.where) > 0 && model.where[0] == '(' {
-		model.where = fmt.Sprintf(`%s OR (%s)`, model.where, newWhere)
-	} else {
-		model.where = fmt.Sprintf(`(%s) OR (%s)`, model.where, newWhere)
+	if newWhere == "" {
+		return model
+	}
+	if model.where == "" {
+		model.where = newWhere
+		model.whereArgs = newArgs
+		return model
 	}
+	// 已有条件可能是多个条件的组合(例如"(a) OR (b)")，需要整体括起来以保证按照链式调用的顺序组合条件
+	model.where = fmt.Sprintf(`(%s) OR (%s)`, model.where, newWhere)
 	model.whereArgs = append(model.whereArgs, newArgs...)
 	return model
 }
@@ -189,6 +242,15 @@ func (md *Model) GroupBy(groupBy string) *Model {
 	return model
 }
 
+// 链式操作，having，分组过滤条件，需要与GroupBy一起使用，例如：
+// GroupBy("uid").Having("COUNT(1) > ?", 1)
+func (md *Model) Having(having string, args ...interface{}) *Model {
+	model := md.getModel()
+	model.having = having
+	model.havingArgs = args
+	return model
+}
+
 // 链式操作，order by
 func (md *Model) OrderBy(orderBy string) *Model {
 	model := md.getModel()
@@ -223,6 +285,8 @@ func (md *Model) Batch(batch int) *Model {
 // 当time < 0时表示清除缓存， time=0时表示不过期, time > 0时表示过期时间，time过期时间单位：秒；
 // name表示自定义的缓存名称，便于业务层精准定位缓存项(如果业务层需要手动清理时，必须指定缓存名称)，
 // 例如：查询缓存时设置名称，清理缓存时可以给定清理的缓存名称进行精准清理。
+// 缓存默认存储在内存中，可以通过节点配置CacheRedis或者SetQueryCache使用gredis存储；
+// 通过链式操作写入/更新/删除数据表时，与该数据表关联的查询缓存将会自动清除。
 func (md *Model) Cache(time int, name ...string) *Model {
 	model := md.getModel()
 	model.cacheTime = time
@@ -240,6 +304,7 @@ func (md *Model) Cache(time int, name ...string) *Model {
 // 也可以是：key,value,key,value,...。
 func (md *Model) Data(data ...interface{}) *Model {
 	model := md.getModel()
+	model.writeRule = nil
 	if len(data) > 1 {
 		m := make(map[string]interface{})
 		for i := 0; i < len(data); i += 2 {
@@ -273,10 +338,12 @@ func (md *Model) Data(data ...interface{}) *Model {
 					list[i] = structToMap(rv.Index(i).Interface())
 				}
 				model.data = list
+				model.writeRule = getWriteFieldsRule(data[0])
 			case reflect.Map:
-				fallthrough
+				model.data = Map(structToMap(data[0]))
 			case reflect.Struct:
 				model.data = Map(structToMap(data[0]))
+				model.writeRule = getWriteFieldsRule(data[0])
 			default:
 				model.data = data[0]
 			}
@@ -289,9 +356,18 @@ func (md *Model) Data(data ...interface{}) *Model {
 // 根据Data方法传递的参数类型决定该操作是单条操作还是批量操作，
 // 如果Data方法传递的是slice类型，那么为批量操作。
 func (md *Model) Insert() (result sql.Result, err error) {
+	if md.isSharding() {
+		model, err := md.getShardModel()
+		if err != nil {
+			return nil, err
+		}
+		return model.Insert()
+	}
 	defer func() {
 		if err == nil {
 			md.checkAndRemoveCache()
+		} else {
+			md.checkTableFields(err)
 		}
 	}()
 	if md.data == nil {
@@ -308,6 +384,16 @@ func (md *Model) Insert() (result sql.Result, err error) {
 				list[k] = md.db.filterFields(md.tables, m)
 			}
 		}
+		if list, err = md.checkWriteList(list, gWRITE_INSERT); err != nil {
+			return nil, err
+		}
+		list = md.fillInsertTimeList(list)
+		if list, err = md.fillInsertIdList(list); err != nil {
+			return nil, err
+		}
+		if len(md.returning) > 0 {
+			return md.insertReturning(list, batch)
+		}
 		if md.tx == nil {
 			return md.db.BatchInsert(md.tables, list, batch)
 		} else {
@@ -317,11 +403,26 @@ func (md *Model) Insert() (result sql.Result, err error) {
 		if md.filter {
 			data = md.db.filterFields(md.tables, data)
 		}
+		if data, err = md.checkWriteData(data, gWRITE_INSERT); err != nil {
+			return nil, err
+		}
+		data = md.fillInsertTime(data)
+		id := interface{}(nil)
+		if data, id, err = md.fillInsertId(data); err != nil {
+			return nil, err
+		}
+		if len(md.returning) > 0 {
+			return md.insertReturning(List{data}, 1)
+		}
 		if md.tx == nil {
-			return md.db.Insert(md.tables, data)
+			result, err = md.db.Insert(md.tables, data)
 		} else {
-			return md.tx.Insert(md.tables, data)
+			result, err = md.tx.Insert(md.tables, data)
 		}
+		if err == nil && id != nil {
+			result = &idSqlResult{Result: result, id: id}
+		}
+		return result, err
 	}
 	return nil, errors.New("inserting into table with invalid data type")
 }
@@ -330,9 +431,18 @@ func (md *Model) Insert() (result sql.Result, err error) {
 // 根据Data方法传递的参数类型决定该操作是单条操作还是批量操作，
 // 如果Data方法传递的是slice类型，那么为批量操作。
 func (md *Model) Replace() (result sql.Result, err error) {
+	if md.isSharding() {
+		model, err := md.getShardModel()
+		if err != nil {
+			return nil, err
+		}
+		return model.Replace()
+	}
 	defer func() {
 		if err == nil {
 			md.checkAndRemoveCache()
+		} else {
+			md.checkTableFields(err)
 		}
 	}()
 	if md.data == nil {
@@ -349,6 +459,13 @@ func (md *Model) Replace() (result sql.Result, err error) {
 				list[k] = md.db.filterFields(md.tables, m)
 			}
 		}
+		if list, err = md.checkWriteList(list, gWRITE_INSERT); err != nil {
+			return nil, err
+		}
+		list = md.fillInsertTimeList(list)
+		if list, err = md.fillInsertIdList(list); err != nil {
+			return nil, err
+		}
 		if md.tx == nil {
 			return md.db.BatchReplace(md.tables, list, batch)
 		} else {
@@ -358,6 +475,13 @@ func (md *Model) Replace() (result sql.Result, err error) {
 		if md.filter {
 			data = md.db.filterFields(md.tables, data)
 		}
+		if data, err = md.checkWriteData(data, gWRITE_INSERT); err != nil {
+			return nil, err
+		}
+		data = md.fillInsertTime(data)
+		if data, _, err = md.fillInsertId(data); err != nil {
+			return nil, err
+		}
 		if md.tx == nil {
 			return md.db.Replace(md.tables, data)
 		} else {
@@ -367,21 +491,34 @@ func (md *Model) Replace() (result sql.Result, err error) {
 	return nil, errors.New("replacing into table with invalid data type")
 }
 
-// 链式操作， CURD - Save/BatchSave。
+// 链式操作， CURD - Save/BatchSave，写入数据，如果数据已存在(冲突)那么更新数据(upsert)。
 // 根据Data方法传递的参数类型决定该操作是单条操作还是批量操作，
-// 如果Data方法传递的是slice类型，那么为批量操作。
-func (md *Model) Save() (result sql.Result, err error) {
+// 如果Data方法传递的是slice类型，那么为批量操作，每批写入的条数通过Batch方法设置。
+// 参数conflict为判断数据是否存在的冲突字段(主键或者唯一索引字段)，冲突字段不会被更新，
+// MySQL使用ON DUPLICATE KEY UPDATE语法，不需要指定冲突字段；PostgreSQL/SQLite使用ON CONFLICT DO UPDATE语法，必须指定冲突字段。
+func (md *Model) Save(conflict ...string) (result sql.Result, err error) {
+	if md.isSharding() {
+		model, err := md.getShardModel()
+		if err != nil {
+			return nil, err
+		}
+		return model.Save(conflict...)
+	}
 	defer func() {
 		if err == nil {
 			md.checkAndRemoveCache()
+		} else {
+			md.checkTableFields(err)
 		}
 	}()
 	if md.data == nil {
 		return nil, errors.New("replacing into table with empty data")
 	}
+	var data interface{}
+	var insertOnly []string
+	batch := gDEFAULT_BATCH_NUM
 	// 批量操作
 	if list, ok := md.data.(List); ok {
-		batch := gDEFAULT_BATCH_NUM
 		if md.batch > 0 {
 			batch = md.batch
 		}
@@ -390,29 +527,53 @@ func (md *Model) Save() (result sql.Result, err error) {
 				list[k] = md.db.filterFields(md.tables, m)
 			}
 		}
-		if md.tx == nil {
-			return md.db.BatchSave(md.tables, list, batch)
-		} else {
-			return md.tx.BatchSave(md.tables, list, batch)
+		if list, err = md.checkWriteList(list, gWRITE_SAVE); err != nil {
+			return nil, err
 		}
-	} else if data, ok := md.data.(Map); ok {
+		if len(list) > 0 {
+			insertOnly = md.getInsertOnlyFields(list[0])
+		}
+		if list, err = md.fillInsertIdList(md.fillInsertTimeList(list)); err != nil {
+			return nil, err
+		}
+		data = list
+	} else if m, ok := md.data.(Map); ok {
 		if md.filter {
-			data = md.db.filterFields(md.tables, data)
+			m = md.db.filterFields(md.tables, m)
 		}
-		if md.tx == nil {
-			return md.db.Save(md.tables, data)
-		} else {
-			return md.tx.Save(md.tables, data)
+		if m, err = md.checkWriteData(m, gWRITE_SAVE); err != nil {
+			return nil, err
 		}
+		insertOnly = md.getInsertOnlyFields(m)
+		if m, _, err = md.fillInsertId(md.fillInsertTime(m)); err != nil {
+			return nil, err
+		}
+		data = m
+	} else {
+		return nil, errors.New("saving into table with invalid data type")
+	}
+	if md.tx == nil {
+		return md.db.doSave(nil, md.tables, data, conflict, insertOnly, batch)
+	} else {
+		return md.tx.db.doSave(md.tx.link(), md.tables, data, conflict, insertOnly, batch)
 	}
-	return nil, errors.New("saving into table with invalid data type")
 }
 
-// 链式操作， CURD - Update
+// 链式操作， CURD - Update，
+// 当数据表存在乐观锁版本字段并且更新数据包含该字段时，版本冲突将返回*OptimisticLockError错误，参考SetVersionField。
 func (md *Model) Update() (result sql.Result, err error) {
+	if md.isSharding() {
+		model, err := md.getShardModel()
+		if err != nil {
+			return nil, err
+		}
+		return model.Update()
+	}
 	defer func() {
 		if err == nil {
 			md.checkAndRemoveCache()
+		} else {
+			md.checkTableFields(err)
 		}
 	}()
 	if md.data == nil {
@@ -425,20 +586,51 @@ func (md *Model) Update() (result sql.Result, err error) {
 			}
 		}
 	}
+	if m, ok := md.data.(Map); ok {
+		if md.data, err = md.checkWriteData(m, gWRITE_UPDATE); err != nil {
+			return nil, err
+		}
+	}
+	data := md.fillUpdateTime(md.data)
+	where := md.getWhereWithSoftDelete()
+	data, where, args, version := md.fillUpdateVersion(data, where, md.whereArgs)
 	if md.tx == nil {
-		return md.db.doUpdate(nil, md.tables, md.data, md.where, md.whereArgs...)
+		result, err = md.db.doUpdate(nil, md.tables, data, where, args...)
 	} else {
-		return md.tx.doUpdate(md.tables, md.data, md.where, md.whereArgs...)
+		result, err = md.tx.doUpdate(md.tables, data, where, args...)
 	}
+	// 使用乐观锁时没有记录被更新表示版本冲突
+	if err == nil && version != nil && md.dryRun == nil {
+		if n, e := result.RowsAffected(); e == nil && n == 0 {
+			err = &OptimisticLockError{Table: md.getWriteTable(), Version: version}
+		}
+	}
+	return
 }
 
-// 链式操作， CURD - Delete
+// 链式操作， CURD - Delete，
+// 当数据表存在软删除时间字段时，只写入删除时间而不删除记录，可以通过Unscoped直接删除记录。
 func (md *Model) Delete() (result sql.Result, err error) {
+	if md.isSharding() {
+		model, err := md.getShardModel()
+		if err != nil {
+			return nil, err
+		}
+		return model.Delete()
+	}
 	defer func() {
 		if err == nil {
 			md.checkAndRemoveCache()
 		}
 	}()
+	if data := md.getSoftDeleteData(); data != nil {
+		where := md.getWhereWithSoftDelete()
+		if md.tx == nil {
+			return md.db.doUpdate(nil, md.tables, data, where, md.whereArgs...)
+		} else {
+			return md.tx.doUpdate(md.tables, data, where, md.whereArgs...)
+		}
+	}
 	if md.tx == nil {
 		return md.db.doDelete(nil, md.tables, md.where, md.whereArgs...)
 	} else {
@@ -453,7 +645,22 @@ func (md *Model) Select() (Result, error) {
 
 // 链式操作，查询所有记录
 func (md *Model) All() (Result, error) {
-	return md.getAll(md.getFormattedSql(), md.whereArgs...)
+	if md.isSharding() {
+		models, err := md.getShardModels()
+		if err != nil {
+			return nil, err
+		}
+		return md.shardAll(models)
+	}
+	query := md.getFormattedSql()
+	result, err := md.getAll(query, md.getQueryArgs()...)
+	// 字段结构缓存刷新后查询语句发生变化时重新查询一次，查询语句不变时重试也是同样的错误
+	if md.checkTableFields(err) {
+		if s := md.getFormattedSql(); s != query {
+			result, err = md.getAll(s, md.getQueryArgs()...)
+		}
+	}
+	return result, err
 }
 
 // 链式操作，查询单条记录
@@ -486,7 +693,13 @@ func (md *Model) Struct(objPointer interface{}) error {
 	if err != nil {
 		return err
 	}
-	return one.ToStruct(objPointer)
+	if one, err = md.decryptRecord(one, objPointer); err != nil {
+		return err
+	}
+	if err := one.ToStruct(objPointer); err != nil {
+		return err
+	}
+	return md.doWith(objPointer)
 }
 
 // 链式操作，查询多条记录，并自动转换为指定的slice对象, 如: []struct/[]*struct。
@@ -495,7 +708,13 @@ func (md *Model) Structs(objPointerSlice interface{}) error {
 	if err != nil {
 		return err
 	}
-	return r.ToStructs(objPointerSlice)
+	if r, err = md.decryptResult(r, objPointerSlice); err != nil {
+		return err
+	}
+	if err := r.ToStructs(objPointerSlice); err != nil {
+		return err
+	}
+	return md.doWith(objPointerSlice)
 }
 
 // 链式操作，将结果转换为指定的struct/*struct/[]struct/[]*struct,
@@ -523,6 +742,13 @@ func (md *Model) Scan(objPointer interface{}) error {
 // 链式操作，查询数量，fields可以为空，也可以自定义查询字段，
 // 当给定自定义查询字段时，该字段必须为数量结果，否则会引起歧义，使用如：md.Fields("COUNT(id)")
 func (md *Model) Count() (int, error) {
+	if md.isSharding() {
+		models, err := md.getShardModels()
+		if err != nil {
+			return 0, err
+		}
+		return md.shardCount(models)
+	}
 	defer func(fields string) {
 		md.fields = fields
 	}(md.fields)
@@ -535,7 +761,7 @@ func (md *Model) Count() (int, error) {
 	if len(md.groupBy) > 0 {
 		s = fmt.Sprintf("SELECT COUNT(1) FROM (%s) count_alias", s)
 	}
-	list, err := md.getAll(s, md.whereArgs...)
+	list, err := md.getAll(s, md.getQueryArgs()...)
 	if err != nil {
 		return 0, err
 	}
@@ -547,41 +773,119 @@ func (md *Model) Count() (int, error) {
 	return 0, nil
 }
 
+// 链式操作，查询指定字段非NULL值的数量，例如：CountColumn("DISTINCT uid")。
+func (md *Model) CountColumn(column string) (int, error) {
+	model := md.Clone()
+	model.fields = column
+	return model.Count()
+}
+
+// 链式操作，查询指定字段的总和，没有记录时返回0。
+func (md *Model) SumFloat(column string) (float64, error) {
+	value, err := md.doAggregate("SUM", column)
+	if err != nil {
+		return 0, err
+	}
+	return value.Float64(), nil
+}
+
+// 链式操作，查询指定字段的平均值，返回十进制数值的字符串，
+// 以避免DECIMAL类型字段转换为浮点数的精度损失，没有记录时返回空字符串。
+func (md *Model) AvgDecimal(column string) (string, error) {
+	value, err := md.doAggregate("AVG", column)
+	if err != nil {
+		return "", err
+	}
+	return value.String(), nil
+}
+
+// 执行聚合查询，返回第一条记录的聚合结果，当存在GroupBy时返回第一个分组的聚合结果。
+func (md *Model) doAggregate(function string, column string) (Value, error) {
+	if md.isSharding() {
+		models, err := md.getShardModels()
+		if err != nil {
+			return nil, err
+		}
+		return md.shardAggregate(models, function, column)
+	}
+	model := md.Clone()
+	model.fields = fmt.Sprintf("%s(%s)", function, column)
+	value, err := model.Value()
+	if value == nil {
+		value = gvar.New(nil, true)
+	}
+	return value, err
+}
+
+// 链式操作，查询多条记录，并按照属性名称将记录映射到结构体slice元素的指定属性中，
+// 常用于将联表查询的结果映射到嵌套的结构体中，参考Result.ScanList。
+func (md *Model) ScanList(listPointer interface{}, attrName string, relation ...string) error {
+	r, err := md.All()
+	if err != nil {
+		return err
+	}
+	return r.ScanList(listPointer, attrName, relation...)
+}
+
 // 查询操作，对底层SQL操作的封装
 func (md *Model) getAll(query string, args ...interface{}) (result Result, err error) {
-	cacheKey := ""
-	// 查询缓存查询处理
-	if md.cacheEnabled {
-		cacheKey = md.cacheName
-		if len(cacheKey) == 0 {
-			cacheKey = query + "/" + gconv.String(args)
+	if !md.cacheEnabled || md.dryRun != nil {
+		return md.doGetAll(query, args...)
+	}
+	// 查询缓存查询处理，缓存操作失败时直接查询数据库
+	cacheKey := md.cacheName
+	if len(cacheKey) == 0 {
+		cacheKey = query + "/" + gconv.String(args)
+	}
+	cache := md.db.GetQueryCache()
+	if md.cacheTime < 0 {
+		cache.Remove(cacheKey)
+		return md.doGetAll(query, args...)
+	}
+	if result, ok, err := cache.Get(cacheKey); ok && err == nil {
+		return result, nil
+	}
+	// 同一个缓存项的并发查询只执行一次，防止缓存失效时大量查询同时访问数据库
+	return md.db.getCacheFlight().do(cacheKey, func() (Result, error) {
+		if result, ok, err := cache.Get(cacheKey); ok && err == nil {
+			return result, nil
 		}
-		if v := md.db.getCache().Get(cacheKey); v != nil {
-			return v.(Result), nil
+		result, err := md.doGetAll(query, args...)
+		if err == nil {
+			cache.Set(cacheKey, result, md.cacheTime, parseTableNames(md.tables))
 		}
-	}
+		return result, err
+	})
+}
 
+// 执行查询操作
+func (md *Model) doGetAll(query string, args ...interface{}) (result Result, err error) {
 	if md.tx == nil {
-		result, err = md.db.GetAll(query, args...)
-	} else {
-		result, err = md.tx.GetAll(query, args...)
-	}
-	// 查询缓存保存处理
-	if len(cacheKey) > 0 && err == nil {
-		if md.cacheTime < 0 {
-			md.db.getCache().Remove(cacheKey)
-		} else {
-			md.db.getCache().Set(cacheKey, result, md.cacheTime*1000)
-		}
+		return md.db.GetAll(query, args...)
 	}
-	return result, err
+	return md.tx.GetAll(query, args...)
 }
 
-// 检查是否需要查询查询缓存
+// 写操作完成后清除查询缓存，包括指定名称的缓存项，以及与操作数据表关联的缓存项
 func (md *Model) checkAndRemoveCache() {
+	if md.dryRun != nil {
+		return
+	}
+	cache := md.db.GetQueryCache()
 	if md.cacheEnabled && md.cacheTime < 0 && len(md.cacheName) > 0 {
-		md.db.getCache().Remove(md.cacheName)
+		cache.Remove(md.cacheName)
 	}
+	cache.RemoveTables(parseTableNames(md.tables)...)
+}
+
+// 字段不存在时表示数据表结构可能已经变更，清除当前数据表的字段结构缓存以便下一次操作时自动刷新，
+// 返回是否清除了字段结构缓存。写入操作不会自动重新执行，以免非幂等的操作被执行两次。
+func (md *Model) checkTableFields(err error) bool {
+	if err == nil || !isUnknownColumnError(err) {
+		return false
+	}
+	md.db.ClearTableFields(md.tables)
+	return true
 }
 
 // 格式化当前输入参数，返回可执行的SQL语句（不带参数）
@@ -589,13 +893,16 @@ func (md *Model) getFormattedSql() string {
 	if md.fields == "" {
 		md.fields = "*"
 	}
-	s := fmt.Sprintf("SELECT %s FROM %s", md.fields, md.tables)
-	if md.where != "" {
-		s += " WHERE " + md.where
+	s := fmt.Sprintf("SELECT %s FROM %s", md.getSelectFields(), md.tables)
+	if where := md.getWhereWithSoftDelete(); where != "" {
+		s += " WHERE " + where
 	}
 	if md.groupBy != "" {
 		s += " GROUP BY " + md.groupBy
 	}
+	if md.having != "" {
+		s += " HAVING " + md.having
+	}
 	if md.orderBy != "" {
 		s += " ORDER BY " + md.orderBy
 	}
@@ -605,6 +912,57 @@ func (md *Model) getFormattedSql() string {
 	return s
 }
 
+// 返回查询语句的参数，按照语句中的顺序为WHERE参数及HAVING参数。
+func (md *Model) getQueryArgs() []interface{} {
+	if len(md.havingArgs) == 0 {
+		return md.whereArgs
+	}
+	args := make([]interface{}, 0, len(md.whereArgs)+len(md.havingArgs))
+	args = append(args, md.whereArgs...)
+	return append(args, md.havingArgs...)
+}
+
+// 返回表名称的别名，没有别名时返回表名称，例如："user u"及"user AS u"返回"u"。
+func tableAlias(table string) string {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// 链式操作，流式查询记录，从数据库驱动逐条读取记录并调用f，f返回false时停止读取，
+// 不会将全部查询结果读取到内存中，适用于导出大量数据等场景，回调函数中不能在同一个事务中执行其他SQL操作。
+// 注意查询不使用查询缓存，也不受默认超时时间限制(读取时间可能很长)，可以通过Ctx设置上下文控制超时，
+// 跨分片查询时按照分片顺序依次读取各个分片的记录。
+func (md *Model) IterateRows(f func(record Record) bool) error {
+	if md.isSharding() {
+		models, err := md.getShardModels()
+		if err != nil {
+			return err
+		}
+		stopped := false
+		for _, model := range models {
+			err := model.IterateRows(func(record Record) bool {
+				stopped = !f(record)
+				return !stopped
+			})
+			if err != nil || stopped {
+				return err
+			}
+		}
+		return nil
+	}
+	if md.tx != nil {
+		return md.tx.db.iterate(md.tx.link(), md.getFormattedSql(), md.getQueryArgs(), f)
+	}
+	link, err := md.db.Slave()
+	if err != nil {
+		return err
+	}
+	return md.db.iterate(link, md.getFormattedSql(), md.getQueryArgs(), f)
+}
+
 // 组块结果集。
 func (md *Model) Chunk(limit int, callback func(result Result, err error) bool) {
 	page := 1
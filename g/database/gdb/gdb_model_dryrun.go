@@ -0,0 +1,125 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/os/gtime"
+)
+
+// 空跑模式的上下文键名类型
+type dryRunCtxKey struct{}
+
+// 空跑模式捕获的SQL语句
+type dryRunCapture struct {
+	mu     sync.Mutex
+	sqls   []*Sql
+	paused *gtype.Int // 大于0时暂停捕获，用于查询数据表字段结构等元数据操作
+}
+
+// 空跑模式下执行操作的结果，影响行数及自增ID均为0
+type dryRunResult struct{}
+
+func (dryRunResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (dryRunResult) RowsAffected() (int64, error) {
+	return 0, nil
+}
+
+// 链式操作，开启空跑模式：之后的写入/更新/删除/查询操作只生成SQL语句而不执行，
+// 可以通过ToSQL获取最后一次操作生成的SQL语句及参数，或者通过GetDryRunSqls获取所有生成的SQL语句(例如批量写入时分为多个批次)，
+// 常用于单元测试中校验链式操作生成的SQL，以及在执行之前审核SQL。
+// 注意：
+// 1. 空跑模式下查询操作返回空结果，写入/更新/删除操作返回的影响行数为0；
+// 2. 中间件对生成的SQL同样生效；
+// 3. 查询数据表字段结构(例如Filter及时间字段特性)仍然需要访问数据库，无法访问时忽略该特性；
+// 4. 不使用也不清除查询缓存。
+func (md *Model) DryRun() *Model {
+	model := md.getModel()
+	model.dryRun = &dryRunCapture{
+		sqls:   make([]*Sql, 0),
+		paused: gtype.NewInt(),
+	}
+	model = model.Ctx(context.WithValue(model.db.GetCtx(), dryRunCtxKey{}, model.dryRun))
+	return model
+}
+
+// 链式操作，返回生成的SQL语句及参数，不会执行SQL。
+// 空跑模式下执行过操作时返回最后一次操作生成的SQL语句，否则返回查询操作(All)的SQL语句，
+// 设置了分片规则时返回路由到的分片的查询语句(跨分片时返回分片之前的查询语句)。
+func (md *Model) ToSQL() (string, []interface{}) {
+	if sqls := md.GetDryRunSqls(); len(sqls) > 0 {
+		return sqls[len(sqls)-1].Sql, sqls[len(sqls)-1].Args
+	}
+	if md.isSharding() {
+		if model, err := md.getShardModel(); err == nil {
+			return model.ToSQL()
+		}
+	}
+	return md.db.handleSqlBeforeExec(md.getFormattedSql()), md.getQueryArgs()
+}
+
+// 返回空跑模式下生成的所有SQL语句，按照操作顺序排列，未开启空跑模式时返回空
+func (md *Model) GetDryRunSqls() []*Sql {
+	if md.dryRun == nil {
+		return nil
+	}
+	md.dryRun.mu.Lock()
+	defer md.dryRun.mu.Unlock()
+	sqls := make([]*Sql, len(md.dryRun.sqls))
+	copy(sqls, md.dryRun.sqls)
+	return sqls
+}
+
+// 获取上下文中的空跑模式SQL捕获对象，没有开启空跑模式时返回nil
+func getDryRunCapture(ctx context.Context) *dryRunCapture {
+	if ctx == nil {
+		return nil
+	}
+	if c, ok := ctx.Value(dryRunCtxKey{}).(*dryRunCapture); ok {
+		return c
+	}
+	return nil
+}
+
+// 空跑模式下捕获SQL操作而不执行，返回true表示已捕获，调用方不应该再执行该操作
+func (bs *dbBase) captureDryRun(op *Operation) bool {
+	c := getDryRunCapture(op.Ctx)
+	if c == nil || c.paused.Val() > 0 {
+		return false
+	}
+	// 参数列表可能被调用方复用(例如批量写入的各个批次)，因此需要复制
+	args := make([]interface{}, len(op.Args))
+	copy(args, op.Args)
+	now := gtime.Millisecond()
+	c.mu.Lock()
+	c.sqls = append(c.sqls, &Sql{
+		Sql:   op.Sql,
+		Args:  args,
+		Start: now,
+		End:   now,
+	})
+	c.mu.Unlock()
+	if op.Type == OPERATION_EXEC {
+		op.Result = dryRunResult{}
+	}
+	return true
+}
+
+// 暂停空跑模式的SQL捕获并执行f，用于需要实际访问数据库的元数据查询
+func (bs *dbBase) withoutDryRun(f func()) {
+	if c := getDryRunCapture(bs.ctx); c != nil {
+		c.paused.Add(1)
+		defer c.paused.Add(-1)
+	}
+	f()
+}
@@ -0,0 +1,229 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	gWRITE_INSERT = iota // 写入操作(Insert/Replace)
+	gWRITE_UPDATE        // 更新操作(Update)
+	gWRITE_SAVE          // 写入或更新操作(Save)
+)
+
+// 结构体标签定义的写入字段规则，通过Data方法传递struct/*struct/[]struct参数时解析，
+// 标签示例：orm:"insert"表示写入时允许该字段，orm:"update"表示更新时允许该字段，orm:"-"表示不允许写入/更新该字段，
+// orm:"encrypt"表示写入/更新时加密该字段。
+type writeFieldsRule struct {
+	insert   []string        // 允许写入的字段，为nil表示不限制
+	update   []string        // 允许更新的字段，为nil表示不限制
+	excluded []string        // 不允许写入及更新的字段
+	encrypt  map[string]bool // 需要加密的字段，键值表示是否为确定性加密模式
+}
+
+// 链式操作，排除字段，多个字段以半角逗号连接：
+// 查询时查询数据表中除排除字段之外的所有字段(仅支持单表查询)；写入/更新时忽略数据中的排除字段，例如：
+// db.Table("user").FieldsEx("password,is_admin").Data(r.GetPostMap()).Where("uid", uid).Update()
+func (md *Model) FieldsEx(fields string) *Model {
+	model := md.getModel()
+	model.fieldsEx = fields
+	return model
+}
+
+// 链式操作，开启严格模式，用于防止请求参数中的字段(例如is_admin)被批量赋值写入数据表：
+// 写入/更新的数据包含数据表中不存在的字段，或者包含不允许写入的字段(不在Fields指定的字段中、在FieldsEx排除的字段中，或者结构体标签不允许)时返回错误，
+// 非严格模式下忽略不允许写入的字段，不存在的字段由Filter方法过滤。
+func (md *Model) Strict(strict ...bool) *Model {
+	model := md.getModel()
+	model.strict = len(strict) == 0 || strict[0]
+	return model
+}
+
+// 根据Fields/FieldsEx及结构体标签规则检查写入/更新的数据，返回允许写入的数据(加密字段已加密)，严格模式下存在不允许写入的字段时返回错误
+func (md *Model) checkWriteData(data Map, op int) (Map, error) {
+	allowed, excluded := md.getWriteFields(op)
+	encrypt := (map[string]bool)(nil)
+	if md.writeRule != nil {
+		encrypt = md.writeRule.encrypt
+	}
+	if allowed == nil && len(excluded) == 0 && len(encrypt) == 0 && !md.strict {
+		return data, nil
+	}
+	result := make(Map, len(data))
+	for k, v := range data {
+		_, isExcluded := excluded[k]
+		_, isAllowed := allowed[k]
+		if isExcluded || (allowed != nil && !isAllowed) {
+			if md.strict {
+				return nil, fmt.Errorf(`field "%s" is not allowed to be written`, k)
+			}
+			continue
+		}
+		result[k] = v
+	}
+	if md.strict {
+		table := md.getWriteTable()
+		if table == "" || table[0] == '(' {
+			return result, nil
+		}
+		tableFields, err := md.db.getTableFields(strings.Trim(table, "`\"[]"))
+		if err != nil {
+			return nil, err
+		}
+		for k := range result {
+			if _, ok := tableFields[k]; !ok {
+				return nil, fmt.Errorf(`unknown field "%s" of table "%s"`, k, table)
+			}
+		}
+	}
+	if len(encrypt) > 0 {
+		if err := md.encryptData(result, encrypt); err != nil {
+			return nil, err
+		}
+		md.checkEncryptedLike(encrypt)
+	}
+	return result, nil
+}
+
+// 批量检查写入的数据，参考checkWriteData
+func (md *Model) checkWriteList(list List, op int) (List, error) {
+	result := make(List, len(list))
+	for i, m := range list {
+		data, err := md.checkWriteData(m, op)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = data
+	}
+	return result, nil
+}
+
+// 获取允许写入的字段及排除的字段，允许写入的字段为nil表示不限制
+func (md *Model) getWriteFields(op int) (allowed map[string]struct{}, excluded map[string]struct{}) {
+	excluded = make(map[string]struct{})
+	for _, field := range splitFields(md.fieldsEx) {
+		excluded[field] = struct{}{}
+	}
+	if md.fields != "" && md.fields != "*" {
+		allowed = intersectFields(allowed, splitFields(md.fields))
+	}
+	if rule := md.writeRule; rule != nil {
+		for _, field := range rule.excluded {
+			excluded[field] = struct{}{}
+		}
+		if rule.insert != nil && op != gWRITE_UPDATE {
+			allowed = intersectFields(allowed, rule.insert)
+		}
+		if rule.update != nil && op != gWRITE_INSERT {
+			allowed = intersectFields(allowed, rule.update)
+		}
+	}
+	return
+}
+
+// 获取查询的字段，设置了FieldsEx时返回数据表中除排除字段之外的所有字段(按照字段名称排序，保证生成的SQL语句不变)
+func (md *Model) getSelectFields() string {
+	if md.fieldsEx == "" || (md.fields != "" && md.fields != "*") {
+		return md.fields
+	}
+	table := md.getWriteTable()
+	if table == "" || table[0] == '(' {
+		return md.fields
+	}
+	tableFields, err := md.db.getTableFields(strings.Trim(table, "`\"[]"))
+	if err != nil || len(tableFields) == 0 {
+		return md.fields
+	}
+	excluded := make(map[string]struct{})
+	for _, field := range splitFields(md.fieldsEx) {
+		excluded[field] = struct{}{}
+	}
+	names := make([]string, 0, len(tableFields))
+	for name := range tableFields {
+		if _, ok := excluded[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	charL, charR := md.db.getChars()
+	for i, name := range names {
+		names[i] = charL + name + charR
+	}
+	return strings.Join(names, ",")
+}
+
+// 解析结构体标签定义的写入字段规则，没有定义规则时返回nil
+func getWriteFieldsRule(obj interface{}) *writeFieldsRule {
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	rule := &writeFieldsRule{
+		encrypt: getEncryptFields(obj),
+	}
+	defined := len(rule.encrypt) > 0
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("orm")
+		if tag == "" {
+			continue
+		}
+		options := parseOrmTag(tag)
+		key := getStructFieldMapKey(field)
+		if _, ok := options["-"]; ok {
+			rule.excluded = append(rule.excluded, key)
+			defined = true
+		}
+		if _, ok := options["insert"]; ok {
+			rule.insert = append(rule.insert, key)
+			defined = true
+		}
+		if _, ok := options["update"]; ok {
+			rule.update = append(rule.update, key)
+			defined = true
+		}
+	}
+	if !defined {
+		return nil
+	}
+	return rule
+}
+
+// 按照半角逗号分割字段列表，去掉空白及字段名称的引用符号
+func splitFields(fields string) []string {
+	if fields == "" {
+		return nil
+	}
+	array := make([]string, 0)
+	for _, field := range strings.Split(fields, ",") {
+		if field = strings.Trim(strings.TrimSpace(field), "`\"[]"); field != "" {
+			array = append(array, field)
+		}
+	}
+	return array
+}
+
+// 返回字段集合与字段列表的交集，字段集合为nil时返回字段列表的集合
+func intersectFields(set map[string]struct{}, fields []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		if _, ok := set[field]; ok || set == nil {
+			result[field] = struct{}{}
+		}
+	}
+	return result
+}
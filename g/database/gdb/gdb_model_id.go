@@ -0,0 +1,258 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/empty"
+)
+
+const (
+	ID_GENERATOR_UUID_V4   = "uuidv4"    // 随机UUID(版本4)，字符串类型
+	ID_GENERATOR_UUID_V7   = "uuidv7"    // 按照时间递增的UUID(版本7)，字符串类型，相比版本4对索引更加友好
+	ID_GENERATOR_SNOWFLAKE = "snowflake" // 雪花算法ID，64位整型，由毫秒时间戳(41位)、机器ID(10位)及序列号(12位)组成
+
+	gTABLE_PRIMARY_KEY_CACHE_PREFIX = "table_primary_key_" // 数据表主键字段名称的缓存键名前缀
+	gSNOWFLAKE_EPOCH                = 1546300800000        // 雪花算法的起始时间(2019-01-01 00:00:00 UTC，毫秒)
+	gSNOWFLAKE_WORKER_BITS          = 10                   // 雪花算法机器ID的位数
+	gSNOWFLAKE_SEQUENCE_BITS        = 12                   // 雪花算法序列号的位数
+	gSNOWFLAKE_MAX_WORKER_ID        = 1<<gSNOWFLAKE_WORKER_BITS - 1
+	gSNOWFLAKE_SEQUENCE_MASK        = 1<<gSNOWFLAKE_SEQUENCE_BITS - 1
+)
+
+// 雪花算法ID生成器
+type snowflake struct {
+	mu       sync.Mutex
+	workerId int64 // 机器ID
+	last     int64 // 最近一次生成ID使用的时间戳(相对起始时间的毫秒数)
+	sequence int64 // 同一毫秒内的序列号
+}
+
+// 写入数据时生成了主键值的执行结果
+type idSqlResult struct {
+	sql.Result
+	id interface{} // 生成的主键值
+}
+
+var (
+	// 雪花算法ID生成器，键名为机器ID
+	snowflakes   = make(map[int64]*snowflake)
+	snowflakesMu sync.Mutex
+)
+
+// 设置链式操作Insert/Replace/Save写入数据时的主键生成策略，主键为空时自动生成主键值，
+// generator为ID_GENERATOR_*常量，为空表示使用节点配置IdGenerator，节点未配置时不生成主键值。
+// workerId为雪花算法的机器ID(0-1023)，不指定时使用节点配置WorkerId，同时运行的多个进程应当使用不同的机器ID。
+// 只有数据表为单一主键并且主键不是自增字段时才会生成主键值，生成的主键值可以通过InsertAndGetId获取。
+func (bs *dbBase) SetIdGenerator(generator string, workerId ...int) {
+	bs.idGenerator.Set(generator)
+	if len(workerId) > 0 {
+		bs.workerId.Set(workerId[0])
+	}
+}
+
+// 使用当前的主键生成策略生成主键值，没有设置主键生成策略时返回错误
+func (bs *dbBase) GenerateId() (interface{}, error) {
+	generator, workerId := bs.getIdGenerator()
+	if generator == "" {
+		return nil, errors.New("id generator is not configured")
+	}
+	return generateId(generator, workerId)
+}
+
+// 获取主键生成策略及雪花算法的机器ID
+func (bs *dbBase) getIdGenerator() (generator string, workerId int) {
+	generator, workerId = bs.idGenerator.Val(), bs.workerId.Val()
+	if generator != "" && workerId >= 0 {
+		return
+	}
+	configs.RLock()
+	defer configs.RUnlock()
+	if node, err := getConfigNodeByGroup(bs.group, true); err == nil {
+		if generator == "" {
+			generator = node.IdGenerator
+		}
+		if workerId < 0 {
+			workerId = node.WorkerId
+		}
+	}
+	return
+}
+
+// 获取数据表可以自动生成主键值的主键字段名称，联合主键或者自增主键返回空，结果使用字段结构缓存
+func (bs *dbBase) getGeneratedIdField(table string) (field string, err error) {
+	v := bs.cache.GetOrSetFunc(gTABLE_PRIMARY_KEY_CACHE_PREFIX+table, func() interface{} {
+		tableFields := (map[string]*TableField)(nil)
+		bs.withoutDryRun(func() {
+			tableFields, err = bs.db.doTableFields(table, "")
+		})
+		if err != nil {
+			return nil
+		}
+		name := ""
+		for _, f := range tableFields {
+			if f.Key != "PRI" {
+				continue
+			}
+			if name != "" || strings.Contains(strings.ToLower(f.Extra), "auto_increment") {
+				return ""
+			}
+			name = f.Name
+		}
+		return name
+	}, bs.getTableFieldsTTL()*1000)
+	if err == nil && v != nil {
+		field = v.(string)
+	}
+	return
+}
+
+// 为写入数据生成主键值，数据中已经存在非空的主键值时不会被覆盖，返回新的数据及生成的主键值(没有生成时为nil)
+func (md *Model) fillInsertId(data Map) (Map, interface{}, error) {
+	generator, workerId := md.db.getIdGenerator()
+	if generator == "" {
+		return data, nil, nil
+	}
+	table := md.getWriteTable()
+	if table == "" || table[0] == '(' {
+		return data, nil, nil
+	}
+	field, err := md.db.getGeneratedIdField(strings.Trim(table, "`\"[]"))
+	if err != nil || field == "" {
+		return data, nil, err
+	}
+	if v, ok := data[field]; ok && !empty.IsEmpty(v) {
+		return data, nil, nil
+	}
+	id, err := generateId(generator, workerId)
+	if err != nil {
+		return nil, nil, err
+	}
+	newData := make(Map, len(data)+1)
+	for k, v := range data {
+		newData[k] = v
+	}
+	newData[field] = id
+	return newData, id, nil
+}
+
+// 为批量写入数据生成主键值，参考fillInsertId
+func (md *Model) fillInsertIdList(list List) (List, error) {
+	newList := make(List, len(list))
+	for i, data := range list {
+		m, _, err := md.fillInsertId(data)
+		if err != nil {
+			return nil, err
+		}
+		newList[i] = m
+	}
+	return newList, nil
+}
+
+// 链式操作，写入单条数据并返回主键值：生成了主键值时(参考SetIdGenerator)返回生成的主键值，否则返回自增主键值，
+// 支持返回字段语法的数据库(PostgreSQL/SQLite/SQL Server)通过RETURNING/OUTPUT获取主键值，其他数据库使用LastInsertId。
+func (md *Model) InsertAndGetId() (Value, error) {
+	if _, ok := md.data.(Map); !ok {
+		return nil, errors.New("InsertAndGetId requires single record data")
+	}
+	if output, returning := md.db.getReturningClause([]string{""}); output != "" || returning != "" {
+		ids, err := md.InsertAndGetIds()
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, errors.New("no inserted record returned")
+		}
+		return ids[0], nil
+	}
+	result, err := md.Insert()
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := result.(*idSqlResult); ok {
+		return gvar.New(r.id, true), nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return gvar.New(id, true), nil
+}
+
+// 返回生成的主键值，字符串类型的主键值(UUID)返回错误
+func (r *idSqlResult) LastInsertId() (int64, error) {
+	if id, ok := r.id.(int64); ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf(`generated id "%v" is not an integer`, r.id)
+}
+
+// 使用指定的策略生成主键值
+func generateId(generator string, workerId int) (interface{}, error) {
+	switch strings.ToLower(generator) {
+	case ID_GENERATOR_UUID_V4, "uuid":
+		return newUUID(4)
+	case ID_GENERATOR_UUID_V7:
+		return newUUID(7)
+	case ID_GENERATOR_SNOWFLAKE:
+		if workerId < 0 || workerId > gSNOWFLAKE_MAX_WORKER_ID {
+			return nil, fmt.Errorf("invalid snowflake worker id %d, it should be between 0 and %d", workerId, gSNOWFLAKE_MAX_WORKER_ID)
+		}
+		snowflakesMu.Lock()
+		s, ok := snowflakes[int64(workerId)]
+		if !ok {
+			s = &snowflake{workerId: int64(workerId)}
+			snowflakes[int64(workerId)] = s
+		}
+		snowflakesMu.Unlock()
+		return s.next(), nil
+	}
+	return nil, fmt.Errorf(`unsupported id generator "%s"`, generator)
+}
+
+// 生成下一个ID，时钟回拨或者同一毫秒内序列号用尽时使用上一次的时间戳继续递增，保证ID单调递增
+func (s *snowflake) next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UnixNano()/int64(time.Millisecond) - gSNOWFLAKE_EPOCH
+	if now <= s.last {
+		now = s.last
+		s.sequence = (s.sequence + 1) & gSNOWFLAKE_SEQUENCE_MASK
+		if s.sequence == 0 {
+			now++
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.last = now
+	return now<<(gSNOWFLAKE_WORKER_BITS+gSNOWFLAKE_SEQUENCE_BITS) | s.workerId<<gSNOWFLAKE_SEQUENCE_BITS | s.sequence
+}
+
+// 生成UUID字符串，version为4时为随机UUID，为7时前48位为毫秒时间戳
+func newUUID(version int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	if version == 7 {
+		ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+		binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+		binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	}
+	b[6] = b[6]&0x0f | byte(version<<4)
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
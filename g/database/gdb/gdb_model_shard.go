@@ -0,0 +1,163 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gf/g/container/gvar"
+)
+
+// 分片规则，根据分片键值返回实际操作的数据表名称(分表)或者数据库配置分组名称(分库)
+type ShardFunc func(md *Model, key interface{}) string
+
+// 链式操作，设置分表规则，f根据分片键值返回实际操作的数据表名称，分片键值通过Shard方法指定，例如：
+// db.Table("user").ShardTable(func(md *gdb.Model, key interface{}) string { return fmt.Sprintf("user_%d", gconv.Int(key)%64) }).Shard(uid).One()
+// f返回的数据表名称不需要包含别名，只替换Table方法指定的第一个数据表名称，别名及联表的数据表不受影响。
+func (md *Model) ShardTable(f ShardFunc) *Model {
+	model := md.getModel()
+	model.shardTable = f
+	return model
+}
+
+// 链式操作，设置分库规则，f根据分片键值返回实际操作的数据库配置分组名称，分片键值通过Shard方法指定，
+// 各个分组的数据库对象通过Instance获取，并使用当前数据库对象的上下文。可以与分表规则同时使用，
+// 注意事务只能在开启事务的数据库中执行，因此事务的链式操作不支持分库。
+func (md *Model) ShardDb(f ShardFunc) *Model {
+	model := md.getModel()
+	model.shardDb = f
+	return model
+}
+
+// 链式操作，指定分片键值，设置了分表/分库规则时按照分片键值路由到实际的数据表/数据库执行操作。
+// 指定多个分片键值时为跨分片操作，仅支持简单的查询及聚合：
+// 1. All/Select等查询在各个分片中分别执行(包括OrderBy/Limit条件)，按照分片顺序合并查询结果；
+// 2. Count/CountColumn/SumFloat返回各个分片结果的总和，存在GroupBy时Count为各个分片分组数量的总和；
+// 3. AvgDecimal以及写入/更新/删除操作不支持跨分片，返回错误。
+// 多个分片键值路由到相同的数据表时只执行一次。
+func (md *Model) Shard(keys ...interface{}) *Model {
+	model := md.getModel()
+	model.shardKeys = keys
+	return model
+}
+
+// 是否设置了分片规则
+func (md *Model) isSharding() bool {
+	return md.shardTable != nil || md.shardDb != nil
+}
+
+// 按照分片规则及分片键值返回实际执行操作的模型对象列表，没有设置分片规则时返回nil
+func (md *Model) getShardModels() ([]*Model, error) {
+	if !md.isSharding() {
+		return nil, nil
+	}
+	if len(md.shardKeys) == 0 {
+		return nil, errors.New("sharding key is required for sharding model, use Shard to specify")
+	}
+	models := make([]*Model, 0, len(md.shardKeys))
+	routes := make(map[string]struct{}, len(md.shardKeys))
+	for _, key := range md.shardKeys {
+		model := md.Clone()
+		model.shardTable = nil
+		model.shardDb = nil
+		model.shardKeys = nil
+		group := ""
+		if md.shardDb != nil {
+			if md.tx != nil {
+				return nil, errors.New("sharding database is not supported in transaction")
+			}
+			group = md.shardDb(md, key)
+			db, err := Instance(group)
+			if err != nil {
+				return nil, err
+			}
+			if db == nil {
+				return nil, fmt.Errorf(`invalid database group "%s" for sharding key "%v"`, group, key)
+			}
+			model.db = db.Ctx(md.db.GetCtx())
+		}
+		if md.shardTable != nil {
+			table := md.shardTable(md, key)
+			if table == "" {
+				return nil, fmt.Errorf(`empty sharding table for sharding key "%v"`, key)
+			}
+			// 保留数据表别名及联表部分，例如："user u LEFT JOIN ..."替换为"user_1 u LEFT JOIN ..."
+			init := strings.TrimSpace(md.tablesInit)
+			if pos := strings.IndexAny(init, " \t\n,"); pos > 0 {
+				table += init[pos:]
+			}
+			model.tables = table + strings.TrimPrefix(md.tables, md.tablesInit)
+			model.tablesInit = table
+		}
+		route := group + "/" + model.tables
+		if _, ok := routes[route]; ok {
+			continue
+		}
+		routes[route] = struct{}{}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// 返回写操作路由到的单个分片模型对象，没有设置分片规则时返回nil，路由到多个分片时返回错误
+func (md *Model) getShardModel() (*Model, error) {
+	models, err := md.getShardModels()
+	if err != nil || models == nil {
+		return nil, err
+	}
+	if len(models) > 1 {
+		return nil, errors.New("operation across multiple shards is not supported")
+	}
+	return models[0], nil
+}
+
+// 在各个分片中执行查询并按照分片顺序合并查询结果
+func (md *Model) shardAll(models []*Model) (Result, error) {
+	result := make(Result, 0)
+	for _, model := range models {
+		r, err := model.All()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r...)
+	}
+	return result, nil
+}
+
+// 在各个分片中执行数量查询并返回总和
+func (md *Model) shardCount(models []*Model) (int, error) {
+	total := 0
+	for _, model := range models {
+		n, err := model.Count()
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// 在各个分片中执行聚合查询，目前仅支持SUM的跨分片合并
+func (md *Model) shardAggregate(models []*Model, function string, column string) (Value, error) {
+	if len(models) == 1 {
+		return models[0].doAggregate(function, column)
+	}
+	if function != "SUM" {
+		return nil, fmt.Errorf("aggregate function %s across multiple shards is not supported", function)
+	}
+	sum := float64(0)
+	for _, model := range models {
+		value, err := model.doAggregate(function, column)
+		if err != nil {
+			return nil, err
+		}
+		sum += value.Float64()
+	}
+	return gvar.New(sum, true), nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gf/g/os/gtime"
+)
+
+const (
+	gDEFAULT_FIELD_CREATED = "created_at" // 默认的创建时间字段名称
+	gDEFAULT_FIELD_UPDATED = "updated_at" // 默认的更新时间字段名称
+	gDEFAULT_FIELD_DELETED = "deleted_at" // 默认的软删除时间字段名称
+)
+
+// 链式操作自动维护的数据表时间字段名称，字段名称为空表示不使用对应的特性。
+// 只有数据表中存在对应的字段时才会自动维护，字段类型为整型时使用时间戳，否则使用"Y-m-d H:i:s"格式的时间。
+type TimeFields struct {
+	Created string // 创建时间字段，Insert/Replace/Save(仅写入新记录时)自动写入
+	Updated string // 更新时间字段，Insert/Replace/Save/Update时自动写入
+	Deleted string // 软删除时间字段，Delete时写入该字段而不删除记录，查询时自动过滤已删除的记录
+}
+
+// 设置链式操作自动维护的数据表时间字段名称，字段名称为空表示不使用对应的特性。
+// 时间字段及软删除特性默认不启用(以免改变已有数据表Delete及查询操作的行为)，
+// 可以通过该方法设置字段名称启用，或者通过节点配置TimeMaintain启用并使用默认的字段名称：created_at, updated_at, deleted_at。
+func (bs *dbBase) SetTimeFields(fields TimeFields) {
+	bs.timeFields.Set(fields)
+}
+
+// 获取链式操作自动维护的数据表时间字段名称，未启用时返回的字段名称均为空
+func (bs *dbBase) GetTimeFields() TimeFields {
+	if v := bs.timeFields.Val(); v != nil {
+		return v.(TimeFields)
+	}
+	if node, err := getConfigNodeByGroup(bs.group, true); err == nil && node.TimeMaintain {
+		return TimeFields{
+			Created: gDEFAULT_FIELD_CREATED,
+			Updated: gDEFAULT_FIELD_UPDATED,
+			Deleted: gDEFAULT_FIELD_DELETED,
+		}
+	}
+	return TimeFields{}
+}
+
+// 链式操作，不使用软删除特性：查询时不过滤已删除的记录，Delete时直接删除记录。
+func (md *Model) Unscoped() *Model {
+	model := md.getModel()
+	model.unscoped = true
+	return model
+}
+
+// 获取数据表中存在的时间字段名称及字段类型，不存在的字段名称为空
+func (md *Model) getTimeFields(table string) (fields TimeFields, types TimeFields) {
+	names := md.db.GetTimeFields()
+	if names.Created == "" && names.Updated == "" && names.Deleted == "" {
+		return
+	}
+	// 子查询没有字段结构
+	if table == "" || table[0] == '(' {
+		return
+	}
+	tableFields, err := md.db.getTableFields(strings.Trim(table, "`\"[]"))
+	if err != nil {
+		return
+	}
+	if t, ok := tableFields[names.Created]; ok && names.Created != "" {
+		fields.Created, types.Created = names.Created, t
+	}
+	if t, ok := tableFields[names.Updated]; ok && names.Updated != "" {
+		fields.Updated, types.Updated = names.Updated, t
+	}
+	if t, ok := tableFields[names.Deleted]; ok && names.Deleted != "" {
+		fields.Deleted, types.Deleted = names.Deleted, t
+	}
+	return
+}
+
+// 获取写操作的数据表名称，多表操作时返回空
+func (md *Model) getWriteTable() string {
+	if strings.ContainsAny(md.tables, ",") || strings.Contains(strings.ToUpper(md.tables), " JOIN ") {
+		return ""
+	}
+	return tableName(md.tables)
+}
+
+// 获取数据表字段类型对应的当前时间值，整型字段为时间戳
+func timeFieldValue(now *gtime.Time, fieldType string) interface{} {
+	if strings.Contains(strings.ToLower(fieldType), "int") {
+		return now.Unix()
+	}
+	return now.Format("Y-m-d H:i:s")
+}
+
+// 为写入数据设置创建时间及更新时间字段，数据中已经存在的字段不会被覆盖，返回新的数据
+func (md *Model) fillInsertTime(data Map) Map {
+	table := md.getWriteTable()
+	if table == "" {
+		return data
+	}
+	fields, types := md.getTimeFields(table)
+	if fields.Created == "" && fields.Updated == "" {
+		return data
+	}
+	now := gtime.Now()
+	newData := make(Map, len(data)+2)
+	for k, v := range data {
+		newData[k] = v
+	}
+	if _, ok := newData[fields.Created]; !ok && fields.Created != "" {
+		newData[fields.Created] = timeFieldValue(now, types.Created)
+	}
+	if _, ok := newData[fields.Updated]; !ok && fields.Updated != "" {
+		newData[fields.Updated] = timeFieldValue(now, types.Updated)
+	}
+	return newData
+}
+
+// 为批量写入数据设置创建时间及更新时间字段，返回新的数据列表
+func (md *Model) fillInsertTimeList(list List) List {
+	newList := make(List, len(list))
+	for i, m := range list {
+		newList[i] = md.fillInsertTime(m)
+	}
+	return newList
+}
+
+// 获取save操作中只在写入新记录时设置的字段，即自动写入的创建时间字段，
+// 数据已存在时保持原有的创建时间，写入数据中指定的创建时间仍然会被更新
+func (md *Model) getInsertOnlyFields(data Map) []string {
+	table := md.getWriteTable()
+	if table == "" {
+		return nil
+	}
+	fields, _ := md.getTimeFields(table)
+	if fields.Created == "" {
+		return nil
+	}
+	if _, ok := data[fields.Created]; ok {
+		return nil
+	}
+	return []string{fields.Created}
+}
+
+// 为更新数据设置更新时间字段，返回新的数据
+func (md *Model) fillUpdateTime(data interface{}) interface{} {
+	table := md.getWriteTable()
+	if table == "" {
+		return data
+	}
+	fields, types := md.getTimeFields(table)
+	if fields.Updated == "" {
+		return data
+	}
+	value := timeFieldValue(gtime.Now(), types.Updated)
+	switch v := data.(type) {
+	case Map:
+		if _, ok := v[fields.Updated]; ok {
+			return data
+		}
+		newData := make(Map, len(v)+1)
+		for k, value := range v {
+			newData[k] = value
+		}
+		newData[fields.Updated] = value
+		return newData
+	case string:
+		if v == "" || strings.Contains(v, fields.Updated) {
+			return data
+		}
+		charL, charR := md.db.getChars()
+		if s, ok := value.(string); ok {
+			return fmt.Sprintf("%s,%s%s%s='%s'", v, charL, fields.Updated, charR, s)
+		}
+		return fmt.Sprintf("%s,%s%s%s=%v", v, charL, fields.Updated, charR, value)
+	}
+	return data
+}
+
+// 获取过滤已软删除记录的查询条件，多个表时每个表的条件使用AND连接，不使用软删除时返回空
+func (md *Model) getSoftDeleteCondition() string {
+	if md.unscoped {
+		return ""
+	}
+	conditions := make([]string, 0)
+	charL, charR := md.db.getChars()
+	for _, table := range strings.Split(md.tablesInit, ",") {
+		fields, types := md.getTimeFields(tableName(table))
+		if fields.Deleted == "" {
+			continue
+		}
+		field := tableAlias(table) + "." + charL + fields.Deleted + charR
+		if strings.Contains(strings.ToLower(types.Deleted), "int") {
+			conditions = append(conditions, field+"=0")
+		} else {
+			conditions = append(conditions, field+" IS NULL")
+		}
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// 获取加上软删除过滤条件的查询条件
+func (md *Model) getWhereWithSoftDelete() string {
+	condition := md.getSoftDeleteCondition()
+	if condition == "" {
+		return md.where
+	}
+	if md.where == "" {
+		return condition
+	}
+	return fmt.Sprintf("(%s) AND %s", md.where, condition)
+}
+
+// 获取软删除操作的更新数据，不使用软删除时返回nil
+func (md *Model) getSoftDeleteData() Map {
+	if md.unscoped {
+		return nil
+	}
+	table := md.getWriteTable()
+	if table == "" {
+		return nil
+	}
+	fields, types := md.getTimeFields(table)
+	if fields.Deleted == "" {
+		return nil
+	}
+	return Map{fields.Deleted: timeFieldValue(gtime.Now(), types.Deleted)}
+}
+
+// 获取数据表名称(去掉别名)
+func tableName(table string) string {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
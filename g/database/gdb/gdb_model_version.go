@@ -0,0 +1,89 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gf/g/util/gconv"
+)
+
+const (
+	gDEFAULT_FIELD_VERSION = "version" // 默认的乐观锁版本字段名称
+)
+
+// 乐观锁冲突错误，链式操作Update时记录已经被其他操作修改(或者删除)，没有记录被更新
+type OptimisticLockError struct {
+	Table   string      // 数据表名称
+	Version interface{} // 更新时提交的版本号
+}
+
+// 错误信息
+func (e *OptimisticLockError) Error() string {
+	return fmt.Sprintf(`optimistic lock conflict: table "%s" version "%v" has been modified`, e.Table, e.Version)
+}
+
+// 设置链式操作乐观锁的版本字段名称，默认为：version，为空表示不使用乐观锁。
+// 链式操作Update时，如果数据表存在版本字段并且更新数据中包含该字段，
+// 将会使用更新数据中的版本号作为更新条件，并将版本字段更新为版本号加1，
+// 没有记录被更新时表示记录已被其他操作修改，返回*OptimisticLockError错误。
+func (bs *dbBase) SetVersionField(field string) {
+	bs.versionField.Set(field)
+}
+
+// 获取链式操作乐观锁的版本字段名称
+func (bs *dbBase) GetVersionField() string {
+	if v := bs.versionField.Val(); v != nil {
+		return v.(string)
+	}
+	return gDEFAULT_FIELD_VERSION
+}
+
+// 为更新数据及条件添加乐观锁版本字段，返回新的数据、条件及条件参数，
+// 不使用乐观锁时version为nil。
+func (md *Model) fillUpdateVersion(data interface{}, where string, args []interface{}) (newData interface{}, newWhere string, newArgs []interface{}, version interface{}) {
+	newData, newWhere, newArgs = data, where, args
+	m, ok := data.(Map)
+	if !ok {
+		return
+	}
+	field := md.db.GetVersionField()
+	if field == "" {
+		return
+	}
+	if version, ok = m[field]; !ok {
+		return
+	}
+	table := md.getWriteTable()
+	if table == "" {
+		return newData, newWhere, newArgs, nil
+	}
+	tableFields, err := md.db.getTableFields(strings.Trim(table, "`\"[]"))
+	if err != nil {
+		return newData, newWhere, newArgs, nil
+	}
+	if _, ok := tableFields[field]; !ok {
+		return newData, newWhere, newArgs, nil
+	}
+	updateData := make(Map, len(m))
+	for k, v := range m {
+		updateData[k] = v
+	}
+	updateData[field] = gconv.Int64(version) + 1
+	charL, charR := md.db.getChars()
+	condition := fmt.Sprintf("%s%s%s=?", charL, field, charR)
+	if where == "" {
+		newWhere = condition
+	} else {
+		newWhere = fmt.Sprintf("(%s) AND %s", where, condition)
+	}
+	newArgs = make([]interface{}, 0, len(args)+1)
+	newArgs = append(newArgs, args...)
+	newArgs = append(newArgs, version)
+	return updateData, newWhere, newArgs, version
+}
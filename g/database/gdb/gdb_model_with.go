@@ -0,0 +1,173 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gf/g/util/gconv"
+)
+
+// 链式操作，查询结果映射到struct/[]struct时，关联查询并映射带有orm:"with:..."标签的关联属性，
+// 参数attrNames为需要关联查询的属性名称，为空时表示关联查询所有的关联属性。
+// 标签格式为：with:关联表字段=当前结构体字段[, table:关联表名称]，
+// 没有指定table时，关联属性的结构体需要实现TableName() string方法返回关联表名称，
+// 关联属性的类型可以为struct/*struct/[]struct/[]*struct，例如属性：
+// Detail *UserDetail `orm:"with:uid=id, table:user_detail"`
+// 表示使用当前结构体Id属性的值查询user_detail表uid字段关联的记录，映射到Detail属性中:
+// db.Table("user").With().Where("id", 1).Struct(&user)
+func (md *Model) With(attrNames ...string) *Model {
+	model := md.getModel()
+	model.withEnabled = true
+	model.withAttrs = attrNames
+	return model
+}
+
+// 对映射后的struct/[]struct指针对象执行关联查询，并映射关联属性
+func (md *Model) doWith(pointer interface{}) error {
+	if !md.withEnabled {
+		return nil
+	}
+	items := make([]reflect.Value, 0)
+	elem := reflect.ValueOf(pointer)
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	switch elem.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < elem.Len(); i++ {
+			items = append(items, scanListItem(elem.Index(i)))
+		}
+	case reflect.Struct:
+		items = append(items, elem)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	structType := items[0].Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		options := parseOrmTag(field.Tag.Get("orm"))
+		with, ok := options["with"]
+		if !ok {
+			continue
+		}
+		if len(md.withAttrs) > 0 {
+			found := false
+			for _, name := range md.withAttrs {
+				if name == field.Name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		if err := md.doWithAttribute(items, i, with, options["table"]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 关联查询并映射指定索引位置的关联属性
+func (md *Model) doWithAttribute(items []reflect.Value, index int, with string, table string) error {
+	field := items[0].Type().Field(index)
+	keys := strings.Split(with, "=")
+	if len(keys) != 2 {
+		return fmt.Errorf(`invalid with tag "%s" of attribute "%s", which should be in format "with:field=attribute field"`, with, field.Name)
+	}
+	relatedKey, localKey := strings.TrimSpace(keys[0]), strings.TrimSpace(keys[1])
+	if table == "" {
+		relatedType := field.Type
+		for relatedType.Kind() == reflect.Ptr || relatedType.Kind() == reflect.Slice || relatedType.Kind() == reflect.Array {
+			relatedType = relatedType.Elem()
+		}
+		if relatedType.Kind() != reflect.Struct {
+			return fmt.Errorf(`attribute "%s" should be type of struct/*struct/[]struct/[]*struct, but got: %v`, field.Name, field.Type)
+		}
+		if v, ok := reflect.New(relatedType).Interface().(apiTableName); ok {
+			table = v.TableName()
+		} else {
+			return fmt.Errorf(`table of attribute "%s" is not specified, which should be specified by tag "table:" or method "TableName"`, field.Name)
+		}
+	}
+	// 收集当前结构体的关联字段值
+	values := make([]interface{}, 0, len(items))
+	localValues := make([]string, len(items))
+	exists := make(map[string]struct{})
+	for i, item := range items {
+		local := findStructFieldByKey(item, localKey)
+		if !local.IsValid() {
+			return fmt.Errorf(`field "%s" of attribute "%s" not found in %v`, localKey, field.Name, item.Type())
+		}
+		localValues[i] = gconv.String(local.Interface())
+		if _, ok := exists[localValues[i]]; !ok {
+			exists[localValues[i]] = struct{}{}
+			values = append(values, local.Interface())
+		}
+	}
+	model := (*Model)(nil)
+	if md.tx != nil {
+		model = md.tx.Table(table)
+	} else {
+		model = md.db.Table(table)
+	}
+	result, err := model.Where(relatedKey+" IN(?)", values).All()
+	if err != nil {
+		return err
+	}
+	grouped := make(map[string]Result)
+	for _, record := range result {
+		if v, ok := record[relatedKey]; ok {
+			key := v.String()
+			grouped[key] = append(grouped[key], record)
+		}
+	}
+	for i, item := range items {
+		if records, ok := grouped[localValues[i]]; ok {
+			if err := scanToAttribute(item.Field(index), records); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 按照键名查找结构体的属性，支持gconv/json标签名称、忽略大小写及下划线的属性名称，以及匿名结构体的属性
+func findStructFieldByKey(elem reflect.Value, key string) reflect.Value {
+	elemType := elem.Type()
+	for i := 0; i < elem.NumField(); i++ {
+		field := elemType.Field(i)
+		if _, ok := getStructFieldKey(field, map[string]interface{}{key: nil}, map[string]string{
+			formatStructMatchName(key): key,
+		}); ok {
+			return elem.Field(i)
+		}
+	}
+	for i := 0; i < elem.NumField(); i++ {
+		if !elemType.Field(i).Anonymous {
+			continue
+		}
+		value := elem.Field(i)
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Struct {
+			if v := findStructFieldByKey(value, key); v.IsValid() {
+				return v
+			}
+		}
+	}
+	return reflect.Value{}
+}
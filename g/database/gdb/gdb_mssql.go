@@ -16,6 +16,7 @@ package gdb
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -49,6 +50,16 @@ func (db *dbMssql) getChars() (charLeft string, charRight string) {
 	return "\"", "\""
 }
 
+// 获得查询语句的执行计划SQL，SQL Server需要通过SET SHOWPLAN获取执行计划，这里不支持
+func (db *dbMssql) getExplainSql(query string) string {
+	return ""
+}
+
+// SQL Server驱动在上下文取消时发送Attention信号中断语句执行，不需要服务端取消
+func (db *dbMssql) getCancelSql() (idSql string, cancelSql string) {
+	return "", ""
+}
+
 // 在执行sql之前对sql进行进一步处理
 func (db *dbMssql) handleSqlBeforeExec(query string) string {
 	index := 0
@@ -62,6 +73,16 @@ func (db *dbMssql) handleSqlBeforeExec(query string) string {
 	return db.parseSql(str)
 }
 
+// 获取save操作(upsert)写入语句的冲突更新子句，暂不支持
+func (db *dbMssql) getSaveClause(fields []string, conflict []string) (string, error) {
+	return "", errors.New("save operation is not supported by mssql")
+}
+
+// 获取写入语句返回写入数据字段的子句，使用OUTPUT INSERTED语法
+func (db *dbMssql) getReturningClause(fields []string) (output string, returning string) {
+	return "OUTPUT " + quoteFields(db, fields, "INSERTED."), ""
+}
+
 //将MYSQL的SQL语法转换为MSSQL的语法
 //1.由于mssql不支持limit写法所以需要对mysql中的limit用法做转换
 func (db *dbMssql) parseSql(sql string) string {
@@ -153,10 +174,8 @@ func (db *dbMssql) parseSql(sql string) string {
 
 // 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值暂无用途(默认为字段数据类型).
 func (db *dbMssql) getTableFields(table string) (fields map[string]string, err error) {
-	// 缓存不存在时会查询数据表结构，缓存后不过期，直至程序重启(重新部署)
-	v := db.cache.GetOrSetFunc("table_fields_"+table, func() interface{} {
-		result := (Result)(nil)
-		result, err = db.GetAll(fmt.Sprintf(`
+	return db.getTableFieldsWithCache(table, func() (map[string]string, error) {
+		result, err := db.GetAll(fmt.Sprintf(`
 		SELECT c.name as FIELD, CASE t.name 
 			WHEN 'numeric' THEN t.name + '(' + convert(varchar(20),c.xprec) + ',' + convert(varchar(20),c.xscale) + ')' 
 			WHEN 'char' THEN t.name + '(' + convert(varchar(20),c.length)+ ')'
@@ -164,16 +183,76 @@ func (db *dbMssql) getTableFields(table string) (fields map[string]string, err e
 			ELSE t.name + '(' + convert(varchar(20),c.length)+ ')' END as TYPE
 		FROM systypes t,syscolumns c WHERE t.xtype=c.xtype AND c.id = (SELECT id FROM sysobjects WHERE name='%s') ORDER BY c.colid`, strings.ToUpper(table)))
 		if err != nil {
-			return nil
+			return nil, err
 		}
-		fields = make(map[string]string)
+		fields := make(map[string]string)
 		for _, m := range result {
 			fields[strings.ToLower(m["FIELD"].String())] = strings.ToLower(m["TYPE"].String()) //sqlserver返回的field为大写的需要转为小写的
 		}
-		return fields
-	}, 0)
-	if err == nil {
-		fields = v.(map[string]string)
+		return fields, nil
+	})
+}
+
+// 获取数据表名称列表，schema为空时表示当前用户的默认schema
+func (db *dbMssql) doTables(schema string) ([]string, error) {
+	result, err := db.GetAll(`SELECT TABLE_NAME AS name FROM INFORMATION_SCHEMA.TABLES
+	WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA = COALESCE(NULLIF(?, ''), SCHEMA_NAME()) ORDER BY TABLE_NAME`, schema)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, len(result))
+	for i, m := range result {
+		tables[i] = m["name"].String()
+	}
+	return tables, nil
+}
+
+// 获取数据表的字段结构信息，IDENTITY字段标记为auto_increment
+func (db *dbMssql) doTableFields(table string, schema string) (map[string]*TableField, error) {
+	result, err := db.GetAll(`
+	SELECT c.COLUMN_NAME AS name, c.DATA_TYPE AS type, c.CHARACTER_MAXIMUM_LENGTH AS length,
+		c.NUMERIC_PRECISION AS precision, c.NUMERIC_SCALE AS scale, c.IS_NULLABLE AS nullable, c.COLUMN_DEFAULT AS default_value,
+		COLUMNPROPERTY(OBJECT_ID(c.TABLE_SCHEMA + '.' + c.TABLE_NAME), c.COLUMN_NAME, 'IsIdentity') AS is_identity,
+		(SELECT TOP 1 CASE tc.CONSTRAINT_TYPE WHEN 'PRIMARY KEY' THEN 'PRI' ELSE 'UNI' END
+			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+			JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k ON k.CONSTRAINT_NAME = tc.CONSTRAINT_NAME AND k.TABLE_SCHEMA = tc.TABLE_SCHEMA
+			WHERE tc.TABLE_SCHEMA = c.TABLE_SCHEMA AND tc.TABLE_NAME = c.TABLE_NAME AND k.COLUMN_NAME = c.COLUMN_NAME
+				AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE')
+			ORDER BY tc.CONSTRAINT_TYPE) AS key_type
+	FROM INFORMATION_SCHEMA.COLUMNS c
+	WHERE c.TABLE_NAME = ? AND c.TABLE_SCHEMA = COALESCE(NULLIF(?, ''), SCHEMA_NAME())
+	ORDER BY c.ORDINAL_POSITION`, table, schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf(`table "%s" does not exist`, table)
+	}
+	fields := make(map[string]*TableField, len(result))
+	for i, m := range result {
+		fieldType := strings.ToLower(m["type"].String())
+		switch {
+		case m["length"].Int() > 0:
+			fieldType += fmt.Sprintf("(%d)", m["length"].Int())
+		case m["length"].Int() == -1:
+			fieldType += "(max)"
+		case fieldType == "numeric" || fieldType == "decimal":
+			fieldType += fmt.Sprintf("(%d,%d)", m["precision"].Int(), m["scale"].Int())
+		}
+		field := &TableField{
+			Index: i,
+			Name:  strings.ToLower(m["name"].String()),
+			Type:  fieldType,
+			Null:  strings.EqualFold(m["nullable"].String(), "YES"),
+			Key:   m["key_type"].String(),
+		}
+		if !m["default_value"].IsNil() {
+			field.Default = m["default_value"].String()
+		}
+		if m["is_identity"].Int() == 1 {
+			field.Extra = "auto_increment"
+		}
+		fields[field.Name] = field
 	}
-	return
+	return fields, nil
 }
@@ -16,6 +16,7 @@ package gdb
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -48,6 +49,16 @@ func (db *dbOracle) getChars() (charLeft string, charRight string) {
 	return "\"", "\""
 }
 
+// 获得查询语句的执行计划SQL，Oracle的EXPLAIN PLAN不直接返回执行计划，这里不支持
+func (db *dbOracle) getExplainSql(query string) string {
+	return ""
+}
+
+// Oracle取消语句(ALTER SYSTEM CANCEL SQL)需要额外的系统权限，这里不支持
+func (db *dbOracle) getCancelSql() (idSql string, cancelSql string) {
+	return "", ""
+}
+
 // 在执行sql之前对sql进行进一步处理
 func (db *dbOracle) handleSqlBeforeExec(query string) string {
 	index := 0
@@ -61,6 +72,11 @@ func (db *dbOracle) handleSqlBeforeExec(query string) string {
 	return db.parseSql(str)
 }
 
+// 获取save操作(upsert)写入语句的冲突更新子句，暂不支持
+func (db *dbOracle) getSaveClause(fields []string, conflict []string) (string, error) {
+	return "", errors.New("save operation is not supported by oracle")
+}
+
 //由于ORACLE中对LIMIT和批量插入的语法与MYSQL不一致，所以这里需要对LIMIT和批量插入做语法上的转换
 func (db *dbOracle) parseSql(sql string) string {
 	//下面的正则表达式匹配出SELECT和INSERT的关键字后分别做不同的处理，如有LIMIT则将LIMIT的关键字也匹配出
@@ -146,27 +162,83 @@ func (db *dbOracle) parseSql(sql string) string {
 
 // 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值暂无用途(默认为字段数据类型).
 func (db *dbOracle) getTableFields(table string) (fields map[string]string, err error) {
-	// 缓存不存在时会查询数据表结构，缓存后不过期，直至程序重启(重新部署)
-	v := db.cache.GetOrSetFunc("table_fields_"+table, func() interface{} {
-		result := (Result)(nil)
-		result, err = db.GetAll(fmt.Sprintf(`
+	return db.getTableFieldsWithCache(table, func() (map[string]string, error) {
+		result, err := db.GetAll(fmt.Sprintf(`
 		SELECT COLUMN_NAME AS FIELD, CASE DATA_TYPE 
 		    WHEN 'NUMBER' THEN DATA_TYPE||'('||DATA_PRECISION||','||DATA_SCALE||')' 
 			WHEN 'FLOAT' THEN DATA_TYPE||'('||DATA_PRECISION||','||DATA_SCALE||')' 
 			ELSE DATA_TYPE||'('||DATA_LENGTH||')' END AS TYPE  
 		FROM USER_TAB_COLUMNS WHERE TABLE_NAME = '%s' ORDER BY COLUMN_ID`, strings.ToUpper(table)))
 		if err != nil {
-			return nil
+			return nil, err
 		}
-
-		fields = make(map[string]string)
+		fields := make(map[string]string)
 		for _, m := range result {
 			fields[strings.ToLower(m["FIELD"].String())] = strings.ToLower(m["TYPE"].String()) //ORACLE返回的值默认都是大写的，需要转为小写
 		}
-		return fields
-	}, 0)
-	if err == nil {
-		fields = v.(map[string]string)
+		return fields, nil
+	})
+}
+
+// 获取数据表名称列表，schema为空时表示当前用户，ORACLE返回的名称默认为大写，统一转换为小写
+func (db *dbOracle) doTables(schema string) ([]string, error) {
+	query := `SELECT TABLE_NAME FROM USER_TABLES ORDER BY TABLE_NAME`
+	args := []interface{}(nil)
+	if schema != "" {
+		query = `SELECT TABLE_NAME FROM ALL_TABLES WHERE OWNER = ? ORDER BY TABLE_NAME`
+		args = append(args, strings.ToUpper(schema))
+	}
+	result, err := db.GetAll(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, len(result))
+	for i, m := range result {
+		tables[i] = strings.ToLower(m["TABLE_NAME"].String())
+	}
+	return tables, nil
+}
+
+// 获取数据表的字段结构信息，schema为空时表示当前用户
+func (db *dbOracle) doTableFields(table string, schema string) (map[string]*TableField, error) {
+	prefix, ownerCondition := "USER", ""
+	args := []interface{}{strings.ToUpper(table)}
+	if schema != "" {
+		prefix, ownerCondition = "ALL", " AND c.OWNER = ?"
+		args = append(args, strings.ToUpper(schema))
+	}
+	result, err := db.GetAll(fmt.Sprintf(`
+	SELECT c.COLUMN_NAME AS FIELD, CASE c.DATA_TYPE
+			WHEN 'NUMBER' THEN c.DATA_TYPE||'('||c.DATA_PRECISION||','||c.DATA_SCALE||')'
+			WHEN 'FLOAT' THEN c.DATA_TYPE||'('||c.DATA_PRECISION||','||c.DATA_SCALE||')'
+			ELSE c.DATA_TYPE||'('||c.DATA_LENGTH||')' END AS TYPE,
+		c.NULLABLE, c.DATA_DEFAULT, cc.COMMENTS,
+		(SELECT MIN(CASE con.CONSTRAINT_TYPE WHEN 'P' THEN 'PRI' ELSE 'UNI' END)
+			FROM %[1]s_CONS_COLUMNS col JOIN %[1]s_CONSTRAINTS con ON con.CONSTRAINT_NAME = col.CONSTRAINT_NAME AND con.OWNER = col.OWNER
+			WHERE col.TABLE_NAME = c.TABLE_NAME AND col.COLUMN_NAME = c.COLUMN_NAME AND con.CONSTRAINT_TYPE IN ('P', 'U')) AS KEY_TYPE
+	FROM %[1]s_TAB_COLUMNS c
+	LEFT JOIN %[1]s_COL_COMMENTS cc ON cc.TABLE_NAME = c.TABLE_NAME AND cc.COLUMN_NAME = c.COLUMN_NAME
+	WHERE c.TABLE_NAME = ?%[2]s ORDER BY c.COLUMN_ID`, prefix, ownerCondition), args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf(`table "%s" does not exist`, table)
+	}
+	fields := make(map[string]*TableField, len(result))
+	for i, m := range result {
+		field := &TableField{
+			Index:   i,
+			Name:    strings.ToLower(m["FIELD"].String()),
+			Type:    strings.ToLower(m["TYPE"].String()),
+			Null:    m["NULLABLE"].String() == "Y",
+			Key:     m["KEY_TYPE"].String(),
+			Comment: m["COMMENTS"].String(),
+		}
+		if !m["DATA_DEFAULT"].IsNil() {
+			field.Default = strings.TrimSpace(m["DATA_DEFAULT"].String())
+		}
+		fields[field.Name] = field
 	}
-	return
+	return fields, nil
 }
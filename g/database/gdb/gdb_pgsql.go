@@ -10,6 +10,7 @@ import (
 	"database/sql"
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 // PostgreSQL的适配.
@@ -52,3 +53,96 @@ func (db *dbPgsql) handleSqlBeforeExec(query string) string {
 	})
 	return str
 }
+
+// 获取查询当前连接的后端进程ID的SQL，以及在服务端取消正在执行的语句的SQL(发送取消请求)
+func (db *dbPgsql) getCancelSql() (idSql string, cancelSql string) {
+	return "SELECT pg_backend_pid()", "SELECT pg_cancel_backend(%d)"
+}
+
+// 获取save操作(upsert)写入语句的冲突更新子句，使用ON CONFLICT DO UPDATE语法，必须指定冲突字段
+func (db *dbPgsql) getSaveClause(fields []string, conflict []string) (string, error) {
+	return getOnConflictClause(db, fields, conflict)
+}
+
+// 获取写入语句返回写入数据字段的子句，使用RETURNING语法
+func (db *dbPgsql) getReturningClause(fields []string) (output string, returning string) {
+	return "", "RETURNING " + quoteFields(db, fields, "")
+}
+
+// 获取JSON字段包含条件的SQL语句及参数，使用jsonb的@>操作符，JSON路径(例如：$.a.b)转换为#>操作符的路径数组
+func (db *dbPgsql) getJsonContainsSql(field string, path string, value string) (string, []interface{}) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return fmt.Sprintf(`%s::jsonb @> ?::jsonb`, field), []interface{}{value}
+	}
+	keys := strings.Split(path, ".")
+	return fmt.Sprintf(`%s::jsonb #> ?::text[] @> ?::jsonb`, field), []interface{}{"{" + strings.Join(keys, ",") + "}", value}
+}
+
+// 获取数据表名称列表，schema为空时表示当前schema
+func (db *dbPgsql) doTables(schema string) ([]string, error) {
+	result, err := db.GetAll(`SELECT tablename FROM pg_tables WHERE schemaname = COALESCE(NULLIF(?, ''), current_schema()) ORDER BY tablename`, schema)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, len(result))
+	for i, m := range result {
+		tables[i] = m["tablename"].String()
+	}
+	return tables, nil
+}
+
+// 获取数据表的字段结构信息，包括serial/identity自增字段及enum类型的可选值，
+// schema为空时表示当前schema，也可以使用"schema.table"格式的表名称。
+func (db *dbPgsql) doTableFields(table string, schema string) (map[string]*TableField, error) {
+	if schema == "" {
+		if array := strings.SplitN(table, ".", 2); len(array) == 2 {
+			schema, table = array[0], array[1]
+		}
+	}
+	result, err := db.GetAll(`
+	SELECT a.attnum AS index, a.attname AS name, format_type(a.atttypid, a.atttypmod) AS type,
+		NOT a.attnotnull AS nullable, pg_get_expr(d.adbin, d.adrelid) AS default_value,
+		a.attidentity AS identity, col_description(c.oid, a.attnum) AS comment,
+		(SELECT string_agg(e.enumlabel, E'\n' ORDER BY e.enumsortorder) FROM pg_enum e WHERE e.enumtypid = a.atttypid) AS enum_values,
+		COALESCE((SELECT CASE WHEN i.indisprimary THEN 'PRI' WHEN i.indisunique THEN 'UNI' ELSE 'MUL' END
+			FROM pg_index i WHERE i.indrelid = c.oid AND a.attnum = ANY(i.indkey)
+			ORDER BY i.indisprimary DESC, i.indisunique DESC LIMIT 1), '') AS key
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE c.relname = ? AND n.nspname = COALESCE(NULLIF(?, ''), current_schema()) AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY a.attnum`, table, schema)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]*TableField, len(result))
+	for i, m := range result {
+		field := &TableField{
+			Index:   i,
+			Name:    m["name"].String(),
+			Type:    m["type"].String(),
+			Null:    m["nullable"].Bool(),
+			Key:     m["key"].String(),
+			Comment: m["comment"].String(),
+		}
+		defaultValue := m["default_value"].String()
+		switch {
+		case m["identity"].String() == "a" || m["identity"].String() == "d":
+			field.Extra = "identity"
+		case strings.HasPrefix(defaultValue, "nextval("):
+			field.Extra = "serial"
+		case !m["default_value"].IsNil():
+			field.Default = defaultValue
+		}
+		if v := m["enum_values"].String(); v != "" {
+			field.Enum = strings.Split(v, "\n")
+		}
+		fields[field.Name] = field
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf(`table "%s" does not exist`, table)
+	}
+	return fields, nil
+}
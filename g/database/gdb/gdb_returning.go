@@ -0,0 +1,272 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/internal/empty"
+)
+
+const (
+	gTABLE_SINGLE_PRIMARY_KEY_CACHE_PREFIX = "table_single_primary_key_" // 数据表单一主键字段名称的缓存键名前缀
+)
+
+// 写入数据并返回写入数据指定字段的执行结果，参考Model.Returning
+type InsertResult struct {
+	fields       []string // 返回的字段名称
+	records      Result   // 写入数据的返回字段，按照写入顺序排列
+	rowsAffected int64    // 影响的行数
+}
+
+// see sql.Result.RowsAffected
+func (r *InsertResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// 返回最后一条写入数据的第一个返回字段的整型值(一般为自增主键值)
+func (r *InsertResult) LastInsertId() (int64, error) {
+	record := r.LastInsertedRecord()
+	if record == nil || len(r.fields) == 0 || record[r.fields[0]] == nil {
+		return 0, errors.New("no inserted record returned")
+	}
+	return record[r.fields[0]].Int64(), nil
+}
+
+// 返回最后一条写入数据的返回字段，没有写入数据时返回nil
+func (r *InsertResult) LastInsertedRecord() Record {
+	if len(r.records) == 0 {
+		return nil
+	}
+	return r.records[len(r.records)-1]
+}
+
+// 返回所有写入数据的返回字段，按照写入顺序排列
+func (r *InsertResult) InsertedRecords() Result {
+	return r.records
+}
+
+// 链式操作，Insert写入数据时返回写入数据的指定字段(一般为数据库生成的主键值及默认值字段)，
+// 执行结果为*InsertResult，可以通过LastInsertedRecord/InsertedRecords获取返回的字段，批量写入同样有效。
+// PostgreSQL/SQLite使用RETURNING语法，SQL Server使用OUTPUT语法，
+// 其他数据库(MySQL)通过写入数据中的主键值或者自增主键值(LastInsertId)查询返回的字段，要求数据表为单一主键，
+// 注意MySQL批量写入时按照同一语句写入的数据自增主键值连续计算，并发执行INSERT ... SELECT等批量写入时可能不准确。
+func (md *Model) Returning(fields ...string) *Model {
+	model := md.getModel()
+	model.returning = fields
+	return model
+}
+
+// 写入数据并返回写入数据的指定字段
+func (md *Model) insertReturning(list List, batch int) (sql.Result, error) {
+	if md.tx == nil {
+		return md.db.doInsertReturning(nil, md.tables, list, md.returning, batch)
+	}
+	return md.tx.db.doInsertReturning(md.tx.link(), md.tables, list, md.returning, batch)
+}
+
+// 链式操作，批量写入数据并按照写入顺序返回所有写入数据的主键值，数据表需要为单一主键，参考Returning
+func (md *Model) InsertAndGetIds() ([]Value, error) {
+	table := md.getWriteTable()
+	if table == "" || table[0] == '(' {
+		return nil, errors.New("InsertAndGetIds requires single table")
+	}
+	pk, err := md.db.getPrimaryKey(strings.Trim(table, "`\"[]"))
+	if err != nil {
+		return nil, err
+	}
+	result, err := md.Returning(pk).Insert()
+	if err != nil {
+		return nil, err
+	}
+	records := result.(*InsertResult).InsertedRecords()
+	ids := make([]Value, len(records))
+	for i, record := range records {
+		ids[i] = record[pk]
+	}
+	return ids, nil
+}
+
+// 获取写入语句返回写入数据字段的子句，output为VALUES之前的子句(SQL Server的OUTPUT)，
+// returning为语句末尾的子句(PostgreSQL/SQLite的RETURNING)，均为空时表示不支持，通过主键值查询返回的字段。
+func (bs *dbBase) getReturningClause(fields []string) (output string, returning string) {
+	return "", ""
+}
+
+// 使用数据库的关键字操作符包含字段名称，prefix为字段名称前缀(例如：INSERTED.)，返回以半角逗号连接的字段列表
+func quoteFields(db DB, fields []string, prefix string) string {
+	charL, charR := db.getChars()
+	array := make([]string, len(fields))
+	for i, f := range fields {
+		array[i] = prefix + charL + f + charR
+	}
+	return strings.Join(array, ",")
+}
+
+// 获取数据表的单一主键字段名称，数据表没有主键或者为联合主键时返回错误，结果使用字段结构缓存
+func (bs *dbBase) getPrimaryKey(table string) (field string, err error) {
+	v := bs.cache.GetOrSetFunc(gTABLE_SINGLE_PRIMARY_KEY_CACHE_PREFIX+table, func() interface{} {
+		tableFields := (map[string]*TableField)(nil)
+		bs.withoutDryRun(func() {
+			tableFields, err = bs.db.doTableFields(table, "")
+		})
+		if err != nil {
+			return nil
+		}
+		name := ""
+		for _, f := range tableFields {
+			if f.Key != "PRI" {
+				continue
+			}
+			if name != "" {
+				return ""
+			}
+			name = f.Name
+		}
+		return name
+	}, bs.getTableFieldsTTL()*1000)
+	if err != nil {
+		return "", err
+	}
+	if v != nil {
+		field = v.(string)
+	}
+	if field == "" {
+		return "", fmt.Errorf(`table "%s" should have a single primary key`, table)
+	}
+	return field, nil
+}
+
+// 按照批次量写入数据并返回写入数据的指定字段
+func (bs *dbBase) doInsertReturning(link dbLink, table string, list List, returning []string, batch int) (*InsertResult, error) {
+	if len(list) == 0 {
+		return nil, errors.New("empty data list")
+	}
+	if len(returning) == 0 {
+		return nil, errors.New("returning fields are required")
+	}
+	var err error
+	if link == nil {
+		if link, err = bs.db.Master(); err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]string, 0, len(list[0]))
+	for k := range list[0] {
+		keys = append(keys, k)
+	}
+	charL, charR := bs.db.getChars()
+	keyStr := charL + strings.Join(keys, charL+","+charR) + charR
+	valueHolderStr := "(" + strings.TrimRight(strings.Repeat("?,", len(keys)), ",") + ")"
+	output, returningStr := bs.db.getReturningClause(returning)
+	if batch <= 0 {
+		batch = gDEFAULT_BATCH_NUM
+	}
+	result := &InsertResult{fields: returning}
+	for start := 0; start < len(list); start += batch {
+		end := start + batch
+		if end > len(list) {
+			end = len(list)
+		}
+		values := make([]string, 0, end-start)
+		params := make([]interface{}, 0, (end-start)*len(keys))
+		for _, m := range list[start:end] {
+			for _, k := range keys {
+				params = append(params, convertParam(m[k]))
+			}
+			values = append(values, valueHolderStr)
+		}
+		query := fmt.Sprintf("INSERT INTO %s(%s)", table, keyStr)
+		if output != "" {
+			query += " " + output
+		}
+		query += " VALUES" + strings.Join(values, ",")
+		if returningStr != "" {
+			query += " " + returningStr
+		}
+		// 不支持返回字段语法的数据库通过主键值查询返回的字段
+		if output == "" && returningStr == "" {
+			r, err := bs.db.doExec(link, query, params...)
+			if err != nil {
+				return nil, err
+			}
+			n, err := r.RowsAffected()
+			if err != nil {
+				return nil, err
+			}
+			records, err := bs.getInsertedRecords(link, table, list[start:end], r, returning)
+			if err != nil {
+				return nil, err
+			}
+			result.records = append(result.records, records...)
+			result.rowsAffected += n
+			continue
+		}
+		records, err := bs.db.getAll(link, query, params...)
+		if err != nil {
+			return nil, err
+		}
+		result.records = append(result.records, records...)
+		result.rowsAffected += int64(len(records))
+	}
+	return result, nil
+}
+
+// 通过主键值查询写入数据的返回字段：写入数据中包含主键值时直接使用，否则使用自增主键值(LastInsertId)，
+// 同一语句写入的多条数据的自增主键值从LastInsertId开始连续递增。
+func (bs *dbBase) getInsertedRecords(link dbLink, table string, list List, r sql.Result, returning []string) (Result, error) {
+	pk, err := bs.db.getPrimaryKey(strings.Trim(table, "`\"[]"))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]interface{}, len(list))
+	lastInsertId := int64(-1)
+	for i, m := range list {
+		if v, ok := m[pk]; ok && !empty.IsEmpty(v) {
+			ids[i] = v
+			continue
+		}
+		if lastInsertId < 0 {
+			if lastInsertId, err = r.LastInsertId(); err != nil {
+				return nil, err
+			}
+		}
+		ids[i] = lastInsertId
+		lastInsertId++
+	}
+	records := make(Result, len(list))
+	if len(returning) == 1 && returning[0] == pk {
+		for i, id := range ids {
+			records[i] = Record{pk: gvar.New(id, true)}
+		}
+		return records, nil
+	}
+	charL, charR := bs.db.getChars()
+	fields := charL + pk + charR
+	for _, f := range returning {
+		if f != pk {
+			fields += "," + charL + f + charR
+		}
+	}
+	holders := strings.TrimRight(strings.Repeat("?,", len(ids)), ",")
+	all, err := bs.db.getAll(link, fmt.Sprintf("SELECT %s FROM %s WHERE %s%s%s IN(%s)", fields, table, charL, pk, charR, holders), ids...)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]Record, len(all))
+	for _, record := range all {
+		index[record[pk].String()] = record
+	}
+	for i, id := range ids {
+		records[i] = index[gvar.New(id, true).String()]
+	}
+	return records, nil
+}
@@ -0,0 +1,334 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gf/g/util/grand"
+)
+
+const (
+	SAGA_STATUS_RUNNING      = "running"      // 正在执行正向步骤
+	SAGA_STATUS_COMPENSATING = "compensating" // 正在执行补偿步骤
+	SAGA_STATUS_DONE         = "done"         // 所有正向步骤执行成功
+	SAGA_STATUS_COMPENSATED  = "compensated"  // 执行失败，所有补偿步骤执行成功
+
+	gDEFAULT_SAGA_TABLE = "saga_states" // 默认的Saga状态记录表名称
+)
+
+var (
+	// 保存Saga状态时状态已经被其他进程修改(例如同时执行Recover)，当前进程应当放弃该Saga
+	ErrSagaConflict = errors.New("saga state has been modified by another process")
+)
+
+// Saga步骤方法，id为Saga执行ID，data为Saga执行数据，
+// 方法中对data的修改会在步骤执行后持久化，因此可以用于向后续步骤及补偿步骤传递数据(例如创建的订单ID)。
+type SagaFunc = func(id string, data Map) error
+
+// Saga步骤
+type SagaStep struct {
+	Name       string   // 步骤名称
+	Action     SagaFunc // 正向操作
+	Compensate SagaFunc // 补偿操作，为nil表示该步骤不需要补偿
+}
+
+// Saga执行状态
+type SagaState struct {
+	Id         string    // Saga执行ID
+	Name       string    // Saga名称
+	Status     string    // 执行状态，参考SAGA_STATUS_*常量
+	Step       int       // 执行中为已执行成功的正向步骤数量，补偿中为剩余需要补偿的步骤数量
+	Data       Map       // Saga执行数据
+	Error      string    // 导致补偿的错误信息
+	Version    int       // 状态版本号，每次保存加1，用于防止多个进程同时处理同一个Saga
+	UpdateTime time.Time // 状态更新时间
+}
+
+// Saga状态存储接口，可以使用NewSagaStore创建基于数据表的存储，也可以自行实现(例如基于gredis)。
+type SagaStore interface {
+	// 保存状态，Version为0表示新的状态，否则仅当存储的版本号等于Version时保存(否则返回ErrSagaConflict)，保存成功后Version加1
+	SaveSagaState(state *SagaState) error
+	// 获取指定名称的未结束(执行中或者补偿中)的状态
+	GetUnfinishedSagaStates(name string) ([]*SagaState, error)
+}
+
+// Saga协调器，用于协调跨多个数据库分组/gredis/服务的分布式事务：
+// 按照顺序执行正向步骤，某个步骤失败时按照倒序执行已执行步骤(包括失败的步骤)的补偿操作，
+// 执行状态在每个步骤之后持久化，进程异常退出后可以通过Recover继续补偿未结束的Saga。
+// 注意补偿操作必须是幂等的，并且需要能够处理正向操作未生效的情况(例如正向操作失败或者进程在正向操作期间退出)。
+type Saga struct {
+	name  string
+	steps []SagaStep
+	store SagaStore
+}
+
+// 创建Saga协调器，name为Saga名称(用于Recover时区分不同的Saga)，store为状态存储
+func NewSaga(name string, store SagaStore) *Saga {
+	return &Saga{
+		name:  name,
+		store: store,
+	}
+}
+
+// 添加步骤，compensate为nil表示该步骤不需要补偿
+func (s *Saga) Step(name string, action SagaFunc, compensate SagaFunc) *Saga {
+	s.steps = append(s.steps, SagaStep{
+		Name:       name,
+		Action:     action,
+		Compensate: compensate,
+	})
+	return s
+}
+
+// 添加在数据库db的本地事务中执行的步骤，正向操作及补偿操作分别在独立的事务中执行，参考Step
+func (s *Saga) TxStep(name string, db DB, action func(tx *TX, id string, data Map) error, compensate func(tx *TX, id string, data Map) error) *Saga {
+	txFunc := func(f func(tx *TX, id string, data Map) error) SagaFunc {
+		if f == nil {
+			return nil
+		}
+		return func(id string, data Map) error {
+			return db.Transaction(func(tx *TX) error {
+				return f(tx, id, data)
+			})
+		}
+	}
+	return s.Step(name, txFunc(action), txFunc(compensate))
+}
+
+// 执行Saga，data为Saga执行数据(持久化时使用JSON编码)，返回Saga执行ID。
+// 所有步骤执行成功时返回nil，否则执行补偿操作并返回步骤的错误(补偿失败时同时包含补偿的错误，未完成的补偿可以通过Recover继续执行)。
+func (s *Saga) Execute(data Map) (id string, err error) {
+	if data == nil {
+		data = Map{}
+	}
+	state := &SagaState{
+		Id:     strconv.FormatInt(time.Now().UnixNano(), 36) + grand.Str(8),
+		Name:   s.name,
+		Status: SAGA_STATUS_RUNNING,
+		Data:   data,
+	}
+	if len(s.steps) == 0 {
+		state.Status = SAGA_STATUS_DONE
+	}
+	if err := s.save(state); err != nil {
+		return "", err
+	}
+	return state.Id, s.run(state)
+}
+
+// 继续处理未结束的Saga(一般在进程启动时调用)：执行中及补偿中的Saga都将执行补偿操作。
+// 只处理状态在expire时间内没有更新的Saga，避免处理其他进程正在执行的Saga，返回处理的Saga数量。
+// 多个进程同时执行Recover时，通过状态版本号保证每个Saga只被一个进程处理。
+func (s *Saga) Recover(expire time.Duration) (int, error) {
+	states, err := s.store.GetUnfinishedSagaStates(s.name)
+	if err != nil {
+		return 0, err
+	}
+	count, lastErr := 0, error(nil)
+	for _, state := range states {
+		if time.Since(state.UpdateTime) < expire {
+			continue
+		}
+		if state.Data == nil {
+			state.Data = Map{}
+		}
+		// 先保存状态获取Saga的处理权
+		if state.Status == SAGA_STATUS_RUNNING {
+			s.startCompensation(state, "saga is interrupted")
+		}
+		if err := s.save(state); err != nil {
+			if err != ErrSagaConflict {
+				lastErr = err
+			}
+			continue
+		}
+		count++
+		if err := s.compensate(state); err != nil {
+			lastErr = err
+		}
+	}
+	return count, lastErr
+}
+
+// 执行未执行的正向步骤，失败时执行补偿
+func (s *Saga) run(state *SagaState) error {
+	for state.Step < len(s.steps) {
+		step := s.steps[state.Step]
+		if err := callSagaFunc(step.Action, state.Id, state.Data); err != nil {
+			stepErr := fmt.Errorf(`saga "%s" step "%s" failed: %v`, s.name, step.Name, err)
+			s.startCompensation(state, stepErr.Error())
+			if err := s.save(state); err != nil {
+				return fmt.Errorf(`%v; save state failed: %v`, stepErr, err)
+			}
+			if err := s.compensate(state); err != nil {
+				return fmt.Errorf(`%v; %v`, stepErr, err)
+			}
+			return stepErr
+		}
+		state.Step++
+		if state.Step == len(s.steps) {
+			state.Status = SAGA_STATUS_DONE
+		}
+		if err := s.save(state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 将执行中的状态转换为补偿中，执行中的步骤(可能已经部分执行)也需要补偿
+func (s *Saga) startCompensation(state *SagaState, reason string) {
+	state.Status = SAGA_STATUS_COMPENSATING
+	state.Error = reason
+	if state.Step < len(s.steps) {
+		state.Step++
+	}
+}
+
+// 按照倒序执行剩余的补偿步骤
+func (s *Saga) compensate(state *SagaState) error {
+	if state.Step == 0 {
+		state.Status = SAGA_STATUS_COMPENSATED
+		return s.save(state)
+	}
+	for state.Step > 0 {
+		if state.Step > len(s.steps) {
+			return fmt.Errorf(`saga "%s" has %d steps but state "%s" needs %d compensations`, s.name, len(s.steps), state.Id, state.Step)
+		}
+		step := s.steps[state.Step-1]
+		if err := callSagaFunc(step.Compensate, state.Id, state.Data); err != nil {
+			return fmt.Errorf(`saga "%s" step "%s" compensation failed: %v`, s.name, step.Name, err)
+		}
+		state.Step--
+		if state.Step == 0 {
+			state.Status = SAGA_STATUS_COMPENSATED
+		}
+		if err := s.save(state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 保存状态
+func (s *Saga) save(state *SagaState) error {
+	state.UpdateTime = time.Now()
+	return s.store.SaveSagaState(state)
+}
+
+// 执行步骤方法，将panic转换为错误，避免进程退出导致补偿延迟
+func callSagaFunc(f SagaFunc, id string, data Map) (err error) {
+	if f == nil {
+		return nil
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("panic: %v", e)
+		}
+	}()
+	return f(id, data)
+}
+
+// 基于数据表的Saga状态存储
+type sagaDbStore struct {
+	db    DB
+	table string
+}
+
+// 创建基于数据表的Saga状态存储，table默认为saga_states，数据表不存在时自动创建。
+func NewSagaStore(db DB, table ...string) (SagaStore, error) {
+	store := &sagaDbStore{
+		db:    db,
+		table: gDEFAULT_SAGA_TABLE,
+	}
+	if len(table) > 0 && table[0] != "" {
+		store.table = table[0]
+	}
+	if !migrationTableExists(db, store.table) {
+		charL, charR := db.getChars()
+		_, err := db.Exec(fmt.Sprintf(
+			`CREATE TABLE %s%s%s(id VARCHAR(64) NOT NULL PRIMARY KEY, name VARCHAR(255), status VARCHAR(32), `+
+				`step INT, data TEXT, error TEXT, version INT, update_time BIGINT)`,
+			charL, store.table, charR,
+		))
+		// 可能被其他进程同时创建
+		if err != nil && !migrationTableExists(db, store.table) {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// 保存状态
+func (store *sagaDbStore) SaveSagaState(state *SagaState) error {
+	data, err := json.Marshal(state.Data)
+	if err != nil {
+		return err
+	}
+	charL, charR := store.db.getChars()
+	table := charL + store.table + charR
+	updateTime := state.UpdateTime.UnixNano() / int64(time.Millisecond)
+	if state.Version == 0 {
+		_, err = store.db.Exec(
+			fmt.Sprintf(`INSERT INTO %s(id,name,status,step,data,error,version,update_time) VALUES(?,?,?,?,?,?,?,?)`, table),
+			state.Id, state.Name, state.Status, state.Step, string(data), state.Error, 1, updateTime,
+		)
+	} else {
+		result, e := store.db.Exec(
+			fmt.Sprintf(`UPDATE %s SET status=?,step=?,data=?,error=?,version=?,update_time=? WHERE id=? AND version=?`, table),
+			state.Status, state.Step, string(data), state.Error, state.Version+1, updateTime, state.Id, state.Version,
+		)
+		if err = e; err == nil {
+			if n, _ := result.RowsAffected(); n == 0 {
+				return ErrSagaConflict
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	state.Version++
+	return nil
+}
+
+// 获取未结束的状态
+func (store *sagaDbStore) GetUnfinishedSagaStates(name string) ([]*SagaState, error) {
+	charL, charR := store.db.getChars()
+	result, err := store.db.GetAll(
+		fmt.Sprintf(`SELECT * FROM %s%s%s WHERE name=? AND status IN(?,?) ORDER BY update_time`, charL, store.table, charR),
+		name, SAGA_STATUS_RUNNING, SAGA_STATUS_COMPENSATING,
+	)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*SagaState, len(result))
+	for i, record := range result {
+		state := &SagaState{
+			Id:         record["id"].String(),
+			Name:       record["name"].String(),
+			Status:     record["status"].String(),
+			Step:       record["step"].Int(),
+			Error:      record["error"].String(),
+			Version:    record["version"].Int(),
+			UpdateTime: time.Unix(0, record["update_time"].Int64()*int64(time.Millisecond)),
+		}
+		// 使用json.Number保持数值的精度
+		decoder := json.NewDecoder(bytes.NewReader(record["data"].Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&state.Data); err != nil {
+			return nil, fmt.Errorf(`invalid data of saga state "%s": %v`, state.Id, err)
+		}
+		states[i] = state
+	}
+	return states, nil
+}
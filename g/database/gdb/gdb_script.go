@@ -0,0 +1,285 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	gSQL_SCRIPT_DELIMITER = ";" // SQL脚本默认的语句分隔符
+)
+
+var (
+	// 存储过程/函数/触发器等语句的开头，这些语句的BEGIN...END语句块中的分号不作为语句分隔符
+	sqlScriptRoutineRegex = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?(?:DEFINER\s*=\s*\S+\s+)?(?:TEMP\s+|TEMPORARY\s+)?(?:PROCEDURE|FUNCTION|TRIGGER)\b`)
+)
+
+// 单个数据库连接的链接对象，用于在同一个连接中执行SQL脚本的多条语句(保持会话变量、临时表等连接状态)
+type connLink struct {
+	*sql.Conn
+}
+
+func (l *connLink) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return l.QueryContext(context.Background(), query, args...)
+}
+
+func (l *connLink) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return l.ExecContext(context.Background(), query, args...)
+}
+
+func (l *connLink) Prepare(query string) (*sql.Stmt, error) {
+	return l.PrepareContext(context.Background(), query)
+}
+
+// 在master节点的同一个连接中按照顺序执行多条语句的SQL脚本，返回成功执行的语句数量，
+// 任意一条语句执行失败时停止执行并返回错误(已执行的语句不会回滚，需要回滚时请在事务中执行)。
+// 语句的拆分规则参考SplitSqlScript。
+func (bs *dbBase) ExecScript(script string) (int, error) {
+	master, err := bs.db.Master()
+	if err != nil {
+		return 0, err
+	}
+	conn, err := master.Conn(bs.GetCtx())
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return bs.db.doExecScript(&connLink{conn}, script)
+}
+
+// 在链接对象上按照顺序执行SQL脚本的多条语句
+func (bs *dbBase) doExecScript(link dbLink, script string) (int, error) {
+	statements, err := SplitSqlScript(script)
+	if err != nil {
+		return 0, err
+	}
+	for i, statement := range statements {
+		if _, err := bs.db.doExec(link, statement); err != nil {
+			return i, fmt.Errorf("executing statement %d of script failed: %v", i+1, err)
+		}
+	}
+	return len(statements), nil
+}
+
+// (事务)在事务中按照顺序执行多条语句的SQL脚本，返回成功执行的语句数量
+func (tx *TX) ExecScript(script string) (int, error) {
+	return tx.db.doExecScript(tx.link(), script)
+}
+
+// 将SQL脚本拆分为多条语句，拆分时：
+// 1. 忽略引号(', ", `)及PostgreSQL美元符号引用($$...$$, $tag$...$tag$)中的分隔符;
+// 2. 去掉注释(--、行首的#、/* */)，保留MySQL的条件注释(/*! */)及优化器提示(/*+ */);
+// 3. 支持DELIMITER命令修改语句分隔符(DELIMITER命令本身不作为语句)，例如：DELIMITER $$;
+// 4. 使用默认分隔符时，存储过程/函数/触发器的BEGIN...END语句块中的分号不作为语句分隔符。
+func SplitSqlScript(script string) ([]string, error) {
+	var (
+		statements = make([]string, 0)
+		buffer     = strings.Builder{}
+		delimiter  = gSQL_SCRIPT_DELIMITER
+		depth      = 0 // 存储过程等语句中BEGIN...END语句块的嵌套层级
+		length     = len(script)
+	)
+	flush := func() {
+		if s := strings.TrimSpace(buffer.String()); s != "" {
+			statements = append(statements, s)
+		}
+		buffer.Reset()
+		depth = 0
+	}
+	for i := 0; i < length; {
+		// DELIMITER命令必须在语句开头
+		if strings.TrimSpace(buffer.String()) == "" && hasSqlKeywordAt(script, i, "DELIMITER") {
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = length
+			} else {
+				end += i
+			}
+			fields := strings.Fields(script[i+len("DELIMITER") : end])
+			if len(fields) == 0 {
+				return nil, errors.New("missing delimiter for DELIMITER command")
+			}
+			delimiter = fields[0]
+			buffer.Reset()
+			i = end
+			continue
+		}
+		if depth == 0 && strings.HasPrefix(script[i:], delimiter) {
+			flush()
+			i += len(delimiter)
+			continue
+		}
+		c := script[i]
+		switch {
+		// 单行注释
+		case c == '-' && i+1 < length && script[i+1] == '-',
+			c == '#' && isSqlLineStart(script, i):
+			if end := strings.IndexByte(script[i:], '\n'); end < 0 {
+				i = length
+			} else {
+				i += end
+			}
+
+		// 多行注释
+		case c == '/' && i+1 < length && script[i+1] == '*':
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("unterminated comment in sql script")
+			}
+			end += i + 4
+			if i+2 < length && (script[i+2] == '!' || script[i+2] == '+') {
+				buffer.WriteString(script[i:end])
+			} else {
+				buffer.WriteByte(' ')
+			}
+			i = end
+
+		// 引号
+		case c == '\'' || c == '"' || c == '`':
+			end := sqlQuoteEnd(script, i)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted string in sql script: %s", sqlScriptSnippet(script, i))
+			}
+			buffer.WriteString(script[i:end])
+			i = end
+
+		// 美元符号引用
+		case c == '$':
+			if tag := sqlDollarTag(script, i); tag != "" {
+				end := strings.Index(script[i+len(tag):], tag)
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated dollar-quoted string in sql script: %s", sqlScriptSnippet(script, i))
+				}
+				end += i + 2*len(tag)
+				buffer.WriteString(script[i:end])
+				i = end
+			} else {
+				buffer.WriteByte(c)
+				i++
+			}
+
+		// 关键字
+		case isSqlWordByte(c) && (i == 0 || !isSqlWordByte(script[i-1])):
+			end := i
+			for end < length && isSqlWordByte(script[end]) {
+				end++
+			}
+			if delimiter == gSQL_SCRIPT_DELIMITER {
+				switch strings.ToUpper(script[i:end]) {
+				case "BEGIN", "CASE":
+					if sqlScriptRoutineRegex.MatchString(buffer.String()) {
+						depth++
+					}
+				case "END":
+					// END IF/END LOOP等结束的语句块没有计入层级
+					next := strings.Fields(strings.ToUpper(script[end:minInt(end+16, length)]))
+					if depth > 0 && (len(next) == 0 || !isSqlEndBlockKeyword(strings.TrimRight(next[0], ";"))) {
+						depth--
+					}
+				}
+			}
+			buffer.WriteString(script[i:end])
+			i = end
+
+		default:
+			buffer.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return statements, nil
+}
+
+// 判断指定位置是否为关键字(不区分大小写，后面为空白字符)
+func hasSqlKeywordAt(script string, i int, keyword string) bool {
+	end := i + len(keyword)
+	if end >= len(script) || !strings.EqualFold(script[i:end], keyword) {
+		return false
+	}
+	return script[end] == ' ' || script[end] == '\t'
+}
+
+// 判断指定位置之前是否只有当前行的空白字符
+func isSqlLineStart(script string, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		switch script[j] {
+		case '\n':
+			return true
+		case ' ', '\t', '\r':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// 获取引号字符串的结束位置(不包含)，支持反斜杠转义及重复引号转义，没有结束引号时返回-1
+func sqlQuoteEnd(script string, i int) int {
+	quote := script[i]
+	for j := i + 1; j < len(script); j++ {
+		switch script[j] {
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case quote:
+			if j+1 < len(script) && script[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return -1
+}
+
+// 获取指定位置的美元符号引用标签，例如：$$、$body$，不是美元符号引用时返回空
+func sqlDollarTag(script string, i int) string {
+	for j := i + 1; j < len(script); j++ {
+		c := script[j]
+		if c == '$' {
+			return script[i : j+1]
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > i+1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// 判断是否为标识符字符
+func isSqlWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// 判断END之后的关键字是否为没有计入层级的语句块
+func isSqlEndBlockKeyword(word string) bool {
+	switch word {
+	case "IF", "LOOP", "WHILE", "REPEAT", "FOR":
+		return true
+	}
+	return false
+}
+
+// 返回指定位置开始的脚本片段，用于错误信息
+func sqlScriptSnippet(script string, i int) string {
+	return script[i:minInt(i+32, len(script))]
+}
+
+// 返回较小的整数
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
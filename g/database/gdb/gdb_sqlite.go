@@ -9,6 +9,8 @@ package gdb
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 )
 
 // 使用时需要import:
@@ -41,9 +43,115 @@ func (db *dbSqlite) getChars() (charLeft string, charRight string) {
 	return "`", "`"
 }
 
+// 获得查询语句的执行计划SQL
+func (db *dbSqlite) getExplainSql(query string) string {
+	return "EXPLAIN QUERY PLAN " + query
+}
+
+// SQLite驱动在上下文取消时中断语句执行(sqlite3_interrupt)，不需要服务端取消
+func (db *dbSqlite) getCancelSql() (idSql string, cancelSql string) {
+	return "", ""
+}
+
 // 在执行sql之前对sql进行进一步处理
-// @todo 需要增加对Save方法的支持，可使用正则来实现替换，
-// @todo 将ON DUPLICATE KEY UPDATE触发器修改为两条SQL语句(INSERT OR IGNORE & UPDATE)
 func (db *dbSqlite) handleSqlBeforeExec(query string) string {
 	return query
 }
+
+// 获取save操作(upsert)写入语句的冲突更新子句，使用ON CONFLICT DO UPDATE语法(SQLite 3.24+)，必须指定冲突字段
+func (db *dbSqlite) getSaveClause(fields []string, conflict []string) (string, error) {
+	return getOnConflictClause(db, fields, conflict)
+}
+
+// 获取写入语句返回写入数据字段的子句，使用RETURNING语法(SQLite 3.35.0及以上版本)
+func (db *dbSqlite) getReturningClause(fields []string) (output string, returning string) {
+	return "", "RETURNING " + quoteFields(db, fields, "")
+}
+
+// 获取数据表名称列表，schema为附加数据库名称，为空时表示主数据库
+func (db *dbSqlite) doTables(schema string) ([]string, error) {
+	result, err := db.GetAll(fmt.Sprintf(
+		`SELECT name FROM %ssqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%%' ORDER BY name`,
+		sqliteSchemaPrefix(schema),
+	))
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, len(result))
+	for i, m := range result {
+		tables[i] = m["name"].String()
+	}
+	return tables, nil
+}
+
+// 获取数据表的字段结构信息，索引信息通过PRAGMA index_list/index_info获取，
+// INTEGER类型的单一主键为rowid的别名，写入时自动生成，因此标记为auto_increment。
+func (db *dbSqlite) doTableFields(table string, schema string) (map[string]*TableField, error) {
+	prefix := sqliteSchemaPrefix(schema)
+	result, err := db.GetAll(fmt.Sprintf("PRAGMA %stable_info(`%s`)", prefix, table))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf(`table "%s" does not exist`, table)
+	}
+	fields := make(map[string]*TableField, len(result))
+	primaryCount := 0
+	for i, m := range result {
+		field := &TableField{
+			Index: i,
+			Name:  m["name"].String(),
+			Type:  m["type"].String(),
+			Null:  m["notnull"].Int() == 0 && m["pk"].Int() == 0,
+		}
+		if !m["dflt_value"].IsNil() {
+			field.Default = m["dflt_value"].String()
+		}
+		if m["pk"].Int() > 0 {
+			field.Key = "PRI"
+			primaryCount++
+		}
+		fields[field.Name] = field
+	}
+	for _, field := range fields {
+		if field.Key == "PRI" && primaryCount == 1 && strings.EqualFold(field.Type, "INTEGER") {
+			field.Extra = "auto_increment"
+		}
+	}
+	// 唯一索引及普通索引字段
+	indexes, err := db.GetAll(fmt.Sprintf("PRAGMA %sindex_list(`%s`)", prefix, table))
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		if index["origin"].String() == "pk" {
+			continue
+		}
+		key := "MUL"
+		if index["unique"].Int() == 1 {
+			key = "UNI"
+		}
+		columns, err := db.GetAll(fmt.Sprintf("PRAGMA %sindex_info(`%s`)", prefix, index["name"].String()))
+		if err != nil {
+			return nil, err
+		}
+		// 联合唯一索引中的字段单独并不唯一
+		if len(columns) > 1 {
+			key = "MUL"
+		}
+		for _, column := range columns {
+			if field, ok := fields[column["name"].String()]; ok && (field.Key == "" || (field.Key == "MUL" && key == "UNI")) {
+				field.Key = key
+			}
+		}
+	}
+	return fields, nil
+}
+
+// 返回附加数据库名称作为前缀，为空时表示主数据库
+func sqliteSchemaPrefix(schema string) string {
+	if schema == "" {
+		return ""
+	}
+	return "`" + schema + "`."
+}
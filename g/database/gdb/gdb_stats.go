@@ -0,0 +1,100 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gf/g/text/gregex"
+)
+
+// 数据库节点的连接池统计信息，用于监控连接池的使用情况，例如在连接池耗尽导致请求阻塞之前发现问题
+type PoolStats struct {
+	Group             string        `json:"group"`             // 配置分组名称
+	Node              string        `json:"node"`              // 节点地址(不包含账号密码)
+	Role              string        `json:"role"`              // 节点角色：master/slave
+	Dead              bool          `json:"dead"`              // 节点是否被健康检查标记为不可用
+	MaxOpen           int           `json:"maxOpen"`           // 连接池最大连接数，0表示不限制
+	Open              int           `json:"open"`              // 当前连接数(使用中+空闲)
+	InUse             int           `json:"inUse"`             // 使用中的连接数
+	Idle              int           `json:"idle"`              // 空闲的连接数
+	WaitCount         int64         `json:"waitCount"`         // 等待获取连接的总次数
+	WaitDuration      time.Duration `json:"waitDuration"`      // 等待获取连接的总时长
+	MaxIdleClosed     int64         `json:"maxIdleClosed"`     // 由于超过最大空闲连接数关闭的连接数
+	MaxLifetimeClosed int64         `json:"maxLifetimeClosed"` // 由于超过连接最大存活时间关闭的连接数
+}
+
+// 获取当前配置分组中已创建连接池的节点统计信息，节点顺序与配置顺序一致，没有执行过操作的节点不会创建连接池，因此不会返回
+func (bs *dbBase) Stats() []PoolStats {
+	array := make([]PoolStats, 0)
+	for _, node := range GetConfig(bs.group) {
+		health := bs.getNodeHealth(&node)
+		// 与openSqlDb保持一致的连接池缓存键名
+		if node.Charset == "" {
+			node.Charset = "utf8"
+		}
+		v := bs.cache.Get(node.String())
+		if v == nil {
+			continue
+		}
+		role := node.Role
+		if role != "slave" {
+			role = "master"
+		}
+		array = append(array, newPoolStats(bs.group, &node, role, v.(*sql.DB).Stats(), health.dead.Val()))
+	}
+	return array
+}
+
+// 获取所有通过Instance创建的数据库单例对象的连接池统计信息，键名为配置分组名称
+func Stats() map[string][]PoolStats {
+	m := make(map[string][]PoolStats)
+	instances.Iterator(func(group string, v interface{}) bool {
+		if db, ok := v.(DB); ok && db != nil {
+			m[group] = db.Stats()
+		}
+		return true
+	})
+	return m
+}
+
+// 根据sql.DBStats创建节点连接池统计信息
+func newPoolStats(group string, node *ConfigNode, role string, s sql.DBStats, dead bool) PoolStats {
+	return PoolStats{
+		Group:             group,
+		Node:              getNodeAddress(node),
+		Role:              role,
+		Dead:              dead,
+		MaxOpen:           s.MaxOpenConnections,
+		Open:              s.OpenConnections,
+		InUse:             s.InUse,
+		Idle:              s.Idle,
+		WaitCount:         s.WaitCount,
+		WaitDuration:      s.WaitDuration,
+		MaxIdleClosed:     s.MaxIdleClosed,
+		MaxLifetimeClosed: s.MaxLifetimeClosed,
+	}
+}
+
+// 获取用于展示的节点地址，自定义LinkInfo中的账号密码使用"***"替换
+func getNodeAddress(node *ConfigNode) string {
+	if node.LinkInfo != "" {
+		// 例如：user:pass@tcp(127.0.0.1:3306)/test、tcp://127.0.0.1:9000?username=default&password=123456
+		link, _ := gregex.ReplaceString(`[^/:@]+(:[^/@]*)?@`, "***@", node.LinkInfo)
+		link, _ = gregex.ReplaceString(`(?i)(password|pwd|pass)=[^&;\s]*`, "$1=***", link)
+		return link
+	}
+	if node.Host == "" {
+		return node.Name
+	}
+	if node.Port == "" {
+		return fmt.Sprintf("%s/%s", node.Host, node.Name)
+	}
+	return fmt.Sprintf("%s:%s/%s", node.Host, node.Port, node.Name)
+}
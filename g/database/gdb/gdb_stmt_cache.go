@@ -0,0 +1,199 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"container/list"
+	"database/sql"
+	"sync"
+)
+
+// 预处理语句缓存，按照连接池及SQL语句缓存，超过数量限制时按照LRU淘汰并关闭预处理语句。
+// 注意sql.Stmt会在连接池的每个连接上自动进行预处理，因此同一条SQL语句在每个连接上只会预处理一次。
+type stmtCache struct {
+	mu    sync.Mutex
+	size  int                            // 最大缓存数量，<= 0 表示不使用缓存
+	list  *list.List                     // LRU列表，表头为最近使用的预处理语句
+	items map[stmtCacheKey]*list.Element // 缓存项，值为*stmtCacheItem
+}
+
+// 预处理语句缓存键名
+type stmtCacheKey struct {
+	db    *sql.DB
+	query string
+}
+
+// 预处理语句缓存项
+type stmtCacheItem struct {
+	key     stmtCacheKey
+	stmt    *sql.Stmt
+	refs    int  // 正在使用的数量
+	evicted bool // 是否已经被淘汰，淘汰后在不再使用时关闭
+}
+
+// 事务链接对象，用于获取事务中的缓存预处理语句
+type txLink struct {
+	*sql.Tx
+	master *sql.DB // 开启事务的连接池
+}
+
+// 创建预处理语句缓存
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		size:  size,
+		list:  list.New(),
+		items: make(map[stmtCacheKey]*list.Element),
+	}
+}
+
+// 设置每个数据库分组缓存的预处理语句数量，n <= 0 表示不缓存(默认)，默认值可以通过节点配置StmtCacheSize设置，
+// 缓存用于带有预处理参数的查询/执行操作，避免每次操作都重新进行预处理。
+// 注意缓存的预处理语句会占用数据库服务端的资源(例如MySQL的max_prepared_stmt_count限制)，
+// 并且不兼容语句级别的连接代理(例如事务模式的PgBouncer)，因此需要显式开启。
+func (bs *dbBase) SetStmtCacheSize(n int) {
+	bs.stmts.setSize(n)
+}
+
+// 设置最大缓存数量，并淘汰超出数量的预处理语句
+func (c *stmtCache) setSize(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size = n
+	c.evictWithoutLock()
+}
+
+// 获取缓存的预处理语句，不存在时进行预处理并缓存，使用完成后需要调用release
+func (c *stmtCache) get(db *sql.DB, query string) (*stmtCacheItem, error) {
+	key := stmtCacheKey{db, query}
+	c.mu.Lock()
+	if e, ok := c.items[key]; ok {
+		c.list.MoveToFront(e)
+		item := e.Value.(*stmtCacheItem)
+		item.refs++
+		c.mu.Unlock()
+		return item, nil
+	}
+	c.mu.Unlock()
+	// 预处理时不加锁，并发预处理同一条语句时只保留一个
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		stmt.Close()
+		c.list.MoveToFront(e)
+		item := e.Value.(*stmtCacheItem)
+		item.refs++
+		return item, nil
+	}
+	item := &stmtCacheItem{key: key, stmt: stmt, refs: 1}
+	c.items[key] = c.list.PushFront(item)
+	c.evictWithoutLock()
+	return item, nil
+}
+
+// 使用完成预处理语句，已淘汰的预处理语句在不再使用时关闭
+func (c *stmtCache) release(item *stmtCacheItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item.refs--
+	if item.evicted && item.refs == 0 {
+		item.stmt.Close()
+	}
+}
+
+// 淘汰超出数量的最久未使用的预处理语句
+func (c *stmtCache) evictWithoutLock() {
+	for c.list.Len() > 0 && c.list.Len() > c.size {
+		item := c.list.Remove(c.list.Back()).(*stmtCacheItem)
+		delete(c.items, item.key)
+		item.evicted = true
+		if item.refs == 0 {
+			item.stmt.Close()
+		}
+	}
+}
+
+// 获取链接对象上SQL语句的缓存预处理语句，不使用缓存时返回nil
+func (bs *dbBase) getStmt(link dbLink, query string, args []interface{}) (stmt *sql.Stmt, release func(), err error) {
+	if len(args) == 0 {
+		return nil, nil, nil
+	}
+	var (
+		db *sql.DB
+		tx *sql.Tx
+	)
+	switch l := link.(type) {
+	case *sql.DB:
+		db = l
+	case *txLink:
+		db, tx = l.master, l.Tx
+	default:
+		return nil, nil, nil
+	}
+	bs.stmts.mu.Lock()
+	size := bs.stmts.size
+	bs.stmts.mu.Unlock()
+	if size <= 0 {
+		return nil, nil, nil
+	}
+	item, err := bs.stmts.get(db, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	release = func() {
+		bs.stmts.release(item)
+	}
+	// 事务中的预处理语句在事务提交或者回滚时自动关闭
+	if tx != nil {
+		return tx.Stmt(item.stmt), release, nil
+	}
+	return item.stmt, release, nil
+}
+
+// 在链接对象上执行查询，带有预处理参数时使用缓存的预处理语句，查询使用链接对象的上下文
+func (bs *dbBase) linkQuery(link dbLink, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	ctx, link := bs.linkCtx(link)
+	err = bs.doCancelable(ctx, link, func(link dbLink) (bool, error) {
+		stmt, release, err := bs.getStmt(link, query, args)
+		if err != nil {
+			return false, err
+		}
+		if stmt == nil {
+			rows, err = link.QueryContext(ctx, query, args...)
+		} else {
+			defer release()
+			rows, err = stmt.QueryContext(ctx, args...)
+		}
+		return rows != nil, err
+	})
+	return
+}
+
+// 在链接对象上执行操作，带有预处理参数时使用缓存的预处理语句，操作受默认超时时间限制
+func (bs *dbBase) linkExec(link dbLink, query string, args ...interface{}) (sql.Result, error) {
+	ctx, link := bs.linkCtx(link)
+	ctx, cancel := bs.timeoutCtx(ctx)
+	defer cancel()
+	var result sql.Result
+	err := bs.doCancelable(ctx, link, func(link dbLink) (bool, error) {
+		stmt, release, err := bs.getStmt(link, query, args)
+		if err != nil {
+			return false, err
+		}
+		if stmt == nil {
+			result, err = link.ExecContext(ctx, query, args...)
+		} else {
+			defer release()
+			result, err = stmt.ExecContext(ctx, args...)
+		}
+		return false, err
+	})
+	return result, err
+}
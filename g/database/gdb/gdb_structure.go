@@ -8,12 +8,31 @@ package gdb
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/gf/g/text/gregex"
 	"github.com/gf/g/util/gconv"
 )
 
+const (
+	// 数据表字段结构缓存键名前缀
+	gTABLE_FIELDS_CACHE_PREFIX = "table_fields_"
+)
+
+// 数据表字段的结构信息，各个数据库类型使用统一的格式
+type TableField struct {
+	Index   int         // 字段顺序，从0开始
+	Name    string      // 字段名称
+	Type    string      // 字段类型，例如：int(10) unsigned、character varying(45)、INTEGER
+	Null    bool        // 是否允许NULL
+	Key     string      // 索引类型：PRI(主键)、UNI(唯一索引)、MUL(普通索引)，非索引字段为空
+	Default interface{} // 默认值，没有默认值时为nil
+	Extra   string      // 附加信息：auto_increment(MySQL/SQLite自增)、serial/identity(PostgreSQL自增)
+	Comment string      // 字段注释
+	Enum    []string    // 枚举类型的可选值(MySQL的enum/set类型，PostgreSQL的enum类型)，其他类型为nil
+}
+
 /*
 // 同步数据库表结构到内存中
 func (bs *dbBase) syncTableStructure() {
@@ -82,26 +101,174 @@ func (bs *dbBase) filterFields(table string, data map[string]interface{}) map[st
 
 // 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值暂无用途(默认为字段数据类型).
 func (bs *dbBase) getTableFields(table string) (fields map[string]string, err error) {
-	// 缓存不存在时会查询数据表结构，缓存后不过期，直至程序重启(重新部署)
-	v := bs.cache.GetOrSetFunc("table_fields_"+table, func() interface{} {
-		result := (Result)(nil)
-		charL, charR := bs.db.getChars()
-		result, err = bs.GetAll(fmt.Sprintf(`SHOW COLUMNS FROM %s%s%s`, charL, table, charR))
+	return bs.getTableFieldsWithCache(table, func() (map[string]string, error) {
+		tableFields, err := bs.db.doTableFields(table, "")
 		if err != nil {
-			return nil
+			return nil, err
+		}
+		fields := make(map[string]string, len(tableFields))
+		for name, field := range tableFields {
+			fields[name] = field.Type
+		}
+		return fields, nil
+	})
+}
+
+// 获取数据库中所有的数据表名称，按照名称排序，schema为空时表示当前数据库(PostgreSQL为当前schema)，
+// 可用于模型生成等工具，与TableFields配合获取各个数据库类型统一的表结构信息。
+func (bs *dbBase) Tables(schema ...string) ([]string, error) {
+	name := ""
+	if len(schema) > 0 {
+		name = schema[0]
+	}
+	return bs.db.doTables(name)
+}
+
+// 获取指定数据表的字段结构信息，键名为字段名称，字段顺序参考TableField.Index，不使用字段结构缓存。
+// schema为空时表示当前数据库(PostgreSQL为当前schema，也可以使用"schema.table"格式的表名称)。
+func (bs *dbBase) TableFields(table string, schema ...string) (map[string]*TableField, error) {
+	name := ""
+	if len(schema) > 0 {
+		name = schema[0]
+	}
+	return bs.db.doTableFields(table, name)
+}
+
+// 获取数据表名称列表(MySQL)
+func (bs *dbBase) doTables(schema string) ([]string, error) {
+	charL, charR := bs.db.getChars()
+	query := "SHOW TABLES"
+	if schema != "" {
+		query += fmt.Sprintf(" FROM %s%s%s", charL, schema, charR)
+	}
+	result, err := bs.GetAll(query)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, 0, len(result))
+	for _, m := range result {
+		for _, v := range m {
+			tables = append(tables, v.String())
+			break
+		}
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// 获取数据表的字段结构信息(MySQL)
+func (bs *dbBase) doTableFields(table string, schema string) (map[string]*TableField, error) {
+	charL, charR := bs.db.getChars()
+	name := charL + table + charR
+	if schema != "" {
+		name = charL + schema + charR + "." + name
+	}
+	result, err := bs.GetAll(fmt.Sprintf(`SHOW FULL COLUMNS FROM %s`, name))
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]*TableField, len(result))
+	for i, m := range result {
+		field := &TableField{
+			Index:   i,
+			Name:    m["Field"].String(),
+			Type:    m["Type"].String(),
+			Null:    strings.EqualFold(m["Null"].String(), "YES"),
+			Key:     m["Key"].String(),
+			Extra:   m["Extra"].String(),
+			Comment: m["Comment"].String(),
+			Enum:    parseEnumValues(m["Type"].String()),
 		}
-		fields = make(map[string]string)
-		for _, m := range result {
-			fields[m["Field"].String()] = m["Type"].String()
+		if v := m["Default"]; v != nil {
+			field.Default = v.Val()
+		}
+		fields[field.Name] = field
+	}
+	return fields, nil
+}
+
+// 解析枚举类型的可选值，例如：enum('a','b')、set('a','b')、Enum8('a' = 1, 'b' = 2)(ClickHouse)，其他类型返回nil
+func parseEnumValues(fieldType string) []string {
+	match, _ := gregex.MatchString(`(?i)^(enum8|enum16|enum|set)\((.*)\)$`, strings.TrimSpace(fieldType))
+	if len(match) < 3 {
+		return nil
+	}
+	values := make([]string, 0)
+	items, _ := gregex.MatchAllString(`'((?:[^']|'')*)'`, match[2])
+	for _, item := range items {
+		values = append(values, strings.Replace(item[1], "''", "'", -1))
+	}
+	return values
+}
+
+// 获得指定表的字段结构缓存，缓存不存在时通过<f>查询数据表结构并写入缓存。
+// 缓存时间由SetTableFieldsTTL或者节点配置TableFieldsTTL决定，默认不过期，
+// 数据表结构变更(如迁移)后可以通过ClearTableFields手动刷新缓存。
+func (bs *dbBase) getTableFieldsWithCache(table string, f func() (map[string]string, error)) (fields map[string]string, err error) {
+	v := bs.cache.GetOrSetFunc(gTABLE_FIELDS_CACHE_PREFIX+table, func() interface{} {
+		// 空跑模式下同样需要查询实际的数据表结构
+		bs.withoutDryRun(func() {
+			fields, err = f()
+		})
+		if err != nil {
+			return nil
 		}
 		return fields
-	}, 0)
-	if err == nil {
+	}, bs.getTableFieldsTTL()*1000)
+	if err == nil && v != nil {
 		fields = v.(map[string]string)
 	}
 	return
 }
 
+// 获得数据表字段结构的缓存时间(单位秒)，0表示不过期
+func (bs *dbBase) getTableFieldsTTL() int {
+	if n := bs.tableFieldsTTL.Val(); n > 0 {
+		return n
+	}
+	configs.RLock()
+	defer configs.RUnlock()
+	if node, err := getConfigNodeByGroup(bs.group, true); err == nil && node.TableFieldsTTL > 0 {
+		return node.TableFieldsTTL
+	}
+	return 0
+}
+
+// 清除指定数据表的字段结构缓存，下一次使用时将会重新查询数据表结构；
+// 如果没有指定数据表，那么清除所有数据表的字段结构缓存。
+func (bs *dbBase) ClearTableFields(table ...string) {
+	if len(table) > 0 {
+		for _, t := range table {
+			bs.cache.Remove(gTABLE_FIELDS_CACHE_PREFIX + t)
+			bs.cache.Remove(gTABLE_PRIMARY_KEY_CACHE_PREFIX + t)
+		}
+		return
+	}
+	for _, key := range bs.cache.KeyStrings() {
+		if strings.HasPrefix(key, gTABLE_FIELDS_CACHE_PREFIX) || strings.HasPrefix(key, gTABLE_PRIMARY_KEY_CACHE_PREFIX) {
+			bs.cache.Remove(key)
+		}
+	}
+}
+
+// 判断是否为字段不存在的数据库错误，一般是数据表结构变更后字段结构缓存过期导致的
+func isUnknownColumnError(err error) bool {
+	s := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(s, "unknown column"): // mysql
+		return true
+	case strings.Contains(s, "no such column"), strings.Contains(s, "has no column named"): // sqlite
+		return true
+	case strings.Contains(s, "invalid column name"): // mssql
+		return true
+	case strings.Contains(s, "ora-00904"): // oracle
+		return true
+	case strings.Contains(s, "column") && strings.Contains(s, "does not exist"): // pgsql
+		return true
+	}
+	return false
+}
+
 /*
 // 获取当前数据库所有的表结构
 func (bs *dbBase) getTables() []string {
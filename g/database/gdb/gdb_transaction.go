@@ -23,7 +23,17 @@ type TX struct {
 
 // 事务操作，提交
 func (tx *TX) Commit() error {
-	return tx.tx.Commit()
+	if err := tx.tx.Commit(); err != nil {
+		return err
+	}
+	// 事务中的写操作在提交后才对slave可见
+	tx.db.markWrite(nil, tx.db.GetCtx())
+	return nil
+}
+
+// 事务操作的链接对象
+func (tx *TX) link() dbLink {
+	return &txLink{Tx: tx.tx, master: tx.master}
 }
 
 // 事务操作，回滚
@@ -33,27 +43,22 @@ func (tx *TX) Rollback() error {
 
 // (事务)数据库sql查询操作，主要执行查询
 func (tx *TX) Query(query string, args ...interface{}) (rows *sql.Rows, err error) {
-	return tx.db.doQuery(tx.tx, query, args...)
+	return tx.db.doQuery(tx.link(), query, args...)
 }
 
 // (事务)执行一条sql，并返回执行情况，主要用于非查询操作
 func (tx *TX) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return tx.db.doExec(tx.tx, query, args...)
+	return tx.db.doExec(tx.link(), query, args...)
 }
 
 // sql预处理，执行完成后调用返回值sql.Stmt.Exec完成sql操作
 func (tx *TX) Prepare(query string) (*sql.Stmt, error) {
-	return tx.db.doPrepare(tx.tx, query)
+	return tx.db.doPrepare(tx.link(), query)
 }
 
 // 数据库查询，获取查询结果集，以列表结构返回
 func (tx *TX) GetAll(query string, args ...interface{}) (Result, error) {
-	rows, err := tx.Query(query, args...)
-	if err != nil || rows == nil {
-		return nil, err
-	}
-	defer rows.Close()
-	return tx.db.rowsToResult(rows)
+	return tx.db.getAll(tx.link(), query, args...)
 }
 
 // 数据库查询，获取查询结果记录，以关联数组结构返回
@@ -134,32 +139,32 @@ func (tx *TX) GetCount(query string, args ...interface{}) (int, error) {
 
 // CURD操作:单条数据写入, 仅仅执行写入操作，如果存在冲突的主键或者唯一索引，那么报错返回
 func (tx *TX) Insert(table string, data interface{}, batch ...int) (sql.Result, error) {
-	return tx.db.doInsert(tx.tx, table, data, OPTION_INSERT, batch...)
+	return tx.db.doInsert(tx.link(), table, data, OPTION_INSERT, batch...)
 }
 
 // CURD操作:单条数据写入, 如果数据存在(主键或者唯一索引)，那么删除后重新写入一条
 func (tx *TX) Replace(table string, data interface{}, batch ...int) (sql.Result, error) {
-	return tx.db.doInsert(tx.tx, table, data, OPTION_REPLACE, batch...)
+	return tx.db.doInsert(tx.link(), table, data, OPTION_REPLACE, batch...)
 }
 
 // CURD操作:单条数据写入, 如果数据存在(主键或者唯一索引)，那么更新，否则写入一条新数据
 func (tx *TX) Save(table string, data interface{}, batch ...int) (sql.Result, error) {
-	return tx.db.doInsert(tx.tx, table, data, OPTION_SAVE, batch...)
+	return tx.db.doInsert(tx.link(), table, data, OPTION_SAVE, batch...)
 }
 
 // CURD操作:批量数据指定批次量写入
 func (tx *TX) BatchInsert(table string, list interface{}, batch ...int) (sql.Result, error) {
-	return tx.db.doBatchInsert(tx.tx, table, list, OPTION_INSERT, batch...)
+	return tx.db.doBatchInsert(tx.link(), table, list, OPTION_INSERT, batch...)
 }
 
 // CURD操作:批量数据指定批次量写入, 如果数据存在(主键或者唯一索引)，那么删除后重新写入一条
 func (tx *TX) BatchReplace(table string, list interface{}, batch ...int) (sql.Result, error) {
-	return tx.db.doBatchInsert(tx.tx, table, list, OPTION_REPLACE, batch...)
+	return tx.db.doBatchInsert(tx.link(), table, list, OPTION_REPLACE, batch...)
 }
 
 // CURD操作:批量数据指定批次量写入, 如果数据存在(主键或者唯一索引)，那么更新，否则写入一条新数据
 func (tx *TX) BatchSave(table string, list interface{}, batch ...int) (sql.Result, error) {
-	return tx.db.doBatchInsert(tx.tx, table, list, OPTION_SAVE, batch...)
+	return tx.db.doBatchInsert(tx.link(), table, list, OPTION_SAVE, batch...)
 }
 
 // CURD操作:数据更新，统一采用sql预处理,
@@ -171,7 +176,7 @@ func (tx *TX) Update(table string, data interface{}, condition interface{}, args
 
 // 与Update方法的区别是不处理条件参数
 func (tx *TX) doUpdate(table string, data interface{}, condition string, args ...interface{}) (sql.Result, error) {
-	return tx.db.doUpdate(tx.tx, table, data, condition, args...)
+	return tx.db.doUpdate(tx.link(), table, data, condition, args...)
 }
 
 // CURD操作:删除数据
@@ -182,5 +187,5 @@ func (tx *TX) Delete(table string, condition interface{}, args ...interface{}) (
 
 // 与Delete方法的区别是不处理条件参数
 func (tx *TX) doDelete(table string, condition string, args ...interface{}) (sql.Result, error) {
-	return tx.db.doDelete(tx.tx, table, condition, args...)
+	return tx.db.doDelete(tx.link(), table, condition, args...)
 }
@@ -0,0 +1,117 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gf/g/util/grand"
+	"github.com/gf/third/github.com/gf-third/mysql"
+)
+
+const (
+	gDEFAULT_TX_MAX_RETRIES = 3                      // 事务闭包遇到死锁/序列化失败时默认的最大重试次数
+	gTX_RETRY_BASE_DELAY    = 10 * time.Millisecond  // 事务重试的初始等待时间，每次重试翻倍
+	gTX_RETRY_MAX_DELAY     = 500 * time.Millisecond // 事务重试的最大等待时间
+)
+
+// 事务重试回调函数，retry为第几次重试(从1开始)，delay为重试前的等待时间，err为导致重试的错误
+type TxRetryHook = func(retry int, delay time.Duration, err error)
+
+// 返回SQLSTATE错误码的数据库错误接口(例如PostgreSQL驱动的错误对象)
+type apiSQLState interface {
+	SQLState() string
+}
+
+// 事务闭包操作，自动开启事务并执行f，f返回nil时提交事务，返回错误或者panic时回滚事务。
+// 当f或者提交事务返回可重试的错误(MySQL死锁1213, PostgreSQL序列化失败40001/死锁40P01)时，
+// 将会回滚事务并使用带随机抖动的指数退避重新执行f，重试次数通过SetTxMaxRetries设置。
+// 注意f可能被执行多次，因此f中不应当包含事务以外不可重复的操作。
+func (bs *dbBase) Transaction(f func(tx *TX) error) (err error) {
+	for retry := 0; ; retry++ {
+		if err = bs.doTransaction(f); err == nil || !IsRetryableError(err) || retry >= bs.txMaxRetries.Val() {
+			return err
+		}
+		delay := txRetryDelay(retry + 1)
+		if hook, ok := bs.txRetryHook.Val().(TxRetryHook); ok && hook != nil {
+			hook(retry+1, delay, err)
+		}
+		select {
+		case <-bs.GetCtx().Done():
+			return bs.GetCtx().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// 执行一次事务闭包操作
+func (bs *dbBase) doTransaction(f func(tx *TX) error) (err error) {
+	tx, err := bs.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			tx.Rollback()
+			panic(e)
+		}
+	}()
+	if err = f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// 设置事务闭包操作遇到死锁/序列化失败时的最大重试次数，n <= 0 表示不重试
+func (bs *dbBase) SetTxMaxRetries(n int) {
+	bs.txMaxRetries.Set(n)
+}
+
+// 设置事务闭包操作重试时的回调函数，一般用于记录重试日志
+func (bs *dbBase) SetTxRetryHook(hook TxRetryHook) {
+	bs.txRetryHook.Set(hook)
+}
+
+// 判断数据库错误是否为可以通过重新执行事务解决的错误，
+// 包括MySQL死锁(1213)以及PostgreSQL序列化失败(40001)和死锁(40P01)。
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213
+	}
+	var stateErr apiSQLState
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		return state == "40001" || state == "40P01"
+	}
+	// 执行SQL返回的错误经过了格式化(参考formatError)，只能通过错误信息判断
+	s := err.Error()
+	return strings.Contains(s, "Error 1213:") ||
+		strings.Contains(s, "could not serialize access") ||
+		strings.Contains(s, "deadlock detected") ||
+		strings.Contains(s, "SQLSTATE 40001") ||
+		strings.Contains(s, "SQLSTATE 40P01")
+}
+
+// 计算第retry次(从1开始)事务重试的等待时间，指数退避并设置上限，
+// 随机抖动范围为[delay/2, delay]，避免冲突的事务同时重试再次冲突。
+func txRetryDelay(retry int) time.Duration {
+	delay := gTX_RETRY_MAX_DELAY
+	if retry < 16 {
+		if d := gTX_RETRY_BASE_DELAY << uint(retry-1); d < delay {
+			delay = d
+		}
+	}
+	half := int(delay / 2)
+	return time.Duration(half + grand.N(0, half))
+}
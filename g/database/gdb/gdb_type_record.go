@@ -8,18 +8,19 @@ package gdb
 
 import (
 	"github.com/gf/g/encoding/gparser"
+	"github.com/gf/g/util/gconv"
 )
 
 // 将记录结果转换为JSON字符串
 func (r Record) ToJson() string {
 	content, _ := gparser.VarToJson(r.ToMap())
-	return string(content)
+	return gconv.UnsafeBytesToStr(content)
 }
 
 // 将记录结果转换为XML字符串
 func (r Record) ToXml(rootTag ...string) string {
 	content, _ := gparser.VarToXml(r.ToMap(), rootTag...)
-	return string(content)
+	return gconv.UnsafeBytesToStr(content)
 }
 
 // 将Record转换为Map，其中最主要的区别是里面的键值被强制转换为string类型，方便json处理
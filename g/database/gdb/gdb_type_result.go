@@ -7,22 +7,25 @@
 package gdb
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/gf/g/encoding/gparser"
+	"github.com/gf/g/util/gconv"
 )
 
 // 将结果集转换为JSON字符串
 func (r Result) ToJson() string {
 	content, _ := gparser.VarToJson(r.ToList())
-	return string(content)
+	return gconv.UnsafeBytesToStr(content)
 }
 
 // 将结果集转换为XML字符串
 func (r Result) ToXml(rootTag ...string) string {
 	content, _ := gparser.VarToXml(r.ToList(), rootTag...)
-	return string(content)
+	return gconv.UnsafeBytesToStr(content)
 }
 
 // 将结果集转换为List类型返回，便于json处理
@@ -126,3 +129,122 @@ func (r Result) ToStructs(objPointerSlice interface{}) error {
 	reflect.ValueOf(objPointerSlice).Elem().Set(a)
 	return nil
 }
+
+// 将结果集按照属性名称映射到结构体slice元素的指定属性中，参数listPointer为结构体slice的指针，
+// 例如：*[]Entity或者*[]*Entity，属性attrName的类型可以为struct/*struct/[]struct/[]*struct。
+//
+// 1. 当relation参数为空时，结果集的记录按照顺序映射到slice元素的属性中，
+// slice为空时将按照结果集长度自动创建，常用于将联表查询的每一条记录映射到多个嵌套的结构体中，例如：
+// r.ScanList(&entities, "User")
+// r.ScanList(&entities, "UserDetail")
+//
+// 2. 当relation参数不为空时，格式为：关联属性名称, 记录字段名称:关联属性的字段名称，
+// 结果集的记录按照字段值关联到已有的slice元素中，属性为slice类型时映射所有关联的记录，
+// 否则映射第一条关联的记录，例如：
+// r.ScanList(&entities, "UserScores", "User", "uid:Id")
+func (r Result) ScanList(listPointer interface{}, attrName string, relation ...string) error {
+	pointer := reflect.ValueOf(listPointer)
+	if pointer.Kind() != reflect.Ptr || pointer.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("params should be type of slice pointer, but got: %v", pointer.Kind())
+	}
+	list := pointer.Elem()
+	itemType := list.Type().Elem()
+	structType := itemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("element type should be type of struct/*struct, unsupported: %v", itemType)
+	}
+	if _, ok := structType.FieldByName(attrName); !ok {
+		return fmt.Errorf(`attribute "%s" not found in %v`, attrName, structType)
+	}
+	// 按照顺序映射
+	if len(relation) == 0 {
+		if list.Len() == 0 && len(r) > 0 {
+			list.Set(reflect.MakeSlice(list.Type(), len(r), len(r)))
+		}
+		if list.Len() != len(r) {
+			return fmt.Errorf("length of list %d does not match length of result %d", list.Len(), len(r))
+		}
+		for i, record := range r {
+			if err := scanToAttribute(scanListItem(list.Index(i)).FieldByName(attrName), Result{record}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// 按照关联字段映射
+	if len(relation) < 2 {
+		return errors.New(`relation should be in format: relation attribute name, "field:attribute field"`)
+	}
+	if _, ok := structType.FieldByName(relation[0]); !ok {
+		return fmt.Errorf(`relation attribute "%s" not found in %v`, relation[0], structType)
+	}
+	keys := strings.Split(relation[1], ":")
+	if len(keys) != 2 {
+		return fmt.Errorf(`invalid relation keys "%s", which should be in format "field:attribute field"`, relation[1])
+	}
+	grouped := make(map[string]Result)
+	for _, record := range r {
+		if v, ok := record[keys[0]]; ok {
+			key := v.String()
+			grouped[key] = append(grouped[key], record)
+		}
+	}
+	for i := 0; i < list.Len(); i++ {
+		item := scanListItem(list.Index(i))
+		relationAttr := item.FieldByName(relation[0])
+		if relationAttr.Kind() == reflect.Ptr {
+			if relationAttr.IsNil() {
+				continue
+			}
+			relationAttr = relationAttr.Elem()
+		}
+		if relationAttr.Kind() != reflect.Struct {
+			return fmt.Errorf(`relation attribute "%s" should be type of struct/*struct, but got: %v`, relation[0], relationAttr.Type())
+		}
+		field := relationAttr.FieldByName(keys[1])
+		if !field.IsValid() {
+			return fmt.Errorf(`field "%s" not found in relation attribute "%s"`, keys[1], relation[0])
+		}
+		if records, ok := grouped[gconv.String(field.Interface())]; ok {
+			if err := scanToAttribute(item.FieldByName(attrName), records); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 返回slice元素的结构体对象，元素为空指针时自动创建。
+func scanListItem(item reflect.Value) reflect.Value {
+	if item.Kind() == reflect.Ptr {
+		if item.IsNil() {
+			item.Set(reflect.New(item.Type().Elem()))
+		}
+		return item.Elem()
+	}
+	return item
+}
+
+// 将记录映射到属性中，属性为slice类型时映射所有记录，否则映射第一条记录。
+func scanToAttribute(attr reflect.Value, records Result) error {
+	switch attr.Kind() {
+	case reflect.Slice:
+		pointer := reflect.New(attr.Type())
+		if err := records.ToStructs(pointer.Interface()); err != nil {
+			return err
+		}
+		attr.Set(pointer.Elem())
+	case reflect.Ptr:
+		pointer := reflect.New(attr.Type().Elem())
+		if err := records[0].ToStruct(pointer.Interface()); err != nil {
+			return err
+		}
+		attr.Set(pointer)
+	default:
+		return records[0].ToStruct(attr.Addr().Interface())
+	}
+	return nil
+}
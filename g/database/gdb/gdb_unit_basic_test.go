@@ -7,9 +7,16 @@
 package gdb_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gogf/gf/g/database/gdb"
+	"github.com/gogf/gf/g/os/glog"
 	"github.com/gogf/gf/g/test/gtest"
 )
 
@@ -27,3 +34,300 @@ func Test_Instance(t *testing.T) {
 		gtest.Assert(err2, nil)
 	})
 }
+
+func Test_Balance(t *testing.T) {
+	master := gdb.GetConfig("test")[0]
+	slave := master
+	slave.Role = "slave"
+	dead := slave
+	dead.Port = "1"
+	gdb.AddConfigGroup("balance", gdb.ConfigGroup{master, slave, dead})
+	gtest.Case(t, func() {
+		db, err := gdb.New("balance")
+		gtest.Assert(err, nil)
+		db.SetBalance(gdb.BALANCE_ROUND_ROBIN)
+		// 首次选择slave节点时异步进行健康检查
+		db.Slave()
+		time.Sleep(time.Second)
+		nodes := db.GetDeadNodes()
+		gtest.Assert(len(nodes), 1)
+		gtest.Assert(nodes[0].Port, "1")
+		for i := 0; i < 4; i++ {
+			gtest.Assert(db.PingSlave(), nil)
+		}
+
+		// 同一粘滞范围内写操作后在粘滞时间内读操作使用master节点
+		m, err := db.Master()
+		gtest.Assert(err, nil)
+		db.SetStickyMaster(500)
+		sticky := db.Ctx(gdb.WithStickyMaster(context.Background()))
+		_, err = sticky.Exec("SELECT 1")
+		gtest.Assert(err, nil)
+		s, err := sticky.Slave()
+		gtest.Assert(err, nil)
+		gtest.Assert(s == m, true)
+		// 其他粘滞范围以及未使用粘滞范围的读操作不受影响
+		s, err = db.Ctx(gdb.WithStickyMaster(context.Background())).Slave()
+		gtest.Assert(err, nil)
+		gtest.Assert(s == m, false)
+		s, err = db.Slave()
+		gtest.Assert(err, nil)
+		gtest.Assert(s == m, false)
+		time.Sleep(600 * time.Millisecond)
+		s, err = sticky.Slave()
+		gtest.Assert(err, nil)
+		gtest.Assert(s == m, false)
+
+		// 事务中的写操作在提交后开始粘滞
+		tx, err := sticky.Begin()
+		gtest.Assert(err, nil)
+		_, err = tx.Exec("SELECT 1")
+		gtest.Assert(err, nil)
+		s, err = sticky.Slave()
+		gtest.Assert(err, nil)
+		gtest.Assert(s == m, false)
+		gtest.Assert(tx.Commit(), nil)
+		s, err = sticky.Slave()
+		gtest.Assert(err, nil)
+		gtest.Assert(s == m, true)
+	})
+}
+
+func Test_SqlLog(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+	gtest.Case(t, func() {
+		buffer := bytes.NewBuffer(nil)
+		logger := glog.New()
+		logger.SetWriter(buffer)
+		logger.SetStdoutPrint(false)
+		db, err := gdb.New("test")
+		gtest.Assert(err, nil)
+		db.SetSchema("test")
+		db.SetDebug(true)
+		db.SetLogger(logger)
+		gtest.Assert(db.GetLogger(), logger)
+
+		db.SetSqlLogOption(gdb.SqlLogOption{
+			InlineArgs:   true,
+			RedactFields: []string{"password"},
+		})
+		_, err = db.Exec("INSERT INTO "+table+"(`id`,`passport`,`password`,`nickname`) VALUES(?,?,?,?)", 1, "john", "123456", "J'ohn")
+		gtest.Assert(err, nil)
+		gtest.Assert(strings.Contains(buffer.String(), "VALUES(1,'john','***','J''ohn')"), true)
+		gtest.Assert(strings.Contains(buffer.String(), "123456"), false)
+
+		buffer.Reset()
+		db.SetSqlLogOption(gdb.SqlLogOption{
+			RedactFields: []string{"password"},
+			Pretty:       true,
+		})
+		_, err = db.Exec("UPDATE "+table+" SET `password`=?,nickname=? WHERE id IN(?,?) AND passport=?", "654321", "john", 1, 2, "john")
+		gtest.Assert(err, nil)
+		gtest.Assert(strings.Contains(buffer.String(), "\nSET `password`=?,nickname=?\nWHERE id IN(?,?)\n  AND passport=?, [*** john 1 2 john]"), true)
+	})
+}
+
+func Test_SlowLog(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+	gtest.Case(t, func() {
+		buffer := bytes.NewBuffer(nil)
+		logger := glog.New()
+		logger.SetWriter(buffer)
+		logger.SetStdoutPrint(false)
+		db, err := gdb.New("test")
+		gtest.Assert(err, nil)
+		db.SetSchema("test")
+		db.SetLogger(logger)
+
+		// 未设置慢查询阈值时不记录
+		_, err = db.GetAll("SELECT SLEEP(0.05), id FROM " + table)
+		gtest.Assert(err, nil)
+		gtest.Assert(strings.Contains(buffer.String(), "[SLOW]"), false)
+
+		db.SetSlowThreshold(20)
+		_, err = db.GetAll("SELECT id FROM "+table+" WHERE id=?", 1)
+		gtest.Assert(err, nil)
+		gtest.Assert(strings.Contains(buffer.String(), "[SLOW]"), false)
+		_, err = db.GetAll("SELECT SLEEP(0.05), id FROM "+table+" WHERE id<?", 3)
+		gtest.Assert(err, nil)
+		gtest.Assert(strings.Contains(buffer.String(), "[SLOW] SELECT SLEEP(0.05), id FROM "+table+" WHERE id<?, [3], rows: 2"), true)
+		gtest.Assert(strings.Contains(buffer.String(), "Explain:"), false)
+
+		buffer.Reset()
+		db.SetDebug(true)
+		db.SetSlowExplain(true)
+		_, err = db.GetAll("SELECT SLEEP(0.05), id FROM "+table+" WHERE id<?", 3)
+		gtest.Assert(err, nil)
+		gtest.Assert(strings.Contains(buffer.String(), "Explain:"), true)
+	})
+}
+
+func Test_Stats(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+	gtest.Case(t, func() {
+		db, err := gdb.New("test")
+		gtest.Assert(err, nil)
+		db.SetSchema("test")
+		db.SetMaxOpenConns(10)
+		// 没有执行过操作时不会创建连接池
+		gtest.Assert(len(db.Stats()), 0)
+
+		_, err = db.GetAll("SELECT * FROM " + table)
+		gtest.Assert(err, nil)
+		stats := db.Stats()
+		gtest.Assert(len(stats), 1)
+		gtest.Assert(stats[0].Group, "test")
+		gtest.Assert(stats[0].Role, "master")
+		gtest.Assert(stats[0].MaxOpen, 10)
+		gtest.Assert(stats[0].Open >= 1, true)
+		gtest.Assert(stats[0].InUse, 0)
+		gtest.Assert(stats[0].Open, stats[0].Idle)
+		gtest.Assert(strings.Contains(stats[0].Node, "root"), false)
+	})
+}
+
+func Test_StmtCache(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+	gtest.Case(t, func() {
+		db, err := gdb.New("test")
+		gtest.Assert(err, nil)
+		db.SetSchema("test")
+		for _, size := range []int{100, 1, 0} {
+			db.SetStmtCacheSize(size)
+			for i := 1; i <= 3; i++ {
+				one, err := db.GetOne("SELECT * FROM "+table+" WHERE id=?", i)
+				gtest.Assert(err, nil)
+				gtest.Assert(one["passport"].String(), fmt.Sprintf("t%d", i))
+				count, err := db.GetCount("SELECT COUNT(*) FROM "+table+" WHERE id>?", i)
+				gtest.Assert(err, nil)
+				gtest.Assert(count, INIT_DATA_SIZE-i)
+			}
+		}
+		db.SetStmtCacheSize(100)
+		err = db.Transaction(func(tx *gdb.TX) error {
+			if _, err := tx.Exec("UPDATE "+table+" SET nickname=? WHERE id=?", "tx", 1); err != nil {
+				return err
+			}
+			one, err := tx.GetOne("SELECT * FROM "+table+" WHERE id=?", 1)
+			gtest.Assert(one["nickname"].String(), "tx")
+			return err
+		})
+		gtest.Assert(err, nil)
+		value, err := db.GetValue("SELECT nickname FROM "+table+" WHERE id=?", 1)
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "tx")
+	})
+
+	// 通过节点配置开启预处理语句缓存
+	gtest.Case(t, func() {
+		node := gdb.GetConfig("test")[0]
+		node.StmtCacheSize = 10
+		gdb.AddConfigGroup("stmt", gdb.ConfigGroup{node})
+		db, err := gdb.New("stmt")
+		gtest.Assert(err, nil)
+		db.SetSchema("test")
+		for i := 0; i < 2; i++ {
+			one, err := db.GetOne("SELECT * FROM "+table+" WHERE id=?", 2)
+			gtest.Assert(err, nil)
+			gtest.Assert(one["passport"].String(), "t2")
+		}
+	})
+}
+
+func Test_Ctx(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+	gtest.Case(t, func() {
+		db, err := gdb.New("test")
+		gtest.Assert(err, nil)
+		db.SetSchema("test")
+		gtest.Assert(db.GetCtx(), context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		gtest.Assert(db.Ctx(ctx).GetCtx(), ctx)
+		_, err = db.Ctx(ctx).GetValue("SELECT SLEEP(1)")
+		gtest.AssertNE(err, nil)
+		_, err = db.Ctx(ctx).Exec("SELECT SLEEP(1)")
+		gtest.AssertNE(err, nil)
+		_, err = db.Table(table).Ctx(ctx).Where("id=SLEEP(1)").One()
+		gtest.AssertNE(err, nil)
+
+		one, err := db.Ctx(context.Background()).Table(table).Where("id=?", 1).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["passport"].String(), "t1")
+	})
+	// 默认超时时间
+	gtest.Case(t, func() {
+		db, err := gdb.New("test")
+		gtest.Assert(err, nil)
+		db.SetQueryTimeout(100)
+		_, err = db.GetValue("SELECT SLEEP(1)")
+		gtest.AssertNE(err, nil)
+		value, err := db.GetValue("SELECT 1")
+		gtest.Assert(err, nil)
+		gtest.Assert(value.Int(), 1)
+	})
+	// 服务端取消语句
+	gtest.Case(t, func() {
+		db, err := gdb.New("test")
+		gtest.Assert(err, nil)
+		db.SetCancelStatement(true)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err = db.Ctx(ctx).GetValue("SELECT SLEEP(2.5)")
+		gtest.AssertNE(err, nil)
+		_, err = db.Ctx(ctx).Exec("SELECT SLEEP(2.6)")
+		gtest.AssertNE(err, nil)
+		time.Sleep(200 * time.Millisecond)
+		count, err := db.GetCount("SELECT COUNT(*) FROM information_schema.PROCESSLIST WHERE INFO LIKE 'SELECT SLEEP(2._)'")
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 0)
+
+		value, err := db.Ctx(context.Background()).GetValue("SELECT 1")
+		gtest.Assert(err, nil)
+		gtest.Assert(value.Int(), 1)
+	})
+}
+
+func Test_Middleware(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+	gtest.Case(t, func() {
+		db, err := gdb.New("test")
+		gtest.Assert(err, nil)
+		db.SetSchema("test")
+		ops := make([]string, 0)
+		db.Use(func(next gdb.Handler) gdb.Handler {
+			return func(op *gdb.Operation) error {
+				err := next(op)
+				ops = append(ops, fmt.Sprintf("%s:%v:%v", op.Type, op.End >= op.Start, err == nil))
+				return err
+			}
+		}, func(next gdb.Handler) gdb.Handler {
+			return func(op *gdb.Operation) error {
+				if strings.HasPrefix(op.Sql, "DELETE") {
+					return errors.New("delete is not allowed")
+				}
+				// 行过滤条件
+				if op.Type == gdb.OPERATION_QUERY && strings.Contains(op.Sql, table) {
+					op.Sql += " AND id<=?"
+					op.Args = append(op.Args, 2)
+				}
+				return next(op)
+			}
+		})
+		count, err := db.GetCount("SELECT COUNT(*) FROM "+table+" WHERE id>?", 0)
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 2)
+		_, err = db.Exec("UPDATE "+table+" SET nickname=? WHERE id=?", "john", 1)
+		gtest.Assert(err, nil)
+		_, err = db.Exec("DELETE FROM " + table)
+		gtest.AssertNE(err, nil)
+		gtest.Assert(ops, []string{"query:true:true", "exec:true:true", "exec:true:false"})
+	})
+}
@@ -0,0 +1,278 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/database/gdb"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+// 用于测试ClickHouse适配的驱动，不连接服务端，只记录驱动接收到的操作，
+// 注册为"clickhouse"驱动名称，因此不能与真实的ClickHouse驱动同时使用。
+type chTestDriver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+type chTestConn struct {
+	driver *chTestDriver
+}
+
+type chTestStmt struct {
+	driver *chTestDriver
+	query  string
+}
+
+type chTestTx struct {
+	driver *chTestDriver
+}
+
+type chTestRows struct{}
+
+var (
+	chDriver = &chTestDriver{}
+	// 写入语句的字段列表
+	chInsertFieldsRegex = regexp.MustCompile(`\((.+?)\) VALUES`)
+)
+
+func init() {
+	sql.Register("clickhouse", chDriver)
+	gdb.AddConfigNode("clickhouse", gdb.ConfigNode{
+		Type:     "clickhouse",
+		LinkInfo: "tcp://127.0.0.1:9000",
+		Role:     "master",
+	})
+}
+
+// 记录驱动操作
+func (d *chTestDriver) record(event string) {
+	d.mu.Lock()
+	d.events = append(d.events, event)
+	d.mu.Unlock()
+}
+
+// 返回并清空已记录的驱动操作
+func (d *chTestDriver) flush() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	events := d.events
+	d.events = nil
+	return events
+}
+
+func (d *chTestDriver) Open(name string) (driver.Conn, error) {
+	return &chTestConn{driver: d}, nil
+}
+
+func (c *chTestConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.record("prepare: " + query)
+	return &chTestStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *chTestConn) Close() error {
+	return nil
+}
+
+func (c *chTestConn) Begin() (driver.Tx, error) {
+	c.driver.record("begin")
+	return &chTestTx{driver: c.driver}, nil
+}
+
+func (s *chTestStmt) Close() error {
+	return nil
+}
+
+func (s *chTestStmt) NumInput() int {
+	return -1
+}
+
+// 记录执行参数，写入语句的参数按照字段名称排序记录
+func (s *chTestStmt) Exec(args []driver.Value) (driver.Result, error) {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = fmt.Sprintf("%T(%v)", arg, arg)
+	}
+	if match := chInsertFieldsRegex.FindStringSubmatch(s.query); len(match) > 1 {
+		fields := strings.Split(strings.Replace(match[1], "`", "", -1), ",")
+		for i := range values {
+			values[i] = fields[i] + "=" + values[i]
+		}
+		sort.Strings(values)
+	}
+	s.driver.record("exec: " + strings.Join(values, " "))
+	return driver.RowsAffected(1), nil
+}
+
+func (s *chTestStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.driver.record("query: " + s.query)
+	return &chTestRows{}, nil
+}
+
+func (tx *chTestTx) Commit() error {
+	tx.driver.record("commit")
+	return nil
+}
+
+func (tx *chTestTx) Rollback() error {
+	tx.driver.record("rollback")
+	return nil
+}
+
+func (r *chTestRows) Columns() []string {
+	return []string{}
+}
+
+func (r *chTestRows) Close() error {
+	return nil
+}
+
+func (r *chTestRows) Next(dest []driver.Value) error {
+	return io.EOF
+}
+
+func Test_Clickhouse_Insert(t *testing.T) {
+	gtest.Case(t, func() {
+		db, err := gdb.New("clickhouse")
+		gtest.Assert(err, nil)
+		chDriver.flush()
+
+		// 每个批次在独立的事务中通过同一个预处理语句写入数据块
+		created := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+		result, err := db.BatchInsert("events", g.List{
+			{"id": 1, "name": "login", "created": created},
+			{"id": 2, "name": "logout", "created": created},
+			{"id": 3, "name": "view", "created": created},
+		}, 2)
+		gtest.Assert(err, nil)
+		n, err := result.RowsAffected()
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 3)
+		events := chDriver.flush()
+		gtest.Assert(len(events), 9)
+		for _, i := range []int{0, 5} {
+			gtest.Assert(events[i], "begin")
+			gtest.Assert(strings.HasPrefix(events[i+1], "prepare: INSERT INTO events("), true)
+			gtest.Assert(strings.HasSuffix(events[i+1], ") VALUES(?,?,?)"), true)
+			for _, field := range []string{"`id`", "`name`", "`created`"} {
+				gtest.Assert(strings.Contains(events[i+1], field), true)
+			}
+		}
+		gtest.Assert(events[2:5], []string{
+			"exec: created=time.Time(2019-06-01 12:00:00 +0000 UTC) id=int64(1) name=string(login)",
+			"exec: created=time.Time(2019-06-01 12:00:00 +0000 UTC) id=int64(2) name=string(logout)",
+			"commit",
+		})
+		gtest.Assert(events[7:], []string{
+			"exec: created=time.Time(2019-06-01 12:00:00 +0000 UTC) id=int64(3) name=string(view)",
+			"commit",
+		})
+
+		// 单条写入同样使用数据块
+		_, err = db.Insert("events", g.Map{"id": 4, "ok": true, "score": 1.5})
+		gtest.Assert(err, nil)
+		events = chDriver.flush()
+		gtest.Assert(len(events), 4)
+		gtest.Assert(events[2], "exec: id=int64(4) ok=bool(true) score=float64(1.5)")
+
+		// 不支持的写入操作
+		_, err = db.Save("events", g.Map{"id": 1})
+		gtest.AssertNE(err, nil)
+		_, err = db.Replace("events", g.Map{"id": 1})
+		gtest.AssertNE(err, nil)
+		_, err = db.Table("events").Data(g.Map{"id": 1}).Save()
+		gtest.AssertNE(err, nil)
+		_, err = db.BatchInsert("events", g.List{})
+		gtest.AssertNE(err, nil)
+		gtest.Assert(len(chDriver.flush()), 0)
+	})
+}
+
+func Test_Clickhouse_Transaction(t *testing.T) {
+	gtest.Case(t, func() {
+		db, err := gdb.New("clickhouse")
+		gtest.Assert(err, nil)
+		chDriver.flush()
+
+		// 事务中的数据块在提交时发送，回滚时丢弃
+		tx, err := db.Begin()
+		gtest.Assert(err, nil)
+		_, err = tx.Insert("events", g.Map{"id": 1})
+		gtest.Assert(err, nil)
+		gtest.Assert(tx.Rollback(), nil)
+		gtest.Assert(chDriver.flush(), []string{
+			"begin",
+			"prepare: INSERT INTO events(`id`) VALUES(?)",
+			"exec: id=int64(1)",
+			"rollback",
+		})
+
+		err = db.Transaction(func(tx *gdb.TX) error {
+			_, err := tx.BatchInsert("events", g.List{{"id": 1}, {"id": 2}}, 1)
+			return err
+		})
+		gtest.Assert(err, nil)
+		gtest.Assert(chDriver.flush(), []string{
+			"begin",
+			"prepare: INSERT INTO events(`id`) VALUES(?)",
+			"exec: id=int64(1)",
+			"prepare: INSERT INTO events(`id`) VALUES(?)",
+			"exec: id=int64(2)",
+			"commit",
+		})
+	})
+}
+
+func Test_Clickhouse_Mutation(t *testing.T) {
+	gtest.Case(t, func() {
+		db, err := gdb.New("clickhouse")
+		gtest.Assert(err, nil)
+		chDriver.flush()
+
+		// UPDATE/DELETE语句转换为mutation语句，参数保持不变
+		_, err = db.Update("events", "name=?", "id>? AND name=?", "new", 1, "old")
+		gtest.Assert(err, nil)
+		gtest.Assert(chDriver.flush(), []string{
+			"prepare: ALTER TABLE events UPDATE name=? WHERE id>? AND name=?",
+			"exec: string(new) int64(1) string(old)",
+		})
+
+		_, err = db.Delete("events", "id=?", 1)
+		gtest.Assert(err, nil)
+		gtest.Assert(chDriver.flush(), []string{
+			"prepare: ALTER TABLE events DELETE WHERE id=?",
+			"exec: int64(1)",
+		})
+
+		// 没有条件时操作所有数据
+		_, err = db.Exec("DELETE FROM `events`")
+		gtest.Assert(err, nil)
+		gtest.Assert(chDriver.flush(), []string{
+			"prepare: ALTER TABLE `events` DELETE WHERE 1=1",
+			"exec: ",
+		})
+
+		// 查询语句不转换
+		_, err = db.GetAll("SELECT * FROM events WHERE id=?", 1)
+		gtest.Assert(err, nil)
+		gtest.Assert(chDriver.flush(), []string{
+			"prepare: SELECT * FROM events WHERE id=?",
+			"query: SELECT * FROM events WHERE id=?",
+		})
+	})
+}
@@ -534,3 +534,42 @@ func TestDbBase_Time(t *testing.T) {
 		gtest.Assert(n, 2)
 	}
 }
+
+func TestDbBase_Tables(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		tables, err := db.Tables()
+		gtest.Assert(err, nil)
+		gtest.AssertIN(table, tables)
+
+		tables, err = db.Tables("test")
+		gtest.Assert(err, nil)
+		gtest.AssertIN(table, tables)
+	})
+}
+
+func TestDbBase_TableFields(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		fields, err := db.TableFields(table)
+		gtest.Assert(err, nil)
+		gtest.Assert(len(fields), 5)
+		gtest.Assert(fields["id"].Index, 0)
+		gtest.Assert(fields["id"].Key, "PRI")
+		gtest.Assert(fields["id"].Extra, "auto_increment")
+		gtest.Assert(fields["id"].Comment, "用户ID")
+		gtest.Assert(fields["passport"].Index, 1)
+		gtest.Assert(fields["passport"].Type, "varchar(45)")
+		gtest.Assert(fields["passport"].Null, false)
+		gtest.Assert(fields["create_time"].Index, 4)
+	})
+
+	gtest.Case(t, func() {
+		_, err := db.TableFields("none_exist_table")
+		gtest.AssertNE(err, nil)
+	})
+}
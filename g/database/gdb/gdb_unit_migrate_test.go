@@ -0,0 +1,98 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g/database/gdb"
+	"github.com/gogf/gf/g/os/gtime"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Migrate(t *testing.T) {
+	table := fmt.Sprintf(`migrations_%d`, gtime.Nanosecond())
+	defer dropTable(table)
+	defer dropTable(table + "_lock")
+	userTable := fmt.Sprintf(`user_%d`, gtime.Nanosecond())
+	defer dropTable(userTable)
+	migrations := []gdb.Migration{
+		{
+			Version: "002",
+			Name:    "add user column",
+			Up:      fmt.Sprintf(`ALTER TABLE %s ADD COLUMN age int(10) NOT NULL DEFAULT 0`, userTable),
+			Down:    fmt.Sprintf(`ALTER TABLE %s DROP COLUMN age`, userTable),
+		},
+		{
+			Version: "001",
+			Name:    "create user table",
+			Up:      fmt.Sprintf(`CREATE TABLE %s (id int(10) unsigned NOT NULL, PRIMARY KEY (id))`, userTable),
+			DownFunc: func(tx *gdb.TX) error {
+				_, err := tx.Exec(fmt.Sprintf(`DROP TABLE %s`, userTable))
+				return err
+			},
+		},
+	}
+	option := gdb.MigrateOption{Table: table}
+	versions := func(list []gdb.Migration) []string {
+		array := make([]string, len(list))
+		for i, m := range list {
+			array[i] = m.Version
+		}
+		return array
+	}
+	gtest.Case(t, func() {
+		option.DryRun = true
+		list, err := gdb.Migrate(db, migrations, option)
+		gtest.Assert(err, nil)
+		gtest.Assert(versions(list), []string{"001", "002"})
+
+		option.DryRun = false
+		list, err = gdb.Migrate(db, migrations, option)
+		gtest.Assert(err, nil)
+		gtest.Assert(versions(list), []string{"001", "002"})
+		count, err := db.Table(userTable).Where("age", 0).Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 0)
+
+		list, err = gdb.Migrate(db, migrations, option)
+		gtest.Assert(err, nil)
+		gtest.Assert(len(list), 0)
+
+		option.Down = true
+		list, err = gdb.Migrate(db, migrations, option)
+		gtest.Assert(err, nil)
+		gtest.Assert(versions(list), []string{"002"})
+		_, err = db.Table(userTable).Where("age", 0).Count()
+		gtest.AssertNE(err, nil)
+
+		option.Steps = 10
+		list, err = gdb.Migrate(db, migrations, option)
+		gtest.Assert(err, nil)
+		gtest.Assert(versions(list), []string{"001"})
+	})
+	gtest.Case(t, func() {
+		_, err := db.Exec(fmt.Sprintf(`INSERT INTO %s_lock(id) VALUES(1)`, table))
+		gtest.Assert(err, nil)
+		_, err = gdb.Migrate(db, migrations, gdb.MigrateOption{Table: table})
+		gtest.Assert(err, gdb.ErrMigrationLocked)
+
+		// 过期的迁移锁被清除
+		_, err = db.Exec(fmt.Sprintf(`UPDATE %s_lock SET locked_at=? WHERE id=1`, table), gtime.Now().Add(-time.Minute).Format("Y-m-d H:i:s"))
+		gtest.Assert(err, nil)
+		_, err = gdb.Migrate(db, migrations, gdb.MigrateOption{Table: table, LockTTL: time.Hour})
+		gtest.Assert(err, gdb.ErrMigrationLocked)
+		list, err := gdb.Migrate(db, migrations, gdb.MigrateOption{Table: table, LockTTL: 30 * time.Second})
+		gtest.Assert(err, nil)
+		gtest.Assert(versions(list), []string{"001", "002"})
+		count, err := db.Table(table + "_lock").Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 0)
+	})
+}
@@ -7,9 +7,14 @@
 package gdb_test
 
 import (
+	"encoding/base64"
+	"fmt"
 	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/database/gdb"
+	"github.com/gogf/gf/g/encoding/gjson"
 	"github.com/gogf/gf/g/os/gtime"
 	"github.com/gogf/gf/g/test/gtest"
+	"strings"
 	"testing"
 )
 
@@ -138,6 +143,30 @@ func TestModel_Batch(t *testing.T) {
 		gtest.Assert(n, INIT_DATA_SIZE*2)
 	})
 
+	// batch save with conflict columns and batch size
+	gtest.Case(t, func() {
+		table := createInitTable()
+		defer dropTable(table)
+		list := g.List{}
+		for i := INIT_DATA_SIZE - 1; i <= INIT_DATA_SIZE+2; i++ {
+			list = append(list, g.Map{
+				"id":          i,
+				"passport":    fmt.Sprintf(`t%d`, i),
+				"password":    "25d55ad283aa400af464c76d713c07ad",
+				"nickname":    fmt.Sprintf(`name_%d`, i),
+				"create_time": gtime.Now().String(),
+			})
+		}
+		_, e := db.Table(table).Data(list).Batch(3).Save("id")
+		gtest.Assert(e, nil)
+		count, e := db.Table(table).Count()
+		gtest.Assert(e, nil)
+		gtest.Assert(count, INIT_DATA_SIZE+2)
+		value, e := db.Table(table).Fields("nickname").Where("id", INIT_DATA_SIZE).Value()
+		gtest.Assert(e, nil)
+		gtest.Assert(value.String(), fmt.Sprintf(`name_%d`, INIT_DATA_SIZE))
+	})
+
 	// batch replace
 	gtest.Case(t, func() {
 		table := createInitTable()
@@ -643,6 +672,82 @@ func TestModel_Where(t *testing.T) {
 	})
 }
 
+func TestModel_WhereBuilder(t *testing.T) {
+	gtest.Case(t, func() {
+		b := gdb.NewWhereBuilder().
+			Where("id", 1).
+			Or(gdb.NewWhereBuilder().Where("id>?", 1).Where("nickname", "T3"))
+		where, args := b.Build()
+		gtest.Assert(where, "(id=?) OR ((id>?) AND (nickname=?))")
+		gtest.Assert(args, g.Slice{1, 1, "T3"})
+
+		result, err := db.Table("user").Where(b).OrderBy("id ASC").All()
+		if err != nil {
+			gtest.Fatal(err)
+		}
+		gtest.Assert(len(result), 2)
+		gtest.Assert(result[0]["id"].Int(), 1)
+		gtest.Assert(result[1]["id"].Int(), 3)
+	})
+	// builder with And/Or
+	gtest.Case(t, func() {
+		b := gdb.NewWhereBuilder().Where("id", 1).Or("id", 3)
+		s, args := db.Table("user").Where(b).And("nickname", "T3").ToSQL()
+		gtest.Assert(s, "SELECT * FROM user WHERE ((id=?) OR (id=?)) AND (nickname=?)")
+		gtest.Assert(args, g.Slice{1, 3, "T3"})
+		result, err := db.Table("user").Where(b).And("nickname", "T3").All()
+		if err != nil {
+			gtest.Fatal(err)
+		}
+		gtest.Assert(len(result), 1)
+		gtest.Assert(result[0]["id"].Int(), 3)
+
+		s, _ = db.Table("user").Where("id", 1).Or("id", 3).And("nickname", "T3").ToSQL()
+		gtest.Assert(s, "SELECT * FROM user WHERE ((id=?) OR (id=?)) AND (nickname=?)")
+		count, err := db.Table("user").Where("id", 1).Or("id", 3).And("nickname", "T3").Count()
+		if err != nil {
+			gtest.Fatal(err)
+		}
+		gtest.Assert(count, 1)
+	})
+	// NOT/IN/raw
+	gtest.Case(t, func() {
+		b := gdb.NewWhereBuilder().
+			In("id", g.Slice{1, 2, 3}).
+			Not("nickname", "T2").
+			Raw("passport<>? AND id<>?", "t4", 4)
+		where, args := b.Build()
+		gtest.Assert(where, "(id IN(?,?,?)) AND (NOT (nickname=?)) AND (passport<>? AND id<>?)")
+		gtest.Assert(args, g.Slice{1, 2, 3, "T2", "t4", 4})
+
+		result, err := db.Table("user").Where(b).OrderBy("id ASC").All()
+		if err != nil {
+			gtest.Fatal(err)
+		}
+		gtest.Assert(len(result), 2)
+		gtest.Assert(result[0]["id"].Int(), 1)
+		gtest.Assert(result[1]["id"].Int(), 3)
+	})
+	// empty slice of IN
+	gtest.Case(t, func() {
+		where, _ := gdb.NewWhereBuilder().In("id", g.Slice{}).NotIn("id", g.Slice{}).Build()
+		gtest.Assert(where, "(0=1) AND (1=1)")
+		where, _ = gdb.NewWhereBuilder().SetInEmpty(gdb.WHERE_IN_EMPTY_IGNORE).In("id", g.Slice{}).Build()
+		gtest.Assert(where, "")
+
+		count, err := db.Table("user").Where(gdb.NewWhereBuilder().In("id", g.Slice{})).Count()
+		if err != nil {
+			gtest.Fatal(err)
+		}
+		gtest.Assert(count, 0)
+		count, err = db.Table("user").Where(gdb.NewWhereBuilder().SetInEmpty(gdb.WHERE_IN_EMPTY_IGNORE).In("id", g.Slice{})).Count()
+		if err != nil {
+			gtest.Fatal(err)
+		}
+		gtest.Assert(count, 3)
+	})
+}
+
 func TestModel_Delete(t *testing.T) {
 	result, err := db.Table("user").Delete()
 	if err != nil {
@@ -651,3 +756,786 @@ func TestModel_Delete(t *testing.T) {
 	n, _ := result.RowsAffected()
 	gtest.Assert(n, 3)
 }
+
+func TestModel_TableFields(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		data := func(id int) g.Map {
+			return g.Map{
+				"id":          id,
+				"passport":    "t1",
+				"password":    "p1",
+				"nickname":    "T1",
+				"create_time": gtime.Now().String(),
+				"remark":      "remark",
+			}
+		}
+		_, err := db.Table(table).Filter().Data(data(1)).Insert()
+		gtest.Assert(err, nil)
+
+		// The table fields are cached.
+		_, err = db.Exec("ALTER TABLE " + table + " ADD remark varchar(45) NULL")
+		gtest.Assert(err, nil)
+		_, err = db.Table(table).Filter().Data(data(2)).Insert()
+		gtest.Assert(err, nil)
+		value, err := db.Table(table).Fields("remark").Where("id", 2).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "")
+
+		// Manual refresh.
+		db.ClearTableFields(table)
+		_, err = db.Table(table).Filter().Data(data(3)).Insert()
+		gtest.Assert(err, nil)
+		value, err = db.Table(table).Fields("remark").Where("id", 3).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "remark")
+
+		// Automatic refresh after unknown column error.
+		_, err = db.Exec("ALTER TABLE " + table + " DROP remark")
+		gtest.Assert(err, nil)
+		_, err = db.Table(table).Filter().Data(data(4)).Insert()
+		gtest.AssertNE(err, nil)
+		_, err = db.Table(table).Filter().Data(data(4)).Insert()
+		gtest.Assert(err, nil)
+
+		// Automatic refresh and retry of query built with the table fields.
+		_, err = db.Exec("ALTER TABLE " + table + " ADD remark varchar(45) NULL")
+		gtest.Assert(err, nil)
+		db.ClearTableFields(table)
+		one, err := db.Table(table).FieldsEx("password").Where("id", 4).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["nickname"].String(), "T1")
+		_, err = db.Exec("ALTER TABLE " + table + " DROP remark")
+		gtest.Assert(err, nil)
+		one, err = db.Table(table).FieldsEx("password").Where("id", 4).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["nickname"].String(), "T1")
+		gtest.Assert(len(one), 4)
+	})
+}
+
+func TestModel_JoinAggregate(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		result, err := db.Table(table+" u1").
+			LeftJoinOnFields(table+" u2", "id", "=", "id").
+			Fields("u1.id,u1.nickname,u2.passport").
+			Where("u1.id<=?", 2).
+			OrderBy("u1.id").
+			Select()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(result), 2)
+		gtest.Assert(result[1]["passport"].String(), "t2")
+
+		result, err = db.Table(table).
+			Fields("id%2 AS odd,COUNT(1) AS total").
+			Where("id<=?", 5).
+			GroupBy("odd").
+			Having("COUNT(1)>?", 2).
+			Select()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(result), 1)
+		gtest.Assert(result[0]["odd"].Int(), 1)
+		gtest.Assert(result[0]["total"].Int(), 3)
+
+		count, err := db.Table(table).CountColumn("DISTINCT passport")
+		gtest.Assert(err, nil)
+		gtest.Assert(count, INIT_DATA_SIZE)
+		sum, err := db.Table(table).Where("id<=?", 4).SumFloat("id")
+		gtest.Assert(err, nil)
+		gtest.Assert(sum, 10)
+		sum, err = db.Table(table).Where("id<0").SumFloat("id")
+		gtest.Assert(err, nil)
+		gtest.Assert(sum, 0)
+		avg, err := db.Table(table).Where("id<=?", 4).AvgDecimal("id")
+		gtest.Assert(err, nil)
+		gtest.Assert(avg, "2.5000")
+	})
+
+	gtest.Case(t, func() {
+		type User struct {
+			Id       int
+			Nickname string
+		}
+		type Detail struct {
+			Uid      int
+			Passport string
+		}
+		type Entity struct {
+			User    *User
+			Detail  Detail
+			Friends []*User
+		}
+		model := db.Table(table+" u1").
+			InnerJoinOnFields(table+" u2", "id", "=", "id").
+			Fields("u1.id,u1.nickname,u2.id AS uid,u2.passport").
+			Where("u1.id<=?", 2).
+			OrderBy("u1.id")
+		var entities []*Entity
+		gtest.Assert(model.ScanList(&entities, "User"), nil)
+		gtest.Assert(model.ScanList(&entities, "Detail"), nil)
+		gtest.Assert(len(entities), 2)
+		gtest.Assert(entities[0].User.Nickname, "T1")
+		gtest.Assert(entities[1].Detail.Uid, 2)
+		gtest.Assert(entities[1].Detail.Passport, "t2")
+
+		err := db.Table(table).Fields("id,nickname").Where("id IN(?)", g.Slice{1, 2}).
+			ScanList(&entities, "Friends", "User", "id:Id")
+		gtest.Assert(err, nil)
+		gtest.Assert(len(entities[0].Friends), 1)
+		gtest.Assert(entities[0].Friends[0].Nickname, "T1")
+		gtest.Assert(entities[1].Friends[0].Nickname, "T2")
+	})
+}
+
+func TestModel_TimeFields(t *testing.T) {
+	table := fmt.Sprintf(`time_%d`, gtime.Nanosecond())
+	if _, err := db.Exec(fmt.Sprintf(`
+    CREATE TABLE %s (
+        id int(10) unsigned NOT NULL AUTO_INCREMENT,
+        name varchar(45) NOT NULL,
+        created_at datetime DEFAULT NULL,
+        updated_at int(10) unsigned NOT NULL DEFAULT 0,
+        deleted_at datetime DEFAULT NULL,
+        PRIMARY KEY (id)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Fatal(err)
+	}
+	defer dropTable(table)
+
+	// 默认不启用时间字段特性
+	gtest.Case(t, func() {
+		gtest.Assert(db.GetTimeFields(), gdb.TimeFields{})
+		_, err := db.Table(table).Data(g.Map{"id": 9, "name": "none"}).Insert()
+		gtest.Assert(err, nil)
+		one, err := db.Table(table).Where("id=?", 9).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["created_at"].String(), "")
+		gtest.Assert(one["updated_at"].Int(), 0)
+		_, err = db.Table(table).Where("id=?", 9).Delete()
+		gtest.Assert(err, nil)
+		count, err := db.Table(table).Unscoped().Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 0)
+	})
+
+	db.SetTimeFields(gdb.TimeFields{
+		Created: "created_at",
+		Updated: "updated_at",
+		Deleted: "deleted_at",
+	})
+	defer db.SetTimeFields(gdb.TimeFields{})
+
+	gtest.Case(t, func() {
+		_, err := db.Table(table).Data(g.Map{"id": 1, "name": "john"}).Insert()
+		gtest.Assert(err, nil)
+		_, err = db.Table(table).Data(g.List{{"id": 2, "name": "smith"}, {"id": 3, "name": "jack"}}).Insert()
+		gtest.Assert(err, nil)
+		one, err := db.Table(table).Where("id=?", 1).One()
+		gtest.Assert(err, nil)
+		gtest.AssertNE(one["created_at"].String(), "")
+		gtest.AssertGT(one["updated_at"].Int(), 0)
+
+		_, err = db.Table(table).Data(g.Map{"updated_at": 1}).Where("id=?", 1).Update()
+		gtest.Assert(err, nil)
+		_, err = db.Table(table).Data(g.Map{"name": "john2"}).Where("id=?", 1).Update()
+		gtest.Assert(err, nil)
+		value, err := db.Table(table).Fields("updated_at").Where("id=?", 1).Value()
+		gtest.Assert(err, nil)
+		gtest.AssertGT(value.Int(), 1)
+
+		// 软删除
+		_, err = db.Table(table).Where("id=?", 1).Delete()
+		gtest.Assert(err, nil)
+		count, err := db.Table(table).Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 2)
+		one, err = db.Table(table).Where("id=?", 1).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(one), 0)
+		one, err = db.Table(table).Unscoped().Where("id=?", 1).One()
+		gtest.Assert(err, nil)
+		gtest.AssertNE(one["deleted_at"].String(), "")
+
+		// 直接删除
+		_, err = db.Table(table).Unscoped().Where("id=?", 2).Delete()
+		gtest.Assert(err, nil)
+		count, err = db.Table(table).Unscoped().Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 2)
+	})
+
+	// Save写入新记录时写入创建时间，更新已存在的记录时保持原有的创建时间
+	gtest.Case(t, func() {
+		_, err := db.Table(table).Data(g.Map{"id": 4, "name": "rose"}).Save()
+		gtest.Assert(err, nil)
+		one, err := db.Table(table).Where("id=?", 4).One()
+		gtest.Assert(err, nil)
+		gtest.AssertNE(one["created_at"].String(), "")
+		gtest.AssertGT(one["updated_at"].Int(), 0)
+
+		_, err = db.Table(table).Data(g.Map{"created_at": "2000-01-01 00:00:00", "updated_at": 1}).Where("id=?", 4).Update()
+		gtest.Assert(err, nil)
+		_, err = db.Table(table).Data(g.List{{"id": 4, "name": "rose2"}, {"id": 5, "name": "lily"}}).Save()
+		gtest.Assert(err, nil)
+		one, err = db.Table(table).Where("id=?", 4).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["name"].String(), "rose2")
+		gtest.Assert(one["created_at"].String(), "2000-01-01 00:00:00")
+		gtest.AssertGT(one["updated_at"].Int(), 1)
+		one, err = db.Table(table).Where("id=?", 5).One()
+		gtest.Assert(err, nil)
+		gtest.AssertNE(one["created_at"].String(), "")
+
+		// 指定的创建时间会被更新
+		_, err = db.Table(table).Data(g.Map{"id": 4, "name": "rose3", "created_at": "2001-01-01 00:00:00"}).Save()
+		gtest.Assert(err, nil)
+		value, err := db.Table(table).Fields("created_at").Where("id=?", 4).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "2001-01-01 00:00:00")
+	})
+}
+
+func TestModel_OptimisticLock(t *testing.T) {
+	table := fmt.Sprintf(`version_%d`, gtime.Nanosecond())
+	if _, err := db.Exec(fmt.Sprintf(`
+    CREATE TABLE %s (
+        id int(10) unsigned NOT NULL AUTO_INCREMENT,
+        name varchar(45) NOT NULL,
+        version int(10) unsigned NOT NULL DEFAULT 0,
+        PRIMARY KEY (id)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Fatal(err)
+	}
+	defer dropTable(table)
+
+	// 默认不启用时间字段特性
+	gtest.Case(t, func() {
+		gtest.Assert(db.GetTimeFields(), gdb.TimeFields{})
+		_, err := db.Table(table).Data(g.Map{"id": 9, "name": "none"}).Insert()
+		gtest.Assert(err, nil)
+		one, err := db.Table(table).Where("id=?", 9).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["created_at"].String(), "")
+		gtest.Assert(one["updated_at"].Int(), 0)
+		_, err = db.Table(table).Where("id=?", 9).Delete()
+		gtest.Assert(err, nil)
+		count, err := db.Table(table).Unscoped().Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 0)
+	})
+
+	db.SetTimeFields(gdb.TimeFields{
+		Created: "created_at",
+		Updated: "updated_at",
+		Deleted: "deleted_at",
+	})
+	defer db.SetTimeFields(gdb.TimeFields{})
+
+	gtest.Case(t, func() {
+		_, err := db.Table(table).Data(g.Map{"id": 1, "name": "john"}).Insert()
+		gtest.Assert(err, nil)
+		one, err := db.Table(table).Where("id=?", 1).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["version"].Int(), 0)
+
+		_, err = db.Table(table).Data(g.Map{"name": "john2", "version": one["version"].Int()}).Where("id=?", 1).Update()
+		gtest.Assert(err, nil)
+		value, err := db.Table(table).Fields("version").Where("id=?", 1).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.Int(), 1)
+
+		// 使用过期的版本号更新
+		_, err = db.Table(table).Data(g.Map{"name": "john3", "version": one["version"].Int()}).Where("id=?", 1).Update()
+		lockErr, ok := err.(*gdb.OptimisticLockError)
+		gtest.Assert(ok, true)
+		gtest.Assert(lockErr.Table, table)
+		value, err = db.Table(table).Fields("name").Where("id=?", 1).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "john2")
+
+		// 更新数据不包含版本字段时不使用乐观锁
+		_, err = db.Table(table).Data(g.Map{"name": "john3"}).Where("id=?", 1).Update()
+		gtest.Assert(err, nil)
+	})
+}
+
+func TestModel_Cache(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		one, err := db.Table(table).Cache(10).Where("id=?", 1).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["passport"].String(), "t1")
+
+		// 直接修改数据表，缓存的查询结果不变
+		_, err = db.Exec(fmt.Sprintf("UPDATE %s SET passport=? WHERE id=?", table), "cached", 1)
+		gtest.Assert(err, nil)
+		one, err = db.Table(table).Cache(10).Where("id=?", 1).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["passport"].String(), "t1")
+
+		// 通过链式操作更新数据表时清除关联的查询缓存
+		_, err = db.Table(table).Data(g.Map{"passport": "updated"}).Where("id=?", 1).Update()
+		gtest.Assert(err, nil)
+		one, err = db.Table(table).Cache(10).Where("id=?", 1).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["passport"].String(), "updated")
+
+		// 指定缓存名称并清除
+		value, err := db.Table(table).Cache(10, "passport").Fields("passport").Where("id=?", 2).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "t2")
+		_, err = db.Exec(fmt.Sprintf("UPDATE %s SET passport=? WHERE id=?", table), "named", 2)
+		gtest.Assert(err, nil)
+		value, err = db.Table(table).Cache(10, "passport").Fields("passport").Where("id=?", 2).Value()
+		gtest.Assert(value.String(), "t2")
+		value, err = db.Table(table).Cache(-1, "passport").Fields("passport").Where("id=?", 2).Value()
+		gtest.Assert(value.String(), "named")
+	})
+}
+
+func TestModel_DryRun(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		s, args := db.Table(table).Where("id>?", 1).OrderBy("id desc").Limit(0, 2).ToSQL()
+		gtest.Assert(s, fmt.Sprintf("SELECT * FROM %s WHERE id>? ORDER BY id desc LIMIT 0, 2", table))
+		gtest.Assert(args, g.Slice{1})
+
+		// 空跑模式下不执行写入操作
+		model := db.Table(table).DryRun().Data(g.Map{"passport": "dry"}).Where("id=?", 1)
+		result, err := model.Update()
+		gtest.Assert(err, nil)
+		n, _ := result.RowsAffected()
+		gtest.Assert(n, 0)
+		s, args = model.ToSQL()
+		gtest.Assert(s, fmt.Sprintf("UPDATE %s SET `passport`=? WHERE id=?", table))
+		gtest.Assert(args, g.Slice{"dry", 1})
+		value, err := db.Table(table).Fields("passport").Where("id=?", 1).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "t1")
+
+		model = db.Table(table).DryRun().Where("id=?", 2)
+		_, err = model.Delete()
+		gtest.Assert(err, nil)
+		s, args = model.ToSQL()
+		gtest.Assert(s, fmt.Sprintf("DELETE FROM %s WHERE id=?", table))
+		gtest.Assert(args, g.Slice{2})
+		count, err := db.Table(table).Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, INIT_DATA_SIZE)
+
+		// 批量写入生成多条SQL语句
+		model = db.Table(table).DryRun().Data(g.List{
+			{"id": 100, "passport": "t100"},
+			{"id": 101, "passport": "t101"},
+			{"id": 102, "passport": "t102"},
+		}).Batch(2)
+		_, err = model.Insert()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(model.GetDryRunSqls()), 2)
+		count, err = db.Table(table).Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, INIT_DATA_SIZE)
+
+		// 空跑模式下查询返回空结果
+		all, err := db.Table(table).DryRun().All()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(all), 0)
+	})
+}
+
+func TestModel_Shard(t *testing.T) {
+	table0 := createTable()
+	table1 := createTable()
+	defer dropTable(table0)
+	defer dropTable(table1)
+	shardTable := func(md *gdb.Model, key interface{}) string {
+		if key.(int)%2 == 0 {
+			return table0
+		}
+		return table1
+	}
+
+	gtest.Case(t, func() {
+		for i := 1; i <= 4; i++ {
+			_, err := db.Table("user").ShardTable(shardTable).Shard(i).Data(g.Map{
+				"id":       i,
+				"passport": fmt.Sprintf("t%d", i),
+				"nickname": fmt.Sprintf("T%d", i),
+			}).Insert()
+			gtest.Assert(err, nil)
+		}
+		count, err := db.Table(table0).Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 2)
+
+		one, err := db.Table("user").ShardTable(shardTable).Shard(3).Where("id=?", 3).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["passport"].String(), "t3")
+
+		_, err = db.Table("user").ShardTable(shardTable).Shard(3).Data("nickname='shard'").Where("id=?", 3).Update()
+		gtest.Assert(err, nil)
+		value, err := db.Table(table1).Fields("nickname").Where("id=?", 3).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "shard")
+
+		// 跨分片查询及聚合
+		all, err := db.Table("user").ShardTable(shardTable).Shard(1, 2, 3).OrderBy("id").All()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(all), 4)
+		count, err = db.Table("user").ShardTable(shardTable).Shard(1, 2).Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 4)
+		sum, err := db.Table("user").ShardTable(shardTable).Shard(1, 2).SumFloat("id")
+		gtest.Assert(err, nil)
+		gtest.Assert(sum, 10)
+
+		_, err = db.Table("user").ShardTable(shardTable).Shard(1, 2).Delete()
+		gtest.AssertNE(err, nil)
+		_, err = db.Table("user").ShardTable(shardTable).All()
+		gtest.AssertNE(err, nil)
+	})
+}
+
+func TestModel_IterateRows(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		ids := make([]int, 0)
+		err := db.Table(table).Where("id>?", 5).OrderBy("id").IterateRows(func(record gdb.Record) bool {
+			ids = append(ids, record["id"].Int())
+			return true
+		})
+		gtest.Assert(err, nil)
+		gtest.Assert(ids, g.Slice{6, 7, 8, 9, 10})
+
+		// 回调函数返回false时停止读取
+		ids = ids[:0]
+		err = db.Table(table).OrderBy("id").IterateRows(func(record gdb.Record) bool {
+			ids = append(ids, record["id"].Int())
+			return len(ids) < 3
+		})
+		gtest.Assert(err, nil)
+		gtest.Assert(ids, g.Slice{1, 2, 3})
+
+		// 事务中读取
+		tx, err := db.Begin()
+		gtest.Assert(err, nil)
+		defer tx.Rollback()
+		count := 0
+		err = tx.Table(table).IterateRows(func(record gdb.Record) bool {
+			count++
+			return true
+		})
+		gtest.Assert(err, nil)
+		gtest.Assert(count, INIT_DATA_SIZE)
+	})
+}
+
+func TestModel_FieldsEx(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		s, _ := db.Table(table).FieldsEx("password,create_time").ToSQL()
+		gtest.Assert(s, fmt.Sprintf("SELECT `id`,`nickname`,`passport` FROM %s", table))
+
+		// 写入/更新时忽略排除字段
+		model := db.Table(table).DryRun().FieldsEx("password").Data(g.Map{"nickname": "n1", "password": "p1"}).Where("id=?", 1)
+		_, err := model.Update()
+		gtest.Assert(err, nil)
+		s, args := model.ToSQL()
+		gtest.Assert(s, fmt.Sprintf("UPDATE %s SET `nickname`=? WHERE id=?", table))
+		gtest.Assert(args, g.Slice{"n1", 1})
+
+		// Fields指定允许写入的字段
+		model = db.Table(table).DryRun().Fields("nickname").Data(g.Map{"nickname": "n1", "passport": "p1"}).Where("id=?", 1)
+		_, err = model.Update()
+		gtest.Assert(err, nil)
+		s, _ = model.ToSQL()
+		gtest.Assert(s, fmt.Sprintf("UPDATE %s SET `nickname`=? WHERE id=?", table))
+	})
+}
+
+func TestModel_Strict(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.Case(t, func() {
+		_, err := db.Table(table).Strict().Data(g.Map{"nickname": "n1", "is_admin": 1}).Where("id=?", 1).Update()
+		gtest.AssertNE(err, nil)
+		_, err = db.Table(table).Strict().FieldsEx("password").Data(g.Map{"nickname": "n1", "password": "p1"}).Where("id=?", 1).Update()
+		gtest.AssertNE(err, nil)
+		_, err = db.Table(table).Strict().Data(g.Map{"nickname": "n1"}).Where("id=?", 1).Update()
+		gtest.Assert(err, nil)
+		value, err := db.Table(table).Fields("nickname").Where("id=?", 1).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "n1")
+	})
+
+	// 结构体标签定义允许写入/更新的字段
+	type User struct {
+		Id         int    `json:"id" orm:"insert"`
+		Passport   string `json:"passport" orm:"insert"`
+		Password   string `json:"password" orm:"insert,update"`
+		Nickname   string `json:"nickname" orm:"insert,update"`
+		IsAdmin    int    `json:"is_admin" orm:"-"`
+		CreateTime string `json:"create_time" orm:"insert"`
+	}
+	gtest.Case(t, func() {
+		user := User{Id: 100, Passport: "t100", Password: "p100", Nickname: "n100", IsAdmin: 1, CreateTime: gtime.Now().String()}
+		_, err := db.Table(table).Strict().Data(user).Insert()
+		gtest.AssertNE(err, nil)
+		_, err = db.Table(table).Data(user).Insert()
+		gtest.Assert(err, nil)
+
+		model := db.Table(table).DryRun().Data(user).Where("id=?", 100)
+		_, err = model.Update()
+		gtest.Assert(err, nil)
+		s, _ := model.ToSQL()
+		gtest.Assert(strings.Contains(s, "`passport`"), false)
+		gtest.Assert(strings.Contains(s, "`is_admin`"), false)
+		gtest.Assert(strings.Contains(s, "`nickname`"), true)
+	})
+}
+
+func TestModel_Encrypt(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+	db.SetEncryptKey("0123456789abcdef")
+	defer db.SetEncryptKey("")
+
+	type User struct {
+		Id         int    `json:"id"`
+		Passport   string `json:"passport"`
+		Password   string `json:"password"`
+		Nickname   string `json:"nickname" orm:"encrypt:deterministic"`
+		CreateTime string `json:"create_time"`
+	}
+	gtest.Case(t, func() {
+		user := User{Id: 100, Passport: "t100", Password: "p100", Nickname: "n100", CreateTime: gtime.Now().String()}
+		_, err := db.Table(table).Data(user).Insert()
+		gtest.Assert(err, nil)
+
+		// 数据表中保存的是密文
+		value, err := db.Table(table).Fields("nickname").Where("id=?", 100).Value()
+		gtest.Assert(err, nil)
+		gtest.AssertNE(value.String(), "n100")
+		gtest.Assert(value.String(), db.MustEncrypt("n100"))
+
+		// 查询结果转换为struct时解密
+		var u *User
+		err = db.Table(table).Where("nickname", db.MustEncrypt("n100")).Struct(&u)
+		gtest.Assert(err, nil)
+		gtest.Assert(u.Id, 100)
+		gtest.Assert(u.Nickname, "n100")
+
+		var users []User
+		err = db.Table(table).Where("id>=?", 100).Structs(&users)
+		gtest.Assert(err, nil)
+		gtest.Assert(len(users), 1)
+		gtest.Assert(users[0].Nickname, "n100")
+
+		// 确定性加密模式相同的明文加密结果相同，不同的明文加密结果不同
+		gtest.Assert(db.MustEncrypt("n100"), db.MustEncrypt("n100"))
+		gtest.AssertNE(db.MustEncrypt("n101"), db.MustEncrypt("n100"))
+
+		// 密文被篡改时解密失败
+		data, _ := base64.StdEncoding.DecodeString(value.String())
+		data[len(data)-1] ^= 1
+		_, err = db.Table(table).Data("nickname", base64.StdEncoding.EncodeToString(data)).Where("id", 100).Update()
+		gtest.Assert(err, nil)
+		err = db.Table(table).Where("id", 100).Struct(&u)
+		gtest.AssertNE(err, nil)
+	})
+}
+
+func TestModel_IdGenerator(t *testing.T) {
+	table := fmt.Sprintf(`id_%d`, gtime.Nanosecond())
+	if _, err := db.Exec(fmt.Sprintf(
+		`CREATE TABLE %s (id varchar(36) NOT NULL, name varchar(45) NOT NULL, PRIMARY KEY (id))`, table,
+	)); err != nil {
+		gtest.Fatal(err)
+	}
+	defer dropTable(table)
+	defer db.SetIdGenerator("")
+
+	gtest.Case(t, func() {
+		db.SetIdGenerator(gdb.ID_GENERATOR_UUID_V7)
+		id, err := db.Table(table).Data(g.Map{"name": "john"}).InsertAndGetId()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(id.String()), 36)
+		gtest.Assert(id.String()[14:15], "7")
+		value, err := db.Table(table).Fields("name").Where("id", id.String()).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "john")
+
+		// 已存在的主键值不会被覆盖
+		id, err = db.Table(table).Data(g.Map{"id": "custom", "name": "smith"}).InsertAndGetId()
+		gtest.Assert(err, nil)
+		n, err := db.Table(table).Where("id", "custom").Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 1)
+	})
+
+	gtest.Case(t, func() {
+		db.SetIdGenerator(gdb.ID_GENERATOR_SNOWFLAKE, 1)
+		id1, err := db.GenerateId()
+		gtest.Assert(err, nil)
+		id2, err := db.GenerateId()
+		gtest.Assert(err, nil)
+		gtest.Assert(id2.(int64) > id1.(int64), true)
+		gtest.Assert(id1.(int64)>>12&1023, 1)
+
+		result, err := db.Table(table).Data(g.Map{"name": "tom"}).Insert()
+		gtest.Assert(err, nil)
+		id, err := result.LastInsertId()
+		gtest.Assert(err, nil)
+		gtest.Assert(id > id2.(int64), true)
+
+		db.SetIdGenerator(gdb.ID_GENERATOR_SNOWFLAKE, 1024)
+		_, err = db.GenerateId()
+		gtest.AssertNE(err, nil)
+	})
+
+	// 自增主键不生成主键值
+	gtest.Case(t, func() {
+		table := createTable()
+		defer dropTable(table)
+		db.SetIdGenerator(gdb.ID_GENERATOR_UUID_V4)
+		id, err := db.Table(table).Data(g.Map{
+			"passport":    "t1",
+			"password":    "p1",
+			"nickname":    "n1",
+			"create_time": gtime.Now().String(),
+		}).InsertAndGetId()
+		gtest.Assert(err, nil)
+		gtest.Assert(id.Int(), 1)
+	})
+}
+
+func TestModel_Returning(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+	db.SetIdGenerator("")
+
+	gtest.Case(t, func() {
+		result, err := db.Table(table).Returning("id", "nickname").Data(g.Map{
+			"passport":    "t1",
+			"password":    "p1",
+			"nickname":    "n1",
+			"create_time": gtime.Now().String(),
+		}).Insert()
+		gtest.Assert(err, nil)
+		record := result.(*gdb.InsertResult).LastInsertedRecord()
+		gtest.Assert(record["id"].Int(), 1)
+		gtest.Assert(record["nickname"].String(), "n1")
+		id, err := result.LastInsertId()
+		gtest.Assert(err, nil)
+		gtest.Assert(id, 1)
+	})
+
+	// 批量写入，包括指定了主键值的数据
+	gtest.Case(t, func() {
+		ids, err := db.Table(table).Data(g.List{
+			{"passport": "t2", "password": "p2", "nickname": "n2", "create_time": gtime.Now().String()},
+			{"passport": "t3", "password": "p3", "nickname": "n3", "create_time": gtime.Now().String()},
+			{"passport": "t4", "password": "p4", "nickname": "n4", "create_time": gtime.Now().String()},
+		}).Batch(2).InsertAndGetIds()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(ids), 3)
+		gtest.Assert(ids[0].Int(), 2)
+		gtest.Assert(ids[1].Int(), 3)
+		gtest.Assert(ids[2].Int(), 4)
+
+		result, err := db.Table(table).Returning("id", "passport").Data(g.List{
+			{"id": 10, "passport": "t10", "password": "p10", "nickname": "n10", "create_time": gtime.Now().String()},
+			{"id": 11, "passport": "t11", "password": "p11", "nickname": "n11", "create_time": gtime.Now().String()},
+		}).Insert()
+		gtest.Assert(err, nil)
+		records := result.(*gdb.InsertResult).InsertedRecords()
+		gtest.Assert(len(records), 2)
+		gtest.Assert(records[0]["passport"].String(), "t10")
+		gtest.Assert(records[1]["id"].Int(), 11)
+		n, err := result.RowsAffected()
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 2)
+	})
+}
+
+func TestModel_Json(t *testing.T) {
+	table := fmt.Sprintf(`json_%d`, gtime.Nanosecond())
+	if _, err := db.Exec(fmt.Sprintf(`
+    CREATE TABLE %s (
+        id int(10) unsigned NOT NULL AUTO_INCREMENT,
+        tags json,
+        profile json,
+        extra json,
+        PRIMARY KEY (id)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Fatal(err)
+	}
+	defer dropTable(table)
+
+	type Profile struct {
+		City string `json:"city"`
+		Age  int    `json:"age"`
+	}
+	type User struct {
+		Id      int
+		Tags    []string
+		Profile Profile `orm:"json"`
+		Extra   *gjson.Json
+	}
+	gtest.Case(t, func() {
+		user := User{
+			Id:      1,
+			Tags:    []string{"vip", "new"},
+			Profile: Profile{City: "chengdu", Age: 18},
+			Extra:   gjson.New(g.Map{"score": 100}),
+		}
+		_, err := db.Table(table).Data(user).Insert()
+		gtest.Assert(err, nil)
+		_, err = db.Table(table).Data(g.Map{
+			"id":      2,
+			"tags":    []string{"new"},
+			"profile": g.Map{"city": "beijing", "age": 20},
+		}).Insert()
+		gtest.Assert(err, nil)
+
+		var u *User
+		err = db.Table(table).Where("id", 1).Struct(&u)
+		gtest.Assert(err, nil)
+		gtest.Assert(u.Tags, []string{"vip", "new"})
+		gtest.Assert(u.Profile.City, "chengdu")
+		gtest.Assert(u.Profile.Age, 18)
+		gtest.Assert(u.Extra.GetInt("score"), 100)
+
+		var users []*User
+		err = db.Table(table).WhereJsonContains("tags", "new").OrderBy("id").Structs(&users)
+		gtest.Assert(err, nil)
+		gtest.Assert(len(users), 2)
+		gtest.Assert(users[1].Profile.City, "beijing")
+		gtest.Assert(users[1].Extra, nil)
+
+		count, err := db.Table(table).WhereJsonContains("tags", []string{"vip"}).Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 1)
+
+		count, err = db.Table(table).WhereJsonContains("profile", "beijing", "$.city").Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 1)
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/database/gdb"
+	"github.com/gogf/gf/g/os/gtime"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Saga(t *testing.T) {
+	table := fmt.Sprintf(`saga_%d`, gtime.Nanosecond())
+	defer dropTable(table)
+	store, err := gdb.NewSagaStore(db, table)
+	if err != nil {
+		gtest.Fatal(err)
+	}
+	logs := make([]string, 0)
+	newSaga := func(failStep string) *gdb.Saga {
+		saga := gdb.NewSaga("order", store)
+		for _, name := range []string{"a", "b", "c"} {
+			name := name
+			saga.Step(name, func(id string, data gdb.Map) error {
+				logs = append(logs, "+"+name)
+				if name == failStep {
+					return errors.New("failed")
+				}
+				data[name] = 1
+				return nil
+			}, func(id string, data gdb.Map) error {
+				logs = append(logs, "-"+name)
+				return nil
+			})
+		}
+		return saga
+	}
+	// Success.
+	gtest.Case(t, func() {
+		logs = logs[:0]
+		id, err := newSaga("").Execute(g.Map{"uid": 1})
+		gtest.Assert(err, nil)
+		gtest.AssertNE(id, "")
+		gtest.Assert(logs, []string{"+a", "+b", "+c"})
+		value, err := db.Table(table).Fields("status").Where("id", id).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), gdb.SAGA_STATUS_DONE)
+	})
+	// Compensation of the executed steps and the failed step.
+	gtest.Case(t, func() {
+		logs = logs[:0]
+		id, err := newSaga("b").Execute(nil)
+		gtest.AssertNE(err, nil)
+		gtest.Assert(logs, []string{"+a", "+b", "-b", "-a"})
+		one, err := db.Table(table).Where("id", id).One()
+		gtest.Assert(err, nil)
+		gtest.Assert(one["status"].String(), gdb.SAGA_STATUS_COMPENSATED)
+		gtest.Assert(one["step"].Int(), 0)
+	})
+	// Recovering after crash.
+	gtest.Case(t, func() {
+		logs = logs[:0]
+		saga := newSaga("")
+		state := &gdb.SagaState{
+			Id:         "crashed",
+			Name:       "order",
+			Status:     gdb.SAGA_STATUS_RUNNING,
+			Step:       1,
+			Data:       g.Map{"a": 1},
+			UpdateTime: time.Now().Add(-time.Minute),
+		}
+		gtest.Assert(store.SaveSagaState(state), nil)
+		n, err := saga.Recover(time.Hour)
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 0)
+		n, err = saga.Recover(time.Second)
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 1)
+		gtest.Assert(logs, []string{"-b", "-a"})
+		n, err = saga.Recover(0)
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 0)
+		// The state of other process is not overwritten.
+		state.Status = gdb.SAGA_STATUS_RUNNING
+		gtest.Assert(store.SaveSagaState(state), gdb.ErrSagaConflict)
+	})
+}
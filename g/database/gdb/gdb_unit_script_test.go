@@ -0,0 +1,93 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/g/database/gdb"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func TestSplitSqlScript(t *testing.T) {
+	gtest.Case(t, func() {
+		statements, err := gdb.SplitSqlScript(`
+-- 注释;
+# 注释;
+INSERT INTO user(name) VALUES('a;b'), ("c\";d"), ('e'';f');
+/* 注释; */ SELECT * FROM ` + "`a;b`" + `;
+SELECT /*+ MAX_EXECUTION_TIME(1000) */ 1 -- 注释
+;
+`)
+		gtest.Assert(err, nil)
+		gtest.Assert(len(statements), 3)
+		gtest.Assert(statements[0], `INSERT INTO user(name) VALUES('a;b'), ("c\";d"), ('e'';f')`)
+		gtest.Assert(statements[1], "SELECT * FROM `a;b`")
+		gtest.Assert(statements[2], "SELECT /*+ MAX_EXECUTION_TIME(1000) */ 1")
+	})
+	gtest.Case(t, func() {
+		statements, err := gdb.SplitSqlScript(`
+DROP PROCEDURE IF EXISTS p;
+DELIMITER $$
+CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END$$
+DELIMITER ;
+CALL p();`)
+		gtest.Assert(err, nil)
+		gtest.Assert(len(statements), 3)
+		gtest.Assert(statements[1], "CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END")
+		gtest.Assert(statements[2], "CALL p()")
+	})
+	gtest.Case(t, func() {
+		statements, err := gdb.SplitSqlScript(`
+CREATE TRIGGER t AFTER INSERT ON a FOR EACH ROW
+BEGIN
+  IF NEW.id > 0 THEN UPDATE b SET n=n+1; END IF;
+  UPDATE c SET n = CASE WHEN n > 0 THEN 1 ELSE 0 END;
+END;
+CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql;
+SELECT $1;`)
+		gtest.Assert(err, nil)
+		gtest.Assert(len(statements), 3)
+		gtest.Assert(statements[1], "CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql")
+		gtest.Assert(statements[2], "SELECT $1")
+	})
+	gtest.Case(t, func() {
+		_, err := gdb.SplitSqlScript(`SELECT 'a;`)
+		gtest.AssertNE(err, nil)
+		_, err = gdb.SplitSqlScript(`SELECT 1; /* a`)
+		gtest.AssertNE(err, nil)
+	})
+}
+
+func TestExecScript(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+	gtest.Case(t, func() {
+		n, err := db.ExecScript(fmt.Sprintf(`
+SET @passport = 'script;1';
+INSERT INTO %s(id, passport) VALUES(1, @passport);
+INSERT INTO %s(id, passport) VALUES(2, 'script;2');
+`, table, table))
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 3)
+		value, err := db.GetValue(fmt.Sprintf("SELECT passport FROM %s WHERE id=?", table), 1)
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "script;1")
+
+		n, err = db.ExecScript(fmt.Sprintf(`
+INSERT INTO %s(id, passport) VALUES(3, 't3');
+INSERT INTO %s(id, passport) VALUES(3, 't3');
+INSERT INTO %s(id, passport) VALUES(4, 't4');
+`, table, table, table))
+		gtest.AssertNE(err, nil)
+		gtest.Assert(n, 1)
+		count, err := db.GetCount(fmt.Sprintf("SELECT COUNT(*) FROM %s", table))
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 3)
+	})
+}
@@ -7,10 +7,14 @@
 package gdb_test
 
 import (
+	"fmt"
+	"testing"
+
 	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/container/gvar"
+	"github.com/gogf/gf/g/database/gdb"
 	"github.com/gogf/gf/g/os/gtime"
 	"github.com/gogf/gf/g/test/gtest"
-	"testing"
 )
 
 func TestModel_Inherit_Insert(t *testing.T) {
@@ -95,3 +99,97 @@ func TestModel_Inherit_MapToStruct(t *testing.T) {
 	})
 
 }
+
+// 实现了sql.Scanner接口的自定义类型
+type testScannerPassport struct {
+	Value string
+	Valid bool
+}
+
+func (p *testScannerPassport) Scan(value interface{}) error {
+	if value == nil {
+		p.Value, p.Valid = "", false
+		return nil
+	}
+	p.Value, p.Valid = fmt.Sprintf("scan:%s", value), true
+	return nil
+}
+
+func TestModel_Struct_Scanner(t *testing.T) {
+	type Base struct {
+		Id int `json:"id"`
+	}
+	type Status int
+	type User struct {
+		*Base
+		Passport *testScannerPassport
+		Nickname testScannerPassport
+		Status   Status
+	}
+	gtest.Case(t, func() {
+		record := gdb.Record{
+			"id":       gvar.New(1, true),
+			"passport": gvar.New([]byte("t1"), true),
+			"nickname": gvar.New(nil, true),
+			"status":   gvar.New("2", true),
+		}
+		user := new(User)
+		gtest.Assert(record.ToStruct(user), nil)
+		gtest.Assert(user.Base.Id, 1)
+		gtest.Assert(user.Passport.Value, "scan:t1")
+		gtest.Assert(user.Passport.Valid, true)
+		gtest.Assert(user.Nickname.Valid, false)
+		gtest.Assert(user.Status, 2)
+	})
+}
+
+// 关联查询的数据表名称
+var testWithDetailTable string
+
+// 通过TableName方法指定关联表的关联属性类型
+type testWithDetail struct {
+	Id       int    `json:"id"`
+	Nickname string `json:"nickname"`
+}
+
+func (d *testWithDetail) TableName() string {
+	return testWithDetailTable
+}
+
+func TestModel_Struct_With(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+	testWithDetailTable = createInitTable()
+	defer dropTable(testWithDetailTable)
+
+	type User struct {
+		Id       int               `json:"id"`
+		Passport string            `json:"passport"`
+		Detail   *testWithDetail   `orm:"with:id=id"`
+		Details  []*testWithDetail `orm:"with:id=id"`
+	}
+	gtest.Case(t, func() {
+		users := make([]*User, 0)
+		err := db.Table(table).With().Where("id<?", 3).OrderBy("id asc").Structs(&users)
+		gtest.Assert(err, nil)
+		gtest.Assert(len(users), 2)
+		gtest.Assert(users[0].Detail.Nickname, "T1")
+		gtest.Assert(users[1].Detail.Nickname, "T2")
+		gtest.Assert(len(users[1].Details), 1)
+		gtest.Assert(users[1].Details[0].Id, 2)
+
+		user := new(User)
+		err = db.Table(table).With("Details").Where("id", 1).Struct(user)
+		gtest.Assert(err, nil)
+		gtest.Assert(user.Passport, "t1")
+		gtest.Assert(user.Detail, nil)
+		gtest.Assert(len(user.Details), 1)
+
+		// 关联属性不作为写入数据
+		_, err = db.Table(table).Data(User{Id: 100, Passport: "t100", Detail: &testWithDetail{Id: 1}}).Insert()
+		gtest.Assert(err, nil)
+		value, err := db.Table(table).Fields("passport").Where("id", 100).Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(value.String(), "t100")
+	})
+}
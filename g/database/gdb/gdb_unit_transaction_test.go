@@ -7,10 +7,15 @@
 package gdb_test
 
 import (
+	"errors"
+	"testing"
+	"time"
+
 	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/database/gdb"
 	"github.com/gogf/gf/g/os/gtime"
 	"github.com/gogf/gf/g/test/gtest"
-	"testing"
+	"github.com/gogf/gf/third/github.com/gf-third/mysql"
 )
 
 func TestTX_Query(t *testing.T) {
@@ -586,3 +591,62 @@ func TestTX_Delete(t *testing.T) {
 		gtest.Assert(n, 0)
 	}
 }
+
+func TestTX_Transaction(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+	gtest.Case(t, func() {
+		retries := 0
+		db.SetTxRetryHook(func(retry int, delay time.Duration, err error) {
+			retries = retry
+		})
+		defer db.SetTxRetryHook(nil)
+		// 第一次执行模拟死锁错误，重试后成功提交
+		count := 0
+		err := db.Transaction(func(tx *gdb.TX) error {
+			count++
+			if _, err := tx.Insert(table, g.Map{
+				"id":          count,
+				"passport":    "t1",
+				"password":    "p1",
+				"nickname":    "T1",
+				"create_time": gtime.Now().String(),
+			}); err != nil {
+				return err
+			}
+			if count == 1 {
+				return errors.New("Error 1213: Deadlock found when trying to get lock")
+			}
+			return nil
+		})
+		gtest.Assert(err, nil)
+		gtest.Assert(count, 2)
+		gtest.Assert(retries, 1)
+		ids, err := db.Table(table).Fields("id").Value()
+		gtest.Assert(err, nil)
+		gtest.Assert(ids.Int(), 2)
+
+		// 不可重试的错误直接回滚返回
+		err = db.Transaction(func(tx *gdb.TX) error {
+			if _, err := tx.Delete(table, nil); err != nil {
+				return err
+			}
+			return errors.New("rollback")
+		})
+		gtest.Assert(err, errors.New("rollback"))
+		n, err := db.Table(table).Count()
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 1)
+	})
+}
+
+func TestTX_IsRetryableError(t *testing.T) {
+	gtest.Case(t, func() {
+		gtest.Assert(gdb.IsRetryableError(nil), false)
+		gtest.Assert(gdb.IsRetryableError(errors.New("error")), false)
+		gtest.Assert(gdb.IsRetryableError(&mysql.MySQLError{Number: 1213}), true)
+		gtest.Assert(gdb.IsRetryableError(&mysql.MySQLError{Number: 1062}), false)
+		gtest.Assert(gdb.IsRetryableError(errors.New("DB ERROR: Error 1213: Deadlock found")), true)
+		gtest.Assert(gdb.IsRetryableError(errors.New("pq: could not serialize access due to concurrent update")), true)
+	})
+}
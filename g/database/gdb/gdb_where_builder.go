@@ -0,0 +1,178 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gdb
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+)
+
+const (
+	// IN条件参数为空slice时，条件恒为假(NOT IN条件恒为真)，这是默认的处理方式。
+	WHERE_IN_EMPTY_FALSE = 0
+	// IN/NOT IN条件参数为空slice时，忽略该条件。
+	WHERE_IN_EMPTY_IGNORE = 1
+)
+
+// 条件构造对象，用于组合复杂的嵌套查询条件，构造结果可作为Model的Where/And/Or方法参数使用。
+// 注意条件构造对象不是并发安全的。
+type WhereBuilder struct {
+	inEmpty    int          // IN条件参数为空slice时的处理方式
+	conditions []*whereItem // 条件项列表
+}
+
+// 条件项
+type whereItem struct {
+	operator string        // 与前一条件的连接操作符: AND/OR
+	where    string        // 条件语句
+	args     []interface{} // 条件参数
+}
+
+// 创建条件构造对象
+func NewWhereBuilder() *WhereBuilder {
+	return &WhereBuilder{
+		inEmpty:    WHERE_IN_EMPTY_FALSE,
+		conditions: make([]*whereItem, 0),
+	}
+}
+
+// 设置IN/NOT IN条件参数为空slice时的处理方式，可选值: WHERE_IN_EMPTY_FALSE, WHERE_IN_EMPTY_IGNORE。
+func (b *WhereBuilder) SetInEmpty(mode int) *WhereBuilder {
+	b.inEmpty = mode
+	return b
+}
+
+// 添加AND条件，参数格式与Model.Where一致，<where>也可以是一个嵌套的*WhereBuilder对象。
+func (b *WhereBuilder) Where(where interface{}, args ...interface{}) *WhereBuilder {
+	return b.And(where, args...)
+}
+
+// 添加AND条件
+func (b *WhereBuilder) And(where interface{}, args ...interface{}) *WhereBuilder {
+	newWhere, newArgs := formatCondition(where, args)
+	return b.add("AND", newWhere, newArgs)
+}
+
+// 添加OR条件
+func (b *WhereBuilder) Or(where interface{}, args ...interface{}) *WhereBuilder {
+	newWhere, newArgs := formatCondition(where, args)
+	return b.add("OR", newWhere, newArgs)
+}
+
+// 添加AND NOT条件
+func (b *WhereBuilder) Not(where interface{}, args ...interface{}) *WhereBuilder {
+	newWhere, newArgs := formatCondition(where, args)
+	if newWhere != "" {
+		newWhere = "NOT (" + newWhere + ")"
+	}
+	return b.add("AND", newWhere, newArgs)
+}
+
+// 添加OR NOT条件
+func (b *WhereBuilder) OrNot(where interface{}, args ...interface{}) *WhereBuilder {
+	newWhere, newArgs := formatCondition(where, args)
+	if newWhere != "" {
+		newWhere = "NOT (" + newWhere + ")"
+	}
+	return b.add("OR", newWhere, newArgs)
+}
+
+// 添加AND IN条件，<values>应当为slice/array类型，参数为空slice时根据SetInEmpty设置处理。
+func (b *WhereBuilder) In(column string, values interface{}) *WhereBuilder {
+	return b.in("AND", column, values, false)
+}
+
+// 添加OR IN条件
+func (b *WhereBuilder) OrIn(column string, values interface{}) *WhereBuilder {
+	return b.in("OR", column, values, false)
+}
+
+// 添加AND NOT IN条件
+func (b *WhereBuilder) NotIn(column string, values interface{}) *WhereBuilder {
+	return b.in("AND", column, values, true)
+}
+
+// 添加OR NOT IN条件
+func (b *WhereBuilder) OrNotIn(column string, values interface{}) *WhereBuilder {
+	return b.in("OR", column, values, true)
+}
+
+// 添加AND原生条件语句，条件语句不做任何处理，仅将slice参数按照'?'占位符展开。
+func (b *WhereBuilder) Raw(sql string, args ...interface{}) *WhereBuilder {
+	newWhere, newArgs := formatRawCondition(sql, args)
+	return b.add("AND", newWhere, newArgs)
+}
+
+// 添加OR原生条件语句
+func (b *WhereBuilder) OrRaw(sql string, args ...interface{}) *WhereBuilder {
+	newWhere, newArgs := formatRawCondition(sql, args)
+	return b.add("OR", newWhere, newArgs)
+}
+
+// 构造条件语句及条件参数，没有任何条件时返回空字符串。
+func (b *WhereBuilder) Build() (where string, args []interface{}) {
+	if len(b.conditions) == 1 {
+		return b.conditions[0].where, b.conditions[0].args
+	}
+	buffer := bytes.NewBuffer(nil)
+	for i, item := range b.conditions {
+		if i > 0 {
+			buffer.WriteString(" " + item.operator + " ")
+		}
+		buffer.WriteString("(" + item.where + ")")
+		args = append(args, item.args...)
+	}
+	return buffer.String(), args
+}
+
+// 添加条件项，空条件将被忽略。
+func (b *WhereBuilder) add(operator string, where string, args []interface{}) *WhereBuilder {
+	if where != "" {
+		b.conditions = append(b.conditions, &whereItem{
+			operator: operator,
+			where:    where,
+			args:     args,
+		})
+	}
+	return b
+}
+
+// 添加IN/NOT IN条件项
+func (b *WhereBuilder) in(operator string, column string, values interface{}, not bool) *WhereBuilder {
+	rv := reflect.ValueOf(values)
+	kind := rv.Kind()
+	if kind == reflect.Ptr {
+		rv = rv.Elem()
+		kind = rv.Kind()
+	}
+	if kind != reflect.Slice && kind != reflect.Array {
+		values = []interface{}{values}
+	} else if rv.Len() == 0 {
+		if b.inEmpty == WHERE_IN_EMPTY_IGNORE {
+			return b
+		}
+		if not {
+			return b.add(operator, "1=1", nil)
+		}
+		return b.add(operator, "0=1", nil)
+	}
+	where := column + " IN(?)"
+	if not {
+		where = column + " NOT IN(?)"
+	}
+	newWhere, newArgs := formatCondition(where, []interface{}{values})
+	return b.add(operator, newWhere, newArgs)
+}
+
+// 格式化原生条件语句，条件语句中没有'?'占位符时不做任何处理。
+func formatRawCondition(sql string, args []interface{}) (string, []interface{}) {
+	if strings.IndexByte(sql, '?') == -1 {
+		return sql, args
+	}
+	return formatCondition(sql, args)
+}
@@ -14,7 +14,10 @@
 package gredis
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gf/g/container/gmap"
@@ -23,15 +26,22 @@ import (
 )
 
 const (
+	gDEFAULT_POOL_MAX_IDLE      = 10
 	gDEFAULT_POOL_IDLE_TIMEOUT  = 60 * time.Second
 	gDEFAULT_POOL_MAX_LIFE_TIME = 60 * time.Second
 )
 
 // Redis client.
 type Redis struct {
-	pool   *redis.Pool // Underlying connection pool.
-	group  string      // Configuration group.
-	config Config      // Configuration.
+	pool     *redis.Pool // Underlying connection pool.
+	group    string      // Configuration group.
+	config   Config      // Configuration.
+	cluster  *cluster    // Cluster client, which is nil if it is not in cluster mode.
+	sentinel *sentinel   // Sentinel client, which is nil if it is not in sentinel mode.
+	replicas *replicas   // Replicas for read-only commands, which is nil if there's no replica configured.
+	waits    *poolWaits  // Waiting statistics of the pool.
+	hookMu   sync.RWMutex
+	hooks    []CommandHook // Hooks called after each command.
 }
 
 // Redis connection.
@@ -41,19 +51,34 @@ type Conn struct {
 
 // Redis configuration.
 type Config struct {
-	Host            string
+	Host            string // Host of the server, or comma-separated seed nodes for cluster, like: 192.168.1.1:7000,192.168.1.2:7000
 	Port            int
 	Db              int
 	Pass            string        // Password for AUTH.
-	MaxIdle         int           // Maximum number of connections allowed to be idle (default is 0 means no idle connection)
+	MaxIdle         int           // Maximum number of connections allowed to be idle (default is 10, and -1 means no idle connection)
 	MaxActive       int           // Maximum number of connections limit (default is 0 means no limit)
 	IdleTimeout     time.Duration // Maximum idle time for connection (default is 60 seconds, not allowed to be set to 0)
 	MaxConnLifetime time.Duration // Maximum lifetime of the connection (default is 60 seconds, not allowed to be set to 0)
+	MinIdle         int           // Minimum number of idle connections kept warm by health checking (default is 0 means no warmup)
+	HealthCheck     time.Duration // Interval of background health checking (default is 0 means disabled)
+	Cluster         bool          // Whether the server is redis cluster, which is also enabled if Host contains multiple nodes.
+	Sentinels       string        // Comma-separated sentinel addresses, like: 192.168.1.1:26379,192.168.1.2:26379, which enables sentinel mode and Host/Port are ignored.
+	MasterName      string        // Master name monitored by sentinels, which is necessary in sentinel mode.
+	ConnectTimeout  time.Duration // Timeout for connecting to the server (default is 0 means no timeout)
+	ReadTimeout     time.Duration // Timeout for reading a reply of command (default is 0 means no timeout)
+	WriteTimeout    time.Duration // Timeout for writing a command (default is 0 means no timeout)
+	AutoTune        time.Duration // Interval of tuning MaxIdle based on observed concurrency (default is 0 means disabled)
+	Wait            bool          // Whether waiting for a connection returned to the pool if MaxActive is reached (default is false means returning error)
+	SlowThreshold   time.Duration // Commands costing no less than it are logged as slow commands (default is 0 means disabled)
+	Replicas        string        // Comma-separated replica addresses, like: 192.168.1.2:6379,192.168.1.3:6379, which the read-only commands are routed to.
+	ReplicaPolicy   string        // Policy of picking replica: roundrobin or random (default is roundrobin)
 }
 
 // Pool statistics.
 type PoolStats struct {
 	redis.PoolStats
+	WaitCount    int64         // Total count of waiting for a connection, which requires Config.Wait.
+	WaitDuration time.Duration // Total duration of waiting for a connection.
 }
 
 var (
@@ -72,40 +97,109 @@ func New(config Config) *Redis {
 	if config.MaxConnLifetime == 0 {
 		config.MaxConnLifetime = gDEFAULT_POOL_MAX_LIFE_TIME
 	}
-	return &Redis{
+	if config.MaxIdle == 0 {
+		config.MaxIdle = gDEFAULT_POOL_MAX_IDLE
+	}
+	if config.MaxActive > 0 && config.MaxIdle > config.MaxActive {
+		config.MaxIdle = config.MaxActive
+	}
+	if config.MaxIdle < config.MinIdle {
+		config.MaxIdle = config.MinIdle
+	}
+	if config.MaxIdle < 0 {
+		config.MaxIdle = 0
+	}
+	r := &Redis{
 		config: config,
-		pool: pools.GetOrSetFuncLock(fmt.Sprintf("%v", config), func() interface{} {
-			return &redis.Pool{
-				IdleTimeout:     config.IdleTimeout,
-				MaxConnLifetime: config.MaxConnLifetime,
-				Dial: func() (redis.Conn, error) {
-					c, err := redis.Dial("tcp", fmt.Sprintf("%s:%d", config.Host, config.Port))
-					if err != nil {
-						return nil, err
-					}
-					// AUTH
-					if len(config.Pass) > 0 {
-						if _, err := c.Do("AUTH", config.Pass); err != nil {
-							return nil, err
-						}
-					}
-					// DB
-					if _, err := c.Do("SELECT", config.Db); err != nil {
-						return nil, err
-					}
-					return c, nil
-				},
-				// After the conn is taken from the connection pool, to test if the connection is available,
-				// If error is returned then it closes the connection object and recreate a new connection.
-				TestOnBorrow: func(c redis.Conn, t time.Time) error {
-					_, err := c.Do("PING")
-					return err
-				},
+		waits:  getPoolWaits(config),
+	}
+	if config.SlowThreshold > 0 {
+		r.OnCommand(SlowLogHook(config.SlowThreshold))
+	}
+	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	if isClusterConfig(config) {
+		// The pool of the first seed node is used for the connection level operations,
+		// and the commands of Do are routed to the nodes by slots.
+		r.cluster = newCluster(config)
+		if len(r.cluster.seeds) > 0 {
+			address = r.cluster.seeds[0]
+		}
+	}
+	if isSentinelConfig(config) {
+		// The master address is discovered from sentinels on dialing.
+		r.sentinel = getSentinel(config)
+	}
+	r.pool = pools.GetOrSetFuncLock(fmt.Sprintf("%v", config), func() interface{} {
+		var pool *redis.Pool
+		if r.sentinel != nil {
+			pool = r.sentinel.newPool()
+		} else {
+			pool = newPool(config, address)
+		}
+		if config.AutoTune > 0 {
+			startPoolTuner(pool, config)
+		}
+		return pool
+	}).(*redis.Pool)
+	if r.cluster == nil {
+		// The replicas of cluster nodes are managed by the cluster itself.
+		r.replicas = newReplicas(config)
+	}
+	if config.HealthCheck > 0 {
+		r.startHealthCheck()
+	}
+	return r
+}
+
+// newPool creates and returns a connection pool for the server of <address> with <config>.
+func newPool(config Config, address string) *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:         config.MaxIdle,
+		MaxActive:       config.MaxActive,
+		IdleTimeout:     config.IdleTimeout,
+		MaxConnLifetime: config.MaxConnLifetime,
+		Wait:            config.Wait,
+		Dial: func() (redis.Conn, error) {
+			c, err := redis.Dial("tcp", address, dialOptions(config)...)
+			if err != nil {
+				return nil, err
 			}
-		}).(*redis.Pool),
+			if err := initConn(c, config); err != nil {
+				c.Close()
+				return nil, err
+			}
+			return c, nil
+		},
+		// After the conn is taken from the connection pool, to test if the connection is available,
+		// If error is returned then it closes the connection object and recreate a new connection.
+		TestOnBorrow: testOnBorrow(config),
+	}
+}
+
+// dialOptions returns the options of dialing with the timeouts of <config>.
+func dialOptions(config Config) []redis.DialOption {
+	return []redis.DialOption{
+		redis.DialConnectTimeout(config.ConnectTimeout),
+		redis.DialReadTimeout(config.ReadTimeout),
+		redis.DialWriteTimeout(config.WriteTimeout),
 	}
 }
 
+// initConn authenticates and selects the database for the new connection <c> with <config>.
+func initConn(c redis.Conn, config Config) error {
+	// AUTH
+	if len(config.Pass) > 0 {
+		if _, err := c.Do("AUTH", config.Pass); err != nil {
+			return err
+		}
+	}
+	// DB
+	if _, err := c.Do("SELECT", config.Db); err != nil {
+		return err
+	}
+	return nil
+}
+
 // Instance returns an instance of redis client with specified group.
 // The <group> param is unnecessary, if <group> is not passed,
 // it returns a redis instance with default group.
@@ -137,11 +231,24 @@ func (r *Redis) Close() error {
 		instances.Remove(r.group)
 	}
 	pools.Remove(fmt.Sprintf("%v", r.config))
+	waits.Remove(fmt.Sprintf("%v", r.config))
+	r.stopHealthCheck()
+	stopPoolTuner(r.config)
+	if r.cluster != nil {
+		r.cluster.Close()
+	}
+	if r.sentinel != nil {
+		removeSentinel(r.config)
+	}
+	if r.replicas != nil {
+		r.replicas.close(r.config)
+	}
 	return r.pool.Close()
 }
 
 // Conn returns a raw underlying connection object,
 // which expose more methods to communicate with server.
+// In cluster mode, it returns a connection to the first seed node, which does not follow redirections.
 // **You should call Close function manually if you do not use this connection any further.**
 func (r *Redis) Conn() *Conn {
 	return &Conn{r.pool.Get()}
@@ -172,18 +279,147 @@ func (r *Redis) SetMaxConnLifetime(value time.Duration) {
 	r.pool.MaxConnLifetime = value
 }
 
+// ClusterNodes returns the addresses of the master nodes of redis cluster,
+// it returns nil if it is not in cluster mode.
+func (r *Redis) ClusterNodes() []string {
+	if r.cluster == nil {
+		return nil
+	}
+	return r.cluster.Nodes()
+}
+
+// RefreshCluster reloads the slots topology of redis cluster,
+// it does nothing if it is not in cluster mode.
+func (r *Redis) RefreshCluster() error {
+	if r.cluster == nil {
+		return nil
+	}
+	return r.cluster.refresh()
+}
+
+// SentinelMaster returns the current master address discovered from sentinels,
+// it returns empty string if it is not in sentinel mode or the master is not discovered yet.
+func (r *Redis) SentinelMaster() string {
+	if r.sentinel == nil {
+		return ""
+	}
+	return r.sentinel.Master()
+}
+
 // Stats returns pool's statistics.
 func (r *Redis) Stats() *PoolStats {
-	return &PoolStats{r.pool.Stats()}
+	return &PoolStats{
+		PoolStats:    r.pool.Stats(),
+		WaitCount:    r.waits.count.Val(),
+		WaitDuration: time.Duration(r.waits.duration.Val()),
+	}
 }
 
 // Do sends a command to the server and returns the received reply.
 // Do automatically get a connection from pool, and close it when reply received.
 // It does not really "close" the connection, but drop it back to the connection pool.
+// In cluster mode, the command is sent to the node owning the slot of its key,
+// and the MOVED/ASK redirections are followed automatically.
 func (r *Redis) Do(command string, args ...interface{}) (interface{}, error) {
-	conn := &Conn{r.pool.Get()}
+	return r.doWithTimeout(context.Background(), 0, command, args...)
+}
+
+// DoWithTimeout acts like Do, but it uses <timeout> as the read timeout of the reply,
+// which overrides the ReadTimeout of configuration for this call.
+// The connection is closed instead of being reused if the reply times out.
+func (r *Redis) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	return r.doWithTimeout(context.Background(), timeout, command, args...)
+}
+
+// DoCtx acts like Do, but it is controlled by context <ctx>, eg: the context of ghttp request.
+// The deadline of <ctx> is used for waiting the connection from pool and as the read timeout of the reply,
+// so that the connection is released when the deadline exceeds.
+// It returns ctx.Err() immediately if <ctx> is done before the reply received, note that if <ctx> is canceled
+// without deadline, the command is still in progress and its connection is released after the reply received
+// or the ReadTimeout of configuration exceeds.
+func (r *Redis) DoCtx(ctx context.Context, command string, args ...interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(0)
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout = time.Until(deadline); timeout <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+	}
+	if ctx.Done() == nil {
+		return r.doWithTimeout(ctx, timeout, command, args...)
+	}
+	type result struct {
+		reply interface{}
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		reply, err := r.doWithTimeout(ctx, timeout, command, args...)
+		done <- result{reply, err}
+	}()
+	select {
+	case res := <-done:
+		if res.err != nil {
+			// The read timeout of the deadline may be reached a little earlier than ctx.Done.
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+				return nil, context.DeadlineExceeded
+			}
+		}
+		return res.reply, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// DoVarCtx returns value from DoCtx as gvar.Var.
+func (r *Redis) DoVarCtx(ctx context.Context, command string, args ...interface{}) (*gvar.Var, error) {
+	v, err := r.DoCtx(ctx, command, args...)
+	return gvar.New(v, true), err
+}
+
+// doWithTimeout sends the command with read <timeout>, the ReadTimeout of configuration is used if <timeout> is 0.
+// The <ctx> is used for waiting the connection from pool. The hooks are called after the reply received.
+func (r *Redis) doWithTimeout(ctx context.Context, timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	if !r.hasHooks() {
+		return r.doCommand(ctx, timeout, command, args...)
+	}
+	start := time.Now()
+	reply, err := r.doCommand(ctx, timeout, command, args...)
+	r.callHooks(command, args, time.Since(start), err)
+	return reply, err
+}
+
+// doCommand sends the command with read <timeout> using a connection from pool or the cluster.
+// The read-only command is sent to replica if there're replicas, and it falls back to master
+// if the replica is unavailable.
+func (r *Redis) doCommand(ctx context.Context, timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	if r.cluster != nil {
+		return r.cluster.DoWithTimeout(timeout, command, args...)
+	}
+	if r.replicas != nil && isReplicaCommand(ctx, command) {
+		if reply, ok, err := r.replicas.do(timeout, command, args...); ok {
+			return reply, err
+		}
+	}
+	conn, err := r.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
 	defer conn.Close()
-	return conn.Do(command, args...)
+	reply, err := doConn(conn, timeout, command, args...)
+	if err != nil && r.sentinel != nil {
+		// The connected server was demoted to replica in failover,
+		// rediscovering the master so that the stale connections are dropped on borrowing.
+		if e, ok := err.(redis.Error); ok && strings.HasPrefix(string(e), "READONLY") {
+			r.sentinel.discover()
+		}
+	}
+	return reply, err
 }
 
 // DoVar returns value from Do as gvar.Var.
@@ -199,3 +435,11 @@ func (r *Redis) Send(command string, args ...interface{}) error {
 	defer conn.Close()
 	return conn.Send(command, args...)
 }
+
+// doConn sends the command with <conn> using read <timeout>, it uses the read timeout of the connection if <timeout> is 0.
+func doConn(conn redis.Conn, timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	if timeout > 0 {
+		return redis.DoWithTimeout(conn, timeout, command, args...)
+	}
+	return conn.Do(command, args...)
+}
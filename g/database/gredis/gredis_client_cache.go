@@ -0,0 +1,370 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gf/g/container/gmap"
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/os/gcache"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Default expiration of the client-side cached values.
+	gCLIENT_CACHE_DEFAULT_EXPIRE = 60 * time.Second
+	// Channel of the invalidation messages of client tracking in RESP2.
+	gCLIENT_CACHE_INVALIDATE_CHANNEL = "__redis__:invalidate"
+)
+
+// ClientCacheConfig is the configuration of the client-side cache.
+type ClientCacheConfig struct {
+	Expire   time.Duration // Expiration of the cached values (default is 60 seconds)
+	Size     int           // Maximum count of cached keys, which are evicted by LRU (default is 0 means no limit)
+	Prefixes []string      // Prefixes of the cached keys (default is empty means all keys are cached)
+}
+
+// ClientCache is a client-side cache of GET/HGET results, which are invalidated by redis server
+// using client tracking (redis >= 6.0) in broadcasting mode.
+//
+// It uses a dedicated connection to enable the tracking and receive the invalidation messages
+// redirected to itself from channel "__redis__:invalidate", which works with RESP2.
+// All the cached values are dropped and the cache is bypassed while the connection is broken,
+// as the invalidation messages may be lost, until it's reconnected.
+//
+// It is designed for the hot keys that are read frequently and written rarely,
+// like configurations and feature flags. The tracked keys should be limited by Prefixes,
+// as the server sends invalidation messages for all the matching keys modified by any client.
+type ClientCache struct {
+	mu       sync.Mutex
+	redis    *Redis
+	config   ClientCacheConfig
+	cache    *gcache.Cache         // Cached values, which is replaced on flushing.
+	conn     redis.Conn            // Tracking connection, which is nil while reconnecting.
+	fetching map[string]*cacheLoad // Keys being loaded from server.
+	closed   *gtype.Bool           // Whether the cache is closed.
+	done     chan struct{}         // Closed when the cache is closed.
+}
+
+// cacheLoad marks a key being loaded from server, which should not be cached
+// if it's invalidated while loading.
+type cacheLoad struct {
+	refs        int  // Count of loading goroutines.
+	invalidated bool // Whether the key is invalidated while loading.
+}
+
+// cacheKey is the key of gcache for a redis key, which separates string and hash values.
+type cacheKey struct {
+	key  string
+	hash bool
+}
+
+// ClientCache creates and returns a client-side cache with <config>, which is connected to the server
+// and tracks the keys immediately. The cache MUST be closed by ClientCache.Close if it's not used any more.
+//
+// It is not supported in cluster mode, as the tracking is per node.
+func (r *Redis) ClientCache(config ...ClientCacheConfig) (*ClientCache, error) {
+	if r.cluster != nil {
+		return nil, errors.New("client cache is not supported in cluster mode")
+	}
+	c := &ClientCache{
+		redis:    r,
+		fetching: make(map[string]*cacheLoad),
+		closed:   gtype.NewBool(),
+		done:     make(chan struct{}),
+	}
+	if len(config) > 0 {
+		c.config = config[0]
+	}
+	if c.config.Expire <= 0 {
+		c.config.Expire = gCLIENT_CACHE_DEFAULT_EXPIRE
+	}
+	c.cache = gcache.New(c.config.Size)
+	if err := c.connect(); err != nil {
+		c.cache.Close()
+		return nil, err
+	}
+	go c.keepalive()
+	go c.receive()
+	return c, nil
+}
+
+// Get returns the value of <key> using GET, from the local cache if it's cached.
+func (c *ClientCache) Get(key string) (*gvar.Var, error) {
+	ck := cacheKey{key: key}
+	if v := c.getCache(ck); v != nil {
+		return v.(*gvar.Var), nil
+	}
+	if !c.load(key) {
+		return c.redis.DoVar("GET", key)
+	}
+	v, err := c.redis.DoVar("GET", key)
+	c.loaded(key, func(cache *gcache.Cache) {
+		if err == nil {
+			cache.Set(ck, v, c.expire())
+		}
+	})
+	return v, err
+}
+
+// HGet returns the value of <field> in hash <key> using HGET, from the local cache if it's cached.
+// The cached fields of <key> are invalidated together.
+func (c *ClientCache) HGet(key, field string) (*gvar.Var, error) {
+	ck := cacheKey{key: key, hash: true}
+	if v := c.getCache(ck); v != nil {
+		if value := v.(*gmap.StrAnyMap).Get(field); value != nil {
+			return value.(*gvar.Var), nil
+		}
+	}
+	if !c.load(key) {
+		return c.redis.DoVar("HGET", key, field)
+	}
+	v, err := c.redis.DoVar("HGET", key, field)
+	c.loaded(key, func(cache *gcache.Cache) {
+		if err == nil {
+			fields := cache.GetOrSetFuncLock(ck, func() interface{} {
+				return gmap.NewStrAnyMap()
+			}, c.expire()).(*gmap.StrAnyMap)
+			fields.Set(field, v)
+		}
+	})
+	return v, err
+}
+
+// Invalidate removes <keys> from the local cache.
+func (c *ClientCache) Invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		c.invalidateWithoutLock(key)
+	}
+}
+
+// Clear removes all the values from the local cache.
+func (c *ClientCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushWithoutLock()
+}
+
+// Size returns the count of the cached keys.
+func (c *ClientCache) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Size()
+}
+
+// Close closes the cache and its tracking connection.
+func (c *ClientCache) Close() error {
+	if c.closed.Set(true) {
+		return nil
+	}
+	close(c.done)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Close()
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// expire returns the expiration in milliseconds for gcache.
+func (c *ClientCache) expire() int {
+	return int(c.config.Expire / time.Millisecond)
+}
+
+// cacheable checks whether <key> matches the prefixes.
+func (c *ClientCache) cacheable(key string) bool {
+	if len(c.config.Prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range c.config.Prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getCache returns the cached value of <ck>, or nil if it's not cached.
+func (c *ClientCache) getCache(ck cacheKey) interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	return c.cache.Get(ck)
+}
+
+// load marks <key> being loaded from server, it returns false if <key> should not be cached.
+// The key must be unmarked by loaded if it returns true.
+func (c *ClientCache) load(key string) bool {
+	if !c.cacheable(key) {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return false
+	}
+	l, ok := c.fetching[key]
+	if !ok {
+		l = &cacheLoad{}
+		c.fetching[key] = l
+	}
+	l.refs++
+	return true
+}
+
+// loaded unmarks <key> being loaded, and calls <set> to cache the loaded value
+// if it's not invalidated while loading.
+func (c *ClientCache) loaded(key string, set func(cache *gcache.Cache)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l := c.fetching[key]
+	if l == nil {
+		// It's flushed while loading.
+		return
+	}
+	l.refs--
+	if l.refs == 0 {
+		delete(c.fetching, key)
+	}
+	if !l.invalidated && c.conn != nil {
+		set(c.cache)
+	}
+}
+
+// invalidateWithoutLock removes <key> from the local cache, and marks it invalidated if it's being loaded.
+func (c *ClientCache) invalidateWithoutLock(key string) {
+	c.cache.Remove(cacheKey{key: key})
+	c.cache.Remove(cacheKey{key: key, hash: true})
+	if l, ok := c.fetching[key]; ok {
+		l.invalidated = true
+	}
+}
+
+// flushWithoutLock removes all the values from the local cache, and marks all the loading keys invalidated.
+func (c *ClientCache) flushWithoutLock() {
+	old := c.cache
+	c.cache = gcache.New(c.config.Size)
+	old.Close()
+	for _, l := range c.fetching {
+		l.invalidated = true
+	}
+}
+
+// connect dials a new connection, enables the tracking redirected to itself,
+// and subscribes the invalidation channel.
+func (c *ClientCache) connect() error {
+	conn, err := c.redis.pool.Dial()
+	if err != nil {
+		return err
+	}
+	id, err := redis.Int64(conn.Do("CLIENT", "ID"))
+	if err == nil {
+		args := []interface{}{"TRACKING", "ON", "REDIRECT", id, "BCAST"}
+		for _, prefix := range c.config.Prefixes {
+			args = append(args, "PREFIX", prefix)
+		}
+		_, err = conn.Do("CLIENT", args...)
+	}
+	if err == nil {
+		err = (&redis.PubSubConn{Conn: conn}).Subscribe(gCLIENT_CACHE_INVALIDATE_CHANNEL)
+	}
+	if err == nil {
+		// Receiving the reply of SUBSCRIBE.
+		_, err = redis.ReceiveWithTimeout(conn, gSUBSCRIPTION_READ_TIMEOUT)
+	}
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed.Val() {
+		return conn.Close()
+	}
+	// The values cached before are not tracked by the new connection.
+	c.flushWithoutLock()
+	c.conn = conn
+	return nil
+}
+
+// receive receives invalidation messages from the connection, and reconnects if the connection
+// is broken, until the cache is closed.
+func (c *ClientCache) receive() {
+	for !c.closed.Val() {
+		c.mu.Lock()
+		conn := c.conn
+		c.mu.Unlock()
+		if conn == nil {
+			if err := c.connect(); err != nil {
+				select {
+				case <-c.done:
+				case <-time.After(gSUBSCRIPTION_RETRY_INTERVAL):
+				}
+			}
+			continue
+		}
+		reply, err := redis.Values(redis.ReceiveWithTimeout(conn, gSUBSCRIPTION_READ_TIMEOUT))
+		if err != nil {
+			c.mu.Lock()
+			if c.conn == conn {
+				// The invalidation messages may be lost, so the cache is bypassed until reconnected.
+				c.conn = nil
+				c.flushWithoutLock()
+			}
+			c.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		// Invalidation message: ["message", "__redis__:invalidate", [keys...]],
+		// in which the keys are nil if the server flushes the database.
+		if len(reply) != 3 {
+			continue
+		}
+		if kind, _ := redis.String(reply[0], nil); kind != "message" {
+			continue
+		}
+		keys, _ := redis.Strings(reply[2], nil)
+		c.mu.Lock()
+		if reply[2] == nil {
+			c.flushWithoutLock()
+		} else {
+			for _, key := range keys {
+				c.invalidateWithoutLock(key)
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// keepalive sends PING in interval to detect the broken connection,
+// which makes the receiving time out and reconnect.
+func (c *ClientCache) keepalive() {
+	ticker := time.NewTicker(gSUBSCRIPTION_PING_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			if c.conn != nil {
+				(&redis.PubSubConn{Conn: c.conn}).Ping("")
+			}
+			c.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gf/g/util/gconv"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Count of hash slots of redis cluster.
+	gCLUSTER_SLOTS = 16384
+	// Max count of MOVED/ASK redirections for a command.
+	gCLUSTER_MAX_REDIRECTS = 5
+)
+
+// cluster is the slot-aware client for redis cluster, which maintains a connection pool for each node.
+type cluster struct {
+	mu         sync.RWMutex
+	config     Config
+	seeds      []string               // Seed node addresses from configuration.
+	slots      []string               // Node address of each slot.
+	pools      map[string]*redis.Pool // Node address to connection pool.
+	refreshing int32                  // Whether the topology refreshing is in progress.
+}
+
+// newCluster creates and returns a cluster client with <config>,
+// the <Host> of which is a comma-separated seed node list, like: 192.168.1.1:7000,192.168.1.2:7000.
+// The seed nodes without port use the <Port> of the configuration.
+// The cluster topology is loaded lazily when the first command is executed.
+func newCluster(config Config) *cluster {
+	return &cluster{
+		config: config,
+		seeds:  parseClusterHosts(config.Host, config.Port),
+		slots:  make([]string, gCLUSTER_SLOTS),
+		pools:  make(map[string]*redis.Pool),
+	}
+}
+
+// isClusterConfig checks whether <config> is for redis cluster.
+func isClusterConfig(config Config) bool {
+	return config.Cluster || strings.Contains(config.Host, ",")
+}
+
+// parseClusterHosts parses the comma-separated <hosts> to node addresses.
+func parseClusterHosts(hosts string, port int) []string {
+	addresses := make([]string, 0)
+	for _, host := range strings.Split(hosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		}
+		addresses = append(addresses, host)
+	}
+	return addresses
+}
+
+// Slot returns the hash slot of <key> in redis cluster.
+// Only the content in the first "{...}" of the key is hashed if it is not empty,
+// which is called hash tag and can be used to force keys into the same slot.
+func Slot(key string) int {
+	if start := strings.IndexByte(key, '{'); start != -1 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % gCLUSTER_SLOTS)
+}
+
+// crc16 implements the CRC16-CCITT(XMODEM) algorithm used by redis cluster.
+func crc16(s string) uint16 {
+	crc := uint16(0)
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Do sends the command to the node owning the slot of the command key, and returns the reply.
+// It follows the MOVED/ASK redirections, and refreshes the cluster topology when MOVED received.
+func (c *cluster) Do(command string, args ...interface{}) (interface{}, error) {
+	return c.DoWithTimeout(0, command, args...)
+}
+
+// DoWithTimeout acts like Do, but it uses <timeout> as the read timeout of each reply.
+func (c *cluster) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	var (
+		address = ""
+		asking  = false
+		lastErr error
+	)
+	slot := commandSlot(command, args)
+	for i := 0; i <= gCLUSTER_MAX_REDIRECTS; i++ {
+		if address == "" {
+			address = c.getNode(slot)
+		}
+		if address == "" {
+			// No node available, reloading the topology.
+			if err := c.refresh(); err != nil {
+				return nil, err
+			}
+			if address = c.getNode(slot); address == "" {
+				return nil, errors.New("no available node in redis cluster")
+			}
+		}
+		conn := c.getPool(address).Get()
+		if asking {
+			if _, err := conn.Do("ASKING"); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		reply, err := doConn(conn, timeout, command, args...)
+		conn.Close()
+		if err == nil {
+			return reply, nil
+		}
+		lastErr = err
+		redisErr, ok := err.(redis.Error)
+		if !ok {
+			// Network error, the node may be down, reloading the topology and retrying.
+			c.refresh()
+			address, asking = "", false
+			continue
+		}
+		// Redirection error: MOVED/ASK <slot> <address>
+		array := strings.Fields(string(redisErr))
+		if len(array) != 3 || (array[0] != "MOVED" && array[0] != "ASK") {
+			return reply, err
+		}
+		address, asking = array[2], array[0] == "ASK"
+		if array[0] == "MOVED" {
+			c.setNode(gconv.Int(array[1]), address)
+			c.refreshAsync()
+		}
+	}
+	return nil, fmt.Errorf("too many redirections in redis cluster: %v", lastErr)
+}
+
+// Close closes all the connection pools of the nodes.
+func (c *cluster) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for address, pool := range c.pools {
+		pool.Close()
+		delete(c.pools, address)
+	}
+	return nil
+}
+
+// Nodes returns the addresses of all the nodes owning slots.
+func (c *cluster) Nodes() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	nodes := make([]string, 0)
+	exists := make(map[string]struct{})
+	for _, address := range c.slots {
+		if _, ok := exists[address]; !ok && address != "" {
+			exists[address] = struct{}{}
+			nodes = append(nodes, address)
+		}
+	}
+	return nodes
+}
+
+// getNode returns the node address owning <slot>.
+// The slot -1 means the command has no key, and any node can be returned.
+func (c *cluster) getNode(slot int) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if slot >= 0 {
+		return c.slots[slot]
+	}
+	for _, address := range c.slots {
+		if address != "" {
+			return address
+		}
+	}
+	if len(c.seeds) > 0 {
+		return c.seeds[0]
+	}
+	return ""
+}
+
+// setNode sets the node address owning <slot>.
+func (c *cluster) setNode(slot int, address string) {
+	if slot < 0 || slot >= gCLUSTER_SLOTS {
+		return
+	}
+	c.mu.Lock()
+	c.slots[slot] = address
+	c.mu.Unlock()
+}
+
+// getPool returns the connection pool of node <address>, it creates one if it does not exist.
+func (c *cluster) getPool(address string) *redis.Pool {
+	c.mu.RLock()
+	pool, ok := c.pools[address]
+	c.mu.RUnlock()
+	if ok {
+		return pool
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if pool, ok = c.pools[address]; !ok {
+		pool = newPool(c.config, address)
+		c.pools[address] = pool
+	}
+	return pool
+}
+
+// refreshAsync refreshes the cluster topology in background,
+// it does nothing if there's already a refreshing in progress.
+func (c *cluster) refreshAsync() {
+	if atomic.CompareAndSwapInt32(&c.refreshing, 0, 1) {
+		go func() {
+			defer atomic.StoreInt32(&c.refreshing, 0)
+			c.refresh()
+		}()
+	}
+}
+
+// refresh loads the cluster topology by command CLUSTER SLOTS from the known nodes and seed nodes,
+// it returns the last error if no node is available.
+func (c *cluster) refresh() error {
+	var lastErr error = errors.New("no seed node for redis cluster")
+	addresses := append(c.Nodes(), c.seeds...)
+	for _, address := range addresses {
+		conn := c.getPool(address).Get()
+		reply, err := redis.Values(conn.Do("CLUSTER", "SLOTS"))
+		conn.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		slots := make([]string, gCLUSTER_SLOTS)
+		for _, item := range reply {
+			// Format: [start, end, [ip, port, id], replicas...]
+			array, err := redis.Values(item, nil)
+			if err != nil || len(array) < 3 {
+				continue
+			}
+			master, err := redis.Values(array[2], nil)
+			if err != nil || len(master) < 2 {
+				continue
+			}
+			host := gconv.String(master[0])
+			if host == "" {
+				// Empty ip means the same host as the node replying.
+				host, _, _ = net.SplitHostPort(address)
+			}
+			node := net.JoinHostPort(host, gconv.String(master[1]))
+			start, end := gconv.Int(array[0]), gconv.Int(array[1])
+			for slot := start; slot <= end && slot < gCLUSTER_SLOTS; slot++ {
+				slots[slot] = node
+			}
+		}
+		c.mu.Lock()
+		c.slots = slots
+		c.mu.Unlock()
+		return nil
+	}
+	return lastErr
+}
+
+// commandSlot returns the slot of the key of <command>, it returns -1 if the command has no key.
+// The key is the first argument for most commands, and the first key for EVAL/EVALSHA.
+func commandSlot(command string, args []interface{}) int {
+	index := 0
+	switch strings.ToUpper(command) {
+	case "PING", "INFO", "ECHO", "TIME", "DBSIZE", "RANDOMKEY", "CLUSTER", "SCRIPT", "CONFIG", "CLIENT":
+		return -1
+	case "EVAL", "EVALSHA":
+		if len(args) < 3 || gconv.Int(args[1]) <= 0 {
+			return -1
+		}
+		index = 2
+	}
+	if len(args) <= index {
+		return -1
+	}
+	return Slot(gconv.String(args[index]))
+}
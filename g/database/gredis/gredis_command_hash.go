@@ -0,0 +1,88 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"github.com/gf/g/container/gvar"
+	"github.com/gomodule/redigo/redis"
+)
+
+// HGetVar returns the value of <field> in hash <key> as *gvar.Var, which is nil if the field does not exist.
+func (r *Redis) HGetVar(key string, field string) (*gvar.Var, error) {
+	return r.DoVar("HGET", key, field)
+}
+
+// HGetString returns the value of <field> in hash <key> as string.
+func (r *Redis) HGetString(key string, field string) (string, error) {
+	v, err := redis.String(r.Do("HGET", key, field))
+	return v, ignoreNil(err)
+}
+
+// HGetInt64 returns the value of <field> in hash <key> as int64.
+func (r *Redis) HGetInt64(key string, field string) (int64, error) {
+	v, err := redis.Int64(r.Do("HGET", key, field))
+	return v, ignoreNil(err)
+}
+
+// HSet sets <field> in hash <key> to <value>, and returns whether the field is newly created.
+func (r *Redis) HSet(key string, field string, value interface{}) (bool, error) {
+	return redis.Bool(r.Do("HSET", key, field, value))
+}
+
+// HMSet sets the fields in hash <key> to their values in <data>.
+func (r *Redis) HMSet(key string, data map[string]interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, 2*len(data)+1)
+	args = append(args, key)
+	for field, value := range data {
+		args = append(args, field, value)
+	}
+	_, err := r.Do("HMSET", args...)
+	return err
+}
+
+// HGetAllMap returns all the fields and values in hash <key>, which is empty if the key does not exist.
+func (r *Redis) HGetAllMap(key string) (map[string]string, error) {
+	return redis.StringMap(r.Do("HGETALL", key))
+}
+
+// HGetAllVarMap returns all the fields and values in hash <key> as map of *gvar.Var.
+func (r *Redis) HGetAllVarMap(key string) (map[string]*gvar.Var, error) {
+	values, err := redis.Values(r.Do("HGETALL", key))
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]*gvar.Var, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		field, _ := redis.String(values[i], nil)
+		m[field] = gvar.New(values[i+1], true)
+	}
+	return m, nil
+}
+
+// HDel deletes <fields> in hash <key>, and returns the count of the deleted fields.
+func (r *Redis) HDel(key string, fields ...string) (int64, error) {
+	return redis.Int64(r.Do("HDEL", append([]interface{}{key}, stringsToInterfaces(fields)...)...))
+}
+
+// HExists checks whether <field> exists in hash <key>.
+func (r *Redis) HExists(key string, field string) (bool, error) {
+	return redis.Bool(r.Do("HEXISTS", key, field))
+}
+
+// HIncrBy increments the integer value of <field> in hash <key> by <increment>,
+// and returns the value after increment.
+func (r *Redis) HIncrBy(key string, field string, increment int64) (int64, error) {
+	return redis.Int64(r.Do("HINCRBY", key, field, increment))
+}
+
+// HLen returns the count of fields in hash <key>.
+func (r *Redis) HLen(key string) (int64, error) {
+	return redis.Int64(r.Do("HLEN", key))
+}
@@ -0,0 +1,36 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"github.com/gomodule/redigo/redis"
+)
+
+// SAdd adds <members> to set <key>, and returns the count of the newly added members.
+func (r *Redis) SAdd(key string, members ...interface{}) (int64, error) {
+	return redis.Int64(r.Do("SADD", append([]interface{}{key}, members...)...))
+}
+
+// SRem removes <members> from set <key>, and returns the count of the removed members.
+func (r *Redis) SRem(key string, members ...interface{}) (int64, error) {
+	return redis.Int64(r.Do("SREM", append([]interface{}{key}, members...)...))
+}
+
+// SMembers returns all the members of set <key>, which is empty if the key does not exist.
+func (r *Redis) SMembers(key string) ([]string, error) {
+	return redis.Strings(r.Do("SMEMBERS", key))
+}
+
+// SIsMember checks whether <member> is in set <key>.
+func (r *Redis) SIsMember(key string, member interface{}) (bool, error) {
+	return redis.Bool(r.Do("SISMEMBER", key, member))
+}
+
+// SCard returns the count of members of set <key>.
+func (r *Redis) SCard(key string) (int64, error) {
+	return redis.Int64(r.Do("SCARD", key))
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"time"
+
+	"github.com/gf/g/container/gvar"
+	"github.com/gomodule/redigo/redis"
+)
+
+// The typed command wrappers parse the replies into Go types,
+// they return the zero value without error if the key does not exist,
+// use the *Var methods and Var.IsNil to tell the non-existing key from the empty value.
+
+// GetVar returns the value of <key> as *gvar.Var, which is nil if <key> does not exist.
+func (r *Redis) GetVar(key string) (*gvar.Var, error) {
+	return r.DoVar("GET", key)
+}
+
+// GetString returns the value of <key> as string.
+func (r *Redis) GetString(key string) (string, error) {
+	v, err := redis.String(r.Do("GET", key))
+	return v, ignoreNil(err)
+}
+
+// GetBytes returns the value of <key> as []byte.
+func (r *Redis) GetBytes(key string) ([]byte, error) {
+	v, err := redis.Bytes(r.Do("GET", key))
+	return v, ignoreNil(err)
+}
+
+// GetInt64 returns the value of <key> as int64.
+func (r *Redis) GetInt64(key string) (int64, error) {
+	v, err := redis.Int64(r.Do("GET", key))
+	return v, ignoreNil(err)
+}
+
+// GetFloat64 returns the value of <key> as float64.
+func (r *Redis) GetFloat64(key string) (float64, error) {
+	v, err := redis.Float64(r.Do("GET", key))
+	return v, ignoreNil(err)
+}
+
+// Set sets <key> to <value>.
+func (r *Redis) Set(key string, value interface{}) error {
+	_, err := r.Do("SET", key, value)
+	return err
+}
+
+// SetEX sets <key> to <value> with expiration <ttl>, the precision of which is millisecond.
+func (r *Redis) SetEX(key string, value interface{}, ttl time.Duration) error {
+	_, err := r.Do("SET", append([]interface{}{key, value}, expireArgs(ttl)...)...)
+	return err
+}
+
+// SetNX sets <key> to <value> if <key> does not exist, and returns whether it is set.
+// The optional parameter <ttl> specifies the expiration of the key.
+func (r *Redis) SetNX(key string, value interface{}, ttl ...time.Duration) (bool, error) {
+	args := []interface{}{key, value}
+	if len(ttl) > 0 && ttl[0] > 0 {
+		args = append(args, expireArgs(ttl[0])...)
+	}
+	reply, err := r.Do("SET", append(args, "NX")...)
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// Incr increments the integer value of <key> by one, and returns the value after increment.
+func (r *Redis) Incr(key string) (int64, error) {
+	return redis.Int64(r.Do("INCR", key))
+}
+
+// IncrBy increments the integer value of <key> by <increment>, and returns the value after increment.
+func (r *Redis) IncrBy(key string, increment int64) (int64, error) {
+	return redis.Int64(r.Do("INCRBY", key, increment))
+}
+
+// Del deletes <keys>, and returns the count of the deleted keys.
+// In cluster mode, the keys should be in the same slot.
+func (r *Redis) Del(keys ...string) (int64, error) {
+	return redis.Int64(r.Do("DEL", stringsToInterfaces(keys)...))
+}
+
+// Exists checks whether <key> exists.
+func (r *Redis) Exists(key string) (bool, error) {
+	return redis.Bool(r.Do("EXISTS", key))
+}
+
+// Expire sets expiration <ttl> of <key>, the precision of which is millisecond,
+// and returns false if <key> does not exist.
+func (r *Redis) Expire(key string, ttl time.Duration) (bool, error) {
+	return redis.Bool(r.Do("PEXPIRE", key, int64(ttl/time.Millisecond)))
+}
+
+// TTL returns the remaining time to live of <key>.
+// It returns -1 if <key> has no expiration, and -2 if <key> does not exist, like command TTL.
+func (r *Redis) TTL(key string) (time.Duration, error) {
+	v, err := redis.Int64(r.Do("PTTL", key))
+	if err != nil || v < 0 {
+		return time.Duration(v), err
+	}
+	return time.Duration(v) * time.Millisecond, nil
+}
+
+// expireArgs returns the expiration arguments of command SET for <ttl>,
+// it uses EX if <ttl> is in seconds, or else PX.
+func expireArgs(ttl time.Duration) []interface{} {
+	if ttl%time.Second == 0 {
+		return []interface{}{"EX", int64(ttl / time.Second)}
+	}
+	return []interface{}{"PX", int64(ttl / time.Millisecond)}
+}
+
+// ignoreNil returns nil if <err> is redis.ErrNil, which means the key does not exist.
+func ignoreNil(err error) error {
+	if err == redis.ErrNil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"github.com/gomodule/redigo/redis"
+)
+
+// ZMember is a member of sorted set with its score.
+type ZMember struct {
+	Member string
+	Score  float64
+}
+
+// ZAdd adds <members> with their scores to sorted set <key>, or updates the scores if they exist,
+// and returns the count of the newly added members.
+func (r *Redis) ZAdd(key string, members ...ZMember) (int64, error) {
+	args := make([]interface{}, 0, 2*len(members)+1)
+	args = append(args, key)
+	for _, member := range members {
+		args = append(args, member.Score, member.Member)
+	}
+	return redis.Int64(r.Do("ZADD", args...))
+}
+
+// ZRem removes <members> from sorted set <key>, and returns the count of the removed members.
+func (r *Redis) ZRem(key string, members ...interface{}) (int64, error) {
+	return redis.Int64(r.Do("ZREM", append([]interface{}{key}, members...)...))
+}
+
+// ZScore returns the score of <member> in sorted set <key>,
+// the returned bool is false if the member does not exist.
+func (r *Redis) ZScore(key string, member interface{}) (float64, bool, error) {
+	v, err := redis.Float64(r.Do("ZSCORE", key, member))
+	if err == redis.ErrNil {
+		return 0, false, nil
+	}
+	return v, err == nil, err
+}
+
+// ZIncrBy increments the score of <member> in sorted set <key> by <increment>,
+// and returns the score after increment.
+func (r *Redis) ZIncrBy(key string, increment float64, member interface{}) (float64, error) {
+	return redis.Float64(r.Do("ZINCRBY", key, increment, member))
+}
+
+// ZCard returns the count of members of sorted set <key>.
+func (r *Redis) ZCard(key string) (int64, error) {
+	return redis.Int64(r.Do("ZCARD", key))
+}
+
+// ZRange returns the members in range [<start>, <stop>] of sorted set <key> in ascending order of score.
+func (r *Redis) ZRange(key string, start, stop int) ([]string, error) {
+	return redis.Strings(r.Do("ZRANGE", key, start, stop))
+}
+
+// ZRangeWithScores returns the members with scores in range [<start>, <stop>] of sorted set <key>
+// in ascending order of score.
+func (r *Redis) ZRangeWithScores(key string, start, stop int) ([]ZMember, error) {
+	return parseZMembers(r.Do("ZRANGE", key, start, stop, "WITHSCORES"))
+}
+
+// ZRevRangeWithScores returns the members with scores in range [<start>, <stop>] of sorted set <key>
+// in descending order of score.
+func (r *Redis) ZRevRangeWithScores(key string, start, stop int) ([]ZMember, error) {
+	return parseZMembers(r.Do("ZREVRANGE", key, start, stop, "WITHSCORES"))
+}
+
+// ZRangeByScoreWithScores returns the members with scores between <min> and <max> of sorted set <key>
+// in ascending order of score, the <min> and <max> can be exclusive like "(1" or infinite like "-inf".
+func (r *Redis) ZRangeByScoreWithScores(key string, min, max interface{}) ([]ZMember, error) {
+	return parseZMembers(r.Do("ZRANGEBYSCORE", key, min, max, "WITHSCORES"))
+}
+
+// parseZMembers parses the reply of member and score pairs.
+func parseZMembers(reply interface{}, err error) ([]ZMember, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]ZMember, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		member, err := redis.String(values[i], nil)
+		if err != nil {
+			return nil, err
+		}
+		score, err := redis.Float64(values[i+1], nil)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, ZMember{Member: member, Score: score})
+	}
+	return members, nil
+}
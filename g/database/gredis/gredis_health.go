@@ -0,0 +1,124 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gf/g/container/gmap"
+	"github.com/gf/g/os/gtimer"
+	"github.com/gomodule/redigo/redis"
+)
+
+var (
+	// Health checking timer entries, which is indexed by pool key.
+	healthCheckers = gmap.NewStrAnyMap()
+)
+
+// Ping sends PING command to the server using a connection from pool,
+// and returns error if the server is not available.
+// The optional parameter <timeout> specifies the timeout for reading the reply.
+func (r *Redis) Ping(timeout ...time.Duration) error {
+	conn := r.pool.Get()
+	defer conn.Close()
+	var err error
+	if len(timeout) > 0 && timeout[0] > 0 {
+		_, err = redis.DoWithTimeout(conn, timeout[0], "PING")
+	} else {
+		_, err = conn.Do("PING")
+	}
+	return err
+}
+
+// healthChecker is the background health checker of a connection pool,
+// which is shared by the clients using the same pool.
+type healthChecker struct {
+	pool   *redis.Pool   // Connection pool being checked.
+	config Config        // Configuration of the pool.
+	entry  *gtimer.Entry // Timer entry of the checking.
+	refs   int           // Count of the clients using the checker.
+}
+
+// startHealthCheck starts background health checking for the connection pool,
+// which is shared by clients using the same pool and reference counted by them.
+// It warms up the pool immediately in case of cold start.
+func (r *Redis) startHealthCheck() {
+	healthCheckers.LockFunc(func(m map[string]interface{}) {
+		key := fmt.Sprintf("%v", r.config)
+		if v, ok := m[key]; ok {
+			v.(*healthChecker).refs++
+			return
+		}
+		c := &healthChecker{
+			pool:   r.pool,
+			config: r.config,
+			refs:   1,
+		}
+		go c.check()
+		c.entry = gtimer.AddSingleton(r.config.HealthCheck, c.check)
+		m[key] = c
+	})
+}
+
+// stopHealthCheck releases the background health checking of the connection pool,
+// which is stopped only if no client uses the pool any more.
+func (r *Redis) stopHealthCheck() {
+	healthCheckers.LockFunc(func(m map[string]interface{}) {
+		key := fmt.Sprintf("%v", r.config)
+		if v, ok := m[key]; ok {
+			c := v.(*healthChecker)
+			if c.refs--; c.refs <= 0 {
+				c.entry.Close()
+				delete(m, key)
+			}
+		}
+	})
+}
+
+// testOnBorrow returns the function testing the idle connection borrowed from the pool.
+// It tests the connection by PING on every borrowing if health checking is disabled,
+// or else only if the connection has been idle longer than the health checking interval.
+func testOnBorrow(config Config) func(c redis.Conn, t time.Time) error {
+	return func(c redis.Conn, t time.Time) error {
+		if config.HealthCheck > 0 && time.Since(t) < config.HealthCheck {
+			return nil
+		}
+		_, err := c.Do("PING")
+		return err
+	}
+}
+
+// check borrows <MinIdle> connections from the pool, tests them by PING and puts them back.
+// The broken connections are closed and dropped from the pool, and new connections are
+// established if the pool has not enough idle ones, so that there are always <MinIdle>
+// warm connections in the pool, which avoids latency spikes after idle periods.
+func (c *healthChecker) check() {
+	count := c.config.MinIdle
+	if count <= 0 {
+		// It checks at least one connection if no warmup needed.
+		count = 1
+	}
+	conns := make([]redis.Conn, 0, count)
+	for i := 0; i < count; i++ {
+		conn := c.pool.Get()
+		// The error is returned if dialing fails or the pool is exhausted.
+		if conn.Err() != nil {
+			conn.Close()
+			break
+		}
+		// The connection in error state is closed instead of being put back on closing.
+		if _, err := conn.Do("PING"); err != nil {
+			conn.Close()
+			continue
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gf/g/container/gmap"
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/os/glog"
+	"github.com/gomodule/redigo/redis"
+)
+
+// CommandHook is called after each command sent by Do/DoCtx/DoWithTimeout and the typed command methods,
+// with the time <cost> of the command including waiting for the connection from pool.
+type CommandHook func(command string, args []interface{}, cost time.Duration, err error)
+
+// poolWaits is the statistics of waiting for connections from pool.
+type poolWaits struct {
+	count    *gtype.Int64 // Count of waiting.
+	duration *gtype.Int64 // Total waiting duration in nanoseconds.
+}
+
+var (
+	// Pool waiting statistics, which is indexed by pool key.
+	waits = gmap.NewStrAnyMap()
+)
+
+// OnCommand adds <hook> called after each command, eg: logging slow commands or exporting metrics.
+// The hooks are called synchronously in order, so they should return as soon as possible.
+// Note that the commands sent by the raw connection, Pipeline and Multi are not hooked.
+func (r *Redis) OnCommand(hook CommandHook) {
+	r.hookMu.Lock()
+	r.hooks = append(r.hooks, hook)
+	r.hookMu.Unlock()
+}
+
+// SlowLogHook returns a hook logging the commands costing no less than <threshold> using glog.
+func SlowLogHook(threshold time.Duration) CommandHook {
+	return func(command string, args []interface{}, cost time.Duration, err error) {
+		if cost < threshold {
+			return
+		}
+		if err != nil {
+			glog.Warningf("redis slow command: %s %v, cost: %v, error: %v", command, args, cost, err)
+		} else {
+			glog.Warningf("redis slow command: %s %v, cost: %v", command, args, cost)
+		}
+	}
+}
+
+// GroupStats returns the pool statistics of all the configuration groups, which is indexed by group name.
+// Only the instances created by Instance are included.
+func GroupStats() map[string]*PoolStats {
+	stats := make(map[string]*PoolStats)
+	instances.RLockFunc(func(m map[string]interface{}) {
+		for group, v := range m {
+			if r, ok := v.(*Redis); ok && r != nil {
+				stats[group] = r.Stats()
+			}
+		}
+	})
+	return stats
+}
+
+// callHooks calls the hooks of the command.
+func (r *Redis) callHooks(command string, args []interface{}, cost time.Duration, err error) {
+	r.hookMu.RLock()
+	hooks := r.hooks
+	r.hookMu.RUnlock()
+	for _, hook := range hooks {
+		hook(command, args, cost, err)
+	}
+}
+
+// hasHooks checks whether there's any hook.
+func (r *Redis) hasHooks() bool {
+	r.hookMu.RLock()
+	defer r.hookMu.RUnlock()
+	return len(r.hooks) > 0
+}
+
+// getPoolWaits returns the waiting statistics of the pool for <config>.
+func getPoolWaits(config Config) *poolWaits {
+	return waits.GetOrSetFuncLock(fmt.Sprintf("%v", config), func() interface{} {
+		return &poolWaits{
+			count:    gtype.NewInt64(),
+			duration: gtype.NewInt64(),
+		}
+	}).(*poolWaits)
+}
+
+// getConn gets a connection from pool using <ctx>, and records the waiting if the pool is exhausted
+// and Config.Wait is enabled.
+func (r *Redis) getConn(ctx context.Context) (redis.Conn, error) {
+	if !r.pool.Wait || r.pool.MaxActive <= 0 {
+		return r.pool.GetContext(ctx)
+	}
+	stats := r.pool.Stats()
+	if stats.ActiveCount-stats.IdleCount < r.pool.MaxActive {
+		return r.pool.GetContext(ctx)
+	}
+	start := time.Now()
+	conn, err := r.pool.GetContext(ctx)
+	r.waits.count.Add(1)
+	r.waits.duration.Add(int64(time.Since(start)))
+	return conn, err
+}
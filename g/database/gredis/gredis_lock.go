@@ -0,0 +1,180 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gf/g/util/grand"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Interval for retrying to obtain the lock held by others.
+	gLOCK_RETRY_INTERVAL = 50 * time.Millisecond
+	// Length of the random token identifying the lock owner.
+	gLOCK_TOKEN_LENGTH = 32
+)
+
+var (
+	// ErrLockNotObtained is returned by TryLock if the lock is held by others.
+	ErrLockNotObtained = errors.New("redis lock not obtained")
+	// ErrLockNotHeld is returned by Unlock/Refresh if the lock is expired or held by others.
+	ErrLockNotHeld = errors.New("redis lock not held")
+
+	// Deleting the key only if it's still held by the token.
+	lockUnlockScript = NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`)
+	// Renewing the expiration only if it's still held by the token.
+	lockRefreshScript = NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`)
+)
+
+// Lock is a distributed lock on a redis key, which is obtained by SET NX PX with a random token,
+// the token is verified on unlocking and refreshing, so that the lock obtained by others after
+// expiration is never released by mistake, eg:
+//
+//	lock, err := redis.Lock("lock:order:1", 10*time.Second)
+//	if err != nil {
+//		return err
+//	}
+//	defer lock.Unlock()
+//
+// Note that the lock expires after its ttl, which should be longer than the protected operation,
+// or else it should be renewed by Refresh or the watchdog of KeepAlive.
+type Lock struct {
+	mu     sync.Mutex
+	redis  *Redis
+	key    string
+	token  string
+	ttl    time.Duration
+	stop   chan struct{} // Closed to stop the watchdog, which is nil if the watchdog is not started.
+	closed bool          // Whether the lock is unlocked.
+}
+
+// TryLock tries to obtain the lock on <key> expiring after <ttl>, it returns ErrLockNotObtained
+// immediately if the lock is held by others.
+func (r *Redis) TryLock(key string, ttl time.Duration) (*Lock, error) {
+	if ttl < time.Millisecond {
+		return nil, errors.New("lock ttl should not be less than 1 millisecond")
+	}
+	token := grand.Str(gLOCK_TOKEN_LENGTH)
+	ok, err := r.SetNX(key, token, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLockNotObtained
+	}
+	return &Lock{
+		redis: r,
+		key:   key,
+		token: token,
+		ttl:   ttl,
+	}, nil
+}
+
+// Lock obtains the lock on <key> expiring after <ttl>, it blocks until the lock is obtained.
+func (r *Redis) Lock(key string, ttl time.Duration) (*Lock, error) {
+	return r.LockCtx(context.Background(), key, ttl)
+}
+
+// LockCtx acts like Lock, but it stops waiting and returns ctx.Err() if <ctx> is done.
+func (r *Redis) LockCtx(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	for {
+		lock, err := r.TryLock(key, ttl)
+		if err != ErrLockNotObtained {
+			return lock, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(gLOCK_RETRY_INTERVAL):
+		}
+	}
+}
+
+// Key returns the key of the lock.
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Token returns the random token identifying the owner of the lock.
+func (l *Lock) Token() string {
+	return l.token
+}
+
+// Refresh renews the expiration of the lock to <ttl>, the ttl of locking is used if <ttl> is not given.
+// It returns ErrLockNotHeld if the lock is expired or held by others.
+func (l *Lock) Refresh(ttl ...time.Duration) error {
+	d := l.ttl
+	if len(ttl) > 0 && ttl[0] >= time.Millisecond {
+		d = ttl[0]
+	}
+	n, err := redis.Int(lockRefreshScript.Do(l.redis, []string{l.key}, l.token, int64(d/time.Millisecond)))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// KeepAlive starts a watchdog goroutine, which refreshes the lock every one third of its ttl
+// until it is unlocked, so that the lock does not expire while the owner is still alive.
+// The watchdog stops if the lock is lost, eg: it's expired as the refreshing failed for a long time.
+func (l *Lock) KeepAlive() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stop != nil || l.closed {
+		return
+	}
+	l.stop = make(chan struct{})
+	go l.watchdog(l.stop)
+}
+
+// watchdog refreshes the lock in interval until <stop> is closed or the lock is lost.
+func (l *Lock) watchdog(stop chan struct{}) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			// The network error is ignored and retried in the next interval.
+			if err := l.Refresh(); err == ErrLockNotHeld {
+				return
+			}
+		}
+	}
+}
+
+// Unlock releases the lock and stops the watchdog.
+// It returns ErrLockNotHeld if the lock is expired or held by others.
+func (l *Lock) Unlock() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrLockNotHeld
+	}
+	l.closed = true
+	if l.stop != nil {
+		close(l.stop)
+	}
+	l.mu.Unlock()
+	n, err := redis.Int(lockUnlockScript.Do(l.redis, []string{l.key}, l.token))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+var (
+	// ErrTxAborted is returned by Multi if the transaction is aborted,
+	// which is commonly caused by the modification of the WATCHed keys.
+	ErrTxAborted = errors.New("redis transaction aborted")
+)
+
+// Pipeline buffers commands and sends them in one round-trip, eg:
+//
+// replies, err := r.Pipeline().Send("SET", "k1", "v1").Send("INCR", "k2").Exec()
+//
+// Note that the pipeline is not concurrent-safe.
+type Pipeline struct {
+	redis    *Redis
+	commands []pipelineCommand
+}
+
+// pipelineCommand is a buffered command of pipeline.
+type pipelineCommand struct {
+	name string
+	args []interface{}
+}
+
+// Pipeline creates and returns a pipeline of the client.
+func (r *Redis) Pipeline() *Pipeline {
+	return &Pipeline{
+		redis:    r,
+		commands: make([]pipelineCommand, 0),
+	}
+}
+
+// Send buffers the command, which is sent by Exec.
+func (p *Pipeline) Send(command string, args ...interface{}) *Pipeline {
+	p.commands = append(p.commands, pipelineCommand{command, args})
+	return p
+}
+
+// Len returns the count of the buffered commands.
+func (p *Pipeline) Len() int {
+	return len(p.commands)
+}
+
+// Exec sends all the buffered commands in one round-trip, and returns the replies in order.
+// The reply of the failed command is a redis.Error in the replies, and the first error is also returned.
+// The buffered commands are cleared after Exec, so the pipeline can be reused.
+// In cluster mode, the commands are sent one by one as they may belong to different nodes.
+func (p *Pipeline) Exec() ([]interface{}, error) {
+	commands := p.commands
+	p.commands = make([]pipelineCommand, 0)
+	if len(commands) == 0 {
+		return nil, nil
+	}
+	var (
+		replies  = make([]interface{}, len(commands))
+		firstErr error
+	)
+	if p.redis.cluster != nil {
+		for i, command := range commands {
+			reply, err := p.redis.cluster.Do(command.name, command.args...)
+			if err != nil {
+				if _, ok := err.(redis.Error); !ok {
+					return nil, err
+				}
+				reply = err
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+			replies[i] = reply
+		}
+		return replies, firstErr
+	}
+	conn := p.redis.pool.Get()
+	defer conn.Close()
+	for _, command := range commands {
+		if err := conn.Send(command.name, command.args...); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	for i := range commands {
+		reply, err := conn.Receive()
+		if err != nil {
+			if _, ok := err.(redis.Error); !ok {
+				return nil, err
+			}
+			reply = err
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		replies[i] = reply
+	}
+	return replies, firstErr
+}
+
+// Multi executes <f> in a transaction wrapped with MULTI/EXEC, and returns the replies of the queued commands.
+// The commands should be sent by tx.Send in <f>, which are queued and executed atomically by EXEC.
+// The transaction is discarded with DISCARD if <f> returns error, and the error is returned.
+// The optional parameter <watch> specifies the keys to WATCH before MULTI for optimistic locking,
+// and ErrTxAborted is returned if any of them is modified before EXEC.
+// It is not supported in cluster mode.
+func (r *Redis) Multi(f func(tx *Conn) error, watch ...string) ([]interface{}, error) {
+	if r.cluster != nil {
+		return nil, errors.New("transaction is not supported in redis cluster mode")
+	}
+	tx := &Conn{r.pool.Get()}
+	defer tx.Close()
+	if len(watch) > 0 {
+		if _, err := tx.Do("WATCH", stringsToInterfaces(watch)...); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Send("MULTI"); err != nil {
+		return nil, err
+	}
+	if err := f(tx); err != nil {
+		// DISCARD also unwatches all the keys.
+		tx.Do("DISCARD")
+		return nil, err
+	}
+	reply, err := tx.Do("EXEC")
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrTxAborted
+	}
+	return redis.Values(reply, nil)
+}
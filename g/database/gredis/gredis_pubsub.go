@@ -0,0 +1,282 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gf/g/container/gtype"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Interval of PING keep-alive for subscription connection.
+	gSUBSCRIPTION_PING_INTERVAL = 30 * time.Second
+	// Timeout for receiving from subscription connection, which should be greater than the PING interval.
+	gSUBSCRIPTION_READ_TIMEOUT = gSUBSCRIPTION_PING_INTERVAL + 10*time.Second
+	// Interval for reconnecting after the subscription connection is broken.
+	gSUBSCRIPTION_RETRY_INTERVAL = time.Second
+	// Buffer size of the message channel.
+	gSUBSCRIPTION_BUFFER_SIZE = 100
+)
+
+// Message is a message received from the subscribed channels.
+type Message struct {
+	Channel string // Channel the message is published to.
+	Pattern string // Matched pattern, which is empty if it is received by Subscribe.
+	Data    []byte // Message content.
+}
+
+// Subscription is a Pub/Sub subscription using a dedicated connection.
+// It keeps the connection alive with PING, and reconnects and resubscribes all the channels
+// and patterns automatically if the connection is broken.
+// The messages published while reconnecting are lost, which is the nature of redis Pub/Sub.
+type Subscription struct {
+	mu       sync.Mutex
+	redis    *Redis
+	conn     *redis.PubSubConn   // Current connection, which is nil while reconnecting.
+	channels map[string]struct{} // Subscribed channels.
+	patterns map[string]struct{} // Subscribed patterns.
+	messages chan *Message       // Received messages.
+	closed   *gtype.Bool         // Whether the subscription is closed.
+	done     chan struct{}       // Closed when the subscription is closed.
+}
+
+// Subscribe subscribes <channels> with a dedicated connection, and returns the subscription,
+// the messages of which can be received from Subscription.Channel.
+// The subscription MUST be closed by Subscription.Close if it's not used any more.
+func (r *Redis) Subscribe(channels ...string) (*Subscription, error) {
+	if len(channels) == 0 {
+		return nil, errors.New("channels cannot be empty")
+	}
+	s := newSubscription(r)
+	for _, channel := range channels {
+		s.channels[channel] = struct{}{}
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	go s.keepalive()
+	go s.receive()
+	return s, nil
+}
+
+// PSubscribe subscribes channels matching <patterns> with a dedicated connection, see Subscribe.
+func (r *Redis) PSubscribe(patterns ...string) (*Subscription, error) {
+	if len(patterns) == 0 {
+		return nil, errors.New("patterns cannot be empty")
+	}
+	s := newSubscription(r)
+	for _, pattern := range patterns {
+		s.patterns[pattern] = struct{}{}
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	go s.keepalive()
+	go s.receive()
+	return s, nil
+}
+
+// newSubscription creates and returns a subscription of <r> without connecting.
+func newSubscription(r *Redis) *Subscription {
+	return &Subscription{
+		redis:    r,
+		channels: make(map[string]struct{}),
+		patterns: make(map[string]struct{}),
+		messages: make(chan *Message, gSUBSCRIPTION_BUFFER_SIZE),
+		closed:   gtype.NewBool(),
+		done:     make(chan struct{}),
+	}
+}
+
+// Channel returns the channel of received messages, which is closed after the subscription is closed.
+func (s *Subscription) Channel() <-chan *Message {
+	return s.messages
+}
+
+// Subscribe subscribes more <channels> on the subscription.
+func (s *Subscription) Subscribe(channels ...string) error {
+	return s.update(channels, s.channels, true, func(c *redis.PubSubConn, args []interface{}) error {
+		return c.Subscribe(args...)
+	})
+}
+
+// PSubscribe subscribes more <patterns> on the subscription.
+func (s *Subscription) PSubscribe(patterns ...string) error {
+	return s.update(patterns, s.patterns, true, func(c *redis.PubSubConn, args []interface{}) error {
+		return c.PSubscribe(args...)
+	})
+}
+
+// Unsubscribe unsubscribes <channels> from the subscription.
+func (s *Subscription) Unsubscribe(channels ...string) error {
+	return s.update(channels, s.channels, false, func(c *redis.PubSubConn, args []interface{}) error {
+		return c.Unsubscribe(args...)
+	})
+}
+
+// PUnsubscribe unsubscribes <patterns> from the subscription.
+func (s *Subscription) PUnsubscribe(patterns ...string) error {
+	return s.update(patterns, s.patterns, false, func(c *redis.PubSubConn, args []interface{}) error {
+		return c.PUnsubscribe(args...)
+	})
+}
+
+// Channels returns the subscribed channels.
+func (s *Subscription) Channels() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return setKeys(s.channels)
+}
+
+// Patterns returns the subscribed patterns.
+func (s *Subscription) Patterns() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return setKeys(s.patterns)
+}
+
+// Close closes the subscription and its connection.
+func (s *Subscription) Close() error {
+	if s.closed.Set(true) {
+		return nil
+	}
+	close(s.done)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
+
+// update adds or removes <names> in <set>, and sends the command by <send> if connected.
+// The changes are applied on reconnecting if the connection is broken.
+func (s *Subscription) update(names []string, set map[string]struct{}, add bool, send func(*redis.PubSubConn, []interface{}) error) error {
+	if s.closed.Val() {
+		return errors.New("subscription is closed")
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		if add {
+			set[name] = struct{}{}
+		} else {
+			delete(set, name)
+		}
+		args[i] = name
+	}
+	if s.conn != nil {
+		return send(s.conn, args)
+	}
+	return nil
+}
+
+// connect dials a new connection, and subscribes all the channels and patterns.
+func (s *Subscription) connect() error {
+	c, err := s.redis.pool.Dial()
+	if err != nil {
+		return err
+	}
+	conn := &redis.PubSubConn{Conn: c}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed.Val() {
+		return conn.Close()
+	}
+	if len(s.channels) > 0 {
+		err = conn.Subscribe(stringsToInterfaces(setKeys(s.channels))...)
+	}
+	if err == nil && len(s.patterns) > 0 {
+		err = conn.PSubscribe(stringsToInterfaces(setKeys(s.patterns))...)
+	}
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// receive receives messages from the connection, and reconnects if the connection is broken,
+// until the subscription is closed.
+func (s *Subscription) receive() {
+	defer close(s.messages)
+	for !s.closed.Val() {
+		s.mu.Lock()
+		conn := s.conn
+		s.mu.Unlock()
+		if conn == nil {
+			if err := s.connect(); err != nil {
+				select {
+				case <-s.done:
+				case <-time.After(gSUBSCRIPTION_RETRY_INTERVAL):
+				}
+			}
+			continue
+		}
+		switch v := conn.ReceiveWithTimeout(gSUBSCRIPTION_READ_TIMEOUT).(type) {
+		case redis.Message:
+			select {
+			case s.messages <- &Message{Channel: v.Channel, Pattern: v.Pattern, Data: v.Data}:
+			case <-s.done:
+			}
+		case error:
+			s.mu.Lock()
+			if s.conn == conn {
+				s.conn = nil
+			}
+			s.mu.Unlock()
+			conn.Close()
+		}
+	}
+}
+
+// keepalive sends PING in interval to detect the broken connection,
+// which makes the receiving time out and reconnect.
+func (s *Subscription) keepalive() {
+	ticker := time.NewTicker(gSUBSCRIPTION_PING_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			// PING is only allowed in subscribed state, or else the reply cannot be received as pubsub notification.
+			if s.conn != nil && len(s.channels)+len(s.patterns) > 0 {
+				s.conn.Ping("")
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// setKeys returns the keys of <set>.
+func setKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// stringsToInterfaces converts <array> to []interface{}.
+func stringsToInterfaces(array []string) []interface{} {
+	result := make([]interface{}, len(array))
+	for i, v := range array {
+		result[i] = v
+	}
+	return result
+}
@@ -0,0 +1,195 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/util/grand"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Replica balancing policies.
+	REPLICA_POLICY_ROUND_ROBIN = "roundrobin"
+	REPLICA_POLICY_RANDOM      = "random"
+)
+
+var (
+	// Read-only commands which are routed to replicas.
+	readOnlyCommands = map[string]struct{}{
+		"GET": {}, "MGET": {}, "STRLEN": {}, "GETRANGE": {}, "GETBIT": {}, "BITCOUNT": {}, "BITPOS": {},
+		"EXISTS": {}, "TYPE": {}, "TTL": {}, "PTTL": {}, "SCAN": {},
+		"HGET": {}, "HMGET": {}, "HGETALL": {}, "HKEYS": {}, "HVALS": {}, "HLEN": {}, "HEXISTS": {}, "HSTRLEN": {}, "HSCAN": {},
+		"LRANGE": {}, "LLEN": {}, "LINDEX": {},
+		"SMEMBERS": {}, "SISMEMBER": {}, "SCARD": {}, "SRANDMEMBER": {}, "SINTER": {}, "SUNION": {}, "SDIFF": {}, "SSCAN": {},
+		"ZRANGE": {}, "ZREVRANGE": {}, "ZRANGEBYSCORE": {}, "ZREVRANGEBYSCORE": {}, "ZRANGEBYLEX": {}, "ZREVRANGEBYLEX": {},
+		"ZSCORE": {}, "ZCARD": {}, "ZCOUNT": {}, "ZLEXCOUNT": {}, "ZRANK": {}, "ZREVRANK": {}, "ZSCAN": {},
+		"PFCOUNT": {}, "GEOPOS": {}, "GEODIST": {}, "GEOHASH": {},
+	}
+)
+
+// masterContextKey is the context key marking the command should be sent to master.
+type masterContextKey struct{}
+
+// ReplicaBalancer picks the replica for a read-only command.
+type ReplicaBalancer interface {
+	// Pick returns the index of the picked replica in <addresses>.
+	Pick(addresses []string, command string, args []interface{}) int
+}
+
+// RoundRobinBalancer picks the replicas in turn.
+type RoundRobinBalancer struct {
+	next *gtype.Int
+}
+
+// RandomBalancer picks the replicas randomly.
+type RandomBalancer struct{}
+
+// replicas is the connection pools of the replicas.
+type replicas struct {
+	mu        sync.RWMutex
+	addresses []string
+	pools     []*redis.Pool
+	balancer  ReplicaBalancer
+}
+
+// NewRoundRobinBalancer creates and returns a round-robin balancer.
+func NewRoundRobinBalancer() *RoundRobinBalancer {
+	return &RoundRobinBalancer{next: gtype.NewInt()}
+}
+
+// Pick implements ReplicaBalancer.
+func (b *RoundRobinBalancer) Pick(addresses []string, command string, args []interface{}) int {
+	return (b.next.Add(1) - 1) % len(addresses)
+}
+
+// Pick implements ReplicaBalancer.
+func (b *RandomBalancer) Pick(addresses []string, command string, args []interface{}) int {
+	return grand.N(0, len(addresses)-1)
+}
+
+// SetReplicaBalancer sets the balancer picking the replica for read-only commands,
+// which overrides the ReplicaPolicy of configuration.
+func (r *Redis) SetReplicaBalancer(balancer ReplicaBalancer) {
+	if r.replicas != nil {
+		r.replicas.mu.Lock()
+		r.replicas.balancer = balancer
+		r.replicas.mu.Unlock()
+	}
+}
+
+// Replicas returns the addresses of the replicas, it returns nil if there's no replica configured.
+func (r *Redis) Replicas() []string {
+	if r.replicas == nil {
+		return nil
+	}
+	return r.replicas.addresses
+}
+
+// DoMaster acts like Do, but the command is always sent to the master even if it's read-only,
+// eg: reading the value just written, which may be not replicated yet.
+func (r *Redis) DoMaster(command string, args ...interface{}) (interface{}, error) {
+	return r.doWithTimeout(context.WithValue(context.Background(), masterContextKey{}, true), 0, command, args...)
+}
+
+// DoVarMaster returns value from DoMaster as gvar.Var.
+func (r *Redis) DoVarMaster(command string, args ...interface{}) (*gvar.Var, error) {
+	v, err := r.DoMaster(command, args...)
+	return gvar.New(v, true), err
+}
+
+// newReplicas creates the connection pools of the replicas in <config>, it returns nil if there's no replica.
+func newReplicas(config Config) *replicas {
+	addresses := make([]string, 0)
+	for _, address := range strings.Split(config.Replicas, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	rs := &replicas{
+		addresses: addresses,
+		pools:     make([]*redis.Pool, len(addresses)),
+	}
+	for i, address := range addresses {
+		rs.pools[i] = pools.GetOrSetFuncLock(replicaPoolKey(config, address), func() interface{} {
+			return newPool(config, address)
+		}).(*redis.Pool)
+	}
+	switch strings.ToLower(config.ReplicaPolicy) {
+	case REPLICA_POLICY_RANDOM:
+		rs.balancer = &RandomBalancer{}
+	default:
+		rs.balancer = NewRoundRobinBalancer()
+	}
+	return rs
+}
+
+// replicaPoolKey returns the key of the replica pool of <address> in pool map.
+func replicaPoolKey(config Config, address string) string {
+	return fmt.Sprintf("%v@%s", config, address)
+}
+
+// close closes the connection pools of the replicas.
+func (rs *replicas) close(config Config) {
+	for i, address := range rs.addresses {
+		pools.Remove(replicaPoolKey(config, address))
+		rs.pools[i].Close()
+	}
+}
+
+// do sends the read-only command to a replica picked by the balancer with read <timeout>,
+// it returns false if it fails on the replica, and the command should be sent to master.
+func (rs *replicas) do(timeout time.Duration, command string, args ...interface{}) (reply interface{}, ok bool, err error) {
+	rs.mu.RLock()
+	balancer := rs.balancer
+	rs.mu.RUnlock()
+	index := balancer.Pick(rs.addresses, command, args)
+	if index < 0 || index >= len(rs.pools) {
+		return nil, false, nil
+	}
+	conn := rs.pools[index].Get()
+	defer conn.Close()
+	reply, err = doConn(conn, timeout, command, args...)
+	if err != nil && isReplicaUnavailable(err) {
+		return nil, false, err
+	}
+	return reply, true, err
+}
+
+// isReplicaCommand checks whether <command> can be sent to replicas with <ctx>.
+func isReplicaCommand(ctx context.Context, command string) bool {
+	if ctx.Value(masterContextKey{}) != nil {
+		return false
+	}
+	_, ok := readOnlyCommands[strings.ToUpper(command)]
+	return ok
+}
+
+// isReplicaUnavailable checks whether <err> means that the replica is unavailable,
+// like connection errors, or the replica is loading or disconnected from master.
+func isReplicaUnavailable(err error) bool {
+	switch e := err.(type) {
+	case redis.Error:
+		s := string(e)
+		return strings.HasPrefix(s, "LOADING") || strings.HasPrefix(s, "MASTERDOWN")
+	case net.Error:
+		return true
+	}
+	return err == redis.ErrPoolExhausted || err == io.EOF || err == io.ErrUnexpectedEOF
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/gf/g/container/gset"
+	"github.com/gf/g/container/gvar"
+	"github.com/gomodule/redigo/redis"
+)
+
+// Script is a Lua script, which is executed by EVALSHA with its SHA1 digest,
+// so that the script body is not sent on every call, eg:
+//
+// var incrScript = gredis.NewScript(`return redis.call("INCRBY", KEYS[1], ARGV[1])`)
+//
+// v, err := incrScript.Do(redis, []string{"counter"}, 10)
+//
+// The script is loaded by SCRIPT LOAD automatically before it's firstly executed on a pool,
+// and it falls back to EVAL if the server replies NOSCRIPT, eg: the script cache is flushed
+// or the command is executed on another node in cluster mode.
+// Script is concurrent-safe and can be shared by multiple clients.
+type Script struct {
+	src    string
+	sha    string
+	loaded *gset.StringSet // Pool keys of the pools on which the script is loaded.
+}
+
+// NewScript creates and returns a script object with Lua source <src>.
+func NewScript(src string) *Script {
+	h := sha1.Sum([]byte(src))
+	return &Script{
+		src:    src,
+		sha:    hex.EncodeToString(h[:]),
+		loaded: gset.NewStringSet(),
+	}
+}
+
+// Hash returns the SHA1 digest of the script.
+func (s *Script) Hash() string {
+	return s.sha
+}
+
+// Load loads the script to the server of <r> by SCRIPT LOAD.
+// It's not necessary to call Load manually, as Do loads the script automatically.
+func (s *Script) Load(r *Redis) error {
+	sha, err := redis.String(r.Do("SCRIPT", "LOAD", s.src))
+	if err != nil {
+		return err
+	}
+	if sha != s.sha {
+		return fmt.Errorf(`unexpected script hash "%s", expect "%s"`, sha, s.sha)
+	}
+	s.loaded.Add(fmt.Sprintf("%v", r.config))
+	return nil
+}
+
+// Do executes the script with <keys> and <args> on <r> by EVALSHA, and returns the reply.
+// The <keys> and <args> are accessible as KEYS and ARGV in the script.
+// In cluster mode, the keys should be in the same slot.
+func (s *Script) Do(r *Redis, keys []string, args ...interface{}) (interface{}, error) {
+	key := fmt.Sprintf("%v", r.config)
+	if !s.loaded.Contains(key) {
+		if err := s.Load(r); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := r.Do("EVALSHA", s.args(s.sha, keys, args)...)
+	if e, ok := err.(redis.Error); ok && strings.HasPrefix(string(e), "NOSCRIPT") {
+		// The script cache of server is flushed, it's reloaded on the next call.
+		s.loaded.Remove(key)
+		reply, err = r.Do("EVAL", s.args(s.src, keys, args)...)
+	}
+	return reply, err
+}
+
+// DoVar returns value from Do as gvar.Var.
+func (s *Script) DoVar(r *Redis, keys []string, args ...interface{}) (*gvar.Var, error) {
+	v, err := s.Do(r, keys, args...)
+	return gvar.New(v, true), err
+}
+
+// args builds the arguments for EVAL/EVALSHA with <script> which is source or SHA1 digest.
+func (s *Script) args(script string, keys []string, args []interface{}) []interface{} {
+	result := make([]interface{}, 0, 2+len(keys)+len(args))
+	result = append(result, script, len(keys))
+	for _, key := range keys {
+		result = append(result, key)
+	}
+	return append(result, args...)
+}
@@ -0,0 +1,292 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gf/g/container/gmap"
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/util/gconv"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Default port of sentinel.
+	gSENTINEL_DEFAULT_PORT = 26379
+	// Timeout for connecting and querying sentinels.
+	gSENTINEL_TIMEOUT = time.Second
+	// Interval for reconnecting sentinels when the switching watcher is broken.
+	gSENTINEL_RETRY_INTERVAL = time.Second
+)
+
+var (
+	// Sentinel clients, which is indexed by pool key.
+	sentinels = gmap.NewStrAnyMap()
+)
+
+// sentinel discovers the master address from redis sentinels,
+// and watches the "+switch-master" event to follow the failover.
+type sentinel struct {
+	mu        sync.RWMutex
+	config    Config
+	addresses []string    // Sentinel addresses, the available one is moved to the front.
+	master    string      // Current master address.
+	closed    *gtype.Bool // Whether the sentinel client is closed.
+	watchConn redis.Conn  // Connection for watching the switching, which is closed to stop watching.
+	watchOnce sync.Once   // Ensures that the watcher is started only once.
+}
+
+// sentinelConn is the connection to master, which remembers the address it is dialed to,
+// so that the connection can be dropped after the master is switched.
+type sentinelConn struct {
+	redis.Conn
+	address string
+}
+
+// isSentinelConfig checks whether <config> is for redis sentinel.
+func isSentinelConfig(config Config) bool {
+	return config.Sentinels != ""
+}
+
+// getSentinel returns the sentinel client for <config>, which is shared by the clients using the same pool.
+func getSentinel(config Config) *sentinel {
+	return sentinels.GetOrSetFuncLock(fmt.Sprintf("%v", config), func() interface{} {
+		return &sentinel{
+			config:    config,
+			addresses: parseClusterHosts(config.Sentinels, gSENTINEL_DEFAULT_PORT),
+			closed:    gtype.NewBool(),
+		}
+	}).(*sentinel)
+}
+
+// removeSentinel closes and removes the sentinel client for <config>.
+func removeSentinel(config Config) {
+	if v := sentinels.Remove(fmt.Sprintf("%v", config)); v != nil {
+		v.(*sentinel).Close()
+	}
+}
+
+// newPool creates and returns a connection pool, the connections of which are dialed to the current master.
+// The connections to the former master are closed on borrowing after failover.
+func (s *sentinel) newPool() *redis.Pool {
+	pool := newPool(s.config, "")
+	pool.Dial = s.dial
+	test := pool.TestOnBorrow
+	pool.TestOnBorrow = func(c redis.Conn, t time.Time) error {
+		if conn, ok := c.(*sentinelConn); ok && conn.address != s.Master() {
+			return errors.New("redis master switched")
+		}
+		return test(c, t)
+	}
+	return pool
+}
+
+// Master returns the current master address, it returns empty string if it's not discovered yet.
+func (s *sentinel) Master() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.master
+}
+
+// setMaster sets the current master address.
+func (s *sentinel) setMaster(address string) {
+	s.mu.Lock()
+	s.master = address
+	s.mu.Unlock()
+}
+
+// Close stops watching the switching.
+func (s *sentinel) Close() error {
+	s.closed.Set(true)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchConn != nil {
+		return s.watchConn.Close()
+	}
+	return nil
+}
+
+// dial dials to the current master, it rediscovers the master from sentinels
+// if the dialing fails or the dialed server is not master any more.
+func (s *sentinel) dial() (redis.Conn, error) {
+	s.watchOnce.Do(func() {
+		go s.watch()
+	})
+	var lastErr error
+	for i := 0; i < 2; i++ {
+		address := s.Master()
+		if address == "" || i > 0 {
+			var err error
+			if address, err = s.discover(); err != nil {
+				return nil, err
+			}
+		}
+		c, err := redis.Dial("tcp", address, dialOptions(s.config)...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := checkMasterRole(c); err != nil {
+			c.Close()
+			lastErr = err
+			continue
+		}
+		if err := initConn(c, s.config); err != nil {
+			c.Close()
+			return nil, err
+		}
+		return &sentinelConn{Conn: c, address: address}, nil
+	}
+	return nil, lastErr
+}
+
+// discover queries the master address from sentinels in order, and updates the current master.
+// The sentinel replying is moved to the front, so that it is queried first next time.
+func (s *sentinel) discover() (string, error) {
+	s.mu.RLock()
+	addresses := make([]string, len(s.addresses))
+	copy(addresses, s.addresses)
+	s.mu.RUnlock()
+	var lastErr error = errors.New("no sentinel address configured")
+	for i, address := range addresses {
+		master, err := s.queryMaster(address)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		s.mu.Lock()
+		s.master = master
+		if i > 0 {
+			s.addresses = append([]string{address}, append(addresses[:i:i], addresses[i+1:]...)...)
+		}
+		s.mu.Unlock()
+		return master, nil
+	}
+	return "", fmt.Errorf(`cannot discover redis master "%s" from sentinels: %v`, s.config.MasterName, lastErr)
+}
+
+// queryMaster queries the master address from the sentinel of <address>.
+func (s *sentinel) queryMaster(address string) (string, error) {
+	c, err := dialSentinel(address)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+	reply, err := redis.Strings(c.Do("SENTINEL", "get-master-addr-by-name", s.config.MasterName))
+	if err != nil {
+		return "", err
+	}
+	if len(reply) != 2 {
+		return "", fmt.Errorf(`master "%s" is unknown by sentinel %s`, s.config.MasterName, address)
+	}
+	return net.JoinHostPort(reply[0], reply[1]), nil
+}
+
+// watch subscribes the "+switch-master" event from sentinels, and updates the current master on switching.
+// It reconnects to the sentinels if the connection is broken, until the sentinel client is closed.
+func (s *sentinel) watch() {
+	for !s.closed.Val() {
+		if err := s.subscribe(); err != nil && !s.closed.Val() {
+			time.Sleep(gSENTINEL_RETRY_INTERVAL)
+		}
+	}
+}
+
+// subscribe subscribes the "+switch-master" event from the first available sentinel,
+// and blocks until the connection is broken.
+func (s *sentinel) subscribe() error {
+	s.mu.RLock()
+	addresses := make([]string, len(s.addresses))
+	copy(addresses, s.addresses)
+	s.mu.RUnlock()
+	var (
+		conn    redis.Conn
+		lastErr error = errors.New("no sentinel address configured")
+	)
+	for _, address := range addresses {
+		c, err := redis.Dial("tcp", address, redis.DialConnectTimeout(gSENTINEL_TIMEOUT))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		conn = c
+		break
+	}
+	if conn == nil {
+		return lastErr
+	}
+	s.mu.Lock()
+	if s.closed.Val() {
+		s.mu.Unlock()
+		return conn.Close()
+	}
+	s.watchConn = conn
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.watchConn = nil
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	psc := redis.PubSubConn{Conn: conn}
+	if err := psc.Subscribe("+switch-master"); err != nil {
+		return err
+	}
+	// The switching may be missed while the watcher was disconnected.
+	s.discover()
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			// Format: <master name> <old ip> <old port> <new ip> <new port>
+			array := strings.Fields(string(v.Data))
+			if len(array) == 5 && array[0] == s.config.MasterName {
+				s.setMaster(net.JoinHostPort(array[3], array[4]))
+			}
+		case error:
+			return v
+		}
+	}
+}
+
+// dialSentinel dials to the sentinel of <address> with timeout.
+func dialSentinel(address string) (redis.Conn, error) {
+	return redis.Dial(
+		"tcp",
+		address,
+		redis.DialConnectTimeout(gSENTINEL_TIMEOUT),
+		redis.DialReadTimeout(gSENTINEL_TIMEOUT),
+		redis.DialWriteTimeout(gSENTINEL_TIMEOUT),
+	)
+}
+
+// checkMasterRole checks whether the server of <c> is master by command ROLE.
+func checkMasterRole(c redis.Conn) error {
+	reply, err := redis.Values(c.Do("ROLE"))
+	if err != nil {
+		return err
+	}
+	if len(reply) == 0 || gconv.String(reply[0]) != "master" {
+		return errors.New("redis server is not master")
+	}
+	return nil
+}
+
+// DoWithTimeout implements redis.ConnWithTimeout.
+func (c *sentinelConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, command, args...)
+}
+
+// ReceiveWithTimeout implements redis.ConnWithTimeout.
+func (c *sentinelConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gredis
+
+import (
+	"fmt"
+
+	"github.com/gf/g/container/gmap"
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/os/glog"
+	"github.com/gf/g/os/gtimer"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Sampling times of the pool statistics in a tuning interval.
+	gPOOL_TUNE_SAMPLES = 10
+	// Minimum dialing count in a tuning interval to be considered as churn.
+	gPOOL_CHURN_MIN_DIALS = 10
+)
+
+var (
+	// Pool tuners, which is indexed by pool key.
+	tuners = gmap.NewStrAnyMap()
+)
+
+// poolTuner sizes the MaxIdle of the pool based on the observed concurrency.
+type poolTuner struct {
+	pool    *redis.Pool
+	config  Config
+	dials   *gtype.Int // Count of dialed connections in current interval.
+	peak    int        // Peak count of in-use connections in current interval.
+	samples int        // Sampling times in current interval.
+	entry   *gtimer.Entry
+}
+
+// startPoolTuner starts auto-tuning for <pool> with <config>, which should be called before
+// the pool is used, as it wraps the Dial function of the pool to count the dialing.
+func startPoolTuner(pool *redis.Pool, config Config) {
+	t := &poolTuner{
+		pool:   pool,
+		config: config,
+		dials:  gtype.NewInt(),
+	}
+	dial := pool.Dial
+	pool.Dial = func() (redis.Conn, error) {
+		t.dials.Add(1)
+		return dial()
+	}
+	t.entry = gtimer.AddSingleton(config.AutoTune/gPOOL_TUNE_SAMPLES, t.sample)
+	tuners.Set(fmt.Sprintf("%v", config), t)
+}
+
+// stopPoolTuner stops the auto-tuning of the pool for <config>.
+func stopPoolTuner(config Config) {
+	if v := tuners.Remove(fmt.Sprintf("%v", config)); v != nil {
+		v.(*poolTuner).entry.Close()
+	}
+}
+
+// sample records the peak count of in-use connections, and tunes the pool at the end of the interval.
+func (t *poolTuner) sample() {
+	stats := t.pool.Stats()
+	if inUse := stats.ActiveCount - stats.IdleCount; inUse > t.peak {
+		t.peak = inUse
+	}
+	if t.samples++; t.samples < gPOOL_TUNE_SAMPLES {
+		return
+	}
+	peak, dials := t.peak, t.dials.Set(0)
+	t.peak, t.samples = 0, 0
+	t.tune(peak, dials)
+}
+
+// tune adjusts the MaxIdle of the pool to <peak> concurrency in the last interval,
+// it grows immediately but shrinks by half of the difference each time to avoid oscillation.
+// The MaxIdle is not less than MinIdle and not greater than MaxActive of the configuration.
+// It logs a warning if too many connections are dialed in the last interval, which means that
+// the connections are closed and dialed again frequently as the MaxIdle is too small.
+func (t *poolTuner) tune(peak int, dials int) {
+	maxIdle := t.pool.MaxIdle
+	target := maxIdle
+	if peak > maxIdle {
+		target = peak
+	} else if peak < maxIdle {
+		target = maxIdle - (maxIdle-peak)/2
+	}
+	if target < t.config.MinIdle {
+		target = t.config.MinIdle
+	}
+	if target < 1 {
+		target = 1
+	}
+	if t.config.MaxActive > 0 && target > t.config.MaxActive {
+		target = t.config.MaxActive
+	}
+	if dials >= gPOOL_CHURN_MIN_DIALS && dials > 2*peak {
+		glog.Warningf(
+			`[gredis] connection churn detected: %d connections dialed in %v with peak concurrency %d, MaxIdle is %d and tuned to %d`,
+			dials, t.config.AutoTune, peak, maxIdle, target,
+		)
+	}
+	if target != maxIdle {
+		t.pool.MaxIdle = target
+		// The pool statistics is retrieved with the lock of the pool,
+		// which makes the change visible to the pool.
+		t.pool.Stats()
+	}
+}
@@ -7,6 +7,11 @@
 package gredis_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/gogf/gf/g/database/gredis"
 	"github.com/gogf/gf/g/test/gtest"
 	redis2 "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
@@ -207,3 +212,536 @@ func Test_Basic(t *testing.T) {
 		time.Sleep(time.Second)
 	})
 }
+
+func Test_Ping(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		gtest.Assert(redis.Ping(), nil)
+		gtest.Assert(redis.Ping(time.Second), nil)
+	})
+	gtest.Case(t, func() {
+		redis := gredis.New(gredis.Config{
+			Host: "127.0.0.1",
+			Port: 1,
+		})
+		defer redis.Close()
+		gtest.AssertNE(redis.Ping(time.Second), nil)
+	})
+}
+
+func Test_HealthCheck(t *testing.T) {
+	gtest.Case(t, func() {
+		c := config
+		c.MinIdle = 3
+		c.HealthCheck = 100 * time.Millisecond
+		redis := gredis.New(c)
+		defer redis.Close()
+		time.Sleep(500 * time.Millisecond)
+		gtest.Assert(redis.Stats().IdleCount, 3)
+	})
+}
+
+func Test_AutoTune(t *testing.T) {
+	gtest.Case(t, func() {
+		c := config
+		c.MaxIdle = 2
+		c.AutoTune = 500 * time.Millisecond
+		redis := gredis.New(c)
+		defer redis.Close()
+		array := make([]*gredis.Conn, 0)
+		for i := 0; i < 6; i++ {
+			conn := redis.Conn()
+			gtest.Assert(conn.Err(), nil)
+			array = append(array, conn)
+		}
+		time.Sleep(700 * time.Millisecond)
+		for _, conn := range array {
+			conn.Close()
+		}
+		// The MaxIdle is tuned to the peak concurrency.
+		gtest.Assert(redis.Stats().IdleCount, 6)
+	})
+}
+
+func Test_Cluster_Slot(t *testing.T) {
+	gtest.Case(t, func() {
+		gtest.Assert(gredis.Slot("123456789"), 12739)
+		gtest.Assert(gredis.Slot("foo"), 12182)
+		gtest.Assert(gredis.Slot("{user1000}.following"), gredis.Slot("{user1000}.followers"))
+		gtest.Assert(gredis.Slot("{user1000}.following"), gredis.Slot("user1000"))
+		// Empty hash tag is not used.
+		gtest.Assert(gredis.Slot("foo{}{bar}"), gredis.Slot("foo{}{bar}"))
+		gtest.AssertNE(gredis.Slot("foo{}{bar}"), gredis.Slot("bar"))
+	})
+}
+
+func Test_Sentinel_Unavailable(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(gredis.Config{
+			Sentinels:  "127.0.0.1:1",
+			MasterName: "mymaster",
+		})
+		defer redis.Close()
+		_, err := redis.Do("PING")
+		gtest.AssertNE(err, nil)
+		gtest.Assert(redis.SentinelMaster(), "")
+	})
+}
+
+func Test_Subscribe(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		sub, err := redis.Subscribe("gf.channel1")
+		gtest.Assert(err, nil)
+		defer sub.Close()
+		gtest.Assert(sub.PSubscribe("gf.pattern.*"), nil)
+		gtest.Assert(sub.Channels(), []string{"gf.channel1"})
+		gtest.Assert(sub.Patterns(), []string{"gf.pattern.*"})
+		time.Sleep(100 * time.Millisecond)
+
+		_, err = redis.Do("PUBLISH", "gf.channel1", "v1")
+		gtest.Assert(err, nil)
+		msg := <-sub.Channel()
+		gtest.Assert(msg.Channel, "gf.channel1")
+		gtest.Assert(msg.Pattern, "")
+		gtest.Assert(msg.Data, []byte("v1"))
+
+		_, err = redis.Do("PUBLISH", "gf.pattern.1", "v2")
+		gtest.Assert(err, nil)
+		msg = <-sub.Channel()
+		gtest.Assert(msg.Channel, "gf.pattern.1")
+		gtest.Assert(msg.Pattern, "gf.pattern.*")
+		gtest.Assert(msg.Data, []byte("v2"))
+
+		gtest.Assert(sub.Close(), nil)
+		_, ok := <-sub.Channel()
+		gtest.Assert(ok, false)
+	})
+}
+
+func Test_Pipeline(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		pipeline := redis.Pipeline()
+		pipeline.Send("SET", "gf.pipeline", 1).Send("INCR", "gf.pipeline").Send("GET", "gf.pipeline")
+		gtest.Assert(pipeline.Len(), 3)
+		replies, err := pipeline.Exec()
+		gtest.Assert(err, nil)
+		gtest.Assert(len(replies), 3)
+		gtest.Assert(replies[1], 2)
+		gtest.Assert(replies[2], []byte("2"))
+		gtest.Assert(pipeline.Len(), 0)
+
+		// Command error.
+		replies, err = pipeline.Send("SET", "gf.pipeline", "v").Send("INCR", "gf.pipeline").Exec()
+		gtest.AssertNE(err, nil)
+		gtest.Assert(len(replies), 2)
+		gtest.Assert(replies[0], "OK")
+
+		_, err = redis.Do("DEL", "gf.pipeline")
+		gtest.Assert(err, nil)
+	})
+}
+
+func Test_Multi(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		replies, err := redis.Multi(func(tx *gredis.Conn) error {
+			tx.Send("SET", "gf.multi", 1)
+			tx.Send("INCR", "gf.multi")
+			return nil
+		})
+		gtest.Assert(err, nil)
+		gtest.Assert(replies, []interface{}{"OK", 2})
+
+		// Discarded.
+		_, err = redis.Multi(func(tx *gredis.Conn) error {
+			tx.Send("INCR", "gf.multi")
+			return errors.New("discard")
+		})
+		gtest.Assert(err, errors.New("discard"))
+		v, _ := redis.DoVar("GET", "gf.multi")
+		gtest.Assert(v.Int(), 2)
+
+		// Aborted by modification of the watched key.
+		_, err = redis.Multi(func(tx *gredis.Conn) error {
+			redis.Do("INCR", "gf.multi")
+			return tx.Send("INCR", "gf.multi")
+		}, "gf.multi")
+		gtest.Assert(err, gredis.ErrTxAborted)
+		v, _ = redis.DoVar("GET", "gf.multi")
+		gtest.Assert(v.Int(), 3)
+
+		_, err = redis.Do("DEL", "gf.multi")
+		gtest.Assert(err, nil)
+	})
+}
+
+func Test_Command_String(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		defer redis.Del("gf.cmd.string")
+
+		v, err := redis.GetVar("gf.cmd.string")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.IsNil(), true)
+		s, err := redis.GetString("gf.cmd.string")
+		gtest.Assert(err, nil)
+		gtest.Assert(s, "")
+
+		gtest.Assert(redis.Set("gf.cmd.string", 1), nil)
+		n, err := redis.Incr("gf.cmd.string")
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 2)
+		n, err = redis.IncrBy("gf.cmd.string", 10)
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 12)
+		n, err = redis.GetInt64("gf.cmd.string")
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 12)
+
+		ok, err := redis.SetNX("gf.cmd.string", "v")
+		gtest.Assert(err, nil)
+		gtest.Assert(ok, false)
+		gtest.Assert(redis.SetEX("gf.cmd.string", "v", time.Minute), nil)
+		s, err = redis.GetString("gf.cmd.string")
+		gtest.Assert(err, nil)
+		gtest.Assert(s, "v")
+
+		n, err = redis.Del("gf.cmd.string")
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 1)
+		ok, err = redis.SetNX("gf.cmd.string", "v", time.Minute)
+		gtest.Assert(err, nil)
+		gtest.Assert(ok, true)
+	})
+}
+
+func Test_Command_Hash(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		defer redis.Del("gf.cmd.hash")
+
+		m, err := redis.HGetAllMap("gf.cmd.hash")
+		gtest.Assert(err, nil)
+		gtest.Assert(len(m), 0)
+
+		gtest.Assert(redis.HMSet("gf.cmd.hash", map[string]interface{}{"a": 1, "b": "x"}), nil)
+		ok, err := redis.HSet("gf.cmd.hash", "c", 3)
+		gtest.Assert(err, nil)
+		gtest.Assert(ok, true)
+		m, err = redis.HGetAllMap("gf.cmd.hash")
+		gtest.Assert(err, nil)
+		gtest.Assert(m, map[string]string{"a": "1", "b": "x", "c": "3"})
+
+		n, err := redis.HGetInt64("gf.cmd.hash", "c")
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 3)
+		v, err := redis.HGetVar("gf.cmd.hash", "none")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.IsNil(), true)
+
+		n, err = redis.HDel("gf.cmd.hash", "a", "none")
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 1)
+	})
+}
+
+func Test_Command_Set(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		defer redis.Del("gf.cmd.set")
+
+		n, err := redis.SAdd("gf.cmd.set", "a", "b", "a")
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 2)
+		members, err := redis.SMembers("gf.cmd.set")
+		gtest.Assert(err, nil)
+		sort.Strings(members)
+		gtest.Assert(members, []string{"a", "b"})
+		ok, err := redis.SIsMember("gf.cmd.set", "b")
+		gtest.Assert(err, nil)
+		gtest.Assert(ok, true)
+		ok, err = redis.SIsMember("gf.cmd.set", "c")
+		gtest.Assert(err, nil)
+		gtest.Assert(ok, false)
+	})
+}
+
+func Test_Command_ZSet(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		defer redis.Del("gf.cmd.zset")
+
+		n, err := redis.ZAdd("gf.cmd.zset", gredis.ZMember{"a", 1}, gredis.ZMember{"b", 2.5}, gredis.ZMember{"c", 2})
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 3)
+		members, err := redis.ZRangeWithScores("gf.cmd.zset", 0, -1)
+		gtest.Assert(err, nil)
+		gtest.Assert(members, []gredis.ZMember{{"a", 1}, {"c", 2}, {"b", 2.5}})
+		members, err = redis.ZRevRangeWithScores("gf.cmd.zset", 0, 0)
+		gtest.Assert(err, nil)
+		gtest.Assert(members, []gredis.ZMember{{"b", 2.5}})
+
+		score, ok, err := redis.ZScore("gf.cmd.zset", "c")
+		gtest.Assert(err, nil)
+		gtest.Assert(ok, true)
+		gtest.Assert(score, 2)
+		_, ok, err = redis.ZScore("gf.cmd.zset", "none")
+		gtest.Assert(err, nil)
+		gtest.Assert(ok, false)
+	})
+}
+
+func Test_DoCtx(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := redis.DoCtx(ctx, "SET", "gf.ctx", 1)
+		gtest.Assert(err, nil)
+		v, err := redis.DoVarCtx(ctx, "GET", "gf.ctx")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.Int(), 1)
+
+		// The slow command is canceled by the deadline.
+		ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err = redis.DoCtx(ctx, "DEBUG", "SLEEP", 0.5)
+		gtest.Assert(err, context.DeadlineExceeded)
+		gtest.Assert(time.Since(start) < 400*time.Millisecond, true)
+
+		_, err = redis.DoWithTimeout(100*time.Millisecond, "DEBUG", "SLEEP", 0.5)
+		gtest.AssertNE(err, nil)
+
+		_, err = redis.DoCtx(ctx, "GET", "gf.ctx")
+		gtest.Assert(err, context.DeadlineExceeded)
+
+		_, err = redis.Do("DEL", "gf.ctx")
+		gtest.Assert(err, nil)
+	})
+}
+
+func Test_Script(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		script := gredis.NewScript(`return redis.call("INCRBY", KEYS[1], ARGV[1])`)
+		gtest.Assert(script.Hash(), "7d6a962aa4923dd6a700f73ce6ad148d2fc16ec9")
+		defer redis.Do("DEL", "gf.script")
+
+		v, err := script.DoVar(redis, []string{"gf.script"}, 10)
+		gtest.Assert(err, nil)
+		gtest.Assert(v.Int(), 10)
+
+		// The script is executed by EVAL after the script cache is flushed.
+		_, err = redis.Do("SCRIPT", "FLUSH")
+		gtest.Assert(err, nil)
+		v, err = script.DoVar(redis, []string{"gf.script"}, 5)
+		gtest.Assert(err, nil)
+		gtest.Assert(v.Int(), 15)
+		v, err = script.DoVar(redis, []string{"gf.script"}, 5)
+		gtest.Assert(err, nil)
+		gtest.Assert(v.Int(), 20)
+	})
+}
+
+func Test_Lock(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		defer redis.Do("DEL", "gf.lock")
+
+		lock, err := redis.TryLock("gf.lock", time.Second)
+		gtest.Assert(err, nil)
+		_, err = redis.TryLock("gf.lock", time.Second)
+		gtest.Assert(err, gredis.ErrLockNotObtained)
+
+		// Waiting for the lock with timeout.
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err = redis.LockCtx(ctx, "gf.lock", time.Second)
+		gtest.Assert(err, context.DeadlineExceeded)
+
+		gtest.Assert(lock.Refresh(), nil)
+		gtest.Assert(lock.Unlock(), nil)
+		gtest.Assert(lock.Unlock(), gredis.ErrLockNotHeld)
+
+		// The expired lock obtained by others is not released.
+		lock, err = redis.TryLock("gf.lock", 100*time.Millisecond)
+		gtest.Assert(err, nil)
+		time.Sleep(200 * time.Millisecond)
+		lock2, err := redis.Lock("gf.lock", time.Second)
+		gtest.Assert(err, nil)
+		gtest.Assert(lock.Refresh(), gredis.ErrLockNotHeld)
+		gtest.Assert(lock.Unlock(), gredis.ErrLockNotHeld)
+		gtest.Assert(lock2.Unlock(), nil)
+
+		// The watchdog keeps the lock alive.
+		lock, err = redis.TryLock("gf.lock", 150*time.Millisecond)
+		gtest.Assert(err, nil)
+		lock.KeepAlive()
+		time.Sleep(400 * time.Millisecond)
+		_, err = redis.TryLock("gf.lock", time.Second)
+		gtest.Assert(err, gredis.ErrLockNotObtained)
+		gtest.Assert(lock.Unlock(), nil)
+	})
+}
+
+func Test_ClientCache(t *testing.T) {
+	gtest.Case(t, func() {
+		redis := gredis.New(config)
+		defer redis.Close()
+		defer redis.Do("DEL", "gf.cc.key", "gf.cc.hash", "gf.other")
+
+		cache, err := redis.ClientCache(gredis.ClientCacheConfig{
+			Prefixes: []string{"gf.cc."},
+		})
+		gtest.Assert(err, nil)
+		defer cache.Close()
+
+		gtest.Assert(redis.Set("gf.cc.key", "v1"), nil)
+		v, err := cache.Get("gf.cc.key")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.String(), "v1")
+		gtest.Assert(cache.Size(), 1)
+		v, err = cache.Get("gf.cc.key")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.String(), "v1")
+
+		// The keys not matching the prefixes are not cached.
+		v, err = cache.Get("gf.other")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.IsNil(), true)
+		gtest.Assert(cache.Size(), 1)
+
+		// Invalidated by the server on modification.
+		gtest.Assert(redis.Set("gf.cc.key", "v2"), nil)
+		time.Sleep(100 * time.Millisecond)
+		gtest.Assert(cache.Size(), 0)
+		v, err = cache.Get("gf.cc.key")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.String(), "v2")
+
+		_, err = redis.Do("HSET", "gf.cc.hash", "f1", "1", "f2", "2")
+		gtest.Assert(err, nil)
+		v, err = cache.HGet("gf.cc.hash", "f1")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.Int(), 1)
+		v, err = cache.HGet("gf.cc.hash", "f2")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.Int(), 2)
+		gtest.Assert(cache.Size(), 2)
+		_, err = redis.Do("HSET", "gf.cc.hash", "f1", "10")
+		gtest.Assert(err, nil)
+		time.Sleep(100 * time.Millisecond)
+		gtest.Assert(cache.Size(), 1)
+		v, err = cache.HGet("gf.cc.hash", "f1")
+		gtest.Assert(err, nil)
+		gtest.Assert(v.Int(), 10)
+
+		cache.Invalidate("gf.cc.key")
+		gtest.Assert(cache.Size(), 1)
+		cache.Clear()
+		gtest.Assert(cache.Size(), 0)
+	})
+}
+
+func Test_OnCommand(t *testing.T) {
+	gtest.Case(t, func() {
+		c := config
+		c.MaxActive = 1
+		c.Wait = true
+		redis := gredis.New(c)
+		defer redis.Close()
+
+		var (
+			commands = make([]string, 0)
+			failures = 0
+		)
+		redis.OnCommand(func(command string, args []interface{}, cost time.Duration, err error) {
+			commands = append(commands, command)
+			if err != nil {
+				failures++
+			}
+		})
+		_, err := redis.Do("SET", "gf.hook", "a")
+		gtest.Assert(err, nil)
+		v, err := redis.GetString("gf.hook")
+		gtest.Assert(err, nil)
+		gtest.Assert(v, "a")
+		_, err = redis.Do("INCR", "gf.hook")
+		gtest.AssertNE(err, nil)
+		_, err = redis.Do("DEL", "gf.hook")
+		gtest.Assert(err, nil)
+		gtest.Assert(commands, []string{"SET", "GET", "INCR", "DEL"})
+		gtest.Assert(failures, 1)
+
+		// Waiting for the only connection in use.
+		conn := redis.Conn()
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			conn.Close()
+		}()
+		_, err = redis.Do("PING")
+		gtest.Assert(err, nil)
+		stats := redis.Stats()
+		gtest.Assert(stats.WaitCount, 1)
+		gtest.Assert(stats.WaitDuration >= 50*time.Millisecond, true)
+	})
+}
+
+type testBalancer struct {
+	picks []int
+	index int
+}
+
+func (b *testBalancer) Pick(addresses []string, command string, args []interface{}) int {
+	b.picks = append(b.picks, b.index)
+	return b.index
+}
+
+func Test_Replicas(t *testing.T) {
+	gtest.Case(t, func() {
+		c := config
+		c.Replicas = fmt.Sprintf("127.0.0.1:1, %s:%d", config.Host, config.Port)
+		redis := gredis.New(c)
+		defer redis.Close()
+		gtest.Assert(redis.Replicas(), []string{"127.0.0.1:1", fmt.Sprintf("%s:%d", config.Host, config.Port)})
+
+		balancer := &testBalancer{}
+		redis.SetReplicaBalancer(balancer)
+		gtest.Assert(redis.Set("gf.replica", "v"), nil)
+		gtest.Assert(len(balancer.picks), 0)
+
+		// Falling back to master if the replica is unavailable.
+		v, err := redis.GetString("gf.replica")
+		gtest.Assert(err, nil)
+		gtest.Assert(v, "v")
+		gtest.Assert(balancer.picks, []int{0})
+
+		balancer.index = 1
+		v, err = redis.GetString("gf.replica")
+		gtest.Assert(err, nil)
+		gtest.Assert(v, "v")
+		gtest.Assert(balancer.picks, []int{0, 1})
+
+		r, err := redis.DoVarMaster("GET", "gf.replica")
+		gtest.Assert(err, nil)
+		gtest.Assert(r.String(), "v")
+		gtest.Assert(len(balancer.picks), 2)
+
+		_, err = redis.Do("DEL", "gf.replica")
+		gtest.Assert(err, nil)
+	})
+}
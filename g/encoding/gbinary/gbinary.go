@@ -6,7 +6,7 @@
 
 // Package gbinary provides useful API for handling binary/bytes data.
 //
-// 注意gbinary模块统一使用LittleEndian进行编码。
+// 注意gbinary模块统一使用LittleEndian进行编码，EncodeStruct/DecodeStruct可通过属性标签指定字节序。
 package gbinary
 
 import (
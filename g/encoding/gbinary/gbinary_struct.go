@@ -0,0 +1,438 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gbinary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// 结构体属性编码选项的标签名称
+	gSTRUCT_TAG_NAME = "gbinary"
+)
+
+// 结构体属性的编码选项
+type structOption struct {
+	order  binary.ByteOrder // 字节序，默认为LittleEndian
+	varint bool             // 整型属性是否使用varint编码
+	size   int              // string/[]byte的固定字节长度，或者slice的固定元素数量
+}
+
+// 将结构体对象按照属性定义顺序编码为二进制数据，<value>可以为结构体对象或者其指针。
+// 属性编码选项使用gbinary标签设置，多个选项使用','符号分隔：
+// 1. "-": 忽略该属性；
+// 2. "be"/"le": 使用BigEndian/LittleEndian字节序(默认为LittleEndian)，嵌套结构体属性继承该字节序；
+// 3. "varint": 整型属性使用varint编码(有符号整型使用zigzag编码)；
+// 4. "size=N": string/[]byte属性使用N个字节的固定长度(不足补0，超出截断)，slice属性使用N个元素的固定数量；
+// 未指定size的string/[]byte/slice属性使用uvarint编码的长度前缀。
+// 注意int/uint类型属性按照64位整型编码，私有属性将被忽略。
+func EncodeStruct(value interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("invalid value type %T, should be struct or pointer of struct", value)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := encodeStruct(buffer, rv, structOption{order: binary.LittleEndian}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// 将EncodeStruct编码的二进制数据解码到结构体对象，<pointer>应当为结构体对象的指针。
+func DecodeStruct(b []byte, pointer interface{}) error {
+	rv := reflect.ValueOf(pointer)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("invalid pointer type %T, should be pointer of struct", pointer)
+	}
+	d := &structDecoder{b: b}
+	return d.decodeStruct(rv.Elem(), structOption{order: binary.LittleEndian})
+}
+
+// 解析属性的gbinary标签，<order>为继承的字节序。
+func parseStructOption(tag string, order binary.ByteOrder) (structOption, error) {
+	option := structOption{order: order}
+	for _, item := range strings.Split(tag, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "":
+		case item == "be":
+			option.order = binary.BigEndian
+		case item == "le":
+			option.order = binary.LittleEndian
+		case item == "varint":
+			option.varint = true
+		case strings.HasPrefix(item, "size="):
+			size, err := strconv.Atoi(item[5:])
+			if err != nil || size < 0 {
+				return option, fmt.Errorf(`invalid size option "%s"`, item)
+			}
+			option.size = size
+		default:
+			return option, fmt.Errorf(`unknown option "%s"`, item)
+		}
+	}
+	return option, nil
+}
+
+// 按照属性定义顺序编码结构体对象<rv>。
+func encodeStruct(buffer *bytes.Buffer, rv reflect.Value, option structOption) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get(gSTRUCT_TAG_NAME)
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		fieldOption, err := parseStructOption(tag, option.order)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %s", rt.Name(), field.Name, err.Error())
+		}
+		if err := encodeValue(buffer, rv.Field(i), fieldOption); err != nil {
+			return fmt.Errorf("field %s.%s: %s", rt.Name(), field.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// 编码单个值<rv>。
+func encodeValue(buffer *bytes.Buffer, rv reflect.Value, option structOption) error {
+	b := make([]byte, 8)
+	switch rv.Kind() {
+	case reflect.Bool:
+		buffer.Write(EncodeBool(rv.Bool()))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if option.varint {
+			buffer.Write(EncodeVarint(rv.Int()))
+			return nil
+		}
+		switch rv.Kind() {
+		case reflect.Int8:
+			buffer.WriteByte(byte(rv.Int()))
+		case reflect.Int16:
+			option.order.PutUint16(b, uint16(rv.Int()))
+			buffer.Write(b[:2])
+		case reflect.Int32:
+			option.order.PutUint32(b, uint32(rv.Int()))
+			buffer.Write(b[:4])
+		default:
+			option.order.PutUint64(b, uint64(rv.Int()))
+			buffer.Write(b)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if option.varint {
+			buffer.Write(EncodeUvarint(rv.Uint()))
+			return nil
+		}
+		switch rv.Kind() {
+		case reflect.Uint8:
+			buffer.WriteByte(byte(rv.Uint()))
+		case reflect.Uint16:
+			option.order.PutUint16(b, uint16(rv.Uint()))
+			buffer.Write(b[:2])
+		case reflect.Uint32:
+			option.order.PutUint32(b, uint32(rv.Uint()))
+			buffer.Write(b[:4])
+		default:
+			option.order.PutUint64(b, rv.Uint())
+			buffer.Write(b)
+		}
+
+	case reflect.Float32:
+		option.order.PutUint32(b, math.Float32bits(float32(rv.Float())))
+		buffer.Write(b[:4])
+
+	case reflect.Float64:
+		option.order.PutUint64(b, math.Float64bits(rv.Float()))
+		buffer.Write(b)
+
+	case reflect.String:
+		encodeBytes(buffer, []byte(rv.String()), option)
+
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			encodeBytes(buffer, rv.Bytes(), option)
+			return nil
+		}
+		length := rv.Len()
+		if option.size > 0 {
+			length = option.size
+		} else {
+			buffer.Write(EncodeUvarint(uint64(length)))
+		}
+		elemOption := structOption{order: option.order, varint: option.varint}
+		for i := 0; i < length; i++ {
+			elem := reflect.Zero(rv.Type().Elem())
+			if i < rv.Len() {
+				elem = rv.Index(i)
+			}
+			if err := encodeValue(buffer, elem, elemOption); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Array:
+		elemOption := structOption{order: option.order, varint: option.varint}
+		for i := 0; i < rv.Len(); i++ {
+			if err := encodeValue(buffer, rv.Index(i), elemOption); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		return encodeStruct(buffer, rv, option)
+
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return encodeValue(buffer, reflect.Zero(rv.Type().Elem()), option)
+		}
+		return encodeValue(buffer, rv.Elem(), option)
+
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type().String())
+	}
+	return nil
+}
+
+// 编码string/[]byte数据，固定长度或者uvarint长度前缀。
+func encodeBytes(buffer *bytes.Buffer, b []byte, option structOption) {
+	if option.size > 0 {
+		if len(b) >= option.size {
+			buffer.Write(b[:option.size])
+		} else {
+			buffer.Write(b)
+			buffer.Write(make([]byte, option.size-len(b)))
+		}
+		return
+	}
+	buffer.Write(EncodeUvarint(uint64(len(b))))
+	buffer.Write(b)
+}
+
+// 结构体二进制数据解码对象
+type structDecoder struct {
+	b   []byte // 二进制数据
+	pos int    // 当前读取位置
+}
+
+// 读取<n>个字节。
+func (d *structDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.b)-d.pos < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := d.b[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// 读取uvarint编码的无符号整数。
+func (d *structDecoder) readUvarint() (uint64, error) {
+	u, n := DecodeUvarint(d.b[d.pos:])
+	if n <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	d.pos += n
+	return u, nil
+}
+
+// 读取varint编码的有符号整数。
+func (d *structDecoder) readVarint() (int64, error) {
+	i, n := DecodeVarint(d.b[d.pos:])
+	if n <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	d.pos += n
+	return i, nil
+}
+
+// 读取string/[]byte数据，固定长度或者uvarint长度前缀。
+func (d *structDecoder) readBytes(option structOption) ([]byte, error) {
+	if option.size > 0 {
+		return d.read(option.size)
+	}
+	length, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if length > uint64(len(d.b)-d.pos) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return d.read(int(length))
+}
+
+// 按照属性定义顺序解码结构体对象<rv>。
+func (d *structDecoder) decodeStruct(rv reflect.Value, option structOption) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get(gSTRUCT_TAG_NAME)
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		fieldOption, err := parseStructOption(tag, option.order)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %s", rt.Name(), field.Name, err.Error())
+		}
+		if err := d.decodeValue(rv.Field(i), fieldOption); err != nil {
+			return fmt.Errorf("field %s.%s: %s", rt.Name(), field.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// 解码单个值到<rv>。
+func (d *structDecoder) decodeValue(rv reflect.Value, option structOption) error {
+	switch rv.Kind() {
+	case reflect.Bool:
+		b, err := d.read(1)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(DecodeToBool(b))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if option.varint {
+			i, err := d.readVarint()
+			if err != nil {
+				return err
+			}
+			rv.SetInt(i)
+			return nil
+		}
+		size := int(rv.Type().Size())
+		if rv.Kind() == reflect.Int {
+			size = 8
+		}
+		b, err := d.read(size)
+		if err != nil {
+			return err
+		}
+		switch size {
+		case 1:
+			rv.SetInt(int64(int8(b[0])))
+		case 2:
+			rv.SetInt(int64(int16(option.order.Uint16(b))))
+		case 4:
+			rv.SetInt(int64(int32(option.order.Uint32(b))))
+		default:
+			rv.SetInt(int64(option.order.Uint64(b)))
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if option.varint {
+			u, err := d.readUvarint()
+			if err != nil {
+				return err
+			}
+			rv.SetUint(u)
+			return nil
+		}
+		size := int(rv.Type().Size())
+		if rv.Kind() == reflect.Uint {
+			size = 8
+		}
+		b, err := d.read(size)
+		if err != nil {
+			return err
+		}
+		switch size {
+		case 1:
+			rv.SetUint(uint64(b[0]))
+		case 2:
+			rv.SetUint(uint64(option.order.Uint16(b)))
+		case 4:
+			rv.SetUint(uint64(option.order.Uint32(b)))
+		default:
+			rv.SetUint(option.order.Uint64(b))
+		}
+
+	case reflect.Float32:
+		b, err := d.read(4)
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(float64(math.Float32frombits(option.order.Uint32(b))))
+
+	case reflect.Float64:
+		b, err := d.read(8)
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(math.Float64frombits(option.order.Uint64(b)))
+
+	case reflect.String:
+		b, err := d.readBytes(option)
+		if err != nil {
+			return err
+		}
+		if option.size > 0 {
+			b = bytes.TrimRight(b, "\x00")
+		}
+		rv.SetString(string(b))
+
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := d.readBytes(option)
+			if err != nil {
+				return err
+			}
+			rv.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		length := uint64(option.size)
+		if option.size == 0 {
+			var err error
+			if length, err = d.readUvarint(); err != nil {
+				return err
+			}
+			// Each element takes one byte at least, which avoids huge allocation for invalid data.
+			if length > uint64(len(d.b)-d.pos) {
+				return io.ErrUnexpectedEOF
+			}
+		}
+		slice := reflect.MakeSlice(rv.Type(), int(length), int(length))
+		elemOption := structOption{order: option.order, varint: option.varint}
+		for i := 0; i < int(length); i++ {
+			if err := d.decodeValue(slice.Index(i), elemOption); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+
+	case reflect.Array:
+		elemOption := structOption{order: option.order, varint: option.varint}
+		for i := 0; i < rv.Len(); i++ {
+			if err := d.decodeValue(rv.Index(i), elemOption); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		return d.decodeStruct(rv, option)
+
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return d.decodeValue(rv.Elem(), option)
+
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type().String())
+	}
+	return nil
+}
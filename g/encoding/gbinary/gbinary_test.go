@@ -129,3 +129,104 @@ func TestBits(t *testing.T) {
 	}
 
 }
+
+func TestVarint(t *testing.T) {
+	gtest.Case(t, func() {
+		for _, i := range []int64{0, 1, -1, 63, -64, 64, 300, -300, math.MaxInt64, math.MinInt64} {
+			b := gbinary.EncodeVarint(i)
+			v, n := gbinary.DecodeVarint(b)
+			gtest.Assert(v, i)
+			gtest.Assert(n, len(b))
+		}
+		gtest.Assert(gbinary.EncodeVarint(-1), []byte{1})
+		gtest.Assert(gbinary.EncodeUvarint(300), []byte{0xac, 0x02})
+		u, n := gbinary.DecodeUvarint([]byte{0xac, 0x02})
+		gtest.Assert(u, 300)
+		gtest.Assert(n, 2)
+		_, n = gbinary.DecodeUvarint([]byte{0xac})
+		gtest.Assert(n, 0)
+	})
+	gtest.Case(t, func() {
+		gtest.Assert(gbinary.EncodeZigZag32(0), 0)
+		gtest.Assert(gbinary.EncodeZigZag32(-1), 1)
+		gtest.Assert(gbinary.EncodeZigZag32(1), 2)
+		gtest.Assert(gbinary.EncodeZigZag32(-2), 3)
+		gtest.Assert(gbinary.EncodeZigZag64(math.MinInt64), uint64(math.MaxUint64))
+		for _, i := range []int32{0, -1, 1, math.MaxInt32, math.MinInt32} {
+			gtest.Assert(gbinary.DecodeZigZag32(gbinary.EncodeZigZag32(i)), i)
+		}
+		for _, i := range []int64{0, -1, 1, math.MaxInt64, math.MinInt64} {
+			gtest.Assert(gbinary.DecodeZigZag64(gbinary.EncodeZigZag64(i)), i)
+		}
+	})
+}
+
+type packetHeader struct {
+	Magic   uint16 `gbinary:"be"`
+	Version uint8
+	Length  uint32 `gbinary:"be"`
+}
+
+type packet struct {
+	Header  packetHeader
+	Id      int64 `gbinary:"varint"`
+	Name    string
+	Code    string `gbinary:"size=4"`
+	Flags   [2]bool
+	Values  []int16
+	Ratio   float32
+	Data    []byte
+	Extra   *packetHeader
+	Ignored string `gbinary:"-"`
+	private int
+}
+
+func TestEncodeDecodeStruct(t *testing.T) {
+	gtest.Case(t, func() {
+		p := packet{
+			Header:  packetHeader{Magic: 0xCAFE, Version: 1, Length: 258},
+			Id:      -2,
+			Name:    "john",
+			Code:    "ab",
+			Flags:   [2]bool{true, false},
+			Values:  []int16{1, -1},
+			Ratio:   0.5,
+			Data:    []byte{9, 8},
+			Extra:   &packetHeader{Magic: 1},
+			Ignored: "ignored",
+			private: 1,
+		}
+		b, err := gbinary.EncodeStruct(&p)
+		gtest.Assert(err, nil)
+		gtest.Assert(b[:7], []byte{0xCA, 0xFE, 1, 0, 0, 1, 2})
+		gtest.Assert(b[7:], gbinary.Encode(
+			[]byte{3},
+			[]byte{4}, "john",
+			"ab", []byte{0, 0},
+			true, false,
+			[]byte{2}, int16(1), int16(-1),
+			float32(0.5),
+			[]byte{2, 9, 8},
+			[]byte{0, 1}, uint8(0), uint32(0),
+		))
+
+		var p2 packet
+		gtest.Assert(gbinary.DecodeStruct(b, &p2), nil)
+		gtest.Assert(*p2.Extra, *p.Extra)
+		p.Ignored = ""
+		p.private = 0
+		p.Extra, p2.Extra = nil, nil
+		gtest.Assert(p2, p)
+
+		gtest.AssertNE(gbinary.DecodeStruct(b[:len(b)-1], &p2), nil)
+		gtest.AssertNE(gbinary.DecodeStruct(b, p2), nil)
+		_, err = gbinary.EncodeStruct(1)
+		gtest.AssertNE(err, nil)
+		_, err = gbinary.EncodeStruct(struct{ M map[string]int }{})
+		gtest.AssertNE(err, nil)
+		_, err = gbinary.EncodeStruct(struct {
+			S string `gbinary:"size=x"`
+		}{})
+		gtest.AssertNE(err, nil)
+	})
+}
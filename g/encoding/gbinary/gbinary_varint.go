@@ -0,0 +1,55 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gbinary
+
+import (
+	"encoding/binary"
+)
+
+// 将有符号整数按照varint(zigzag)方式编码，数值绝对值越小编码后的字节数越少，最多10个字节。
+func EncodeVarint(i int64) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	return b[:binary.PutVarint(b, i)]
+}
+
+// 将无符号整数按照uvarint方式编码，数值越小编码后的字节数越少，最多10个字节。
+func EncodeUvarint(i uint64) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	return b[:binary.PutUvarint(b, i)]
+}
+
+// 解码varint(zigzag)编码的有符号整数，返回解码的数值及读取的字节数。
+// 当<b>长度不足时返回的字节数为0，当数值溢出64位时返回的字节数为负数。
+func DecodeVarint(b []byte) (int64, int) {
+	return binary.Varint(b)
+}
+
+// 解码uvarint编码的无符号整数，返回解码的数值及读取的字节数。
+// 当<b>长度不足时返回的字节数为0，当数值溢出64位时返回的字节数为负数。
+func DecodeUvarint(b []byte) (uint64, int) {
+	return binary.Uvarint(b)
+}
+
+// 对32位有符号整数进行zigzag编码，使得绝对值较小的负数也能得到较小的无符号整数。
+func EncodeZigZag32(i int32) uint32 {
+	return uint32((i << 1) ^ (i >> 31))
+}
+
+// 对64位有符号整数进行zigzag编码。
+func EncodeZigZag64(i int64) uint64 {
+	return uint64((i << 1) ^ (i >> 63))
+}
+
+// 解码zigzag编码的32位整数。
+func DecodeZigZag32(u uint32) int32 {
+	return int32(u>>1) ^ -int32(u&1)
+}
+
+// 解码zigzag编码的64位整数。
+func DecodeZigZag64(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gcharset
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// detectCandidate is a multi-byte charset candidate for detection.
+type detectCandidate struct {
+	charset  string        // Charset name.
+	frequent map[rune]bool // Frequently used characters of the language of the charset.
+	kana     bool          // Whether the Japanese kana characters are frequently used.
+}
+
+const (
+	// Max length of content for detection, longer content is truncated.
+	gDETECT_MAX_LENGTH = 64 * 1024
+	// The ratio of frequently used characters in normal text,
+	// which is considered full confidence for the language.
+	gDETECT_FREQUENT_RATIO = 0.3
+)
+
+var (
+	// Multi-byte charset candidates for detection, in priority order.
+	detectCandidates = []*detectCandidate{
+		{
+			charset: "GBK",
+			frequent: runeSet(
+				"的一是了不在人有我他这个们中来上大为和国地到以说时要就出会可也你对生能而子那得于着下自之年过发后作里用道行所然家种事成方多经么去法学如都同现当没动面起看定天分还进好小部其些主样理心她本前开但因只从想实",
+			),
+		},
+		{
+			charset: "Big5",
+			frequent: runeSet(
+				"的一是了不在人有我他這個們中來上大為和國地到以說時要就出會可也你對生能而子那得於著下自之年過發後作裡用道行所然家種事成方多經麼去法學如都同現當沒動面起看定天分還進好小部其些主樣理心她本前開但因只從想實",
+			),
+		},
+		{
+			charset:  "Shift_JIS",
+			frequent: runeSet("日本人年大中出一国会上生事者自分行時見言"),
+			kana:     true,
+		},
+		{
+			charset:  "EUC-JP",
+			frequent: runeSet("日本人年大中出一国会上生事者自分行時見言"),
+			kana:     true,
+		},
+		{
+			charset: "EUC-KR",
+			frequent: runeSet(
+				"이다는의에하가고을지를기서한로도으사들있어리나대시정그수아자일것보게면해인제주내라요수부만우전적상거되니스구없않소국연",
+			),
+		},
+	}
+)
+
+// Detect detects the charset of content <b>, and returns the charset name and the confidence in [0, 100].
+// It recognizes UTF-8/UTF-16 by BOM and UTF-8 by validation,
+// and the multi-byte charsets GBK/Big5/Shift_JIS/EUC-JP/EUC-KR by the frequently used characters.
+// It returns empty charset and confidence 0 if the charset cannot be detected.
+// Note that the detection is heuristic, the longer the content is, the more accurate the result is.
+func Detect(b []byte) (charset string, confidence int) {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return "UTF-8", 100
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		return "UTF-16BE", 100
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+		return "UTF-16LE", 100
+	}
+	if len(b) > gDETECT_MAX_LENGTH {
+		b = b[:gDETECT_MAX_LENGTH]
+		// Dropping the truncated incomplete character at the end.
+		for i := 0; i < utf8.UTFMax && len(b) > 0 && b[len(b)-1] >= 0x80; i++ {
+			b = b[:len(b)-1]
+		}
+	}
+	// ASCII content is also valid UTF-8 content.
+	if utf8.Valid(b) {
+		return "UTF-8", 100
+	}
+	for _, c := range detectCandidates {
+		if score := c.score(b); score > confidence {
+			charset, confidence = c.charset, score
+		}
+	}
+	return
+}
+
+// score returns the confidence in [0, 100] of content <b> being encoded in the candidate charset.
+func (c *detectCandidate) score(b []byte) int {
+	e := getEncoding(c.charset)
+	if e == nil {
+		return 0
+	}
+	s, _, err := transform.Bytes(e.NewDecoder(), b)
+	if err != nil {
+		return 0
+	}
+	total, invalid, frequent := 0, 0, 0
+	for _, r := range string(s) {
+		if r < utf8.RuneSelf {
+			continue
+		}
+		total++
+		switch {
+		case r == utf8.RuneError:
+			invalid++
+		case c.frequent[r]:
+			frequent++
+		case c.kana && r >= 0x3040 && r <= 0x30FF:
+			frequent++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	valid := float64(total-invalid) / float64(total)
+	ratio := float64(frequent) / float64(total) / gDETECT_FREQUENT_RATIO
+	if ratio > 1 {
+		ratio = 1
+	}
+	return int(100 * valid * valid * (0.5 + 0.5*ratio))
+}
+
+// runeSet returns a set of the characters of <s>.
+func runeSet(s string) map[rune]bool {
+	m := make(map[rune]bool)
+	for _, r := range s {
+		m[r] = true
+	}
+	return m
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gcharset
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/text/transform"
+)
+
+// NewReader returns a reader that converts the content of <reader> from charset <from> to charset <to>
+// in streaming, which does not load the whole content into memory.
+// It returns <reader> directly if <from> and <to> are the same charset,
+// and returns error if any of the charsets is not supported.
+func NewReader(reader io.Reader, from string, to string) (io.Reader, error) {
+	if from == to {
+		return reader, nil
+	}
+	transformers := make([]transform.Transformer, 0, 2)
+	// Converting <from> to UTF-8.
+	if from != "UTF-8" {
+		if e := getEncoding(from); e != nil {
+			transformers = append(transformers, e.NewDecoder())
+		} else {
+			return nil, fmt.Errorf("unsupport srcCharset: %s", from)
+		}
+	}
+	// Converting UTF-8 to <to>.
+	if to != "UTF-8" {
+		if e := getEncoding(to); e != nil {
+			transformers = append(transformers, e.NewEncoder())
+		} else {
+			return nil, fmt.Errorf("unsupport dstCharset: %s", to)
+		}
+	}
+	return transform.NewReader(reader, transform.Chain(transformers...)), nil
+}
+
+// NewUTF8Reader returns a reader that converts the content of <reader> from charset <from> to UTF-8.
+func NewUTF8Reader(reader io.Reader, from string) (io.Reader, error) {
+	return NewReader(reader, from, "UTF-8")
+}
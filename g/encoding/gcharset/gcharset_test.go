@@ -7,8 +7,11 @@
 package gcharset_test
 
 import (
+	"bytes"
 	"github.com/gogf/gf/g/encoding/gcharset"
 	"github.com/gogf/gf/g/test/gtest"
+	"io/ioutil"
+	"strings"
 	"testing"
 )
 
@@ -162,3 +165,69 @@ func TestSupported(t *testing.T) {
 		gtest.Assert(gcharset.Supported("UTF-80"), false)
 	})
 }
+
+func TestNewReader(t *testing.T) {
+	gtest.Case(t, func() {
+		for _, data := range testData {
+			reader, err := gcharset.NewReader(bytes.NewReader([]byte(data.other)), data.otherEncoding, "UTF-8")
+			gtest.Assert(err, nil)
+			content, err := ioutil.ReadAll(reader)
+			gtest.Assert(err, nil)
+			gtest.Assert(string(content), data.utf8)
+		}
+	})
+	gtest.Case(t, func() {
+		reader, err := gcharset.NewReader(strings.NewReader("常用字"), "UTF-8", "GBK")
+		gtest.Assert(err, nil)
+		reader, err = gcharset.NewReader(reader, "GBK", "Big5")
+		gtest.Assert(err, nil)
+		reader, err = gcharset.NewUTF8Reader(reader, "Big5")
+		gtest.Assert(err, nil)
+		content, err := ioutil.ReadAll(reader)
+		gtest.Assert(err, nil)
+		gtest.Assert(string(content), "常用字")
+
+		_, err = gcharset.NewReader(strings.NewReader(""), "UTF-8", "none")
+		gtest.AssertNE(err, nil)
+		_, err = gcharset.NewReader(strings.NewReader(""), "none", "UTF-8")
+		gtest.AssertNE(err, nil)
+	})
+}
+
+func TestDetect(t *testing.T) {
+	gtest.Case(t, func() {
+		charset, confidence := gcharset.Detect([]byte("hello world"))
+		gtest.Assert(charset, "UTF-8")
+		gtest.Assert(confidence, 100)
+
+		charset, confidence = gcharset.Detect([]byte("\xef\xbb\xbfhello"))
+		gtest.Assert(charset, "UTF-8")
+		gtest.Assert(confidence, 100)
+
+		charset, _ = gcharset.Detect([]byte("\xff\xfeh\x00i\x00"))
+		gtest.Assert(charset, "UTF-16LE")
+
+		charset, _ = gcharset.Detect([]byte("中文内容的字符集检测"))
+		gtest.Assert(charset, "UTF-8")
+
+		charset, confidence = gcharset.Detect(nil)
+		gtest.Assert(charset, "UTF-8")
+		gtest.Assert(confidence, 100)
+	})
+	gtest.Case(t, func() {
+		texts := map[string]string{
+			"GBK":       "我们的国家是一个有着悠久历史的大国，这是一个大家都知道的事实。",
+			"Big5":      "我們的國家是一個有著悠久歷史的大國，這是一個大家都知道的事實。",
+			"Shift_JIS": "これは日本語の文章です。私たちは毎日新しいことを学んでいます。",
+			"EUC-JP":    "これは日本語の文章です。私たちは毎日新しいことを学んでいます。",
+			"EUC-KR":    "이것은 한국어 문장입니다. 우리는 매일 새로운 것을 배우고 있습니다.",
+		}
+		for charset, text := range texts {
+			content, err := gcharset.UTF8To(charset, text)
+			gtest.Assert(err, nil)
+			detected, confidence := gcharset.Detect([]byte(content))
+			gtest.Assert(detected, charset)
+			gtest.AssertGT(confidence, 50)
+		}
+	})
+}
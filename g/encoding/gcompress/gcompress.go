@@ -4,7 +4,8 @@
 // If a copy of the MIT was not distributed with this file,
 // You can obtain one at https://github.com/gogf/gf.
 
-// Package gcompress provides kinds of compression algorithms for binary/bytes data.
+// Package gcompress provides kinds of compression algorithms for binary/bytes data,
+// files and directories.
 package gcompress
 
 import (
@@ -0,0 +1,66 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcompress
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// NewGzipWriter creates a streaming gzip writer writing compressed data to <writer>.
+// The optional parameter <level> specifies the compression level, which is gzip.DefaultCompression in default.
+// Note that the caller should close the returned writer to flush the remaining data.
+func NewGzipWriter(writer io.Writer, level ...int) (*gzip.Writer, error) {
+	if len(level) > 0 {
+		return gzip.NewWriterLevel(writer, level[0])
+	}
+	return gzip.NewWriter(writer), nil
+}
+
+// NewGzipReader creates a streaming gzip reader decompressing data from <reader>.
+func NewGzipReader(reader io.Reader) (*gzip.Reader, error) {
+	return gzip.NewReader(reader)
+}
+
+// GzipFile compresses file <src> to <dst> with gzip algorithm, in streaming way.
+func GzipFile(src, dst string, level ...int) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+	return writeFileAtomic(dst, func(dstWriter io.Writer) error {
+		writer, err := NewGzipWriter(dstWriter, level...)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(writer, srcFile); err != nil {
+			writer.Close()
+			return err
+		}
+		return writer.Close()
+	})
+}
+
+// UnGzipFile decompresses gzip file <src> to <dst>, in streaming way.
+func UnGzipFile(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+	reader, err := NewGzipReader(srcFile)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return writeFileAtomic(dst, func(writer io.Writer) error {
+		_, err := io.Copy(writer, reader)
+		return err
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcompress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gf/g/os/gfile"
+)
+
+const (
+	// Namespace of the temp files for writing archives.
+	gTEMP_NAMESPACE = "gcompress"
+)
+
+// walkPath walks the file or directory <path> in lexical order, and calls <f> for each
+// file and sub directory with its slash-separated name relative to the parent of <path>.
+// The files and directories matching any glob pattern of <excludes> are skipped,
+// the pattern is matched against both the relative name and the base name.
+func walkPath(path string, excludes []string, f func(file string, name string, info os.FileInfo) error) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	parent := filepath.Dir(path)
+	return filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(parent, file)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if isExcluded(name, excludes) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			// Symbolic links, devices, etc.
+			return nil
+		}
+		return f(file, name, info)
+	})
+}
+
+// isExcluded checks whether slash-separated <name> matches any glob pattern of <excludes>.
+func isExcluded(name string, excludes []string) bool {
+	base := name[strings.LastIndexByte(name, '/')+1:]
+	for _, pattern := range excludes {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// extractPath returns the local path for archive entry <name> under directory <dest>.
+// It returns error if the entry points outside of <dest>, eg: "../../etc/passwd".
+func extractPath(dest string, name string) (string, error) {
+	path := filepath.Join(dest, filepath.FromSlash(name))
+	if path != filepath.Clean(dest) && !strings.HasPrefix(path, filepath.Clean(dest)+string(filepath.Separator)) {
+		return "", fmt.Errorf(`invalid file name in archive: %s`, name)
+	}
+	return path, nil
+}
+
+// copyFileTo copies the content of file <path> to <writer>.
+func copyFileTo(writer io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(writer, file)
+	return err
+}
+
+// writeFileAtomic calls <write> to write a temp file, which is moved to <dest> if it succeeds,
+// so <dest> is never partially written. The temp files left by crashed processes are reclaimed
+// by gfile.TempManager.
+func writeFileAtomic(dest string, write func(writer io.Writer) error) error {
+	temp := gfile.Temp(gTEMP_NAMESPACE)
+	file, err := temp.CreateFile(filepath.Base(dest) + ".*")
+	if err != nil {
+		return err
+	}
+	err = write(file)
+	if e := file.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		temp.Remove(file.Name())
+		return err
+	}
+	return temp.Commit(file.Name(), dest)
+}
+
+// writeFileFrom writes the content from <reader> to file <path> with permission <perm>.
+func writeFileFrom(path string, perm os.FileMode, reader io.Reader) error {
+	if perm == 0 {
+		perm = 0644
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm.Perm())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, reader)
+	return err
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcompress
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// TarPath packs file or directory <path> to tar file <dest>.
+// The files and directories matching any glob pattern of <excludes> are skipped.
+func TarPath(path, dest string, excludes ...string) error {
+	return writeFileAtomic(dest, func(writer io.Writer) error {
+		return TarPathWriter(path, writer, excludes...)
+	})
+}
+
+// TarPathWriter packs file or directory <path> to <writer> in tar format, in streaming way.
+// The files and directories matching any glob pattern of <excludes> are skipped,
+// the pattern is matched against both the base name and the relative name,
+// eg: "*.log", "dir/tmp".
+func TarPathWriter(path string, writer io.Writer, excludes ...string) error {
+	tarWriter := tar.NewWriter(writer)
+	err := walkPath(path, excludes, func(file string, name string, info os.FileInfo) error {
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tarWriter.WriteHeader(header); err != nil || info.IsDir() {
+			return err
+		}
+		return copyFileTo(tarWriter, file)
+	})
+	if err != nil {
+		tarWriter.Close()
+		return err
+	}
+	return tarWriter.Close()
+}
+
+// TarGzPath packs file or directory <path> to tar.gz file <dest>.
+// The files and directories matching any glob pattern of <excludes> are skipped.
+func TarGzPath(path, dest string, excludes ...string) error {
+	return writeFileAtomic(dest, func(writer io.Writer) error {
+		return TarGzPathWriter(path, writer, excludes...)
+	})
+}
+
+// TarGzPathWriter packs file or directory <path> to <writer> in tar.gz format, in streaming way.
+func TarGzPathWriter(path string, writer io.Writer, excludes ...string) error {
+	gzipWriter, _ := NewGzipWriter(writer)
+	if err := TarPathWriter(path, gzipWriter, excludes...); err != nil {
+		gzipWriter.Close()
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// UnTar unpacks tar file <archive> to directory <dest>.
+func UnTar(archive, dest string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return UnTarReader(file, dest)
+}
+
+// UnTarReader unpacks tar data from <reader> to directory <dest>, in streaming way.
+func UnTarReader(reader io.Reader, dest string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path, err := extractPath(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := writeFileFrom(path, os.FileMode(header.Mode).Perm(), tarReader); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf(`unsupported file type "%c" in archive: %s`, header.Typeflag, header.Name)
+		}
+	}
+}
+
+// UnTarGz unpacks tar.gz file <archive> to directory <dest>.
+func UnTarGz(archive, dest string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return UnTarGzReader(file, dest)
+}
+
+// UnTarGzReader unpacks tar.gz data from <reader> to directory <dest>, in streaming way.
+func UnTarGzReader(reader io.Reader, dest string) error {
+	gzipReader, err := NewGzipReader(reader)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+	return UnTarReader(gzipReader, dest)
+}
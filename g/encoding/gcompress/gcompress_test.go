@@ -7,9 +7,16 @@
 package gcompress_test
 
 import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/gogf/gf/g/encoding/gcompress"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/os/gtime"
 	"github.com/gogf/gf/g/test/gtest"
 )
 
@@ -58,3 +65,133 @@ func TestGzip(t *testing.T) {
 	data, _ = gcompress.UnGzip(gzip[1:])
 	gtest.Assert(data, nil)
 }
+
+func TestGzipWriter(t *testing.T) {
+	gtest.Case(t, func() {
+		buffer := bytes.NewBuffer(nil)
+		writer, err := gcompress.NewGzipWriter(buffer)
+		gtest.Assert(err, nil)
+		writer.Write([]byte("Hello "))
+		writer.Write([]byte("World!!"))
+		gtest.Assert(writer.Close(), nil)
+
+		data, err := gcompress.UnGzip(buffer.Bytes())
+		gtest.Assert(err, nil)
+		gtest.Assert(string(data), "Hello World!!")
+
+		_, err = gcompress.NewGzipWriter(buffer, 100)
+		gtest.AssertNE(err, nil)
+	})
+}
+
+// createTestDir creates a directory for packing tests and returns its path.
+func createTestDir() string {
+	dir := gfile.TempDir() + gfile.Separator + fmt.Sprintf("gcompress_%d", gtime.Nanosecond())
+	gfile.PutContents(dir+"/src/a.txt", "a")
+	gfile.PutContents(dir+"/src/b.log", "b")
+	gfile.PutContents(dir+"/src/sub/c.txt", "c")
+	gfile.PutContents(dir+"/src/tmp/d.txt", "d")
+	return dir
+}
+
+func TestZipPath(t *testing.T) {
+	gtest.Case(t, func() {
+		dir := createTestDir()
+		defer gfile.Remove(dir)
+
+		buffer := bytes.NewBuffer(nil)
+		gtest.Assert(gcompress.ZipPathWriter(dir+"/src", buffer, "*.log", "src/tmp"), nil)
+		reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		gtest.Assert(err, nil)
+		names := make([]string, 0)
+		for _, file := range reader.File {
+			names = append(names, file.Name)
+		}
+		gtest.Assert(names, []string{"src/", "src/a.txt", "src/sub/", "src/sub/c.txt"})
+
+		gtest.Assert(gcompress.ZipPath(dir+"/src", dir+"/src.zip"), nil)
+		gtest.Assert(gcompress.UnZipFile(dir+"/src.zip", dir+"/zip"), nil)
+		gtest.Assert(gfile.GetContents(dir+"/zip/src/a.txt"), "a")
+		gtest.Assert(gfile.GetContents(dir+"/zip/src/b.log"), "b")
+		gtest.Assert(gfile.GetContents(dir+"/zip/src/sub/c.txt"), "c")
+
+		gtest.Assert(gcompress.UnZipContent(buffer.Bytes(), dir+"/zip2"), nil)
+		gtest.Assert(gfile.GetContents(dir+"/zip2/src/a.txt"), "a")
+		gtest.Assert(gfile.Exists(dir+"/zip2/src/b.log"), false)
+		gtest.Assert(gfile.Exists(dir+"/zip2/src/tmp"), false)
+	})
+	// Invalid file name.
+	gtest.Case(t, func() {
+		buffer := bytes.NewBuffer(nil)
+		writer := zip.NewWriter(buffer)
+		w, _ := writer.Create("../evil.txt")
+		w.Write([]byte("evil"))
+		writer.Close()
+		gtest.AssertNE(gcompress.UnZipContent(buffer.Bytes(), gfile.TempDir()+"/gcompress_evil"), nil)
+	})
+}
+
+func TestZipEntriesWriter(t *testing.T) {
+	gtest.Case(t, func() {
+		dir := createTestDir()
+		defer gfile.Remove(dir)
+
+		buffer := bytes.NewBuffer(nil)
+		gtest.Assert(gcompress.ZipEntriesWriter([]gcompress.ZipEntry{
+			{Path: dir + "/src/a.txt"},
+			{Name: "docs/c.txt", Path: dir + "/src/sub/c.txt", Store: true},
+			{Name: "../../readme.txt", Reader: strings.NewReader("readme")},
+		}, buffer), nil)
+		reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		gtest.Assert(err, nil)
+		names := make([]string, 0)
+		contents := make([]string, 0)
+		for _, file := range reader.File {
+			names = append(names, file.Name)
+			r, err := file.Open()
+			gtest.Assert(err, nil)
+			b, _ := ioutil.ReadAll(r)
+			r.Close()
+			contents = append(contents, string(b))
+		}
+		gtest.Assert(names, []string{"a.txt", "docs/c.txt", "readme.txt"})
+		gtest.Assert(contents, []string{"a", "c", "readme"})
+		gtest.Assert(reader.File[1].Method, zip.Store)
+	})
+	// Duplicated name or invalid path.
+	gtest.Case(t, func() {
+		dir := createTestDir()
+		defer gfile.Remove(dir)
+
+		buffer := bytes.NewBuffer(nil)
+		gtest.AssertNE(gcompress.ZipEntriesWriter([]gcompress.ZipEntry{
+			{Path: dir + "/src/a.txt"},
+			{Name: "a.txt", Reader: strings.NewReader("a")},
+		}, buffer), nil)
+		gtest.AssertNE(gcompress.ZipEntriesWriter([]gcompress.ZipEntry{{Path: dir + "/src/sub"}}, buffer), nil)
+		gtest.AssertNE(gcompress.ZipEntriesWriter([]gcompress.ZipEntry{{Path: dir + "/none.txt"}}, buffer), nil)
+	})
+}
+
+func TestTarPath(t *testing.T) {
+	gtest.Case(t, func() {
+		dir := createTestDir()
+		defer gfile.Remove(dir)
+
+		gtest.Assert(gcompress.TarPath(dir+"/src", dir+"/src.tar", "*.log"), nil)
+		gtest.Assert(gcompress.UnTar(dir+"/src.tar", dir+"/tar"), nil)
+		gtest.Assert(gfile.GetContents(dir+"/tar/src/a.txt"), "a")
+		gtest.Assert(gfile.GetContents(dir+"/tar/src/sub/c.txt"), "c")
+		gtest.Assert(gfile.GetContents(dir+"/tar/src/tmp/d.txt"), "d")
+		gtest.Assert(gfile.Exists(dir+"/tar/src/b.log"), false)
+
+		gtest.Assert(gcompress.TarGzPath(dir+"/src/sub", dir+"/sub.tar.gz"), nil)
+		gtest.Assert(gcompress.UnTarGz(dir+"/sub.tar.gz", dir+"/targz"), nil)
+		gtest.Assert(gfile.GetContents(dir+"/targz/sub/c.txt"), "c")
+
+		buffer := bytes.NewBuffer(nil)
+		gtest.Assert(gcompress.TarGzPathWriter(dir+"/src/a.txt", buffer), nil)
+		gtest.Assert(gcompress.UnTarGzReader(buffer, dir+"/file"), nil)
+		gtest.Assert(gfile.GetContents(dir+"/file/a.txt"), "a")
+	})
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcompress
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ZipPath compresses file or directory <path> to zip file <dest>.
+// The files and directories matching any glob pattern of <excludes> are skipped.
+func ZipPath(path, dest string, excludes ...string) error {
+	return writeFileAtomic(dest, func(writer io.Writer) error {
+		return ZipPathWriter(path, writer, excludes...)
+	})
+}
+
+// ZipPathWriter compresses file or directory <path> to <writer> in zip format, in streaming way.
+// The files and directories matching any glob pattern of <excludes> are skipped,
+// the pattern is matched against both the base name and the relative name,
+// eg: "*.log", "dir/tmp".
+func ZipPathWriter(path string, writer io.Writer, excludes ...string) error {
+	zipWriter := zip.NewWriter(writer)
+	err := walkPath(path, excludes, func(file string, name string, info os.FileInfo) error {
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+		w, err := zipWriter.CreateHeader(header)
+		if err != nil || info.IsDir() {
+			return err
+		}
+		return copyFileTo(w, file)
+	})
+	if err != nil {
+		zipWriter.Close()
+		return err
+	}
+	return zipWriter.Close()
+}
+
+// UnZipFile decompresses zip file <archive> to directory <dest>.
+func UnZipFile(archive, dest string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return unZipFiles(reader.File, dest)
+}
+
+// UnZipContent decompresses zip content <data> to directory <dest>.
+func UnZipContent(data []byte, dest string) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	return unZipFiles(reader.File, dest)
+}
+
+// unZipFiles extracts the zip <files> to directory <dest>.
+func unZipFiles(files []*zip.File, dest string) error {
+	for _, file := range files {
+		path, err := extractPath(dest, file.Name)
+		if err != nil {
+			return err
+		}
+		info := file.FileInfo()
+		if info.IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFileFrom(path, info.Mode(), reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ZipEntry is a file entry of the zip archive built by ZipEntriesWriter.
+type ZipEntry struct {
+	Name    string    // Slash-separated name in the archive, which is the base name of Path in default.
+	Path    string    // Local file path of the content, which is used if Reader is nil.
+	Reader  io.Reader // Reader of the content, which is closed after reading if it's an io.Closer.
+	ModTime time.Time // Modification time, which is the modification time of Path or current time in default.
+	Store   bool      // Whether storing the content without compression, eg: for images or videos.
+}
+
+// ZipEntriesWriter builds a zip archive from file <entries> and writes it to <writer> in streaming way,
+// without staging the archive on disk, eg: streaming "download all" archive to http response.
+// The entry names are cleaned to be relative, and it returns error if any of them is duplicated.
+func ZipEntriesWriter(entries []ZipEntry, writer io.Writer) error {
+	zipWriter := zip.NewWriter(writer)
+	names := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		// The archive is not closed on error, so the buffered content is not flushed to <writer>.
+		if err := writeZipEntry(zipWriter, entry, names); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+// writeZipEntry writes <entry> to <zipWriter>, and checks its name duplication with <names>.
+func writeZipEntry(zipWriter *zip.Writer, entry ZipEntry, names map[string]struct{}) error {
+	reader := entry.Reader
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	name := entry.Name
+	if name == "" {
+		name = filepath.Base(entry.Path)
+	}
+	name = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+	if name == "" {
+		return errors.New("empty zip entry name")
+	}
+	if _, ok := names[name]; ok {
+		return fmt.Errorf("duplicated zip entry name: %s", name)
+	}
+	names[name] = struct{}{}
+	header := &zip.FileHeader{
+		Name:   name,
+		Method: zip.Deflate,
+	}
+	if entry.Store {
+		header.Method = zip.Store
+	}
+	modTime := entry.ModTime
+	if reader == nil {
+		file, err := os.Open(entry.Path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fmt.Errorf("zip entry path is a directory: %s", entry.Path)
+		}
+		if modTime.IsZero() {
+			modTime = info.ModTime()
+		}
+		reader = file
+	}
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+	header.Modified = modTime
+	w, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, reader)
+	return err
+}
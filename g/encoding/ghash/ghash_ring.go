@@ -0,0 +1,177 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghash
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const (
+	// Default count of virtual nodes for each weight unit of node.
+	gRING_DEFAULT_REPLICAS = 160
+)
+
+// Ring is a consistent hashing ring, which maps keys to nodes stably when nodes are added or removed:
+// only the keys of the added or removed node are remapped.
+// Each node is placed on the ring as <replicas>*<weight> virtual nodes for balancing.
+// It is concurrent-safe.
+type Ring struct {
+	mu       sync.RWMutex
+	replicas int                 // Count of virtual nodes for each weight unit.
+	hash     func([]byte) uint32 // Hash function.
+	weights  map[string]int      // Node to weight.
+	hashes   []uint32            // Sorted hashes of virtual nodes.
+	nodes    map[uint32]string   // Hash of virtual node to node.
+}
+
+// NewRing creates and returns a consistent hashing ring.
+// The parameter <replicas> specifies the count of virtual nodes for each weight unit of node,
+// which is 160 in default if it is not greater than 0.
+// The optional parameter <hash> specifies the hash function, which is crc32.ChecksumIEEE in default.
+func NewRing(replicas int, hash ...func([]byte) uint32) *Ring {
+	r := &Ring{
+		replicas: replicas,
+		hash:     crc32.ChecksumIEEE,
+		weights:  make(map[string]int),
+		nodes:    make(map[uint32]string),
+	}
+	if r.replicas <= 0 {
+		r.replicas = gRING_DEFAULT_REPLICAS
+	}
+	if len(hash) > 0 && hash[0] != nil {
+		r.hash = hash[0]
+	}
+	return r
+}
+
+// Add adds <node> to the ring with <weight>, which is 1 in default.
+// It updates the weight of the node if it already exists.
+func (r *Ring) Add(node string, weight ...int) {
+	w := 1
+	if len(weight) > 0 && weight[0] > 0 {
+		w = weight[0]
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.weights[node] = w
+	r.build()
+}
+
+// Remove removes <node> from the ring.
+func (r *Ring) Remove(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.weights[node]; ok {
+		delete(r.weights, node)
+		r.build()
+	}
+}
+
+// Get returns the node that <key> is mapped to.
+// It returns empty string if there's no node in the ring.
+func (r *Ring) Get(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	return r.nodes[r.hashes[r.search(key)]]
+}
+
+// GetN returns at most <n> distinct nodes for <key> in the clockwise order of the ring,
+// the first of which is the same as Get, it is useful for data replication.
+func (r *Ring) GetN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n > len(r.weights) {
+		n = len(r.weights)
+	}
+	if n <= 0 {
+		return nil
+	}
+	var (
+		nodes = make([]string, 0, n)
+		seen  = make(map[string]struct{}, n)
+		index = r.search(key)
+	)
+	for i := 0; i < len(r.hashes) && len(nodes) < n; i++ {
+		node := r.nodes[r.hashes[(index+i)%len(r.hashes)]]
+		if _, ok := seen[node]; !ok {
+			seen[node] = struct{}{}
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Nodes returns all the nodes of the ring in ascending order.
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := make([]string, 0, len(r.weights))
+	for node := range r.weights {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Weight returns the weight of <node>, it returns 0 if the node does not exist.
+func (r *Ring) Weight(node string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.weights[node]
+}
+
+// Len returns the count of nodes of the ring.
+func (r *Ring) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.weights)
+}
+
+// search returns the index of the first virtual node whose hash is not less than the hash of <key>.
+func (r *Ring) search(key string) int {
+	hash := r.hash([]byte(key))
+	index := sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= hash
+	})
+	if index == len(r.hashes) {
+		index = 0
+	}
+	return index
+}
+
+// build rebuilds the virtual nodes of the ring.
+// The nodes are placed in ascending order, and the former node wins if hash collision occurs,
+// which makes the ring deterministic regardless of the adding order.
+func (r *Ring) build() {
+	nodes := make([]string, 0, len(r.weights))
+	for node := range r.weights {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	r.nodes = make(map[uint32]string)
+	r.hashes = r.hashes[:0]
+	for _, node := range nodes {
+		count := r.replicas * r.weights[node]
+		for i := 0; i < count; i++ {
+			hash := r.hash([]byte(node + "#" + strconv.Itoa(i)))
+			if _, ok := r.nodes[hash]; ok {
+				continue
+			}
+			r.nodes[hash] = node
+			r.hashes = append(r.hashes, hash)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool {
+		return r.hashes[i] < r.hashes[j]
+	})
+}
@@ -0,0 +1,86 @@
+package ghash_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/g/encoding/ghash"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Ring_Basic(t *testing.T) {
+	gtest.Case(t, func() {
+		r := ghash.NewRing(0)
+		gtest.Assert(r.Get("key"), "")
+		gtest.Assert(r.GetN("key", 2), nil)
+
+		r.Add("node1")
+		r.Add("node2", 2)
+		r.Add("node3")
+		gtest.Assert(r.Len(), 3)
+		gtest.Assert(r.Nodes(), []string{"node1", "node2", "node3"})
+		gtest.Assert(r.Weight("node2"), 2)
+		gtest.Assert(r.Weight("none"), 0)
+
+		gtest.AssertIN(r.Get("key"), r.Nodes())
+		nodes := r.GetN("key", 5)
+		gtest.Assert(len(nodes), 3)
+		gtest.Assert(nodes[0], r.Get("key"))
+
+		r.Remove("node2")
+		r.Remove("none")
+		gtest.Assert(r.Nodes(), []string{"node1", "node3"})
+	})
+}
+
+func Test_Ring_Stable(t *testing.T) {
+	gtest.Case(t, func() {
+		r1 := ghash.NewRing(100)
+		r2 := ghash.NewRing(100)
+		for i := 1; i <= 4; i++ {
+			r1.Add(fmt.Sprintf("node%d", i))
+			r2.Add(fmt.Sprintf("node%d", 5-i))
+		}
+		before := make(map[string]string)
+		for i := 0; i < 10000; i++ {
+			key := fmt.Sprintf("key%d", i)
+			before[key] = r1.Get(key)
+			// Same mapping regardless of the adding order.
+			gtest.Assert(r2.Get(key), before[key])
+		}
+		// Only the keys of the removed node are remapped.
+		r1.Remove("node2")
+		for key, node := range before {
+			if node != "node2" {
+				gtest.Assert(r1.Get(key), node)
+			} else {
+				gtest.AssertNE(r1.Get(key), "node2")
+			}
+		}
+		// Keys are only moved to the newly added node.
+		r1.Add("node2")
+		r1.Add("node5")
+		moved := 0
+		for key, node := range before {
+			if n := r1.Get(key); n != node {
+				gtest.Assert(n, "node5")
+				moved++
+			}
+		}
+		gtest.AssertGT(moved, 1000)
+		gtest.AssertLT(moved, 3000)
+	})
+}
+
+func Test_Ring_Weight(t *testing.T) {
+	gtest.Case(t, func() {
+		r := ghash.NewRing(100)
+		r.Add("node1", 1)
+		r.Add("node2", 3)
+		counts := make(map[string]int)
+		for i := 0; i < 10000; i++ {
+			counts[r.Get(fmt.Sprintf("key%d", i))]++
+		}
+		gtest.AssertGT(counts["node2"], counts["node1"]*2)
+	})
+}
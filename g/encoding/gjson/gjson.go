@@ -11,6 +11,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"unsafe"
 
 	"github.com/gf/g/internal/rwmutex"
 	"github.com/gf/g/text/gstr"
@@ -23,6 +25,12 @@ const (
 )
 
 // The customized JSON struct.
+//
+// For concurrent-safe Json object, the data is copy-on-write: writing operations
+// copy the nodes along the pattern path and then replace the root pointer atomically,
+// so reading operations like Get* are lock-free and never see a partially updated data.
+// For un-concurrent-safe Json object, writing operations update the data in place,
+// which is more efficient for bulk building.
 type Json struct {
 	mu *rwmutex.RWMutex
 	p  *interface{} // Pointer for hierarchical data access, it's the root of data in default.
@@ -30,6 +38,11 @@ type Json struct {
 	// Violence Check(false in default), which is used to access data
 	// when the hierarchical data key contains separator char.
 	vc bool
+	// Key order of objects by their pattern, which is nil if ordered mode is not enabled.
+	o map[string][]string
+	// XML attribute mode(false in default), in which the keys with "@" prefix are
+	// encoded as XML attributes by ToXml/ToXmlIndent. See SetXmlAttr.
+	xa bool
 }
 
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
@@ -42,21 +55,38 @@ func (j *Json) MarshalJSON() ([]byte, error) {
 // 1. If value is nil and removed is true, means deleting this value;
 // 2. It's quite complicated in hierarchical data search, node creating and data assignment;
 func (j *Json) setValue(pattern string, value interface{}, removed bool) error {
+	value = j.convertValue(value)
+	j.mu.Lock()
+	defer j.mu.Unlock()
 	array := strings.Split(pattern, string(j.c))
+	if j.o != nil {
+		j.updateOrderedKeys(array, removed && value == nil)
+	}
+	if !j.mu.IsSafe() {
+		return j.doSetValue(j.p, array, value, removed)
+	}
+	// Copy-on-write, the readers still hold the old root.
+	root := copyOnWrite(*j.p, array)
+	if err := j.doSetValue(&root, array, value, removed); err != nil {
+		return err
+	}
+	atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&j.p)), unsafe.Pointer(&root))
+	return nil
+}
+
+// doSetValue sets <value> to the node of pattern <array> in data <root>, in place.
+func (j *Json) doSetValue(root *interface{}, array []string, value interface{}, removed bool) error {
 	length := len(array)
-	value = j.convertValue(value)
 	// 初始化判断
-	if *j.p == nil {
+	if *root == nil {
 		if gstr.IsNumeric(array[0]) {
-			*j.p = make([]interface{}, 0)
+			*root = make([]interface{}, 0)
 		} else {
-			*j.p = make(map[string]interface{})
+			*root = make(map[string]interface{})
 		}
 	}
-	var pparent *interface{} = nil // 父级元素项(设置时需要根据子级的内容确定数据类型，所以必须记录父级)
-	var pointer *interface{} = j.p // 当前操作层级项
-	j.mu.Lock()
-	defer j.mu.Unlock()
+	var pparent *interface{} = nil  // 父级元素项(设置时需要根据子级的内容确定数据类型，所以必须记录父级)
+	var pointer *interface{} = root // 当前操作层级项
 	for i := 0; i < length; i++ {
 		switch (*pointer).(type) {
 		case map[string]interface{}:
@@ -199,8 +229,61 @@ done:
 	return nil
 }
 
+// copyOnWrite returns a copy of data <root>, in which the map and slice nodes along
+// the pattern <array> are shallow copied, so that they can be updated in place
+// without affecting the original data. The other nodes are shared with <root>.
+func copyOnWrite(root interface{}, array []string) interface{} {
+	root = copyNode(root)
+	node := root
+	for _, key := range array {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			child, ok := v[key]
+			if !ok {
+				return root
+			}
+			child = copyNode(child)
+			v[key] = child
+			node = child
+		case []interface{}:
+			n, err := strconv.Atoi(key)
+			if err != nil || n < 0 || n >= len(v) {
+				return root
+			}
+			v[n] = copyNode(v[n])
+			node = v[n]
+		default:
+			return root
+		}
+	}
+	return root
+}
+
+// copyNode returns a shallow copy of <value> if it's a map or slice, or else <value> itself.
+func copyNode(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = item
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		copy(s, v)
+		return s
+	}
+	return value
+}
+
+// root returns the pointer to the root of data, which is safe for lock-free reading.
+func (j *Json) root() *interface{} {
+	return (*interface{})(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&j.p))))
+}
+
 // Convert <value> to map[string]interface{} or []interface{},
 // which can be supported for hierarchical data access.
+// The big numbers are converted to json.Number, keeping their precision.
 func (j *Json) convertValue(value interface{}) interface{} {
 	switch value.(type) {
 	case map[string]interface{}:
@@ -208,6 +291,9 @@ func (j *Json) convertValue(value interface{}) interface{} {
 	case []interface{}:
 		return value
 	default:
+		if n, ok := bigNumberToJsonNumber(value); ok {
+			return n
+		}
 		rv := reflect.ValueOf(value)
 		kind := rv.Kind()
 		if kind == reflect.Ptr {
@@ -274,7 +360,7 @@ func (j *Json) getPointerByPatternWithViolenceCheck(pattern string) *interface{}
 	index := len(pattern)
 	start := 0
 	length := 0
-	pointer := j.p
+	pointer := j.root()
 	if index == 0 {
 		return pointer
 	}
@@ -310,21 +396,17 @@ func (j *Json) getPointerByPatternWithoutViolenceCheck(pattern string) *interfac
 	if j.vc {
 		return j.getPointerByPatternWithViolenceCheck(pattern)
 	}
-	pointer := j.p
+	pointer := j.root()
 	if len(pattern) == 0 {
 		return pointer
 	}
-	array := strings.Split(pattern, string(j.c))
-	for k, v := range array {
-		if r := j.checkPatternByPointer(v, pointer); r != nil {
-			if k == len(array)-1 {
-				return r
-			} else {
-				pointer = r
-			}
-		} else {
-			break
-		}
+	// Pattern of single key needs no parsing.
+	if strings.IndexByte(pattern, j.c) == -1 {
+		return j.checkPatternByPointer(pattern, pointer)
+	}
+	// The parsed pattern is cached for frequently used patterns.
+	if value, ok := j.getValueByKeys(getPattern(pattern, j.c).keys); ok {
+		return &value
 	}
 	return nil
 }
@@ -8,6 +8,7 @@ package gjson
 
 import (
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/gf/g/container/gvar"
@@ -17,9 +18,7 @@ import (
 
 // Val returns the json value.
 func (j *Json) Value() interface{} {
-	j.mu.RLock()
-	defer j.mu.RUnlock()
-	return *(j.p)
+	return *(j.root())
 }
 
 // Get returns value by specified <pattern>.
@@ -30,10 +29,9 @@ func (j *Json) Value() interface{} {
 // eg: "items.name.first", "list.10".
 //
 // It returns a default value specified by <def> if value for <pattern> is not found.
+//
+// It does not lock the Json object, as the data of concurrent-safe Json object is copy-on-write.
 func (j *Json) Get(pattern string, def ...interface{}) interface{} {
-	j.mu.RLock()
-	defer j.mu.RUnlock()
-
 	var result *interface{}
 	if j.vc {
 		result = j.getPointerByPattern(pattern)
@@ -69,7 +67,14 @@ func (j *Json) GetMap(pattern string, def ...interface{}) map[string]interface{}
 func (j *Json) GetJson(pattern string, def ...interface{}) *Json {
 	result := j.Get(pattern, def...)
 	if result != nil {
-		return New(result, true)
+		r := New(result, true)
+		j.mu.RLock()
+		if j.o != nil {
+			r.o = j.subOrderedKeys(pattern)
+		}
+		r.xa = j.xa
+		j.mu.RUnlock()
+		return r
 	}
 	return nil
 }
@@ -170,6 +175,22 @@ func (j *Json) GetFloat64(pattern string, def ...interface{}) float64 {
 	return gconv.Float64(j.Get(pattern, def...))
 }
 
+// GetBigInt gets the value by specified <pattern>,
+// and converts it to *big.Int without precision loss, which is useful for 64-bit IDs.
+// It returns nil if the value is not an integer.
+func (j *Json) GetBigInt(pattern string, def ...interface{}) *big.Int {
+	return toBigInt(j.Get(pattern, def...))
+}
+
+// GetDecimal gets the value by specified <pattern>,
+// and converts it to *big.Rat without precision loss, which is useful for money values.
+// It returns nil if the value is not a number.
+//
+// Use its FloatString method to format it, eg: j.GetDecimal("price").FloatString(2).
+func (j *Json) GetDecimal(pattern string, def ...interface{}) *big.Rat {
+	return toBigRat(j.Get(pattern, def...))
+}
+
 func (j *Json) GetFloats(pattern string, def ...interface{}) []float64 {
 	return gconv.Floats(j.Get(pattern, def...))
 }
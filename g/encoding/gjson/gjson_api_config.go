@@ -19,3 +19,31 @@ func (j *Json) SetViolenceCheck(enabled bool) {
 	j.vc = enabled
 	j.mu.Unlock()
 }
+
+// SetKeepOrder enables/disables ordered mode for current Json object.
+// In ordered mode, ToJson/ToJsonIndent/Dump emit object keys in their original document
+// order if the object is created by LoadOrdered/LoadContentOrdered, or in insertion order
+// for keys added by Set. Keys having no recorded order are emitted in ascending order.
+//
+// Note that the order is recorded using patterns of the keys, so it should not change
+// the separator char after ordered mode is enabled.
+func (j *Json) SetKeepOrder(enabled bool) {
+	j.mu.Lock()
+	if !enabled {
+		j.o = nil
+	} else if j.o == nil {
+		j.o = make(map[string][]string)
+	}
+	j.mu.Unlock()
+}
+
+// SetXmlAttr enables/disables XML attribute mode for current Json object.
+// In XML attribute mode, ToXml/ToXmlIndent encode keys with "@" prefix as XML attributes,
+// and key "#text" as the text content of the element, which is the data structure
+// created by LoadContentType with data type "xml-attr". So that XML documents
+// with attributes and namespace prefixes can be round-tripped.
+func (j *Json) SetXmlAttr(enabled bool) {
+	j.mu.Lock()
+	j.xa = enabled
+	j.mu.Unlock()
+}
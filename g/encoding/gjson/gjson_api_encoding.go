@@ -7,51 +7,145 @@
 package gjson
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
+	"sync"
 
 	"github.com/gf/g/encoding/gtoml"
 	"github.com/gf/g/encoding/gxml"
 	"github.com/gf/g/encoding/gyaml"
+	"github.com/gf/g/util/gconv"
 )
 
+const (
+	// Buffers larger than this are not put back to the pool, to avoid holding too much memory.
+	gMAX_POOLED_BUFFER_SIZE = 64 * 1024
+)
+
+var (
+	// Pool of the buffers for encoding.
+	bufferPool = sync.Pool{
+		New: func() interface{} {
+			return new(bytes.Buffer)
+		},
+	}
+)
+
+// getBuffer retrieves an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	return buffer
+}
+
+// putBuffer puts <buffer> back to the pool.
+func putBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() <= gMAX_POOLED_BUFFER_SIZE {
+		bufferPool.Put(buffer)
+	}
+}
+
 func (j *Json) ToXml(rootTag ...string) ([]byte, error) {
+	if j.isXmlAttr() {
+		return gxml.EncodeWithAttr(j.ToMap(), rootTag...)
+	}
 	return gxml.Encode(j.ToMap(), rootTag...)
 }
 
 func (j *Json) ToXmlString(rootTag ...string) (string, error) {
 	b, e := j.ToXml(rootTag...)
-	return string(b), e
+	return gconv.UnsafeBytesToStr(b), e
 }
 
 func (j *Json) ToXmlIndent(rootTag ...string) ([]byte, error) {
+	if j.isXmlAttr() {
+		return gxml.EncodeWithAttrIndent(j.ToMap(), rootTag...)
+	}
 	return gxml.EncodeWithIndent(j.ToMap(), rootTag...)
 }
 
+// isXmlAttr checks whether current Json object is in XML attribute mode.
+func (j *Json) isXmlAttr() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.xa
+}
+
 func (j *Json) ToXmlIndentString(rootTag ...string) (string, error) {
 	b, e := j.ToXmlIndent(rootTag...)
-	return string(b), e
+	return gconv.UnsafeBytesToStr(b), e
 }
 
 func (j *Json) ToJson() ([]byte, error) {
-	j.mu.RLock()
-	defer j.mu.RUnlock()
-	return Encode(*(j.p))
+	return j.toJsonBytes(false)
 }
 
 func (j *Json) ToJsonString() (string, error) {
 	b, e := j.ToJson()
-	return string(b), e
+	return gconv.UnsafeBytesToStr(b), e
 }
 
 func (j *Json) ToJsonIndent() ([]byte, error) {
+	return j.toJsonBytes(true)
+}
+
+// EncodeTo encodes current Json object as JSON and writes it to <w> in one Write call,
+// which is usually a response writer, so that no intermediate result is allocated.
+// The optional parameter <indent> specifies whether the JSON is indented like ToJsonIndent.
+func (j *Json) EncodeTo(w io.Writer, indent ...bool) error {
+	buffer := getBuffer()
+	defer putBuffer(buffer)
+	if err := j.encodeJson(buffer, len(indent) > 0 && indent[0]); err != nil {
+		return err
+	}
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// toJsonBytes encodes current Json object as JSON using pooled buffer,
+// and returns a copy of the result.
+func (j *Json) toJsonBytes(indent bool) ([]byte, error) {
+	buffer := getBuffer()
+	defer putBuffer(buffer)
+	if err := j.encodeJson(buffer, indent); err != nil {
+		return nil, err
+	}
+	b := make([]byte, buffer.Len())
+	copy(b, buffer.Bytes())
+	return b, nil
+}
+
+// encodeJson encodes current Json object as JSON into <buffer>, in recorded key order if it's ordered.
+func (j *Json) encodeJson(buffer *bytes.Buffer, indent bool) error {
 	j.mu.RLock()
 	defer j.mu.RUnlock()
-	return json.MarshalIndent(*(j.p), "", "\t")
+	if j.o != nil {
+		if !indent {
+			return j.encodeOrdered(buffer, "", *(j.p))
+		}
+		temp := getBuffer()
+		defer putBuffer(temp)
+		if err := j.encodeOrdered(temp, "", *(j.p)); err != nil {
+			return err
+		}
+		return json.Indent(buffer, temp.Bytes(), "", "\t")
+	}
+	encoder := json.NewEncoder(buffer)
+	if indent {
+		encoder.SetIndent("", "\t")
+	}
+	if err := encoder.Encode(*(j.p)); err != nil {
+		return err
+	}
+	// Removing the newline appended by json.Encoder, to be the same as json.Marshal.
+	buffer.Truncate(buffer.Len() - 1)
+	return nil
 }
 
 func (j *Json) ToJsonIndentString() (string, error) {
 	b, e := j.ToJsonIndent()
-	return string(b), e
+	return gconv.UnsafeBytesToStr(b), e
 }
 
 func (j *Json) ToYaml() ([]byte, error) {
@@ -62,7 +156,7 @@ func (j *Json) ToYaml() ([]byte, error) {
 
 func (j *Json) ToYamlString() (string, error) {
 	b, e := j.ToYaml()
-	return string(b), e
+	return gconv.UnsafeBytesToStr(b), e
 }
 
 func (j *Json) ToToml() ([]byte, error) {
@@ -73,5 +167,5 @@ func (j *Json) ToToml() ([]byte, error) {
 
 func (j *Json) ToTomlString() (string, error) {
 	b, e := j.ToToml()
-	return string(b), e
+	return gconv.UnsafeBytesToStr(b), e
 }
@@ -13,12 +13,14 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/gf/g/encoding/gtoml"
 	"github.com/gf/g/encoding/gxml"
 	"github.com/gf/g/encoding/gyaml"
 	"github.com/gf/g/internal/rwmutex"
 	"github.com/gf/g/os/gfcache"
+	"github.com/gf/g/os/gfile"
 	"github.com/gf/g/text/gregex"
 	"github.com/gf/g/util/gconv"
 )
@@ -128,7 +130,11 @@ func DecodeToJson(data interface{}, unsafe ...bool) (*Json, error) {
 
 // Load loads content from specified file <path>,
 // and creates a Json object from its content.
+// The file with extension ".json5" is loaded in lenient JSON5 mode.
 func Load(path string, unsafe ...bool) (*Json, error) {
+	if gfile.Ext(path) == ".json5" {
+		return LoadContentType(gfcache.GetBinContents(path), "json5", unsafe...)
+	}
 	return LoadContent(gfcache.GetBinContents(path), unsafe...)
 }
 
@@ -136,33 +142,152 @@ func Load(path string, unsafe ...bool) (*Json, error) {
 // it checks the data type of <content> automatically,
 // supporting JSON, XML, YAML and TOML types of data.
 func LoadContent(data interface{}, unsafe ...bool) (*Json, error) {
-	var err error
-	var result interface{}
 	b := gconv.Bytes(data)
-	t := ""
 	if len(b) == 0 {
 		return New(nil, unsafe...), nil
 	}
-	// auto check data type
+	b, err := contentToJson(b, "")
+	if err != nil {
+		return nil, err
+	}
+	return decodeContent(b, unsafe...)
+}
+
+// LoadContentType creates a Json object from given content of specified <dataType>,
+// which can be: json, json5, xml, xml-attr, yml/yaml, toml.
+//
+// The json5 type is a lenient mode for JSON content written by humans,
+// which accepts //-comments, /* */-comments, trailing commas, unquoted keys
+// and single-quoted strings.
+//
+// The xml-attr type keeps the attributes and namespace prefixes of XML content,
+// the attributes are mapped to keys with "@" prefix, eg: {"@id": "1"}.
+// The created Json object is in XML attribute mode, see SetXmlAttr.
+func LoadContentType(data interface{}, dataType string, unsafe ...bool) (*Json, error) {
+	b := gconv.Bytes(data)
+	if len(b) == 0 {
+		return New(nil, unsafe...), nil
+	}
+	b, err := contentToJson(b, dataType)
+	if err != nil {
+		return nil, err
+	}
+	j, err := decodeContent(b, unsafe...)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(dataType, "xml-attr") {
+		j.xa = true
+	}
+	return j, nil
+}
+
+// LoadOrdered loads content from specified file <path>,
+// and creates a Json object in ordered mode from its content.
+// See LoadContentOrdered.
+func LoadOrdered(path string, unsafe ...bool) (*Json, error) {
+	return LoadContentOrdered(gfcache.GetBinContents(path), unsafe...)
+}
+
+// LoadContentOrdered creates a Json object in ordered mode from given content,
+// which keeps the key order of objects in the original document for ToJson/ToJsonIndent/Dump.
+// It is useful for config files, signature canonicalization and human diffing.
+//
+// Note that the key order of XML/YAML/TOML content cannot be kept,
+// as they are converted to JSON using unordered maps.
+func LoadContentOrdered(data interface{}, unsafe ...bool) (*Json, error) {
+	b := gconv.Bytes(data)
+	if len(b) == 0 {
+		j := New(nil, unsafe...)
+		j.o = make(map[string][]string)
+		return j, nil
+	}
+	b, err := contentToJson(b, "")
+	if err != nil {
+		return nil, err
+	}
+	j, err := decodeContent(b, unsafe...)
+	if err != nil {
+		return nil, err
+	}
+	if j.o, err = parseOrderedKeys(b, j.c); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// LoadPrecise loads content from specified file <path>,
+// and creates a Json object keeping the precision of numbers from its content.
+// See LoadContentPrecise.
+func LoadPrecise(path string, unsafe ...bool) (*Json, error) {
+	return LoadContentPrecise(gfcache.GetBinContents(path), unsafe...)
+}
+
+// LoadContentPrecise creates a Json object from given content like LoadContent,
+// but the numbers of YAML content are also decoded as json.Number keeping their original text,
+// so that big integers and decimals survive Get/Set/ToJson round-trips without precision loss.
+//
+// Note that the numbers of JSON content are always decoded as json.Number, and the values of XML content
+// are decoded as strings, which keep their precision in LoadContent as well.
+// The numbers of TOML content are still decoded as int64/float64.
+func LoadContentPrecise(data interface{}, unsafe ...bool) (*Json, error) {
+	b := gconv.Bytes(data)
+	if len(b) == 0 {
+		return New(nil, unsafe...), nil
+	}
+	t, err := checkDataType(b)
+	if err != nil {
+		return nil, err
+	}
+	if t == "yml" {
+		b, err = gyaml.ToJsonNumber(b)
+	} else {
+		b, err = contentToJson(b, t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeContent(b, unsafe...)
+}
+
+// checkDataType checks and returns the data type of content <b>.
+func checkDataType(b []byte) (string, error) {
 	if json.Valid(b) {
-		t = "json"
+		return "json", nil
 	} else if gregex.IsMatch(`^<.+>[\S\s]+<.+>$`, b) {
-		t = "xml"
+		return "xml", nil
 	} else if gregex.IsMatch(`^[\s\t]*\w+\s*:\s*.+`, b) || gregex.IsMatch(`\n[\s\t]*\w+\s*:\s*.+`, b) {
-		t = "yml"
+		return "yml", nil
 	} else if gregex.IsMatch(`^[\s\t]*\w+\s*=\s*.+`, b) || gregex.IsMatch(`\n[\s\t]*\w+\s*=\s*.+`, b) {
-		t = "toml"
-	} else {
-		return nil, errors.New("unsupported data type")
+		return "toml", nil
+	}
+	return "", errors.New("unsupported data type")
+}
+
+// contentToJson converts content <b> of data type <t> to JSON content.
+// It checks the data type of <b> automatically if <t> is empty.
+func contentToJson(b []byte, t string) ([]byte, error) {
+	var err error
+	// auto check data type
+	if t == "" {
+		if t, err = checkDataType(b); err != nil {
+			return nil, err
+		}
 	}
 	// convert to json type data
-	switch t {
+	switch strings.ToLower(t) {
 	case "json", ".json":
 		// ok
+	case "json5", ".json5":
+		b, err = json5ToJson(b)
+
 	case "xml", ".xml":
 		// TODO UseNumber
 		b, err = gxml.ToJson(b)
 
+	case "xml-attr":
+		b, err = gxml.ToJsonWithAttr(b)
+
 	case "yml", "yaml", ".yml", ".yaml":
 		// TODO UseNumber
 		b, err = gyaml.ToJson(b)
@@ -174,19 +299,20 @@ func LoadContent(data interface{}, unsafe ...bool) (*Json, error) {
 	default:
 		err = errors.New("nonsupport type " + t)
 	}
-	if err != nil {
+	return b, err
+}
+
+// decodeContent decodes JSON content <b> and creates a Json object.
+func decodeContent(b []byte, unsafe ...bool) (*Json, error) {
+	var result interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
 		return nil, err
 	}
-	if result == nil {
-		decoder := json.NewDecoder(bytes.NewReader(b))
-		decoder.UseNumber()
-		if err := decoder.Decode(&result); err != nil {
-			return nil, err
-		}
-		switch result.(type) {
-		case string, []byte:
-			return nil, fmt.Errorf(`json decoding failed for content: %s`, string(b))
-		}
+	switch result.(type) {
+	case string, []byte:
+		return nil, fmt.Errorf(`json decoding failed for content: %s`, string(b))
 	}
 	return New(result, unsafe...), nil
 }
@@ -0,0 +1,209 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+// json5ToJson converts lenient JSON5 content <b> to standard JSON content.
+// It removes //-comments, /* */-comments and trailing commas,
+// quotes unquoted keys and converts single-quoted strings to double-quoted ones.
+func json5ToJson(b []byte) ([]byte, error) {
+	buffer := bytes.NewBuffer(make([]byte, 0, len(b)))
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == '/':
+			n, err := json5SkipComment(b, i)
+			if err != nil {
+				return nil, err
+			}
+			// Keeps the line number of the original content for error reporting.
+			buffer.Write(bytes.Repeat([]byte{'\n'}, bytes.Count(b[i:n], []byte{'\n'})))
+			i = n
+
+		case c == '"' || c == '\'':
+			n, err := json5WriteString(buffer, b, i)
+			if err != nil {
+				return nil, err
+			}
+			i = n
+
+		case c == ',':
+			n, err := json5SkipSpace(b, i+1)
+			if err != nil {
+				return nil, err
+			}
+			// Trailing comma.
+			if n < len(b) && (b[n] == '}' || b[n] == ']') {
+				i++
+				continue
+			}
+			buffer.WriteByte(c)
+			i++
+
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			n := i + 1
+			for n < len(b) && (json5IsIdentifierPart(b[n]) || b[n] == '.' || b[n] == '+' || b[n] == '-') {
+				n++
+			}
+			if err := json5WriteNumber(buffer, b[i:n]); err != nil {
+				return nil, fmt.Errorf(`%s at offset %d`, err.Error(), i)
+			}
+			i = n
+
+		case json5IsIdentifierStart(c):
+			n := i + 1
+			for n < len(b) && json5IsIdentifierPart(b[n]) {
+				n++
+			}
+			word := b[i:n]
+			next, err := json5SkipSpace(b, n)
+			if err != nil {
+				return nil, err
+			}
+			if next < len(b) && b[next] == ':' {
+				// Unquoted key.
+				buffer.WriteByte('"')
+				buffer.Write(word)
+				buffer.WriteByte('"')
+			} else {
+				switch string(word) {
+				case "true", "false", "null":
+					buffer.Write(word)
+				default:
+					return nil, fmt.Errorf(`invalid character '%s' at offset %d`, string(word), i)
+				}
+			}
+			i = n
+
+		default:
+			buffer.WriteByte(c)
+			i++
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// json5SkipComment skips the comment starting at <i>, and returns the index after it.
+func json5SkipComment(b []byte, i int) (int, error) {
+	if i+1 < len(b) {
+		switch b[i+1] {
+		case '/':
+			if n := bytes.IndexByte(b[i:], '\n'); n != -1 {
+				return i + n, nil
+			}
+			return len(b), nil
+		case '*':
+			if n := bytes.Index(b[i+2:], []byte("*/")); n != -1 {
+				return i + 2 + n + 2, nil
+			}
+			return 0, fmt.Errorf(`unterminated comment at offset %d`, i)
+		}
+	}
+	return 0, fmt.Errorf(`invalid character '/' at offset %d`, i)
+}
+
+// json5SkipSpace skips the white spaces and comments starting at <i>,
+// and returns the index of next significant character.
+func json5SkipSpace(b []byte, i int) (int, error) {
+	var err error
+	for i < len(b) {
+		switch b[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		case '/':
+			if i, err = json5SkipComment(b, i); err != nil {
+				return 0, err
+			}
+		default:
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+// json5WriteString writes the string starting at <i> as a double-quoted JSON string
+// to <buffer>, and returns the index after the string.
+func json5WriteString(buffer *bytes.Buffer, b []byte, i int) (int, error) {
+	quote := b[i]
+	buffer.WriteByte('"')
+	for n := i + 1; n < len(b); n++ {
+		switch c := b[n]; c {
+		case quote:
+			buffer.WriteByte('"')
+			return n + 1, nil
+		case '\\':
+			if n+1 >= len(b) {
+				break
+			}
+			n++
+			switch b[n] {
+			case '\'':
+				buffer.WriteByte('\'')
+			case '\n':
+				// Line continuation.
+			default:
+				buffer.WriteByte('\\')
+				buffer.WriteByte(b[n])
+			}
+		case '"':
+			buffer.WriteString(`\"`)
+		case '\n':
+			return 0, fmt.Errorf(`unterminated string at offset %d`, i)
+		default:
+			buffer.WriteByte(c)
+		}
+	}
+	return 0, fmt.Errorf(`unterminated string at offset %d`, i)
+}
+
+// json5WriteNumber writes JSON5 number <number> as a standard JSON number to <buffer>.
+// The leading '+' is removed, the hexadecimal number is converted to decimal,
+// and the leading or trailing decimal point is completed, eg: .5 -> 0.5, 5. -> 5.
+func json5WriteNumber(buffer *bytes.Buffer, number []byte) error {
+	sign := ""
+	switch number[0] {
+	case '-':
+		sign = "-"
+		number = number[1:]
+	case '+':
+		number = number[1:]
+	}
+	if len(number) > 2 && number[0] == '0' && (number[1] == 'x' || number[1] == 'X') {
+		value, ok := new(big.Int).SetString(string(number[2:]), 16)
+		if !ok {
+			return fmt.Errorf(`invalid hexadecimal number '%s%s'`, sign, string(number))
+		}
+		buffer.WriteString(sign)
+		buffer.WriteString(value.String())
+		return nil
+	}
+	buffer.WriteString(sign)
+	if len(number) > 0 && number[0] == '.' {
+		buffer.WriteByte('0')
+	}
+	if pos := bytes.IndexByte(number, '.'); pos != -1 &&
+		(pos == len(number)-1 || number[pos+1] == 'e' || number[pos+1] == 'E') {
+		buffer.Write(number[:pos])
+		buffer.Write(number[pos+1:])
+		return nil
+	}
+	buffer.Write(number)
+	return nil
+}
+
+func json5IsIdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func json5IsIdentifierPart(c byte) bool {
+	return json5IsIdentifierStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/gf/g/util/gconv"
+)
+
+// bigNumberToJsonNumber converts big number <value> to json.Number,
+// which keeps its precision for encoding.
+// It returns false if <value> is not a big number.
+func bigNumberToJsonNumber(value interface{}) (json.Number, bool) {
+	switch v := value.(type) {
+	case *big.Int:
+		return json.Number(v.String()), true
+	case big.Int:
+		return json.Number(v.String()), true
+	case *big.Float:
+		return json.Number(v.Text('f', -1)), true
+	case big.Float:
+		return json.Number(v.Text('f', -1)), true
+	case *big.Rat:
+		return json.Number(ratToDecimalString(v)), true
+	case big.Rat:
+		return json.Number(ratToDecimalString(&v)), true
+	}
+	return "", false
+}
+
+// ratToDecimalString converts <r> to decimal string.
+// The result is exact if the denominator of <r> has only factors 2 and 5,
+// or else it is rounded to 16 digits after the decimal point.
+func ratToDecimalString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	var (
+		denom  = new(big.Int).Set(r.Denom())
+		two    = big.NewInt(2)
+		five   = big.NewInt(5)
+		mod    = new(big.Int)
+		count2 = 0
+		count5 = 0
+	)
+	for mod.Mod(denom, two).Sign() == 0 {
+		denom.Quo(denom, two)
+		count2++
+	}
+	for mod.Mod(denom, five).Sign() == 0 {
+		denom.Quo(denom, five)
+		count5++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return r.FloatString(16)
+	}
+	if count2 > count5 {
+		return r.FloatString(count2)
+	}
+	return r.FloatString(count5)
+}
+
+// numberString returns the decimal string of number <value> without float conversion.
+// It returns false if <value> is not a number.
+func numberString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil, bool:
+		return "", false
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	if n, ok := bigNumberToJsonNumber(value); ok {
+		return n.String(), true
+	}
+	s := strings.TrimSpace(gconv.String(value))
+	if s == "" {
+		return "", false
+	}
+	return s, true
+}
+
+// toBigInt converts <value> to *big.Int, it returns nil if <value> is not an integer.
+func toBigInt(value interface{}) *big.Int {
+	s, ok := numberString(value)
+	if !ok {
+		return nil
+	}
+	if i, ok := new(big.Int).SetString(s, 10); ok {
+		return i
+	}
+	// Integer in float format, eg: 1e3, 100.0.
+	if r, ok := new(big.Rat).SetString(s); ok && r.IsInt() {
+		return new(big.Int).Set(r.Num())
+	}
+	return nil
+}
+
+// toBigRat converts <value> to *big.Rat, it returns nil if <value> is not a number.
+func toBigRat(value interface{}) *big.Rat {
+	s, ok := numberString(value)
+	if !ok {
+		return nil
+	}
+	if r, ok := new(big.Rat).SetString(s); ok {
+		return r
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gf/g/text/gstr"
+)
+
+// parseOrderedKeys parses JSON content <data> with a streaming decoder,
+// and returns the key order of all objects indexed by their pattern joined by <char>.
+func parseOrderedKeys(data []byte, char byte) (map[string][]string, error) {
+	order := make(map[string][]string)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := parseOrderedValue(decoder, "", char, order); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return order, nil
+}
+
+// parseOrderedValue reads next value from <decoder> and records the key order
+// of objects into <order>, the <pattern> is the pattern of the current value.
+func parseOrderedValue(decoder *json.Decoder, pattern string, char byte, order map[string][]string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		keys := make([]string, 0)
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			keys = append(keys, key)
+			if err := parseOrderedValue(decoder, joinPattern(pattern, key, char), char, order); err != nil {
+				return err
+			}
+		}
+		order[pattern] = keys
+	case '[':
+		for i := 0; decoder.More(); i++ {
+			if err := parseOrderedValue(decoder, joinPattern(pattern, strconv.Itoa(i), char), char, order); err != nil {
+				return err
+			}
+		}
+	}
+	// Closing delimiter.
+	_, err = decoder.Token()
+	return err
+}
+
+// joinPattern joins the <parent> pattern and <key> with separator <char>.
+func joinPattern(parent string, key string, char byte) string {
+	if parent == "" {
+		return key
+	}
+	return parent + string(char) + key
+}
+
+// updateOrderedKeys updates the key order for pattern node <array> being set or removed.
+// It must be called before the data is changed, as it checks the current data to tell
+// object keys from array indexes.
+//
+// The recorded order of the node's descendants is dropped as the node is replaced or removed,
+// and the recorded order of the following elements is shifted if an array element is removed.
+func (j *Json) updateOrderedKeys(array []string, removed bool) {
+	j.removeOrderedKeys(strings.Join(array, string(j.c)))
+	parent := ""
+	node := *j.p
+	for i, key := range array {
+		last := i == len(array)-1
+		switch v := node.(type) {
+		case map[string]interface{}:
+			child, ok := v[key]
+			if removed {
+				if !ok {
+					return
+				}
+				if last {
+					j.removeOrderedKey(parent, key)
+				}
+			} else {
+				j.addOrderedKey(parent, key)
+			}
+			node = child
+
+		case []interface{}:
+			n, err := strconv.Atoi(key)
+			if err != nil {
+				if removed {
+					return
+				}
+				// The array is overwritten by an object.
+				j.removeOrderedKeys(parent)
+				j.addOrderedKey(parent, key)
+				node = nil
+				break
+			}
+			if n >= len(v) {
+				if removed {
+					return
+				}
+				node = nil
+				break
+			}
+			if last {
+				if removed {
+					j.shiftOrderedKeys(parent, n)
+				}
+				break
+			}
+			// The element is replaced by a new node if it's not the leaf.
+			j.removeOrderedKeys(joinPattern(parent, key, j.c))
+			node = nil
+
+		default:
+			if removed {
+				return
+			}
+			// New node created by Set, which is an object if the key is not numeric.
+			if !gstr.IsNumeric(key) {
+				j.addOrderedKey(parent, key)
+			}
+			node = nil
+		}
+		parent = joinPattern(parent, key, j.c)
+	}
+}
+
+// addOrderedKey appends <key> to the key order of object <pattern> if it's not recorded.
+func (j *Json) addOrderedKey(pattern string, key string) {
+	for _, v := range j.o[pattern] {
+		if v == key {
+			return
+		}
+	}
+	j.o[pattern] = append(j.o[pattern], key)
+}
+
+// removeOrderedKey removes <key> from the key order of object <pattern>.
+func (j *Json) removeOrderedKey(pattern string, key string) {
+	keys := j.o[pattern]
+	for i, v := range keys {
+		if v == key {
+			j.o[pattern] = append(keys[:i:i], keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// removeOrderedKeys removes the recorded key order of <pattern> and all its descendants.
+func (j *Json) removeOrderedKeys(pattern string) {
+	if pattern == "" {
+		for k := range j.o {
+			delete(j.o, k)
+		}
+		return
+	}
+	prefix := pattern + string(j.c)
+	for k := range j.o {
+		if k == pattern || strings.HasPrefix(k, prefix) {
+			delete(j.o, k)
+		}
+	}
+}
+
+// shiftOrderedKeys moves the recorded key order of elements after index <n>
+// of array <pattern> forward by one, as the element <n> is removed.
+func (j *Json) shiftOrderedKeys(pattern string, n int) {
+	prefix := ""
+	if pattern != "" {
+		prefix = pattern + string(j.c)
+	}
+	shifted := make(map[string][]string)
+	for k, v := range j.o {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		rest := k[len(prefix):]
+		index, tail := rest, ""
+		if pos := strings.IndexByte(rest, j.c); pos != -1 {
+			index, tail = rest[:pos], rest[pos:]
+		}
+		if i, err := strconv.Atoi(index); err == nil && i > n {
+			delete(j.o, k)
+			shifted[prefix+strconv.Itoa(i-1)+tail] = v
+		}
+	}
+	for k, v := range shifted {
+		j.o[k] = v
+	}
+}
+
+// subOrderedKeys returns the key order for value of <pattern> with the pattern prefix removed.
+func (j *Json) subOrderedKeys(pattern string) map[string][]string {
+	order := make(map[string][]string)
+	prefix := pattern + string(j.c)
+	for k, v := range j.o {
+		switch {
+		case k == pattern:
+			order[""] = v
+		case len(k) > len(prefix) && k[:len(prefix)] == prefix:
+			order[k[len(prefix):]] = v
+		}
+	}
+	return order
+}
+
+// encodeOrdered encodes <value> of <pattern> into <buffer> in recorded key order.
+// The keys that have no recorded order are appended in ascending order.
+func (j *Json) encodeOrdered(buffer *bytes.Buffer, pattern string, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		done := make(map[string]struct{}, len(v))
+		for _, key := range j.o[pattern] {
+			if _, ok := v[key]; ok {
+				if _, ok := done[key]; !ok {
+					keys = append(keys, key)
+					done[key] = struct{}{}
+				}
+			}
+		}
+		if len(keys) < len(v) {
+			rest := make([]string, 0, len(v)-len(keys))
+			for key := range v {
+				if _, ok := done[key]; !ok {
+					rest = append(rest, key)
+				}
+			}
+			sort.Strings(rest)
+			keys = append(keys, rest...)
+		}
+		buffer.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if b, err := json.Marshal(key); err != nil {
+				return err
+			} else {
+				buffer.Write(b)
+			}
+			buffer.WriteByte(':')
+			if err := j.encodeOrdered(buffer, joinPattern(pattern, key, j.c), v[key]); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+	case []interface{}:
+		buffer.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := j.encodeOrdered(buffer, joinPattern(pattern, strconv.Itoa(i), j.c), item); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+	default:
+		if b, err := json.Marshal(v); err != nil {
+			return err
+		} else {
+			buffer.Write(b)
+		}
+	}
+	return nil
+}
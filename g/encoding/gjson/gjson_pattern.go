@@ -0,0 +1,170 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gjson
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/text/gstr"
+)
+
+const (
+	// Max number of parsed patterns in the internal pattern cache.
+	gPATTERN_CACHE_SIZE = 1024
+)
+
+// Pattern is a precompiled pattern for fast hierarchical data access,
+// which does not split and parse the pattern string on each reading.
+// It's concurrent-safe and can be used with any Json object, see Precompile.
+type Pattern struct {
+	pattern   string       // The original pattern string.
+	separator byte         // Separator char of the pattern.
+	keys      []patternKey // Parsed keys of the pattern.
+}
+
+// patternKey is a parsed key of pattern.
+type patternKey struct {
+	key   string // Key for map access.
+	index int    // Index for slice access, which is -1 if the key is not numeric.
+}
+
+// patternCacheKey is the key of the internal pattern cache.
+type patternCacheKey struct {
+	pattern   string
+	separator byte
+}
+
+// patternCache is the LRU cache for parsed patterns, the front of the list is the most recently used.
+type patternCache struct {
+	mu    sync.Mutex
+	size  int
+	list  *list.List
+	items map[patternCacheKey]*list.Element
+}
+
+var (
+	// Internal cache for frequently used patterns.
+	patterns = &patternCache{
+		size:  gPATTERN_CACHE_SIZE,
+		list:  list.New(),
+		items: make(map[patternCacheKey]*list.Element),
+	}
+)
+
+// Precompile parses <pattern> and returns a precompiled Pattern for fast reading,
+// which is useful for frequently used patterns in hot path, eg:
+//
+//	var namePattern = gjson.Precompile("user.profile.name")
+//	name := namePattern.GetVar(j).String()
+//
+// The optional parameter <separator> specifies the separator char, which is '.' in default.
+// Note that the precompiled pattern falls back to Json.Get if the Json object
+// enables violence check or uses a different separator char.
+func Precompile(pattern string, separator ...byte) *Pattern {
+	c := byte(gDEFAULT_SPLIT_CHAR)
+	if len(separator) > 0 {
+		c = separator[0]
+	}
+	return newPattern(pattern, c)
+}
+
+// newPattern parses <pattern> with separator char <c>.
+func newPattern(pattern string, c byte) *Pattern {
+	p := &Pattern{
+		pattern:   pattern,
+		separator: c,
+	}
+	if len(pattern) == 0 {
+		return p
+	}
+	array := strings.Split(pattern, string(c))
+	p.keys = make([]patternKey, len(array))
+	for i, key := range array {
+		p.keys[i] = patternKey{key: key, index: -1}
+		if gstr.IsNumeric(key) {
+			if n, err := strconv.Atoi(key); err == nil {
+				p.keys[i].index = n
+			}
+		}
+	}
+	return p
+}
+
+// getPattern returns the parsed pattern from the internal LRU cache,
+// it parses and caches the pattern if it's not cached.
+func getPattern(pattern string, c byte) *Pattern {
+	key := patternCacheKey{pattern, c}
+	patterns.mu.Lock()
+	defer patterns.mu.Unlock()
+	if e, ok := patterns.items[key]; ok {
+		patterns.list.MoveToFront(e)
+		return e.Value.(*Pattern)
+	}
+	p := newPattern(pattern, c)
+	patterns.items[key] = patterns.list.PushFront(p)
+	for patterns.list.Len() > patterns.size {
+		e := patterns.list.Back()
+		patterns.list.Remove(e)
+		last := e.Value.(*Pattern)
+		delete(patterns.items, patternCacheKey{last.pattern, last.separator})
+	}
+	return p
+}
+
+// String returns the original pattern string.
+func (p *Pattern) String() string {
+	return p.pattern
+}
+
+// Get returns the value of <j> by the precompiled pattern, see Json.Get.
+func (p *Pattern) Get(j *Json, def ...interface{}) interface{} {
+	if j.vc || j.c != p.separator {
+		return j.Get(p.pattern, def...)
+	}
+	if result, ok := j.getValueByKeys(p.keys); ok {
+		return result
+	}
+	if len(def) > 0 {
+		return def[0]
+	}
+	return nil
+}
+
+// GetVar returns a *gvar.Var with the value of <j> by the precompiled pattern, see Json.GetVar.
+func (p *Pattern) GetVar(j *Json, def ...interface{}) *gvar.Var {
+	return gvar.New(p.Get(j, def...), true)
+}
+
+// Contains checks whether the value of <j> by the precompiled pattern exists.
+func (p *Pattern) Contains(j *Json) bool {
+	return p.Get(j) != nil
+}
+
+// getValueByKeys returns the value of parsed pattern <keys>, with no violence check.
+// The returned <ok> is false if the value is not found.
+func (j *Json) getValueByKeys(keys []patternKey) (value interface{}, ok bool) {
+	value = *j.root()
+	for _, k := range keys {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if value, ok = v[k.key]; ok {
+				continue
+			}
+		case []interface{}:
+			if k.index >= 0 && len(v) > k.index {
+				value = v[k.index]
+				continue
+			}
+		}
+		return nil, false
+	}
+	return value, true
+}
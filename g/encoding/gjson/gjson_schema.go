@@ -0,0 +1,453 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gf/g/util/gconv"
+)
+
+// SchemaError is the error of JSON schema validation.
+type SchemaError struct {
+	Pattern string // Pattern of the invalid value, which is empty for the root value.
+	Reason  string // Reason of the failure, eg: "is required", "should be an integer".
+}
+
+// schemaValidator validates values against the schema document <root>,
+// which is used to resolve local references "$ref".
+type schemaValidator struct {
+	root    interface{}
+	regexps map[string]*regexp.Regexp
+}
+
+const (
+	// Max depth of resolving references, which avoids infinite reference loop.
+	gSCHEMA_MAX_REF_DEPTH = 32
+)
+
+// Error implements the interface of error.
+func (e *SchemaError) Error() string {
+	if e.Pattern == "" {
+		return "value " + e.Reason
+	}
+	return fmt.Sprintf(`"%s" %s`, e.Pattern, e.Reason)
+}
+
+// ValidateSchema validates the data of current Json object against JSON schema <schema>.
+// See ValidateSchema.
+func (j *Json) ValidateSchema(schema *Json) error {
+	return ValidateSchema(j.Value(), schema)
+}
+
+// ValidateSchema validates <value> against JSON schema <schema>.
+// It returns a *SchemaError describing the first failure, or nil if the validation passes.
+//
+// It implements a useful subset of JSON schema:
+// type, enum, const, required, properties, additionalProperties, items,
+// minItems, maxItems, uniqueItems, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// multipleOf, minLength, maxLength, pattern, allOf, anyOf, not and local reference "$ref".
+// The "nullable" keyword of OpenAPI is also supported.
+func ValidateSchema(value interface{}, schema *Json) error {
+	var root interface{}
+	if schema != nil {
+		root = schema.Value()
+	}
+	v := &schemaValidator{
+		root:    root,
+		regexps: make(map[string]*regexp.Regexp),
+	}
+	return v.validate("", root, value)
+}
+
+// validate validates <value> of <pattern> against <schema>.
+func (v *schemaValidator) validate(pattern string, schema interface{}, value interface{}) error {
+	s, err := v.resolve(schema)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+	if value == nil {
+		types := gconv.Strings(s["type"])
+		if s["type"] == nil || gconv.Bool(s["nullable"]) || v.inStrings("null", types) {
+			return nil
+		}
+		return v.error(pattern, "should not be null")
+	}
+	// Type checking.
+	if types := gconv.Strings(s["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if v.isType(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return v.error(pattern, "should be "+v.typeNames(types))
+		}
+	}
+	// Enumeration.
+	if enum, ok := s["enum"]; ok {
+		matched := false
+		for _, item := range gconv.Interfaces(enum) {
+			if v.equal(item, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return v.error(pattern, fmt.Sprintf("should be one of %v", enum))
+		}
+	}
+	if c, ok := s["const"]; ok && !v.equal(c, value) {
+		return v.error(pattern, fmt.Sprintf("should be %v", c))
+	}
+	// Composition.
+	for _, item := range gconv.Interfaces(s["allOf"]) {
+		if err := v.validate(pattern, item, value); err != nil {
+			return err
+		}
+	}
+	if anyOf := gconv.Interfaces(s["anyOf"]); len(anyOf) > 0 {
+		var firstErr error
+		for _, item := range anyOf {
+			if err := v.validate(pattern, item, value); err == nil {
+				firstErr = nil
+				break
+			} else if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if firstErr != nil {
+			return firstErr
+		}
+	}
+	if not, ok := s["not"]; ok {
+		if err := v.validate(pattern, not, value); err == nil {
+			return v.error(pattern, "should not match the schema in \"not\"")
+		}
+	}
+	// Keywords by value type.
+	if m, ok := v.toMap(value); ok {
+		return v.validateObject(pattern, s, m)
+	}
+	if array, ok := v.toArray(value); ok {
+		return v.validateArray(pattern, s, array)
+	}
+	if str, ok := value.(string); ok {
+		return v.validateString(pattern, s, str)
+	}
+	if n := v.toNumber(value); n != nil {
+		return v.validateNumber(pattern, s, n)
+	}
+	return nil
+}
+
+// validateObject validates object <m> against keywords of object type in <s>.
+func (v *schemaValidator) validateObject(pattern string, s map[string]interface{}, m map[string]interface{}) error {
+	for _, key := range gconv.Strings(s["required"]) {
+		if _, ok := m[key]; !ok {
+			return v.error(v.join(pattern, key), "is required")
+		}
+	}
+	properties := gconv.Map(s["properties"])
+	for key, property := range properties {
+		if item, ok := m[key]; ok {
+			if err := v.validate(v.join(pattern, key), property, item); err != nil {
+				return err
+			}
+		}
+	}
+	additional, ok := s["additionalProperties"]
+	if !ok {
+		return nil
+	}
+	for key, item := range m {
+		if _, ok := properties[key]; ok {
+			continue
+		}
+		if b, ok := additional.(bool); ok {
+			if !b {
+				return v.error(v.join(pattern, key), "is not allowed")
+			}
+			continue
+		}
+		if err := v.validate(v.join(pattern, key), additional, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateArray validates <array> against keywords of array type in <s>.
+func (v *schemaValidator) validateArray(pattern string, s map[string]interface{}, array []interface{}) error {
+	if min, ok := s["minItems"]; ok && len(array) < gconv.Int(min) {
+		return v.error(pattern, fmt.Sprintf("should have at least %v items", min))
+	}
+	if max, ok := s["maxItems"]; ok && len(array) > gconv.Int(max) {
+		return v.error(pattern, fmt.Sprintf("should have at most %v items", max))
+	}
+	if gconv.Bool(s["uniqueItems"]) {
+		for i := 0; i < len(array); i++ {
+			for k := i + 1; k < len(array); k++ {
+				if v.equal(array[i], array[k]) {
+					return v.error(pattern, "should have unique items")
+				}
+			}
+		}
+	}
+	if items, ok := s["items"]; ok {
+		for i, item := range array {
+			if err := v.validate(v.join(pattern, strconv.Itoa(i)), items, item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateString validates <str> against keywords of string type in <s>.
+func (v *schemaValidator) validateString(pattern string, s map[string]interface{}, str string) error {
+	length := utf8.RuneCountInString(str)
+	if min, ok := s["minLength"]; ok && length < gconv.Int(min) {
+		return v.error(pattern, fmt.Sprintf("should have at least %v characters", min))
+	}
+	if max, ok := s["maxLength"]; ok && length > gconv.Int(max) {
+		return v.error(pattern, fmt.Sprintf("should have at most %v characters", max))
+	}
+	if p, ok := s["pattern"]; ok {
+		expr := gconv.String(p)
+		regex, ok := v.regexps[expr]
+		if !ok {
+			var err error
+			if regex, err = regexp.Compile(expr); err != nil {
+				return v.error(pattern, fmt.Sprintf("has invalid pattern in schema: %v", err))
+			}
+			v.regexps[expr] = regex
+		}
+		if !regex.MatchString(str) {
+			return v.error(pattern, fmt.Sprintf("should match pattern %s", expr))
+		}
+	}
+	return nil
+}
+
+// validateNumber validates number <n> against keywords of number type in <s>.
+// The exclusiveMinimum/exclusiveMaximum can be either number(draft 6+) or boolean(draft 4 and OpenAPI 3.0).
+func (v *schemaValidator) validateNumber(pattern string, s map[string]interface{}, n *big.Rat) error {
+	if min := v.toNumber(s["minimum"]); min != nil {
+		exclusive, _ := s["exclusiveMinimum"].(bool)
+		if cmp := n.Cmp(min); cmp < 0 || (exclusive && cmp == 0) {
+			if exclusive {
+				return v.error(pattern, fmt.Sprintf("should be greater than %v", s["minimum"]))
+			}
+			return v.error(pattern, fmt.Sprintf("should be greater than or equal to %v", s["minimum"]))
+		}
+	}
+	if max := v.toNumber(s["maximum"]); max != nil {
+		exclusive, _ := s["exclusiveMaximum"].(bool)
+		if cmp := n.Cmp(max); cmp > 0 || (exclusive && cmp == 0) {
+			if exclusive {
+				return v.error(pattern, fmt.Sprintf("should be less than %v", s["maximum"]))
+			}
+			return v.error(pattern, fmt.Sprintf("should be less than or equal to %v", s["maximum"]))
+		}
+	}
+	if min := v.toNumber(s["exclusiveMinimum"]); min != nil && n.Cmp(min) <= 0 {
+		return v.error(pattern, fmt.Sprintf("should be greater than %v", s["exclusiveMinimum"]))
+	}
+	if max := v.toNumber(s["exclusiveMaximum"]); max != nil && n.Cmp(max) >= 0 {
+		return v.error(pattern, fmt.Sprintf("should be less than %v", s["exclusiveMaximum"]))
+	}
+	if m := v.toNumber(s["multipleOf"]); m != nil && m.Sign() > 0 {
+		if !new(big.Rat).Quo(n, m).IsInt() {
+			return v.error(pattern, fmt.Sprintf("should be multiple of %v", s["multipleOf"]))
+		}
+	}
+	return nil
+}
+
+// resolve converts <schema> to map and resolves its local reference "$ref", eg: #/definitions/User.
+func (v *schemaValidator) resolve(schema interface{}) (map[string]interface{}, error) {
+	for i := 0; ; i++ {
+		if i > gSCHEMA_MAX_REF_DEPTH {
+			return nil, &SchemaError{Reason: "schema reference is too deep"}
+		}
+		if b, ok := schema.(bool); ok {
+			// Boolean schema, true means any value, false means no value.
+			if b {
+				return nil, nil
+			}
+			return map[string]interface{}{"not": map[string]interface{}{}}, nil
+		}
+		s, ok := schema.(map[string]interface{})
+		if !ok {
+			s = gconv.Map(schema)
+		}
+		ref, ok := s["$ref"]
+		if !ok {
+			return s, nil
+		}
+		if schema, ok = v.pointer(gconv.String(ref)); !ok {
+			return nil, &SchemaError{Reason: fmt.Sprintf(`schema reference "%v" cannot be resolved`, ref)}
+		}
+	}
+}
+
+// pointer returns the value of JSON pointer <ref> in the schema document.
+func (v *schemaValidator) pointer(ref string) (interface{}, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	value := v.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref[1:], "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch node := value.(type) {
+		case map[string]interface{}:
+			item, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			value = item
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// isType checks whether <value> is type of JSON schema type <t>.
+func (v *schemaValidator) isType(t string, value interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := v.toMap(value)
+		return ok
+	case "array":
+		_, ok := v.toArray(value)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		n := v.toNumber(value)
+		return n != nil && n.IsInt()
+	case "number":
+		return v.toNumber(value) != nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+// typeNames returns readable names of JSON schema types <types>, eg: an integer, a string or null.
+func (v *schemaValidator) typeNames(types []string) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "object", "array", "integer":
+			names[i] = "an " + t
+		case "null":
+			names[i] = t
+		default:
+			names[i] = "a " + t
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+// equal checks whether <a> and <b> are equal JSON values.
+// Numbers are compared by their values, eg: 1 equals to 1.0.
+func (v *schemaValidator) equal(a, b interface{}) bool {
+	if x, y := v.toNumber(a), v.toNumber(b); x != nil && y != nil {
+		return x.Cmp(y) == 0
+	}
+	x, err1 := json.Marshal(a)
+	y, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(x, y)
+}
+
+// toNumber converts number <value> to *big.Rat, it returns nil if <value> is not a number.
+func (v *schemaValidator) toNumber(value interface{}) *big.Rat {
+	switch value.(type) {
+	case json.Number, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, *big.Int, *big.Float, *big.Rat:
+		return toBigRat(value)
+	}
+	return nil
+}
+
+// toMap converts object <value> to map, it returns false if <value> is not an object.
+func (v *schemaValidator) toMap(value interface{}) (map[string]interface{}, bool) {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m, true
+	}
+	if reflect.ValueOf(value).Kind() == reflect.Map {
+		return gconv.Map(value), true
+	}
+	return nil, false
+}
+
+// toArray converts array <value> to slice, it returns false if <value> is not an array.
+func (v *schemaValidator) toArray(value interface{}) ([]interface{}, bool) {
+	if array, ok := value.([]interface{}); ok {
+		return array, true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array:
+		if _, ok := value.([]byte); ok {
+			return nil, false
+		}
+		return gconv.Interfaces(value), true
+	}
+	return nil, false
+}
+
+// inStrings checks whether <s> is in <array>.
+func (v *schemaValidator) inStrings(s string, array []string) bool {
+	for _, item := range array {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// join joins the <parent> pattern and <key>.
+func (v *schemaValidator) join(parent string, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// error creates and returns a *SchemaError.
+func (v *schemaValidator) error(pattern string, reason string) error {
+	return &SchemaError{Pattern: pattern, Reason: reason}
+}
@@ -8,6 +8,7 @@ package gjson_test
 
 import (
 	"github.com/gogf/gf/g/encoding/gjson"
+	"io/ioutil"
 	"testing"
 )
 
@@ -27,3 +28,50 @@ func Benchmark_Set2(b *testing.B) {
 		p.Set("0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0", []int{1, 2, 3})
 	}
 }
+
+func Benchmark_ToJson(b *testing.B) {
+	p := gjson.New(map[string]interface{}{
+		"k1": "v1",
+		"k2": []int{1, 2, 3},
+	})
+	for i := 0; i < b.N; i++ {
+		p.ToJson()
+	}
+}
+
+func Benchmark_EncodeTo(b *testing.B) {
+	p := gjson.New(map[string]interface{}{
+		"k1": "v1",
+		"k2": []int{1, 2, 3},
+	})
+	for i := 0; i < b.N; i++ {
+		p.EncodeTo(ioutil.Discard)
+	}
+}
+
+func Benchmark_Get(b *testing.B) {
+	p := gjson.New(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{
+				"c": []int{1, 2, 3},
+			},
+		},
+	})
+	for i := 0; i < b.N; i++ {
+		p.Get("a.b.c.1")
+	}
+}
+
+func Benchmark_Precompile_Get(b *testing.B) {
+	p := gjson.New(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{
+				"c": []int{1, 2, 3},
+			},
+		},
+	})
+	pattern := gjson.Precompile("a.b.c.1")
+	for i := 0; i < b.N; i++ {
+		pattern.Get(p)
+	}
+}
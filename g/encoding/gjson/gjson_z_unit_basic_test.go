@@ -7,6 +7,8 @@
 package gjson_test
 
 import (
+	"bytes"
+
 	"github.com/gogf/gf/g"
 	"github.com/gogf/gf/g/encoding/gjson"
 	"github.com/gogf/gf/g/test/gtest"
@@ -264,6 +266,29 @@ func TestJson_ToJson(t *testing.T) {
 	})
 }
 
+func TestJson_EncodeTo(t *testing.T) {
+	gtest.Case(t, func() {
+		p := gjson.New(g.Map{"a": "<b>", "c": g.Slice{1, 2}})
+		buffer := bytes.NewBuffer(nil)
+		gtest.Assert(p.EncodeTo(buffer), nil)
+		gtest.Assert(buffer.String(), `{"a":"\u003cb\u003e","c":[1,2]}`)
+
+		b, err := p.ToJsonIndent()
+		gtest.Assert(err, nil)
+		buffer.Reset()
+		gtest.Assert(p.EncodeTo(buffer, true), nil)
+		gtest.Assert(buffer.String(), string(b))
+		gtest.Assert(buffer.String(), "{\n\t\"a\": \"\\u003cb\\u003e\",\n\t\"c\": [\n\t\t1,\n\t\t2\n\t]\n}")
+	})
+	gtest.Case(t, func() {
+		p, err := gjson.LoadContentOrdered(`{"b":1,"a":2}`)
+		gtest.Assert(err, nil)
+		buffer := bytes.NewBuffer(nil)
+		gtest.Assert(p.EncodeTo(buffer), nil)
+		gtest.Assert(buffer.String(), `{"b":1,"a":2}`)
+	})
+}
+
 func TestJson_Default(t *testing.T) {
 	gtest.Case(t, func() {
 		j := gjson.New(nil)
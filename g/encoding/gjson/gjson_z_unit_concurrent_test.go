@@ -0,0 +1,69 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/gogf/gf/g/encoding/gjson"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_CopyOnWrite(t *testing.T) {
+	gtest.Case(t, func() {
+		j := gjson.New(`{"a":{"b":[1,2,3]},"c":"d"}`)
+		m := j.GetMap("a")
+		a := j.GetArray("a.b")
+		gtest.Assert(j.Set("a.b.0", 100), nil)
+		gtest.Assert(j.Set("a.e", "f"), nil)
+		gtest.Assert(j.Remove("c"), nil)
+		gtest.Assert(j.GetInt("a.b.0"), 100)
+		gtest.Assert(j.Get("a.e"), "f")
+		gtest.Assert(j.Contains("c"), false)
+		// Values retrieved before writing are not affected.
+		gtest.Assert(a[0], 1)
+		gtest.Assert(len(m), 1)
+	})
+	gtest.Case(t, func() {
+		j := gjson.NewUnsafe(`{"a":{"b":[1,2,3]}}`)
+		a := j.GetArray("a.b")
+		gtest.Assert(j.Set("a.b.0", 100), nil)
+		gtest.Assert(a[0], 100)
+	})
+}
+
+func Test_Concurrent(t *testing.T) {
+	gtest.Case(t, func() {
+		j := gjson.New(`{"list":[0],"map":{},"config":{"name":"john"}}`)
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				for n := 0; n < 100; n++ {
+					j.Set("config.name", "john")
+					j.Set("list.0", n)
+					j.Set(fmt.Sprintf("map.%d_%d", i, n), n)
+				}
+			}(i)
+			go func() {
+				defer wg.Done()
+				for n := 0; n < 100; n++ {
+					gtest.Assert(j.GetString("config.name"), "john")
+					gtest.AssertNE(j.Get("list.0"), nil)
+					j.Len("map")
+					j.ToJson()
+				}
+			}()
+		}
+		wg.Wait()
+		gtest.Assert(j.Len("map"), 1000)
+		gtest.Assert(j.Get("list.0"), 99)
+	})
+}
@@ -195,3 +195,77 @@ func Test_Load_Basic(t *testing.T) {
 
 	})
 }
+
+func Test_Load_JSON5(t *testing.T) {
+	data := `
+// comment
+{
+	name: 'john', /* block comment */
+	"age": 18,
+	score: +1.5e2,
+	tags: ["a", 'b\'s', "c//d",],
+	$ref: {enabled: true, value: null,},
+}`
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentType(data, "json5")
+		gtest.Assert(err, nil)
+		gtest.Assert(j.GetString("name"), "john")
+		gtest.Assert(j.GetInt("age"), 18)
+		gtest.Assert(j.GetFloat64("score"), 150)
+		gtest.Assert(j.GetStrings("tags"), []string{"a", "b's", "c//d"})
+		gtest.Assert(j.GetBool("$ref.enabled"), true)
+		gtest.Assert(j.Get("$ref.value"), nil)
+	})
+	gtest.Case(t, func() {
+		path := "test.json5"
+		gfile.PutContents(path, data)
+		defer gfile.Remove(path)
+		j, err := gjson.Load(path)
+		gtest.Assert(err, nil)
+		gtest.Assert(j.GetString("tags.1"), "b's")
+	})
+	gtest.Case(t, func() {
+		_, err := gjson.LoadContentType(`{name: 'john}`, "json5")
+		gtest.AssertNE(err, nil)
+		_, err = gjson.LoadContentType(`{name: john}`, "json5")
+		gtest.AssertNE(err, nil)
+		_, err = gjson.LoadContentType(`{name: 1 /* comment}`, "json5")
+		gtest.AssertNE(err, nil)
+		_, err = gjson.LoadContentType(`{value: 0xZZ}`, "json5")
+		gtest.AssertNE(err, nil)
+	})
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentType(`{hex: 0x1F, neg: -0XfF, big: 0xFFFFFFFFFFFFFFFF, a: .5, b: 5., c: -.25, d: +5.e2, e: 1.5}`, "json5")
+		gtest.Assert(err, nil)
+		gtest.Assert(j.GetInt("hex"), 31)
+		gtest.Assert(j.GetInt("neg"), -255)
+		gtest.Assert(j.GetString("big"), "18446744073709551615")
+		gtest.Assert(j.GetFloat64("a"), 0.5)
+		gtest.Assert(j.GetInt("b"), 5)
+		gtest.Assert(j.GetFloat64("c"), -0.25)
+		gtest.Assert(j.GetInt("d"), 500)
+		gtest.Assert(j.GetFloat64("e"), 1.5)
+	})
+}
+
+func Test_Load_XMLAttr(t *testing.T) {
+	data := `<rss version="2.0"><channel><title>gf</title><item id="1"><title lang="en">first</title></item></channel></rss>`
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentType(data, "xml-attr")
+		gtest.Assert(err, nil)
+		gtest.Assert(j.Get("rss.@version"), "2.0")
+		gtest.Assert(j.Get("rss.channel.title"), "gf")
+		gtest.Assert(j.Get("rss.channel.item.@id"), "1")
+		gtest.Assert(j.Get("rss.channel.item.title.#text"), "first")
+		gtest.Assert(j.Get("rss.channel.item.title.@lang"), "en")
+
+		gtest.Assert(j.Set("rss.channel.item.@id", "2"), nil)
+		b, err := j.ToXml()
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), `<rss version="2.0"><channel><item id="2"><title lang="en">first</title></item><title>gf</title></channel></rss>`)
+
+		s, err := j.GetJson("rss.channel").ToXmlString("channel")
+		gtest.Assert(err, nil)
+		gtest.Assert(s, `<channel><item id="2"><title lang="en">first</title></item><title>gf</title></channel>`)
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gogf/gf/g/encoding/gjson"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_BigNumber(t *testing.T) {
+	data := `{"id":123456789012345678901234567890,"uid":9007199254740993,"price":19.99,"rate":1e-20,"name":"john"}`
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContent(data)
+		gtest.Assert(err, nil)
+		gtest.Assert(j.GetBigInt("id").String(), "123456789012345678901234567890")
+		gtest.Assert(j.GetBigInt("uid").String(), "9007199254740993")
+		gtest.Assert(j.GetInt64("uid"), int64(9007199254740993))
+		gtest.Assert(j.GetDecimal("price").FloatString(2), "19.99")
+		gtest.Assert(j.GetDecimal("rate").FloatString(20), "0.00000000000000000001")
+		gtest.Assert(j.GetBigInt("price"), nil)
+		gtest.Assert(j.GetBigInt("name"), nil)
+		gtest.Assert(j.GetDecimal("name"), nil)
+		gtest.Assert(j.GetDecimal("none"), nil)
+		gtest.Assert(j.GetBigInt("none", "100").String(), "100")
+
+		b, err := j.ToJson()
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), `{"id":123456789012345678901234567890,"name":"john","price":19.99,"rate":1e-20,"uid":9007199254740993}`)
+	})
+	gtest.Case(t, func() {
+		j := gjson.New(nil)
+		id, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+		j.Set("id", id)
+		j.Set("price", big.NewRat(1999, 100))
+		j.Set("third", big.NewRat(1, 3))
+		j.Set("float", big.NewFloat(0.5))
+		j.Set("uid", uint64(18446744073709551615))
+		b, err := j.ToJson()
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), `{"float":0.5,"id":123456789012345678901234567890,"price":19.99,"third":0.3333333333333333,"uid":18446744073709551615}`)
+		gtest.Assert(j.GetBigInt("id"), id)
+		gtest.Assert(j.GetDecimal("price"), big.NewRat(1999, 100))
+		gtest.Assert(j.GetUint64("uid"), uint64(18446744073709551615))
+	})
+}
+
+func Test_LoadContentPrecise(t *testing.T) {
+	data := `
+id: 123456789012345678901234567890
+price: 0.10000000000000000555
+count: 10
+hex: 0x1F
+name: john
+items:
+  - 9007199254740993
+  - nil
+`
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContent(data)
+		gtest.Assert(err, nil)
+		gtest.AssertNE(j.GetDecimal("price").FloatString(20), "0.10000000000000000555")
+
+		j, err = gjson.LoadContentPrecise(data)
+		gtest.Assert(err, nil)
+		gtest.Assert(j.GetBigInt("id").String(), "123456789012345678901234567890")
+		gtest.Assert(j.GetDecimal("price").FloatString(20), "0.10000000000000000555")
+		gtest.Assert(j.GetInt("count"), 10)
+		gtest.Assert(j.GetInt("hex"), 31)
+		gtest.Assert(j.GetString("name"), "john")
+		gtest.Assert(j.GetBigInt("items.0").String(), "9007199254740993")
+		b, err := j.ToJson()
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), `{"count":10,"hex":31,"id":123456789012345678901234567890,"items":[9007199254740993,"nil"],"name":"john","price":0.10000000000000000555}`)
+	})
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentPrecise(`{"id":123456789012345678901234567890}`)
+		gtest.Assert(err, nil)
+		gtest.Assert(j.GetBigInt("id").String(), "123456789012345678901234567890")
+	})
+}
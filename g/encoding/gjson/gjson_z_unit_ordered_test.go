@@ -0,0 +1,89 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson_test
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/encoding/gjson"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Ordered_Load(t *testing.T) {
+	data := `{"z":1,"b":{"y":"1","x":[{"k2":1,"k1":2}]},"a":null}`
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentOrdered(data)
+		gtest.Assert(err, nil)
+		s, err := j.ToJsonString()
+		gtest.Assert(err, nil)
+		gtest.Assert(s, data)
+
+		gtest.Assert(orderedString(j.GetJson("b")), `{"y":"1","x":[{"k2":1,"k1":2}]}`)
+	})
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentOrdered(`{"b":1,"a":2}`)
+		gtest.Assert(err, nil)
+		b, err := j.ToJsonIndent()
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), "{\n\t\"b\": 1,\n\t\"a\": 2\n}")
+	})
+}
+
+func Test_Ordered_Set(t *testing.T) {
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentOrdered(`{"z":1,"b":2}`)
+		gtest.Assert(err, nil)
+		j.Set("c.y", 1)
+		j.Set("c.x", 2)
+		j.Set("a", 3)
+		j.Remove("z")
+		gtest.Assert(orderedString(j), `{"b":2,"c":{"y":1,"x":2},"a":3}`)
+		j.Set("z", 4)
+		gtest.Assert(orderedString(j), `{"b":2,"c":{"y":1,"x":2},"a":3,"z":4}`)
+	})
+	gtest.Case(t, func() {
+		j := gjson.New(nil)
+		j.SetKeepOrder(true)
+		j.Set("name", "john")
+		j.Set("age", 18)
+		gtest.Assert(orderedString(j), `{"name":"john","age":18}`)
+		j.SetKeepOrder(false)
+		gtest.Assert(orderedString(j), `{"age":18,"name":"john"}`)
+	})
+}
+
+func Test_Ordered_Remove(t *testing.T) {
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentOrdered(`{"b":{"z":1,"y":2},"a":1}`)
+		gtest.Assert(err, nil)
+		j.Remove("b.z")
+		gtest.Assert(orderedString(j), `{"b":{"y":2},"a":1}`)
+		j.Remove("b")
+		gtest.Assert(orderedString(j), `{"a":1}`)
+		j.Set("b.x", 1)
+		j.Set("b.z", 2)
+		gtest.Assert(orderedString(j), `{"a":1,"b":{"x":1,"z":2}}`)
+		j.Set("b", g.Map{"y": 1, "x": 2})
+		gtest.Assert(orderedString(j), `{"a":1,"b":{"x":2,"y":1}}`)
+	})
+	gtest.Case(t, func() {
+		j, err := gjson.LoadContentOrdered(`{"list":[{"b":1,"a":2},{"d":1,"c":2},{"f":1,"e":2}]}`)
+		gtest.Assert(err, nil)
+		j.Remove("list.0")
+		gtest.Assert(orderedString(j), `{"list":[{"d":1,"c":2},{"f":1,"e":2}]}`)
+		j.Remove("list.0")
+		gtest.Assert(orderedString(j), `{"list":[{"f":1,"e":2}]}`)
+		j.Set("list.0.b", 3)
+		gtest.Assert(orderedString(j), `{"list":[{"b":3}]}`)
+	})
+}
+
+func orderedString(j *gjson.Json) string {
+	s, _ := j.ToJsonString()
+	return s
+}
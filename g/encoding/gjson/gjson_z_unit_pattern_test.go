@@ -0,0 +1,64 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/g/encoding/gjson"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Pattern_Precompile(t *testing.T) {
+	gtest.Case(t, func() {
+		j, err := gjson.DecodeToJson(`{"a":{"b":[1,{"c":"v"}]},"d.e":1}`)
+		gtest.Assert(err, nil)
+
+		p := gjson.Precompile("a.b.1.c")
+		gtest.Assert(p.String(), "a.b.1.c")
+		gtest.Assert(p.Get(j), "v")
+		gtest.Assert(p.GetVar(j).String(), "v")
+		gtest.Assert(p.Contains(j), true)
+		gtest.Assert(gjson.Precompile("a.b.0").GetVar(j).Int(), 1)
+		gtest.Assert(gjson.Precompile("a.b.2").Get(j), nil)
+		gtest.Assert(gjson.Precompile("a.b.x").Get(j, "def"), "def")
+		gtest.Assert(gjson.Precompile("a.b.1.c.d").Contains(j), false)
+		gtest.Assert(gjson.Precompile("").Get(j), j.Value())
+		gtest.Assert(gjson.Precompile("a/b/0", '/').Get(j), nil)
+		j.SetSplitChar('/')
+		gtest.Assert(gjson.Precompile("a/b/0", '/').Get(j), 1)
+		j.SetSplitChar('.')
+
+		// The precompiled pattern reads the latest data.
+		j.Set("a.b.1.c", "v2")
+		gtest.Assert(p.Get(j), "v2")
+
+		// It falls back to Json.Get in violence check mode.
+		j.SetViolenceCheck(true)
+		gtest.Assert(gjson.Precompile("d.e").Get(j), 1)
+		j.SetViolenceCheck(false)
+		gtest.Assert(gjson.Precompile("d.e").Get(j), nil)
+	})
+}
+
+func Test_Pattern_Cache(t *testing.T) {
+	gtest.Case(t, func() {
+		j := gjson.New(nil)
+		// Patterns more than the cache size.
+		for i := 0; i < 2000; i++ {
+			j.Set(fmt.Sprintf("k.%d", i), i)
+		}
+		for n := 0; n < 2; n++ {
+			for i := 0; i < 2000; i++ {
+				gtest.Assert(j.GetInt(fmt.Sprintf("k.%d", i)), i)
+			}
+		}
+		gtest.Assert(j.Get("k.2000"), nil)
+		gtest.Assert(j.GetInt("k"), 0)
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson_test
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/encoding/gjson"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+var schemaContent = `
+{
+	"type": "object",
+	"required": ["id", "name"],
+	"additionalProperties": false,
+	"properties": {
+		"id":     {"type": "integer", "minimum": 1},
+		"name":   {"type": "string", "minLength": 2, "maxLength": 8, "pattern": "^[a-z]+$"},
+		"score":  {"type": "number", "exclusiveMaximum": 100, "multipleOf": 0.5},
+		"role":   {"enum": ["admin", "user"]},
+		"remark": {"type": ["string", "null"]},
+		"tags":   {"type": "array", "items": {"type": "string"}, "minItems": 1, "uniqueItems": true},
+		"friends": {"type": "array", "items": {"$ref": "#/definitions/friend"}}
+	},
+	"definitions": {
+		"friend": {
+			"type": "object",
+			"required": ["id"],
+			"properties": {"id": {"type": "integer"}}
+		}
+	}
+}`
+
+func Test_ValidateSchema(t *testing.T) {
+	gtest.Case(t, func() {
+		schema, err := gjson.LoadContent(schemaContent)
+		gtest.Assert(err, nil)
+		check := func(data string) string {
+			j, err := gjson.LoadContent(data)
+			gtest.Assert(err, nil)
+			if err := j.ValidateSchema(schema); err != nil {
+				return err.Error()
+			}
+			return ""
+		}
+		gtest.Assert(check(`{"id":1,"name":"john"}`), "")
+		gtest.Assert(check(`{"id":1,"name":"john","score":99.5,"role":"admin","remark":null,"tags":["a","b"],"friends":[{"id":2}]}`), "")
+		gtest.Assert(check(`{"name":"john"}`), `"id" is required`)
+		gtest.Assert(check(`{"id":1.5,"name":"john"}`), `"id" should be an integer`)
+		gtest.Assert(check(`{"id":0,"name":"john"}`), `"id" should be greater than or equal to 1`)
+		gtest.Assert(check(`{"id":1,"name":"j"}`), `"name" should have at least 2 characters`)
+		gtest.Assert(check(`{"id":1,"name":"johnsmith"}`), `"name" should have at most 8 characters`)
+		gtest.Assert(check(`{"id":1,"name":"John"}`), `"name" should match pattern ^[a-z]+$`)
+		gtest.Assert(check(`{"id":1,"name":"john","score":100}`), `"score" should be less than 100`)
+		gtest.Assert(check(`{"id":1,"name":"john","score":1.2}`), `"score" should be multiple of 0.5`)
+		gtest.Assert(check(`{"id":1,"name":"john","role":"guest"}`), `"role" should be one of [admin user]`)
+		gtest.Assert(check(`{"id":1,"name":"john","remark":1}`), `"remark" should be a string or null`)
+		gtest.Assert(check(`{"id":1,"name":"john","tags":[]}`), `"tags" should have at least 1 items`)
+		gtest.Assert(check(`{"id":1,"name":"john","tags":["a","a"]}`), `"tags" should have unique items`)
+		gtest.Assert(check(`{"id":1,"name":"john","tags":["a",1]}`), `"tags.1" should be a string`)
+		gtest.Assert(check(`{"id":1,"name":"john","friends":[{"id":2},{}]}`), `"friends.1.id" is required`)
+		gtest.Assert(check(`{"id":1,"name":"john","age":18}`), `"age" is not allowed`)
+		gtest.Assert(check(`[]`), `value should be an object`)
+	})
+	gtest.Case(t, func() {
+		schema := gjson.New(g.Map{"type": "integer", "maximum": 10})
+		gtest.Assert(gjson.ValidateSchema(10, schema), nil)
+		gtest.Assert(gjson.ValidateSchema(11, schema).Error(), `value should be less than or equal to 10`)
+		gtest.Assert(gjson.ValidateSchema("10", schema).Error(), `value should be an integer`)
+		gtest.Assert(gjson.ValidateSchema(nil, schema).Error(), `value should not be null`)
+		gtest.Assert(gjson.ValidateSchema(1, gjson.New(g.Map{"$ref": "#/none"})).Error(), `value schema reference "#/none" cannot be resolved`)
+	})
+}
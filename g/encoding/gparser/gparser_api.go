@@ -7,6 +7,7 @@
 package gparser
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/gf/g/container/gvar"
@@ -113,6 +114,18 @@ func (p *Parser) GetFloat64(pattern string, def ...interface{}) float64 {
 	return p.json.GetFloat64(pattern, def...)
 }
 
+// GetBigInt gets the value by specified <pattern>,
+// and converts it to *big.Int without precision loss.
+func (p *Parser) GetBigInt(pattern string, def ...interface{}) *big.Int {
+	return p.json.GetBigInt(pattern, def...)
+}
+
+// GetDecimal gets the value by specified <pattern>,
+// and converts it to *big.Rat without precision loss.
+func (p *Parser) GetDecimal(pattern string, def ...interface{}) *big.Rat {
+	return p.json.GetDecimal(pattern, def...)
+}
+
 func (p *Parser) GetFloats(pattern string, def ...interface{}) []float64 {
 	return p.json.GetFloats(pattern, def...)
 }
@@ -200,3 +213,12 @@ func (p *Parser) ToStruct(pointer interface{}) error {
 func (p *Parser) Dump() error {
 	return p.json.Dump()
 }
+
+// ValidateSchema validates current Parser object against JSON schema <schema>.
+// See gjson.ValidateSchema.
+func (p *Parser) ValidateSchema(schema *Parser) error {
+	if schema == nil {
+		return p.json.ValidateSchema(nil)
+	}
+	return p.json.ValidateSchema(schema.json)
+}
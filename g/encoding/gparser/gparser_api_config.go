@@ -15,3 +15,14 @@ func (p *Parser) SetSplitChar(char byte) {
 func (p *Parser) SetViolenceCheck(check bool) {
 	p.json.SetViolenceCheck(check)
 }
+
+// SetKeepOrder enables/disables ordered mode for current Parser object.
+func (p *Parser) SetKeepOrder(enabled bool) {
+	p.json.SetKeepOrder(enabled)
+}
+
+// SetXmlAttr enables/disables XML attribute mode for current Parser object.
+// See gjson.Json.SetXmlAttr.
+func (p *Parser) SetXmlAttr(enabled bool) {
+	p.json.SetXmlAttr(enabled)
+}
@@ -47,3 +47,53 @@ func LoadContent(data interface{}, unsafe ...bool) (*Parser, error) {
 		return nil, e
 	}
 }
+
+// LoadContentType creates a Parser object from given content of specified <dataType>,
+// which can be: json, json5, xml, xml-attr, yml/yaml, toml.
+func LoadContentType(data interface{}, dataType string, unsafe ...bool) (*Parser, error) {
+	if j, e := gjson.LoadContentType(data, dataType, unsafe...); e == nil {
+		return &Parser{j}, nil
+	} else {
+		return nil, e
+	}
+}
+
+// LoadOrdered loads content from specified file <path>,
+// and creates a Parser object in ordered mode from its content.
+func LoadOrdered(path string, unsafe ...bool) (*Parser, error) {
+	if j, e := gjson.LoadOrdered(path, unsafe...); e == nil {
+		return &Parser{j}, nil
+	} else {
+		return nil, e
+	}
+}
+
+// LoadContentOrdered creates a Parser object in ordered mode from given content,
+// which keeps the key order of objects in the original document.
+func LoadContentOrdered(data interface{}, unsafe ...bool) (*Parser, error) {
+	if j, e := gjson.LoadContentOrdered(data, unsafe...); e == nil {
+		return &Parser{j}, nil
+	} else {
+		return nil, e
+	}
+}
+
+// LoadPrecise loads content from specified file <path>,
+// and creates a Parser object keeping the precision of numbers from its content.
+func LoadPrecise(path string, unsafe ...bool) (*Parser, error) {
+	if j, e := gjson.LoadPrecise(path, unsafe...); e == nil {
+		return &Parser{j}, nil
+	} else {
+		return nil, e
+	}
+}
+
+// LoadContentPrecise creates a Parser object from given content,
+// which decodes the numbers of YAML content as json.Number keeping their original text.
+func LoadContentPrecise(data interface{}, unsafe ...bool) (*Parser, error) {
+	if j, e := gjson.LoadContentPrecise(data, unsafe...); e == nil {
+		return &Parser{j}, nil
+	} else {
+		return nil, e
+	}
+}
@@ -187,3 +187,12 @@ func Test_Load_Nil(t *testing.T) {
 		gtest.AssertNE(err, nil)
 	})
 }
+
+func Test_Load_Precise(t *testing.T) {
+	gtest.Case(t, func() {
+		p, err := gparser.LoadContentPrecise("id: 123456789012345678901234567890\nprice: 0.10000000000000000555\n")
+		gtest.Assert(err, nil)
+		gtest.Assert(p.GetBigInt("id").String(), "123456789012345678901234567890")
+		gtest.Assert(p.GetDecimal("price").FloatString(20), "0.10000000000000000555")
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gurl
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Builder is a fluent URL builder, eg:
+//
+// gurl.NewBuilder("https://api.example.com/v1").Path("users", "1").Query("page", 1).String()
+//
+// The errors occurring in the building are retained and returned by Build.
+// Note that the builder is not concurrent-safe.
+type Builder struct {
+	url   *url.URL
+	query url.Values
+	err   error
+}
+
+// NewBuilder creates and returns a URL builder based on URL <base>,
+// the query parameters of <base> are retained.
+func NewBuilder(base ...string) *Builder {
+	b := &Builder{
+		url:   &url.URL{},
+		query: url.Values{},
+	}
+	if len(base) > 0 && base[0] != "" {
+		if u, err := url.Parse(base[0]); err != nil {
+			b.err = err
+		} else {
+			b.url = u
+			b.query = u.Query()
+		}
+	}
+	return b
+}
+
+// Scheme sets the scheme of the URL, eg: http, https.
+func (b *Builder) Scheme(scheme string) *Builder {
+	b.url.Scheme = scheme
+	return b
+}
+
+// Host sets the host of the URL, which can be with port, eg: 127.0.0.1:8080.
+func (b *Builder) Host(host string) *Builder {
+	b.url.Host = host
+	return b
+}
+
+// User sets the user name and password of the URL.
+func (b *Builder) User(username string, password ...string) *Builder {
+	if len(password) > 0 {
+		b.url.User = url.UserPassword(username, password[0])
+	} else {
+		b.url.User = url.User(username)
+	}
+	return b
+}
+
+// Path joins path <segments> to the path of the URL with '/'.
+// Each segment is escaped, so it should not contain the '/' separator unless it is desired to be escaped.
+func (b *Builder) Path(segments ...string) *Builder {
+	path := strings.TrimRight(b.url.EscapedPath(), "/")
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
+	}
+	return b.RawPath(path)
+}
+
+// RawPath sets the escaped path of the URL, which replaces the existing path.
+func (b *Builder) RawPath(path string) *Builder {
+	if path != "" && path[0] != '/' {
+		path = "/" + path
+	}
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.url.Path = unescaped
+	b.url.RawPath = path
+	return b
+}
+
+// Query adds query parameter <key> with <value>.
+// The <value> of slice/array type is added as multiple values of the same key.
+func (b *Builder) Query(key string, value interface{}) *Builder {
+	values, err := EncodeQuery(map[string]interface{}{key: value})
+	if err != nil {
+		b.err = err
+		return b
+	}
+	for _, v := range values[key] {
+		b.query.Add(key, v)
+	}
+	return b
+}
+
+// SetQuery sets query parameter <key> with <value>, which replaces the existing values of <key>.
+func (b *Builder) SetQuery(key string, value interface{}) *Builder {
+	b.query.Del(key)
+	return b.Query(key, value)
+}
+
+// DelQuery deletes query parameter <key>.
+func (b *Builder) DelQuery(key string) *Builder {
+	b.query.Del(key)
+	return b
+}
+
+// Queries adds query parameters from <params>, which can be url.Values, a map or a struct,
+// the struct attributes are encoded honoring their "url" tags, see EncodeQuery.
+func (b *Builder) Queries(params interface{}) *Builder {
+	values, err := EncodeQuery(params)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	for key, array := range values {
+		for _, v := range array {
+			b.query.Add(key, v)
+		}
+	}
+	return b
+}
+
+// Fragment sets the fragment of the URL, without the leading '#'.
+func (b *Builder) Fragment(fragment string) *Builder {
+	b.url.Fragment = fragment
+	return b
+}
+
+// Build builds and returns the URL string, it returns error if any error occurs in the building.
+// The query parameters are sorted by key.
+func (b *Builder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	return b.URL().String(), nil
+}
+
+// URL returns a copy of the built url.URL.
+func (b *Builder) URL() *url.URL {
+	u := *b.url
+	if b.url.User != nil {
+		user := *b.url.User
+		u.User = &user
+	}
+	u.RawQuery = b.query.Encode()
+	return &u
+}
+
+// String returns the built URL string, it returns empty string if any error occurs in the building.
+func (b *Builder) String() string {
+	s, _ := b.Build()
+	return s
+}
@@ -0,0 +1,278 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gurl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gf/g/util/gconv"
+)
+
+var (
+	// Reflect type of time.Time, which is encoded in RFC3339 format.
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// EncodeQuery encodes <params> to url.Values.
+// The <params> can be url.Values, a map or a struct(pointer).
+// For struct, the query name of each attribute is specified by its "url" tag,
+// eg: `url:"name,omitempty"`, the attribute name is used if no tag given,
+// the attribute with tag "-" is ignored, and the attribute with option "omitempty" is ignored if it is empty.
+// The embedded struct attributes are encoded as the attributes of the outer struct.
+// The slice/array attributes are encoded as multiple values with the same name,
+// and time.Time attributes are encoded in RFC3339 format.
+func EncodeQuery(params interface{}) (url.Values, error) {
+	values := url.Values{}
+	switch v := params.(type) {
+	case nil:
+		return values, nil
+	case url.Values:
+		for key, array := range v {
+			values[key] = append([]string(nil), array...)
+		}
+		return values, nil
+	case map[string]string:
+		for key, value := range v {
+			values.Set(key, value)
+		}
+		return values, nil
+	}
+	rv := reflect.ValueOf(params)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return values, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		for _, key := range rv.MapKeys() {
+			addQueryValue(values, gconv.String(key.Interface()), rv.MapIndex(key))
+		}
+	case reflect.Struct:
+		encodeQueryStruct(values, rv)
+	default:
+		return nil, fmt.Errorf(`invalid query params type: %s`, rv.Type().String())
+	}
+	return values, nil
+}
+
+// ParseQueryToStruct parses the query string <query> and binds the values to struct <pointer>.
+// The <query> can be with or without the leading '?'.
+// The query name of each attribute is specified by its "url" tag like EncodeQuery,
+// or else the attribute name is matched case-insensitively ignoring the '-'/'_' characters.
+// The slice attributes are bound with all the values of the same name.
+func ParseQueryToStruct(query string, pointer interface{}) error {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return err
+	}
+	return ValuesToStruct(values, pointer)
+}
+
+// ValuesToStruct binds the url.Values <values> to struct <pointer>, see ParseQueryToStruct.
+func ValuesToStruct(values url.Values, pointer interface{}) error {
+	rv := reflect.ValueOf(pointer)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("object pointer should be a non-nil pointer to struct")
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf(`object pointer should be a pointer to struct, but given: %s`, rv.Type().String())
+	}
+	// Mapping from the simplified name to the query name, for fuzzy matching.
+	names := make(map[string]string, len(values))
+	for name := range values {
+		names[simplifyQueryName(name)] = name
+	}
+	return decodeQueryStruct(values, names, rv)
+}
+
+// encodeQueryStruct encodes the attributes of struct <rv> to <values>.
+func encodeQueryStruct(values url.Values, rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, omitEmpty, ok := getQueryFieldName(field)
+		if !ok {
+			continue
+		}
+		value := rv.Field(i)
+		if field.Anonymous && field.Tag.Get("url") == "" {
+			for value.Kind() == reflect.Ptr && !value.IsNil() {
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct && value.Type() != timeType {
+				encodeQueryStruct(values, value)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if omitEmpty && value.IsZero() {
+			continue
+		}
+		addQueryValue(values, name, value)
+	}
+}
+
+// addQueryValue adds the query value <rv> of <name> to <values>.
+func addQueryValue(values url.Values, name string, rv reflect.Value) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		// []byte is treated as string.
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			values.Add(name, gconv.String(rv.Interface()))
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			addQueryValue(values, name, rv.Index(i))
+		}
+	default:
+		if rv.Type() == timeType {
+			values.Add(name, rv.Interface().(time.Time).Format(time.RFC3339))
+			return
+		}
+		values.Add(name, gconv.String(rv.Interface()))
+	}
+}
+
+// decodeQueryStruct binds <values> to the attributes of struct <rv>.
+func decodeQueryStruct(values url.Values, names map[string]string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, _, ok := getQueryFieldName(field)
+		if !ok {
+			continue
+		}
+		value := rv.Field(i)
+		if field.Anonymous && field.Tag.Get("url") == "" {
+			if value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct {
+				if value.IsNil() {
+					if !value.CanSet() {
+						continue
+					}
+					value.Set(reflect.New(value.Type().Elem()))
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct && value.Type() != timeType {
+				if err := decodeQueryStruct(values, names, value); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		array, ok := values[name]
+		if !ok && field.Tag.Get("url") == "" {
+			array, ok = values[names[simplifyQueryName(name)]]
+		}
+		if !ok || len(array) == 0 {
+			continue
+		}
+		if err := setQueryValue(value, array); err != nil {
+			return fmt.Errorf(`binding query "%s" to attribute "%s" failed: %v`, name, field.Name, err)
+		}
+	}
+	return nil
+}
+
+// setQueryValue sets the query values <array> to <rv>.
+func setQueryValue(rv reflect.Value, array []string) error {
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return setQueryValue(rv.Elem(), array)
+
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			rv.SetBytes([]byte(array[0]))
+			return nil
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(array), len(array))
+		for i, s := range array {
+			if err := setQueryValue(slice.Index(i), []string{s}); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+	}
+	s := array[0]
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Bool:
+		rv.SetBool(gconv.Bool(s))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Type() == reflect.TypeOf(time.Duration(0)) {
+			rv.SetInt(int64(gconv.Duration(s)))
+		} else {
+			rv.SetInt(gconv.Int64(s))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		rv.SetUint(gconv.Uint64(s))
+	case reflect.Float32, reflect.Float64:
+		rv.SetFloat(gconv.Float64(s))
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(s))
+	default:
+		if rv.Type() == timeType {
+			rv.Set(reflect.ValueOf(gconv.Time(s)))
+			return nil
+		}
+		return fmt.Errorf(`unsupported type "%s"`, rv.Type().String())
+	}
+	return nil
+}
+
+// getQueryFieldName returns the query name and the "omitempty" option of struct attribute <field>,
+// it returns false if the attribute should be ignored.
+func getQueryFieldName(field reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	// Only public attributes, but embedded struct attributes are always checked.
+	if field.PkgPath != "" && !field.Anonymous {
+		return "", false, false
+	}
+	tag := field.Tag.Get("url")
+	if tag == "-" {
+		return "", false, false
+	}
+	array := strings.Split(tag, ",")
+	name = strings.TrimSpace(array[0])
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range array[1:] {
+		if strings.TrimSpace(option) == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, true
+}
+
+// simplifyQueryName simplifies query name <name> for fuzzy matching.
+func simplifyQueryName(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+}
@@ -0,0 +1,299 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gxml
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gf/g/util/gconv"
+)
+
+const (
+	// 属性键名前缀
+	ATTR_PREFIX = "@"
+	// 同时具有属性/子节点及文本内容的节点，其文本内容的键名
+	TEXT_KEY = "#text"
+	// 未指定根节点名称且map包含多个键值时使用的根节点名称
+	gDEFAULT_ROOT_TAG = "doc"
+)
+
+// 节点的子节点顺序，键名为节点路径，键值为子节点名称在文档中首次出现的顺序。
+// 节点路径由各级节点名称及其在同名兄弟节点中的索引使用"/"连接组成，
+// 例如: <a><b/><b><c/><d/></b></a> 中第二个b节点的路径为"a/0/b/1"。
+type Order map[string][]string
+
+// 将XML内容解析为map变量，并保留节点属性及命名空间前缀。
+// 节点属性使用"@"前缀的键名保存，例如: <a id="1"> 解析为 {"a": {"@id": "1"}}；
+// 节点名称及属性名称保留原有的命名空间前缀，例如: soap:Envelope, @xmlns:soap；
+// 同时具有属性/子节点及文本内容的节点，文本内容使用"#text"键名保存。
+// 解析结果可通过EncodeWithAttr还原为XML内容，编码时子节点按照名称排序。
+func DecodeWithAttr(content []byte) (map[string]interface{}, error) {
+	return decodeWithAttr(content, nil)
+}
+
+// 同DecodeWithAttr，同时返回各节点的子节点在文档中的顺序，子节点顺序不会写入返回的map变量中，
+// 可通过EncodeWithAttrOrder按照原有顺序还原为XML内容。
+// 注意同名子节点合并为数组，因此与其他子节点交错出现的同名子节点编码时将会相邻。
+func DecodeWithAttrOrder(content []byte) (map[string]interface{}, Order, error) {
+	order := make(Order)
+	m, err := decodeWithAttr(content, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, order, nil
+}
+
+// 解析XML内容，<order>不为nil时记录各节点的子节点顺序。
+func decodeWithAttr(content []byte, order Order) (map[string]interface{}, error) {
+	res, err := convert(content)
+	if err != nil {
+		return nil, err
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(res))
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("no root element found in xml content")
+			}
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			value, err := decodeElementWithAttr(decoder, start, orderPath("", xmlName(start.Name), 0), order)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{xmlName(start.Name): value}, nil
+		}
+	}
+}
+
+// 将使用DecodeWithAttr解析的map变量编码为XML格式内容，"@"前缀的键名编码为节点属性。
+func EncodeWithAttr(v map[string]interface{}, rootTag ...string) ([]byte, error) {
+	return encodeWithAttr(v, nil, "", rootTag...)
+}
+
+// 将使用DecodeWithAttr解析的map变量编码为带缩进的XML格式内容。
+func EncodeWithAttrIndent(v map[string]interface{}, rootTag ...string) ([]byte, error) {
+	return encodeWithAttr(v, nil, "\t", rootTag...)
+}
+
+// 将使用DecodeWithAttrOrder解析的map变量按照子节点顺序<order>编码为XML格式内容，
+// 不在<order>中的子节点(例如解析后新增的子节点)按照名称排序后排在最后。
+func EncodeWithAttrOrder(v map[string]interface{}, order Order, rootTag ...string) ([]byte, error) {
+	return encodeWithAttr(v, order, "", rootTag...)
+}
+
+// XML格式内容直接转换为JSON格式内容，并保留节点属性及命名空间前缀。
+func ToJsonWithAttr(content []byte) ([]byte, error) {
+	m, err := DecodeWithAttr(content)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+// 解析节点<start>的内容(包括属性、子节点及文本内容)，直到节点结束。
+// <path>为节点路径，<order>不为nil时记录子节点顺序。
+func decodeElementWithAttr(decoder *xml.Decoder, start xml.StartElement, path string, order Order) (interface{}, error) {
+	node := make(map[string]interface{})
+	names := make([]string, 0)
+	for _, attr := range start.Attr {
+		node[ATTR_PREFIX+xmlName(attr.Name)] = attr.Value
+	}
+	text := bytes.NewBuffer(nil)
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("unexpected EOF, element <%s> is not closed", xmlName(start.Name))
+			}
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			// 同名子节点的索引
+			name, index := xmlName(t.Name), 0
+			if v, ok := node[name]; ok {
+				index = 1
+				if array, ok := v.([]interface{}); ok {
+					index = len(array)
+				}
+			}
+			value, err := decodeElementWithAttr(decoder, t, orderPath(path, name, index), order)
+			if err != nil {
+				return nil, err
+			}
+			// 同名子节点转换为数组
+			if v, ok := node[name]; ok {
+				if array, ok := v.([]interface{}); ok {
+					node[name] = append(array, value)
+				} else {
+					node[name] = []interface{}{v, value}
+				}
+			} else {
+				node[name] = value
+				names = append(names, name)
+			}
+
+		case xml.CharData:
+			text.Write(t)
+
+		case xml.EndElement:
+			if xmlName(t.Name) != xmlName(start.Name) {
+				return nil, fmt.Errorf("element <%s> closed by </%s>", xmlName(start.Name), xmlName(t.Name))
+			}
+			s := strings.TrimSpace(text.String())
+			if len(node) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				node[TEXT_KEY] = s
+			}
+			if order != nil && len(names) > 1 {
+				order[path] = names
+			}
+			return node, nil
+		}
+	}
+}
+
+// 返回带命名空间前缀的名称
+func xmlName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+// 返回父节点路径为<parent>，名称为<name>，同名兄弟节点索引为<index>的节点路径。
+func orderPath(parent string, name string, index int) string {
+	path := name + "/" + strconv.Itoa(index)
+	if parent != "" {
+		path = parent + "/" + path
+	}
+	return path
+}
+
+// 将map变量编码为XML格式内容，<order>为子节点顺序，<indent>为空时不进行缩进。
+func encodeWithAttr(v map[string]interface{}, order Order, indent string, rootTag ...string) ([]byte, error) {
+	e := &attrEncoder{
+		buffer: bytes.NewBuffer(nil),
+		order:  order,
+		indent: indent,
+	}
+	if len(rootTag) > 0 && rootTag[0] != "" {
+		e.encodeElement("", rootTag[0], v, 0)
+	} else if len(v) == 1 {
+		for name, value := range v {
+			e.encodeElement("", name, value, 0)
+		}
+	} else {
+		e.encodeElement("", gDEFAULT_ROOT_TAG, v, 0)
+	}
+	return e.buffer.Bytes(), nil
+}
+
+// 带属性的XML编码器
+type attrEncoder struct {
+	buffer *bytes.Buffer // 编码结果
+	order  Order         // 子节点顺序，为nil时子节点按照名称排序
+	indent string        // 缩进字符串，为空时不进行缩进
+}
+
+// 将父节点路径为<parent>，名称为<name>的节点编码写入缓冲区，<depth>为节点层级。
+func (e *attrEncoder) encodeElement(parent string, name string, value interface{}, depth int) {
+	if array, ok := value.([]interface{}); ok {
+		for i, item := range array {
+			e.encodeItem(orderPath(parent, name, i), name, item, depth)
+		}
+		return
+	}
+	e.encodeItem(orderPath(parent, name, 0), name, value, depth)
+}
+
+// 将路径为<path>，名称为<name>的单个节点编码写入缓冲区，<depth>为节点层级。
+func (e *attrEncoder) encodeItem(path string, name string, value interface{}, depth int) {
+	buffer, indent := e.buffer, e.indent
+	if indent != "" && depth > 0 {
+		buffer.WriteString("\n" + strings.Repeat(indent, depth))
+	}
+	buffer.WriteString("<" + name)
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteString("/>")
+
+	case map[string]interface{}:
+		text := ""
+		children := make([]string, 0, len(v))
+		attrs := make([]string, 0)
+		for k := range v {
+			switch {
+			case k == TEXT_KEY:
+				text = gconv.String(v[k])
+			case strings.HasPrefix(k, ATTR_PREFIX):
+				attrs = append(attrs, k)
+			default:
+				children = append(children, k)
+			}
+		}
+		sort.Strings(attrs)
+		children = sortChildren(children, e.order[path])
+		for _, k := range attrs {
+			buffer.WriteString(" " + k[len(ATTR_PREFIX):] + `="`)
+			xml.EscapeText(buffer, []byte(gconv.String(v[k])))
+			buffer.WriteByte('"')
+		}
+		if text == "" && len(children) == 0 {
+			buffer.WriteString("/>")
+			return
+		}
+		buffer.WriteByte('>')
+		xml.EscapeText(buffer, []byte(text))
+		for _, k := range children {
+			e.encodeElement(path, k, v[k], depth+1)
+		}
+		if indent != "" && len(children) > 0 {
+			buffer.WriteString("\n" + strings.Repeat(indent, depth))
+		}
+		buffer.WriteString("</" + name + ">")
+
+	default:
+		buffer.WriteByte('>')
+		xml.EscapeText(buffer, []byte(gconv.String(v)))
+		buffer.WriteString("</" + name + ">")
+	}
+}
+
+// 按照<order>的顺序排列子节点名称<children>，未在<order>中的子节点按照名称排序后排在最后。
+func sortChildren(children []string, order []string) []string {
+	sort.Strings(children)
+	if len(order) == 0 {
+		return children
+	}
+	index := make(map[string]int, len(order))
+	for i, name := range order {
+		index[name] = i
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		a, aok := index[children[i]]
+		b, bok := index[children[j]]
+		if aok && bok {
+			return a < b
+		}
+		return aok && !bok
+	})
+	return children
+}
@@ -179,3 +179,91 @@ func TestErrCase(t *testing.T) {
 		}
 	})
 }
+
+func Test_DecodeWithAttr(t *testing.T) {
+	gtest.Case(t, func() {
+		xml := `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+	<soap:Body>
+		<m:GetPrice xmlns:m="https://www.w3schools.com/prices">
+			<m:Item id="1" lang="en">Apples</m:Item>
+			<m:Item id="2">Pears</m:Item>
+			<m:Empty/>
+		</m:GetPrice>
+	</soap:Body>
+</soap:Envelope>`
+		m, err := gxml.DecodeWithAttr([]byte(xml))
+		gtest.Assert(err, nil)
+		envelope := m["soap:Envelope"].(map[string]interface{})
+		gtest.Assert(envelope["@xmlns:soap"], "http://schemas.xmlsoap.org/soap/envelope/")
+		price := envelope["soap:Body"].(map[string]interface{})["m:GetPrice"].(map[string]interface{})
+		gtest.Assert(price["@xmlns:m"], "https://www.w3schools.com/prices")
+		gtest.Assert(price["m:Empty"], "")
+		items := price["m:Item"].([]interface{})
+		gtest.Assert(len(items), 2)
+		gtest.Assert(items[0], map[string]interface{}{"@id": "1", "@lang": "en", "#text": "Apples"})
+		gtest.Assert(items[1], map[string]interface{}{"@id": "2", "#text": "Pears"})
+
+		b, err := gxml.EncodeWithAttr(m)
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">`+
+			`<soap:Body><m:GetPrice xmlns:m="https://www.w3schools.com/prices"><m:Empty></m:Empty>`+
+			`<m:Item id="1" lang="en">Apples</m:Item><m:Item id="2">Pears</m:Item>`+
+			`</m:GetPrice></soap:Body></soap:Envelope>`)
+
+		// Round trip.
+		m2, err := gxml.DecodeWithAttr(b)
+		gtest.Assert(err, nil)
+		gtest.Assert(m2, m)
+	})
+
+	gtest.Case(t, func() {
+		b, err := gxml.EncodeWithAttrIndent(map[string]interface{}{
+			"urlset": map[string]interface{}{
+				"@xmlns": "http://www.sitemaps.org/schemas/sitemap/0.9",
+				"url":    map[string]interface{}{"loc": "https://goframe.org/?a=1&b=2"},
+			},
+		})
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), "<urlset xmlns=\"http://www.sitemaps.org/schemas/sitemap/0.9\">\n\t<url>\n\t\t<loc>https://goframe.org/?a=1&amp;b=2</loc>\n\t</url>\n</urlset>")
+	})
+
+	// Document order of siblings.
+	gtest.Case(t, func() {
+		xml := `<books><book id="1"><title>gf</title><author>john</author><chapter>1</chapter><chapter>2</chapter><abstract>text</abstract></book>` +
+			`<book id="2"><title>go</title><date>2019</date><author>rob</author></book></books>`
+		m, order, err := gxml.DecodeWithAttrOrder([]byte(xml))
+		gtest.Assert(err, nil)
+		gtest.Assert(order, gxml.Order{
+			"books/0/book/0": {"title", "author", "chapter", "abstract"},
+			"books/0/book/1": {"title", "date", "author"},
+		})
+		// The order is not kept in the map.
+		book := m["books"].(map[string]interface{})["book"].([]interface{})[0].(map[string]interface{})
+		gtest.Assert(len(book), 5)
+		b, err := gxml.EncodeWithAttrOrder(m, order)
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), xml)
+
+		// The children not in order are appended in name order.
+		book["isbn"] = "123"
+		book["cover"] = "cover.png"
+		b, err = gxml.EncodeWithAttrOrder(m, order)
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), `<books><book id="1"><title>gf</title><author>john</author><chapter>1</chapter><chapter>2</chapter><abstract>text</abstract>`+
+			`<cover>cover.png</cover><isbn>123</isbn></book><book id="2"><title>go</title><date>2019</date><author>rob</author></book></books>`)
+
+		// Without order the children are encoded in name order.
+		b, err = gxml.EncodeWithAttr(m)
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), `<books><book id="1"><abstract>text</abstract><author>john</author><chapter>1</chapter><chapter>2</chapter>`+
+			`<cover>cover.png</cover><isbn>123</isbn><title>gf</title></book><book id="2"><author>rob</author><date>2019</date><title>go</title></book></books>`)
+	})
+
+	gtest.Case(t, func() {
+		_, err := gxml.DecodeWithAttr([]byte(`<root><a id="1">text</b></root>`))
+		gtest.AssertNE(err, nil)
+		_, err = gxml.DecodeWithAttr([]byte(`<root><a>text</a>`))
+		gtest.AssertNE(err, nil)
+	})
+}
@@ -7,7 +7,17 @@
 // Package gyaml provides accessing and converting for YAML content.
 package gyaml
 
-import "github.com/gf/third/github.com/ghodss/yaml"
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/gf/third/github.com/ghodss/yaml"
+	yamlv2 "github.com/gf/third/gopkg.in/yaml.v2"
+)
+
+// jsonNumberRegex matches the numbers in JSON format.
+var jsonNumberRegex = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
 
 func Encode(v interface{}) ([]byte, error) {
 	return yaml.Marshal(v)
@@ -28,3 +38,83 @@ func DecodeTo(v []byte, result interface{}) error {
 func ToJson(v []byte) ([]byte, error) {
 	return yaml.YAMLToJSON(v)
 }
+
+// DecodeNumber decodes YAML content like Decode, but the numbers are decoded as json.Number
+// keeping their original text instead of int/float64, so big integers and decimals do not lose precision.
+// The numbers which are not in JSON number format, eg: 0x1F, 1_000, .inf, are decoded as Decode does.
+func DecodeNumber(v []byte) (interface{}, error) {
+	node := new(numberNode)
+	if err := yamlv2.Unmarshal(v, node); err != nil {
+		return nil, err
+	}
+	return node.value, nil
+}
+
+// ToJsonNumber converts YAML content to JSON content like ToJson, keeping the original text of numbers.
+// See DecodeNumber.
+func ToJsonNumber(v []byte) ([]byte, error) {
+	value, err := DecodeNumber(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// numberNode is a YAML node whose number values are decoded as json.Number.
+type numberNode struct {
+	value interface{}
+}
+
+// UnmarshalYAML implements the interface yaml.Unmarshaler for numberNode.
+// It decodes the node as interface{} to check its kind first, then decodes the children of mapping
+// and sequence as numberNode, and the original text of number scalar as json.Number.
+func (n *numberNode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value interface{}
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	switch value.(type) {
+	case map[interface{}]interface{}:
+		nodes := make(map[interface{}]*numberNode)
+		if err := unmarshal(&nodes); err != nil {
+			return err
+		}
+		m := make(map[string]interface{}, len(nodes))
+		for k, node := range nodes {
+			m[fmt.Sprintf("%v", k)] = node.nodeValue()
+		}
+		n.value = m
+
+	case []interface{}:
+		nodes := make([]*numberNode, 0)
+		if err := unmarshal(&nodes); err != nil {
+			return err
+		}
+		array := make([]interface{}, len(nodes))
+		for i, node := range nodes {
+			array[i] = node.nodeValue()
+		}
+		n.value = array
+
+	case int, int64, uint64, float64:
+		// The number scalar is decoded as its original text.
+		text := ""
+		if err := unmarshal(&text); err == nil && jsonNumberRegex.MatchString(text) {
+			n.value = json.Number(text)
+		} else {
+			n.value = value
+		}
+
+	default:
+		n.value = value
+	}
+	return nil
+}
+
+// nodeValue returns the decoded value of the node, which is nil for null node.
+func (n *numberNode) nodeValue() interface{} {
+	if n == nil {
+		return nil
+	}
+	return n.value
+}
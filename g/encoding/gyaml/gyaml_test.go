@@ -6,6 +6,7 @@
 package gyaml_test
 
 import (
+	"encoding/json"
 	"github.com/gogf/gf/g/encoding/gparser"
 	"github.com/gogf/gf/g/encoding/gyaml"
 	"github.com/gogf/gf/g/test/gtest"
@@ -141,3 +142,20 @@ func TestToJson(t *testing.T) {
 		}
 	})
 }
+
+func TestDecodeNumber(t *testing.T) {
+	gtest.Case(t, func() {
+		v, err := gyaml.DecodeNumber([]byte("id: 123456789012345678901234567890\nrate: 1.50\nhex: 0x1F\nempty: ~\nlist: [1, a]\n"))
+		gtest.Assert(err, nil)
+		m := v.(map[string]interface{})
+		gtest.Assert(m["id"], json.Number("123456789012345678901234567890"))
+		gtest.Assert(m["rate"], json.Number("1.50"))
+		gtest.Assert(m["hex"], 31)
+		gtest.Assert(m["empty"], nil)
+		gtest.Assert(m["list"], []interface{}{json.Number("1"), "a"})
+
+		b, err := gyaml.ToJsonNumber([]byte("id: 123456789012345678901234567890\n"))
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), `{"id":123456789012345678901234567890}`)
+	})
+}
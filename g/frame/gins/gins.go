@@ -9,6 +9,7 @@ package gins
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gf/g/container/gmap"
@@ -26,6 +27,7 @@ import (
 const (
 	gFRAME_CORE_COMPONENT_NAME_REDIS    = "gf.core.component.redis"
 	gFRAME_CORE_COMPONENT_NAME_DATABASE = "gf.core.component.database"
+	gFRAME_CORE_COMPONENT_NAME_VIEWER   = "gf.core.component.viewer"
 )
 
 // 单例对象存储器
@@ -63,8 +65,36 @@ func SetIfNotExist(key string, value interface{}) bool {
 
 // View returns an instance of View with default settings.
 // The parameter <name> is the name for the instance.
+// The instance is configured with the "viewer" node of the configuration file if it exists,
+// or the "viewer.<name>" node for named instance, eg:
+//
+//	[viewer]
+//	    delimiters = ["[[", "]]"]
 func View(name ...string) *gview.View {
-	return gview.Instance(name...)
+	group := gview.DEFAULT_INSTANCE_NAME
+	if len(name) > 0 && name[0] != "" {
+		group = name[0]
+	}
+	key := fmt.Sprintf("%s.%s", gFRAME_CORE_COMPONENT_NAME_VIEWER, group)
+	return instances.GetOrSetFuncLock(key, func() interface{} {
+		view := gview.Instance(group)
+		config := Config()
+		// It does not read the configuration if there's no configuration file,
+		// which avoids the error logging of missing file for the applications without configuration.
+		if config.FilePath() == "" && gcfg.GetContent(config.GetFileName()) == "" {
+			return view
+		}
+		node := "viewer"
+		if len(name) > 0 && name[0] != "" {
+			node = "viewer." + name[0]
+		}
+		if m := config.GetMap(node); m != nil {
+			if err := view.SetConfigWithMap(m); err != nil {
+				glog.Error(err)
+			}
+		}
+		return view
+	}).(*gview.View)
 }
 
 // Config returns an instance of View with default settings.
@@ -153,6 +183,24 @@ func Database(name ...string) gdb.DB {
 						if value, ok := nodeMap["maxLifetime"]; ok {
 							node.MaxConnLifetime = gconv.Int(value)
 						}
+						if value, ok := nodeMap["encryptKey"]; ok {
+							node.EncryptKey = gconv.String(value)
+						}
+						if value, ok := nodeMap["idGenerator"]; ok {
+							node.IdGenerator = gconv.String(value)
+						}
+						if value, ok := nodeMap["workerId"]; ok {
+							node.WorkerId = gconv.Int(value)
+						}
+						if value, ok := nodeMap["cancelStatement"]; ok {
+							node.CancelStatement = gconv.Bool(value)
+						}
+						if value, ok := nodeMap["stmtCacheSize"]; ok {
+							node.StmtCacheSize = gconv.Int(value)
+						}
+						if value, ok := nodeMap["timeMaintain"]; ok {
+							node.TimeMaintain = gconv.Bool(value)
+						}
 						cg = append(cg, node)
 					}
 				}
@@ -173,6 +221,19 @@ func Database(name ...string) gdb.DB {
 	return nil
 }
 
+// 获取所有数据库单例对象的连接池统计信息，键名为配置分组名称
+func DatabaseStats() map[string][]gdb.PoolStats {
+	m := make(map[string][]gdb.PoolStats)
+	prefix := gFRAME_CORE_COMPONENT_NAME_DATABASE + "."
+	instances.Iterator(func(key string, v interface{}) bool {
+		if db, ok := v.(gdb.DB); ok && db != nil && strings.HasPrefix(key, prefix) {
+			m[key[len(prefix):]] = db.Stats()
+		}
+		return true
+	})
+	return m
+}
+
 // Redis操作对象，使用了连接池
 func Redis(name ...string) *gredis.Redis {
 	config := Config()
@@ -183,7 +244,7 @@ func Redis(name ...string) *gredis.Redis {
 	key := fmt.Sprintf("%s.%s", gFRAME_CORE_COMPONENT_NAME_REDIS, group)
 	result := instances.GetOrSetFuncLock(key, func() interface{} {
 		if m := config.GetMap("redis"); m != nil {
-			// host:port[,db,pass?maxIdle=x&maxActive=x&idleTimeout=x&maxConnLifetime=x]
+			// host:port[,db,pass?maxIdle=x&maxActive=x&idleTimeout=x&maxConnLifetime=x&minIdle=x&healthCheck=x&sentinels=x&masterName=x&connectTimeout=x&readTimeout=x&writeTimeout=x&autoTune=x&wait=x&slowThreshold=x&replicas=x&replicaPolicy=x]
 			if v, ok := m[group]; ok {
 				line := gconv.String(v)
 				array, _ := gregex.MatchString(`(.+):(\d+),{0,1}(\d*),{0,1}(.*)\?(.+)`, line)
@@ -207,6 +268,43 @@ func Redis(name ...string) *gredis.Redis {
 					if v, ok := parse["maxConnLifetime"]; ok {
 						redisConfig.MaxConnLifetime = gconv.Duration(v) * time.Second
 					}
+					if v, ok := parse["minIdle"]; ok {
+						redisConfig.MinIdle = gconv.Int(v)
+					}
+					if v, ok := parse["healthCheck"]; ok {
+						redisConfig.HealthCheck = gconv.Duration(v) * time.Second
+					}
+					if v, ok := parse["sentinels"]; ok {
+						redisConfig.Sentinels = gconv.String(v)
+					}
+					if v, ok := parse["masterName"]; ok {
+						redisConfig.MasterName = gconv.String(v)
+					}
+					if v, ok := parse["connectTimeout"]; ok {
+						redisConfig.ConnectTimeout = gconv.Duration(v) * time.Second
+					}
+					if v, ok := parse["readTimeout"]; ok {
+						redisConfig.ReadTimeout = gconv.Duration(v) * time.Second
+					}
+					if v, ok := parse["writeTimeout"]; ok {
+						redisConfig.WriteTimeout = gconv.Duration(v) * time.Second
+					}
+					if v, ok := parse["autoTune"]; ok {
+						redisConfig.AutoTune = gconv.Duration(v) * time.Second
+					}
+					if v, ok := parse["wait"]; ok {
+						redisConfig.Wait = gconv.Bool(v)
+					}
+					// The slow threshold is in milliseconds, as the other durations in seconds are too coarse for it.
+					if v, ok := parse["slowThreshold"]; ok {
+						redisConfig.SlowThreshold = gconv.Duration(v) * time.Millisecond
+					}
+					if v, ok := parse["replicas"]; ok {
+						redisConfig.Replicas = gconv.String(v)
+					}
+					if v, ok := parse["replicaPolicy"]; ok {
+						redisConfig.ReplicaPolicy = gconv.String(v)
+					}
 					addConfigMonitor(key, config)
 					return gredis.New(redisConfig)
 				}
@@ -9,6 +9,7 @@ package gins_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gogf/gf/g/frame/gins"
 	"github.com/gogf/gf/g/os/gfile"
@@ -47,3 +48,25 @@ func Test_View(t *testing.T) {
 		gtest.Assert(string(b), "中国人")
 	})
 }
+
+func Test_View_Config(t *testing.T) {
+	config := `
+[viewer]
+    [viewer.vue]
+        delimiters = ["[[", "]]"]
+`
+	path := "config.toml"
+	err := gfile.PutContents(path, config)
+	gtest.Assert(err, nil)
+	defer gfile.Remove(path)
+	defer gins.Config().Clear()
+
+	// for gfsnotify callbacks to refresh cache of config file
+	time.Sleep(500 * time.Millisecond)
+
+	gtest.Case(t, func() {
+		b, e := gins.View("vue").ParseContent(`[[.name]] {{ name }}`, map[string]interface{}{"name": "gf"})
+		gtest.Assert(e, nil)
+		gtest.Assert(b, "gf {{ name }}")
+	})
+}
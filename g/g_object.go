@@ -18,8 +18,14 @@ import (
 )
 
 // Server returns an instance of http server with specified name.
+// The pool statistics of databases are registered as "database" metrics of the server,
+// which are served by the metrics endpoint, see ghttp.Server.EnableMetrics.
 func Server(name ...interface{}) *ghttp.Server {
-	return ghttp.GetServer(name...)
+	s := ghttp.GetServer(name...)
+	s.AddMetricsProvider("database", func() interface{} {
+		return gins.DatabaseStats()
+	})
+	return s
 }
 
 // TCPServer returns an instance of tcp server with specified name.
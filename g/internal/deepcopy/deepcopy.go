@@ -0,0 +1,97 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package deepcopy provides deep copying for the values stored in containers.
+package deepcopy
+
+import (
+	"reflect"
+)
+
+// apiDeepCopy is the interface for containers supporting deep copying.
+type apiDeepCopy interface {
+	DeepCopy() interface{}
+}
+
+// Copy returns a deep copy of <value>.
+// The containers implementing DeepCopy() interface{} are copied by their DeepCopy,
+// and the plain maps, slices and arrays are copied recursively.
+// The other values like pointers, structs and channels are returned as they are.
+func Copy(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	// Common types are asserted first for performance.
+	switch v := value.(type) {
+	case apiDeepCopy:
+		return v.DeepCopy()
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = Copy(item)
+		}
+		return m
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, item := range v {
+			array[i] = Copy(item)
+		}
+		return array
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return copyValue(rv).Interface()
+	}
+	return value
+}
+
+// copyValue returns a deep copy of reflect value <rv>.
+func copyValue(rv reflect.Value) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+		v := reflect.New(rv.Type()).Elem()
+		v.Set(reflect.ValueOf(Copy(rv.Interface())))
+		return v
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+		m := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		for _, key := range rv.MapKeys() {
+			m.SetMapIndex(key, copyValue(rv.MapIndex(key)))
+		}
+		return m
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+		s := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			s.Index(i).Set(copyValue(rv.Index(i)))
+		}
+		return s
+	case reflect.Array:
+		a := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			a.Index(i).Set(copyValue(rv.Index(i)))
+		}
+		return a
+	case reflect.Ptr:
+		// The containers are commonly stored as pointers.
+		if !rv.IsNil() && rv.CanInterface() {
+			if v, ok := rv.Interface().(apiDeepCopy); ok {
+				return reflect.ValueOf(v.DeepCopy())
+			}
+		}
+	}
+	return rv
+}
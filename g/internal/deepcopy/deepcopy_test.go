@@ -0,0 +1,36 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package deepcopy_test
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/g/container/garray"
+	"github.com/gogf/gf/g/internal/deepcopy"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func TestCopy(t *testing.T) {
+	gtest.Case(t, func() {
+		gtest.Assert(deepcopy.Copy(nil), nil)
+		gtest.Assert(deepcopy.Copy(1), 1)
+		gtest.Assert(deepcopy.Copy("gf"), "gf")
+
+		array := garray.NewIntArrayFrom([]int{1, 2})
+		value := map[int][]interface{}{
+			1: {[]string{"a"}, map[string]int{"b": 1}, array, [2]int{1, 2}},
+		}
+		copied := deepcopy.Copy(value).(map[int][]interface{})
+		value[1][0].([]string)[0] = "x"
+		value[1][1].(map[string]int)["b"] = 2
+		array.Append(3)
+		gtest.Assert(copied[1][0], []string{"a"})
+		gtest.Assert(copied[1][1], map[string]int{"b": 1})
+		gtest.Assert(copied[1][2].(*garray.IntArray).Slice(), []int{1, 2})
+		gtest.Assert(copied[1][3].([2]int) == [2]int{1, 2}, true)
+	})
+}
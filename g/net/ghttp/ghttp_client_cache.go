@@ -0,0 +1,231 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+// HTTP客户端响应缓存.
+
+package ghttp
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gf/g/os/gcache"
+)
+
+const (
+	gDEFAULT_CLIENT_CACHE_SIZE     = 1000    // 默认最多缓存的响应数量(LRU)
+	gDEFAULT_CLIENT_CACHE_MAX_BODY = 1 << 20 // 可缓存的响应内容最大长度，超过时不缓存
+)
+
+// 缓存的GET请求响应
+type clientCacheItem struct {
+	status       string            // 响应状态，例如：200 OK
+	statusCode   int               // 响应状态码
+	proto        string            // 响应协议版本
+	header       http.Header       // 响应Header
+	body         []byte            // 响应内容
+	vary         map[string]string // 响应Vary指定的请求Header及请求时的值，值不同的请求不使用该缓存
+	etag         string            // 响应的ETag
+	lastModified string            // 响应的Last-Modified
+	expires      time.Time         // 缓存过期时间，过期之后需要向服务端校验(If-None-Match/If-Modified-Since)
+}
+
+// 设置是否开启GET请求的响应缓存，遵循服务端返回的Cache-Control/Expires/ETag/Last-Modified：
+// 1. 缓存未过期(Cache-Control: max-age或者Expires)时直接返回缓存的响应，不发送请求；
+// 2. 缓存过期时携带If-None-Match/If-Modified-Since请求服务端校验，服务端返回304时返回缓存的响应；
+// 3. Cache-Control为no-store、Vary为*、响应状态码不是200或者响应内容过大时不缓存，Cache-Control为no-cache时每次都需要校验。
+// 常用于减少对慢速上游接口的重复请求。缓存在克隆的客户端对象之间共享，自定义了If-None-Match/If-Modified-Since的请求不使用缓存。
+func (c *Client) SetCache(enabled bool) {
+	if !enabled {
+		c.cache = nil
+	} else if c.cache == nil {
+		c.cache = gcache.New(gDEFAULT_CLIENT_CACHE_SIZE)
+	}
+}
+
+// 链式操作, See SetCache
+func (c *Client) Cache(enabled bool) *Client {
+	c.SetCache(enabled)
+	return c
+}
+
+// 清空响应缓存
+func (c *Client) ClearCache() {
+	if c.cache != nil {
+		c.cache.Clear()
+	}
+}
+
+// 获取请求可使用的缓存，请求不使用缓存时返回的cacheKey为空
+func (c *Client) getCacheItem(req *http.Request, param string) (cacheKey string, item *clientCacheItem) {
+	if c.cache == nil || req.Method != "GET" {
+		return "", nil
+	}
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return "", nil
+	}
+	cacheKey = req.URL.String()
+	if param != "" {
+		cacheKey += "\n" + param
+	}
+	if v := c.cache.Get(cacheKey); v != nil {
+		item = v.(*clientCacheItem)
+		for k, v := range item.vary {
+			if req.Header.Get(k) != v {
+				return cacheKey, nil
+			}
+		}
+	}
+	return cacheKey, item
+}
+
+// 处理服务端返回的响应：304时返回缓存的响应并更新缓存过期时间，200时缓存响应内容
+func (c *Client) handleCacheResponse(cacheKey string, item *clientCacheItem, req *http.Request, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode == http.StatusNotModified && item != nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		newItem := *item
+		newItem.header = cloneHeader(item.header)
+		for _, k := range []string{"Cache-Control", "Expires", "Date", "ETag", "Last-Modified"} {
+			if v := resp.Header.Get(k); v != "" {
+				newItem.header.Set(k, v)
+			}
+		}
+		newItem.etag = newItem.header.Get("ETag")
+		newItem.lastModified = newItem.header.Get("Last-Modified")
+		newItem.expires, _ = getCacheExpires(newItem.header)
+		c.setCacheItem(cacheKey, &newItem)
+		return newItem.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	expires, cacheable := getCacheExpires(resp.Header)
+	if !cacheable || resp.ContentLength > gDEFAULT_CLIENT_CACHE_MAX_BODY {
+		return resp, nil
+	}
+	// 读取响应内容之后重新设置为可读取的内容，内容过大时不缓存并保留未读取的部分
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, gDEFAULT_CLIENT_CACHE_MAX_BODY+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > gDEFAULT_CLIENT_CACHE_MAX_BODY {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	item = &clientCacheItem{
+		status:       resp.Status,
+		statusCode:   resp.StatusCode,
+		proto:        resp.Proto,
+		header:       cloneHeader(resp.Header),
+		body:         body,
+		vary:         make(map[string]string),
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		expires:      expires,
+	}
+	for _, v := range resp.Header["Vary"] {
+		for _, k := range strings.Split(v, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				item.vary[k] = req.Header.Get(k)
+			}
+		}
+	}
+	c.setCacheItem(cacheKey, item)
+	return resp, nil
+}
+
+// 保存缓存，没有校验标识(ETag/Last-Modified)的缓存过期之后无法使用，因此同时过期
+func (c *Client) setCacheItem(cacheKey string, item *clientCacheItem) {
+	if item.etag == "" && item.lastModified == "" {
+		ttl := int(time.Until(item.expires) / time.Millisecond)
+		if ttl <= 0 {
+			c.cache.Remove(cacheKey)
+			return
+		}
+		c.cache.Set(cacheKey, item, ttl)
+		return
+	}
+	c.cache.Set(cacheKey, item, 0)
+}
+
+// 设置请求的校验Header，返回缓存是否未过期(未过期时不需要发送请求)
+func (item *clientCacheItem) prepare(req *http.Request) (fresh bool) {
+	if time.Now().Before(item.expires) {
+		return true
+	}
+	if item.etag != "" {
+		req.Header.Set("If-None-Match", item.etag)
+	}
+	if item.lastModified != "" {
+		req.Header.Set("If-Modified-Since", item.lastModified)
+	}
+	return false
+}
+
+// 根据缓存创建响应对象
+func (item *clientCacheItem) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        item.status,
+		StatusCode:    item.statusCode,
+		Proto:         item.proto,
+		Header:        cloneHeader(item.header),
+		Body:          ioutil.NopCloser(bytes.NewReader(item.body)),
+		ContentLength: int64(len(item.body)),
+		Request:       req,
+	}
+}
+
+// 根据响应Header获取缓存过期时间，返回的cacheable表示该响应是否可以缓存
+func getCacheExpires(header http.Header) (expires time.Time, cacheable bool) {
+	now := time.Now()
+	hasValidator := header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+	if strings.TrimSpace(header.Get("Vary")) == "*" {
+		return now, false
+	}
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store":
+			return now, false
+		case directive == "no-cache":
+			return now, hasValidator
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				expires = now.Add(time.Duration(seconds) * time.Second)
+				return expires, hasValidator || seconds > 0
+			}
+		}
+	}
+	if v := header.Get("Expires"); v != "" {
+		if t, err := http.ParseTime(v); err == nil {
+			return t, hasValidator || t.After(now)
+		}
+		// 无效的Expires表示已过期
+		return now, hasValidator
+	}
+	return now, hasValidator
+}
+
+// 复制Header
+func cloneHeader(header http.Header) http.Header {
+	h := make(http.Header, len(header))
+	for k, v := range header {
+		h[k] = append([]string(nil), v...)
+	}
+	return h
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gf/g/os/gcache"
 	"github.com/gf/g/os/gfile"
 )
 
@@ -35,6 +36,7 @@ type Client struct {
 	browserMode   bool              // 是否模拟浏览器模式(自动保存提交COOKIE)
 	retryCount    int               // 失败重试次数(网络失败情况下)
 	retryInterval int               // 失败重试间隔
+	cache         *gcache.Cache     // GET请求的响应缓存，为nil表示未开启
 }
 
 // http客户端对象指针
@@ -308,6 +310,14 @@ func (c *Client) DoRequest(method, url string, data ...interface{}) (*ClientResp
 			req.Header.Set("Cookie", headerCookie)
 		}
 	}
+	// 响应缓存
+	cacheKey, cacheItem := c.getCacheItem(req, param)
+	if cacheItem != nil && cacheItem.prepare(req) {
+		return &ClientResponse{
+			Response: cacheItem.response(req),
+			cookies:  make(map[string]string),
+		}, nil
+	}
 	// 执行请求
 	resp := (*http.Response)(nil)
 	for {
@@ -322,6 +332,11 @@ func (c *Client) DoRequest(method, url string, data ...interface{}) (*ClientResp
 			break
 		}
 	}
+	if cacheKey != "" {
+		if resp, err = c.handleCacheResponse(cacheKey, cacheItem, req, resp); err != nil {
+			return nil, err
+		}
+	}
 	r := &ClientResponse{
 		cookies: make(map[string]string),
 	}
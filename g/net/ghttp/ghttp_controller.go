@@ -11,3 +11,11 @@ type Controller interface {
 	Init(*Request)
 	Shut()
 }
+
+// 控制器生命周期回调方法，对服务注册的所有控制器生效，回调方法的参数c为当前请求新创建的控制器对象。
+// 控制器可以通过注入回调获取依赖的服务对象(例如数据库、缓存对象)，而不需要使用全局的单例对象。
+type ControllerHooks struct {
+	Inject func(r *Request, c Controller) // 注入回调，在控制器对象创建之后、Init之前调用
+	Init   func(r *Request, c Controller) // 初始化回调，在控制器Init之后、路由方法执行之前调用
+	Shut   func(r *Request, c Controller) // 结束回调，在控制器Shut之后调用，即使请求已退出也会调用，可用于释放请求相关的资源
+}
@@ -7,6 +7,7 @@
 package ghttp
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -14,9 +15,11 @@ import (
 
 	"github.com/gf/g/container/gvar"
 	"github.com/gf/g/encoding/gjson"
+	"github.com/gf/g/os/glog"
 	"github.com/gf/g/os/gtime"
 	"github.com/gf/g/text/gregex"
 	"github.com/gf/third/github.com/fatih/structs"
+	"github.com/gf/third/github.com/gorilla/websocket"
 )
 
 // 请求对象
@@ -39,7 +42,14 @@ type Request struct {
 	parsedHost    string                 // 解析过后不带端口号的服务器域名名称
 	clientIp      string                 // 解析过后的客户端IP地址
 	rawContent    []byte                 // 客户端提交的原始参数
+	bodyTooLarge  bool                   // 请求内容是否超过缓冲大小限制
 	isFileRequest bool                   // 是否为静态文件请求(非服务请求，当静态文件存在时，优先级会被服务请求高，被识别为文件请求)
+	isNotFound    bool                   // 是否未匹配到任何路由、静态文件及静态目录
+	logBuffer     *glog.Buffer           // 请求日志缓冲对象(开启请求日志缓冲时有效)
+	error         error                  // 请求处理错误(通过SetError设置)
+	uploadChecks  uploadChecks           // 上传文件的内容检查结果
+	Middleware    *Middleware            // 中间件流程控制对象
+	websocket     *WebSocket             // 当前请求升级的WebSocket连接
 }
 
 // 创建一个Request对象
@@ -56,20 +66,42 @@ func newRequest(s *Server, r *http.Request, w http.ResponseWriter) *Request {
 	request.Cookie = GetCookie(request)
 	request.Session = GetSession(request)
 	request.Response.request = request
+	request.Middleware = &Middleware{request: request}
+	// 请求日志缓冲
+	if s.config.LogBufferLevel > 0 {
+		request.logBuffer = glog.NewBuffer(s.config.LogBufferLevel)
+	}
 	return request
 }
 
 // 获取Web Socket连接对象(如果是非WS请求会失败，注意检查返回的error结果)
 func (r *Request) WebSocket() (*WebSocket, error) {
+	// 优雅关闭过程中不再接受新的WebSocket连接
+	if r.Server.IsShuttingDown() {
+		r.Response.WriteStatus(http.StatusServiceUnavailable)
+		return nil, errors.New("server is shutting down")
+	}
 	if conn, err := wsUpgrader.Upgrade(r.Response.ResponseWriter.ResponseWriter, r.Request, nil); err == nil {
-		return &WebSocket{
-			conn,
-		}, nil
+		ws := &WebSocket{
+			Conn:         conn,
+			server:       r.Server,
+			request:      r,
+			interceptors: append([]WebSocketInterceptor(nil), r.Server.wsInterceptors...),
+		}
+		// 请求处理方法返回时从连接列表中移除，参考handleRequest
+		r.websocket = ws
+		r.Server.websockets.Add(ws)
+		return ws, nil
 	} else {
 		return nil, err
 	}
 }
 
+// 判断是否为WebSocket连接升级请求，可以用于在中间件中对WebSocket连接进行单独的鉴权处理
+func (r *Request) IsWebSocket() bool {
+	return websocket.IsWebSocketUpgrade(r.Request)
+}
+
 // 获得指定名称的参数字符串(Router/GET/POST)，同 GetRequestString
 // 这是常用方法的简化别名
 func (r *Request) Get(key string, def ...interface{}) string {
@@ -81,16 +113,20 @@ func (r *Request) GetVar(key string, def ...interface{}) *gvar.Var {
 	return r.GetRequestVar(key, def...)
 }
 
-// 获取原始请求输入二进制。
+// 获取原始请求输入二进制，可以重复调用，参考GetBody。
+// 请求内容超过缓冲大小限制时仍然读取完整的请求内容。
 func (r *Request) GetRaw() []byte {
-	err := error(nil)
-	if r.rawContent == nil {
-		r.rawContent, err = ioutil.ReadAll(r.Body)
-		if err != nil {
-			r.Error("error reading request body: ", err)
+	content, err := r.GetBody()
+	if err == ErrBodyTooLarge {
+		if content, err = ioutil.ReadAll(r.Body); err == nil {
+			r.rawContent = content
+			r.resetBody()
 		}
 	}
-	return r.rawContent
+	if err != nil {
+		r.Error("error reading request body: ", err)
+	}
+	return content
 }
 
 // 获取原始请求输入字符串。
@@ -161,6 +197,12 @@ func (r *Request) GetToStruct(pointer interface{}, mapping ...map[string]string)
 	r.GetRequestToStruct(pointer, mapping...)
 }
 
+// 同GetToStruct，将所有的request参数及上传文件映射到struct属性上，
+// 并返回参数映射及上传文件校验(如：max-size、ext规则)的错误。
+func (r *Request) Parse(pointer interface{}, mapping ...map[string]string) error {
+	return r.GetRequestToStruct(pointer, mapping...)
+}
+
 // 仅退出当前逻辑执行函数, 如:服务函数、HOOK函数
 func (r *Request) Exit() {
 	panic(gEXCEPTION_EXIT)
@@ -200,6 +242,11 @@ func (r *Request) IsFileRequest() bool {
 	return r.isFileRequest
 }
 
+// 判断请求是否未匹配到任何路由、静态文件及静态目录(将返回404状态码)，可在中间件中使用
+func (r *Request) IsNotFound() bool {
+	return r.isNotFound
+}
+
 // 判断是否为AJAX请求
 func (r *Request) IsAjaxRequest() bool {
 	return strings.EqualFold(r.Header.Get("X-Requested-With"), "XMLHttpRequest")
@@ -0,0 +1,149 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package ghttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+var (
+	// 请求内容超过缓冲大小限制时GetBody返回的错误
+	ErrBodyTooLarge = errors.New("request body exceeds the buffer size limit")
+)
+
+// 恢复已读取内容的请求内容读取对象，关闭时关闭原有的Body
+type bodyReader struct {
+	io.Reader
+	io.Closer
+}
+
+// 获取请求的原始提交内容，可以重复调用。
+// 请求内容读取后会被缓冲，并且请求的Body会被重置为缓冲内容的读取对象，
+// 因此中间件/HOOK读取请求内容后，后续的处理流程(例如表单参数解析、GetRaw)仍然可以读取完整的请求内容。
+// 请求内容超过缓冲大小限制(ServerConfig.BodyBufferSize)时不会被缓冲，并返回ErrBodyTooLarge，
+// 此时请求的Body保持完整，但是只能被读取一次。
+func (r *Request) GetBody() ([]byte, error) {
+	if r.rawContent == nil {
+		if r.bodyTooLarge {
+			return nil, ErrBodyTooLarge
+		}
+		if r.Body == nil {
+			r.rawContent = []byte{}
+			return r.rawContent, nil
+		}
+		limit := r.Server.config.BodyBufferSize
+		reader := io.Reader(r.Body)
+		if limit > 0 {
+			reader = io.LimitReader(r.Body, limit+1)
+		}
+		content, err := ioutil.ReadAll(reader)
+		if err == nil && limit > 0 && int64(len(content)) > limit {
+			r.bodyTooLarge = true
+			err = ErrBodyTooLarge
+		}
+		if err != nil {
+			// 恢复已读取的内容，保证后续的处理流程可以读取完整的请求内容
+			r.Body = &bodyReader{io.MultiReader(bytes.NewReader(content), r.Body), r.Body}
+			return nil, err
+		}
+		r.rawContent = content
+	}
+	r.resetBody()
+	return r.rawContent, nil
+}
+
+// 将请求的Body重置为缓冲内容的读取对象
+func (r *Request) resetBody() {
+	if r.rawContent != nil {
+		r.Body = ioutil.NopCloser(bytes.NewReader(r.rawContent))
+	}
+}
+
+// 判断是否为multipart表单请求
+func (r *Request) isMultipart() bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "multipart/form-data" || mediaType == "multipart/mixed"
+}
+
+// 创建当前请求对象的副本，副本与当前请求的处理流程分离，可以安全地传递给后台goroutine使用：
+// 1. 副本的上下文不会在请求结束时取消；
+// 2. 副本复制了请求头、请求参数、自定义参数及请求内容(超过缓冲大小限制的请求内容为空)；
+// 3. 副本不包含Response、Cookie及Session对象，也不包含上传文件(上传的临时文件在请求结束时删除)。
+func (r *Request) Clone() *Request {
+	// 解析请求参数，以便复制到副本中
+	r.initGet()
+	r.initPost()
+	httpRequest := r.Request.WithContext(context.Background())
+	httpRequest.Header = http.Header(copyValues(r.Header))
+	httpRequest.Trailer = http.Header(copyValues(r.Trailer))
+	httpRequest.Form = url.Values(copyValues(r.Form))
+	httpRequest.PostForm = url.Values(copyValues(r.PostForm))
+	httpRequest.MultipartForm = nil
+	httpRequest.GetBody = nil
+	if r.URL != nil {
+		u := *r.URL
+		if u.User != nil {
+			user := *u.User
+			u.User = &user
+		}
+		httpRequest.URL = &u
+	}
+	var content []byte
+	if r.rawContent != nil {
+		content = make([]byte, len(r.rawContent))
+		copy(content, r.rawContent)
+		httpRequest.Body = ioutil.NopCloser(bytes.NewReader(content))
+	} else {
+		httpRequest.Body = http.NoBody
+	}
+	request := &Request{
+		Request:       httpRequest,
+		parsedGet:     r.parsedGet,
+		parsedPost:    r.parsedPost,
+		queryVars:     copyValues(r.queryVars),
+		routerVars:    copyValues(r.routerVars),
+		exit:          r.exit,
+		Id:            r.Id,
+		Server:        r.Server,
+		Router:        r.Router,
+		EnterTime:     r.EnterTime,
+		LeaveTime:     r.LeaveTime,
+		parsedHost:    r.parsedHost,
+		clientIp:      r.clientIp,
+		rawContent:    content,
+		bodyTooLarge:  r.bodyTooLarge,
+		isFileRequest: r.isFileRequest,
+		isNotFound:    r.isNotFound,
+		error:         r.error,
+	}
+	if r.params != nil {
+		request.params = make(map[string]interface{}, len(r.params))
+		for k, v := range r.params {
+			request.params[k] = v
+		}
+	}
+	return request
+}
+
+// 复制参数列表
+func copyValues(values map[string][]string) map[string][]string {
+	if values == nil {
+		return nil
+	}
+	m := make(map[string][]string, len(values))
+	for k, v := range values {
+		m[k] = append([]string(nil), v...)
+	}
+	return m
+}
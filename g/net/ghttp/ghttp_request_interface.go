@@ -0,0 +1,107 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+// Handler-facing interfaces of Request/Response.
+
+package ghttp
+
+import (
+	"net/http"
+
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/encoding/gjson"
+)
+
+// HandlerRequest is the handler-facing surface of Request, which decouples business handlers
+// from the HTTP server. Handlers written against it can be bound to the server with WrapHandler,
+// unit-tested with MemoryRequest, or driven by other transports like message queues.
+type HandlerRequest interface {
+	// Get returns the parameter <key> as string, or <def> if it does not exist.
+	Get(key string, def ...interface{}) string
+	// GetVar returns the parameter <key> as *gvar.Var, or <def> if it does not exist.
+	GetVar(key string, def ...interface{}) *gvar.Var
+	// GetString is alias of Get.
+	GetString(key string, def ...interface{}) string
+	// GetInt returns the parameter <key> as int.
+	GetInt(key string, def ...interface{}) int
+	// GetFloat64 returns the parameter <key> as float64.
+	GetFloat64(key string, def ...interface{}) float64
+	// GetMap returns all parameters as map, or <def> if there's no parameter.
+	GetMap(def ...map[string]string) map[string]string
+	// GetToStruct maps the parameters to the struct object <pointer>.
+	GetToStruct(pointer interface{}, mapping ...map[string]string)
+	// Parse maps the parameters to the struct object <pointer>, and returns the error of mapping.
+	Parse(pointer interface{}, mapping ...map[string]string) error
+	// GetRouterString returns the router parameter <key>.
+	GetRouterString(key string) string
+	// GetRaw returns the raw request body.
+	GetRaw() []byte
+	// GetJson parses the raw request body as JSON, which is nil if the body is empty or invalid.
+	GetJson() *gjson.Json
+	// GetHeader returns the value of request header <key>.
+	GetHeader(key string) string
+	// GetClientIp returns the client ip of the request.
+	GetClientIp() string
+	// GetUrl returns the full url of the request.
+	GetUrl() string
+	// SetParam sets custom parameter <key>, which is valid during the request.
+	SetParam(key string, value interface{})
+	// GetParam returns custom parameter <key>, or <def> if it does not exist.
+	GetParam(key string, def ...interface{}) *gvar.Var
+	// Exit stops the execution of the current handler.
+	Exit()
+	// IsExited checks whether the request is exited.
+	IsExited() bool
+	// GetResponse returns the response of the request.
+	GetResponse() HandlerResponse
+}
+
+// HandlerResponse is the handler-facing surface of Response.
+type HandlerResponse interface {
+	// Header returns the response header map.
+	Header() http.Header
+	// WriteHeader sets the response status code.
+	WriteHeader(status int)
+	// Write writes <content> of any type to the response buffer.
+	Write(content ...interface{})
+	// Writef writes formatted content to the response buffer.
+	Writef(format string, params ...interface{})
+	// Writeln writes <content> with a trailing line feed to the response buffer.
+	Writeln(content ...interface{})
+	// WriteJson writes <content> as JSON to the response buffer.
+	WriteJson(content interface{}) error
+	// WriteXml writes <content> as XML to the response buffer.
+	WriteXml(content interface{}, rootTag ...string) error
+	// WriteStatus sets the status code and writes <content> or the status text.
+	WriteStatus(status int, content ...string)
+	// Buffer returns the content of the response buffer.
+	Buffer() []byte
+}
+
+// RequestHandler is the handler function using HandlerRequest.
+type RequestHandler func(r HandlerRequest)
+
+var (
+	// Checks the implementations of the interfaces.
+	_ HandlerRequest  = (*Request)(nil)
+	_ HandlerResponse = (*Response)(nil)
+)
+
+// WrapHandler converts RequestHandler <h> to HandlerFunc, so that it can be bound to the server.
+func WrapHandler(h RequestHandler) HandlerFunc {
+	return func(r *Request) {
+		h(r)
+	}
+}
+
+// GetHeader returns the value of request header <key>.
+func (r *Request) GetHeader(key string) string {
+	return r.Header.Get(key)
+}
+
+// GetResponse returns the response of the request.
+func (r *Request) GetResponse() HandlerResponse {
+	return r.Response
+}
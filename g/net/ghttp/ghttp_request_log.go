@@ -6,9 +6,22 @@
 
 package ghttp
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/gf/g/os/glog"
+)
 
 // 打印error日志
 func (r *Request) Error(value ...interface{}) {
 	r.Server.handleErrorLog(fmt.Sprint(value...), r)
 }
+
+// 获取与当前请求绑定的日志对象，开启请求日志缓冲时(参考Server.SetLogBuffer)，
+// 指定级别的日志将被缓冲，仅当请求失败或者执行时间超过阈值时才会输出。
+func (r *Request) Logger() *glog.Logger {
+	if r.logBuffer != nil {
+		return r.Server.logger.Buffered(r.logBuffer).Stdout(r.Server.config.LogStdout)
+	}
+	return r.Server.logger.Stdout(r.Server.config.LogStdout)
+}
@@ -0,0 +1,254 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+// In-memory implementations of HandlerRequest/HandlerResponse.
+
+package ghttp
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/encoding/gjson"
+	"github.com/gf/g/encoding/gparser"
+	"github.com/gf/g/util/gconv"
+)
+
+// MemoryRequest is an in-memory HandlerRequest without HTTP server,
+// which is used in unit tests of handlers or by transports other than HTTP.
+// All the parameters including router parameters are read from the same parameter map.
+type MemoryRequest struct {
+	Header   http.Header            // Request headers.
+	Body     []byte                 // Raw request body.
+	ClientIp string                 // Client ip.
+	Url      string                 // Request url.
+	Response *MemoryResponse        // Response of the request.
+	params   map[string]interface{} // Request parameters.
+	custom   map[string]interface{} // Custom parameters set by SetParam.
+	exit     bool                   // Whether the request is exited.
+}
+
+// MemoryResponse is an in-memory HandlerResponse, which keeps the status, headers and content in memory.
+type MemoryResponse struct {
+	Status int           // Response status code.
+	header http.Header   // Response headers.
+	buffer *bytes.Buffer // Response content.
+}
+
+// NewMemoryRequest creates and returns a MemoryRequest with parameters <params> and optional raw body.
+func NewMemoryRequest(params map[string]interface{}, body ...[]byte) *MemoryRequest {
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	r := &MemoryRequest{
+		Header:   make(http.Header),
+		Response: NewMemoryResponse(),
+		params:   params,
+		custom:   make(map[string]interface{}),
+	}
+	if len(body) > 0 {
+		r.Body = body[0]
+	}
+	return r
+}
+
+// NewMemoryResponse creates and returns a MemoryResponse.
+func NewMemoryResponse() *MemoryResponse {
+	return &MemoryResponse{
+		header: make(http.Header),
+		buffer: bytes.NewBuffer(nil),
+	}
+}
+
+// Serve calls handler <h> with the request and returns the response.
+// It stops the handler like the server does if Exit is called.
+func (r *MemoryRequest) Serve(h RequestHandler) *MemoryResponse {
+	func() {
+		defer func() {
+			if e := recover(); e != nil && e != gEXCEPTION_EXIT && e != gEXCEPTION_EXIT_ALL {
+				panic(e)
+			}
+		}()
+		h(r)
+	}()
+	return r.Response
+}
+
+func (r *MemoryRequest) Get(key string, def ...interface{}) string {
+	return r.GetVar(key, def...).String()
+}
+
+func (r *MemoryRequest) GetVar(key string, def ...interface{}) *gvar.Var {
+	if v, ok := r.params[key]; ok {
+		return gvar.New(v, true)
+	}
+	if len(def) > 0 {
+		return gvar.New(def[0], true)
+	}
+	return gvar.New(nil, true)
+}
+
+func (r *MemoryRequest) GetString(key string, def ...interface{}) string {
+	return r.Get(key, def...)
+}
+
+func (r *MemoryRequest) GetInt(key string, def ...interface{}) int {
+	return r.GetVar(key, def...).Int()
+}
+
+func (r *MemoryRequest) GetFloat64(key string, def ...interface{}) float64 {
+	return r.GetVar(key, def...).Float64()
+}
+
+func (r *MemoryRequest) GetMap(def ...map[string]string) map[string]string {
+	if len(r.params) == 0 && len(def) > 0 {
+		return def[0]
+	}
+	m := make(map[string]string, len(r.params))
+	for k, v := range r.params {
+		m[k] = gconv.String(v)
+	}
+	return m
+}
+
+func (r *MemoryRequest) GetToStruct(pointer interface{}, mapping ...map[string]string) {
+	r.Parse(pointer, mapping...)
+}
+
+func (r *MemoryRequest) Parse(pointer interface{}, mapping ...map[string]string) error {
+	return gconv.Struct(r.params, pointer, mapping...)
+}
+
+func (r *MemoryRequest) GetRouterString(key string) string {
+	return r.Get(key)
+}
+
+func (r *MemoryRequest) GetRaw() []byte {
+	return r.Body
+}
+
+func (r *MemoryRequest) GetJson() *gjson.Json {
+	if len(r.Body) > 0 {
+		if j, err := gjson.DecodeToJson(r.Body); err == nil {
+			return j
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRequest) GetHeader(key string) string {
+	return r.Header.Get(key)
+}
+
+func (r *MemoryRequest) GetClientIp() string {
+	return r.ClientIp
+}
+
+func (r *MemoryRequest) GetUrl() string {
+	return r.Url
+}
+
+func (r *MemoryRequest) SetParam(key string, value interface{}) {
+	r.custom[key] = value
+}
+
+func (r *MemoryRequest) GetParam(key string, def ...interface{}) *gvar.Var {
+	if v, ok := r.custom[key]; ok {
+		return gvar.New(v, true)
+	}
+	if len(def) > 0 {
+		return gvar.New(def[0], true)
+	}
+	return gvar.New(nil, true)
+}
+
+func (r *MemoryRequest) Exit() {
+	r.exit = true
+	panic(gEXCEPTION_EXIT)
+}
+
+func (r *MemoryRequest) IsExited() bool {
+	return r.exit
+}
+
+func (r *MemoryRequest) GetResponse() HandlerResponse {
+	return r.Response
+}
+
+func (r *MemoryResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *MemoryResponse) WriteHeader(status int) {
+	r.Status = status
+}
+
+func (r *MemoryResponse) Write(content ...interface{}) {
+	for _, v := range content {
+		switch value := v.(type) {
+		case []byte:
+			r.buffer.Write(value)
+		case string:
+			r.buffer.WriteString(value)
+		default:
+			r.buffer.WriteString(gconv.String(v))
+		}
+	}
+}
+
+func (r *MemoryResponse) Writef(format string, params ...interface{}) {
+	r.Write(fmt.Sprintf(format, params...))
+}
+
+func (r *MemoryResponse) Writeln(content ...interface{}) {
+	if len(content) == 0 {
+		r.Write("\n")
+		return
+	}
+	r.Write(append(content, "\n")...)
+}
+
+func (r *MemoryResponse) WriteJson(content interface{}) error {
+	b, err := gparser.VarToJson(content)
+	if err != nil {
+		return err
+	}
+	r.header.Set("Content-Type", "application/json")
+	r.Write(b)
+	return nil
+}
+
+func (r *MemoryResponse) WriteXml(content interface{}, rootTag ...string) error {
+	b, err := gparser.VarToXml(content, rootTag...)
+	if err != nil {
+		return err
+	}
+	r.header.Set("Content-Type", "application/xml")
+	r.Write(b)
+	return nil
+}
+
+func (r *MemoryResponse) WriteStatus(status int, content ...string) {
+	if r.buffer.Len() == 0 {
+		r.header.Set("Content-Type", "text/plain; charset=utf-8")
+		if len(content) > 0 {
+			r.Write(content[0])
+		} else {
+			r.Write(http.StatusText(status))
+		}
+	}
+	r.WriteHeader(status)
+}
+
+func (r *MemoryResponse) Buffer() []byte {
+	return r.buffer.Bytes()
+}
+
+// BufferString returns the content of the response buffer as string.
+func (r *MemoryResponse) BufferString() string {
+	return r.buffer.String()
+}
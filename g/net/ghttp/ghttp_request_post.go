@@ -13,6 +13,12 @@ import (
 // 初始化POST请求参数
 func (r *Request) initPost() {
 	if !r.parsedPost {
+		// 非multipart表单的请求内容先进行缓冲，以便解析后仍然可以通过GetBody读取
+		if r.isMultipart() {
+			r.resetBody()
+		} else {
+			r.GetBody()
+		}
 		// MultiMedia表单请求解析允许最大使用内存：1GB
 		if r.ParseMultipartForm(1024*1024*1024) == nil {
 			r.parsedPost = true
@@ -141,7 +147,8 @@ func (r *Request) GetPostMap(def ...map[string]string) map[string]string {
 	return m
 }
 
-// 将所有的request参数映射到struct属性上，参数object应当为一个struct对象的指针, mapping为非必需参数，自定义参数与属性的映射关系
+// 将所有的request参数映射到struct属性上，参数object应当为一个struct对象的指针, mapping为非必需参数，自定义参数与属性的映射关系。
+// 类型为*UploadFile/[]*UploadFile的属性将会使用上传文件填充，并按照属性的valid标签进行文件校验(如：max-size、ext规则)。
 func (r *Request) GetPostToStruct(pointer interface{}, mapping ...map[string]string) error {
 	tagMap := r.getStructParamsTagMap(pointer)
 	if len(mapping) > 0 {
@@ -153,5 +160,8 @@ func (r *Request) GetPostToStruct(pointer interface{}, mapping ...map[string]str
 	for k, v := range r.GetPostMap() {
 		params[k] = v
 	}
-	return gconv.Struct(params, pointer, tagMap)
+	if err := gconv.Struct(params, pointer, tagMap); err != nil {
+		return err
+	}
+	return r.bindUploadFiles(pointer, tagMap)
 }
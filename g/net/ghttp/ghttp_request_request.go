@@ -131,7 +131,8 @@ func (r *Request) GetRequestMap(def ...map[string]string) map[string]string {
 	return m
 }
 
-// 将所有的request参数映射到struct属性上，参数object应当为一个struct对象的指针, mapping为非必需参数，自定义参数与属性的映射关系
+// 将所有的request参数映射到struct属性上，参数object应当为一个struct对象的指针, mapping为非必需参数，自定义参数与属性的映射关系。
+// 类型为*UploadFile/[]*UploadFile的属性将会使用上传文件填充，并按照属性的valid标签进行文件校验(如：max-size、ext规则)。
 func (r *Request) GetRequestToStruct(pointer interface{}, mapping ...map[string]string) error {
 	tagMap := r.getStructParamsTagMap(pointer)
 	if len(mapping) > 0 {
@@ -148,5 +149,8 @@ func (r *Request) GetRequestToStruct(pointer interface{}, mapping ...map[string]
 			params = j.ToMap()
 		}
 	}
-	return gconv.Struct(params, pointer, tagMap)
+	if err := gconv.Struct(params, pointer, tagMap); err != nil {
+		return err
+	}
+	return r.bindUploadFiles(pointer, tagMap)
 }
@@ -0,0 +1,299 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gf/g/os/gfile"
+	"github.com/gf/g/os/gtime"
+	"github.com/gf/g/util/grand"
+	"github.com/gf/g/util/gvalid"
+)
+
+const (
+	// Namespace of the temp files for saving uploading files.
+	gUPLOAD_TEMP_NAMESPACE = "ghttp.upload"
+)
+
+// UploadFile wraps the multipart uploading file.
+type UploadFile struct {
+	*multipart.FileHeader
+	request *Request // The request that the file is uploaded with, which is used for content inspection.
+}
+
+// uploadChecks caches the content inspection results of uploading files in a request.
+type uploadChecks map[*multipart.FileHeader]error
+
+// UploadInspector inspects the content of uploading <file> of request <r> before it's saved or bound to struct,
+// eg: scanning viruses using ClamAV or checking the MIME type. The <content> is the stream of the file content,
+// which does not need to be read completely. It returns an error to reject the file.
+type UploadInspector func(r *Request, file *UploadFile, content io.Reader) error
+
+var (
+	// Reflect types for struct binding of uploading files.
+	uploadFileType      = reflect.TypeOf((*UploadFile)(nil))
+	uploadFileSliceType = reflect.TypeOf([]*UploadFile(nil))
+)
+
+// String returns the client file name of the uploading file.
+// It returns empty string if <f> is nil, which is used by validation rule "required".
+func (f *UploadFile) String() string {
+	if f == nil || f.FileHeader == nil {
+		return ""
+	}
+	return f.Filename
+}
+
+// GetFilename returns the client file name of the uploading file.
+func (f *UploadFile) GetFilename() string {
+	return f.String()
+}
+
+// GetSize returns the size in bytes of the uploading file.
+func (f *UploadFile) GetSize() int64 {
+	if f == nil || f.FileHeader == nil {
+		return 0
+	}
+	return f.Size
+}
+
+// Save saves the uploading file to directory <dirPath> and returns the saved file name.
+// The <randomlyRename> specifies whether renaming the file name randomly,
+// which keeps the file extension.
+func (f *UploadFile) Save(dirPath string, randomlyRename ...bool) (filename string, err error) {
+	if f == nil || f.FileHeader == nil {
+		return "", errors.New("file is empty, maybe you retrieve it from invalid field name or form enctype")
+	}
+	if err = f.Inspect(); err != nil {
+		return "", err
+	}
+	if !gfile.Exists(dirPath) {
+		if err = gfile.Mkdir(dirPath); err != nil {
+			return
+		}
+	} else if !gfile.IsDir(dirPath) {
+		return "", fmt.Errorf(`parameter "dirPath" should be a directory path: %s`, dirPath)
+	}
+	file, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	filename = gfile.Basename(f.Filename)
+	if len(randomlyRename) > 0 && randomlyRename[0] {
+		filename = strings.ToLower(fmt.Sprintf(`%d%s%s`, gtime.Nanosecond(), grand.Str(6), gfile.Ext(f.Filename)))
+	}
+	// Writing to a temp file first, so no partial file is left if the copying fails.
+	temp := gfile.Temp(gUPLOAD_TEMP_NAMESPACE)
+	tempFile, err := temp.CreateFile(filename + ".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(tempFile, file)
+	if e := tempFile.Close(); err == nil {
+		err = e
+	}
+	if err == nil {
+		err = temp.Commit(tempFile.Name(), strings.TrimRight(dirPath, `/\`)+gfile.Separator+filename)
+	}
+	if err != nil {
+		temp.Remove(tempFile.Name())
+		return "", err
+	}
+	return filename, nil
+}
+
+// Inspect inspects the content of the uploading file using the UploadInspector of the server,
+// see Server.SetUploadInspector. It returns nil if no inspector is set.
+// The result is cached in the request, so the file is inspected only once even it's bound and saved.
+func (f *UploadFile) Inspect() error {
+	if f == nil || f.FileHeader == nil || f.request == nil || f.request.Server == nil {
+		return nil
+	}
+	inspector := f.request.Server.config.UploadInspector
+	if inspector == nil {
+		return nil
+	}
+	r := f.request
+	if err, ok := r.uploadChecks[f.FileHeader]; ok {
+		return err
+	}
+	file, err := f.Open()
+	if err != nil {
+		return err
+	}
+	err = inspector(r, f, file)
+	file.Close()
+	if r.uploadChecks == nil {
+		r.uploadChecks = make(uploadChecks)
+	}
+	r.uploadChecks[f.FileHeader] = err
+	return err
+}
+
+// UploadMimeInspector returns an UploadInspector which sniffs the MIME type of the file content
+// using http.DetectContentType, and rejects the file if the type does not match any of <allowed>.
+// The allowed type supports wildcard subtype, eg: "image/*".
+// Note that the sniffed type of plain text is "text/plain", and the type of unknown binary is "application/octet-stream".
+func UploadMimeInspector(allowed ...string) UploadInspector {
+	return func(r *Request, file *UploadFile, content io.Reader) error {
+		buffer := make([]byte, 512)
+		n, err := io.ReadFull(content, buffer)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		mimeType := http.DetectContentType(buffer[:n])
+		if i := strings.IndexByte(mimeType, ';'); i != -1 {
+			mimeType = strings.TrimSpace(mimeType[:i])
+		}
+		for _, v := range allowed {
+			if strings.EqualFold(v, mimeType) {
+				return nil
+			}
+			if strings.HasSuffix(v, "/*") && strings.HasPrefix(strings.ToLower(mimeType), strings.ToLower(v[:len(v)-1])) {
+				return nil
+			}
+		}
+		return fmt.Errorf(`file "%s" of type "%s" is not allowed`, file.GetFilename(), mimeType)
+	}
+}
+
+// GetUploadFile returns the first uploading file with form field <name>.
+// It returns nil if the request is not a multipart request or the file is not found.
+func (r *Request) GetUploadFile(name string) *UploadFile {
+	files := r.GetUploadFiles(name)
+	if len(files) > 0 {
+		return files[0]
+	}
+	return nil
+}
+
+// GetUploadFiles returns all uploading files with form field <name>.
+func (r *Request) GetUploadFiles(name string) []*UploadFile {
+	r.initPost()
+	if r.MultipartForm == nil {
+		return nil
+	}
+	if headers, ok := r.MultipartForm.File[name]; ok && len(headers) > 0 {
+		files := make([]*UploadFile, len(headers))
+		for i, header := range headers {
+			files[i] = &UploadFile{FileHeader: header, request: r}
+		}
+		return files
+	}
+	return nil
+}
+
+// bindUploadFiles populates the fields of type *UploadFile/[]*UploadFile of struct <pointer>
+// with uploading files, and validates them using the gvalid rules in their "valid"/"gvalid" tags.
+// The <tagMap> is the mapping from parameter name to attribute name.
+func (r *Request) bindUploadFiles(pointer interface{}, tagMap map[string]string) error {
+	rv := reflect.ValueOf(pointer)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	// Mapping from attribute name to parameter name.
+	nameMap := make(map[string]string)
+	for k, v := range tagMap {
+		nameMap[v] = k
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Type != uploadFileType && field.Type != uploadFileSliceType {
+			continue
+		}
+		name, ok := nameMap[field.Name]
+		if !ok {
+			name = r.searchUploadFileName(field.Name)
+		}
+		files := r.GetUploadFiles(name)
+		if field.Type == uploadFileType {
+			if len(files) > 0 {
+				rv.Field(i).Set(reflect.ValueOf(files[0]))
+			}
+		} else {
+			rv.Field(i).Set(reflect.ValueOf(files))
+		}
+		// File validation, and then content inspection which may be expensive.
+		tag := field.Tag.Get("valid")
+		if tag == "" {
+			tag = field.Tag.Get("gvalid")
+		}
+		if err := validateUploadFiles(files, tag); err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := file.Inspect(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateUploadFiles validates uploading <files> using the gvalid rules in struct tag <tag>.
+func validateUploadFiles(files []*UploadFile, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	array := strings.SplitN(tag, "#", 2)
+	rules := array[0]
+	if n := strings.Index(rules, "@"); n != -1 {
+		rules = rules[n+1:]
+	}
+	rules = strings.TrimSpace(rules)
+	msgs := ""
+	if len(array) > 1 {
+		msgs = strings.TrimSpace(array[1])
+	}
+	if len(files) == 0 {
+		files = []*UploadFile{nil}
+	}
+	for _, file := range files {
+		err := gvalid.Check(file, rules, msgs)
+		if err == nil {
+			continue
+		}
+		// Empty file only needs the "required" rule checking.
+		if file == nil {
+			if msg, ok := err.Map()["required"]; ok {
+				return errors.New(msg)
+			}
+			continue
+		}
+		return errors.New(err.FirstString())
+	}
+	return nil
+}
+
+// searchUploadFileName searches the form field name of uploading files for attribute <attrName>,
+// using the same fuzzy matching rule as struct binding: case-insensitive and ignoring '-'/'_'.
+func (r *Request) searchUploadFileName(attrName string) string {
+	if r.MultipartForm == nil {
+		return attrName
+	}
+	replacer := strings.NewReplacer("-", "", "_", "")
+	for name := range r.MultipartForm.File {
+		if strings.EqualFold(replacer.Replace(name), replacer.Replace(attrName)) {
+			return name
+		}
+	}
+	return attrName
+}
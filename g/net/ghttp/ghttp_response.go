@@ -10,9 +10,12 @@ package ghttp
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/gf/g/encoding/gcompress"
 	"github.com/gf/g/encoding/gparser"
 	"github.com/gf/g/os/gfile"
 	"github.com/gf/g/util/gconv"
@@ -189,6 +192,39 @@ func (r *Response) ServeFileDownload(path string, name ...string) {
 	r.Server.serveFile(r.request, path)
 }
 
+// 流式输出content的内容，name用于判断Content-Type(如果没有设置)，modtime用于Last-Modified及缓存判断(为零值时忽略)，
+// 支持Range/If-Range等请求头的断点续传及部分内容(206)输出，适用于非文件存储的内容，例如对象存储中的音视频数据。
+// 输出内容不会在内存中缓冲，注意调用后不能再通过Write等方法修改输出的HTTP状态码及HEADER。
+func (r *Response) ServeContent(name string, modtime time.Time, content io.ReadSeeker) {
+	r.Header().Set("Server", r.Server.config.ServerAgent)
+	r.Header().Set("Accept-Ranges", "bytes")
+	r.request.Cookie.Output()
+	r.Writer.direct = true
+	http.ServeContent(r.Writer, r.request.Request, name, modtime, content)
+	r.Writer.direct = false
+}
+
+// 将entries打包为zip压缩文件，以name文件名流式输出到客户端下载，压缩文件不会在内存或者磁盘中缓冲，
+// 适用于"打包下载"等场景。
+// 注意开始输出后HTTP状态码及HEADER已经发送到客户端，此时打包失败只能中断输出；
+// 开始输出前打包失败(例如第一个文件不存在)时返回500状态码。
+func (r *Response) ServeZipDownload(name string, entries []gcompress.ZipEntry) error {
+	r.Header().Set("Content-Type", "application/zip")
+	r.Header().Set("Content-Disposition", fmt.Sprintf(`attachment;filename="%s"`, name))
+	r.Header().Set("Server", r.Server.config.ServerAgent)
+	r.Header().Del("Content-Length")
+	r.request.Cookie.Output()
+	r.Writer.direct = true
+	err := gcompress.ZipEntriesWriter(entries, r.Writer)
+	r.Writer.direct = false
+	if err != nil && !r.Writer.wroteHeader {
+		r.Header().Del("Content-Type")
+		r.Header().Del("Content-Disposition")
+		r.WriteStatus(http.StatusInternalServerError)
+	}
+	return err
+}
+
 // 返回location标识，引导客户端跳转。
 // 注意这里要先把设置的cookie输出，否则会被忽略。
 func (r *Response) RedirectTo(location string) {
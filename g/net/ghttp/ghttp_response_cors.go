@@ -8,13 +8,17 @@
 package ghttp
 
 import (
+	"net/http"
+	"strings"
+
+	"github.com/gf/g/os/glog"
 	"github.com/gf/g/util/gconv"
 )
 
 // See https://www.w3.org/TR/cors/ .
 // 服务端允许跨域请求选项
 type CORSOptions struct {
-	AllowOrigin      string // Access-Control-Allow-Origin
+	AllowOrigin      string // Access-Control-Allow-Origin，可以为*或者以半角逗号分隔的多个域名(例如：https://a.com,https://b.com)
 	AllowCredentials string // Access-Control-Allow-Credentials
 	ExposeHeaders    string // Access-Control-Expose-Headers
 	MaxAge           int    // Access-Control-Max-Age
@@ -34,9 +38,24 @@ func (r *Response) DefaultCORSOptions() CORSOptions {
 
 // See https://www.w3.org/TR/cors/ .
 // 允许请求跨域访问.
+// AllowOrigin为多个域名时只有请求的Origin在列表中才返回跨域头信息，并且Access-Control-Allow-Origin返回请求的Origin；
+// AllowOrigin为*并且AllowCredentials为true时返回请求的Origin(浏览器不允许携带凭证的跨域请求使用*)；
+// AllowHeaders为空时预检请求返回请求的Access-Control-Request-Headers。
 func (r *Response) CORS(options CORSOptions) {
-	if options.AllowOrigin != "" {
-		r.Header().Set("Access-Control-Allow-Origin", options.AllowOrigin)
+	origin := r.request.Header.Get("Origin")
+	allowOrigin := options.AllowOrigin
+	if strings.Contains(allowOrigin, ",") {
+		if origin == "" || !corsOriginAllowed(allowOrigin, origin) {
+			return
+		}
+		allowOrigin = origin
+		r.Header().Add("Vary", "Origin")
+	} else if allowOrigin == "*" && origin != "" && options.AllowCredentials == "true" {
+		allowOrigin = origin
+		r.Header().Add("Vary", "Origin")
+	}
+	if allowOrigin != "" {
+		r.Header().Set("Access-Control-Allow-Origin", allowOrigin)
 	}
 	if options.AllowCredentials != "" {
 		r.Header().Set("Access-Control-Allow-Credentials", options.AllowCredentials)
@@ -52,6 +71,10 @@ func (r *Response) CORS(options CORSOptions) {
 	}
 	if options.AllowHeaders != "" {
 		r.Header().Set("Access-Control-Allow-Headers", options.AllowHeaders)
+	} else if r.request.isCORSPreflight() {
+		if headers := r.request.Header.Get("Access-Control-Request-Headers"); headers != "" {
+			r.Header().Set("Access-Control-Allow-Headers", headers)
+		}
 	}
 }
 
@@ -59,3 +82,30 @@ func (r *Response) CORS(options CORSOptions) {
 func (r *Response) CORSDefault() {
 	r.CORS(r.DefaultCORSOptions())
 }
+
+// 设置Server默认的跨域请求选项，对所有请求生效(包括静态文件及未匹配路由的请求)，
+// 跨域预检请求(OPTIONS)直接返回204状态码，不再执行路由匹配及服务方法。
+func (s *Server) SetCORSOptions(options CORSOptions) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.CORSOptions = &options
+}
+
+// 判断请求的Origin是否在以半角逗号分隔的允许域名列表中
+func corsOriginAllowed(allowOrigin string, origin string) bool {
+	for _, v := range strings.Split(allowOrigin, ",") {
+		if v = strings.TrimSpace(v); v == "*" || strings.EqualFold(v, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// 判断是否为跨域预检请求
+func (r *Request) isCORSPreflight() bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
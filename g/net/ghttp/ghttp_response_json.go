@@ -0,0 +1,117 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+//
+
+package ghttp
+
+import (
+	"net/http"
+
+	"github.com/gf/g/os/glog"
+)
+
+const (
+	JSON_CODE_OK     = 0    // 统一JSON返回格式中成功的业务码
+	gJSON_MESSAGE_OK = "ok" // 统一JSON返回格式中成功的提示信息
+)
+
+// 统一的JSON返回格式
+type JsonResponse struct {
+	Code    int         `json:"code"`    // 业务码，JSON_CODE_OK表示成功
+	Message string      `json:"message"` // 提示信息
+	Data    interface{} `json:"data"`    // 返回数据
+}
+
+// 带有业务码及HTTP状态码的错误，通过WriteJsonResult返回时按照该错误设置返回状态码及返回内容
+type JsonError struct {
+	Status  int         // HTTP状态码
+	Code    int         // 业务码
+	Message string      // 提示信息
+	Data    interface{} // 返回数据
+}
+
+// 统一JSON返回格式的包装方法，将业务码、提示信息及返回数据包装为返回的JSON对象，
+// 默认包装为JsonResponse，可以通过SetJsonWrapper自定义返回格式(例如使用不同的字段名称)
+type JsonWrapper func(r *Request, code int, message string, data interface{}) interface{}
+
+// 创建带有业务码的错误，status为HTTP状态码，不指定时为400(code为HTTP错误状态码时使用code)
+func NewJsonError(code int, message string, status ...int) *JsonError {
+	err := &JsonError{
+		Code:    code,
+		Message: message,
+	}
+	if len(status) > 0 {
+		err.Status = status[0]
+	} else if code >= http.StatusBadRequest && code < 600 {
+		err.Status = code
+	} else {
+		err.Status = http.StatusBadRequest
+	}
+	return err
+}
+
+func (e *JsonError) Error() string {
+	return e.Message
+}
+
+// 设置统一JSON返回格式的包装方法，参考JsonWrapper
+func (s *Server) SetJsonWrapper(wrapper JsonWrapper) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.JsonWrapper = wrapper
+}
+
+// 使用统一JSON返回格式返回成功结果，例如：{"code":0,"message":"ok","data":data}
+func (r *Response) WriteJsonOk(data interface{}) error {
+	return r.writeJsonEnvelope(JSON_CODE_OK, gJSON_MESSAGE_OK, data)
+}
+
+// 使用统一JSON返回格式返回错误结果，例如：{"code":code,"message":message,"data":null}，
+// code为HTTP错误状态码(400-599)时同时设置为返回状态码，否则返回状态码不变(默认为200)。
+func (r *Response) WriteJsonError(code int, message string, data ...interface{}) error {
+	if code >= http.StatusBadRequest && code < 600 {
+		r.WriteHeader(code)
+	}
+	var d interface{}
+	if len(data) > 0 {
+		d = data[0]
+	}
+	return r.writeJsonEnvelope(code, message, d)
+}
+
+// 使用统一JSON返回格式返回处理结果，err为空时返回成功结果，否则按照错误返回：
+// *JsonError按照其HTTP状态码、业务码及提示信息返回，其他错误返回500状态码及错误信息。
+// 错误同时通过Request.SetError记录到请求中(用于请求统计)。
+func (r *Response) WriteJsonResult(data interface{}, err error) error {
+	if err == nil {
+		return r.WriteJsonOk(data)
+	}
+	r.request.SetError(err)
+	if e, ok := err.(*JsonError); ok {
+		status := e.Status
+		if status == 0 {
+			status = http.StatusBadRequest
+		}
+		r.WriteHeader(status)
+		return r.writeJsonEnvelope(e.Code, e.Message, e.Data)
+	}
+	r.WriteHeader(http.StatusInternalServerError)
+	return r.writeJsonEnvelope(http.StatusInternalServerError, err.Error(), nil)
+}
+
+// 按照统一JSON返回格式(或者自定义的包装方法)输出返回内容
+func (r *Response) writeJsonEnvelope(code int, message string, data interface{}) error {
+	if wrapper := r.Server.config.JsonWrapper; wrapper != nil {
+		return r.WriteJson(wrapper(r.request, code, message, data))
+	}
+	return r.WriteJson(JsonResponse{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	})
+}
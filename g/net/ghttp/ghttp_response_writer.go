@@ -15,12 +15,21 @@ import (
 // 自定义的ResponseWriter，用于写入流的控制
 type ResponseWriter struct {
 	http.ResponseWriter
-	Status int           // http status
-	buffer *bytes.Buffer // 缓冲区内容
+	Status      int           // http status
+	buffer      *bytes.Buffer // 缓冲区内容
+	direct      bool          // 是否直接输出到客户端(不缓冲)，用于流式输出
+	wroteHeader bool          // 是否已经输出HEADER
 }
 
 // 覆盖父级的WriteHeader方法
 func (w *ResponseWriter) Write(data []byte) (int, error) {
+	if w.direct {
+		if w.Status == 0 {
+			w.Status = http.StatusOK
+		}
+		w.OutputBuffer()
+		return w.ResponseWriter.Write(data)
+	}
 	w.buffer.Write(data)
 	return len(data), nil
 }
@@ -32,8 +41,9 @@ func (w *ResponseWriter) WriteHeader(status int) {
 
 // 输出buffer数据到客户端.
 func (w *ResponseWriter) OutputBuffer() {
-	if w.Status != 0 {
+	if w.Status != 0 && !w.wroteHeader {
 		w.ResponseWriter.WriteHeader(w.Status)
+		w.wroteHeader = true
 	}
 	if w.buffer.Len() > 0 {
 		w.ResponseWriter.Write(w.buffer.Bytes())
@@ -20,7 +20,9 @@ import (
 
 	"github.com/gf/g/container/garray"
 	"github.com/gf/g/container/gmap"
+	"github.com/gf/g/container/gset"
 	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/frame/gins"
 	"github.com/gf/g/os/gcache"
 	"github.com/gf/g/os/genv"
 	"github.com/gf/g/os/gfile"
@@ -49,6 +51,13 @@ type (
 		serveCache *gcache.Cache                    // 服务注册路由内存缓存
 		hooksCache *gcache.Cache                    // 事件回调路由内存缓存
 		routesMap  map[string][]registeredRouteItem // 已经注册的路由及对应的注册方法文件地址(用以路由重复注册判断)
+		// 路由匹配选项
+		groupOption     *RouteOption // 当前通过分组注册的路由的匹配选项(仅在分组路由注册过程中有效)
+		caseInsensitive bool         // 是否有分组路由需要忽略大小写匹配
+		// 中间件
+		middleware      []HandlerFunc          // 全局中间件
+		groupMiddleware []HandlerFunc          // 当前通过分组注册的路由的中间件(仅在分组路由注册过程中有效)
+		wsInterceptors  []WebSocketInterceptor // 全局WebSocket消息拦截器
 		// 自定义状态码回调
 		hsmu             sync.RWMutex           // status handler互斥锁
 		statusHandlerMap map[string]HandlerFunc // 不同状态码下的注册处理方法(例如404状态时的处理方法)
@@ -56,6 +65,17 @@ type (
 		sessions *gcache.Cache // Session内存缓存
 		// Logger
 		logger *glog.Logger // 日志管理对象
+		// 请求统计
+		metrics *serverMetrics // 路由请求统计及告警
+		// OpenAPI文档
+		openapi *openApi // OpenAPI文档配置及路由注解
+		// 静态挂载
+		mounts *staticMounts // 静态挂载列表，支持通过配置文件热更新
+		// 优雅关闭
+		shutdownHandlers []func()       // 优雅关闭时执行的回调方法
+		shuttingDown     *gtype.Bool    // 是否正在优雅关闭
+		draining         sync.WaitGroup // 正在执行的优雅关闭，Run等待优雅关闭完成后才返回
+		websockets       *gset.Set      // 当前打开的WebSocket连接
 	}
 
 	// 路由对象
@@ -70,14 +90,17 @@ type (
 
 	// http回调函数注册信息
 	handlerItem struct {
-		name   string       // 注册的方法名称信息
-		rtype  int          // 注册方式(执行对象/回调函数/控制器)
-		ctype  reflect.Type // 控制器类型(反射类型)
-		fname  string       // 回调方法名称
-		faddr  HandlerFunc  // 准确的执行方法内存地址(与以上两个参数二选一)
-		finit  HandlerFunc  // 初始化请求回调方法(执行对象注册方式下有效)
-		fshut  HandlerFunc  // 完成请求回调方法(执行对象注册方式下有效)
-		router *Router      // 注册时绑定的路由对象
+		name   string        // 注册的方法名称信息
+		rtype  int           // 注册方式(执行对象/回调函数/控制器)
+		ctype  reflect.Type  // 控制器类型(反射类型)
+		cvalue reflect.Value // 注册的控制器对象(反射值)，每一次请求复制该对象创建新的控制器对象
+		fname  string        // 回调方法名称
+		faddr  HandlerFunc   // 准确的执行方法内存地址(与以上两个参数二选一)
+		finit  HandlerFunc   // 初始化请求回调方法(执行对象注册方式下有效)
+		fshut  HandlerFunc   // 完成请求回调方法(执行对象注册方式下有效)
+		option *RouteOption  // 分组路由的匹配选项，为空时使用Server的路由匹配选项
+		chain  []HandlerFunc // 分组路由的中间件
+		router *Router       // 注册时绑定的路由对象
 	}
 
 	// 根据特定URL.Path解析后的路由检索结果项
@@ -220,6 +243,10 @@ func GetServer(name ...interface{}) *Server {
 		sessions:         gcache.New(),
 		servedCount:      gtype.NewInt(),
 		logger:           glog.New(),
+		metrics:          newServerMetrics(),
+		mounts:           newStaticMounts(),
+		shuttingDown:     gtype.NewBool(),
+		websockets:       gset.New(),
 	}
 	// 初始化时使用默认配置
 	s.SetConfig(defaultServerConfig)
@@ -238,6 +265,7 @@ func (s *Server) Start() error {
 	if s.Status() == SERVER_STATUS_RUNNING {
 		return errors.New("server is already running")
 	}
+	s.shuttingDown.Set(false)
 
 	// 没有注册任何路由，且没有开启文件服务，那么提示错误
 	if len(s.routesMap) == 0 && !s.config.FileServerEnabled {
@@ -259,6 +287,9 @@ func (s *Server) Start() error {
 		}
 	}
 
+	// 注册静态文件指纹模板函数: {{asset "/js/app.js"}}
+	gins.View().BindFunc("asset", AssetUrl)
+
 	// gzip压缩文件类型
 	//if s.config.GzipContentTypes != nil {
 	//    for _, v := range s.config.GzipContentTypes {
@@ -389,6 +420,8 @@ func (s *Server) Run() {
 	}
 	// 阻塞等待服务执行完成
 	<-s.closeChan
+	// 等待优雅关闭完成
+	s.draining.Wait()
 
 	glog.Printf("%d: all servers shutdown", gproc.Pid())
 }
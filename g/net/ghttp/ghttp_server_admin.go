@@ -8,6 +8,7 @@
 package ghttp
 
 import (
+	"context"
 	"os"
 	"strings"
 	"time"
@@ -78,8 +79,14 @@ func (s *Server) EnableAdmin(pattern ...string) {
 	s.BindObject(p, &utilAdmin{})
 }
 
-// 关闭当前Web Server
-func (s *Server) Shutdown() error {
+// 关闭当前Web Server。
+// 给定ctx时执行优雅关闭：停止接收新连接，执行BindShutdownHandler注册的回调方法，
+// 等待正在处理的请求及WebSocket连接完成后返回，ctx取消或者超时时强制关闭剩余的连接并返回ctx.Err()；
+// 不给定ctx时异步1秒后强制关闭。
+func (s *Server) Shutdown(ctx ...context.Context) error {
+	if len(ctx) > 0 && ctx[0] != nil {
+		return s.gracefulShutdown(ctx[0])
+	}
 	// 非终端信号下，异步1秒后再执行关闭，
 	// 目的是让接口能够正确返回结果，否则接口会报错(因为web server关闭了)
 	gtimer.SetTimeout(time.Second, func() {
@@ -9,6 +9,7 @@ package ghttp
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -220,8 +221,8 @@ func shutdownWebServers(signal ...string) {
 	serverProcessStatus.Set(gADMIN_ACTION_SHUTINGDOWN)
 	if len(signal) > 0 {
 		glog.Printf("%d: server shutting down by signal: %s", gproc.Pid(), signal[0])
-		// 在终端信号下，立即执行关闭操作
-		forceCloseWebServers()
+		// 在终端信号下，立即执行关闭操作(设置了优雅关闭超时时间的Server执行优雅关闭)
+		shutdownWebServersBySignal()
 		allDoneChan <- struct{}{}
 	} else {
 		glog.Printf("%d: server shutting down by api", gproc.Pid())
@@ -240,12 +241,40 @@ func gracefulShutdownWebServers() {
 	serverMapping.RLockFunc(func(m map[string]interface{}) {
 		for _, v := range m {
 			for _, s := range v.(*Server).servers {
-				s.shutdown()
+				s.shutdown(context.Background())
 			}
 		}
 	})
 }
 
+// 终端信号下关闭进程所有端口的Web Server服务，设置了ShutdownTimeout的Server在超时时间内优雅关闭，其他Server强制关闭
+// 注意，只是关闭Web Server服务，并不是退出进程
+func shutdownWebServersBySignal() {
+	servers := make([]*Server, 0)
+	serverMapping.RLockFunc(func(m map[string]interface{}) {
+		for _, v := range m {
+			servers = append(servers, v.(*Server))
+		}
+	})
+	wg := sync.WaitGroup{}
+	for _, s := range servers {
+		if s.config.ShutdownTimeout <= 0 {
+			for _, v := range s.servers {
+				v.close()
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(s *Server) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+			defer cancel()
+			s.Shutdown(ctx)
+		}(s)
+	}
+	wg.Wait()
+}
+
 // 强制关闭进程所有端口的Web Server服务
 // 注意，只是关闭Web Server服务，并不是退出进程
 func forceCloseWebServers() {
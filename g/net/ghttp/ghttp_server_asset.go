@@ -0,0 +1,130 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package ghttp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gf/g/container/gmap"
+	"github.com/gf/g/crypto/gmd5"
+)
+
+const (
+	// 静态文件指纹URL中的版本参数名称
+	gASSET_VERSION_NAME = "v"
+	// 带有正确指纹的静态文件的浏览器缓存时间(秒)，一年
+	gASSET_CACHE_MAX_AGE = 365 * 24 * 3600
+	// 静态文件指纹长度
+	gASSET_HASH_LENGTH = 8
+)
+
+// 静态文件内容指纹
+type assetHashItem struct {
+	modTime int64  // 计算指纹时文件的修改时间(纳秒)
+	size    int64  // 计算指纹时文件的大小
+	hash    string // 文件内容指纹
+}
+
+var (
+	// 静态文件内容指纹缓存，键名为文件绝对路径，文件修改后自动重新计算
+	assetHashes = gmap.NewStrAnyMap()
+)
+
+// 获取静态文件<uri>带有内容指纹的URL，例如: /js/app.js -> /js/app.js?v=1a2b3c4d，
+// 文件内容修改后指纹随之改变，因此带有正确指纹的静态文件请求将返回长期缓存的Cache-Control头信息。
+// 静态文件从当前Server的静态文件目录中检索，当文件不存在时直接返回<uri>。
+func (s *Server) AssetUrl(uri string) string {
+	if !s.config.FileServerEnabled {
+		return uri
+	}
+	path := uri
+	if pos := strings.IndexAny(path, "?#"); pos != -1 {
+		path = path[:pos]
+	}
+	if path == "" || path[0] != '/' {
+		path = "/" + path
+	}
+	file, isDir := s.searchStaticFile(path)
+	if file == "" || isDir {
+		return uri
+	}
+	hash := getAssetHash(file)
+	if hash == "" {
+		return uri
+	}
+	if uri == "" || uri[0] != '/' {
+		uri = "/" + uri
+	}
+	separator := "?"
+	if strings.IndexByte(uri, '?') != -1 {
+		separator = "&"
+	}
+	if pos := strings.IndexByte(uri, '#'); pos != -1 {
+		return uri[:pos] + separator + gASSET_VERSION_NAME + "=" + hash + uri[pos:]
+	}
+	return uri + separator + gASSET_VERSION_NAME + "=" + hash
+}
+
+// 获取静态文件<uri>带有内容指纹的URL，优先从默认Server检索静态文件，其次从其他Server中检索。
+// 该方法在Server启动时会作为模板函数asset注册到默认的模板引擎对象中，
+// 模板中的使用方式: {{asset "/js/app.js"}}
+func AssetUrl(uri string) string {
+	if s := serverMapping.Get(gDEFAULT_SERVER); s != nil {
+		if u := s.(*Server).AssetUrl(uri); u != uri {
+			return u
+		}
+	}
+	result := uri
+	serverMapping.RLockFunc(func(m map[string]interface{}) {
+		for name, s := range m {
+			if name == gDEFAULT_SERVER {
+				continue
+			}
+			if u := s.(*Server).AssetUrl(uri); u != uri {
+				result = u
+				return
+			}
+		}
+	})
+	return result
+}
+
+// 获取静态文件内容指纹，当文件不存在或者无法读取时返回空字符串。
+func getAssetHash(file string) string {
+	info, err := os.Stat(file)
+	if err != nil {
+		return ""
+	}
+	if v := assetHashes.Get(file); v != nil {
+		item := v.(*assetHashItem)
+		if item.modTime == info.ModTime().UnixNano() && item.size == info.Size() {
+			return item.hash
+		}
+	}
+	hash, err := gmd5.EncryptFile(file)
+	if err != nil {
+		return ""
+	}
+	hash = hash[:gASSET_HASH_LENGTH]
+	assetHashes.Set(file, &assetHashItem{
+		modTime: info.ModTime().UnixNano(),
+		size:    info.Size(),
+		hash:    hash,
+	})
+	return hash
+}
+
+// 静态文件请求带有正确的内容指纹时，设置长期缓存的Cache-Control头信息。
+func (s *Server) setAssetCacheHeader(r *Request, file string) {
+	version := r.URL.Query().Get(gASSET_VERSION_NAME)
+	if version == "" || version != getAssetHash(file) {
+		return
+	}
+	r.Response.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", gASSET_CACHE_MAX_AGE))
+}
@@ -18,16 +18,17 @@ import (
 )
 
 const (
-	gDEFAULT_HTTP_ADDR                 = ":80"         // 默认HTTP监听地址
-	gDEFAULT_HTTPS_ADDR                = ":443"        // 默认HTTPS监听地址
-	NAME_TO_URI_TYPE_DEFAULT           = 0             // 服务注册时对象和方法名称转换为URI时，全部转为小写，单词以'-'连接符号连接
-	NAME_TO_URI_TYPE_FULLNAME          = 1             // 不处理名称，以原有名称构建成URI
-	NAME_TO_URI_TYPE_ALLLOWER          = 2             // 仅转为小写，单词间不使用连接符号
-	NAME_TO_URI_TYPE_CAMEL             = 3             // 采用驼峰命名方式
-	gDEFAULT_COOKIE_PATH               = "/"           // 默认path
-	gDEFAULT_COOKIE_MAX_AGE            = 86400 * 365   // 默认cookie有效期(一年)
-	gDEFAULT_SESSION_MAX_AGE           = 600000        // 默认session有效期(600秒)
-	gDEFAULT_SESSION_ID_NAME           = "gfsessionid" // 默认存放Cookie中的SessionId名称
+	gDEFAULT_HTTP_ADDR                 = ":80"           // 默认HTTP监听地址
+	gDEFAULT_HTTPS_ADDR                = ":443"          // 默认HTTPS监听地址
+	NAME_TO_URI_TYPE_DEFAULT           = 0               // 服务注册时对象和方法名称转换为URI时，全部转为小写，单词以'-'连接符号连接
+	NAME_TO_URI_TYPE_FULLNAME          = 1               // 不处理名称，以原有名称构建成URI
+	NAME_TO_URI_TYPE_ALLLOWER          = 2               // 仅转为小写，单词间不使用连接符号
+	NAME_TO_URI_TYPE_CAMEL             = 3               // 采用驼峰命名方式
+	gDEFAULT_COOKIE_PATH               = "/"             // 默认path
+	gDEFAULT_COOKIE_MAX_AGE            = 86400 * 365     // 默认cookie有效期(一年)
+	gDEFAULT_SESSION_MAX_AGE           = 600000          // 默认session有效期(600秒)
+	gDEFAULT_SESSION_ID_NAME           = "gfsessionid"   // 默认存放Cookie中的SessionId名称
+	gDEFAULT_BODY_BUFFER_SIZE          = 8 * 1024 * 1024 // 默认请求内容缓冲的最大大小(8MB)
 	gCHANGE_CONFIG_WHILE_RUNNING_ERROR = "cannot be changed while running"
 )
 
@@ -64,8 +65,9 @@ type ServerConfig struct {
 	CookieDomain string // Cookie有效Domain(注意同时也会影响SessionID)
 
 	// SESSION
-	SessionMaxAge int    // Session有效期
-	SessionIdName string // SessionId名称
+	SessionMaxAge  int            // Session有效期
+	SessionIdName  string         // SessionId名称
+	SessionStorage SessionStorage // Session外部存储(默认为空，表示存储在内存中)
 
 	// IP访问控制
 	DenyIps  []string // 不允许访问的ip列表，支持ip前缀过滤，如: 10 将不允许10开头的ip访问
@@ -76,17 +78,26 @@ type ServerConfig struct {
 	Rewrites   map[string]string // URI Rewrite重写配置
 
 	// 日志配置
-	LogPath          string     // 存放日志的目录路径(默认为空，表示不写文件)
-	LogHandler       LogHandler // 自定义日志处理回调方法(默认为空)
-	LogStdout        bool       // 是否打印日志到终端(默认开启)
-	ErrorLogEnabled  bool       // 是否开启error log(默认开启)
-	AccessLogEnabled bool       // 是否开启access log(默认关闭)
+	LogPath            string        // 存放日志的目录路径(默认为空，表示不写文件)
+	LogHandler         LogHandler    // 自定义日志处理回调方法(默认为空)
+	LogStdout          bool          // 是否打印日志到终端(默认开启)
+	ErrorLogEnabled    bool          // 是否开启error log(默认开启)
+	AccessLogEnabled   bool          // 是否开启access log(默认关闭)
+	LogBufferLevel     int           // 请求日志缓冲的日志级别(默认为0，表示不开启)，参考glog.NewBuffer
+	LogBufferThreshold time.Duration // 请求执行时间超过该阈值时输出缓冲的日志(默认为0，表示仅在请求失败时输出)
 
 	// 其他设置
-	NameToUriType     int      // 服务注册时对象和方法名称转换为URI时的规则
-	GzipContentTypes  []string // 允许进行gzip压缩的文件类型
-	DumpRouteMap      bool     // 是否在程序启动时默认打印路由表信息
-	RouterCacheExpire int      // 路由检索缓存过期时间(秒)
+	NameToUriType     int             // 服务注册时对象和方法名称转换为URI时的规则
+	GzipContentTypes  []string        // 允许进行gzip压缩的文件类型
+	DumpRouteMap      bool            // 是否在程序启动时默认打印路由表信息
+	RouterCacheExpire int             // 路由检索缓存过期时间(秒)
+	BodyBufferSize    int64           // 请求内容缓冲的最大大小(字节)，超过该大小的请求内容不能通过GetBody重复读取
+	ControllerHooks   ControllerHooks // 控制器生命周期回调方法
+	RouteOption       RouteOption     // 路由匹配选项
+	UploadInspector   UploadInspector // 上传文件内容检查方法，保存及绑定上传文件前调用，返回错误时拒绝该文件
+	JsonWrapper       JsonWrapper     // 统一JSON返回格式的包装方法，为空时使用JsonResponse
+	ShutdownTimeout   time.Duration   // 收到终止信号时优雅关闭的超时时间，为0时(默认)立即关闭
+	CORSOptions       *CORSOptions    // 默认的跨域请求选项，为空时不处理跨域请求
 }
 
 // 默认HTTP Server配置
@@ -120,6 +131,7 @@ var defaultServerConfig = ServerConfig{
 	GzipContentTypes:  defaultGzipContentTypes,
 	DumpRouteMap:      true,
 	RouterCacheExpire: 60,
+	BodyBufferSize:    gDEFAULT_BODY_BUFFER_SIZE,
 	Rewrites:          make(map[string]string),
 }
 
@@ -319,6 +331,34 @@ func (s *Server) SetRouterCacheExpire(expire int) {
 	s.config.RouterCacheExpire = expire
 }
 
+// 设置请求内容缓冲的最大大小(字节)
+func (s *Server) SetBodyBufferSize(size int64) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.BodyBufferSize = size
+}
+
+// 设置控制器生命周期回调方法，参考ControllerHooks
+func (s *Server) SetControllerHooks(hooks ControllerHooks) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.ControllerHooks = hooks
+}
+
+// 设置上传文件内容检查方法(例如病毒扫描、MIME类型检查)，上传文件保存(UploadFile.Save)及绑定到结构体之前调用，
+// 返回错误时拒绝该文件，参考UploadInspector及UploadMimeInspector
+func (s *Server) SetUploadInspector(inspector UploadInspector) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.UploadInspector = inspector
+}
+
 // 设置KeepAlive
 func (s *Server) SetKeepAlive(enabled bool) {
 	if s.Status() == SERVER_STATUS_RUNNING {
@@ -7,6 +7,8 @@
 package ghttp
 
 import (
+	"time"
+
 	"github.com/gf/g/os/glog"
 )
 
@@ -54,6 +56,18 @@ func (s *Server) SetErrorLogEnabled(enabled bool) {
 	s.config.ErrorLogEnabled = enabled
 }
 
+// 设置请求日志缓冲，通过Request.Logger输出的<level>级别日志将会被缓冲，
+// 仅当请求失败(panic或者返回状态码>=500)，或者请求执行时间超过<threshold>时才会输出，否则将被丢弃。
+// <level>为0时表示关闭请求日志缓冲，<threshold>为0时表示仅在请求失败时输出。
+func (s *Server) SetLogBuffer(level int, threshold time.Duration) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.LogBufferLevel = level
+	s.config.LogBufferThreshold = threshold
+}
+
 // 设置日志写入的回调函数
 func (s *Server) SetLogHandler(handler LogHandler) {
 	if s.Status() == SERVER_STATUS_RUNNING {
@@ -35,3 +35,17 @@ func (s *Server) GetSessionMaxAge() int {
 func (s *Server) GetSessionIdName() string {
 	return s.config.SessionIdName
 }
+
+// 设置http server参数 - SessionStorage，设置后Session数据将存储到外部存储中，以便多个Server共享
+func (s *Server) SetSessionStorage(storage SessionStorage) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.SessionStorage = storage
+}
+
+// 获取http server参数 - SessionStorage
+func (s *Server) GetSessionStorage() SessionStorage {
+	return s.config.SessionStorage
+}
@@ -168,14 +168,16 @@ func (s *gracefulServer) getNetListener(addr string) (net.Listener, error) {
 	return ln, nil
 }
 
-// 执行请求优雅关闭
-func (s *gracefulServer) shutdown() {
+// 执行请求优雅关闭，ctx用于控制等待请求处理完成的超时时间
+func (s *gracefulServer) shutdown(ctx context.Context) error {
 	if s.status == SERVER_STATUS_STOPPED {
-		return
+		return nil
 	}
-	if err := s.httpServer.Shutdown(context.Background()); err != nil {
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
 		glog.Errorf("%d: %s server [%s] shutdown error: %v", gproc.Pid(), s.getProto(), s.addr, err)
 	}
+	return err
 }
 
 // 执行请求强制关闭
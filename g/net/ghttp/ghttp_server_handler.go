@@ -41,7 +41,8 @@ func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 		r.URL.Path = "/"
 	}
 
-	// 去掉末尾的"/"号
+	// 去掉末尾的"/"号，原始URI用于路由匹配选项的判断
+	rawPath := r.URL.Path
 	if r.URL.Path != "/" {
 		for r.URL.Path[len(r.URL.Path)-1] == '/' {
 			r.URL.Path = r.URL.Path[:len(r.URL.Path)-1]
@@ -54,6 +55,10 @@ func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 	defer func() {
 		// 设置请求完成时间
 		request.LeaveTime = gtime.Microsecond()
+		// 请求处理方法返回之后，升级的WebSocket连接不再由Server管理，优雅关闭时不再等待该连接
+		if request.websocket != nil {
+			request.websocket.deregister()
+		}
 		// 事件 - BeforeOutput
 		if !request.IsExited() {
 			s.callHookHandler(HOOK_BEFORE_OUTPUT, request)
@@ -63,10 +68,22 @@ func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 			request.Response.Status = http.StatusOK
 		}
 		// error log
+		panicked := false
 		if e := recover(); e != nil {
+			panicked = true
 			request.Response.WriteStatus(http.StatusInternalServerError)
 			s.handleErrorLog(e, request)
 		}
+		// 请求统计
+		s.metrics.record(request, panicked)
+		// 请求日志缓冲，请求失败或者执行时间超过阈值时输出缓冲的日志
+		if request.logBuffer != nil {
+			if request.Response.Status >= http.StatusInternalServerError {
+				request.logBuffer.Fail()
+			} else {
+				request.logBuffer.Done(s.config.LogBufferThreshold)
+			}
+		}
 		// access log
 		s.handleAccessLog(request)
 		// 输出Cookie
@@ -77,10 +94,19 @@ func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 		if !request.IsExited() {
 			s.callHookHandler(HOOK_AFTER_OUTPUT, request)
 		}
-		// 更新Session会话超时时间
-		request.Session.UpdateExpire()
+		// 写回变更的Session数据，并更新Session会话超时时间
+		request.Session.flush()
 	}()
 
+	// 默认的跨域请求处理，预检请求直接返回
+	if s.config.CORSOptions != nil {
+		request.Response.CORS(*s.config.CORSOptions)
+		if request.isCORSPreflight() {
+			request.Response.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
 	// ============================================================
 	// 优先级控制:
 	// 静态文件 > 动态服务 > 静态目录
@@ -88,18 +114,28 @@ func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 
 	staticFile := ""
 	isStaticDir := false
-	// 优先执行静态文件检索(检测是否存在对应的静态文件，包括index files处理)
+	mountFile := (*staticMountFile)(nil)
+	// 优先执行静态文件检索(检测是否存在对应的静态文件，包括index files处理)，静态挂载优先
 	if s.config.FileServerEnabled {
-		staticFile, isStaticDir = s.searchStaticFile(r.URL.Path)
-		if staticFile != "" {
+		if mountFile = s.searchStaticMount(r.URL.Path); mountFile != nil {
 			request.isFileRequest = true
+		} else {
+			staticFile, isStaticDir = s.searchStaticFile(r.URL.Path)
+			if staticFile != "" {
+				request.isFileRequest = true
+			}
 		}
 	}
 
 	// 动态服务检索
 	handler := (*handlerItem)(nil)
 	if !request.isFileRequest || isStaticDir {
-		if parsedItem := s.getServeHandlerWithCache(request); parsedItem != nil {
+		parsedItem, redirect := s.searchServeHandlerWithOption(request, rawPath)
+		if redirect != "" {
+			s.redirectRoutePath(request, redirect)
+			return
+		}
+		if parsedItem != nil {
 			handler = parsedItem.handler
 			for k, v := range parsedItem.values {
 				request.routerVars[k] = v
@@ -112,33 +148,38 @@ func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 	if isStaticDir && handler != nil {
 		request.isFileRequest = false
 	}
+	// 未匹配到任何路由、静态文件及静态目录
+	request.isNotFound = !request.isFileRequest && handler == nil && !isStaticDir
 
 	// 事件 - BeforeServe
 	s.callHookHandler(HOOK_BEFORE_SERVE, request)
 
-	// 执行静态文件服务/回调控制器/执行对象/方法
+	// 执行中间件，中间件执行完毕后执行静态文件服务/回调控制器/执行对象/方法
 	if !request.IsExited() {
-		// 需要再次判断文件是否真实存在，
-		// 因为文件检索可能使用了缓存，从健壮性考虑这里需要二次判断
-		if request.isFileRequest /* && gfile.Exists(staticFile) */ {
-			s.serveFile(request, staticFile)
-		} else {
-			if handler != nil {
-				// 动态服务
-				s.callServeHandler(handler, request)
+		request.Middleware.handlers = s.getMiddleware(handler)
+		request.Middleware.serve = func() {
+			// 需要再次判断文件是否真实存在，
+			// 因为文件检索可能使用了缓存，从健壮性考虑这里需要二次判断
+			if request.isFileRequest /* && gfile.Exists(staticFile) */ {
+				if mountFile != nil {
+					s.serveStaticMount(request, mountFile)
+				} else {
+					s.serveFile(request, staticFile)
+				}
 			} else {
-				if isStaticDir {
+				if handler != nil {
+					// 动态服务
+					s.callServeHandler(handler, request)
+				} else if isStaticDir {
 					// 静态目录
 					s.serveFile(request, staticFile)
-				} else {
-					if len(request.Response.Header()) == 0 &&
-						request.Response.Status == 0 &&
-						request.Response.BufferLength() == 0 {
-						request.Response.WriteStatus(http.StatusNotFound)
-					}
+				} else if request.isNotFound && request.Response.Status == 0 {
+					// 中间件设置的Header及输出内容不影响404状态码，中间件需要自行处理时可以设置状态码
+					request.Response.WriteStatus(http.StatusNotFound)
 				}
 			}
 		}
+		request.Middleware.Next()
 	}
 
 	// 事件 - AfterServe
@@ -175,10 +216,28 @@ func (s *Server) searchStaticFile(uri string) (filePath string, isDir bool) {
 // 调用服务接口
 func (s *Server) callServeHandler(h *handlerItem, r *Request) {
 	if h.faddr == nil {
+		// 以注册的控制器对象为原型创建新的控制器对象，注册时设置的属性(例如服务对象)将被复制
 		c := reflect.New(h.ctype)
-		s.niceCallFunc(func() {
-			c.MethodByName("Init").Call([]reflect.Value{reflect.ValueOf(r)})
-		})
+		if h.cvalue.IsValid() {
+			c.Elem().Set(h.cvalue)
+		}
+		hooks := s.config.ControllerHooks
+		ctrl, _ := c.Interface().(Controller)
+		if hooks.Inject != nil {
+			s.niceCallFunc(func() {
+				hooks.Inject(r, ctrl)
+			})
+		}
+		if !r.IsExited() {
+			s.niceCallFunc(func() {
+				c.MethodByName("Init").Call([]reflect.Value{reflect.ValueOf(r)})
+			})
+		}
+		if hooks.Init != nil && !r.IsExited() {
+			s.niceCallFunc(func() {
+				hooks.Init(r, ctrl)
+			})
+		}
 		if !r.IsExited() {
 			s.niceCallFunc(func() {
 				c.MethodByName(h.fname).Call(nil)
@@ -189,6 +248,11 @@ func (s *Server) callServeHandler(h *handlerItem, r *Request) {
 				c.MethodByName("Shut").Call(nil)
 			})
 		}
+		if hooks.Shut != nil {
+			s.niceCallFunc(func() {
+				hooks.Shut(r, ctrl)
+			})
+		}
 	} else {
 		if h.finit != nil {
 			s.niceCallFunc(func() {
@@ -241,6 +305,7 @@ func (s *Server) serveFile(r *Request, path string) {
 			r.Response.WriteStatus(http.StatusForbidden)
 		}
 	} else {
+		s.setAssetCacheHeader(r, path)
 		// 读取文件内容返回, no buffer
 		http.ServeContent(r.Response.Writer, r.Request, info.Name(), info.ModTime(), f)
 	}
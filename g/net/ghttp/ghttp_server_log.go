@@ -38,6 +38,10 @@ func (s *Server) handleAccessLog(r *Request) {
 
 // 处理服务错误信息，主要是panic，http请求的status由access log进行管理
 func (s *Server) handleErrorLog(error interface{}, r *Request) {
+	// 请求失败时输出缓冲的日志
+	if r.logBuffer != nil {
+		r.logBuffer.Fail()
+	}
 	// 错误输出默认是开启的
 	if !s.IsErrorLogEnabled() {
 		return
@@ -0,0 +1,281 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+// Request metrics per route and simple alerting.
+
+package ghttp
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gf/g/container/gtype"
+)
+
+const (
+	METRIC_SERVER_ERROR = "5xx"   // Responses with status code >= 500.
+	METRIC_PANIC        = "panic" // Panics in handlers or hooks.
+	METRIC_ERROR        = "error" // Errors returned by handlers using Request.SetError.
+
+	// Route name of the requests not matching any registered route, eg: static files and 404.
+	gMETRICS_UNMATCHED_ROUTE = "-"
+
+	// Default pattern of the metrics endpoint.
+	gDEFAULT_METRICS_PATTERN = "/debug/metrics"
+)
+
+// RouteMetrics is the snapshot of the metrics of a route.
+type RouteMetrics struct {
+	Route        string // Route in format "METHOD:URI[@DOMAIN]".
+	Requests     int64  // Count of requests.
+	ServerErrors int64  // Count of responses with status code >= 500.
+	Panics       int64  // Count of panics.
+	Errors       int64  // Count of errors returned by handlers using Request.SetError.
+}
+
+// AlertRule defines the condition of alerting: <Threshold> events of <Metric> within <Window>.
+type AlertRule struct {
+	Metric    string        // METRIC_SERVER_ERROR, METRIC_PANIC or METRIC_ERROR.
+	Route     string        // Route in format "METHOD:URI[@DOMAIN]", which is empty for all routes.
+	Threshold int           // Count of events triggering the alert.
+	Window    time.Duration // Sliding time window of counting, which is unlimited if it's 0.
+}
+
+// AlertEvent is passed to the alert hook when the alert is triggered.
+type AlertEvent struct {
+	Rule     AlertRule // Triggered rule.
+	Route    string    // Route of the last event.
+	Uri      string    // Request URI of the last event.
+	ClientIp string    // Client IP of the last event.
+	Count    int       // Count of events within the window, which equals to Threshold.
+	Time     time.Time // Time of triggering.
+}
+
+// AlertHook is called asynchronously when an alert is triggered.
+type AlertHook interface {
+	OnAlert(event *AlertEvent)
+}
+
+// AlertHookFunc is an adapter to use function as AlertHook.
+type AlertHookFunc func(event *AlertEvent)
+
+// OnAlert implements AlertHook.
+func (f AlertHookFunc) OnAlert(event *AlertEvent) {
+	f(event)
+}
+
+// MetricsProvider returns the snapshot of custom metrics, eg: the pool statistics of databases,
+// which is served by the metrics endpoint in JSON format.
+type MetricsProvider func() interface{}
+
+// serverMetrics collects the metrics of a server.
+type serverMetrics struct {
+	mu        sync.RWMutex
+	enabled   *gtype.Bool
+	routes    map[string]*RouteMetrics // Route to its metrics.
+	alerts    []*alert
+	providers map[string]MetricsProvider // Name to custom metrics provider.
+}
+
+// alert is a registered alert rule with its recent event times.
+type alert struct {
+	mu    sync.Mutex
+	rule  AlertRule
+	hook  AlertHook
+	times []time.Time // Times of the recent events, at most <Threshold> items.
+}
+
+// newServerMetrics creates and returns a disabled metrics collector.
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		enabled:   gtype.NewBool(),
+		routes:    make(map[string]*RouteMetrics),
+		alerts:    make([]*alert, 0),
+		providers: make(map[string]MetricsProvider),
+	}
+}
+
+// SetMetricsEnabled enables or disables collecting the request metrics per route, which is disabled in default.
+func (s *Server) SetMetricsEnabled(enabled bool) {
+	s.metrics.enabled.Set(enabled)
+}
+
+// GetMetrics returns the snapshot of the metrics of all routes, ordered by route.
+func (s *Server) GetMetrics() []RouteMetrics {
+	s.metrics.mu.RLock()
+	array := make([]RouteMetrics, 0, len(s.metrics.routes))
+	for _, m := range s.metrics.routes {
+		array = append(array, *m)
+	}
+	s.metrics.mu.RUnlock()
+	sort.Slice(array, func(i, j int) bool {
+		return array[i].Route < array[j].Route
+	})
+	return array
+}
+
+// ResetMetrics clears the metrics of all routes.
+func (s *Server) ResetMetrics() {
+	s.metrics.mu.Lock()
+	s.metrics.routes = make(map[string]*RouteMetrics)
+	s.metrics.mu.Unlock()
+}
+
+// AddAlert registers alert <hook> for <rule>, which is called when <rule.Threshold> events of <rule.Metric>
+// occur within <rule.Window>. The events are cleared after triggering, so the hook is called at most once
+// for every <rule.Threshold> events. It enables the metrics collecting automatically.
+func (s *Server) AddAlert(rule AlertRule, hook AlertHook) {
+	if rule.Threshold <= 0 {
+		rule.Threshold = 1
+	}
+	s.metrics.mu.Lock()
+	s.metrics.alerts = append(s.metrics.alerts, &alert{
+		rule:  rule,
+		hook:  hook,
+		times: make([]time.Time, 0, rule.Threshold),
+	})
+	s.metrics.mu.Unlock()
+	s.metrics.enabled.Set(true)
+}
+
+// AddMetricsProvider registers custom metrics <provider> with <name>, which replaces the provider
+// with the same name. The provider is called on each request of the metrics endpoint.
+func (s *Server) AddMetricsProvider(name string, provider MetricsProvider) {
+	s.metrics.mu.Lock()
+	s.metrics.providers[name] = provider
+	s.metrics.mu.Unlock()
+}
+
+// GetMetricsProviders returns the snapshot of all custom metrics, which maps provider name to its result.
+func (s *Server) GetMetricsProviders() map[string]interface{} {
+	s.metrics.mu.RLock()
+	providers := make(map[string]MetricsProvider, len(s.metrics.providers))
+	for name, provider := range s.metrics.providers {
+		providers[name] = provider
+	}
+	s.metrics.mu.RUnlock()
+	// Providers are called outside the lock as they might be slow or use the server itself.
+	m := make(map[string]interface{}, len(providers))
+	for name, provider := range providers {
+		m[name] = provider()
+	}
+	return m
+}
+
+// EnableMetrics binds the metrics endpoint to <pattern>, which is "/debug/metrics" in default.
+// The endpoint serves the route metrics under key "routes" and the custom metrics under their provider
+// names in JSON format. It enables the route metrics collecting automatically.
+func (s *Server) EnableMetrics(pattern ...string) {
+	p := gDEFAULT_METRICS_PATTERN
+	if len(pattern) > 0 && pattern[0] != "" {
+		p = pattern[0]
+	}
+	s.metrics.enabled.Set(true)
+	s.BindHandler(p, func(r *Request) {
+		data := s.GetMetricsProviders()
+		data["routes"] = s.GetMetrics()
+		r.Response.WriteJson(data)
+	})
+}
+
+// SetError sets the error of handling the request, which is counted in the METRIC_ERROR metrics.
+func (r *Request) SetError(err error) {
+	r.error = err
+}
+
+// GetError returns the error set by SetError.
+func (r *Request) GetError() error {
+	return r.error
+}
+
+// routeName returns the route name of <r> for metrics.
+func routeName(r *Request) string {
+	if r.Router == nil {
+		return gMETRICS_UNMATCHED_ROUTE
+	}
+	name := r.Router.Method + ":" + r.Router.Uri
+	if r.Router.Domain != "" && r.Router.Domain != gDEFAULT_DOMAIN {
+		name += "@" + r.Router.Domain
+	}
+	return name
+}
+
+// record records the metrics of request <r> after it's handled, and triggers the alerts.
+func (m *serverMetrics) record(r *Request, panicked bool) {
+	if !m.enabled.Val() {
+		return
+	}
+	var (
+		route   = routeName(r)
+		metrics = make([]string, 0, 3)
+	)
+	if r.Response.Status >= http.StatusInternalServerError {
+		metrics = append(metrics, METRIC_SERVER_ERROR)
+	}
+	if panicked {
+		metrics = append(metrics, METRIC_PANIC)
+	}
+	if r.error != nil {
+		metrics = append(metrics, METRIC_ERROR)
+	}
+	m.mu.Lock()
+	item, ok := m.routes[route]
+	if !ok {
+		item = &RouteMetrics{Route: route}
+		m.routes[route] = item
+	}
+	item.Requests++
+	for _, metric := range metrics {
+		switch metric {
+		case METRIC_SERVER_ERROR:
+			item.ServerErrors++
+		case METRIC_PANIC:
+			item.Panics++
+		case METRIC_ERROR:
+			item.Errors++
+		}
+	}
+	alerts := m.alerts
+	m.mu.Unlock()
+	if len(metrics) == 0 {
+		return
+	}
+	now := time.Now()
+	for _, a := range alerts {
+		for _, metric := range metrics {
+			if a.rule.Metric == metric && (a.rule.Route == "" || a.rule.Route == route) {
+				a.add(now, route, r)
+			}
+		}
+	}
+}
+
+// add adds an event at <now>, and calls the hook if the count of events within the window reaches the threshold.
+func (a *alert) add(now time.Time, route string, r *Request) {
+	a.mu.Lock()
+	// Removing the events out of the window.
+	start := 0
+	for a.rule.Window > 0 && start < len(a.times) && now.Sub(a.times[start]) > a.rule.Window {
+		start++
+	}
+	a.times = append(a.times[:0], a.times[start:]...)
+	a.times = append(a.times, now)
+	if len(a.times) < a.rule.Threshold {
+		a.mu.Unlock()
+		return
+	}
+	a.times = a.times[:0]
+	a.mu.Unlock()
+	go a.hook.OnAlert(&AlertEvent{
+		Rule:     a.rule,
+		Route:    route,
+		Uri:      r.URL.String(),
+		ClientIp: r.GetClientIp(),
+		Count:    a.rule.Threshold,
+		Time:     now,
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+// 中间件管理.
+
+package ghttp
+
+import (
+	"github.com/gf/g/os/glog"
+)
+
+// 请求的中间件流程控制对象，中间件中通过 r.Middleware.Next() 执行后续的中间件及服务方法，
+// 中间件不调用Next时后续的中间件及服务方法都不会被执行(例如鉴权失败时)。
+type Middleware struct {
+	request  *Request      // 关联的请求对象
+	handlers []HandlerFunc // 请求需要执行的中间件列表(全局中间件在前，分组中间件在后)
+	index    int           // 下一个需要执行的中间件索引
+	served   bool          // 服务方法是否已经执行
+	serve    func()        // 中间件执行完毕后的服务方法(静态文件/回调函数/执行对象/控制器)
+}
+
+// 注册全局中间件，对所有请求生效(包括静态文件及未匹配路由的请求)，按照注册顺序执行。
+func (s *Server) Use(handlers ...HandlerFunc) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.middleware = append(s.middleware, handlers...)
+}
+
+// 注册分组中间件，对之后通过该分组注册的路由生效，在全局中间件之后按照注册顺序执行。
+func (g *RouterGroup) Middleware(handlers ...HandlerFunc) *RouterGroup {
+	g.middleware = append(g.middleware, handlers...)
+	return g
+}
+
+// 执行下一个中间件，所有中间件执行完毕后执行服务方法，请求已退出(Exit)时不再继续执行。
+func (m *Middleware) Next() {
+	if m == nil || m.request.IsExited() {
+		return
+	}
+	if m.index < len(m.handlers) {
+		handler := m.handlers[m.index]
+		m.index++
+		m.request.Server.niceCallFunc(func() {
+			handler(m.request)
+		})
+		return
+	}
+	if !m.served && m.serve != nil {
+		m.served = true
+		m.serve()
+	}
+}
+
+// 获取请求需要执行的中间件列表，handler为匹配到的路由项(可能为空)
+func (s *Server) getMiddleware(handler *handlerItem) []HandlerFunc {
+	if handler == nil || len(handler.chain) == 0 {
+		return s.middleware
+	}
+	handlers := make([]HandlerFunc, 0, len(s.middleware)+len(handler.chain))
+	handlers = append(handlers, s.middleware...)
+	return append(handlers, handler.chain...)
+}
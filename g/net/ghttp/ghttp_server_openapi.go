@@ -0,0 +1,601 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+// OpenAPI document generation from registered routes and Swagger UI.
+
+package ghttp
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gf/g/os/glog"
+	"github.com/gf/g/os/gtime"
+	"github.com/gf/g/text/gregex"
+	"github.com/gf/g/util/gconv"
+)
+
+const (
+	gOPENAPI_DEFAULT_PATH    = "/swagger"      // Default path serving the Swagger UI.
+	gOPENAPI_DOCUMENT_NAME   = "/openapi.json" // Path of the document relative to the Swagger UI path.
+	gOPENAPI_VERSION         = "3.0.0"         // Version of the generated OpenAPI document.
+	gOPENAPI_DEFAULT_TITLE   = "API Reference" // Default title of the document.
+	gOPENAPI_DEFAULT_VERSION = "1.0.0"         // Default version of the API.
+)
+
+var (
+	// Path parameters of route rules, eg: :name, *any and {field}.
+	openApiPathParamRegex = regexp.MustCompile(`[:\*](\w+)|\{(\w+)\}`)
+	// Names of components schemas, only letters, numbers and "._-" are allowed.
+	openApiSchemaNameRegex = regexp.MustCompile(`[^\w\.\-]+`)
+)
+
+// OpenApiConfig is the configuration for the generated OpenAPI document.
+type OpenApiConfig struct {
+	Title       string // Title of the API, default is "API Reference".
+	Version     string // Version of the API, default is "1.0.0".
+	Description string // Description of the API.
+	// Envelope wraps the response schemas in the standard JSON response
+	// {code,message,data}, for handlers responding with WriteJsonOk/WriteJsonResult.
+	Envelope bool
+}
+
+// OpenApiDoc is the annotation of a route for the generated OpenAPI document.
+//
+// The <Request> and <Response> are struct objects (or pointers to them) describing
+// the request parameters and the JSON response body. The request parameter names
+// are taken from "gconv" or "json" tags, the response property names from "json" tags,
+// and the "description" tag is used as the description of the parameter or property.
+// The "valid"/"gvalid" validation rules are converted to schema constraints,
+// eg: required, length, between, in, email and regex.
+type OpenApiDoc struct {
+	Summary     string      // Short summary of the operation.
+	Description string      // Verbose description of the operation.
+	Tags        []string    // Tags for grouping operations in the Swagger UI.
+	Deprecated  bool        // Marks the operation as deprecated.
+	Request     interface{} // Request parameters struct.
+	Response    interface{} // Response body struct.
+}
+
+// openApi holds the OpenAPI configuration and route annotations of a server.
+type openApi struct {
+	uri    string                // Path serving the Swagger UI, empty if not enabled.
+	config OpenApiConfig         // Document configuration.
+	docs   map[string]OpenApiDoc // Route annotations, key is "METHOD:uri@domain".
+}
+
+// openApiBuilder builds one OpenAPI document.
+type openApiBuilder struct {
+	schemas map[string]interface{}  // Components schemas.
+	names   map[reflect.Type]string // Component names of named struct types.
+}
+
+// getOpenApi returns the OpenAPI object of the server, creating it if necessary.
+func (s *Server) getOpenApi() *openApi {
+	if s.openapi == nil {
+		s.openapi = &openApi{
+			docs: make(map[string]OpenApiDoc),
+		}
+	}
+	return s.openapi
+}
+
+// EnableOpenApi serves the OpenAPI document generated from registered routes at
+// <pattern>/openapi.json and the Swagger UI at <pattern>, the default <pattern> is "/swagger".
+// The document is generated on each request, so routes registered after it are also included.
+func (s *Server) EnableOpenApi(pattern ...string) {
+	p := gOPENAPI_DEFAULT_PATH
+	if len(pattern) > 0 {
+		p = pattern[0]
+	}
+	_, _, uri, _ := s.parsePattern(p)
+	uri = strings.TrimRight(uri, "/")
+	s.getOpenApi().uri = uri
+	s.BindHandler(uri, s.serveOpenApiUI)
+	s.BindHandler(uri+gOPENAPI_DOCUMENT_NAME, func(r *Request) {
+		r.Response.WriteJson(s.GetOpenApi())
+	})
+}
+
+// SetOpenApiConfig sets the configuration for the generated OpenAPI document.
+func (s *Server) SetOpenApiConfig(config OpenApiConfig) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.getOpenApi().config = config
+}
+
+// BindOpenApiDoc annotates routes matching <pattern> for the generated OpenAPI document,
+// the <pattern> is the same as the one used in route registering, eg: POST:/user/{id}.
+// If the route is registered for all methods, the operations are documented for the methods
+// of its annotations, or GET and POST if it is annotated without method.
+func (s *Server) BindOpenApiDoc(pattern string, doc OpenApiDoc) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	domain, method, uri, err := s.parsePattern(pattern)
+	if err != nil {
+		glog.Error("invalid pattern:", pattern, err)
+		return
+	}
+	s.getOpenApi().docs[s.serveHandlerKey(method, uri, domain)] = doc
+}
+
+// GetOpenApi generates and returns the OpenAPI document of registered routes.
+func (s *Server) GetOpenApi() map[string]interface{} {
+	o := s.getOpenApi()
+	b := &openApiBuilder{
+		schemas: make(map[string]interface{}),
+		names:   make(map[reflect.Type]string),
+	}
+	info := map[string]interface{}{
+		"title":   o.config.Title,
+		"version": o.config.Version,
+	}
+	if o.config.Title == "" {
+		info["title"] = gOPENAPI_DEFAULT_TITLE
+	}
+	if o.config.Version == "" {
+		info["version"] = gOPENAPI_DEFAULT_VERSION
+	}
+	if o.config.Description != "" {
+		info["description"] = o.config.Description
+	}
+	paths := make(map[string]interface{})
+	for key, items := range s.routesMap {
+		array, _ := gregex.MatchString(`(.*?)%([A-Z]+):(.+)@(.+)`, key)
+		if len(array) < 5 || array[1] != "" || len(items) == 0 {
+			continue
+		}
+		method, uri, domain := array[2], array[3], array[4]
+		// The Swagger UI and document themselves are not documented.
+		if o.uri != "" && (uri == o.uri || uri == o.uri+gOPENAPI_DOCUMENT_NAME) {
+			continue
+		}
+		path := openApiPathParamRegex.ReplaceAllString(uri, "{$1$2}")
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+		for _, m := range s.openApiMethodsOf(method, uri, domain) {
+			doc, ok := o.docs[s.serveHandlerKey(m, uri, domain)]
+			if !ok {
+				doc = o.docs[s.serveHandlerKey(gDEFAULT_METHOD, uri, domain)]
+			}
+			item[strings.ToLower(m)] = b.operation(m, uri, doc, o.config.Envelope)
+		}
+	}
+	document := map[string]interface{}{
+		"openapi": gOPENAPI_VERSION,
+		"info":    info,
+		"paths":   paths,
+	}
+	if len(b.schemas) > 0 {
+		document["components"] = map[string]interface{}{
+			"schemas": b.schemas,
+		}
+	}
+	return document
+}
+
+// openApiMethodsOf returns the HTTP methods documented for a route.
+func (s *Server) openApiMethodsOf(method, uri, domain string) []string {
+	if method != gDEFAULT_METHOD {
+		return []string{method}
+	}
+	methods := make([]string, 0)
+	for _, m := range strings.Split(HTTP_METHODS, ",") {
+		if _, ok := s.getOpenApi().docs[s.serveHandlerKey(m, uri, domain)]; ok {
+			methods = append(methods, m)
+		}
+	}
+	if len(methods) == 0 {
+		methods = []string{"GET", "POST"}
+	}
+	return methods
+}
+
+// serveOpenApiUI serves the Swagger UI page loading the generated document.
+func (s *Server) serveOpenApiUI(r *Request) {
+	title := s.getOpenApi().config.Title
+	if title == "" {
+		title = gOPENAPI_DEFAULT_TITLE
+	}
+	r.Response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	r.Response.Write(fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <title>%s</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@3/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@3/swagger-ui-bundle.js"></script>
+    <script>
+        window.ui = SwaggerUIBundle({url: "%s", dom_id: "#swagger-ui"});
+    </script>
+</body>
+</html>`, title, s.getOpenApi().uri+gOPENAPI_DOCUMENT_NAME))
+}
+
+// operation builds the operation object of a route.
+func (b *openApiBuilder) operation(method, uri string, doc OpenApiDoc, envelope bool) map[string]interface{} {
+	operation := make(map[string]interface{})
+	if doc.Summary != "" {
+		operation["summary"] = doc.Summary
+	}
+	if doc.Description != "" {
+		operation["description"] = doc.Description
+	}
+	if len(doc.Tags) > 0 {
+		operation["tags"] = doc.Tags
+	}
+	if doc.Deprecated {
+		operation["deprecated"] = true
+	}
+	// Path parameters.
+	fields := b.fieldsOf(doc.Request, "gconv", "json")
+	pathNames := make(map[string]bool)
+	parameters := make([]interface{}, 0)
+	for _, match := range openApiPathParamRegex.FindAllStringSubmatch(uri, -1) {
+		name := match[1] + match[2]
+		pathNames[name] = true
+		schema := map[string]interface{}{"type": "string"}
+		for _, f := range fields {
+			if f.name == name {
+				schema = f.schema
+				break
+			}
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   schema,
+		})
+	}
+	// Request parameters of query string or body.
+	body := false
+	switch method {
+	case "POST", "PUT", "PATCH":
+		body = true
+	}
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+	upload := false
+	for _, f := range fields {
+		if pathNames[f.name] {
+			continue
+		}
+		if body {
+			properties[f.name] = f.schema
+			if f.required {
+				required = append(required, f.name)
+			}
+			upload = upload || f.upload
+			continue
+		}
+		parameter := map[string]interface{}{
+			"name":   f.name,
+			"in":     "query",
+			"schema": f.schema,
+		}
+		if f.required {
+			parameter["required"] = true
+		}
+		if f.description != "" {
+			parameter["description"] = f.description
+		}
+		parameters = append(parameters, parameter)
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if len(properties) > 0 {
+		schema := map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		content := make(map[string]interface{})
+		if upload {
+			content["multipart/form-data"] = map[string]interface{}{"schema": schema}
+		} else {
+			content["application/json"] = map[string]interface{}{"schema": schema}
+			content["application/x-www-form-urlencoded"] = map[string]interface{}{"schema": schema}
+		}
+		operation["requestBody"] = map[string]interface{}{
+			"required": len(required) > 0,
+			"content":  content,
+		}
+	}
+	// Response.
+	response := map[string]interface{}{
+		"description": "OK",
+	}
+	var schema map[string]interface{}
+	if doc.Response != nil {
+		schema = b.schemaOf(reflect.TypeOf(doc.Response))
+	}
+	if envelope {
+		data := schema
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		schema = map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":    map[string]interface{}{"type": "integer"},
+				"message": map[string]interface{}{"type": "string"},
+				"data":    data,
+			},
+		}
+	}
+	if schema != nil {
+		response["content"] = map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		}
+	}
+	operation["responses"] = map[string]interface{}{
+		"200": response,
+	}
+	return operation
+}
+
+// openApiField is a parsed struct field for parameters or properties.
+type openApiField struct {
+	name        string                 // Parameter or property name.
+	description string                 // Description from "description" tag.
+	required    bool                   // Whether it is required by validation rules.
+	upload      bool                   // Whether it is an uploading file.
+	schema      map[string]interface{} // Schema of the field.
+}
+
+// fieldsOf parses the exported fields of struct <object>, the field names are
+// taken from the first non-empty tag of <tags>, embedded structs are flattened.
+func (b *openApiBuilder) fieldsOf(object interface{}, tags ...string) []openApiField {
+	if object == nil {
+		return nil
+	}
+	t := reflect.TypeOf(object)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return b.structFields(t, tags...)
+}
+
+// structFields parses the exported fields of struct type <t>.
+func (b *openApiBuilder) structFields(t reflect.Type, tags ...string) []openApiField {
+	fields := make([]openApiField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := ""
+		for _, tag := range tags {
+			if name = strings.Split(field.Tag.Get(tag), ",")[0]; name != "" {
+				break
+			}
+		}
+		if name == "-" {
+			continue
+		}
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, b.structFields(ft, tags...)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		f := openApiField{
+			name:        name,
+			description: field.Tag.Get("description"),
+			upload:      ft == reflect.TypeOf(UploadFile{}) || (ft.Kind() == reflect.Slice && b.isUploadFile(ft.Elem())),
+			schema:      b.schemaOf(field.Type),
+		}
+		if f.description != "" {
+			f.schema["description"] = f.description
+		}
+		rule := field.Tag.Get("valid")
+		if rule == "" {
+			rule = field.Tag.Get("gvalid")
+		}
+		if rule != "" {
+			f.required = b.applyRules(f.schema, rule)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// isUploadFile checks whether <t> is UploadFile or pointer to it.
+func (b *openApiBuilder) isUploadFile(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == reflect.TypeOf(UploadFile{})
+}
+
+// schemaOf returns the schema of type <t>, named struct types are added to
+// the components schemas and referenced with "$ref".
+func (b *openApiBuilder) schemaOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case reflect.TypeOf(time.Time{}), reflect.TypeOf(gtime.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(UploadFile{}):
+		return map[string]interface{}{"type": "string", "format": "binary"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name, ok := b.names[t]
+		if !ok {
+			name = b.schemaName(t)
+			b.names[t] = name
+			// Placeholder for recursive types.
+			b.schemas[name] = map[string]interface{}{}
+			b.schemas[name] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema returns the object schema of struct type <t> using "json" tags.
+func (b *openApiBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+	for _, f := range b.structFields(t, "json") {
+		properties[f.name] = f.schema
+		if f.required {
+			required = append(required, f.name)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// schemaName returns a unique component name for named struct type <t>,
+// the package name is prefixed if the type name is already used.
+func (b *openApiBuilder) schemaName(t reflect.Type) string {
+	name := openApiSchemaNameRegex.ReplaceAllString(t.Name(), "_")
+	if _, ok := b.schemas[name]; !ok {
+		return name
+	}
+	name = openApiSchemaNameRegex.ReplaceAllString(t.String(), "_")
+	for i := 2; ; i++ {
+		if _, ok := b.schemas[name]; !ok {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", strings.TrimRight(name, "0123456789"), i)
+	}
+}
+
+// applyRules converts validation rules of gvalid tag <tag> to constraints of <schema>,
+// and returns whether the field is required. The tag format is: [name@]rules[#messages].
+func (b *openApiBuilder) applyRules(schema map[string]interface{}, tag string) (required bool) {
+	if i := strings.Index(tag, "#"); i >= 0 {
+		tag = tag[:i]
+	}
+	if i := strings.Index(tag, "@"); i >= 0 {
+		tag = tag[i+1:]
+	}
+	// Constraints are not applied to referenced schemas.
+	_, isRef := schema["$ref"]
+	for _, item := range strings.Split(tag, "|") {
+		array := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		rule, value := array[0], ""
+		if len(array) > 1 {
+			value = array[1]
+		}
+		if rule == "required" {
+			required = true
+		}
+		if isRef {
+			continue
+		}
+		values := strings.Split(value, ",")
+		switch rule {
+		case "length":
+			if len(values) == 2 {
+				schema["minLength"] = gconv.Int(strings.TrimSpace(values[0]))
+				schema["maxLength"] = gconv.Int(strings.TrimSpace(values[1]))
+			}
+		case "min-length":
+			schema["minLength"] = gconv.Int(strings.TrimSpace(value))
+		case "max-length":
+			schema["maxLength"] = gconv.Int(strings.TrimSpace(value))
+		case "between":
+			if len(values) == 2 {
+				schema["minimum"] = gconv.Float64(strings.TrimSpace(values[0]))
+				schema["maximum"] = gconv.Float64(strings.TrimSpace(values[1]))
+			}
+		case "min":
+			schema["minimum"] = gconv.Float64(strings.TrimSpace(value))
+		case "max":
+			schema["maximum"] = gconv.Float64(strings.TrimSpace(value))
+		case "in":
+			enum := make([]interface{}, 0, len(values))
+			for _, v := range values {
+				switch schema["type"] {
+				case "integer":
+					enum = append(enum, gconv.Int64(strings.TrimSpace(v)))
+				case "number":
+					enum = append(enum, gconv.Float64(strings.TrimSpace(v)))
+				default:
+					enum = append(enum, strings.TrimSpace(v))
+				}
+			}
+			schema["enum"] = enum
+		case "regex":
+			schema["pattern"] = value
+		case "integer":
+			schema["type"] = "integer"
+		case "float":
+			schema["type"] = "number"
+		case "boolean":
+			schema["type"] = "boolean"
+		case "email":
+			schema["format"] = "email"
+		case "url":
+			schema["format"] = "uri"
+		case "domain":
+			schema["format"] = "hostname"
+		case "date":
+			schema["format"] = "date"
+		case "ip", "ipv4", "ipv6":
+			schema["format"] = rule
+		}
+	}
+	return
+}
@@ -0,0 +1,346 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+// OpenAPI contract validation for requests and responses.
+
+package ghttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gf/g/encoding/gjson"
+	"github.com/gf/g/internal/cmdenv"
+	"github.com/gf/g/util/gconv"
+)
+
+const (
+	// Command line or environment key to switch OpenAPI validation on/off explicitly,
+	// eg: --gf.ghttp.openapi.validate=true or GF_GHTTP_OPENAPI_VALIDATE=true.
+	gOPENAPI_VALIDATE_KEY = "gf.ghttp.openapi.validate"
+	// Command line or environment key of running mode, eg: --gf.mode=dev or GF_MODE=test,
+	// the OpenAPI validation is enabled in default only in development and testing mode.
+	gOPENAPI_MODE_KEY = "gf.mode"
+)
+
+// OpenApiValidator validates requests and responses against an OpenAPI 3 document.
+// It is designed for development and testing stage to catch contract drift early,
+// so it's active only if the running mode "gf.mode" is development or testing,
+// or it's enabled explicitly with command option or environment variable "gf.ghttp.openapi.validate".
+type OpenApiValidator struct {
+	spec       *gjson.Json         // The OpenAPI document.
+	operations []*openApiOperation // Compiled operations from <paths> of the document.
+	// Response validation is enabled in default, set it false to validate requests only.
+	ValidateResponse bool
+}
+
+// openApiOperation is a compiled operation of the OpenAPI document.
+type openApiOperation struct {
+	method  string                 // Lowercase HTTP method.
+	path    string                 // Path template, eg: /user/{id}.
+	regex   *regexp.Regexp         // Regular expression compiled from path template.
+	names   []string               // Names of path parameters in order.
+	content map[string]interface{} // Operation object.
+}
+
+// NewOpenApiValidator creates and returns a validator with given OpenAPI document <spec>,
+// which can be a file path, JSON/YAML content, or a *gjson.Json object.
+func NewOpenApiValidator(spec interface{}) (*OpenApiValidator, error) {
+	var j *gjson.Json
+	switch v := spec.(type) {
+	case *gjson.Json:
+		j = v
+	case string:
+		if strings.ContainsAny(v, "{:\n") {
+			if r, err := gjson.LoadContent(v); err != nil {
+				return nil, err
+			} else {
+				j = r
+			}
+		} else {
+			if r, err := gjson.Load(v); err != nil {
+				return nil, err
+			} else {
+				j = r
+			}
+		}
+	default:
+		if r, err := gjson.LoadContent(gconv.Bytes(spec)); err != nil {
+			return nil, err
+		} else {
+			j = r
+		}
+	}
+	if j == nil || j.GetMap("paths") == nil {
+		return nil, errors.New("invalid OpenAPI document: paths not found")
+	}
+	v := &OpenApiValidator{
+		spec:             j,
+		operations:       make([]*openApiOperation, 0),
+		ValidateResponse: true,
+	}
+	nameRegex := regexp.MustCompile(`\{([^/]+?)\}`)
+	quotedNameRegex := regexp.MustCompile(`\\\{[^/]+?\\\}`)
+	for path, item := range j.GetMap("paths") {
+		names := make([]string, 0)
+		for _, match := range nameRegex.FindAllStringSubmatch(path, -1) {
+			names = append(names, match[1])
+		}
+		// The braces are escaped by QuoteMeta, so it replaces the escaped form.
+		rule := quotedNameRegex.ReplaceAllString(regexp.QuoteMeta(path), `([^/]+)`)
+		regex, err := regexp.Compile("^" + rule + "$")
+		if err != nil {
+			return nil, err
+		}
+		for method, operation := range gconv.Map(item) {
+			if _, ok := methodsMap[strings.ToUpper(method)]; !ok {
+				continue
+			}
+			v.operations = append(v.operations, &openApiOperation{
+				method:  strings.ToLower(method),
+				path:    path,
+				regex:   regex,
+				names:   names,
+				content: gconv.Map(operation),
+			})
+		}
+	}
+	return v, nil
+}
+
+// search returns the operation and the parsed path parameters for given request.
+func (v *OpenApiValidator) search(r *Request) (*openApiOperation, map[string]string) {
+	method := strings.ToLower(r.Method)
+	for _, op := range v.operations {
+		if op.method != method {
+			continue
+		}
+		if match := op.regex.FindStringSubmatch(r.URL.Path); len(match) > 0 {
+			values := make(map[string]string, len(op.names))
+			for i, name := range op.names {
+				if i+1 < len(match) {
+					values[name] = match[i+1]
+				}
+			}
+			return op, values
+		}
+	}
+	return nil, nil
+}
+
+// CheckRequest validates the parameters and JSON body of request <r>.
+// It returns nil if the request has no matched operation in the document.
+func (v *OpenApiValidator) CheckRequest(r *Request) error {
+	op, pathValues := v.search(r)
+	if op == nil {
+		return nil
+	}
+	for _, p := range gconv.Interfaces(op.content["parameters"]) {
+		param := v.resolve(gconv.Map(p))
+		name := gconv.String(param["name"])
+		value := ""
+		found := false
+		switch gconv.String(param["in"]) {
+		case "path":
+			value, found = pathValues[name]
+		case "query":
+			if values, ok := r.URL.Query()[name]; ok && len(values) > 0 {
+				value, found = values[0], true
+			}
+		case "header":
+			if values, ok := r.Header[http.CanonicalHeaderKey(name)]; ok && len(values) > 0 {
+				value, found = values[0], true
+			}
+		case "cookie":
+			if c, err := r.Request.Cookie(name); err == nil {
+				value, found = c.Value, true
+			}
+		}
+		if !found {
+			if gconv.Bool(param["required"]) {
+				return fmt.Errorf(`required parameter "%s" in %s is missing`, name, param["in"])
+			}
+			continue
+		}
+		if schema := gconv.Map(param["schema"]); schema != nil {
+			if err := v.checkSchema(name, v.resolve(schema), v.parseParamValue(value, schema)); err != nil {
+				return err
+			}
+		}
+	}
+	if body := v.resolve(gconv.Map(op.content["requestBody"])); body != nil {
+		raw := r.GetRaw()
+		if len(raw) == 0 {
+			if gconv.Bool(body["required"]) {
+				return errors.New("request body is required")
+			}
+			return nil
+		}
+		if schema := v.jsonSchemaOf(body); schema != nil && v.isJsonContent(r.Header.Get("Content-Type")) {
+			var data interface{}
+			if err := gjson.DecodeTo(raw, &data); err != nil {
+				return fmt.Errorf("request body is not valid JSON: %v", err)
+			}
+			if err := v.checkSchema("body", schema, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckResponse validates the buffered JSON response of request <r>
+// against the response object of its status code.
+func (v *OpenApiValidator) CheckResponse(r *Request) error {
+	op, _ := v.search(r)
+	if op == nil {
+		return nil
+	}
+	status := r.Response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	responses := gconv.Map(op.content["responses"])
+	if len(responses) == 0 {
+		return nil
+	}
+	response, ok := responses[gconv.String(status)]
+	if !ok {
+		// Range definitions like "2XX".
+		response, ok = responses[fmt.Sprintf("%dXX", status/100)]
+	}
+	if !ok {
+		if response, ok = responses["default"]; !ok {
+			return fmt.Errorf(`response status %d is not documented`, status)
+		}
+	}
+	schema := v.jsonSchemaOf(v.resolve(gconv.Map(response)))
+	if schema == nil || !v.isJsonContent(r.Response.Header().Get("Content-Type")) {
+		return nil
+	}
+	var data interface{}
+	if err := gjson.DecodeTo(r.Response.Buffer(), &data); err != nil {
+		return fmt.Errorf("response body is not valid JSON: %v", err)
+	}
+	return v.checkSchema("response", schema, data)
+}
+
+// BindOpenApiValidator binds the validator <v> to routes matching <pattern>
+// using BeforeServe/BeforeOutput hooks. Requests failing validation are answered
+// with status 400, responses failing validation are replaced with status 500,
+// both carrying the validation error message.
+//
+// Note that the validation is bound only in development and testing mode,
+// see OpenApiValidator, it does nothing in other modes including production.
+func (s *Server) BindOpenApiValidator(pattern string, v *OpenApiValidator) {
+	if !openApiValidateEnabled() {
+		return
+	}
+	s.BindHookHandler(pattern, HOOK_BEFORE_SERVE, func(r *Request) {
+		if err := v.CheckRequest(r); err != nil {
+			r.Response.ClearBuffer()
+			r.Response.WriteStatus(http.StatusBadRequest, err.Error())
+			r.ExitAll()
+		}
+	})
+	s.BindHookHandler(pattern, HOOK_BEFORE_OUTPUT, func(r *Request) {
+		if !v.ValidateResponse {
+			return
+		}
+		if err := v.CheckResponse(r); err != nil {
+			s.handleErrorLog(fmt.Sprintf(`OpenAPI response validation failed: %v`, err), r)
+			r.Response.ClearBuffer()
+			r.Response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			r.Response.Write(err.Error())
+			r.Response.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+// isJsonContent checks whether given content type is a JSON type.
+func (v *OpenApiValidator) isJsonContent(contentType string) bool {
+	return contentType == "" || strings.Contains(contentType, "json")
+}
+
+// jsonSchemaOf returns the JSON schema of a request body or response object.
+func (v *OpenApiValidator) jsonSchemaOf(object map[string]interface{}) map[string]interface{} {
+	for contentType, media := range gconv.Map(object["content"]) {
+		if strings.Contains(contentType, "json") {
+			return v.resolve(gconv.Map(gconv.Map(media)["schema"]))
+		}
+	}
+	return nil
+}
+
+// resolve resolves local reference "$ref" of <object>, eg: #/components/schemas/User.
+func (v *OpenApiValidator) resolve(object map[string]interface{}) map[string]interface{} {
+	for i := 0; object != nil && i < 32; i++ {
+		ref, ok := object["$ref"]
+		if !ok {
+			break
+		}
+		path := strings.TrimPrefix(gconv.String(ref), "#/")
+		object = v.spec.GetMap(strings.Replace(path, "/", ".", -1))
+	}
+	return object
+}
+
+// parseParamValue converts string parameter <value> according to the type of <schema>.
+func (v *OpenApiValidator) parseParamValue(value string, schema map[string]interface{}) interface{} {
+	switch gconv.String(schema["type"]) {
+	case "integer", "number":
+		return json.Number(value)
+	case "boolean":
+		switch value {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	return value
+}
+
+// checkSchema validates <value> against JSON <schema> using gjson.ValidateSchema,
+// the <name> is used as the prefix of value pattern in error message.
+func (v *OpenApiValidator) checkSchema(name string, schema map[string]interface{}, value interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	// The schema is merged into the document root for resolving references,
+	// eg: #/components/schemas/User.
+	document := make(map[string]interface{})
+	for k, item := range v.spec.ToMap() {
+		document[k] = item
+	}
+	for k, item := range schema {
+		document[k] = item
+	}
+	err := gjson.ValidateSchema(value, gjson.New(document))
+	if e, ok := err.(*gjson.SchemaError); ok {
+		if e.Pattern != "" {
+			name += "." + e.Pattern
+		}
+		return fmt.Errorf(`"%s" %s`, name, e.Reason)
+	}
+	return err
+}
+
+// openApiValidateEnabled checks whether the OpenAPI validation is enabled,
+// the explicit switch "gf.ghttp.openapi.validate" takes precedence over the running mode "gf.mode".
+func openApiValidateEnabled() bool {
+	if v := cmdenv.Get(gOPENAPI_VALIDATE_KEY); !v.IsNil() {
+		return v.Bool()
+	}
+	switch strings.ToLower(cmdenv.Get(gOPENAPI_MODE_KEY).String()) {
+	case "dev", "develop", "development", "test", "testing":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+// Request rate limiting with per-client quotas.
+
+package ghttp
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Interval for removing the idle buckets of rate limiter.
+	gRATE_LIMIT_SWEEP_INTERVAL = time.Minute
+)
+
+// RateLimitQuota is the request quota of a client.
+type RateLimitQuota struct {
+	Key       string // Identity of the client, eg: API key, the remote IP is used if it is empty.
+	PerMinute int    // Requests allowed per minute, the client is not limited if it is not greater than 0.
+	Burst     int    // Max requests allowed in a burst, which is PerMinute in default.
+}
+
+// RateLimiter limits the request rate of clients using token buckets.
+// Each client has a bucket of <Burst> tokens, which is refilled at <PerMinute> tokens per minute,
+// and each request takes one token, the request is rejected with status 429 if the bucket is empty.
+//
+// The quota of each request is resolved by the quota resolver, which makes tiered limits
+// possible, eg: resolving quota by API key for authenticated clients.
+//
+// The clients are identified by the remote IP of the connection in default, as the headers
+// like X-Real-IP and X-Forwarded-For can be forged by clients to bypass the limiting.
+// The headers are only used for requests from the trusted proxies, see SetTrustedProxies.
+type RateLimiter struct {
+	mu        sync.Mutex
+	quota     RateLimitQuota                   // Default quota.
+	resolver  func(r *Request) *RateLimitQuota // Custom quota resolver.
+	proxies   []*net.IPNet                     // Trusted proxies.
+	buckets   map[string]*rateLimitBucket      // Client key to token bucket.
+	lastSweep time.Time                        // Last time of removing idle buckets.
+}
+
+// rateLimitBucket is the token bucket of a client.
+type rateLimitBucket struct {
+	tokens   float64   // Available tokens.
+	capacity float64   // Max tokens.
+	rate     float64   // Tokens refilled per second.
+	updated  time.Time // Last time of refilling.
+}
+
+// RateLimitResult is the result of taking token from rate limiter.
+type RateLimitResult struct {
+	Allowed   bool          // Whether the request is allowed.
+	Limit     int           // Max requests allowed in a burst, which is 0 if the client is not limited.
+	Remaining int           // Remaining requests allowed.
+	Reset     time.Duration // Duration after which the bucket is refilled to full.
+	Retry     time.Duration // Duration after which the next request is allowed, which is 0 if allowed.
+}
+
+// NewRateLimiter creates and returns a rate limiter with default quota,
+// which allows <perMinute> requests per minute with burst <burst> for each remote IP.
+func NewRateLimiter(perMinute int, burst ...int) *RateLimiter {
+	l := &RateLimiter{
+		quota:     RateLimitQuota{PerMinute: perMinute},
+		buckets:   make(map[string]*rateLimitBucket),
+		lastSweep: time.Now(),
+	}
+	if len(burst) > 0 {
+		l.quota.Burst = burst[0]
+	}
+	return l
+}
+
+// SetQuotaResolver sets the quota resolver for requests, the default quota is used if it returns nil.
+// The resolver can return a quota with PerMinute 0 to bypass the limiting, eg: for internal clients.
+func (l *RateLimiter) SetQuotaResolver(resolver func(r *Request) *RateLimitQuota) {
+	l.mu.Lock()
+	l.resolver = resolver
+	l.mu.Unlock()
+}
+
+// SetTrustedProxies sets the trusted proxies in IP or CIDR format, eg: "10.0.0.1", "10.0.0.0/8".
+// The client IP of the requests from trusted proxies is the last IP in header X-Forwarded-For
+// that is not a trusted proxy, or the IP in header X-Real-IP if there's no X-Forwarded-For.
+func (l *RateLimiter) SetTrustedProxies(proxies ...string) error {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return err
+		}
+		nets = append(nets, ipNet)
+	}
+	l.mu.Lock()
+	l.proxies = nets
+	l.mu.Unlock()
+	return nil
+}
+
+// Take takes a token for request <r>, and returns the limiting result.
+func (l *RateLimiter) Take(r *Request) RateLimitResult {
+	l.mu.Lock()
+	resolver := l.resolver
+	proxies := l.proxies
+	l.mu.Unlock()
+	quota := (*RateLimitQuota)(nil)
+	if resolver != nil {
+		quota = resolver(r)
+	}
+	if quota == nil {
+		quota = &l.quota
+	}
+	key := quota.Key
+	if key == "" {
+		key = clientIpOfProxies(r, proxies)
+	}
+	return l.TakeKey(key, quota.PerMinute, quota.Burst)
+}
+
+// clientIpOfProxies returns the client IP of request <r>, which trusts the forwarding headers
+// only if the request is from one of the <proxies>.
+func clientIpOfProxies(r *Request, proxies []*net.IPNet) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip, proxies) {
+		return ip
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		array := strings.Split(forwarded, ",")
+		for i := len(array) - 1; i >= 0; i-- {
+			ip = strings.TrimSpace(array[i])
+			if !isTrustedProxy(ip, proxies) {
+				break
+			}
+		}
+		return ip
+	}
+	if realIp := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIp != "" {
+		return realIp
+	}
+	return ip
+}
+
+// isTrustedProxy checks whether <ip> is one of the trusted <proxies>.
+func isTrustedProxy(ip string, proxies []*net.IPNet) bool {
+	if len(proxies) == 0 {
+		return false
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, proxy := range proxies {
+		if proxy.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeKey takes a token for client <key> with quota of <perMinute> requests per minute and <burst>,
+// and returns the limiting result.
+func (l *RateLimiter) TakeKey(key string, perMinute int, burst int) RateLimitResult {
+	if perMinute <= 0 {
+		return RateLimitResult{Allowed: true}
+	}
+	if burst <= 0 {
+		burst = perMinute
+	}
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rateLimitBucket{
+			tokens:  float64(burst),
+			updated: now,
+		}
+		l.buckets[key] = b
+	}
+	// The quota may be changed, eg: the client is upgraded to a higher tier.
+	b.capacity = float64(burst)
+	b.rate = float64(perMinute) / 60
+	b.refill(now)
+	result := RateLimitResult{
+		Limit: burst,
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.Retry = durationOfSeconds((1 - b.tokens) / b.rate)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = durationOfSeconds((b.capacity - b.tokens) / b.rate)
+	return result
+}
+
+// refill refills the bucket with the tokens produced since last refilling.
+func (b *rateLimitBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.updated).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.updated = now
+}
+
+// sweep removes the buckets which are refilled to full, as they are the same as new buckets.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < gRATE_LIMIT_SWEEP_INTERVAL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*b.rate >= b.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// durationOfSeconds converts <seconds> to time.Duration.
+func durationOfSeconds(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// BindRateLimiter binds the rate limiter <l> to routes matching <pattern> using BeforeServe hook.
+// The headers X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset(seconds) are set
+// for limited clients, and the rejected requests are answered with status 429 and header Retry-After.
+func (s *Server) BindRateLimiter(pattern string, l *RateLimiter) {
+	s.BindHookHandler(pattern, HOOK_BEFORE_SERVE, func(r *Request) {
+		result := l.Take(r)
+		if result.Limit == 0 {
+			return
+		}
+		header := r.Response.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.Itoa(int(math.Ceil(result.Retry.Seconds()))))
+			r.Response.ClearBuffer()
+			r.Response.WriteStatus(http.StatusTooManyRequests)
+			r.ExitAll()
+		}
+	})
+}
@@ -97,6 +97,16 @@ func (s *Server) setHandler(pattern string, handler *handlerItem, hook ...string
 		Priority: strings.Count(uri[1:], "/"),
 	}
 	handler.router.RegRule, handler.router.RegNames = s.patternToRegRule(uri)
+	if s.groupOption != nil {
+		option := *s.groupOption
+		handler.option = &option
+		if option.CaseInsensitive {
+			s.caseInsensitive = true
+		}
+	}
+	if len(hookName) == 0 && len(s.groupMiddleware) > 0 {
+		handler.chain = append([]HandlerFunc(nil), s.groupMiddleware...)
+	}
 
 	// 动态注册，首先需要判断是否是动态注册，如果不是那么就没必要添加到动态注册记录变量中。
 	// 非叶节点为哈希表检索节点，按照URI注册的层级进行高效检索，直至到叶子链表节点；
@@ -17,9 +17,11 @@ import (
 
 // 分组路由对象
 type RouterGroup struct {
-	server *Server // Server
-	domain *Domain // Domain
-	prefix string  // URI前缀
+	server     *Server       // Server
+	domain     *Domain       // Domain
+	prefix     string        // URI前缀
+	option     *RouteOption  // 路由匹配选项
+	middleware []HandlerFunc // 分组中间件
 }
 
 // 分组路由批量绑定项
@@ -139,6 +141,24 @@ func (g *RouterGroup) bind(bindType string, pattern string, object interface{},
 			pattern = g.server.serveHandlerKey(method, g.prefix+"/"+strings.TrimLeft(path, "/"), domain)
 		}
 	}
+	server := g.server
+	if server == nil {
+		server = g.domain.s
+	}
+	// 设置分组的路由匹配选项
+	if g.option != nil {
+		server.groupOption = g.option
+		defer func() {
+			server.groupOption = nil
+		}()
+	}
+	// 设置分组的中间件
+	if len(g.middleware) > 0 {
+		server.groupMiddleware = g.middleware
+		defer func() {
+			server.groupMiddleware = nil
+		}()
+	}
 	methods := gconv.Strings(params)
 	// 判断是否事件回调注册
 	if _, ok := object.(HandlerFunc); ok && len(methods) > 0 {
@@ -138,8 +138,8 @@ func (s *Server) searchHookHandler(method, path, domain, hook string) []*handler
 			if _, ok := p.(map[string]interface{})["*list"]; ok {
 				lists = append(lists, p.(map[string]interface{})["*list"].(*list.List))
 			}
-			if _, ok := p.(map[string]interface{})[v]; ok {
-				p = p.(map[string]interface{})[v]
+			if node, ok := s.searchRouteNode(p.(map[string]interface{}), v); ok {
+				p = node
 				if k == len(array)-1 {
 					if _, ok := p.(map[string]interface{})["*list"]; ok {
 						lists = append(lists, p.(map[string]interface{})["*list"].(*list.List))
@@ -170,7 +170,7 @@ func (s *Server) searchHookHandler(method, path, domain, hook string) []*handler
 				// 动态匹配规则带有gDEFAULT_METHOD的情况，不会像静态规则那样直接解析为所有的HTTP METHOD存储
 				if strings.EqualFold(handler.router.Method, gDEFAULT_METHOD) || strings.EqualFold(handler.router.Method, method) {
 					// 注意当不带任何动态路由规则时，len(match) == 1
-					if match, err := gregex.MatchString(handler.router.RegRule, path); err == nil && len(match) > 0 {
+					if match, err := gregex.MatchString(s.getRouteRegRule(handler), path); err == nil && len(match) > 0 {
 						parsedItem := &handlerParsedItem{handler, nil}
 						// 如果需要query匹配，那么需要重新正则解析URL
 						if len(handler.router.RegNames) > 0 {
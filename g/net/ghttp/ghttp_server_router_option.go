@@ -0,0 +1,142 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+// 路由匹配选项.
+
+package ghttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gf/g/os/glog"
+)
+
+const (
+	TRAILING_SLASH_STRIP    = 0 // (默认)忽略URI末尾的"/"，例如：/user/ 与 /user 匹配相同的路由
+	TRAILING_SLASH_REDIRECT = 1 // URI末尾带有"/"(或者重复的"/")时重定向到规范的URI，例如：/user/ 重定向到 /user
+	TRAILING_SLASH_STRICT   = 2 // 严格匹配，URI末尾带有"/"时不匹配路由，例如：/user/ 不匹配 /user 的路由
+)
+
+// 路由匹配选项，可以通过Server设置，也可以通过分组路由设置(对分组中注册的路由生效)
+type RouteOption struct {
+	TrailingSlash   int  // URI末尾"/"的处理策略，默认为TRAILING_SLASH_STRIP
+	CaseInsensitive bool // 是否忽略URI大小写匹配路由，例如：/User/Info 匹配 /user/info 的路由
+	CleanPath       bool // 是否合并URI中重复的"/"，例如：/user//info 匹配 /user/info 的路由
+}
+
+// 设置路由匹配选项，对所有没有设置分组路由选项的路由生效
+func (s *Server) SetRouteOption(option RouteOption) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.RouteOption = option
+}
+
+// 设置分组路由的路由匹配选项，对之后通过该分组注册的路由生效
+func (g *RouterGroup) SetRouteOption(option RouteOption) *RouterGroup {
+	g.option = &option
+	return g
+}
+
+// 获取路由项生效的路由匹配选项
+func (s *Server) getRouteOption(handler *handlerItem) RouteOption {
+	if handler.option != nil {
+		return *handler.option
+	}
+	return s.config.RouteOption
+}
+
+// 判断是否有路由需要忽略大小写匹配
+func (s *Server) isCaseInsensitiveEnabled() bool {
+	return s.config.RouteOption.CaseInsensitive || s.caseInsensitive
+}
+
+// 获取路由项匹配使用的正则表达式
+func (s *Server) getRouteRegRule(handler *handlerItem) string {
+	if s.getRouteOption(handler).CaseInsensitive {
+		return "(?i)" + handler.router.RegRule
+	}
+	return handler.router.RegRule
+}
+
+// 在路由树节点中查找URI层级对应的子节点，找不到并且有路由需要忽略大小写匹配时，忽略大小写查找
+func (s *Server) searchRouteNode(node map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := node[key]; ok {
+		return v, true
+	}
+	if s.isCaseInsensitiveEnabled() {
+		for k, v := range node {
+			if !strings.HasPrefix(k, "*") && strings.EqualFold(k, key) {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// 合并URI中重复的"/"
+func cleanRoutePath(path string) string {
+	if !strings.Contains(path, "//") {
+		return path
+	}
+	b := make([]byte, 0, len(path))
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+		b = append(b, path[i])
+	}
+	return string(b)
+}
+
+// 按照路由匹配选项检索请求的路由项，rawPath为去掉末尾"/"之前的URI，
+// 返回nil表示没有匹配的路由项，返回的redirect不为空时表示需要重定向到该地址。
+func (s *Server) searchServeHandlerWithOption(r *Request, rawPath string) (parsedItem *handlerParsedItem, redirect string) {
+	path := r.URL.Path
+	parsedItem = s.getServeHandlerWithCache(r)
+	cleaned := false
+	if parsedItem == nil && strings.Contains(path, "//") {
+		// 合并重复的"/"后重新检索
+		r.URL.Path = cleanRoutePath(path)
+		if parsedItem = s.getServeHandlerWithCache(r); parsedItem == nil || !s.getRouteOption(parsedItem.handler).CleanPath {
+			r.URL.Path = path
+			return nil, ""
+		}
+		cleaned = true
+	}
+	if parsedItem == nil {
+		return nil, ""
+	}
+	option := s.getRouteOption(parsedItem.handler)
+	hasSlash := len(rawPath) > len(path)
+	switch option.TrailingSlash {
+	case TRAILING_SLASH_STRICT:
+		if hasSlash {
+			r.URL.Path = path
+			return nil, ""
+		}
+	case TRAILING_SLASH_REDIRECT:
+		if hasSlash || cleaned {
+			redirect = r.URL.Path
+			if r.URL.RawQuery != "" {
+				redirect += "?" + r.URL.RawQuery
+			}
+		}
+	}
+	return parsedItem, redirect
+}
+
+// 重定向到规范的URI，GET/HEAD请求使用301，其他请求使用308以保持请求方法及请求内容
+func (s *Server) redirectRoutePath(r *Request, location string) {
+	code := http.StatusMovedPermanently
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		code = http.StatusPermanentRedirect
+	}
+	r.Response.Header().Set("Location", location)
+	r.Response.WriteHeader(code)
+	r.exit = true
+}
@@ -58,8 +58,8 @@ func (s *Server) searchServeHandler(method, path, domain string) *handlerParsedI
 			if _, ok := p.(map[string]interface{})["*list"]; ok {
 				lists = append(lists, p.(map[string]interface{})["*list"].(*list.List))
 			}
-			if _, ok := p.(map[string]interface{})[v]; ok {
-				p = p.(map[string]interface{})[v]
+			if node, ok := s.searchRouteNode(p.(map[string]interface{}), v); ok {
+				p = node
 				if k == len(array)-1 {
 					if _, ok := p.(map[string]interface{})["*list"]; ok {
 						lists = append(lists, p.(map[string]interface{})["*list"].(*list.List))
@@ -89,7 +89,7 @@ func (s *Server) searchServeHandler(method, path, domain string) *handlerParsedI
 				// 动态匹配规则带有gDEFAULT_METHOD的情况，不会像静态规则那样直接解析为所有的HTTP METHOD存储
 				if strings.EqualFold(item.router.Method, gDEFAULT_METHOD) || strings.EqualFold(item.router.Method, method) {
 					// 注意当不带任何动态路由规则时，len(match) == 1
-					if match, err := gregex.MatchString(item.router.RegRule, path); err == nil && len(match) > 0 {
+					if match, err := gregex.MatchString(s.getRouteRegRule(item), path); err == nil && len(match) > 0 {
 						//gutil.Dump(match)
 						//gutil.Dump(names)
 						parsedItem := &handlerParsedItem{item, nil}
@@ -19,7 +19,8 @@ import (
 )
 
 // 绑定控制器，控制器需要实现 gmvc.Controller 接口,
-// 这种方式绑定的控制器每一次请求都会初始化一个新的控制器对象进行处理，对应不同的请求会话,
+// 这种方式绑定的控制器每一次请求都会以注册的控制器对象c为原型复制一个新的控制器对象进行处理，对应不同的请求会话，
+// 因此可以在注册时为控制器对象设置服务对象(例如数据库、缓存对象)，也可以通过SetControllerHooks设置注入回调,
 // 第三个参数methods用以指定需要注册的方法，支持多个方法名称，多个方法以英文“,”号分隔，区分大小写.
 func (s *Server) BindController(pattern string, c Controller, methods ...string) {
 	// 当pattern中的method为all时，去掉该method，以便于后续方法判断
@@ -72,11 +73,12 @@ func (s *Server) BindController(pattern string, c Controller, methods ...string)
 		}
 		key := s.mergeBuildInNameToPattern(pattern, sname, mname, true)
 		m[key] = &handlerItem{
-			name:  fmt.Sprintf(`%s.%s.%s`, pkgPath, ctlName, mname),
-			rtype: gROUTE_REGISTER_CONTROLLER,
-			ctype: v.Elem().Type(),
-			fname: mname,
-			faddr: nil,
+			name:   fmt.Sprintf(`%s.%s.%s`, pkgPath, ctlName, mname),
+			rtype:  gROUTE_REGISTER_CONTROLLER,
+			ctype:  v.Elem().Type(),
+			cvalue: v.Elem(),
+			fname:  mname,
+			faddr:  nil,
 		}
 		// 如果方法中带有Index方法，那么额外自动增加一个路由规则匹配主URI，
 		// 例如: pattern为/user, 那么会同时注册/user及/user/index，
@@ -89,11 +91,12 @@ func (s *Server) BindController(pattern string, c Controller, methods ...string)
 				k = "/" + k
 			}
 			m[k] = &handlerItem{
-				name:  fmt.Sprintf(`%s.%s.%s`, pkgPath, ctlName, mname),
-				rtype: gROUTE_REGISTER_CONTROLLER,
-				ctype: v.Elem().Type(),
-				fname: mname,
-				faddr: nil,
+				name:   fmt.Sprintf(`%s.%s.%s`, pkgPath, ctlName, mname),
+				rtype:  gROUTE_REGISTER_CONTROLLER,
+				ctype:  v.Elem().Type(),
+				cvalue: v.Elem(),
+				fname:  mname,
+				faddr:  nil,
 			}
 		}
 	}
@@ -125,11 +128,12 @@ func (s *Server) BindControllerMethod(pattern string, c Controller, method strin
 	}
 	key := s.mergeBuildInNameToPattern(pattern, sname, mname, false)
 	m[key] = &handlerItem{
-		name:  fmt.Sprintf(`%s.%s.%s`, pkgPath, ctlName, mname),
-		rtype: gROUTE_REGISTER_CONTROLLER,
-		ctype: v.Elem().Type(),
-		fname: mname,
-		faddr: nil,
+		name:   fmt.Sprintf(`%s.%s.%s`, pkgPath, ctlName, mname),
+		rtype:  gROUTE_REGISTER_CONTROLLER,
+		ctype:  v.Elem().Type(),
+		cvalue: v.Elem(),
+		fname:  mname,
+		faddr:  nil,
 	}
 	s.bindHandlerByMap(m)
 }
@@ -164,11 +168,12 @@ func (s *Server) BindControllerRest(pattern string, c Controller) {
 		}
 		key := s.mergeBuildInNameToPattern(mname+":"+pattern, sname, mname, false)
 		m[key] = &handlerItem{
-			name:  fmt.Sprintf(`%s.%s.%s`, pkgPath, ctlName, mname),
-			rtype: gROUTE_REGISTER_CONTROLLER,
-			ctype: v.Elem().Type(),
-			fname: mname,
-			faddr: nil,
+			name:   fmt.Sprintf(`%s.%s.%s`, pkgPath, ctlName, mname),
+			rtype:  gROUTE_REGISTER_CONTROLLER,
+			ctype:  v.Elem().Type(),
+			cvalue: v.Elem(),
+			fname:  mname,
+			faddr:  nil,
 		}
 	}
 	s.bindHandlerByMap(m)
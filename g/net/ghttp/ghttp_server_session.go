@@ -10,10 +10,12 @@ package ghttp
 import (
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gf/g/container/gmap"
 	"github.com/gf/g/container/gvar"
+	"github.com/gf/g/os/glog"
 	"github.com/gf/g/os/gtime"
 	"github.com/gf/g/util/gconv"
 	"github.com/gf/g/util/grand"
@@ -25,6 +27,9 @@ type Session struct {
 	data    *gmap.StrAnyMap // Session数据
 	server  *Server         // 所属Server
 	request *Request        // 关联的请求
+	mu      sync.Mutex      // 变更记录互斥锁
+	changes map[string]bool // 本次请求中变更的键名，true表示设置，false表示删除
+	cleared bool            // 本次请求中是否清空过session
 }
 
 // 生成一个唯一的SessionId字符串，长度18位。
@@ -48,6 +53,23 @@ func (s *Session) init() {
 		s.server = s.request.Server
 		// 根据提交的SESSION ID获取已存在SESSION
 		id := s.request.Cookie.GetSessionId()
+		// 使用外部存储时从存储中加载session数据，新建的session在请求结束且有数据时才写入存储
+		if storage := s.server.config.SessionStorage; storage != nil {
+			if id != "" {
+				data, err := storage.Get(id)
+				if err != nil {
+					glog.Error("[ghttp] session storage error:", err)
+				}
+				if data != nil {
+					s.id = id
+					s.data = gmap.NewStrAnyMapFrom(data)
+					return
+				}
+			}
+			s.id = s.request.Cookie.MakeSessionId()
+			s.data = gmap.NewStrAnyMap()
+			return
+		}
 		if id != "" {
 			data := s.server.sessions.Get(id)
 			if data != nil {
@@ -82,12 +104,16 @@ func (s *Session) Map() map[string]interface{} {
 func (s *Session) Set(key string, value interface{}) {
 	s.init()
 	s.data.Set(key, value)
+	s.change(key, true)
 }
 
 // 批量设置
 func (s *Session) Sets(m map[string]interface{}) {
 	s.init()
 	s.data.Sets(m)
+	for k := range m {
+		s.change(k, true)
+	}
 }
 
 // 判断键名是否存在
@@ -123,6 +149,7 @@ func (s *Session) Remove(key string) {
 	if len(s.id) > 0 || s.request.Cookie.GetSessionId() != "" {
 		s.init()
 		s.data.Remove(key)
+		s.change(key, false)
 	}
 }
 
@@ -131,16 +158,77 @@ func (s *Session) Clear() {
 	if len(s.id) > 0 || s.request.Cookie.GetSessionId() != "" {
 		s.init()
 		s.data.Clear()
+		s.mu.Lock()
+		s.changes = nil
+		s.cleared = true
+		s.mu.Unlock()
+	}
+}
+
+// 记录本次请求中变更的键名
+func (s *Session) change(key string, set bool) {
+	s.mu.Lock()
+	if s.changes == nil {
+		s.changes = make(map[string]bool)
 	}
+	s.changes[key] = set
+	s.mu.Unlock()
 }
 
 // 更新过期时间(如果用在守护进程中长期使用，需要手动调用进行更新，防止超时被清除)
 func (s *Session) UpdateExpire() {
 	if len(s.id) > 0 && s.data.Size() > 0 {
+		if storage := s.server.config.SessionStorage; storage != nil {
+			if err := storage.UpdateTTL(s.id, s.ttl()); err != nil {
+				glog.Error("[ghttp] session storage error:", err)
+			}
+			return
+		}
 		s.server.sessions.Set(s.id, s.data, s.server.GetSessionMaxAge()*1000)
 	}
 }
 
+// 请求结束时将本次请求中变更的session数据写回外部存储，支持键级更新的存储仅写入变更的键值，
+// 没有变更时仅更新过期时间。
+func (s *Session) flush() {
+	if len(s.id) == 0 {
+		return
+	}
+	storage := s.server.config.SessionStorage
+	s.mu.Lock()
+	changes, cleared := s.changes, s.cleared
+	s.changes, s.cleared = nil, false
+	s.mu.Unlock()
+	if storage == nil || (!cleared && len(changes) == 0) {
+		s.UpdateExpire()
+		return
+	}
+	var err error
+	if partial, ok := storage.(SessionPartialStorage); ok && !cleared {
+		values := make(map[string]interface{})
+		removed := make([]string, 0)
+		for k, set := range changes {
+			// 以当前数据为准，同一个键可能在设置后被删除
+			if v := s.data.Get(k); set && v != nil {
+				values[k] = v
+			} else {
+				removed = append(removed, k)
+			}
+		}
+		err = partial.SetValues(s.id, values, removed, s.ttl())
+	} else {
+		err = storage.Set(s.id, s.data.Map(), s.ttl())
+	}
+	if err != nil {
+		glog.Error("[ghttp] session storage error:", err)
+	}
+}
+
+// session有效期
+func (s *Session) ttl() time.Duration {
+	return time.Duration(s.server.GetSessionMaxAge()) * time.Second
+}
+
 func (s *Session) GetString(key string, def ...interface{}) string {
 	return gconv.String(s.Get(key, def...))
 }
@@ -0,0 +1,146 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+// External session storage.
+
+package ghttp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gf/g/database/gredis"
+)
+
+const (
+	// Default key prefix of the sessions in redis.
+	gDEFAULT_SESSION_REDIS_PREFIX = "gsession:"
+)
+
+// SessionStorage is the external storage of session data, which shares the sessions among servers.
+// The session data is loaded from the storage when it's first used in the request, and written back
+// when the request is done only if it's changed, otherwise only the expiration is updated.
+type SessionStorage interface {
+	// Get returns the data of session <id>, which is nil if the session does not exist or is expired.
+	Get(id string) (map[string]interface{}, error)
+	// Set replaces the data of session <id> with <data> and expiration <ttl>,
+	// the session is removed if <data> is empty.
+	Set(id string, data map[string]interface{}, ttl time.Duration) error
+	// UpdateTTL updates the expiration of session <id>.
+	UpdateTTL(id string, ttl time.Duration) error
+}
+
+// SessionPartialStorage is the SessionStorage supporting key-level updates,
+// which writes only the changed keys instead of the whole session data.
+type SessionPartialStorage interface {
+	SessionStorage
+	// SetValues sets <values> and removes <removed> keys of session <id>, and updates its expiration <ttl>.
+	SetValues(id string, values map[string]interface{}, removed []string, ttl time.Duration) error
+}
+
+// SessionStorageRedis stores each session as a redis hash, in which the values are encoded as JSON.
+// It implements SessionPartialStorage, and it is not supported in cluster mode as it uses transactions.
+type SessionStorageRedis struct {
+	redis  *gredis.Redis
+	prefix string
+}
+
+// NewSessionStorageRedis creates and returns a redis session storage, the optional parameter
+// <prefix> specifies the key prefix of the sessions, which is "gsession:" in default.
+func NewSessionStorageRedis(redis *gredis.Redis, prefix ...string) *SessionStorageRedis {
+	s := &SessionStorageRedis{
+		redis:  redis,
+		prefix: gDEFAULT_SESSION_REDIS_PREFIX,
+	}
+	if len(prefix) > 0 {
+		s.prefix = prefix[0]
+	}
+	return s
+}
+
+// Get implements SessionStorage.
+func (s *SessionStorageRedis) Get(id string) (map[string]interface{}, error) {
+	m, err := s.redis.HGetAllMap(s.prefix + id)
+	if err != nil || len(m) == 0 {
+		return nil, err
+	}
+	data := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		var value interface{}
+		if err := json.Unmarshal([]byte(v), &value); err != nil {
+			return nil, err
+		}
+		data[k] = value
+	}
+	return data, nil
+}
+
+// Set implements SessionStorage.
+func (s *SessionStorageRedis) Set(id string, data map[string]interface{}, ttl time.Duration) error {
+	args, err := s.hashArgs(id, data)
+	if err != nil {
+		return err
+	}
+	_, err = s.redis.Multi(func(tx *gredis.Conn) error {
+		if err := tx.Send("DEL", s.prefix+id); err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		if err := tx.Send("HMSET", args...); err != nil {
+			return err
+		}
+		return tx.Send("PEXPIRE", s.prefix+id, int64(ttl/time.Millisecond))
+	})
+	return err
+}
+
+// SetValues implements SessionPartialStorage.
+func (s *SessionStorageRedis) SetValues(id string, values map[string]interface{}, removed []string, ttl time.Duration) error {
+	args, err := s.hashArgs(id, values)
+	if err != nil {
+		return err
+	}
+	_, err = s.redis.Multi(func(tx *gredis.Conn) error {
+		if len(values) > 0 {
+			if err := tx.Send("HMSET", args...); err != nil {
+				return err
+			}
+		}
+		if len(removed) > 0 {
+			fields := make([]interface{}, 0, len(removed)+1)
+			fields = append(fields, s.prefix+id)
+			for _, k := range removed {
+				fields = append(fields, k)
+			}
+			if err := tx.Send("HDEL", fields...); err != nil {
+				return err
+			}
+		}
+		return tx.Send("PEXPIRE", s.prefix+id, int64(ttl/time.Millisecond))
+	})
+	return err
+}
+
+// UpdateTTL implements SessionStorage.
+func (s *SessionStorageRedis) UpdateTTL(id string, ttl time.Duration) error {
+	_, err := s.redis.Do("PEXPIRE", s.prefix+id, int64(ttl/time.Millisecond))
+	return err
+}
+
+// hashArgs returns the arguments of HMSET for <data> of session <id>.
+func (s *SessionStorageRedis) hashArgs(id string, data map[string]interface{}) ([]interface{}, error) {
+	args := make([]interface{}, 0, 2*len(data)+1)
+	args = append(args, s.prefix+id)
+	for k, v := range data {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, k, b)
+	}
+	return args, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+// 优雅关闭.
+
+package ghttp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gf/g/os/glog"
+	"github.com/gf/g/os/gproc"
+	"github.com/gf/third/github.com/gorilla/websocket"
+)
+
+const (
+	gSHUTDOWN_POLL_INTERVAL = 50 * time.Millisecond // 优雅关闭时检查WebSocket连接是否已经全部关闭的时间间隔
+)
+
+// 注册优雅关闭时执行的回调方法，在Server停止接收新连接之后、等待请求处理完成之前按照注册顺序执行，
+// 可以用于设置服务为未就绪状态(例如Kubernetes的readiness探针)、通知长连接的客户端等。
+func (s *Server) BindShutdownHandler(handler func()) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.shutdownHandlers = append(s.shutdownHandlers, handler)
+}
+
+// 设置收到终止信号(SIGINT/SIGTERM等)时优雅关闭的超时时间，为0时(默认)收到终止信号立即关闭Server
+func (s *Server) SetShutdownTimeout(timeout time.Duration) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.config.ShutdownTimeout = timeout
+}
+
+// 判断Server是否正在优雅关闭
+func (s *Server) IsShuttingDown() bool {
+	return s.shuttingDown.Val()
+}
+
+// 优雅关闭Server：停止接收新连接，执行注册的关闭回调方法，向WebSocket连接发送关闭消息，
+// 然后等待正在处理的请求及WebSocket连接完成，ctx取消或者超时时强制关闭剩余的连接并返回ctx.Err()。
+// 注意不能在请求处理中调用(会等待该请求自身处理完成直至超时)。
+func (s *Server) gracefulShutdown(ctx context.Context) error {
+	if !s.shuttingDown.Cas(false, true) {
+		return nil
+	}
+	// Run需要等待优雅关闭完成后才返回，否则进程可能在请求处理完成之前退出
+	s.draining.Add(1)
+	defer s.draining.Done()
+
+	glog.Printf("%d: server [%s] shutting down gracefully", gproc.Pid(), s.name)
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(s.servers))
+	)
+	for i, server := range s.servers {
+		wg.Add(1)
+		go func(i int, server *gracefulServer) {
+			defer wg.Done()
+			errs[i] = server.shutdown(ctx)
+		}(i, server)
+	}
+	for _, handler := range s.shutdownHandlers {
+		s.niceCallShutdownHandler(handler)
+	}
+	// WebSocket连接被接管后不在http.Server的管理范围内，需要单独通知并等待关闭
+	deadline := time.Now().Add(time.Second)
+	if d, ok := ctx.Deadline(); ok {
+		deadline = d
+	}
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for _, v := range s.websockets.Slice() {
+		// 发送失败时连接已经断开，不再等待该连接
+		if err := v.(*WebSocket).WriteControl(websocket.CloseMessage, message, deadline); err != nil {
+			v.(*WebSocket).deregister()
+		}
+	}
+	ticker := time.NewTicker(gSHUTDOWN_POLL_INTERVAL)
+	defer ticker.Stop()
+	for s.websockets.Size() > 0 && ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		// 超时后强制关闭剩余的连接
+		for _, v := range s.websockets.Slice() {
+			v.(*WebSocket).Close()
+		}
+		for _, server := range s.servers {
+			server.close()
+		}
+		return err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 执行关闭回调方法，回调方法产生的异常不影响关闭流程
+func (s *Server) niceCallShutdownHandler(handler func()) {
+	defer func() {
+		if e := recover(); e != nil {
+			glog.Errorf("%d: server [%s] shutdown handler panic: %v", gproc.Pid(), s.name, e)
+		}
+	}()
+	handler()
+}
@@ -0,0 +1,275 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+// 静态挂载，支持通过配置文件设置并热更新.
+
+package ghttp
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/os/gcfg"
+	"github.com/gf/g/os/gfile"
+	"github.com/gf/g/os/gfsnotify"
+	"github.com/gf/g/os/glog"
+	"github.com/gf/g/os/gspath"
+	"github.com/gf/g/util/gconv"
+)
+
+// 静态挂载项，将URI前缀映射到静态文件目录或者资源包(zip文件)，
+// 可以通过配置文件设置，配置文件示例(toml)：
+//
+//	[[server.static]]
+//	    prefix       = "/assets"
+//	    path         = "public/assets"
+//	    indexFiles   = ["index.html"]
+//	    cacheControl = "public, max-age=3600"
+//	[[server.static]]
+//	    prefix       = "/docs"
+//	    path         = "docs.zip"
+//	    maxAge       = 600
+type StaticMount struct {
+	Prefix       string   // 映射的URI前缀，例如：/assets
+	Path         string   // 静态文件目录路径，或者资源包(zip文件)路径
+	IndexFiles   []string // 访问目录时默认展示的文件列表，为空时使用Server的IndexFiles设置
+	IndexFolder  bool     // 访问目录时没有默认展示文件的情况下是否展示目录的文件列表(仅对目录挂载有效)
+	CacheControl string   // 返回的Cache-Control头信息，例如：public, max-age=3600
+	MaxAge       int      // 浏览器缓存时间(秒)，CacheControl为空时返回Cache-Control: public, max-age=MaxAge
+}
+
+// 生效的静态挂载项
+type staticMountItem struct {
+	StaticMount
+	bundle map[string]*zip.File // 资源包中的文件列表，目录挂载时为空
+}
+
+// 静态挂载管理对象
+type staticMounts struct {
+	mu       sync.Mutex          // 配置绑定及重新加载的互斥锁
+	items    *gtype.Interface    // 当前生效的静态挂载列表([]*staticMountItem)，按照前缀从长到短排序
+	key      string              // 绑定的配置项名称
+	config   *gcfg.Config        // 绑定的配置管理对象
+	callback *gfsnotify.Callback // 配置文件的监控回调
+}
+
+// 查找到的静态挂载文件
+type staticMountFile struct {
+	mount *staticMountItem // 所属的静态挂载项
+	path  string           // 目录挂载时为文件(或者目录)的绝对路径，资源包挂载时为资源包中的文件名称
+	isDir bool             // 是否为目录(仅对目录挂载有效)
+}
+
+func newStaticMounts() *staticMounts {
+	return &staticMounts{
+		items: gtype.NewInterface(),
+	}
+}
+
+// 设置静态挂载列表，替换之前设置的所有静态挂载，可以在Server运行时调用(热更新)。
+// 静态挂载的检索优先级高于StaticPaths及ServerRoot。
+func (s *Server) SetStaticMounts(mounts []StaticMount) error {
+	items, err := s.newStaticMountItems(mounts)
+	if err != nil {
+		return err
+	}
+	s.mounts.items.Set(items)
+	if s.Status() != SERVER_STATUS_RUNNING {
+		s.config.FileServerEnabled = true
+	}
+	return nil
+}
+
+// 绑定配置文件中的静态挂载配置，pattern为配置项名称(例如：server.static)，配置项为StaticMount的数组，
+// config为空时使用默认的配置管理对象。配置文件修改后自动重新加载静态挂载，加载失败时保留之前的静态挂载。
+func (s *Server) BindStaticConfig(pattern string, config ...*gcfg.Config) error {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		return errors.New(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+	}
+	c := gcfg.Instance()
+	if len(config) > 0 && config[0] != nil {
+		c = config[0]
+	}
+	m := s.mounts
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.callback != nil {
+		gfsnotify.RemoveCallback(m.callback.Id)
+		m.callback = nil
+	}
+	m.key, m.config = pattern, c
+	if err := s.loadStaticConfig(); err != nil {
+		return err
+	}
+	s.config.FileServerEnabled = true
+	// 配置文件监控，注意gcfg自身也会在文件修改时清除配置缓存，
+	// 由于监控回调是异步执行的，这里主动清除配置缓存以保证读取到最新的配置内容。
+	if file := c.FilePath(); file != "" {
+		callback, err := gfsnotify.Add(file, func(event *gfsnotify.Event) {
+			if event.IsRemove() || event.IsChmod() {
+				return
+			}
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			c.Clear()
+			if err := s.loadStaticConfig(); err != nil {
+				glog.Errorf(`[ghttp] reload static config "%s" failed: %s`, pattern, err.Error())
+			}
+		})
+		if err != nil {
+			return err
+		}
+		m.callback = callback
+	}
+	return nil
+}
+
+// 从绑定的配置项中加载静态挂载列表
+func (s *Server) loadStaticConfig() error {
+	mounts := make([]StaticMount, 0)
+	for _, v := range s.mounts.config.GetArray(s.mounts.key) {
+		mount := StaticMount{}
+		if err := gconv.Struct(v, &mount); err != nil {
+			return err
+		}
+		mounts = append(mounts, mount)
+	}
+	items, err := s.newStaticMountItems(mounts)
+	if err != nil {
+		return err
+	}
+	s.mounts.items.Set(items)
+	return nil
+}
+
+// 检查并创建静态挂载项列表，按照前缀从长到短排序
+func (s *Server) newStaticMountItems(mounts []StaticMount) ([]*staticMountItem, error) {
+	items := make([]*staticMountItem, 0, len(mounts))
+	for _, mount := range mounts {
+		if mount.Prefix == "" || mount.Prefix[0] != '/' {
+			return nil, fmt.Errorf(`invalid static mount prefix "%s": it should lead with '/'`, mount.Prefix)
+		}
+		if mount.Prefix != "/" {
+			mount.Prefix = strings.TrimRight(mount.Prefix, "/")
+		}
+		realPath, err := gfile.Search(mount.Path)
+		if err != nil {
+			return nil, err
+		}
+		item := &staticMountItem{StaticMount: mount}
+		item.Path = realPath
+		if !gfile.IsDir(realPath) {
+			if item.bundle, err = loadStaticBundle(realPath); err != nil {
+				return nil, fmt.Errorf(`load static bundle "%s" failed: %s`, realPath, err.Error())
+			}
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return len(items[i].Prefix) > len(items[j].Prefix)
+	})
+	return items, nil
+}
+
+// 加载资源包(zip文件)中的文件列表，资源包内容加载到内存中
+func loadStaticBundle(file string) (map[string]*zip.File, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, f := range reader.File {
+		if !f.FileInfo().IsDir() {
+			files[strings.TrimLeft(f.Name, "/")] = f
+		}
+	}
+	return files, nil
+}
+
+// 检索URI对应的静态挂载文件，没有匹配的静态挂载或者文件不存在时返回nil
+func (s *Server) searchStaticMount(uri string) *staticMountFile {
+	items, _ := s.mounts.items.Val().([]*staticMountItem)
+	for _, item := range items {
+		if !strings.HasPrefix(uri, item.Prefix) {
+			continue
+		}
+		// 防止类似 /static/style 映射到 /static/style.css 的情况
+		if item.Prefix != "/" && len(uri) > len(item.Prefix) && uri[len(item.Prefix)] != '/' {
+			continue
+		}
+		indexFiles := item.IndexFiles
+		if len(indexFiles) == 0 {
+			indexFiles = s.config.IndexFiles
+		}
+		name := uri[len(item.Prefix):]
+		if item.bundle == nil {
+			filePath, isDir := gspath.Search(item.Path, name, indexFiles...)
+			if filePath == "" || (isDir && !item.IndexFolder) {
+				continue
+			}
+			return &staticMountFile{mount: item, path: filePath, isDir: isDir}
+		}
+		name = strings.Trim(path.Clean("/"+name), "/")
+		if _, ok := item.bundle[name]; ok {
+			return &staticMountFile{mount: item, path: name}
+		}
+		for _, index := range indexFiles {
+			if _, ok := item.bundle[strings.TrimLeft(name+"/"+index, "/")]; ok {
+				return &staticMountFile{mount: item, path: strings.TrimLeft(name+"/"+index, "/")}
+			}
+		}
+	}
+	return nil
+}
+
+// 输出静态挂载文件
+func (s *Server) serveStaticMount(r *Request, file *staticMountFile) {
+	mount := file.mount
+	if mount.CacheControl != "" {
+		r.Response.Header().Set("Cache-Control", mount.CacheControl)
+	} else if mount.MaxAge > 0 {
+		r.Response.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", mount.MaxAge))
+	}
+	if mount.bundle == nil {
+		if file.isDir {
+			f, err := http.Dir(file.path).Open("/")
+			if err != nil {
+				r.Response.WriteStatus(http.StatusForbidden)
+				return
+			}
+			defer f.Close()
+			s.listDir(r, f)
+			return
+		}
+		s.serveFile(r, file.path)
+		return
+	}
+	f := mount.bundle[file.path]
+	reader, err := f.Open()
+	if err != nil {
+		r.Response.WriteStatus(http.StatusInternalServerError)
+		return
+	}
+	defer reader.Close()
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		r.Response.WriteStatus(http.StatusInternalServerError)
+		return
+	}
+	http.ServeContent(r.Response.Writer, r.Request, path.Base(file.path), f.Modified, bytes.NewReader(content))
+}
@@ -6,12 +6,38 @@
 
 package ghttp
 
-import "github.com/gf/third/github.com/gorilla/websocket"
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gf/g/os/glog"
+	"github.com/gf/third/github.com/gorilla/websocket"
+)
 
 type WebSocket struct {
 	*websocket.Conn
+	server       *Server                // 所属Web Server，用于优雅关闭时通知及关闭连接
+	request      *Request               // 升级为WebSocket连接的HTTP请求
+	interceptors []WebSocketInterceptor // 消息拦截器，全局拦截器在前，连接拦截器在后
+}
+
+// WebSocket消息，拦截器可以修改消息的类型及内容
+type WebSocketMessage struct {
+	Type    int    // 消息类型(WS_MSG_TEXT/WS_MSG_BINARY)
+	Data    []byte // 消息内容
+	Inbound bool   // 是否为客户端发送的消息(读取)，否则为服务端发送的消息(写入)
 }
 
+// WebSocket消息拦截器，在读取/写入每条消息时按照注册顺序执行，可以用于限流、消息大小限制、日志记录等。
+// 拦截器返回ErrWebSocketSkip时丢弃该消息(读取时继续读取下一条消息，写入时不发送该消息)，
+// 返回其他错误时终止后续拦截器的执行并将错误返回给ReadMessage/WriteMessage的调用方。
+type WebSocketInterceptor func(ws *WebSocket, msg *WebSocketMessage) error
+
+var (
+	// 拦截器返回该错误时丢弃当前消息，并不会返回给调用方
+	ErrWebSocketSkip = errors.New("websocket message skipped")
+)
+
 const (
 	// TextMessage denotes a text data message. The text message payload is
 	// interpreted as UTF-8 encoded text data.
@@ -33,3 +59,112 @@ const (
 	// is UTF-8 encoded text.
 	WS_MSG_PONG = websocket.PongMessage
 )
+
+// 关闭WebSocket连接
+func (ws *WebSocket) Close() error {
+	ws.deregister()
+	return ws.Conn.Close()
+}
+
+// 从所属Server的连接列表中移除，之后优雅关闭时不再通知及等待该连接。
+// 连接关闭、读写出错(底层连接的读写错误是不可恢复的)以及升级连接的请求处理方法返回时调用。
+func (ws *WebSocket) deregister() {
+	if ws.server != nil {
+		ws.server.websockets.Remove(ws)
+	}
+}
+
+// 注册全局WebSocket消息拦截器，对之后通过Request.WebSocket升级的所有连接生效，按照注册顺序执行。
+// 连接升级前的鉴权等处理可以通过中间件完成，中间件不调用Next时不会执行连接升级。
+func (s *Server) UseWebSocket(interceptors ...WebSocketInterceptor) {
+	if s.Status() == SERVER_STATUS_RUNNING {
+		glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+		return
+	}
+	s.wsInterceptors = append(s.wsInterceptors, interceptors...)
+}
+
+// 注册当前连接的消息拦截器，在全局拦截器之后执行，注意需要在读写消息之前注册。
+func (ws *WebSocket) Use(interceptors ...WebSocketInterceptor) {
+	ws.interceptors = append(ws.interceptors, interceptors...)
+}
+
+// 获取升级为WebSocket连接的HTTP请求对象，可以用于在拦截器中获取鉴权信息、客户端地址等
+func (ws *WebSocket) Request() *Request {
+	return ws.request
+}
+
+// 读取一条消息，读取的消息经过拦截器处理后返回
+func (ws *WebSocket) ReadMessage() (messageType int, data []byte, err error) {
+	for {
+		messageType, data, err = ws.Conn.ReadMessage()
+		if err != nil {
+			ws.deregister()
+			return
+		}
+		if len(ws.interceptors) == 0 {
+			return
+		}
+		msg := &WebSocketMessage{
+			Type:    messageType,
+			Data:    data,
+			Inbound: true,
+		}
+		if err = ws.intercept(msg); err == nil {
+			return msg.Type, msg.Data, nil
+		}
+		if err != ErrWebSocketSkip {
+			return 0, nil, err
+		}
+	}
+}
+
+// 写入一条消息，消息经过拦截器处理后发送
+func (ws *WebSocket) WriteMessage(messageType int, data []byte) error {
+	if len(ws.interceptors) > 0 {
+		msg := &WebSocketMessage{
+			Type: messageType,
+			Data: data,
+		}
+		if err := ws.intercept(msg); err != nil {
+			if err == ErrWebSocketSkip {
+				return nil
+			}
+			return err
+		}
+		messageType, data = msg.Type, msg.Data
+	}
+	err := ws.Conn.WriteMessage(messageType, data)
+	if err != nil {
+		ws.deregister()
+	}
+	return err
+}
+
+// 读取一条消息并解析为JSON，读取的消息经过拦截器处理
+func (ws *WebSocket) ReadJSON(v interface{}) error {
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// 将v编码为JSON后作为文本消息写入，消息经过拦截器处理
+func (ws *WebSocket) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ws.WriteMessage(WS_MSG_TEXT, data)
+}
+
+// 按照顺序执行消息拦截器
+func (ws *WebSocket) intercept(msg *WebSocketMessage) error {
+	for _, interceptor := range ws.interceptors {
+		if err := interceptor(ws, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
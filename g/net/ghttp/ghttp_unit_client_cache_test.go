@@ -0,0 +1,82 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/container/gtype"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Client_Cache(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	maxAgeCount := gtype.NewInt()
+	etagCount := gtype.NewInt()
+	notModifiedCount := gtype.NewInt()
+	noStoreCount := gtype.NewInt()
+	s.BindHandler("/max-age", func(r *ghttp.Request) {
+		maxAgeCount.Add(1)
+		r.Response.Header().Set("Cache-Control", "max-age=60")
+		r.Response.Write("max-age")
+	})
+	s.BindHandler("/etag", func(r *ghttp.Request) {
+		etagCount.Add(1)
+		r.Response.Header().Set("Cache-Control", "no-cache")
+		r.Response.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModifiedCount.Add(1)
+			r.Response.WriteHeader(304)
+			return
+		}
+		r.Response.Write("etag")
+	})
+	s.BindHandler("/no-store", func(r *ghttp.Request) {
+		noStoreCount.Add(1)
+		r.Response.Header().Set("Cache-Control", "no-store")
+		r.Response.Write("no-store")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient().Cache(true)
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/max-age"), "max-age")
+		gtest.Assert(client.GetContent("/max-age"), "max-age")
+		gtest.Assert(maxAgeCount.Val(), 1)
+
+		gtest.Assert(client.GetContent("/etag"), "etag")
+		gtest.Assert(client.GetContent("/etag"), "etag")
+		gtest.Assert(client.GetContent("/etag"), "etag")
+		gtest.Assert(etagCount.Val(), 3)
+		gtest.Assert(notModifiedCount.Val(), 2)
+
+		gtest.Assert(client.GetContent("/no-store"), "no-store")
+		gtest.Assert(client.GetContent("/no-store"), "no-store")
+		gtest.Assert(noStoreCount.Val(), 2)
+
+		client.ClearCache()
+		gtest.Assert(client.GetContent("/max-age"), "max-age")
+		gtest.Assert(maxAgeCount.Val(), 2)
+	})
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+		gtest.Assert(client.GetContent("/max-age"), "max-age")
+		gtest.Assert(maxAgeCount.Val(), 3)
+	})
+}
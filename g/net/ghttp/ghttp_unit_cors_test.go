@@ -0,0 +1,115 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 跨域请求测试
+package ghttp_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_CORS_Response(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/default", func(r *ghttp.Request) {
+		r.Response.CORSDefault()
+		r.Response.Write("default")
+	})
+	s.BindHandler("/list", func(r *ghttp.Request) {
+		r.Response.CORS(ghttp.CORSOptions{
+			AllowOrigin: "https://a.com, https://b.com",
+		})
+		r.Response.Write("list")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		// 没有Origin的请求
+		resp, err := client.Get("/default")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.Header.Get("Access-Control-Allow-Origin"), "*")
+		resp.Close()
+
+		// 携带凭证时返回请求的Origin
+		client.SetHeader("Origin", "https://c.com")
+		resp, err = client.Get("/default")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.Header.Get("Access-Control-Allow-Origin"), "https://c.com")
+		gtest.Assert(resp.Header.Get("Vary"), "Origin")
+		resp.Close()
+
+		resp, err = client.Get("/list")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.Header.Get("Access-Control-Allow-Origin"), "")
+		resp.Close()
+
+		client.SetHeader("Origin", "https://b.com")
+		resp, err = client.Get("/list")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.Header.Get("Access-Control-Allow-Origin"), "https://b.com")
+		resp.Close()
+	})
+}
+
+func Test_CORS_Server(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.SetCORSOptions(ghttp.CORSOptions{
+		AllowOrigin:      "https://a.com",
+		AllowMethods:     "GET,POST",
+		AllowCredentials: "true",
+		MaxAge:           600,
+	})
+	s.BindHandler("POST:/user", func(r *ghttp.Request) {
+		r.Response.Write("user")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+		client.SetHeader("Origin", "https://a.com")
+
+		resp, err := client.Post("/user")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.ReadAllString(), "user")
+		gtest.Assert(resp.Header.Get("Access-Control-Allow-Origin"), "https://a.com")
+		gtest.Assert(resp.Header.Get("Access-Control-Allow-Credentials"), "true")
+		resp.Close()
+
+		// 预检请求直接返回
+		client.SetHeader("Access-Control-Request-Method", "POST")
+		client.SetHeader("Access-Control-Request-Headers", "X-Token")
+		resp, err = client.Options("/user")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.StatusCode, http.StatusNoContent)
+		gtest.Assert(resp.Header.Get("Access-Control-Allow-Methods"), "GET,POST")
+		gtest.Assert(resp.Header.Get("Access-Control-Allow-Headers"), "X-Token")
+		gtest.Assert(resp.Header.Get("Access-Control-Max-Age"), "600")
+		gtest.Assert(resp.ReadAllString(), "")
+		resp.Close()
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 请求日志测试
+package ghttp_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Log_Buffer(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	path := fmt.Sprintf(`%s/ghttp/log/test/%d`, gfile.TempDir(), p)
+	defer gfile.Remove(path)
+	s.BindHandler("/ok", func(r *ghttp.Request) {
+		r.Logger().Debug("ok-debug")
+		r.Response.Write("ok")
+	})
+	s.BindHandler("/status", func(r *ghttp.Request) {
+		r.Logger().Debug("status-debug")
+		r.Response.WriteStatus(503)
+	})
+	s.BindHandler("/panic", func(r *ghttp.Request) {
+		r.Logger().Debug("panic-debug")
+		panic("panic-error")
+	})
+	s.BindHandler("/slow", func(r *ghttp.Request) {
+		r.Logger().Debug("slow-debug")
+		time.Sleep(300 * time.Millisecond)
+		r.Response.Write("slow")
+	})
+	s.SetLogPath(path)
+	s.SetLogStdout(false)
+	s.SetLogBuffer(glog.LEVEL_DEBU, 200*time.Millisecond)
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+	time.Sleep(time.Second)
+
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/ok"), "ok")
+		client.GetContent("/status")
+		client.GetContent("/panic")
+		gtest.Assert(client.GetContent("/slow"), "slow")
+
+		files, err := gfile.ScanDir(path, "*.log", true)
+		gtest.Assert(err, nil)
+		content := ""
+		for _, file := range files {
+			content += gfile.GetContents(file)
+		}
+		gtest.Assert(strings.Contains(content, "ok-debug"), false)
+		gtest.Assert(strings.Contains(content, "status-debug"), true)
+		gtest.Assert(strings.Contains(content, "panic-debug"), true)
+		gtest.Assert(strings.Contains(content, "panic-error"), true)
+		gtest.Assert(strings.Contains(content, "slow-debug"), true)
+	})
+}
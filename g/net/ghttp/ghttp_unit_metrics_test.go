@@ -0,0 +1,126 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/encoding/gjson"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Metrics(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/ok", func(r *ghttp.Request) {
+		r.Response.Write("ok")
+	})
+	s.BindHandler("/error", func(r *ghttp.Request) {
+		r.SetError(errors.New("invalid"))
+		r.Response.WriteStatus(400, "invalid")
+	})
+	s.BindHandler("/panic", func(r *ghttp.Request) {
+		panic("oops")
+	})
+	s.BindHandler("/500", func(r *ghttp.Request) {
+		r.Response.WriteStatus(500, "failed")
+	})
+	alerts := make(chan *ghttp.AlertEvent, 10)
+	s.AddAlert(ghttp.AlertRule{
+		Metric:    ghttp.METRIC_PANIC,
+		Threshold: 2,
+		Window:    time.Minute,
+	}, ghttp.AlertHookFunc(func(event *ghttp.AlertEvent) {
+		alerts <- event
+	}))
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/ok"), "ok")
+		gtest.Assert(client.GetContent("/ok"), "ok")
+		gtest.Assert(client.GetContent("/error"), "invalid")
+		client.GetContent("/panic")
+		client.GetContent("/500")
+
+		select {
+		case <-alerts:
+			t.Error("alert triggered before reaching threshold")
+		case <-time.After(100 * time.Millisecond):
+		}
+		client.GetContent("/panic")
+		select {
+		case event := <-alerts:
+			gtest.Assert(event.Rule.Metric, ghttp.METRIC_PANIC)
+			gtest.Assert(event.Route, "ALL:/panic")
+			gtest.Assert(event.Uri, "/panic")
+			gtest.Assert(event.Count, 2)
+		case <-time.After(time.Second):
+			t.Error("alert not triggered")
+		}
+
+		metrics := make(map[string]ghttp.RouteMetrics)
+		for _, m := range s.GetMetrics() {
+			metrics[m.Route] = m
+		}
+		gtest.Assert(metrics["ALL:/ok"].Requests, 2)
+		gtest.Assert(metrics["ALL:/ok"].ServerErrors, 0)
+		gtest.Assert(metrics["ALL:/error"].Errors, 1)
+		gtest.Assert(metrics["ALL:/error"].ServerErrors, 0)
+		gtest.Assert(metrics["ALL:/panic"].Requests, 2)
+		gtest.Assert(metrics["ALL:/panic"].Panics, 2)
+		gtest.Assert(metrics["ALL:/panic"].ServerErrors, 2)
+		gtest.Assert(metrics["ALL:/500"].ServerErrors, 1)
+		gtest.Assert(metrics["ALL:/500"].Panics, 0)
+
+		s.ResetMetrics()
+		gtest.Assert(len(s.GetMetrics()), 0)
+	})
+}
+
+func Test_Metrics_Endpoint(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/ok", func(r *ghttp.Request) {
+		r.Response.Write("ok")
+	})
+	s.AddMetricsProvider("pool", func() interface{} {
+		return g.Map{"open": 2, "idle": 1}
+	})
+	s.EnableMetrics()
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/ok"), "ok")
+		j, err := gjson.DecodeToJson([]byte(client.GetContent("/debug/metrics")))
+		gtest.Assert(err, nil)
+		gtest.Assert(j.GetInt("pool.open"), 2)
+		gtest.Assert(j.GetInt("pool.idle"), 1)
+		gtest.Assert(j.GetString("routes.0.Route"), "ALL:/ok")
+		gtest.Assert(j.GetInt("routes.0.Requests"), 1)
+		// Database pool statistics registered by g.Server.
+		gtest.AssertNE(j.Get("database"), nil)
+	})
+}
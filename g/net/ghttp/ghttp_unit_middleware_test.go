@@ -0,0 +1,158 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 中间件测试
+package ghttp_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Middleware_Basic(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.Use(func(r *ghttp.Request) {
+		r.Response.Write("1")
+		r.Middleware.Next()
+		r.Response.Write("2")
+	}, func(r *ghttp.Request) {
+		r.Response.Write("3")
+		r.Middleware.Next()
+		r.Response.Write("4")
+	})
+	s.BindHandler("/test", func(r *ghttp.Request) {
+		r.Response.Write("test")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/test"), "13test42")
+		gtest.Assert(client.GetContent("/none"), "1342")
+	})
+}
+
+func Test_Middleware_Group(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.Use(func(r *ghttp.Request) {
+		defer func() {
+			if e := recover(); e != nil {
+				r.Response.ClearBuffer()
+				r.Response.Write("recovered:", e)
+			}
+		}()
+		r.Middleware.Next()
+	})
+	s.BindHandler("/public", func(r *ghttp.Request) {
+		r.Response.Write("public")
+	})
+	s.BindHandler("/panic", func(r *ghttp.Request) {
+		panic("error")
+	})
+	group := s.Group("/admin").Middleware(func(r *ghttp.Request) {
+		if r.Get("token") != "123" {
+			r.Response.WriteStatus(http.StatusForbidden)
+			return
+		}
+		r.Middleware.Next()
+	}, func(r *ghttp.Request) {
+		r.Response.Write("[")
+		r.Middleware.Next()
+		r.Response.Write("]")
+	})
+	group.ALL("/info", func(r *ghttp.Request) {
+		r.Response.Write("info")
+	})
+	group.GET("/exit", func(r *ghttp.Request) {
+		r.Response.Write("exit")
+		r.Exit()
+		r.Response.Write("unreachable")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/public"), "public")
+		gtest.Assert(client.GetContent("/panic"), "recovered:error")
+		gtest.Assert(client.GetContent("/admin/info"), "Forbidden")
+		gtest.Assert(client.GetContent("/admin/info?token=123"), "[info]")
+		gtest.Assert(client.GetContent("/admin/exit?token=123"), "[exit]")
+	})
+}
+
+func Test_Middleware_NotFound(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.Use(func(r *ghttp.Request) {
+		r.Response.Header().Set("X-Middleware", "1")
+		if r.Get("handled") == "1" {
+			r.Response.WriteHeader(http.StatusOK)
+			r.Response.Write("handled")
+			return
+		}
+		r.Middleware.Next()
+		r.Response.Header().Set("X-Not-Found", fmt.Sprint(r.IsNotFound()))
+	})
+	s.BindHandler("/test", func(r *ghttp.Request) {
+		r.Response.Write("test")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		r, err := client.Get("/test")
+		gtest.Assert(err, nil)
+		gtest.Assert(r.StatusCode, http.StatusOK)
+		gtest.Assert(r.Header.Get("X-Not-Found"), "false")
+		gtest.Assert(r.ReadAllString(), "test")
+		r.Close()
+
+		// 中间件设置了Header时未匹配的路由仍然返回404
+		r, err = client.Get("/none")
+		gtest.Assert(err, nil)
+		gtest.Assert(r.StatusCode, http.StatusNotFound)
+		gtest.Assert(r.Header.Get("X-Middleware"), "1")
+		gtest.Assert(r.Header.Get("X-Not-Found"), "true")
+		gtest.Assert(r.ReadAllString(), "Not Found")
+		r.Close()
+
+		// 中间件自行处理并设置状态码
+		r, err = client.Get("/none?handled=1")
+		gtest.Assert(err, nil)
+		gtest.Assert(r.StatusCode, http.StatusOK)
+		gtest.Assert(r.ReadAllString(), "handled")
+		r.Close()
+	})
+}
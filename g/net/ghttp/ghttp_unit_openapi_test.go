@@ -0,0 +1,118 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/encoding/gjson"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+type openApiUser struct {
+	Id    int      `json:"id"`
+	Name  string   `json:"name" description:"user name"`
+	Roles []string `json:"roles"`
+}
+
+type openApiUserCreateReq struct {
+	Name   string `json:"name" valid:"required|length:2,16"`
+	Email  string `json:"email" valid:"email"`
+	Status int    `json:"status" valid:"in:0,1"`
+}
+
+type openApiUserListReq struct {
+	Page int `json:"page" valid:"min:1"`
+}
+
+func Test_OpenApi_Generate(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("POST:/user", func(r *ghttp.Request) {
+		r.Response.WriteJsonOk(openApiUser{Id: 1, Name: r.GetString("name")})
+	})
+	s.BindHandler("GET:/user/:id", func(r *ghttp.Request) {
+		r.Response.WriteJsonOk(openApiUser{Id: r.GetInt("id")})
+	})
+	s.BindHandler("/user/list", func(r *ghttp.Request) {
+		r.Response.WriteJsonOk([]openApiUser{})
+	})
+	s.BindOpenApiDoc("POST:/user", ghttp.OpenApiDoc{
+		Summary:  "Create user",
+		Tags:     []string{"user"},
+		Request:  openApiUserCreateReq{},
+		Response: &openApiUser{},
+	})
+	s.BindOpenApiDoc("GET:/user/:id", ghttp.OpenApiDoc{
+		Summary:  "Get user",
+		Response: openApiUser{},
+	})
+	s.BindOpenApiDoc("GET:/user/list", ghttp.OpenApiDoc{
+		Request:  openApiUserListReq{},
+		Response: []openApiUser{},
+	})
+	s.SetOpenApiConfig(ghttp.OpenApiConfig{
+		Title:    "User API",
+		Envelope: true,
+	})
+	s.EnableOpenApi("/api-docs")
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(strings.Contains(client.GetContent("/api-docs"), "/api-docs/openapi.json"), true)
+
+		j, err := gjson.LoadContent(client.GetContent("/api-docs/openapi.json"))
+		gtest.Assert(err, nil)
+		gtest.Assert(j.GetString("openapi"), "3.0.0")
+		gtest.Assert(j.GetString("info.title"), "User API")
+		gtest.Assert(j.GetString("info.version"), "1.0.0")
+		gtest.Assert(len(j.GetMap("paths")), 3)
+		gtest.Assert(j.Get("paths./api-docs"), nil)
+
+		// POST with request body and validation rules.
+		post := "paths./user.post."
+		gtest.Assert(j.GetString(post+"summary"), "Create user")
+		gtest.Assert(j.GetStrings(post+"tags"), []string{"user"})
+		body := post + "requestBody.content.application/json.schema."
+		gtest.Assert(j.GetStrings(body+"required"), []string{"name"})
+		gtest.Assert(j.GetInt(body+"properties.name.minLength"), 2)
+		gtest.Assert(j.GetInt(body+"properties.name.maxLength"), 16)
+		gtest.Assert(j.GetString(body+"properties.email.format"), "email")
+		gtest.Assert(j.GetArray(body+"properties.status.enum"), []interface{}{0, 1})
+		response := post + "responses.200.content.application/json.schema."
+		gtest.Assert(j.GetString(response+"properties.data.$ref"), "#/components/schemas/openApiUser")
+
+		// Path parameters are converted to {name}.
+		get := "paths./user/{id}.get."
+		gtest.Assert(j.GetString(get+"parameters.0.name"), "id")
+		gtest.Assert(j.GetString(get+"parameters.0.in"), "path")
+
+		// Route for all methods documented with GET only.
+		gtest.Assert(j.Get("paths./user/list.post"), nil)
+		gtest.Assert(j.GetString("paths./user/list.get.parameters.0.in"), "query")
+		gtest.Assert(j.GetInt("paths./user/list.get.parameters.0.schema.minimum"), 1)
+		gtest.Assert(j.GetString("paths./user/list.get.responses.200.content.application/json.schema.properties.data.type"), "array")
+
+		// Components schemas.
+		gtest.Assert(j.GetString("components.schemas.openApiUser.properties.id.type"), "integer")
+		gtest.Assert(j.GetString("components.schemas.openApiUser.properties.name.description"), "user name")
+		gtest.Assert(j.GetString("components.schemas.openApiUser.properties.roles.items.type"), "string")
+	})
+}
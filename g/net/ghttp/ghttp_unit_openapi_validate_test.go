@@ -0,0 +1,129 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+var openApiValidateSpec = `
+{
+  "openapi": "3.0.0",
+  "paths": {
+    "/user/{id}": {
+      "get": {
+        "parameters": [
+          {"name": "id",   "in": "path",  "required": true, "schema": {"type": "integer"}},
+          {"name": "lang", "in": "query", "schema": {"type": "string", "enum": ["en", "zh"]}}
+        ],
+        "responses": {
+          "200": {
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+          }
+        }
+      }
+    },
+    "/user": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+        },
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {
+        "type": "object",
+        "required": ["id", "name"],
+        "properties": {
+          "id":   {"type": "integer"},
+          "name": {"type": "string"}
+        }
+      }
+    }
+  }
+}
+`
+
+func Test_OpenApi_Validator(t *testing.T) {
+	// 仅在开发/测试模式下启用校验
+	os.Setenv("GF_MODE", "test")
+	defer os.Unsetenv("GF_MODE")
+	v, err := ghttp.NewOpenApiValidator(openApiValidateSpec)
+	gtest.Assert(err, nil)
+
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/user/:id", func(r *ghttp.Request) {
+		if r.GetInt("id") == 1 {
+			r.Response.WriteJson(g.Map{"id": 1, "name": "john"})
+		} else {
+			r.Response.WriteJson(g.Map{"id": "2"})
+		}
+	})
+	s.BindHandler("POST:/user", func(r *ghttp.Request) {
+		r.Response.Write("ok")
+	})
+	s.BindOpenApiValidator("/*", v)
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/user/1"), `{"id":1,"name":"john"}`)
+		gtest.Assert(client.GetContent("/user/1?lang=en"), `{"id":1,"name":"john"}`)
+		gtest.Assert(client.GetContent("/user/1?lang=fr"), `"lang" should be one of [en zh]`)
+		gtest.Assert(client.GetContent("/user/john"), `"id" should be an integer`)
+		gtest.Assert(client.GetContent("/user/2"), `"response.name" is required`)
+
+		client.SetHeader("Content-Type", "application/json")
+		gtest.Assert(client.PostContent("/user", `{"id":1,"name":"john"}`), "ok")
+		gtest.Assert(client.PostContent("/user", `{"id":1}`), `"body.name" is required`)
+		gtest.Assert(client.PostContent("/user", `{"id":1.5,"name":"john"}`), `"body.id" should be an integer`)
+		gtest.Assert(client.PostContent("/user"), `request body is required`)
+	})
+}
+
+func Test_OpenApi_Validator_Disabled(t *testing.T) {
+	v, err := ghttp.NewOpenApiValidator(openApiValidateSpec)
+	gtest.Assert(err, nil)
+
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/user/:id", func(r *ghttp.Request) {
+		r.Response.Write("ok")
+	})
+	// 非开发/测试模式下不启用校验
+	s.BindOpenApiValidator("/*", v)
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/user/john"), "ok")
+	})
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_RateLimiter(t *testing.T) {
+	limiter := ghttp.NewRateLimiter(60, 2)
+	limiter.SetQuotaResolver(func(r *ghttp.Request) *ghttp.RateLimitQuota {
+		switch r.Header.Get("X-Api-Key") {
+		case "internal":
+			return &ghttp.RateLimitQuota{}
+		case "gold":
+			return &ghttp.RateLimitQuota{Key: "gold", PerMinute: 600, Burst: 5}
+		}
+		return nil
+	})
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/api", func(r *ghttp.Request) {
+		r.Response.Write("ok")
+	})
+	s.BindRateLimiter("/api", limiter)
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		resp, err := client.Get("/api")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.ReadAllString(), "ok")
+		gtest.Assert(resp.Header.Get("X-RateLimit-Limit"), "2")
+		gtest.Assert(resp.Header.Get("X-RateLimit-Remaining"), "1")
+		resp.Close()
+		gtest.Assert(client.GetContent("/api"), "ok")
+
+		resp, err = client.Get("/api")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.StatusCode, 429)
+		gtest.Assert(resp.Header.Get("X-RateLimit-Remaining"), "0")
+		gtest.Assert(resp.Header.Get("Retry-After"), "1")
+		resp.Close()
+
+		// The forwarding headers of untrusted clients are ignored.
+		client.SetHeader("X-Real-IP", "10.0.0.1")
+		client.SetHeader("X-Forwarded-For", "10.0.0.2")
+		resp, err = client.Get("/api")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.StatusCode, 429)
+		resp.Close()
+
+		// Bypass.
+		client.SetHeader("X-Api-Key", "internal")
+		for i := 0; i < 5; i++ {
+			resp, err = client.Get("/api")
+			gtest.Assert(err, nil)
+			gtest.Assert(resp.StatusCode, 200)
+			gtest.Assert(resp.Header.Get("X-RateLimit-Limit"), "")
+			resp.Close()
+		}
+
+		// Higher tier.
+		client.SetHeader("X-Api-Key", "gold")
+		for i := 0; i < 5; i++ {
+			gtest.Assert(client.GetContent("/api"), "ok")
+		}
+		resp, err = client.Get("/api")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.StatusCode, 429)
+		resp.Close()
+	})
+	gtest.Case(t, func() {
+		limiter := ghttp.NewRateLimiter(60)
+		result := limiter.TakeKey("key", 120, 1)
+		gtest.Assert(result.Allowed, true)
+		gtest.Assert(result.Remaining, 0)
+		result = limiter.TakeKey("key", 120, 1)
+		gtest.Assert(result.Allowed, false)
+		time.Sleep(600 * time.Millisecond)
+		result = limiter.TakeKey("key", 120, 1)
+		gtest.Assert(result.Allowed, true)
+	})
+}
+
+func Test_RateLimiter_TrustedProxies(t *testing.T) {
+	limiter := ghttp.NewRateLimiter(60, 1)
+	gtest.AssertNE(limiter.SetTrustedProxies("invalid"), nil)
+	gtest.Assert(limiter.SetTrustedProxies("127.0.0.1", "10.0.0.0/8"), nil)
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/api", func(r *ghttp.Request) {
+		r.Response.Write("ok")
+	})
+	s.BindRateLimiter("/api", limiter)
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		status := func(header, value string) int {
+			client := ghttp.NewClient()
+			client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+			if header != "" {
+				client.SetHeader(header, value)
+			}
+			resp, err := client.Get("/api")
+			gtest.Assert(err, nil)
+			defer resp.Close()
+			return resp.StatusCode
+		}
+		gtest.Assert(status("X-Real-IP", "1.1.1.1"), 200)
+		gtest.Assert(status("X-Real-IP", "1.1.1.1"), 429)
+		gtest.Assert(status("X-Real-IP", "2.2.2.2"), 200)
+		// The last untrusted IP of X-Forwarded-For is the client IP.
+		gtest.Assert(status("X-Forwarded-For", "3.3.3.3, 10.0.0.1"), 200)
+		gtest.Assert(status("X-Forwarded-For", "4.4.4.4, 3.3.3.3, 10.0.0.2"), 429)
+		gtest.Assert(status("X-Forwarded-For", "3.3.3.3, 4.4.4.4"), 200)
+		// The remote IP is used without forwarding headers.
+		gtest.Assert(status("", ""), 200)
+		gtest.Assert(status("", ""), 429)
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Request_Body(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHookHandlerByMap("/body/*", map[string]ghttp.HandlerFunc{
+		"BeforeServe": func(r *ghttp.Request) {
+			body, err := r.GetBody()
+			if err != nil {
+				r.Response.Write(err.Error(), ":")
+				return
+			}
+			r.Response.Write(len(body), ":")
+		},
+	})
+	s.BindHandler("/body/form", func(r *ghttp.Request) {
+		body, _ := r.GetBody()
+		r.Response.Write(r.GetPostString("name"), ":", string(body))
+	})
+	s.BindHandler("/body/raw", func(r *ghttp.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Response.Write(len(body), ":", len(r.GetRaw()))
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.SetBodyBufferSize(10)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.PostContent("/body/form", "name=john"), "9:john:name=john")
+		gtest.Assert(client.PostContent("/body/raw", "0123456789"), "10:10:10")
+		// 超过缓冲大小限制的请求内容只能读取一次
+		large := strings.Repeat("0", 20)
+		gtest.Assert(client.PostContent("/body/raw", large), ghttp.ErrBodyTooLarge.Error()+":20:0")
+	})
+}
+
+func Test_Request_Clone(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	ch := make(chan string, 1)
+	s.BindHandler("/clone/:name", func(r *ghttp.Request) {
+		r.SetParam("param", "p")
+		clone := r.Clone()
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			body, _ := clone.GetBody()
+			ch <- fmt.Sprintf("%s:%s:%s:%s:%s:%v",
+				clone.Get("name"), clone.GetQueryString("id"), clone.GetPostString("nickname"),
+				clone.GetParam("param").String(), string(body), clone.Context().Err(),
+			)
+		}()
+		r.Response.Write("ok")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.PostContent("/clone/john?id=1", "nickname=J"), "ok")
+		select {
+		case s := <-ch:
+			gtest.Assert(s, "john:1:J:p:nickname=J:<nil>")
+		case <-time.After(time.Second):
+			t.Error("clone timeout")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+// Business handler written against the handler-facing interfaces.
+func interfaceHelloHandler(r ghttp.HandlerRequest) {
+	name := r.Get("name")
+	if name == "" {
+		r.GetResponse().WriteStatus(http.StatusBadRequest, "name required")
+		r.Exit()
+	}
+	r.GetResponse().Header().Set("X-Token", r.GetHeader("X-Token"))
+	r.GetResponse().Writef("hello %s, %d", name, r.GetInt("age", 18))
+}
+
+func Test_RequestInterface_Memory(t *testing.T) {
+	gtest.Case(t, func() {
+		r := ghttp.NewMemoryRequest(g.Map{"name": "john", "age": 20})
+		r.Header.Set("X-Token", "abc")
+		resp := r.Serve(interfaceHelloHandler)
+		gtest.Assert(resp.Status, 0)
+		gtest.Assert(resp.BufferString(), "hello john, 20")
+		gtest.Assert(resp.Header().Get("X-Token"), "abc")
+
+		r = ghttp.NewMemoryRequest(nil)
+		resp = r.Serve(interfaceHelloHandler)
+		gtest.Assert(r.IsExited(), true)
+		gtest.Assert(resp.Status, http.StatusBadRequest)
+		gtest.Assert(resp.BufferString(), "name required")
+	})
+	gtest.Case(t, func() {
+		type User struct {
+			Name string
+			Age  int
+		}
+		r := ghttp.NewMemoryRequest(g.Map{"name": "john", "age": 20}, []byte(`{"id":1}`))
+		user := new(User)
+		gtest.Assert(r.Parse(user), nil)
+		gtest.Assert(user.Name, "john")
+		gtest.Assert(user.Age, 20)
+		gtest.Assert(r.GetJson().GetInt("id"), 1)
+		gtest.Assert(r.GetMap()["age"], "20")
+		r.SetParam("uid", 100)
+		gtest.Assert(r.GetParam("uid").Int(), 100)
+		gtest.Assert(r.GetResponse().WriteJson(g.Map{"id": 1}), nil)
+		gtest.Assert(string(r.GetResponse().Buffer()), `{"id":1}`)
+	})
+}
+
+func Test_RequestInterface_Server(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/hello", ghttp.WrapHandler(interfaceHelloHandler))
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+		client.SetHeader("X-Token", "abc")
+
+		resp, err := client.Get("/hello?name=john")
+		gtest.Assert(err, nil)
+		defer resp.Close()
+		gtest.Assert(resp.Header.Get("X-Token"), "abc")
+		gtest.Assert(resp.ReadAllString(), "hello john, 18")
+
+		resp2, err := client.Get("/hello")
+		gtest.Assert(err, nil)
+		defer resp2.Close()
+		gtest.Assert(resp2.StatusCode, http.StatusBadRequest)
+		gtest.Assert(resp2.ReadAllString(), "name required")
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 统一JSON返回格式测试
+package ghttp_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Response_Json(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/ok", func(r *ghttp.Request) {
+		r.Response.WriteJsonOk(g.Map{"id": 1})
+	})
+	s.BindHandler("/error", func(r *ghttp.Request) {
+		r.Response.WriteJsonError(404, "not found")
+	})
+	s.BindHandler("/code", func(r *ghttp.Request) {
+		r.Response.WriteJsonError(10001, "invalid name")
+	})
+	s.BindHandler("/result-ok", func(r *ghttp.Request) {
+		r.Response.WriteJsonResult("john", nil)
+	})
+	s.BindHandler("/result-json-error", func(r *ghttp.Request) {
+		r.Response.WriteJsonResult(nil, ghttp.NewJsonError(10002, "forbidden", 403))
+	})
+	s.BindHandler("/result-error", func(r *ghttp.Request) {
+		r.Response.WriteJsonResult(nil, errors.New("db error"))
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/ok"), `{"code":0,"data":{"id":1},"message":"ok"}`)
+		gtest.Assert(client.GetContent("/code"), `{"code":10001,"data":null,"message":"invalid name"}`)
+		gtest.Assert(client.GetContent("/result-ok"), `{"code":0,"data":"john","message":"ok"}`)
+
+		resp, err := client.Get("/error")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.StatusCode, 404)
+		gtest.Assert(resp.ReadAllString(), `{"code":404,"data":null,"message":"not found"}`)
+		resp.Close()
+
+		resp, err = client.Get("/result-json-error")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.StatusCode, 403)
+		gtest.Assert(resp.ReadAllString(), `{"code":10002,"data":null,"message":"forbidden"}`)
+		resp.Close()
+
+		resp, err = client.Get("/result-error")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.StatusCode, 500)
+		gtest.Assert(resp.ReadAllString(), `{"code":500,"data":null,"message":"db error"}`)
+		resp.Close()
+	})
+}
+
+func Test_Response_JsonWrapper(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.SetJsonWrapper(func(r *ghttp.Request, code int, message string, data interface{}) interface{} {
+		return g.Map{"errno": code, "errmsg": message, "result": data}
+	})
+	s.BindHandler("/ok", func(r *ghttp.Request) {
+		r.Response.WriteJsonOk(1)
+	})
+	s.BindHandler("/error", func(r *ghttp.Request) {
+		r.Response.WriteJsonError(10001, "invalid name", "name")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/ok"), `{"errmsg":"ok","errno":0,"result":1}`)
+		gtest.Assert(client.GetContent("/error"), `{"errmsg":"invalid name","errno":10001,"result":"name"}`)
+	})
+}
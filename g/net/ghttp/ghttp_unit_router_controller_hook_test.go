@@ -0,0 +1,80 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/container/gtype"
+	"github.com/gogf/gf/g/frame/gmvc"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+// 注入服务对象的控制器
+type ControllerHook struct {
+	gmvc.Controller
+	prefix  string
+	service string
+}
+
+func (c *ControllerHook) SetService(service string) {
+	c.service = service
+}
+
+func (c *ControllerHook) Show() {
+	c.Response.Write(c.prefix + c.service)
+	c.prefix = "changed"
+}
+
+func (c *ControllerHook) Stop() {
+	c.Response.Write("stop")
+	c.Exit()
+}
+
+func Test_Router_ControllerHooks(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	shut := gtype.NewInt()
+	s.SetControllerHooks(ghttp.ControllerHooks{
+		Inject: func(r *ghttp.Request, c ghttp.Controller) {
+			if v, ok := c.(interface{ SetService(string) }); ok {
+				v.SetService("service")
+			}
+		},
+		Init: func(r *ghttp.Request, c ghttp.Controller) {
+			if r.Get("deny") != "" {
+				r.Response.Write("denied")
+				r.ExitAll()
+			}
+		},
+		Shut: func(r *ghttp.Request, c ghttp.Controller) {
+			shut.Add(1)
+		},
+	})
+	s.BindController("/hook", &ControllerHook{prefix: "prefix-"})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/hook/show"), "prefix-service")
+		gtest.Assert(client.GetContent("/hook/show"), "prefix-service")
+		gtest.Assert(client.GetContent("/hook/show?deny=1"), "denied")
+		gtest.Assert(client.GetContent("/hook/stop"), "stop")
+		gtest.Assert(shut.Val(), 4)
+	})
+}
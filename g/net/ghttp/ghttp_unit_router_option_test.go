@@ -0,0 +1,140 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 路由匹配选项测试
+package ghttp_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+// 不自动跳转的请求客户端，返回状态码及Location
+func getRouteOptionRedirect(url string) (int, string) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, ""
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("Location")
+}
+
+func Test_Router_Option_Default(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/user/info", func(r *ghttp.Request) {
+		r.Response.Write("info")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/user/info"), "info")
+		gtest.Assert(client.GetContent("/user/info/"), "info")
+		gtest.Assert(client.GetContent("/User/Info"), "Not Found")
+		gtest.Assert(client.GetContent("/user//info"), "Not Found")
+	})
+}
+
+func Test_Router_Option_Server(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/user/:name", func(r *ghttp.Request) {
+		r.Response.Write(r.Get("name"))
+	})
+	s.SetRouteOption(ghttp.RouteOption{
+		TrailingSlash:   ghttp.TRAILING_SLASH_REDIRECT,
+		CaseInsensitive: true,
+		CleanPath:       true,
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		prefix := fmt.Sprintf("http://127.0.0.1:%d", p)
+		client := ghttp.NewClient()
+		client.SetPrefix(prefix)
+
+		gtest.Assert(client.GetContent("/user/john"), "john")
+		gtest.Assert(client.GetContent("/USER/John"), "John")
+		gtest.Assert(client.GetContent("/user/john/"), "john")
+		gtest.Assert(client.GetContent("/user//john"), "john")
+
+		code, location := getRouteOptionRedirect(prefix + "/user/john/?id=1")
+		gtest.Assert(code, http.StatusMovedPermanently)
+		gtest.Assert(location, "/user/john?id=1")
+
+		code, location = getRouteOptionRedirect(prefix + "//user//john")
+		gtest.Assert(code, http.StatusMovedPermanently)
+		gtest.Assert(location, "/user/john")
+
+		code, location = getRouteOptionRedirect(prefix + "/user/john")
+		gtest.Assert(code, http.StatusOK)
+		gtest.Assert(location, "")
+	})
+}
+
+func Test_Router_Option_Group(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/home", func(r *ghttp.Request) {
+		r.Response.Write("home")
+	})
+	s.Group("/strict").SetRouteOption(ghttp.RouteOption{
+		TrailingSlash: ghttp.TRAILING_SLASH_STRICT,
+	}).ALL("/info", func(r *ghttp.Request) {
+		r.Response.Write("strict")
+	})
+	s.Group("/ci").SetRouteOption(ghttp.RouteOption{
+		CaseInsensitive: true,
+	}).ALL("/info", func(r *ghttp.Request) {
+		r.Response.Write("ci")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/home"), "home")
+		gtest.Assert(client.GetContent("/home/"), "home")
+		gtest.Assert(client.GetContent("/HOME"), "Not Found")
+
+		gtest.Assert(client.GetContent("/strict/info"), "strict")
+		gtest.Assert(client.GetContent("/strict/info/"), "Not Found")
+
+		gtest.Assert(client.GetContent("/ci/info"), "ci")
+		gtest.Assert(client.GetContent("/CI/Info"), "ci")
+		gtest.Assert(client.GetContent("/ci/info/"), "ci")
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_ServeContent(t *testing.T) {
+	modtime := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/media", func(r *ghttp.Request) {
+		r.Response.ServeContent("media.txt", modtime, strings.NewReader("0123456789"))
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		prefix := fmt.Sprintf("http://127.0.0.1:%d", p)
+		client := ghttp.NewClient()
+		client.SetPrefix(prefix)
+
+		resp, err := client.Get("/media")
+		gtest.Assert(err, nil)
+		defer resp.Close()
+		gtest.Assert(resp.StatusCode, http.StatusOK)
+		gtest.Assert(resp.Header.Get("Accept-Ranges"), "bytes")
+		gtest.Assert(resp.Header.Get("Content-Type"), "text/plain; charset=utf-8")
+		gtest.Assert(resp.Header.Get("Last-Modified"), modtime.Format(http.TimeFormat))
+		gtest.Assert(resp.ReadAllString(), "0123456789")
+
+		rangeClient := ghttp.NewClient()
+		rangeClient.SetPrefix(prefix)
+		rangeClient.SetHeader("Range", "bytes=2-5")
+		resp2, err := rangeClient.Get("/media")
+		gtest.Assert(err, nil)
+		defer resp2.Close()
+		gtest.Assert(resp2.StatusCode, http.StatusPartialContent)
+		gtest.Assert(resp2.Header.Get("Content-Range"), "bytes 2-5/10")
+		gtest.Assert(resp2.ReadAllString(), "2345")
+
+		// If-Range不匹配时返回全部内容
+		rangeClient.SetHeader("If-Range", modtime.Add(-time.Hour).Format(http.TimeFormat))
+		resp3, err := rangeClient.Get("/media")
+		gtest.Assert(err, nil)
+		defer resp3.Close()
+		gtest.Assert(resp3.StatusCode, http.StatusOK)
+		gtest.Assert(resp3.ReadAllString(), "0123456789")
+
+		invalidClient := ghttp.NewClient()
+		invalidClient.SetPrefix(prefix)
+		invalidClient.SetHeader("Range", "bytes=20-30")
+		resp4, err := invalidClient.Get("/media")
+		gtest.Assert(err, nil)
+		defer resp4.Close()
+		gtest.Assert(resp4.StatusCode, http.StatusRequestedRangeNotSatisfiable)
+
+		cacheClient := ghttp.NewClient()
+		cacheClient.SetPrefix(prefix)
+		cacheClient.SetHeader("If-Modified-Since", modtime.Format(http.TimeFormat))
+		resp5, err := cacheClient.Get("/media")
+		gtest.Assert(err, nil)
+		defer resp5.Close()
+		gtest.Assert(resp5.StatusCode, http.StatusNotModified)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gogf/gf/g"
 	"github.com/gogf/gf/g/net/ghttp"
 	"github.com/gogf/gf/g/test/gtest"
+	"sync"
 	"testing"
 	"time"
 )
@@ -63,3 +64,125 @@ func Test_Session(t *testing.T) {
 		gtest.Assert(client.GetContent("/get?k=key2"), "")
 	})
 }
+
+// 记录写入操作的session存储
+type testSessionStorage struct {
+	mu      sync.Mutex
+	data    map[string]map[string]interface{}
+	writes  []string
+	updates int
+}
+
+func (s *testSessionStorage) Get(id string) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data[id] == nil {
+		return nil, nil
+	}
+	m := make(map[string]interface{})
+	for k, v := range s.data[id] {
+		m[k] = v
+	}
+	return m, nil
+}
+
+func (s *testSessionStorage) Set(id string, data map[string]interface{}, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes = append(s.writes, fmt.Sprintf("set:%d", len(data)))
+	s.data[id] = data
+	return nil
+}
+
+func (s *testSessionStorage) SetValues(id string, values map[string]interface{}, removed []string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes = append(s.writes, fmt.Sprintf("values:%d:%d", len(values), len(removed)))
+	if s.data[id] == nil {
+		s.data[id] = make(map[string]interface{})
+	}
+	for k, v := range values {
+		s.data[id][k] = v
+	}
+	for _, k := range removed {
+		delete(s.data[id], k)
+	}
+	return nil
+}
+
+func (s *testSessionStorage) UpdateTTL(id string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates++
+	return nil
+}
+
+func (s *testSessionStorage) stat() ([]string, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writes, updates := s.writes, s.updates
+	s.writes, s.updates = nil, 0
+	return writes, updates
+}
+
+func Test_SessionStorage(t *testing.T) {
+	storage := &testSessionStorage{data: make(map[string]map[string]interface{})}
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.SetSessionStorage(storage)
+	s.BindHandler("/set", func(r *ghttp.Request) {
+		r.Session.Set(r.Get("k"), r.Get("v"))
+	})
+	s.BindHandler("/get", func(r *ghttp.Request) {
+		r.Response.Write(r.Session.Get(r.Get("k")))
+	})
+	s.BindHandler("/remove", func(r *ghttp.Request) {
+		r.Session.Remove(r.Get("k"))
+	})
+	s.BindHandler("/clear", func(r *ghttp.Request) {
+		r.Session.Clear()
+		r.Session.Set("k3", "300")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetBrowserMode(true)
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		// 没有数据的新session不写入存储
+		gtest.Assert(client.GetContent("/get?k=k1"), "")
+		writes, updates := storage.stat()
+		gtest.Assert(len(writes), 0)
+		gtest.Assert(updates, 0)
+
+		gtest.Assert(client.GetContent("/set?k=k1&v=100"), "")
+		gtest.Assert(client.GetContent("/set?k=k2&v=200"), "")
+		writes, _ = storage.stat()
+		gtest.Assert(writes, []string{"values:1:0", "values:1:0"})
+
+		// 只读请求仅更新过期时间
+		gtest.Assert(client.GetContent("/get?k=k1"), "100")
+		gtest.Assert(client.GetContent("/get?k=k2"), "200")
+		writes, updates = storage.stat()
+		gtest.Assert(len(writes), 0)
+		gtest.Assert(updates, 2)
+
+		gtest.Assert(client.GetContent("/remove?k=k1"), "")
+		writes, _ = storage.stat()
+		gtest.Assert(writes, []string{"values:0:1"})
+		gtest.Assert(client.GetContent("/get?k=k1"), "")
+		gtest.Assert(client.GetContent("/get?k=k2"), "200")
+
+		// 清空后整体写入
+		gtest.Assert(client.GetContent("/clear"), "")
+		writes, _ = storage.stat()
+		gtest.Assert(writes, []string{"set:1"})
+		gtest.Assert(client.GetContent("/get?k=k2"), "")
+		gtest.Assert(client.GetContent("/get?k=k3"), "300")
+	})
+}
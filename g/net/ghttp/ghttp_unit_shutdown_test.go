@@ -0,0 +1,175 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 优雅关闭测试
+package ghttp_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/container/gtype"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+	"github.com/gogf/gf/third/github.com/gorilla/websocket"
+)
+
+func Test_Shutdown_Drain(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	hooked := gtype.NewBool()
+	s.BindHandler("/slow", func(r *ghttp.Request) {
+		time.Sleep(500 * time.Millisecond)
+		r.Response.Write("done")
+	})
+	s.BindShutdownHandler(func() {
+		hooked.Set(true)
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		result := make(chan string, 1)
+		go func() {
+			result <- client.GetContent("/slow")
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		gtest.Assert(s.Shutdown(ctx), nil)
+		gtest.Assert(hooked.Val(), true)
+		gtest.Assert(s.IsShuttingDown(), true)
+		gtest.Assert(<-result, "done")
+		// 不再接收新的连接
+		gtest.Assert(client.GetContent("/slow"), "")
+	})
+}
+
+func Test_Shutdown_Timeout(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/slow", func(r *ghttp.Request) {
+		time.Sleep(2 * time.Second)
+		r.Response.Write("done")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		go client.GetContent("/slow")
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		gtest.Assert(s.Shutdown(ctx), context.DeadlineExceeded)
+		gtest.Assert(time.Since(start) < time.Second, true)
+	})
+}
+
+func Test_Shutdown_WebSocket(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	closed := gtype.NewBool()
+	s.BindHandler("/ws", func(r *ghttp.Request) {
+		ws, err := r.WebSocket()
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				closed.Set(websocket.IsCloseError(err, websocket.CloseGoingAway))
+				return
+			}
+		}
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://127.0.0.1:%d/ws", p), nil)
+		gtest.Assert(err, nil)
+		defer conn.Close()
+		// 客户端收到关闭消息后回复关闭消息
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		gtest.Assert(s.Shutdown(ctx), nil)
+		gtest.Assert(closed.Val(), true)
+	})
+}
+
+func Test_Shutdown_WebSocketDeregister(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	// 处理方法返回时未关闭连接
+	s.BindHandler("/return", func(r *ghttp.Request) {
+		r.WebSocket()
+	})
+	// 客户端关闭连接后处理方法仍未返回
+	s.BindHandler("/read", func(r *ghttp.Request) {
+		ws, err := r.WebSocket()
+		if err != nil {
+			return
+		}
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				break
+			}
+		}
+		time.Sleep(3 * time.Second)
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		conn1, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://127.0.0.1:%d/return", p), nil)
+		gtest.Assert(err, nil)
+		defer conn1.Close()
+		conn2, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://127.0.0.1:%d/read", p), nil)
+		gtest.Assert(err, nil)
+		conn2.Close()
+		time.Sleep(100 * time.Millisecond)
+
+		// 已经移除的连接不再等待
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		gtest.Assert(s.Shutdown(ctx), nil)
+		gtest.Assert(time.Since(start) < time.Second, true)
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 静态挂载测试
+package ghttp_test
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/gcfg"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Static_Mount(t *testing.T) {
+	gtest.Case(t, func() {
+		p := ports.PopRand()
+		s := g.Server(p)
+		path := fmt.Sprintf(`%s/ghttp/static/mount/%d`, gfile.TempDir(), p)
+		defer gfile.Remove(path)
+		gfile.PutContents(path+"/assets/app.js", "app")
+		gfile.PutContents(path+"/assets/index.html", "index")
+		// 资源包
+		f, err := os.Create(path + "/docs.zip")
+		gtest.Assert(err, nil)
+		w := zip.NewWriter(f)
+		zf, _ := w.Create("guide/index.html")
+		zf.Write([]byte("guide"))
+		gtest.Assert(w.Close(), nil)
+		f.Close()
+
+		err = s.SetStaticMounts([]ghttp.StaticMount{
+			{Prefix: "/static", Path: path + "/assets", MaxAge: 60},
+			{Prefix: "/docs", Path: path + "/docs.zip", CacheControl: "no-cache"},
+		})
+		gtest.Assert(err, nil)
+		s.BindHandler("/static/api", func(r *ghttp.Request) {
+			r.Response.Write("api")
+		})
+		s.SetPort(p)
+		s.SetDumpRouteMap(false)
+		s.Start()
+		defer s.Shutdown()
+		time.Sleep(time.Second)
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.GetContent("/static/app.js"), "app")
+		gtest.Assert(client.GetContent("/static"), "index")
+		gtest.Assert(client.GetContent("/static/api"), "api")
+		gtest.Assert(client.GetContent("/staticapp.js"), "Not Found")
+		gtest.Assert(client.GetContent("/docs/guide"), "guide")
+		gtest.Assert(client.GetContent("/docs/none.html"), "Not Found")
+
+		resp, err := client.Get("/static/app.js")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.Header.Get("Cache-Control"), "public, max-age=60")
+		resp.Close()
+		resp, err = client.Get("/docs/guide/index.html")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.Header.Get("Cache-Control"), "no-cache")
+		gtest.Assert(resp.ReadAllString(), "guide")
+		resp.Close()
+
+		// 非法的静态挂载不会替换当前的静态挂载
+		err = s.SetStaticMounts([]ghttp.StaticMount{{Prefix: "/none", Path: path + "/none"}})
+		gtest.AssertNE(err, nil)
+		gtest.Assert(client.GetContent("/static/app.js"), "app")
+	})
+}
+
+func Test_Static_Mount_Config(t *testing.T) {
+	gtest.Case(t, func() {
+		p := ports.PopRand()
+		s := g.Server(p)
+		path := fmt.Sprintf(`%s/ghttp/static/config/%d`, gfile.TempDir(), p)
+		defer gfile.Remove(path)
+		gfile.PutContents(path+"/public/app.js", "app")
+		gfile.PutContents(path+"/config.toml", fmt.Sprintf(`
+[[server.static]]
+    prefix       = "/assets"
+    path         = "%s/public"
+    cacheControl = "public, max-age=3600"
+`, path))
+		c := gcfg.New("config.toml")
+		gtest.Assert(c.SetPath(path), nil)
+		gtest.Assert(s.BindStaticConfig("server.static", c), nil)
+		s.SetPort(p)
+		s.SetDumpRouteMap(false)
+		s.Start()
+		defer s.Shutdown()
+		time.Sleep(time.Second)
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		resp, err := client.Get("/assets/app.js")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.ReadAllString(), "app")
+		gtest.Assert(resp.Header.Get("Cache-Control"), "public, max-age=3600")
+		resp.Close()
+
+		// 修改配置文件后自动重新加载
+		gfile.PutContents(path+"/config.toml", fmt.Sprintf(`
+[[server.static]]
+    prefix = "/public"
+    path   = "%s/public"
+    maxAge = 60
+`, path))
+		time.Sleep(500 * time.Millisecond)
+		gtest.Assert(client.GetContent("/assets/app.js"), "Not Found")
+		resp, err = client.Get("/public/app.js")
+		gtest.Assert(err, nil)
+		gtest.Assert(resp.ReadAllString(), "app")
+		gtest.Assert(resp.Header.Get("Cache-Control"), "public, max-age=60")
+		resp.Close()
+	})
+}
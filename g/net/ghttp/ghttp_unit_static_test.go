@@ -270,3 +270,47 @@ func Test_Static_Rewrite(t *testing.T) {
 		gtest.Assert(client.GetContent("/my-test2"), "test2")
 	})
 }
+
+func Test_Static_Asset(t *testing.T) {
+	gtest.Case(t, func() {
+		p := ports.PopRand()
+		s := g.Server(p)
+		path := fmt.Sprintf(`%s/ghttp/static/test/%d`, gfile.TempDir(), p)
+		defer gfile.Remove(path)
+		gfile.PutContents(path+"/js/app.js", "app")
+		s.SetServerRoot(path)
+		s.BindHandler("/page", func(r *ghttp.Request) {
+			r.Response.WriteTplContent(`{{asset "/js/app.js"}}`)
+		})
+		s.SetPort(p)
+		s.SetDumpRouteMap(false)
+		s.Start()
+		defer s.Shutdown()
+		time.Sleep(time.Second)
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		url := s.AssetUrl("/js/app.js")
+		gtest.Assert(url, "/js/app.js?v=d2a57dc1")
+		gtest.Assert(s.AssetUrl("js/app.js?t=1#top"), "/js/app.js?t=1&v=d2a57dc1#top")
+		gtest.Assert(s.AssetUrl("/js/none.js"), "/js/none.js")
+		gtest.Assert(client.GetContent("/page"), url)
+
+		r, err := client.Get(url)
+		gtest.Assert(err, nil)
+		gtest.Assert(r.ReadAllString(), "app")
+		gtest.Assert(r.Header.Get("Cache-Control"), "public, max-age=31536000, immutable")
+		r.Close()
+
+		r, err = client.Get("/js/app.js?v=00000000")
+		gtest.Assert(err, nil)
+		gtest.Assert(r.ReadAllString(), "app")
+		gtest.Assert(r.Header.Get("Cache-Control"), "")
+		r.Close()
+
+		// The hash changes after content updated.
+		time.Sleep(10 * time.Millisecond)
+		gfile.PutContents(path+"/js/app.js", "app2")
+		gtest.AssertNE(s.AssetUrl("/js/app.js"), url)
+	})
+}
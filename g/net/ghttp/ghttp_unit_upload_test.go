@@ -0,0 +1,142 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Upload_Struct(t *testing.T) {
+	type Form struct {
+		Name   string
+		Avatar *ghttp.UploadFile   `valid:"required|ext:png,jpg#请上传头像|头像格式不正确"`
+		Docs   []*ghttp.UploadFile `valid:"max-size:10"`
+	}
+	dir := gfile.TempDir() + gfile.Separator + "ghttp_upload_test"
+	gfile.Mkdir(dir)
+	defer gfile.Remove(dir)
+	gfile.PutContents(dir+"/avatar.png", "png")
+	gfile.PutContents(dir+"/avatar.gif", "gif")
+	gfile.PutContents(dir+"/1.txt", "1")
+	gfile.PutContents(dir+"/2.txt", "22")
+	gfile.PutContents(dir+"/3.txt", "01234567890")
+
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/upload", func(r *ghttp.Request) {
+		form := new(Form)
+		if err := r.Parse(form); err != nil {
+			r.Response.Write(err.Error())
+			return
+		}
+		r.Response.Write(form.Name, ":", form.Avatar.GetFilename(), ":", len(form.Docs))
+		for _, doc := range form.Docs {
+			r.Response.Write(":", doc.GetSize())
+		}
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		gtest.Assert(client.PostContent("/upload", "name=john&avatar=@file:"+dir+"/avatar.png"), "john:avatar.png:0")
+		gtest.Assert(client.PostContent("/upload", "name=john&avatar=@file:"+dir+"/avatar.png&docs=@file:"+dir+"/1.txt&docs=@file:"+dir+"/2.txt"), "john:avatar.png:2:1:2")
+		gtest.Assert(client.PostContent("/upload", "name=john&avatar=@file:"+dir+"/avatar.gif"), "头像格式不正确")
+		gtest.Assert(client.PostContent("/upload", "name=john&docs=@file:"+dir+"/1.txt"), "请上传头像")
+		gtest.Assert(client.PostContent("/upload", "name=john&avatar=@file:"+dir+"/avatar.png&docs=@file:"+dir+"/3.txt"), "文件大小不能超过10")
+	})
+}
+
+func Test_Upload_Inspector(t *testing.T) {
+	type Form struct {
+		File *ghttp.UploadFile `valid:"required"`
+	}
+	dir := gfile.TempDir() + gfile.Separator + "ghttp_upload_inspector_test"
+	gfile.Mkdir(dir)
+	defer gfile.Remove(dir)
+	gfile.PutContents(dir+"/clean.txt", "hello")
+	gfile.PutContents(dir+"/virus.txt", "X5O!P%@AP EICAR")
+	gfile.PutContents(dir+"/image.png", "\x89PNG\x0D\x0A\x1A\x0A")
+
+	p := ports.PopRand()
+	s := g.Server(p)
+	count := 0
+	s.SetUploadInspector(func(r *ghttp.Request, file *ghttp.UploadFile, content io.Reader) error {
+		count++
+		b, _ := ioutil.ReadAll(content)
+		if strings.Contains(string(b), "EICAR") {
+			return errors.New("virus found in " + file.GetFilename())
+		}
+		return nil
+	})
+	s.BindHandler("/bind", func(r *ghttp.Request) {
+		form := new(Form)
+		if err := r.Parse(form); err != nil {
+			r.Response.Write(err.Error())
+			return
+		}
+		if _, err := form.File.Save(dir + "/saved"); err != nil {
+			r.Response.Write(err.Error())
+			return
+		}
+		r.Response.Write("ok:", count)
+	})
+	s.BindHandler("/save", func(r *ghttp.Request) {
+		if _, err := r.GetUploadFile("file").Save(dir + "/saved"); err != nil {
+			r.Response.Write(err.Error())
+			return
+		}
+		r.Response.Write("ok")
+	})
+	s.BindHandler("/mime", func(r *ghttp.Request) {
+		inspector := ghttp.UploadMimeInspector("image/*")
+		file := r.GetUploadFile("file")
+		content, _ := file.Open()
+		defer content.Close()
+		if err := inspector(r, file, content); err != nil {
+			r.Response.Write(err.Error())
+			return
+		}
+		r.Response.Write("ok")
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		// Inspected only once for binding and saving.
+		gtest.Assert(client.PostContent("/bind", "file=@file:"+dir+"/clean.txt"), "ok:1")
+		gtest.Assert(gfile.GetContents(dir+"/saved/clean.txt"), "hello")
+		gtest.Assert(client.PostContent("/bind", "file=@file:"+dir+"/virus.txt"), "virus found in virus.txt")
+		gtest.Assert(client.PostContent("/save", "file=@file:"+dir+"/virus.txt"), "virus found in virus.txt")
+		gtest.Assert(gfile.Exists(dir+"/saved/virus.txt"), false)
+
+		gtest.Assert(client.PostContent("/mime", "file=@file:"+dir+"/image.png"), "ok")
+		gtest.Assert(client.PostContent("/mime", "file=@file:"+dir+"/clean.txt"), `file "clean.txt" of type "text/plain" is not allowed`)
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// WebSocket鉴权及消息拦截器测试
+package ghttp_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/test/gtest"
+	"github.com/gogf/gf/third/github.com/gorilla/websocket"
+)
+
+func Test_WebSocket_Interceptor(t *testing.T) {
+	p := ports.PopRand()
+	s := g.Server(p)
+	// 中间件对WebSocket连接升级请求进行鉴权
+	s.Use(func(r *ghttp.Request) {
+		if r.IsWebSocket() && r.Get("token") != "123" {
+			r.Response.WriteStatus(http.StatusUnauthorized)
+			return
+		}
+		r.Middleware.Next()
+	})
+	s.UseWebSocket(func(ws *ghttp.WebSocket, msg *ghttp.WebSocketMessage) error {
+		if msg.Inbound && bytes.Equal(msg.Data, []byte("drop")) {
+			return ghttp.ErrWebSocketSkip
+		}
+		return nil
+	}, func(ws *ghttp.WebSocket, msg *ghttp.WebSocketMessage) error {
+		if msg.Inbound && len(msg.Data) > 10 {
+			return errors.New("message too large")
+		}
+		return nil
+	})
+	s.BindHandler("/ws", func(r *ghttp.Request) {
+		ws, err := r.WebSocket()
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		ws.Use(func(ws *ghttp.WebSocket, msg *ghttp.WebSocketMessage) error {
+			if !msg.Inbound {
+				msg.Data = append([]byte(ws.Request().Get("token")+":"), msg.Data...)
+			}
+			return nil
+		})
+		for {
+			msgType, data, err := ws.ReadMessage()
+			if err != nil {
+				ws.WriteMessage(ghttp.WS_MSG_TEXT, []byte(err.Error()))
+				return
+			}
+			if err = ws.WriteMessage(msgType, data); err != nil {
+				return
+			}
+		}
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	// 等待启动完成
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		_, resp, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://127.0.0.1:%d/ws", p), nil)
+		gtest.AssertNE(err, nil)
+		gtest.Assert(resp.StatusCode, http.StatusUnauthorized)
+
+		conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://127.0.0.1:%d/ws?token=123", p), nil)
+		gtest.Assert(err, nil)
+		defer conn.Close()
+
+		gtest.Assert(conn.WriteMessage(websocket.TextMessage, []byte("hello")), nil)
+		_, data, err := conn.ReadMessage()
+		gtest.Assert(err, nil)
+		gtest.Assert(string(data), "123:hello")
+
+		// 被丢弃的消息不会返回
+		gtest.Assert(conn.WriteMessage(websocket.TextMessage, []byte("drop")), nil)
+		gtest.Assert(conn.WriteMessage(websocket.TextMessage, []byte("world")), nil)
+		_, data, err = conn.ReadMessage()
+		gtest.Assert(err, nil)
+		gtest.Assert(string(data), "123:world")
+
+		gtest.Assert(conn.WriteMessage(websocket.TextMessage, []byte("hello world")), nil)
+		_, data, err = conn.ReadMessage()
+		gtest.Assert(err, nil)
+		gtest.Assert(string(data), "123:message too large")
+	})
+}
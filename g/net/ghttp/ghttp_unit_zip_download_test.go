@@ -0,0 +1,77 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/encoding/gcompress"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_ZipDownload(t *testing.T) {
+	dir := gfile.TempDir() + gfile.Separator + "ghttp_zip_download_test"
+	gfile.PutContents(dir+"/1.txt", "1")
+	gfile.PutContents(dir+"/2.txt", "22")
+	defer gfile.Remove(dir)
+
+	p := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/download", func(r *ghttp.Request) {
+		r.Response.ServeZipDownload("all.zip", []gcompress.ZipEntry{
+			{Path: dir + "/1.txt"},
+			{Name: "sub/2.txt", Path: dir + "/2.txt"},
+			{Name: "3.txt", Reader: strings.NewReader("333")},
+		})
+	})
+	s.BindHandler("/none", func(r *ghttp.Request) {
+		r.Response.ServeZipDownload("none.zip", []gcompress.ZipEntry{{Path: dir + "/none.txt"}})
+	})
+	s.SetPort(p)
+	s.SetDumpRouteMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(time.Second)
+	gtest.Case(t, func() {
+		client := ghttp.NewClient()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		resp, err := client.Get("/download")
+		gtest.Assert(err, nil)
+		defer resp.Close()
+		gtest.Assert(resp.StatusCode, 200)
+		gtest.Assert(resp.Header.Get("Content-Type"), "application/zip")
+		gtest.Assert(resp.Header.Get("Content-Disposition"), `attachment;filename="all.zip"`)
+		content := resp.ReadAll()
+		reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		gtest.Assert(err, nil)
+		contents := make([]string, 0)
+		for _, file := range reader.File {
+			r, _ := file.Open()
+			b, _ := ioutil.ReadAll(r)
+			r.Close()
+			contents = append(contents, file.Name+":"+string(b))
+		}
+		gtest.Assert(contents, []string{"1.txt:1", "sub/2.txt:22", "3.txt:333"})
+
+		resp2, err := client.Get("/none")
+		gtest.Assert(err, nil)
+		defer resp2.Close()
+		gtest.Assert(resp2.StatusCode, 500)
+		gtest.AssertNE(resp2.Header.Get("Content-Type"), "application/zip")
+	})
+}
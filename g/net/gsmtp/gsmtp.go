@@ -9,19 +9,46 @@
 // eg:
 // s := smtp.New("smtp.exmail.qq.com:25", "notify@a.com", "password")
 // glog.Println(s.SendMail("notify@a.com", "ulric@b.com;rain@c.com", "subject", "body, <font color=red>red</font>"))
+//
+// Multipart messages with attachments are sent with Send:
+// m := gsmtp.NewMessage("notify@a.com", []string{"ulric@b.com"}, "subject")
+// m.Html = `<img src="cid:logo"/>`
+// m.Embed("/path/to/logo.png", "logo")
+// m.Attach("/path/to/report.pdf")
+// glog.Println(s.Send(m))
 package gsmtp
 
 import (
-	"encoding/base64"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/smtp"
 	"strings"
+	"sync"
+)
+
+const (
+	// Switches to TLS with STARTTLS if the server supports it, which is the default mode.
+	TLS_MODE_AUTO = 0
+	// Requires switching to TLS with STARTTLS, it fails if the server does not support it.
+	TLS_MODE_STARTTLS = 1
+	// Uses implicit TLS connection, which is commonly on port 465.
+	TLS_MODE_IMPLICIT = 2
+	// Does not use TLS.
+	TLS_MODE_NONE = 3
 )
 
 type SMTP struct {
-	Address  string
-	Username string
-	Password string
+	Address   string
+	Username  string
+	Password  string
+	TLSMode   int         // TLS mode, see TLS_MODE_*.
+	TLSConfig *tls.Config // Custom TLS configuration, the ServerName is the host of Address in default.
+	PoolSize  int         // Max count of idle connections kept for reusing, 0 means no pooling.
+
+	mu   sync.Mutex
+	idle []*smtp.Client // Idle connections.
 }
 
 // New creates and returns a new SMTP object.
@@ -61,29 +88,153 @@ func (s *SMTP) SendMail(from, tos, subject, body string, contentType ...string)
 		return fmt.Errorf("tos invalid")
 	}
 
-	tos = strings.Join(safeArr, ";")
-	b64 := base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/")
+	message := NewMessage(from, safeArr, subject)
+	if len(contentType) > 0 && contentType[0] == "html" {
+		message.Html = body
+	} else {
+		message.Text = body
+	}
+	return s.Send(message)
+}
 
-	header := make(map[string]string)
-	header["From"] = from
-	header["To"] = tos
-	header["Subject"] = fmt.Sprintf("=?UTF-8?B?%s?=", b64.EncodeToString([]byte(subject)))
-	header["MIME-Version"] = "1.0"
+// Send sends the multipart <message>.
+// The connection is kept for reusing if PoolSize is greater than 0, see Close.
+func (s *SMTP) Send(message *Message) error {
+	content, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	recipients, err := message.Recipients()
+	if err != nil {
+		return err
+	}
+	from, err := parseAddressList([]string{message.From})
+	if err != nil {
+		return err
+	}
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
+	if err := s.send(client, from[0].Address, recipients, content); err != nil {
+		client.Close()
+		return err
+	}
+	s.putClient(client)
+	return nil
+}
 
-	ct := "text/plain; charset=UTF-8"
-	if len(contentType) > 0 && contentType[0] == "html" {
-		ct = "text/html; charset=UTF-8"
+// Close closes all the idle connections in the pool.
+func (s *SMTP) Close() error {
+	s.mu.Lock()
+	idle := s.idle
+	s.idle = nil
+	s.mu.Unlock()
+	for _, client := range idle {
+		client.Quit()
 	}
+	return nil
+}
 
-	header["Content-Type"] = ct
-	header["Content-Transfer-Encoding"] = "base64"
+// send sends <content> with <client>.
+func (s *SMTP) send(client *smtp.Client, from string, recipients []string, content []byte) error {
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(content); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// getClient returns an idle connection from the pool, or creates a new one if there's no available.
+func (s *SMTP) getClient() (*smtp.Client, error) {
+	for {
+		s.mu.Lock()
+		if len(s.idle) == 0 {
+			s.mu.Unlock()
+			break
+		}
+		client := s.idle[len(s.idle)-1]
+		s.idle = s.idle[:len(s.idle)-1]
+		s.mu.Unlock()
+		// Checking whether the connection is still alive.
+		if err := client.Reset(); err == nil {
+			return client, nil
+		}
+		client.Close()
+	}
+	return s.dial()
+}
 
-	message := ""
-	for k, v := range header {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
+// putClient puts <client> back to the pool, or closes it if the pool is full.
+func (s *SMTP) putClient(client *smtp.Client) {
+	s.mu.Lock()
+	if len(s.idle) < s.PoolSize {
+		s.idle = append(s.idle, client)
+		s.mu.Unlock()
+		return
 	}
-	message += "\r\n" + b64.EncodeToString([]byte(body))
+	s.mu.Unlock()
+	client.Quit()
+}
 
-	auth := smtp.PlainAuth("", s.Username, s.Password, hp[0])
-	return smtp.SendMail(s.Address, auth, from, strings.Split(tos, ";"), []byte(message))
+// dial creates a new connection to the server, switches to TLS according to TLSMode,
+// and authenticates if Username is not empty.
+func (s *SMTP) dial() (*smtp.Client, error) {
+	if s.Address == "" {
+		return nil, errors.New("address is necessary")
+	}
+	host, _, err := net.SplitHostPort(s.Address)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := s.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: host}
+	}
+	client := (*smtp.Client)(nil)
+	if s.TLSMode == TLS_MODE_IMPLICIT {
+		conn, err := tls.Dial("tcp", s.Address, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		if client, err = smtp.NewClient(conn, host); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	} else {
+		if client, err = smtp.Dial(s.Address); err != nil {
+			return nil, err
+		}
+	}
+	if s.TLSMode == TLS_MODE_AUTO || s.TLSMode == TLS_MODE_STARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, err
+			}
+		} else if s.TLSMode == TLS_MODE_STARTTLS {
+			client.Close()
+			return nil, errors.New("server does not support STARTTLS")
+		}
+	}
+	if s.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	return client, nil
 }
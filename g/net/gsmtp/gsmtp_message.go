@@ -0,0 +1,351 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsmtp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gf/g/util/grand"
+)
+
+// Message is a mail message, which supports HTML with text alternative,
+// file attachments and inline images referenced by CID.
+//
+// The MIME structure of the message is:
+// multipart/mixed(attachments) > multipart/related(inline images) > multipart/alternative(text and HTML),
+// and the unnecessary levels are omitted.
+type Message struct {
+	From        string            // Sender address, eg: "notify@a.com" or "Notify <notify@a.com>".
+	To          []string          // Recipient addresses.
+	Cc          []string          // Carbon copy addresses.
+	Bcc         []string          // Blind carbon copy addresses, which are not written to the headers.
+	ReplyTo     string            // Reply address.
+	Subject     string            // Subject.
+	Text        string            // Plain text body.
+	Html        string            // HTML body, the inline images can be referenced by "cid:<ContentId>".
+	Headers     map[string]string // Custom headers.
+	Attachments []*Attachment     // Attachments and inline images.
+}
+
+// Attachment is a file attachment or an inline image of message.
+type Attachment struct {
+	Name        string // File name.
+	ContentType string // MIME type, which is detected by the extension of Name if it's empty.
+	ContentId   string // Content id for inline images, the attachment is inline if it's not empty.
+	Content     []byte // File content.
+}
+
+const (
+	// Max line length of base64 encoded content.
+	gBASE64_LINE_LENGTH = 76
+)
+
+// mimePart is a MIME part with its header and content writer.
+type mimePart struct {
+	header textproto.MIMEHeader
+	write  func(w io.Writer) error
+}
+
+// NewMessage creates and returns a message.
+func NewMessage(from string, to []string, subject string) *Message {
+	return &Message{
+		From:    from,
+		To:      to,
+		Subject: subject,
+		Headers: make(map[string]string),
+	}
+}
+
+// Attach adds the file of <path> as attachment.
+func (m *Message) Attach(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	m.AttachContent(filepath.Base(path), content)
+	return nil
+}
+
+// AttachContent adds <content> as attachment named <name>.
+// The optional parameter <contentType> specifies the MIME type of the attachment.
+func (m *Message) AttachContent(name string, content []byte, contentType ...string) {
+	attachment := &Attachment{
+		Name:    name,
+		Content: content,
+	}
+	if len(contentType) > 0 {
+		attachment.ContentType = contentType[0]
+	}
+	m.Attachments = append(m.Attachments, attachment)
+}
+
+// Embed adds the file of <path> as inline image, which can be referenced in HTML by "cid:<cid>".
+func (m *Message) Embed(path string, cid string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	m.EmbedContent(filepath.Base(path), content, cid)
+	return nil
+}
+
+// EmbedContent adds <content> as inline image named <name>, which can be referenced in HTML by "cid:<cid>".
+// The optional parameter <contentType> specifies the MIME type of the image.
+func (m *Message) EmbedContent(name string, content []byte, cid string, contentType ...string) {
+	m.AttachContent(name, content, contentType...)
+	m.Attachments[len(m.Attachments)-1].ContentId = cid
+}
+
+// Recipients returns the envelope addresses of all the recipients, including To, Cc and Bcc.
+func (m *Message) Recipients() ([]string, error) {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	for _, list := range [][]string{m.To, m.Cc, m.Bcc} {
+		addresses, err := parseAddressList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range addresses {
+			recipients = append(recipients, address.Address)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("recipients cannot be empty")
+	}
+	return recipients, nil
+}
+
+// Bytes builds and returns the MIME content of the message.
+func (m *Message) Bytes() ([]byte, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf(`invalid from address "%s": %v`, m.From, err)
+	}
+	buffer := bytes.NewBuffer(nil)
+	writeHeader(buffer, "From", from.String())
+	for _, item := range []struct {
+		name string
+		list []string
+	}{{"To", m.To}, {"Cc", m.Cc}} {
+		addresses, err := parseAddressList(item.list)
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses) > 0 {
+			array := make([]string, len(addresses))
+			for i, address := range addresses {
+				array[i] = address.String()
+			}
+			writeHeader(buffer, item.name, strings.Join(array, ", "))
+		}
+	}
+	if m.ReplyTo != "" {
+		replyTo, err := mail.ParseAddress(m.ReplyTo)
+		if err != nil {
+			return nil, fmt.Errorf(`invalid reply address "%s": %v`, m.ReplyTo, err)
+		}
+		writeHeader(buffer, "Reply-To", replyTo.String())
+	}
+	writeHeader(buffer, "Subject", mime.BEncoding.Encode("UTF-8", m.Subject))
+	writeHeader(buffer, "Date", time.Now().Format(time.RFC1123Z))
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+	writeHeader(buffer, "Message-ID", fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), grand.Digits(8), domain))
+	writeHeader(buffer, "MIME-Version", "1.0")
+	// Custom headers are written in order.
+	names := make([]string, 0, len(m.Headers))
+	for name := range m.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeHeader(buffer, name, mime.QEncoding.Encode("UTF-8", m.Headers[name]))
+	}
+	// Body.
+	part := m.rootPart()
+	writeMIMEHeader(buffer, part.header)
+	buffer.WriteString("\r\n")
+	if err := part.write(buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// rootPart returns the root MIME part of the message body.
+func (m *Message) rootPart() mimePart {
+	var (
+		inlines     = make([]mimePart, 0)
+		attachments = make([]mimePart, 0)
+	)
+	for _, attachment := range m.Attachments {
+		if attachment.ContentId != "" {
+			inlines = append(inlines, attachment.part())
+		} else {
+			attachments = append(attachments, attachment.part())
+		}
+	}
+	// Text and HTML.
+	part := textPart("text/html", m.Html)
+	switch {
+	case m.Html == "":
+		part = textPart("text/plain", m.Text)
+	case m.Text != "":
+		part = multipartPart("alternative", []mimePart{
+			textPart("text/plain", m.Text),
+			part,
+		})
+	}
+	// Inline images.
+	if len(inlines) > 0 {
+		part = multipartPart("related", append([]mimePart{part}, inlines...))
+	}
+	// Attachments.
+	if len(attachments) > 0 {
+		part = multipartPart("mixed", append([]mimePart{part}, attachments...))
+	}
+	return part
+}
+
+// part returns the MIME part of the attachment.
+func (a *Attachment) part() mimePart {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(a.Name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", make(map[string]string)
+	}
+	params["name"] = a.Name
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	header.Set("Content-Transfer-Encoding", "base64")
+	if a.ContentId != "" {
+		header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": a.Name}))
+		header.Set("Content-ID", "<"+a.ContentId+">")
+	} else {
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Name}))
+	}
+	return mimePart{
+		header: header,
+		write: func(w io.Writer) error {
+			return writeBase64(w, a.Content)
+		},
+	}
+}
+
+// textPart returns a text MIME part of <contentType> in quoted-printable encoding.
+func textPart(contentType string, content string) mimePart {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=UTF-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	return mimePart{
+		header: header,
+		write: func(w io.Writer) error {
+			writer := quotedprintable.NewWriter(w)
+			if _, err := writer.Write([]byte(content)); err != nil {
+				return err
+			}
+			return writer.Close()
+		},
+	}
+}
+
+// multipartPart returns a multipart MIME part of <subtype> with <parts>.
+func multipartPart(subtype string, parts []mimePart) mimePart {
+	boundary := multipart.NewWriter(nil).Boundary()
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary}))
+	return mimePart{
+		header: header,
+		write: func(w io.Writer) error {
+			writer := multipart.NewWriter(w)
+			if err := writer.SetBoundary(boundary); err != nil {
+				return err
+			}
+			for _, part := range parts {
+				partWriter, err := writer.CreatePart(part.header)
+				if err != nil {
+					return err
+				}
+				if err := part.write(partWriter); err != nil {
+					return err
+				}
+			}
+			return writer.Close()
+		},
+	}
+}
+
+// writeBase64 writes <content> to <w> in base64 encoding with line wrapping.
+func writeBase64(w io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 0 {
+		n := gBASE64_LINE_LENGTH
+		if n > len(encoded) {
+			n = len(encoded)
+		}
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// writeHeader writes a header line to <buffer>.
+func writeHeader(buffer *bytes.Buffer, name string, value string) {
+	buffer.WriteString(name + ": " + value + "\r\n")
+}
+
+// writeMIMEHeader writes <header> to <buffer> in order.
+func writeMIMEHeader(buffer *bytes.Buffer, header textproto.MIMEHeader) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			writeHeader(buffer, name, value)
+		}
+	}
+}
+
+// parseAddressList parses the addresses in <list>,
+// each item of which can also be multiple addresses separated by ',' or ';'.
+func parseAddressList(list []string) ([]*mail.Address, error) {
+	addresses := make([]*mail.Address, 0, len(list))
+	for _, item := range list {
+		for _, s := range strings.Split(item, ";") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			array, err := mail.ParseAddressList(s)
+			if err != nil {
+				return nil, fmt.Errorf(`invalid address "%s": %v`, s, err)
+			}
+			addresses = append(addresses, array...)
+		}
+	}
+	return addresses, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsmtp_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/g/net/gsmtp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+// readMessage parses the MIME content of <message>.
+func readMessage(message *gsmtp.Message) *mail.Message {
+	content, err := message.Bytes()
+	gtest.Assert(err, nil)
+	m, err := mail.ReadMessage(bytes.NewReader(content))
+	gtest.Assert(err, nil)
+	return m
+}
+
+// readParts reads the parts of multipart <body> in <contentType>,
+// it returns the media type of each part and the decoded content.
+func readParts(contentType string, body io.Reader) (types []string, parts []*multipart.Part, contents []string) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	gtest.Assert(err, nil)
+	gtest.Assert(strings.HasPrefix(mediaType, "multipart/"), true)
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		gtest.Assert(err, nil)
+		b, err := ioutil.ReadAll(part)
+		gtest.Assert(err, nil)
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		types = append(types, partType)
+		parts = append(parts, part)
+		contents = append(contents, string(b))
+	}
+	return
+}
+
+func Test_Message_Text(t *testing.T) {
+	gtest.Case(t, func() {
+		message := gsmtp.NewMessage("Notify <notify@a.com>", []string{"john@a.com, Rob <rob@a.com>"}, "你好 gf")
+		message.Cc = []string{"smith@a.com; lee@a.com"}
+		message.Bcc = []string{"boss@a.com"}
+		message.ReplyTo = "support@a.com"
+		message.Headers["X-Priority"] = "1"
+		message.Text = "long line " + strings.Repeat("text ", 30) + "\r\nend=中文"
+
+		m := readMessage(message)
+		gtest.Assert(m.Header.Get("From"), `"Notify" <notify@a.com>`)
+		gtest.Assert(m.Header.Get("To"), `<john@a.com>, "Rob" <rob@a.com>`)
+		gtest.Assert(m.Header.Get("Cc"), `<smith@a.com>, <lee@a.com>`)
+		gtest.Assert(m.Header.Get("Bcc"), "")
+		gtest.Assert(m.Header.Get("Reply-To"), `<support@a.com>`)
+		gtest.Assert(m.Header.Get("X-Priority"), "1")
+		gtest.Assert(m.Header.Get("MIME-Version"), "1.0")
+		gtest.Assert(strings.HasSuffix(m.Header.Get("Message-ID"), "@a.com>"), true)
+		_, err := m.Header.Date()
+		gtest.Assert(err, nil)
+		subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+		gtest.Assert(err, nil)
+		gtest.Assert(subject, "你好 gf")
+
+		// The text body is quoted-printable encoded with short lines.
+		gtest.Assert(m.Header.Get("Content-Type"), "text/plain; charset=UTF-8")
+		gtest.Assert(m.Header.Get("Content-Transfer-Encoding"), "quoted-printable")
+		body, err := ioutil.ReadAll(m.Body)
+		gtest.Assert(err, nil)
+		for _, line := range strings.Split(string(body), "\r\n") {
+			gtest.Assert(len(line) <= 76, true)
+		}
+		text, err := ioutil.ReadAll(quotedprintable.NewReader(bytes.NewReader(body)))
+		gtest.Assert(err, nil)
+		gtest.Assert(string(text), message.Text)
+
+		recipients, err := message.Recipients()
+		gtest.Assert(err, nil)
+		gtest.Assert(recipients, []string{"john@a.com", "rob@a.com", "smith@a.com", "lee@a.com", "boss@a.com"})
+	})
+}
+
+func Test_Message_Multipart(t *testing.T) {
+	gtest.Case(t, func() {
+		logo := bytes.Repeat([]byte{0x89, 'P', 'N', 'G', 0}, 40)
+		message := gsmtp.NewMessage("notify@a.com", []string{"john@a.com"}, "report")
+		message.Text = "text body"
+		message.Html = `<p>html body</p><img src="cid:logo">`
+		message.EmbedContent("logo.png", logo, "logo")
+		message.AttachContent("report.csv", []byte("a,b\n1,2\n"), "text/csv")
+		message.AttachContent("data.unknown", []byte("data"))
+
+		// multipart/mixed > multipart/related > multipart/alternative.
+		m := readMessage(message)
+		types, parts, contents := readParts(m.Header.Get("Content-Type"), m.Body)
+		gtest.Assert(types, []string{"multipart/related", "text/csv", "application/octet-stream"})
+
+		gtest.Assert(parts[1].FileName(), "report.csv")
+		gtest.Assert(parts[1].Header.Get("Content-Transfer-Encoding"), "base64")
+		gtest.Assert(strings.HasPrefix(parts[1].Header.Get("Content-Disposition"), "attachment"), true)
+		b, err := base64.StdEncoding.DecodeString(strings.Replace(contents[1], "\r\n", "", -1))
+		gtest.Assert(err, nil)
+		gtest.Assert(string(b), "a,b\n1,2\n")
+		gtest.Assert(parts[2].FileName(), "data.unknown")
+
+		types, parts, contents = readParts(parts[0].Header.Get("Content-Type"), strings.NewReader(contents[0]))
+		gtest.Assert(types, []string{"multipart/alternative", "image/png"})
+		gtest.Assert(parts[1].Header.Get("Content-ID"), "<logo>")
+		gtest.Assert(strings.HasPrefix(parts[1].Header.Get("Content-Disposition"), "inline"), true)
+		// The base64 content is wrapped in lines of 76 characters.
+		lines := strings.Split(strings.TrimSuffix(contents[1], "\r\n"), "\r\n")
+		gtest.Assert(len(lines), 4)
+		gtest.Assert(len(lines[0]), 76)
+		b, err = base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+		gtest.Assert(err, nil)
+		gtest.Assert(b, logo)
+
+		types, _, contents = readParts(parts[0].Header.Get("Content-Type"), strings.NewReader(contents[0]))
+		gtest.Assert(types, []string{"text/plain", "text/html"})
+		gtest.Assert(contents, []string{message.Text, message.Html})
+	})
+
+	// The unnecessary levels are omitted.
+	gtest.Case(t, func() {
+		message := gsmtp.NewMessage("notify@a.com", []string{"john@a.com"}, "report")
+		message.Html = "<p>html body</p>"
+		message.AttachContent("report.txt", []byte("report"))
+		m := readMessage(message)
+		types, _, contents := readParts(m.Header.Get("Content-Type"), m.Body)
+		gtest.Assert(types, []string{"text/html", "text/plain"})
+		gtest.Assert(contents[0], message.Html)
+	})
+}
+
+func Test_Message_Error(t *testing.T) {
+	gtest.Case(t, func() {
+		message := gsmtp.NewMessage("invalid", []string{"john@a.com"}, "subject")
+		_, err := message.Bytes()
+		gtest.AssertNE(err, nil)
+
+		message = gsmtp.NewMessage("notify@a.com", []string{"john@"}, "subject")
+		_, err = message.Bytes()
+		gtest.AssertNE(err, nil)
+		_, err = message.Recipients()
+		gtest.AssertNE(err, nil)
+
+		message = gsmtp.NewMessage("notify@a.com", nil, "subject")
+		_, err = message.Recipients()
+		gtest.AssertNE(err, nil)
+
+		message.ReplyTo = "invalid"
+		_, err = message.Bytes()
+		gtest.AssertNE(err, nil)
+	})
+}
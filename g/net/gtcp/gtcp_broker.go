@@ -0,0 +1,291 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gtcp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Frame types of the broker protocol.
+const (
+	FRAME_SUBSCRIBE   = 1 // Client subscribes a topic.
+	FRAME_UNSUBSCRIBE = 2 // Client unsubscribes a topic.
+	FRAME_PUBLISH     = 3 // Client publishes a message to a topic.
+	FRAME_MESSAGE     = 4 // Broker delivers a message of a topic to a subscriber.
+	FRAME_PING        = 5 // Client pings the broker.
+	FRAME_PONG        = 6 // Broker responds the ping.
+)
+
+const (
+	// Max size of a broker frame, which is the max size of the simple package protocol.
+	gBROKER_MAX_FRAME_SIZE = 0xFFFFFF
+	// Size of the frame header: frame type(8bit)|topic length(16bit).
+	gBROKER_FRAME_HEADER_SIZE = 3
+	// Default size of the message queue of each subscriber.
+	gBROKER_DEFAULT_QUEUE_SIZE = 1024
+)
+
+var (
+	// Package option for broker frames.
+	brokerPkgOption = PkgOption{MaxSize: gBROKER_MAX_FRAME_SIZE}
+)
+
+// Broker is a lightweight pub/sub message broker built on TCP server,
+// which uses the simple package protocol for frame transferring, see BrokerClient.
+//
+// The message delivery is in QoS0 semantics(at most once):
+// messages are delivered only to the subscribers online when publishing,
+// and messages are dropped if the message queue of the subscriber is full or the connection is broken.
+type Broker struct {
+	mu        sync.RWMutex
+	server    *Server
+	queueSize int                                       // Message queue size of each subscriber.
+	topics    map[string]map[*brokerSubscriber]struct{} // Topic to subscribers.
+	clients   map[*brokerSubscriber]struct{}            // All connected clients.
+}
+
+// brokerSubscriber is a client connection of the broker.
+type brokerSubscriber struct {
+	conn   *Conn
+	queue  chan []byte         // Frames waiting for sending.
+	topics map[string]struct{} // Subscribed topics, which is protected by Broker.mu.
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewBroker creates and returns a new broker listening on <address>.
+// The parameter <name> is optional, which is used to specify the instance name of the underlying server.
+func NewBroker(address string, name ...string) *Broker {
+	b := &Broker{
+		queueSize: gBROKER_DEFAULT_QUEUE_SIZE,
+		topics:    make(map[string]map[*brokerSubscriber]struct{}),
+		clients:   make(map[*brokerSubscriber]struct{}),
+	}
+	b.server = NewServer(address, b.handle, name...)
+	return b
+}
+
+// SetQueueSize sets the message queue size of each subscriber, which is 1024 in default.
+// The messages exceeding the queue size are dropped for slow subscribers.
+// It should be called before Run.
+func (b *Broker) SetQueueSize(size int) {
+	b.queueSize = size
+}
+
+// Server returns the underlying TCP server of the broker, which can be used for TLS configuration.
+func (b *Broker) Server() *Server {
+	return b.server
+}
+
+// Run starts running the broker, it blocks until the broker is closed.
+func (b *Broker) Run() error {
+	return b.server.Run()
+}
+
+// Close closes the broker and all its client connections.
+func (b *Broker) Close() error {
+	err := b.server.Close()
+	b.mu.Lock()
+	clients := make([]*brokerSubscriber, 0, len(b.clients))
+	for client := range b.clients {
+		clients = append(clients, client)
+	}
+	b.mu.Unlock()
+	for _, client := range clients {
+		client.close()
+	}
+	return err
+}
+
+// Publish publishes message <data> to all the subscribers of <topic>,
+// and returns the count of subscribers that the message is queued for.
+func (b *Broker) Publish(topic string, data []byte) int {
+	frame, err := encodeBrokerFrame(FRAME_MESSAGE, topic, data)
+	if err != nil {
+		return 0
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	count := 0
+	for client := range b.topics[topic] {
+		if client.send(frame) {
+			count++
+		}
+	}
+	return count
+}
+
+// Subscribers returns the count of the subscribers of <topic>.
+func (b *Broker) Subscribers(topic string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.topics[topic])
+}
+
+// Topics returns all the topics that have subscribers.
+func (b *Broker) Topics() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	topics := make([]string, 0, len(b.topics))
+	for topic := range b.topics {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// handle is the connection handler of the underlying server.
+func (b *Broker) handle(conn *Conn) {
+	client := &brokerSubscriber{
+		conn:   conn,
+		queue:  make(chan []byte, b.queueSize),
+		topics: make(map[string]struct{}),
+		closed: make(chan struct{}),
+	}
+	b.mu.Lock()
+	b.clients[client] = struct{}{}
+	b.mu.Unlock()
+	defer b.remove(client)
+
+	go client.loop()
+	for {
+		data, err := conn.RecvPkg(brokerPkgOption)
+		if err != nil {
+			return
+		}
+		kind, topic, payload, err := decodeBrokerFrame(data)
+		if err != nil {
+			return
+		}
+		switch kind {
+		case FRAME_SUBSCRIBE:
+			b.subscribe(client, topic)
+		case FRAME_UNSUBSCRIBE:
+			b.unsubscribe(client, topic)
+		case FRAME_PUBLISH:
+			b.Publish(topic, payload)
+		case FRAME_PING:
+			if frame, err := encodeBrokerFrame(FRAME_PONG, "", nil); err == nil {
+				client.send(frame)
+			}
+		}
+	}
+}
+
+// subscribe subscribes <topic> for <client>.
+func (b *Broker) subscribe(client *brokerSubscriber, topic string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.topics[topic]; !ok {
+		b.topics[topic] = make(map[*brokerSubscriber]struct{})
+	}
+	b.topics[topic][client] = struct{}{}
+	client.topics[topic] = struct{}{}
+}
+
+// unsubscribe unsubscribes <topic> for <client>.
+func (b *Broker) unsubscribe(client *brokerSubscriber, topic string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unsubscribeWithoutLock(client, topic)
+}
+
+// unsubscribeWithoutLock unsubscribes <topic> for <client> without locking.
+func (b *Broker) unsubscribeWithoutLock(client *brokerSubscriber, topic string) {
+	if subscribers, ok := b.topics[topic]; ok {
+		delete(subscribers, client)
+		if len(subscribers) == 0 {
+			delete(b.topics, topic)
+		}
+	}
+	delete(client.topics, topic)
+}
+
+// remove removes <client> and all its subscriptions from the broker, and closes its connection.
+func (b *Broker) remove(client *brokerSubscriber) {
+	b.mu.Lock()
+	for topic := range client.topics {
+		b.unsubscribeWithoutLock(client, topic)
+	}
+	delete(b.clients, client)
+	b.mu.Unlock()
+	client.close()
+}
+
+// send queues <frame> for sending, it returns false if the queue is full or the client is closed.
+func (c *brokerSubscriber) send(frame []byte) bool {
+	select {
+	case <-c.closed:
+		return false
+	default:
+	}
+	select {
+	case c.queue <- frame:
+		return true
+	default:
+		return false
+	}
+}
+
+// loop sends the queued frames to the client until the client is closed.
+func (c *brokerSubscriber) loop() {
+	for {
+		select {
+		case <-c.closed:
+			return
+		case frame := <-c.queue:
+			if err := c.conn.SendPkg(frame, brokerPkgOption); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// close closes the client connection.
+func (c *brokerSubscriber) close() {
+	c.once.Do(func() {
+		close(c.closed)
+		c.conn.Close()
+	})
+}
+
+// encodeBrokerFrame encodes a broker frame.
+//
+// Frame format: frame type(8bit)|topic length(16bit)|topic|payload.
+func encodeBrokerFrame(kind int, topic string, payload []byte) ([]byte, error) {
+	if len(topic) > 0xFFFF {
+		return nil, fmt.Errorf(`topic length %d exceeds max length %d`, len(topic), 0xFFFF)
+	}
+	size := gBROKER_FRAME_HEADER_SIZE + len(topic) + len(payload)
+	if size > gBROKER_MAX_FRAME_SIZE {
+		return nil, fmt.Errorf(`frame size %d exceeds max size %d`, size, gBROKER_MAX_FRAME_SIZE)
+	}
+	frame := make([]byte, size)
+	frame[0] = byte(kind)
+	binary.BigEndian.PutUint16(frame[1:], uint16(len(topic)))
+	copy(frame[gBROKER_FRAME_HEADER_SIZE:], topic)
+	copy(frame[gBROKER_FRAME_HEADER_SIZE+len(topic):], payload)
+	return frame, nil
+}
+
+// decodeBrokerFrame decodes a broker frame.
+func decodeBrokerFrame(frame []byte) (kind int, topic string, payload []byte, err error) {
+	if len(frame) < gBROKER_FRAME_HEADER_SIZE {
+		return 0, "", nil, errors.New("invalid broker frame: incomplete header")
+	}
+	length := int(binary.BigEndian.Uint16(frame[1:]))
+	if len(frame) < gBROKER_FRAME_HEADER_SIZE+length {
+		return 0, "", nil, errors.New("invalid broker frame: incomplete topic")
+	}
+	kind = int(frame[0])
+	topic = string(frame[gBROKER_FRAME_HEADER_SIZE : gBROKER_FRAME_HEADER_SIZE+length])
+	payload = frame[gBROKER_FRAME_HEADER_SIZE+length:]
+	return
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gtcp
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BrokerHandler is the handler for the messages of subscribed topic.
+type BrokerHandler func(topic string, data []byte)
+
+// BrokerClient is the client of Broker.
+// Note that the message handlers are called in the receiving goroutine in order,
+// so they should not block for long.
+type BrokerClient struct {
+	conn     *Conn
+	sendMu   sync.Mutex               // Sending lock, as sending on Conn is not concurrent-safe.
+	mu       sync.RWMutex             // Lock for handlers.
+	handlers map[string]BrokerHandler // Topic to message handler.
+	pong     chan struct{}            // Notifying channel for ping responses.
+	done     chan struct{}            // Closed when the receiving goroutine ends.
+	err      error                    // Error that ends the receiving goroutine.
+}
+
+// NewBrokerClient creates and returns a client connected to the broker of <address>.
+func NewBrokerClient(address string) (*BrokerClient, error) {
+	conn, err := NewConn(address)
+	if err != nil {
+		return nil, err
+	}
+	return NewBrokerClientByConn(conn), nil
+}
+
+// NewBrokerClientByConn creates and returns a client using the broker connection <conn>,
+// which can be a TLS connection.
+func NewBrokerClientByConn(conn *Conn) *BrokerClient {
+	c := &BrokerClient{
+		conn:     conn,
+		handlers: make(map[string]BrokerHandler),
+		pong:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go c.loop()
+	return c
+}
+
+// Subscribe subscribes <topic> with message <handler>,
+// the previous handler of the topic is replaced if it is already subscribed.
+func (c *BrokerClient) Subscribe(topic string, handler BrokerHandler) error {
+	if handler == nil {
+		return errors.New("message handler cannot be nil")
+	}
+	c.mu.Lock()
+	c.handlers[topic] = handler
+	c.mu.Unlock()
+	return c.send(FRAME_SUBSCRIBE, topic, nil)
+}
+
+// Unsubscribe unsubscribes <topic>.
+func (c *BrokerClient) Unsubscribe(topic string) error {
+	c.mu.Lock()
+	delete(c.handlers, topic)
+	c.mu.Unlock()
+	return c.send(FRAME_UNSUBSCRIBE, topic, nil)
+}
+
+// Publish publishes message <data> to <topic>.
+func (c *BrokerClient) Publish(topic string, data []byte) error {
+	return c.send(FRAME_PUBLISH, topic, data)
+}
+
+// Ping pings the broker and waits for the response in <timeout>.
+// As the frames are handled in order by the broker,
+// it can also be used to make sure the previous frames have been handled.
+func (c *BrokerClient) Ping(timeout time.Duration) error {
+	if err := c.send(FRAME_PING, "", nil); err != nil {
+		return err
+	}
+	select {
+	case <-c.pong:
+		return nil
+	case <-c.done:
+		return c.err
+	case <-time.After(timeout):
+		return errors.New("ping timeout")
+	}
+}
+
+// Close closes the client connection.
+func (c *BrokerClient) Close() error {
+	return c.conn.Close()
+}
+
+// Done returns a channel which is closed when the client connection is closed or broken.
+func (c *BrokerClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// send sends a frame to the broker.
+func (c *BrokerClient) send(kind int, topic string, payload []byte) error {
+	frame, err := encodeBrokerFrame(kind, topic, payload)
+	if err != nil {
+		return err
+	}
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.conn.SendPkg(frame, brokerPkgOption)
+}
+
+// loop receives and handles the frames from the broker until the connection is closed.
+func (c *BrokerClient) loop() {
+	defer close(c.done)
+	for {
+		data, err := c.conn.RecvPkg(brokerPkgOption)
+		if err != nil {
+			c.err = err
+			return
+		}
+		kind, topic, payload, err := decodeBrokerFrame(data)
+		if err != nil {
+			c.err = err
+			c.conn.Close()
+			return
+		}
+		switch kind {
+		case FRAME_MESSAGE:
+			c.mu.RLock()
+			handler := c.handlers[topic]
+			c.mu.RUnlock()
+			if handler != nil {
+				handler(topic, payload)
+			}
+		case FRAME_PONG:
+			select {
+			case c.pong <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
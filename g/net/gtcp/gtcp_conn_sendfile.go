@@ -0,0 +1,48 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gtcp
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// 发送文件内容，从文件的offset位置开始发送count字节，count <= 0时表示发送到文件末尾，返回实际发送的字节数。
+// 对于TCP链接由标准库的TCPConn.ReadFrom完成发送，支持的系统(例如Linux)下使用sendfile系统调用在内核中完成数据传输(零拷贝)，
+// 其他系统或者TLS链接使用缓冲复制的方式发送。发送超时时间可以通过SetSendDeadline设置。
+func (c *Conn) SendFile(path string, offset int64, count int64) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.IsDir() {
+		return 0, errors.New("cannot send a directory: " + path)
+	}
+	if offset < 0 || offset > info.Size() {
+		return 0, errors.New("invalid offset for sending file: " + path)
+	}
+	size := info.Size() - offset
+	if count > 0 {
+		if count > size {
+			return 0, errors.New("count exceeds the file size for sending file: " + path)
+		}
+		size = count
+	}
+	if size == 0 {
+		return 0, nil
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.CopyN(c.Conn, f, size)
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtcp_test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g/net/gtcp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+// freeAddress returns a local address with a free port for testing.
+func freeAddress() string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// startBroker starts a broker on a free port and waits until it is listening.
+func startBroker() (*gtcp.Broker, string) {
+	address := freeAddress()
+	broker := gtcp.NewBroker(address)
+	go broker.Run()
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("tcp", address); err == nil {
+			conn.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return broker, address
+}
+
+// waitSubscribers waits until the count of subscribers of <topic> is <count>.
+func waitSubscribers(broker *gtcp.Broker, topic string, count int) int {
+	for i := 0; i < 100 && broker.Subscribers(topic) != count; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return broker.Subscribers(topic)
+}
+
+func Test_Broker_RoundTrip(t *testing.T) {
+	broker, address := startBroker()
+	defer broker.Close()
+
+	gtest.Case(t, func() {
+		subscriber, err := gtcp.NewBrokerClient(address)
+		gtest.Assert(err, nil)
+		defer subscriber.Close()
+		publisher, err := gtcp.NewBrokerClient(address)
+		gtest.Assert(err, nil)
+		defer publisher.Close()
+
+		messages := make(chan string, 10)
+		err = subscriber.Subscribe("news", func(topic string, data []byte) {
+			messages <- topic + ":" + string(data)
+		})
+		gtest.Assert(err, nil)
+		// The subscription is handled by the broker before the ping response.
+		gtest.Assert(subscriber.Ping(time.Second), nil)
+		gtest.Assert(broker.Subscribers("news"), 1)
+		gtest.Assert(broker.Topics(), []string{"news"})
+
+		// Messages are delivered in order, and the ones of other topics are not.
+		gtest.Assert(publisher.Publish("sports", []byte("0")), nil)
+		for i := 1; i <= 3; i++ {
+			gtest.Assert(publisher.Publish("news", []byte(fmt.Sprintf("%d", i))), nil)
+		}
+		for i := 1; i <= 3; i++ {
+			select {
+			case message := <-messages:
+				gtest.Assert(message, fmt.Sprintf("news:%d", i))
+			case <-time.After(time.Second):
+				t.Fatal("message not received")
+			}
+		}
+
+		// Publishing from the broker side.
+		gtest.Assert(broker.Publish("news", []byte("4")), 1)
+		select {
+		case message := <-messages:
+			gtest.Assert(message, "news:4")
+		case <-time.After(time.Second):
+			t.Fatal("message not received")
+		}
+
+		// No more messages after unsubscribing.
+		gtest.Assert(subscriber.Unsubscribe("news"), nil)
+		gtest.Assert(subscriber.Ping(time.Second), nil)
+		gtest.Assert(broker.Subscribers("news"), 0)
+		gtest.Assert(broker.Publish("news", []byte("5")), 0)
+		gtest.Assert(subscriber.Ping(time.Second), nil)
+		gtest.Assert(len(messages), 0)
+	})
+
+	gtest.Case(t, func() {
+		client, err := gtcp.NewBrokerClient(address)
+		gtest.Assert(err, nil)
+		defer client.Close()
+		gtest.AssertNE(client.Subscribe("news", nil), nil)
+	})
+}
+
+func Test_Broker_Reconnect(t *testing.T) {
+	broker, address := startBroker()
+	defer broker.Close()
+
+	gtest.Case(t, func() {
+		client, err := gtcp.NewBrokerClient(address)
+		gtest.Assert(err, nil)
+		gtest.Assert(client.Subscribe("news", func(topic string, data []byte) {}), nil)
+		gtest.Assert(client.Ping(time.Second), nil)
+		gtest.Assert(broker.Subscribers("news"), 1)
+
+		// The subscriptions of the closed connection are removed by the broker.
+		gtest.Assert(client.Close(), nil)
+		select {
+		case <-client.Done():
+		case <-time.After(time.Second):
+			t.Fatal("client not done")
+		}
+		gtest.Assert(waitSubscribers(broker, "news", 0), 0)
+		gtest.Assert(len(broker.Topics()), 0)
+
+		// The reconnected client subscribes again and receives new messages.
+		client, err = gtcp.NewBrokerClient(address)
+		gtest.Assert(err, nil)
+		defer client.Close()
+		messages := make(chan string, 10)
+		gtest.Assert(client.Subscribe("news", func(topic string, data []byte) {
+			messages <- string(data)
+		}), nil)
+		gtest.Assert(client.Ping(time.Second), nil)
+		gtest.Assert(broker.Publish("news", []byte("again")), 1)
+		select {
+		case message := <-messages:
+			gtest.Assert(message, "again")
+		case <-time.After(time.Second):
+			t.Fatal("message not received")
+		}
+	})
+
+	// The clients are notified when the broker is closed.
+	gtest.Case(t, func() {
+		broker, address := startBroker()
+		client, err := gtcp.NewBrokerClient(address)
+		gtest.Assert(err, nil)
+		defer client.Close()
+		gtest.Assert(client.Ping(time.Second), nil)
+		broker.Close()
+		select {
+		case <-client.Done():
+		case <-time.After(time.Second):
+			t.Fatal("client not done")
+		}
+		gtest.AssertNE(client.Ping(time.Second), nil)
+	})
+}
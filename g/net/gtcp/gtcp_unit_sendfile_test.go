@@ -0,0 +1,92 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtcp_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/gogf/gf/g/net/gtcp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+// sendFile sends the file of <path> from <offset> with <count> bytes through a loopback connection,
+// and returns the sent bytes and the received content.
+func sendFile(path string, offset int64, count int64) (int64, []byte, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, nil, err
+	}
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		b, _ := ioutil.ReadAll(conn)
+		received <- b
+	}()
+	conn, err := gtcp.NewConn(listener.Addr().String())
+	if err != nil {
+		return 0, nil, err
+	}
+	n, err := conn.SendFile(path, offset, count)
+	conn.Close()
+	return n, <-received, err
+}
+
+func Test_Conn_SendFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "gtcp_sendfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	content := bytes.Repeat([]byte("0123456789"), 100*1024)
+	file.Write(content)
+	file.Close()
+
+	gtest.Case(t, func() {
+		n, b, err := sendFile(file.Name(), 0, 0)
+		gtest.Assert(err, nil)
+		gtest.Assert(n, len(content))
+		gtest.Assert(bytes.Equal(b, content), true)
+
+		n, b, err = sendFile(file.Name(), 5, 0)
+		gtest.Assert(err, nil)
+		gtest.Assert(n, len(content)-5)
+		gtest.Assert(bytes.Equal(b, content[5:]), true)
+
+		n, b, err = sendFile(file.Name(), 12345, 100000)
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 100000)
+		gtest.Assert(bytes.Equal(b, content[12345:112345]), true)
+
+		n, b, err = sendFile(file.Name(), int64(len(content)), 0)
+		gtest.Assert(err, nil)
+		gtest.Assert(n, 0)
+		gtest.Assert(len(b), 0)
+	})
+
+	gtest.Case(t, func() {
+		_, _, err := sendFile(file.Name(), -1, 0)
+		gtest.AssertNE(err, nil)
+		_, _, err = sendFile(file.Name(), int64(len(content))+1, 0)
+		gtest.AssertNE(err, nil)
+		_, _, err = sendFile(file.Name(), 10, int64(len(content)))
+		gtest.AssertNE(err, nil)
+		_, _, err = sendFile(os.TempDir(), 0, 0)
+		gtest.AssertNE(err, nil)
+		_, _, err = sendFile(file.Name()+".none", 0, 0)
+		gtest.AssertNE(err, nil)
+	})
+}
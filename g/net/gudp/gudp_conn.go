@@ -21,6 +21,7 @@ type Conn struct {
 	recvDeadline   time.Time     // 读取超时时间
 	sendDeadline   time.Time     // 写入超时时间
 	recvBufferWait time.Duration // 读取全部缓冲区数据时，读取完毕后的写入等待间隔
+	reliable       *reliable     // 可靠传输模式(SendReliable/RecvReliable)的状态
 }
 
 const (
@@ -50,6 +51,7 @@ func NewConnByNetConn(udp *net.UDPConn) *Conn {
 		recvDeadline:   time.Time{},
 		sendDeadline:   time.Time{},
 		recvBufferWait: gRECV_ALL_WAIT_TIMEOUT,
+		reliable:       newReliable(),
 	}
 }
 
@@ -51,6 +51,16 @@ func SendRecv(addr string, data []byte, receive int, retry ...Retry) ([]byte, er
 	return conn.SendRecv(data, receive, retry...)
 }
 
+// (面向短链接)使用可靠传输模式发送数据，参考Conn.SendReliable
+func SendReliable(addr string, data []byte, option ...ReliableOption) error {
+	conn, err := NewConn(addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.SendReliable(data, option...)
+}
+
 // 判断是否是超时错误
 func isTimeout(err error) bool {
 	if err == nil {
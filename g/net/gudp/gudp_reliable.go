@@ -0,0 +1,261 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gudp
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	gRELIABLE_FLAG_DATA       = 1     // 数据包标识
+	gRELIABLE_FLAG_ACK        = 2     // 确认包标识
+	gRELIABLE_HEADER_SIZE     = 9     // 包头大小：标识(1)|会话ID(4)|序列号(4)
+	gRELIABLE_MAX_PACKET      = 65507 // UDP数据包的最大大小(IPv4)
+	gRELIABLE_MAX_DATA_SIZE   = gRELIABLE_MAX_PACKET - gRELIABLE_HEADER_SIZE
+	gRELIABLE_DEFAULT_TIMEOUT = 200 * time.Millisecond // 默认的确认等待超时时间
+	gRELIABLE_DEFAULT_RETRY   = 3                      // 默认的最大重传次数
+	gRELIABLE_WINDOW_SIZE     = 1024                   // 接收端每个发送端保留的去重序列号数量
+	gRELIABLE_PEER_EXPIRE     = time.Minute            // 接收端发送端去重状态的过期时间
+)
+
+// 可靠传输选项
+type ReliableOption struct {
+	Timeout time.Duration // 等待确认的超时时间，超时未收到确认时重传数据，默认为200毫秒
+	Retry   int           // 最大重传次数，默认为3次
+}
+
+// 可靠传输模式下重传之后仍未收到确认时返回的错误
+var ErrReliableNoAck = errors.New("no ack received from remote peer")
+
+// 可靠传输模式的状态
+type reliable struct {
+	mu      sync.Mutex
+	session uint32                   // 发送端会话ID，随机生成，接收端用以区分同一地址的不同发送进程
+	seq     uint32                   // 最近一次发送的序列号
+	pending []*reliablePacket        // 发送端等待确认期间收到的对方数据包
+	peers   map[string]*reliablePeer // 接收端各发送端的去重状态，键名为远程地址及会话ID
+	purged  time.Time                // 最近一次清理过期去重状态的时间
+}
+
+// 已接收的数据包
+type reliablePacket struct {
+	data  []byte
+	raddr *net.UDPAddr
+}
+
+// 接收端单个发送端的去重状态，保留最近接收的gRELIABLE_WINDOW_SIZE个序列号
+type reliablePeer struct {
+	seqs   map[uint32]struct{}
+	ring   []uint32
+	index  int
+	active time.Time
+}
+
+func newReliable() *reliable {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		binary.BigEndian.PutUint32(b, uint32(time.Now().UnixNano()))
+	}
+	return &reliable{
+		session: binary.BigEndian.Uint32(b),
+		peers:   make(map[string]*reliablePeer),
+		purged:  time.Now(),
+	}
+}
+
+// getReliableOption returns the ReliableOption with default values.
+func getReliableOption(option ...ReliableOption) ReliableOption {
+	reliableOption := ReliableOption{}
+	if len(option) > 0 {
+		reliableOption = option[0]
+	}
+	if reliableOption.Timeout <= 0 {
+		reliableOption.Timeout = gRELIABLE_DEFAULT_TIMEOUT
+	}
+	if reliableOption.Retry <= 0 {
+		reliableOption.Retry = gRELIABLE_DEFAULT_RETRY
+	}
+	return reliableOption
+}
+
+// 使用可靠传输模式发送数据，适用于小数据量的消息(单个UDP包)，直到收到对方的确认后返回。
+//
+// 可靠传输协议格式：标识(8bit)|会话ID(32bit)|序列号(32bit)|数据字段(变长)。
+//
+// 注意：
+// 1. 对方需要使用RecvReliable接收数据，接收端会回复确认包并根据会话ID和序列号对重传的数据去重；
+// 2. 超时未收到确认时重传数据，重传option.Retry次之后仍未收到确认时返回ErrReliableNoAck，此时对方可能已经收到数据；
+// 3. 等待确认期间收到的对方数据包会被确认并缓存，由下一次RecvReliable返回；
+// 4. 同一个Conn对象的可靠传输方法不能并发调用。
+func (c *Conn) SendReliable(data []byte, option ...ReliableOption) error {
+	reliableOption := getReliableOption(option...)
+	if len(data) > gRELIABLE_MAX_DATA_SIZE {
+		return fmt.Errorf(`data size %d exceeds max reliable data size %d`, len(data), gRELIABLE_MAX_DATA_SIZE)
+	}
+	c.reliable.mu.Lock()
+	c.reliable.seq++
+	session, seq := c.reliable.session, c.reliable.seq
+	c.reliable.mu.Unlock()
+
+	packet := encodeReliablePacket(gRELIABLE_FLAG_DATA, session, seq, data)
+	buffer := make([]byte, gRELIABLE_MAX_PACKET)
+	defer c.SetReadDeadline(c.recvDeadline)
+	for i := 0; i <= reliableOption.Retry; i++ {
+		if err := c.writeReliablePacket(packet, c.raddr); err != nil {
+			return err
+		}
+		if err := c.SetReadDeadline(time.Now().Add(reliableOption.Timeout)); err != nil {
+			return err
+		}
+		for {
+			size, raddr, err := c.ReadFromUDP(buffer)
+			if err != nil {
+				if isTimeout(err) {
+					break
+				}
+				return err
+			}
+			flag, s, q, payload, ok := decodeReliablePacket(buffer[:size])
+			if !ok {
+				continue
+			}
+			if flag == gRELIABLE_FLAG_ACK {
+				if s == session && q == seq {
+					return nil
+				}
+				continue
+			}
+			if c.acceptReliablePacket(raddr, s, q) {
+				c.reliable.mu.Lock()
+				c.reliable.pending = append(c.reliable.pending, &reliablePacket{
+					data:  append([]byte(nil), payload...),
+					raddr: raddr,
+				})
+				c.reliable.mu.Unlock()
+			}
+		}
+	}
+	return ErrReliableNoAck
+}
+
+// 使用可靠传输模式接收数据，收到数据包后回复确认包，重复的数据包只确认不返回。
+// 非可靠传输协议格式的数据包将被忽略，读取超时请使用SetRecvDeadline设置。
+func (c *Conn) RecvReliable() ([]byte, error) {
+	c.reliable.mu.Lock()
+	if len(c.reliable.pending) > 0 {
+		packet := c.reliable.pending[0]
+		c.reliable.pending = c.reliable.pending[1:]
+		c.reliable.mu.Unlock()
+		c.raddr = packet.raddr
+		return packet.data, nil
+	}
+	c.reliable.mu.Unlock()
+
+	buffer := make([]byte, gRELIABLE_MAX_PACKET)
+	for {
+		size, raddr, err := c.ReadFromUDP(buffer)
+		if err != nil {
+			return nil, err
+		}
+		flag, session, seq, payload, ok := decodeReliablePacket(buffer[:size])
+		// 过期的确认包(例如重传导致的重复确认)直接忽略
+		if !ok || flag != gRELIABLE_FLAG_DATA {
+			continue
+		}
+		if c.acceptReliablePacket(raddr, session, seq) {
+			c.raddr = raddr
+			return append([]byte(nil), payload...), nil
+		}
+	}
+}
+
+// 确认收到的数据包，并返回该数据包是否是第一次接收(非重复数据包)。
+// 重复的数据包也需要确认，因为对方重传可能是由于确认包丢失导致的。
+func (c *Conn) acceptReliablePacket(raddr *net.UDPAddr, session, seq uint32) bool {
+	// 确认包发送失败时对方会重传，因此这里忽略错误
+	c.writeReliablePacket(encodeReliablePacket(gRELIABLE_FLAG_ACK, session, seq, nil), raddr)
+
+	c.reliable.mu.Lock()
+	defer c.reliable.mu.Unlock()
+	now := time.Now()
+	if now.Sub(c.reliable.purged) > gRELIABLE_PEER_EXPIRE {
+		for k, p := range c.reliable.peers {
+			if now.Sub(p.active) > gRELIABLE_PEER_EXPIRE {
+				delete(c.reliable.peers, k)
+			}
+		}
+		c.reliable.purged = now
+	}
+	key := strconv.FormatUint(uint64(session), 10)
+	if raddr != nil {
+		key = raddr.String() + "/" + key
+	}
+	peer, ok := c.reliable.peers[key]
+	if !ok {
+		peer = &reliablePeer{
+			seqs: make(map[uint32]struct{}),
+			ring: make([]uint32, 0, gRELIABLE_WINDOW_SIZE),
+		}
+		c.reliable.peers[key] = peer
+	}
+	peer.active = now
+	if _, ok := peer.seqs[seq]; ok {
+		return false
+	}
+	if len(peer.ring) < gRELIABLE_WINDOW_SIZE {
+		peer.ring = append(peer.ring, seq)
+	} else {
+		delete(peer.seqs, peer.ring[peer.index])
+		peer.ring[peer.index] = seq
+		peer.index = (peer.index + 1) % gRELIABLE_WINDOW_SIZE
+	}
+	peer.seqs[seq] = struct{}{}
+	return true
+}
+
+// 发送可靠传输数据包，已连接的链接直接写入，否则发送到指定的远程地址
+func (c *Conn) writeReliablePacket(packet []byte, raddr *net.UDPAddr) (err error) {
+	if c.UDPConn.RemoteAddr() != nil {
+		_, err = c.Write(packet)
+	} else if raddr != nil {
+		_, err = c.WriteToUDP(packet, raddr)
+	} else {
+		err = errors.New("remote address is unknown for reliable sending")
+	}
+	return
+}
+
+// 编码可靠传输数据包
+func encodeReliablePacket(flag byte, session, seq uint32, data []byte) []byte {
+	packet := make([]byte, gRELIABLE_HEADER_SIZE+len(data))
+	packet[0] = flag
+	binary.BigEndian.PutUint32(packet[1:], session)
+	binary.BigEndian.PutUint32(packet[5:], seq)
+	copy(packet[gRELIABLE_HEADER_SIZE:], data)
+	return packet
+}
+
+// 解码可靠传输数据包，数据包格式不正确时ok返回false
+func decodeReliablePacket(packet []byte) (flag byte, session, seq uint32, data []byte, ok bool) {
+	if len(packet) < gRELIABLE_HEADER_SIZE {
+		return
+	}
+	flag = packet[0]
+	if flag != gRELIABLE_FLAG_DATA && flag != gRELIABLE_FLAG_ACK {
+		return
+	}
+	session = binary.BigEndian.Uint32(packet[1:])
+	seq = binary.BigEndian.Uint32(packet[5:])
+	return flag, session, seq, packet[gRELIABLE_HEADER_SIZE:], true
+}
@@ -0,0 +1,218 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gudp_test
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g/net/gudp"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+const (
+	flagData = 1 // Flag of reliable data packets.
+	flagAck  = 2 // Flag of reliable ack packets.
+)
+
+// lossyProxy relays udp packets between a client and a server,
+// dropping the packets for which the <drop> function returns true.
+type lossyProxy struct {
+	mu     sync.Mutex
+	conn   *net.UDPConn
+	server *net.UDPAddr
+	client *net.UDPAddr
+	drop   func(flag byte, n int) bool // n is the count of relayed packets of the same flag, starting from 1.
+	counts map[byte]int
+}
+
+func newLossyProxy(server *net.UDPAddr, drop func(flag byte, n int) bool) (*lossyProxy, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return nil, err
+	}
+	p := &lossyProxy{
+		conn:   conn,
+		server: server,
+		drop:   drop,
+		counts: make(map[byte]int),
+	}
+	go p.relay()
+	return p, nil
+}
+
+func (p *lossyProxy) relay() {
+	buffer := make([]byte, 65535)
+	for {
+		size, addr, err := p.conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+		if size == 0 {
+			continue
+		}
+		p.mu.Lock()
+		flag := buffer[0]
+		p.counts[flag]++
+		dropped := p.drop(flag, p.counts[flag])
+		to := p.server
+		if addr.String() == p.server.String() {
+			to = p.client
+		} else {
+			p.client = addr
+		}
+		p.mu.Unlock()
+		if !dropped && to != nil {
+			p.conn.WriteToUDP(buffer[:size], to)
+		}
+	}
+}
+
+// count returns the count of relayed packets of <flag>, including the dropped ones.
+func (p *lossyProxy) count(flag byte) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.counts[flag]
+}
+
+func (p *lossyProxy) addr() string {
+	return p.conn.LocalAddr().String()
+}
+
+func (p *lossyProxy) close() {
+	p.conn.Close()
+}
+
+// startReliableServer starts a server receiving data with RecvReliable through a lossy proxy,
+// and returns the proxy and the channel of received data.
+func startReliableServer(drop func(flag byte, n int) bool) (*gudp.Conn, *lossyProxy, chan string, error) {
+	udp, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	proxy, err := newLossyProxy(udp.LocalAddr().(*net.UDPAddr), drop)
+	if err != nil {
+		udp.Close()
+		return nil, nil, nil, err
+	}
+	server := gudp.NewConnByNetConn(udp)
+	received := make(chan string, 100)
+	go func() {
+		for {
+			data, err := server.RecvReliable()
+			if err != nil {
+				return
+			}
+			received <- string(data)
+		}
+	}()
+	return server, proxy, received, nil
+}
+
+// receiveAll returns all the data received until no more data arrives in <wait>.
+func receiveAll(received chan string, wait time.Duration) []string {
+	array := make([]string, 0)
+	for {
+		select {
+		case data := <-received:
+			array = append(array, data)
+		case <-time.After(wait):
+			return array
+		}
+	}
+}
+
+func Test_Reliable_RetransmitOnDataLoss(t *testing.T) {
+	// The first two data packets are lost.
+	server, proxy, received, err := startReliableServer(func(flag byte, n int) bool {
+		return flag == flagData && n <= 2
+	})
+	gtest.Assert(err, nil)
+	defer server.Close()
+	defer proxy.close()
+
+	gtest.Case(t, func() {
+		conn, err := gudp.NewConn(proxy.addr())
+		gtest.Assert(err, nil)
+		defer conn.Close()
+		err = conn.SendReliable([]byte("hello"), gudp.ReliableOption{Timeout: 50 * time.Millisecond})
+		gtest.Assert(err, nil)
+		gtest.Assert(proxy.count(flagData), 3)
+		gtest.Assert(receiveAll(received, 200*time.Millisecond), []string{"hello"})
+	})
+}
+
+func Test_Reliable_RetransmitOnAckLoss(t *testing.T) {
+	// The first ack packet is lost, so the server receives the first data packet twice.
+	server, proxy, received, err := startReliableServer(func(flag byte, n int) bool {
+		return flag == flagAck && n == 1
+	})
+	gtest.Assert(err, nil)
+	defer server.Close()
+	defer proxy.close()
+
+	gtest.Case(t, func() {
+		conn, err := gudp.NewConn(proxy.addr())
+		gtest.Assert(err, nil)
+		defer conn.Close()
+		option := gudp.ReliableOption{Timeout: 50 * time.Millisecond}
+		gtest.Assert(conn.SendReliable([]byte("a"), option), nil)
+		gtest.Assert(conn.SendReliable([]byte("b"), option), nil)
+		gtest.Assert(proxy.count(flagData), 3)
+		gtest.Assert(proxy.count(flagAck), 3)
+		// The retransmitted data packet is acked but not received again.
+		gtest.Assert(receiveAll(received, 200*time.Millisecond), []string{"a", "b"})
+	})
+}
+
+func Test_Reliable_Ordering(t *testing.T) {
+	// Every third data packet and every fourth ack packet are lost.
+	server, proxy, received, err := startReliableServer(func(flag byte, n int) bool {
+		return (flag == flagData && n%3 == 0) || (flag == flagAck && n%4 == 0)
+	})
+	gtest.Assert(err, nil)
+	defer server.Close()
+	defer proxy.close()
+
+	gtest.Case(t, func() {
+		conn, err := gudp.NewConn(proxy.addr())
+		gtest.Assert(err, nil)
+		defer conn.Close()
+		expect := make([]string, 0)
+		for i := 0; i < 20; i++ {
+			data := fmt.Sprintf("message-%d", i)
+			expect = append(expect, data)
+			gtest.Assert(conn.SendReliable([]byte(data), gudp.ReliableOption{Timeout: 50 * time.Millisecond}), nil)
+		}
+		gtest.Assert(proxy.count(flagData) > 20, true)
+		gtest.Assert(receiveAll(received, 200*time.Millisecond), expect)
+	})
+}
+
+func Test_Reliable_NoAck(t *testing.T) {
+	// All the ack packets are lost.
+	server, proxy, received, err := startReliableServer(func(flag byte, n int) bool {
+		return flag == flagAck
+	})
+	gtest.Assert(err, nil)
+	defer server.Close()
+	defer proxy.close()
+
+	gtest.Case(t, func() {
+		conn, err := gudp.NewConn(proxy.addr())
+		gtest.Assert(err, nil)
+		defer conn.Close()
+		err = conn.SendReliable([]byte("lost"), gudp.ReliableOption{Timeout: 30 * time.Millisecond, Retry: 2})
+		gtest.Assert(err, gudp.ErrReliableNoAck)
+		gtest.Assert(proxy.count(flagData), 3)
+		// The remote peer may have received the data although no ack is received.
+		gtest.Assert(receiveAll(received, 200*time.Millisecond), []string{"lost"})
+	})
+}
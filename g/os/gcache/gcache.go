@@ -63,6 +63,13 @@ func GetOrSetFuncLock(key interface{}, f func() interface{}, expire int) interfa
 	return cache.GetOrSetFuncLock(key, f, expire)
 }
 
+// SetNegativeExpire enables negative caching of the default cache with <expire> in milliseconds,
+// which remembers the nil result of the loader function for nonexistent keys.
+// If <expire> <=0 means negative caching is disabled.
+func SetNegativeExpire(expire int) {
+	cache.SetNegativeExpire(expire)
+}
+
 // Contains returns true if <key> exists in the cache, or else returns false.
 func Contains(key interface{}) bool {
 	return cache.Contains(key)
@@ -30,8 +30,10 @@ func New(lruCap ...int) *Cache {
 
 // Clear clears all data of the cache.
 func (c *Cache) Clear() {
+	m := newMemCache()
+	m.negativeExpire.Set(c.negativeExpire.Val())
 	// atomic swap to ensure atomicity.
-	old := atomic.SwapPointer((*unsafe.Pointer)(unsafe.Pointer(&c.memCache)), unsafe.Pointer(newMemCache()))
+	old := atomic.SwapPointer((*unsafe.Pointer)(unsafe.Pointer(&c.memCache)), unsafe.Pointer(m))
 	// close the old cache object.
 	(*memCache)(old).Close()
 }
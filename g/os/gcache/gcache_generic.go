@@ -0,0 +1,64 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+//go:build go1.18
+// +build go1.18
+
+package gcache
+
+import (
+	"fmt"
+)
+
+// GetOrSetT returns the value of <key> in <c> as type T,
+// or sets <key> with result of function <f> and returns its result
+// if <key> does not exist in the cache.
+// The default cache is used if <c> is nil.
+// The key-value pair expires after <expire> milliseconds.
+// If <expire> <=0 means it does not expire.
+//
+// If <f> returns error, the error is returned and nothing is cached.
+// It also returns error if the cached value of <key> is not type of T.
+func GetOrSetT[T any](c *Cache, key interface{}, expire int, f func() (T, error)) (T, error) {
+	if c == nil {
+		c = cache
+	}
+	if v := c.Get(key); v != nil {
+		return assertT[T](key, v)
+	}
+	if c.isNegative(key) {
+		var value T
+		return value, nil
+	}
+	value, err := f()
+	if err != nil {
+		return value, err
+	}
+	// The nil value is not cached but remembered by negative caching, see doSetWithLockCheck.
+	v := c.doSetWithLockCheck(key, value, expire)
+	if v == nil {
+		return value, nil
+	}
+	return assertT[T](key, v)
+}
+
+// MustGetOrSetT acts like GetOrSetT, but it panics if any error occurs.
+func MustGetOrSetT[T any](c *Cache, key interface{}, expire int, f func() (T, error)) T {
+	value, err := GetOrSetT[T](c, key, expire, f)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// assertT asserts cached value <v> of <key> to type T.
+func assertT[T any](key interface{}, v interface{}) (T, error) {
+	value, ok := v.(T)
+	if !ok {
+		return value, fmt.Errorf(`cached value of key "%v" is type of %T, but expected %T`, key, v, value)
+	}
+	return value, nil
+}
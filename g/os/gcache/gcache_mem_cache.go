@@ -32,6 +32,9 @@ type memCache struct {
 	data        map[interface{}]memCacheItem // Underlying cache data which is stored in a hash table.
 	expireTimes map[interface{}]int64        // Expiring key mapping to its timestamp, which is used for quick indexing and deleting.
 	expireSets  map[int64]*gset.Set          // Expiring timestamp mapping to its key set, which is used for quick indexing and deleting.
+	negatives   map[interface{}]int64        // Missing key mapping to its expire time in milliseconds for negative caching.
+
+	negativeExpire *gtype.Int // Expire in milliseconds for negative caching, which is disabled if it is not greater than 0.
 
 	lru        *memCacheLru // LRU object, which is enabled when <cap> > 0.
 	lruGetList *glist.List  // LRU history according with Get function.
@@ -64,8 +67,11 @@ func newMemCache(lruCap ...int) *memCache {
 		data:        make(map[interface{}]memCacheItem),
 		expireTimes: make(map[interface{}]int64),
 		expireSets:  make(map[int64]*gset.Set),
+		negatives:   make(map[interface{}]int64),
 		eventList:   glist.New(),
 		closed:      gtype.NewBool(),
+
+		negativeExpire: gtype.NewInt(),
 	}
 	if len(lruCap) > 0 {
 		c.cap = lruCap[0]
@@ -109,6 +115,7 @@ func (c *memCache) Set(key interface{}, value interface{}, expire int) {
 	expireTime := c.getInternalExpire(expire)
 	c.dataMu.Lock()
 	c.data[key] = memCacheItem{v: value, e: expireTime}
+	delete(c.negatives, key)
 	c.dataMu.Unlock()
 	c.eventList.PushBack(&memCacheEvent{k: key, e: expireTime})
 }
@@ -127,12 +134,20 @@ func (c *memCache) doSetWithLockCheck(key interface{}, value interface{}, expire
 		return v.v
 	}
 	if f, ok := value.(func() interface{}); ok {
+		// The miss is remembered by negative caching, the loader function is not called.
+		if c.isNegativeWithoutLock(key) {
+			c.dataMu.Unlock()
+			return nil
+		}
 		value = f()
 	}
 	if value == nil {
+		c.setNegativeWithoutLock(key)
+		c.dataMu.Unlock()
 		return nil
 	}
 	c.data[key] = memCacheItem{v: value, e: expireTimestamp}
+	delete(c.negatives, key)
 	c.dataMu.Unlock()
 	c.eventList.PushBack(&memCacheEvent{k: key, e: expireTimestamp})
 	return value
@@ -165,6 +180,7 @@ func (c *memCache) Sets(data map[interface{}]interface{}, expire int) {
 	for k, v := range data {
 		c.dataMu.Lock()
 		c.data[k] = memCacheItem{v: v, e: expireTime}
+		delete(c.negatives, k)
 		c.dataMu.Unlock()
 		c.eventList.PushBack(&memCacheEvent{k: k, e: expireTime})
 	}
@@ -205,6 +221,9 @@ func (c *memCache) GetOrSet(key interface{}, value interface{}, expire int) inte
 // If <expire> <=0 means it does not expire.
 func (c *memCache) GetOrSetFunc(key interface{}, f func() interface{}, expire int) interface{} {
 	if v := c.Get(key); v == nil {
+		if c.isNegative(key) {
+			return nil
+		}
 		return c.doSetWithLockCheck(key, f(), expire)
 	} else {
 		return v
@@ -233,14 +252,13 @@ func (c *memCache) Contains(key interface{}) bool {
 
 // Remove deletes the <key> in the cache, and returns its value.
 func (c *memCache) Remove(key interface{}) (value interface{}) {
-	c.dataMu.RLock()
+	c.dataMu.Lock()
 	item, ok := c.data[key]
-	c.dataMu.RUnlock()
+	delete(c.data, key)
+	delete(c.negatives, key)
+	c.dataMu.Unlock()
 	if ok {
 		value = item.v
-		c.dataMu.Lock()
-		delete(c.data, key)
-		c.dataMu.Unlock()
 		c.eventList.PushBack(&memCacheEvent{k: key, e: gtime.Millisecond() - 1000})
 	}
 	return
@@ -383,6 +401,7 @@ func (c *memCache) syncEventAndClearExpired() {
 			c.expireSetMu.Unlock()
 		}
 	}
+	c.clearExpiredNegatives()
 }
 
 // clearByKey deletes the key-value pair with given <key>.
@@ -0,0 +1,61 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gcache
+
+import "github.com/gf/g/os/gtime"
+
+// SetNegativeExpire enables negative caching with <expire> in milliseconds,
+// which is usually much shorter than the expire of normal values.
+// If <expire> <=0 means negative caching is disabled, which is the default.
+//
+// If negative caching is enabled, the nil result of the loader function of GetOrSetFunc/GetOrSetFuncLock,
+// which means "not found", is remembered for <expire> milliseconds, within which the loader function
+// is not called again for the same key and nil is returned directly.
+// So repeated misses for nonexistent keys do not hammer the underlying storage like database.
+//
+// The remembered misses are not treated as cached values by Get/Contains/Keys/Size etc,
+// and they are removed by Set/Sets/Remove of the same key.
+func (c *memCache) SetNegativeExpire(expire int) {
+	c.negativeExpire.Set(expire)
+}
+
+// isNegative checks whether <key> is remembered as a miss and not expired.
+func (c *memCache) isNegative(key interface{}) bool {
+	c.dataMu.RLock()
+	e, ok := c.negatives[key]
+	c.dataMu.RUnlock()
+	return ok && e >= gtime.Millisecond()
+}
+
+// isNegativeWithoutLock acts like isNegative, but it should be called within the data mutex lock.
+func (c *memCache) isNegativeWithoutLock(key interface{}) bool {
+	e, ok := c.negatives[key]
+	return ok && e >= gtime.Millisecond()
+}
+
+// setNegativeWithoutLock remembers <key> as a miss if negative caching is enabled,
+// it should be called within the data mutex writing lock.
+func (c *memCache) setNegativeWithoutLock(key interface{}) {
+	if expire := c.negativeExpire.Val(); expire > 0 {
+		c.negatives[key] = gtime.Millisecond() + int64(expire)
+	}
+}
+
+// clearExpiredNegatives deletes the expired misses.
+func (c *memCache) clearExpiredNegatives() {
+	c.dataMu.Lock()
+	defer c.dataMu.Unlock()
+	if len(c.negatives) == 0 {
+		return
+	}
+	now := gtime.Millisecond()
+	for k, e := range c.negatives {
+		if e < now {
+			delete(c.negatives, k)
+		}
+	}
+}
@@ -183,6 +183,44 @@ func TestCache_GetOrSetFuncLock(t *testing.T) {
 	})
 }
 
+func TestCache_Negative(t *testing.T) {
+	gtest.Case(t, func() {
+		cache := gcache.New()
+		cache.SetNegativeExpire(100)
+		count := 0
+		loader := func() interface{} {
+			count++
+			return nil
+		}
+		gtest.Assert(cache.GetOrSetFunc(1, loader, 0), nil)
+		gtest.Assert(cache.GetOrSetFunc(1, loader, 0), nil)
+		gtest.Assert(cache.GetOrSetFuncLock(1, loader, 0), nil)
+		gtest.Assert(count, 1)
+		gtest.Assert(cache.Contains(1), false)
+		gtest.Assert(cache.Size(), 0)
+
+		// The miss expires.
+		time.Sleep(200 * time.Millisecond)
+		gtest.Assert(cache.GetOrSetFuncLock(1, loader, 0), nil)
+		gtest.Assert(count, 2)
+
+		// The miss is removed by Set and Remove.
+		cache.Set(1, 11, 0)
+		gtest.Assert(cache.GetOrSetFunc(1, loader, 0), 11)
+		cache.Remove(1)
+		gtest.Assert(cache.GetOrSetFunc(1, func() interface{} {
+			return 111
+		}, 0), 111)
+		gtest.Assert(count, 2)
+
+		// Disabled.
+		cache.SetNegativeExpire(0)
+		cache.GetOrSetFunc(2, loader, 0)
+		cache.GetOrSetFunc(2, loader, 0)
+		gtest.Assert(count, 4)
+	})
+}
+
 func TestCache_Clear(t *testing.T) {
 	gtest.Case(t, func() {
 		cache := gcache.New()
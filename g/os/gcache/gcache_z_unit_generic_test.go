@@ -0,0 +1,66 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+//go:build go1.18
+// +build go1.18
+
+package gcache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gogf/gf/g/os/gcache"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func TestCache_GetOrSetT(t *testing.T) {
+	gtest.Case(t, func() {
+		cache := gcache.New()
+		count := 0
+		f := func() (string, error) {
+			count++
+			return "john", nil
+		}
+		v, err := gcache.GetOrSetT(cache, "name", 0, f)
+		gtest.Assert(err, nil)
+		gtest.Assert(v, "john")
+		v, err = gcache.GetOrSetT(cache, "name", 0, f)
+		gtest.Assert(err, nil)
+		gtest.Assert(v, "john")
+		gtest.Assert(count, 1)
+
+		// Error of the loader is returned and nothing is cached.
+		_, err = gcache.GetOrSetT(cache, "age", 0, func() (int, error) {
+			return 0, errors.New("load failed")
+		})
+		gtest.Assert(err.Error(), "load failed")
+		gtest.Assert(cache.Contains("age"), false)
+
+		// Type mismatch.
+		_, err = gcache.GetOrSetT(cache, "name", 0, func() (int, error) {
+			return 1, nil
+		})
+		gtest.AssertNE(err, nil)
+		gtest.Assert(gcache.MustGetOrSetT(cache, "name", 0, f), "john")
+	})
+	gtest.Case(t, func() {
+		defer gcache.Remove("gcache_generic_key")
+		v := gcache.MustGetOrSetT(nil, "gcache_generic_key", 0, func() ([]int, error) {
+			return []int{1, 2}, nil
+		})
+		gtest.Assert(v, []int{1, 2})
+		gtest.Assert(gcache.Get("gcache_generic_key"), []int{1, 2})
+	})
+	gtest.Case(t, func() {
+		defer func() {
+			gtest.AssertNE(recover(), nil)
+		}()
+		gcache.MustGetOrSetT(nil, "gcache_generic_key", 0, func() (int, error) {
+			return 0, errors.New("load failed")
+		})
+	})
+}
@@ -38,6 +38,8 @@ type Config struct {
 	jsons *gmap.StrAnyMap     // The pared JSON objects for configuration files.
 	vc    *gtype.Bool         // Whether do violence check in value index searching.
 	// It affects the performance when set true(false in default).
+	overrides *gmap.ListMap   // In-memory overrides, pattern to value, in the order of setting.
+	merged    *gmap.StrAnyMap // Cached configuration contents merged with overrides, file name to *overrideJson.
 }
 
 // New returns a new configuration management object.
@@ -48,10 +50,12 @@ func New(file ...string) *Config {
 		name = file[0]
 	}
 	c := &Config{
-		name:  gtype.NewString(name),
-		paths: garray.NewStringArray(),
-		jsons: gmap.NewStrAnyMap(),
-		vc:    gtype.NewBool(),
+		name:      gtype.NewString(name),
+		paths:     garray.NewStringArray(),
+		jsons:     gmap.NewStrAnyMap(),
+		vc:        gtype.NewBool(),
+		overrides: gmap.NewListMap(),
+		merged:    gmap.NewStrAnyMap(),
 	}
 	// Customized dir path from env/cmd.
 	if envPath := cmdenv.Get("gf.gcfg.path").String(); envPath != "" {
@@ -259,9 +263,9 @@ func (c *Config) GetFileName() string {
 	return c.name.Val()
 }
 
-// getJson returns a gjson.Json object for the specified <file> content.
+// getJson returns a gjson.Json object for the specified <file> content merged with the in-memory overrides.
 // It would print error if file reading fails.
-// If any error occurs, it return nil.
+// If any error occurs and there's no override, it return nil.
 func (c *Config) getJson(file ...string) *gjson.Json {
 	name := c.name.Val()
 	if len(file) > 0 {
@@ -302,9 +306,9 @@ func (c *Config) getJson(file ...string) *gjson.Json {
 		return nil
 	})
 	if r != nil {
-		return r.(*gjson.Json)
+		return c.withOverrides(name, r.(*gjson.Json))
 	}
-	return nil
+	return c.withOverrides(name, nil)
 }
 
 func (c *Config) Get(pattern string, def ...interface{}) interface{} {
@@ -475,9 +479,28 @@ func (c *Config) GetTime(pattern string, format ...string) time.Time {
 	return time.Time{}
 }
 
+// GetDuration returns the value by <pattern> as time.Duration, the value can be like "30s", "5m", "1h30m".
+// It returns 0 and prints the error if the value has invalid unit.
 func (c *Config) GetDuration(pattern string, def ...interface{}) time.Duration {
 	if j := c.getJson(); j != nil {
-		return j.GetDuration(pattern, def...)
+		d, err := ParseDuration(j.GetString(pattern, def...))
+		if err != nil && errorPrint() {
+			glog.Errorf(`%v for "%s"`, err, pattern)
+		}
+		return d
+	}
+	return 0
+}
+
+// GetBytes returns the value by <pattern> as bytes count, the value can be like "512", "100KB", "512MB", "1GiB".
+// It returns 0 and prints the error if the value has invalid unit, see ParseSize.
+func (c *Config) GetBytes(pattern string, def ...interface{}) int64 {
+	if j := c.getJson(); j != nil {
+		size, err := ParseSize(j.GetString(pattern, def...))
+		if err != nil && errorPrint() {
+			glog.Errorf(`%v for "%s"`, err, pattern)
+		}
+		return size
 	}
 	return 0
 }
@@ -503,6 +526,7 @@ func (c *Config) Reload() {
 
 // Clear removes all parsed configuration files content cache,
 // which will force reload configuration content from file.
+// The in-memory overrides are not removed, see ClearOverrides.
 func (c *Config) Clear() {
 	c.jsons.Clear()
 }
@@ -0,0 +1,73 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gcfg
+
+import (
+	"github.com/gf/g/encoding/gjson"
+)
+
+// overrideJson is the cached configuration content merged with the in-memory overrides.
+type overrideJson struct {
+	base *gjson.Json // The configuration content from file, which is nil if file is not found.
+	json *gjson.Json // The merged configuration content.
+}
+
+// Set sets <value> by <pattern> in the in-memory overlay of the configuration, which takes
+// precedence over the file content for all the Get* methods, eg: c.Set("server.port", 8080).
+// The configuration file is not changed, and the overrides are kept after the file is changed and reloaded.
+// The overrides are applied in the order of setting, so the later one overwrites the earlier one on the same node.
+// It's useful for tests and feature-flag style overrides at runtime.
+func (c *Config) Set(pattern string, value interface{}) {
+	c.overrides.Remove(pattern)
+	c.overrides.Set(pattern, value)
+	c.merged.Clear()
+}
+
+// RemoveOverride removes the in-memory override of <pattern>, which restores the file value.
+func (c *Config) RemoveOverride(pattern string) {
+	c.overrides.Remove(pattern)
+	c.merged.Clear()
+}
+
+// ClearOverrides removes all the in-memory overrides.
+func (c *Config) ClearOverrides() {
+	c.overrides.Clear()
+	c.merged.Clear()
+}
+
+// GetOverrides returns a copy of the in-memory overrides, pattern to value.
+func (c *Config) GetOverrides() map[string]interface{} {
+	m := make(map[string]interface{})
+	c.overrides.Iterator(func(key, value interface{}) bool {
+		m[key.(string)] = value
+		return true
+	})
+	return m
+}
+
+// withOverrides returns the configuration content <base> of file <name> merged with the in-memory overrides.
+// It returns <base> if there's no override. The merged content is cached until <base> or the overrides change.
+func (c *Config) withOverrides(name string, base *gjson.Json) *gjson.Json {
+	if c.overrides.Size() == 0 {
+		return base
+	}
+	if v := c.merged.Get(name); v != nil && v.(*overrideJson).base == base {
+		return v.(*overrideJson).json
+	}
+	// Writing to a concurrent-safe Json object is copy-on-write, so it does not change <base>.
+	json := gjson.New(nil)
+	if base != nil {
+		json = gjson.New(base.Value())
+	}
+	json.SetViolenceCheck(c.vc.Val())
+	c.overrides.Iterator(func(key, value interface{}) bool {
+		_ = json.Set(key.(string), value)
+		return true
+	})
+	c.merged.Set(name, &overrideJson{base: base, json: json})
+	return json
+}
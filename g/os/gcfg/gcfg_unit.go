@@ -0,0 +1,85 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gcfg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Byte units for size values.
+// The SI units(KB, MB...) are multiples of 1000, and the IEC units(KiB, MiB...) are multiples of 1024.
+// The single letter units(K, M...) are multiples of 1024, the same as gfile.FormatSize.
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"m":   1 << 20,
+	"g":   1 << 30,
+	"t":   1 << 40,
+	"p":   1 << 50,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// ParseDuration parses duration string <s> like "300ms", "30s", "1h30m".
+// The valid units are "ns", "us"("µs"), "ms", "s", "m", "h".
+// A plain integer is treated as nanoseconds, which is compatible with gconv.Duration.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf(`[gcfg] invalid duration "%s"`, s)
+	}
+	return d, nil
+}
+
+// ParseSize parses size string <s> like "512", "100KB", "512MB", "1.5GiB" to bytes.
+// The unit is case-insensitive, see sizeUnits for the valid units.
+// A plain number is treated as bytes.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	i := 0
+	for ; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && s[i] != '.' && !(i == 0 && (s[i] == '-' || s[i] == '+')) {
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf(`[gcfg] invalid size "%s"`, s)
+	}
+	unit, ok := sizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, fmt.Errorf(`[gcfg] invalid size unit "%s" in "%s"`, strings.TrimSpace(s[i:]), s)
+	}
+	size := number * unit
+	if size > math.MaxInt64 || size < math.MinInt64 {
+		return 0, fmt.Errorf(`[gcfg] size "%s" overflows`, s)
+	}
+	return int64(size), nil
+}
@@ -12,6 +12,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gogf/gf/g"
 	"github.com/gogf/gf/g/encoding/gjson"
@@ -419,3 +420,93 @@ func TestCfg_Config(t *testing.T) {
 		gtest.Assert(gcfg.GetContent("name"), "")
 	})
 }
+
+func TestCfg_Units(t *testing.T) {
+	content := `
+timeout  = "30s"
+interval = "1h30m"
+raw      = 1000
+invalid  = "30x"
+size1    = "512MB"
+size2    = "1GiB"
+size3    = "1.5k"
+size4    = 2048
+size5    = "10XB"
+`
+	gcfg.SetContent(content)
+	defer gcfg.ClearContent()
+
+	gtest.Case(t, func() {
+		c := gcfg.New()
+		gtest.Assert(c.GetDuration("timeout"), 30*time.Second)
+		gtest.Assert(c.GetDuration("interval"), 90*time.Minute)
+		gtest.Assert(c.GetDuration("raw"), 1000*time.Nanosecond)
+		gtest.Assert(c.GetDuration("invalid"), time.Duration(0))
+		gtest.Assert(c.GetDuration("none", "5m"), 5*time.Minute)
+		gtest.Assert(c.GetDuration("none"), time.Duration(0))
+
+		gtest.Assert(c.GetBytes("size1"), 512*1000*1000)
+		gtest.Assert(c.GetBytes("size2"), 1<<30)
+		gtest.Assert(c.GetBytes("size3"), 1536)
+		gtest.Assert(c.GetBytes("size4"), 2048)
+		gtest.Assert(c.GetBytes("size5"), 0)
+		gtest.Assert(c.GetBytes("none", "64KiB"), 64*1024)
+		gtest.Assert(c.GetBytes("none"), 0)
+	})
+	gtest.Case(t, func() {
+		_, err := gcfg.ParseDuration("10y")
+		gtest.AssertNE(err, nil)
+		_, err = gcfg.ParseSize("MB")
+		gtest.AssertNE(err, nil)
+		_, err = gcfg.ParseSize("1e30PB")
+		gtest.AssertNE(err, nil)
+		size, err := gcfg.ParseSize(" 100 kb ")
+		gtest.Assert(err, nil)
+		gtest.Assert(size, 100000)
+	})
+}
+
+func Test_Set(t *testing.T) {
+	content := `
+v1    = 1
+[redis]
+    disk  = "127.0.0.1:6379,0"
+    cache = "127.0.0.1:6379,1"
+`
+	gcfg.SetContent(content)
+	defer gcfg.ClearContent()
+
+	gtest.Case(t, func() {
+		c := gcfg.New()
+		c.Set("v1", 2)
+		c.Set("redis.disk", "127.0.0.1:6380,0")
+		c.Set("feature.enabled", true)
+		gtest.Assert(c.GetInt("v1"), 2)
+		gtest.Assert(c.GetString("redis.disk"), "127.0.0.1:6380,0")
+		gtest.Assert(c.GetString("redis.cache"), "127.0.0.1:6379,1")
+		gtest.Assert(c.GetBool("feature.enabled"), true)
+		gtest.Assert(len(c.GetOverrides()), 3)
+
+		// Other configuration objects are not affected.
+		gtest.Assert(gcfg.New().GetInt("v1"), 1)
+
+		// The overrides are kept after reloading.
+		gcfg.SetContent("v2 = 3\n" + content)
+		c.Clear()
+		gtest.Assert(c.GetInt("v1"), 2)
+		gtest.Assert(c.GetInt("v2"), 3)
+
+		c.RemoveOverride("v1")
+		gtest.Assert(c.GetInt("v1"), 1)
+		c.ClearOverrides()
+		gtest.Assert(c.GetString("redis.disk"), "127.0.0.1:6379,0")
+		gtest.Assert(c.Contains("feature.enabled"), false)
+	})
+
+	gtest.Case(t, func() {
+		c := gcfg.New("none.toml")
+		gtest.Assert(c.Get("v1"), nil)
+		c.Set("v1", 1)
+		gtest.Assert(c.GetInt("v1"), 1)
+	})
+}
@@ -76,6 +76,12 @@ func AddTimes(pattern string, times int, job func(), name ...string) (*Entry, er
 	return defaultCron.AddTimes(pattern, times, job, name...)
 }
 
+// AddPipeline adds a timed task running pipeline <p> to default cron object.
+// A unique <name> can be bound with the timed task, which is the pipeline name by default.
+func AddPipeline(pattern string, p *Pipeline, name ...string) (*Entry, error) {
+	return defaultCron.AddPipeline(pattern, p, name...)
+}
+
 // DelayAdd adds a timed task to default cron object after <delay> time.
 func DelayAdd(delay time.Duration, pattern string, job func(), name ...string) {
 	defaultCron.DelayAdd(delay, pattern, job, name...)
@@ -0,0 +1,285 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/g/container/gmap"
+	"github.com/gogf/gf/g/os/glog"
+)
+
+const (
+	// Failure policies of pipeline.
+	PIPELINE_SKIP_DEPENDENTS = 0 // (Default) Skips the jobs depending on the failed job, other jobs keep running.
+	PIPELINE_ABORT           = 1 // Aborts the run, all the jobs not started yet are skipped.
+	PIPELINE_CONTINUE        = 2 // Runs the jobs depending on the failed job as usual.
+
+	// Status of job in a pipeline run.
+	JOB_SUCCEEDED = 1 // Job finished without error.
+	JOB_FAILED    = 2 // Job returned error or panicked.
+	JOB_SKIPPED   = 3 // Job was not run because of failed dependencies or aborted run.
+)
+
+// Pipeline is a DAG(directed acyclic graph) of jobs, in which a job starts running only after
+// all of its dependencies finish. Jobs without dependency between each other run concurrently.
+type Pipeline struct {
+	mu     sync.RWMutex
+	name   string                  // Pipeline name.
+	policy int                     // Failure policy.
+	jobs   map[string]*pipelineJob // Jobs by name.
+	names  []string                // Job names in adding order.
+}
+
+// Job in pipeline.
+type pipelineJob struct {
+	name    string                           // Job name.
+	job     func(ctx *PipelineContext) error // Job function.
+	depends []string                         // Names of the jobs this job depends on.
+}
+
+// PipelineContext is the context of a pipeline run, which is shared by all jobs of the run.
+// Jobs can pass values to their dependents through Set/Get.
+type PipelineContext struct {
+	context.Context                       // Context of the run, which is canceled if the run is aborted.
+	Pipeline        string                // Pipeline name.
+	Start           time.Time             // Start time of the run.
+	values          *gmap.StrAnyMap       // Values passed between jobs.
+	mu              sync.RWMutex          // Mutex for results.
+	results         map[string]*JobResult // Results of finished jobs.
+}
+
+// JobResult is the result of a job in a pipeline run.
+type JobResult struct {
+	Name   string    // Job name.
+	Status int       // Job status: JOB_SUCCEEDED, JOB_FAILED, JOB_SKIPPED.
+	Error  error     // Error of failed job.
+	Start  time.Time // Start time, which is zero for skipped job.
+	End    time.Time // End time, which is zero for skipped job.
+}
+
+// NewPipeline creates and returns a pipeline with <name> and optional failure <policy>,
+// the default policy is PIPELINE_SKIP_DEPENDENTS.
+func NewPipeline(name string, policy ...int) *Pipeline {
+	p := &Pipeline{
+		name: name,
+		jobs: make(map[string]*pipelineJob),
+	}
+	if len(policy) > 0 {
+		p.policy = policy[0]
+	}
+	return p
+}
+
+// Name returns the name of the pipeline.
+func (p *Pipeline) Name() string {
+	return p.name
+}
+
+// Add adds a job named <name> to the pipeline, which runs after all jobs of <depends> succeed.
+// The dependencies can be added after the job, and they are validated when the pipeline runs.
+// It returns error if the <name> is already used.
+func (p *Pipeline) Add(name string, job func(ctx *PipelineContext) error, depends ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.jobs[name]; ok {
+		return fmt.Errorf(`job "%s" already exists in pipeline "%s"`, name, p.name)
+	}
+	p.jobs[name] = &pipelineJob{
+		name:    name,
+		job:     job,
+		depends: depends,
+	}
+	p.names = append(p.names, name)
+	return nil
+}
+
+// Validate checks that all dependencies exist and there's no dependency cycle.
+func (p *Pipeline) Validate() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, err := p.sortJobs()
+	return err
+}
+
+// sortJobs returns the jobs in topological order.
+func (p *Pipeline) sortJobs() ([]*pipelineJob, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	var (
+		marks = make(map[string]int, len(p.jobs))
+		order = make([]*pipelineJob, 0, len(p.jobs))
+		visit func(name string, path []string) error
+	)
+	visit = func(name string, path []string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf(`dependency cycle in pipeline "%s": %v`, p.name, append(path, name))
+		}
+		marks[name] = visiting
+		job := p.jobs[name]
+		for _, dep := range job.depends {
+			if _, ok := p.jobs[dep]; !ok {
+				return fmt.Errorf(`job "%s" depends on unknown job "%s" in pipeline "%s"`, name, dep, p.name)
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		order = append(order, job)
+		return nil
+	}
+	for _, name := range p.names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Run runs all jobs of the pipeline with optional parent context <ctx>, and returns the results of all jobs.
+// It returns error if the pipeline is invalid or any job fails.
+func (p *Pipeline) Run(ctx ...context.Context) (map[string]*JobResult, error) {
+	p.mu.RLock()
+	jobs, err := p.sortJobs()
+	policy := p.policy
+	p.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	parent := context.Background()
+	if len(ctx) > 0 && ctx[0] != nil {
+		parent = ctx[0]
+	}
+	runCtx, cancel := context.WithCancel(parent)
+	defer cancel()
+	pc := &PipelineContext{
+		Context:  runCtx,
+		Pipeline: p.name,
+		Start:    time.Now(),
+		values:   gmap.NewStrAnyMap(),
+		results:  make(map[string]*JobResult, len(jobs)),
+	}
+	done := make(map[string]chan struct{}, len(jobs))
+	for _, job := range jobs {
+		done[job.name] = make(chan struct{})
+	}
+	wg := sync.WaitGroup{}
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job *pipelineJob) {
+			defer wg.Done()
+			defer close(done[job.name])
+			for _, dep := range job.depends {
+				<-done[dep]
+			}
+			result := &JobResult{
+				Name:   job.name,
+				Status: JOB_SKIPPED,
+			}
+			if runCtx.Err() == nil && pc.dependenciesMet(job, policy) {
+				result.Start = time.Now()
+				result.Error = runPipelineJob(job, pc)
+				result.End = time.Now()
+				if result.Error == nil {
+					result.Status = JOB_SUCCEEDED
+				} else {
+					result.Status = JOB_FAILED
+					if policy == PIPELINE_ABORT {
+						cancel()
+					}
+				}
+			}
+			pc.setResult(result)
+		}(job)
+	}
+	wg.Wait()
+	// The first failed job in topological order.
+	for _, job := range jobs {
+		if result := pc.results[job.name]; result.Status == JOB_FAILED {
+			return pc.results, fmt.Errorf(`job "%s" of pipeline "%s" failed: %v`, job.name, p.name, result.Error)
+		}
+	}
+	return pc.results, nil
+}
+
+// runPipelineJob runs the job and converts panic to error.
+func runPipelineJob(job *pipelineJob, ctx *PipelineContext) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+	if job.job == nil {
+		return errors.New("nil job function")
+	}
+	return job.job(ctx)
+}
+
+// dependenciesMet checks whether the job can run according to the results of its dependencies.
+func (c *PipelineContext) dependenciesMet(job *pipelineJob, policy int) bool {
+	for _, dep := range job.depends {
+		result := c.Result(dep)
+		if result == nil || result.Status == JOB_SKIPPED {
+			return false
+		}
+		if result.Status == JOB_FAILED && policy != PIPELINE_CONTINUE {
+			return false
+		}
+	}
+	return true
+}
+
+// setResult sets the result of finished job.
+func (c *PipelineContext) setResult(result *JobResult) {
+	c.mu.Lock()
+	c.results[result.Name] = result
+	c.mu.Unlock()
+}
+
+// Result returns the result of job <name>, which is nil if the job is not finished.
+func (c *PipelineContext) Result(name string) *JobResult {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.results[name]
+}
+
+// Set sets value <value> with <key>, which can be read by the following jobs of the run.
+func (c *PipelineContext) Set(key string, value interface{}) {
+	c.values.Set(key, value)
+}
+
+// Get returns the value with <key>, which is nil if it does not exist.
+func (c *PipelineContext) Get(key string) interface{} {
+	return c.values.Get(key)
+}
+
+// AddPipeline adds a timed task running pipeline <p>.
+// The task runs in singleton mode, so a run does not start until the previous run finishes.
+// A unique <name> can be bound with the timed task, which is the pipeline name by default.
+func (c *Cron) AddPipeline(pattern string, p *Pipeline, name ...string) (*Entry, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if len(name) == 0 {
+		name = []string{p.Name()}
+	}
+	return c.AddSingleton(pattern, func() {
+		if _, err := p.Run(); err != nil {
+			glog.Path(c.GetLogPath()).Level(c.GetLogLevel()).Errorf("[gcron] pipeline %s: %v", p.Name(), err)
+		}
+	}, name...)
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g/container/garray"
+	"github.com/gogf/gf/g/os/gcron"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func TestPipeline_Run(t *testing.T) {
+	gtest.Case(t, func() {
+		array := garray.NewStringArray()
+		p := gcron.NewPipeline("etl")
+		gtest.Assert(p.Add("load", func(ctx *gcron.PipelineContext) error {
+			array.Append("load:" + ctx.Get("rows").(string))
+			return nil
+		}, "transform"), nil)
+		gtest.Assert(p.Add("extract", func(ctx *gcron.PipelineContext) error {
+			array.Append("extract")
+			ctx.Set("rows", "1,2,3")
+			return nil
+		}), nil)
+		gtest.Assert(p.Add("transform", func(ctx *gcron.PipelineContext) error {
+			array.Append("transform")
+			return nil
+		}, "extract"), nil)
+		gtest.AssertNE(p.Add("extract", nil), nil)
+
+		results, err := p.Run()
+		gtest.Assert(err, nil)
+		gtest.Assert(array.Slice(), []string{"extract", "transform", "load:1,2,3"})
+		gtest.Assert(len(results), 3)
+		gtest.Assert(results["load"].Status, gcron.JOB_SUCCEEDED)
+		gtest.Assert(results["load"].Start.Before(results["transform"].End), false)
+	})
+}
+
+func TestPipeline_Validate(t *testing.T) {
+	gtest.Case(t, func() {
+		p := gcron.NewPipeline("cycle")
+		p.Add("a", func(ctx *gcron.PipelineContext) error { return nil }, "c")
+		p.Add("b", func(ctx *gcron.PipelineContext) error { return nil }, "a")
+		p.Add("c", func(ctx *gcron.PipelineContext) error { return nil }, "b")
+		gtest.AssertNE(p.Validate(), nil)
+		_, err := p.Run()
+		gtest.AssertNE(err, nil)
+
+		p = gcron.NewPipeline("unknown")
+		p.Add("a", func(ctx *gcron.PipelineContext) error { return nil }, "b")
+		gtest.AssertNE(p.Validate(), nil)
+		_, err = gcron.New().AddPipeline("* * * * * *", p)
+		gtest.AssertNE(err, nil)
+	})
+}
+
+func TestPipeline_Policy(t *testing.T) {
+	newPipeline := func(policy int) *gcron.Pipeline {
+		p := gcron.NewPipeline("policy", policy)
+		p.Add("a", func(ctx *gcron.PipelineContext) error {
+			time.Sleep(20 * time.Millisecond)
+			return errors.New("a failed")
+		})
+		p.Add("b", func(ctx *gcron.PipelineContext) error { return nil }, "a")
+		p.Add("c", func(ctx *gcron.PipelineContext) error { return nil }, "b")
+		p.Add("d", func(ctx *gcron.PipelineContext) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+				return nil
+			}
+		})
+		p.Add("e", func(ctx *gcron.PipelineContext) error { return nil }, "d")
+		return p
+	}
+	gtest.Case(t, func() {
+		results, err := newPipeline(gcron.PIPELINE_SKIP_DEPENDENTS).Run()
+		gtest.AssertNE(err, nil)
+		gtest.Assert(results["a"].Status, gcron.JOB_FAILED)
+		gtest.Assert(results["a"].Error.Error(), "a failed")
+		gtest.Assert(results["b"].Status, gcron.JOB_SKIPPED)
+		gtest.Assert(results["c"].Status, gcron.JOB_SKIPPED)
+		gtest.Assert(results["d"].Status, gcron.JOB_SUCCEEDED)
+		gtest.Assert(results["e"].Status, gcron.JOB_SUCCEEDED)
+	})
+	gtest.Case(t, func() {
+		results, err := newPipeline(gcron.PIPELINE_ABORT).Run()
+		gtest.AssertNE(err, nil)
+		gtest.Assert(results["b"].Status, gcron.JOB_SKIPPED)
+		gtest.Assert(results["d"].Status, gcron.JOB_FAILED)
+		gtest.Assert(results["e"].Status, gcron.JOB_SKIPPED)
+	})
+	gtest.Case(t, func() {
+		results, err := newPipeline(gcron.PIPELINE_CONTINUE).Run()
+		gtest.AssertNE(err, nil)
+		gtest.Assert(results["b"].Status, gcron.JOB_SUCCEEDED)
+		gtest.Assert(results["c"].Status, gcron.JOB_SUCCEEDED)
+	})
+	gtest.Case(t, func() {
+		p := gcron.NewPipeline("panic")
+		p.Add("a", func(ctx *gcron.PipelineContext) error {
+			panic("oops")
+		})
+		results, err := p.Run()
+		gtest.AssertNE(err, nil)
+		gtest.Assert(results["a"].Error.Error(), "oops")
+	})
+}
+
+func TestPipeline_Cron(t *testing.T) {
+	gtest.Case(t, func() {
+		array := garray.New()
+		p := gcron.NewPipeline("cron")
+		p.Add("a", func(ctx *gcron.PipelineContext) error {
+			array.Append(1)
+			return nil
+		})
+		p.Add("b", func(ctx *gcron.PipelineContext) error {
+			array.Append(2)
+			return nil
+		}, "a")
+		cron := gcron.New()
+		entry, err := cron.AddPipeline("* * * * * *", p)
+		gtest.Assert(err, nil)
+		gtest.Assert(entry.Name, "cron")
+		gtest.Assert(entry.IsSingleton(), true)
+		time.Sleep(1200 * time.Millisecond)
+		cron.Close()
+		gtest.Assert(array.Slice(), []interface{}{1, 2})
+	})
+}
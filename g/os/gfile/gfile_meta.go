@@ -0,0 +1,226 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileOwner is the owner information of file.
+type FileOwner struct {
+	Uid   int    // User id of the owner, which is -1 if not supported.
+	Gid   int    // Group id of the owner, which is -1 if not supported.
+	User  string // User name of the owner, which is empty if it cannot be resolved.
+	Group string // Group name of the owner, which is empty if it cannot be resolved.
+}
+
+// FileTimes is the high-resolution timestamps of file,
+// the timestamp not supported by the platform is zero time.
+type FileTimes struct {
+	Access time.Time // Last access time.
+	Modify time.Time // Last modification time of content.
+	Change time.Time // Last status change time (unix), eg: permission or owner changes.
+	Birth  time.Time // Creation time (darwin/windows).
+}
+
+var (
+	// ErrNotSupported is returned if the operation is not supported by the platform or file system.
+	ErrNotSupported = errors.New("operation not supported on this platform or file system")
+)
+
+// Owner returns the owner information of file <path>, it does not follow symbolic link.
+// The user and group names are resolved in best effort.
+func Owner(path string) (*FileOwner, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	uid, gid, err := fileOwner(info)
+	if err != nil {
+		return nil, err
+	}
+	owner := &FileOwner{Uid: uid, Gid: gid}
+	if u, err := user.LookupId(strconv.Itoa(uid)); err == nil {
+		owner.User = u.Username
+	}
+	if g, err := user.LookupGroupId(strconv.Itoa(gid)); err == nil {
+		owner.Group = g.Name
+	}
+	return owner, nil
+}
+
+// Chown changes the owner of file <path>, see os.Chown.
+// The parameter <owner> and <group> can be names or numeric ids,
+// the owner or group is not changed if it's empty.
+func Chown(path string, owner string, group string) error {
+	uid, gid := -1, -1
+	if owner != "" {
+		if id, err := strconv.Atoi(owner); err == nil {
+			uid = id
+		} else if u, err := user.Lookup(owner); err == nil {
+			uid, _ = strconv.Atoi(u.Uid)
+		} else {
+			return err
+		}
+	}
+	if group != "" {
+		if id, err := strconv.Atoi(group); err == nil {
+			gid = id
+		} else if g, err := user.LookupGroup(group); err == nil {
+			gid, _ = strconv.Atoi(g.Gid)
+		} else {
+			return err
+		}
+	}
+	return os.Chown(path, uid, gid)
+}
+
+// ChmodSymbolic changes the mode of file <path> with symbolic notation like chmod command,
+// eg: "u+rw", "go-w", "a=rx,u+w", "+x", or octal notation eg: "0755", see ParseMode.
+func ChmodSymbolic(path string, notation string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	mode, err := ParseMode(notation, info.Mode())
+	if err != nil {
+		return err
+	}
+	return os.Chmod(path, mode)
+}
+
+// ParseMode parses chmod notation <notation> based on current mode <base> and returns the new mode.
+//
+// The symbolic notation is comma separated clauses of [ugoa...][+-=][rwxXst...],
+// the clause applies to all (a) if no user class is given,
+// and the 'X' permission sets execute permission only if <base> is a directory or has any execute permission.
+// The octal notation (eg: 0755, 4755) replaces the permission bits of <base>.
+func ParseMode(notation string, base os.FileMode) (os.FileMode, error) {
+	notation = strings.TrimSpace(notation)
+	special := os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+	if n, err := strconv.ParseUint(notation, 8, 32); err == nil {
+		if n > 07777 {
+			return 0, fmt.Errorf(`invalid mode "%s"`, notation)
+		}
+		mode := base&^(os.ModePerm|special) | os.FileMode(n)&os.ModePerm
+		if n&04000 != 0 {
+			mode |= os.ModeSetuid
+		}
+		if n&02000 != 0 {
+			mode |= os.ModeSetgid
+		}
+		if n&01000 != 0 {
+			mode |= os.ModeSticky
+		}
+		return mode, nil
+	}
+	mode := base
+	for _, clause := range strings.Split(notation, ",") {
+		i := 0
+		who := os.FileMode(0)
+		for ; i < len(clause) && strings.IndexByte("ugoa", clause[i]) >= 0; i++ {
+			switch clause[i] {
+			case 'u':
+				who |= 0700 | os.ModeSetuid
+			case 'g':
+				who |= 0070 | os.ModeSetgid
+			case 'o':
+				who |= 0007 | os.ModeSticky
+			case 'a':
+				who |= os.ModePerm | special
+			}
+		}
+		if i >= len(clause) {
+			return 0, fmt.Errorf(`invalid mode "%s": missing operator in clause "%s"`, notation, clause)
+		}
+		if who == 0 {
+			who = os.ModePerm | special
+		}
+		operator := clause[i]
+		if operator != '+' && operator != '-' && operator != '=' {
+			return 0, fmt.Errorf(`invalid mode "%s": unknown operator '%c'`, notation, operator)
+		}
+		perm := os.FileMode(0)
+		for _, c := range clause[i+1:] {
+			switch c {
+			case 'r':
+				perm |= 0444
+			case 'w':
+				perm |= 0222
+			case 'x':
+				perm |= 0111
+			case 'X':
+				if base.IsDir() || base&0111 != 0 {
+					perm |= 0111
+				}
+			case 's':
+				perm |= os.ModeSetuid | os.ModeSetgid
+			case 't':
+				perm |= os.ModeSticky
+			default:
+				return 0, fmt.Errorf(`invalid mode "%s": unknown permission '%c'`, notation, c)
+			}
+		}
+		perm &= who
+		switch operator {
+		case '+':
+			mode |= perm
+		case '-':
+			mode &^= perm
+		case '=':
+			mode = mode&^(who&(os.ModePerm|os.ModeSetuid|os.ModeSetgid)) | perm
+			if who&0007 != 0 {
+				mode = mode&^os.ModeSticky | perm&os.ModeSticky
+			}
+		}
+	}
+	return mode, nil
+}
+
+// Times returns the high-resolution timestamps of file <path>.
+func Times(path string) (*FileTimes, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	times := fileTimes(info)
+	return &times, nil
+}
+
+// SetTimes changes the access and modification times of file <path> in nanosecond precision,
+// see os.Chtimes.
+func SetTimes(path string, atime time.Time, mtime time.Time) error {
+	return os.Chtimes(path, atime, mtime)
+}
+
+// GetXattr returns the value of extended attribute <name> of file <path>.
+// It uses alternate data stream (ADS) on windows, and note that the name should be
+// in "user." namespace on linux for unprivileged users, eg: user.checksum.
+func GetXattr(path string, name string) ([]byte, error) {
+	return getXattr(path, name)
+}
+
+// SetXattr sets the value of extended attribute <name> of file <path>, see GetXattr.
+func SetXattr(path string, name string, value []byte) error {
+	return setXattr(path, name, value)
+}
+
+// RemoveXattr removes the extended attribute <name> of file <path>, see GetXattr.
+func RemoveXattr(path string, name string) error {
+	return removeXattr(path, name)
+}
+
+// ListXattrs returns the names of all extended attributes of file <path>, see GetXattr.
+func ListXattrs(path string) ([]string, error) {
+	return listXattrs(path)
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gfile
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the timestamps of file <info>.
+func fileTimes(info os.FileInfo) FileTimes {
+	times := FileTimes{Modify: info.ModTime()}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		times.Access = time.Unix(stat.Atimespec.Unix())
+		times.Change = time.Unix(stat.Ctimespec.Unix())
+		times.Birth = time.Unix(stat.Birthtimespec.Unix())
+	}
+	return times
+}
+
+// Extended attributes are not supported on darwin as the syscall package does not provide them.
+
+func getXattr(path string, name string) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func setXattr(path string, name string, value []byte) error {
+	return ErrNotSupported
+}
+
+func removeXattr(path string, name string) error {
+	return ErrNotSupported
+}
+
+func listXattrs(path string) ([]string, error) {
+	return nil, ErrNotSupported
+}
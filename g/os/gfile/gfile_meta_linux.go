@@ -0,0 +1,92 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gfile
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the timestamps of file <info>, the birth time is not supported.
+func fileTimes(info os.FileInfo) FileTimes {
+	times := FileTimes{Modify: info.ModTime()}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		times.Access = time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec))
+		times.Change = time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec))
+	}
+	return times
+}
+
+// getXattr returns the value of extended attribute <name> of file <path>.
+func getXattr(path string, name string) ([]byte, error) {
+	for {
+		size, err := syscall.Getxattr(path, name, nil)
+		if err != nil {
+			return nil, xattrError("getxattr", path, err)
+		}
+		value := make([]byte, size)
+		if size == 0 {
+			return value, nil
+		}
+		// The value may be changed between the two calls, retry if the buffer is too small.
+		n, err := syscall.Getxattr(path, name, value)
+		if err == syscall.ERANGE {
+			continue
+		}
+		if err != nil {
+			return nil, xattrError("getxattr", path, err)
+		}
+		return value[:n], nil
+	}
+}
+
+// setXattr sets the value of extended attribute <name> of file <path>.
+func setXattr(path string, name string, value []byte) error {
+	return xattrError("setxattr", path, syscall.Setxattr(path, name, value, 0))
+}
+
+// removeXattr removes the extended attribute <name> of file <path>.
+func removeXattr(path string, name string) error {
+	return xattrError("removexattr", path, syscall.Removexattr(path, name))
+}
+
+// listXattrs returns the names of all extended attributes of file <path>.
+func listXattrs(path string) ([]string, error) {
+	for {
+		size, err := syscall.Listxattr(path, nil)
+		if err != nil {
+			return nil, xattrError("listxattr", path, err)
+		}
+		if size == 0 {
+			return []string{}, nil
+		}
+		buffer := make([]byte, size)
+		n, err := syscall.Listxattr(path, buffer)
+		if err == syscall.ERANGE {
+			continue
+		}
+		if err != nil {
+			return nil, xattrError("listxattr", path, err)
+		}
+		// The names are separated by NUL char.
+		return strings.Split(strings.TrimRight(string(buffer[:n]), "\x00"), "\x00"), nil
+	}
+}
+
+// xattrError converts <err> of extended attribute operation to *os.PathError,
+// it returns ErrNotSupported if the file system does not support extended attributes.
+func xattrError(op string, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if err == syscall.ENOTSUP {
+		return ErrNotSupported
+	}
+	return &os.PathError{Op: op, Path: path, Err: err}
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+// +build !linux,!darwin,!windows
+
+package gfile
+
+import "os"
+
+// fileTimes returns the timestamps of file <info>, only the modification time is supported.
+func fileTimes(info os.FileInfo) FileTimes {
+	return FileTimes{Modify: info.ModTime()}
+}
+
+// Extended attributes are not supported on other platforms.
+
+func getXattr(path string, name string) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func setXattr(path string, name string, value []byte) error {
+	return ErrNotSupported
+}
+
+func removeXattr(path string, name string) error {
+	return ErrNotSupported
+}
+
+func listXattrs(path string) ([]string, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,23 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+// +build !windows
+
+package gfile
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the user id and group id of file <info>.
+func fileOwner(info os.FileInfo) (uid int, gid int, err error) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return -1, -1, ErrNotSupported
+	}
+	return int(stat.Uid), int(stat.Gid), nil
+}
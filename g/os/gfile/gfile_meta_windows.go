@@ -0,0 +1,49 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gfile
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileOwner is not supported on windows.
+func fileOwner(info os.FileInfo) (uid int, gid int, err error) {
+	return -1, -1, ErrNotSupported
+}
+
+// fileTimes returns the timestamps of file <info>, the status change time is not supported.
+func fileTimes(info os.FileInfo) FileTimes {
+	times := FileTimes{Modify: info.ModTime()}
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		times.Access = time.Unix(0, data.LastAccessTime.Nanoseconds())
+		times.Birth = time.Unix(0, data.CreationTime.Nanoseconds())
+	}
+	return times
+}
+
+// Extended attributes are implemented with alternate data streams (ADS) of NTFS,
+// the stream of attribute <name> is "<path>:<name>".
+
+func getXattr(path string, name string) ([]byte, error) {
+	return ioutil.ReadFile(path + ":" + name)
+}
+
+func setXattr(path string, name string, value []byte) error {
+	return ioutil.WriteFile(path+":"+name, value, gDEFAULT_PERM)
+}
+
+func removeXattr(path string, name string) error {
+	return os.Remove(path + ":" + name)
+}
+
+// listXattrs is not supported on windows, as listing streams requires FindFirstStreamW.
+func listXattrs(path string) ([]string, error) {
+	return nil, ErrNotSupported
+}
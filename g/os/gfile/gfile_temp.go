@@ -0,0 +1,287 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package gfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gf/g/container/gtype"
+	"github.com/gf/g/util/grand"
+)
+
+const (
+	// Default max age of temp entries.
+	gDEFAULT_TEMP_MAX_AGE = 24 * time.Hour
+	// Min interval of automatic cleaning on creating temp entries.
+	gTEMP_CLEAN_INTERVAL = time.Minute
+)
+
+// TempConfig is the configuration of TempManager.
+type TempConfig struct {
+	Dir     string        // Parent directory of the namespace directory, which is TempDir() in default.
+	MaxAge  time.Duration // Max age of temp entries, which is 24 hours in default, negative value means no limit.
+	MaxSize int64         // Max total size in bytes of temp entries, the oldest ones are removed if exceeded, 0 means no limit.
+}
+
+// TempManager manages the temp files and directories of a namespace, which are created in
+// directory <Dir>/<namespace> and named with the pid of the creating process.
+//
+// The expired entries and the oldest entries exceeding the size budget are removed automatically
+// when creating new entries. The entries left by crashed processes are reclaimed when the manager
+// is created, so the temp entries should be removed explicitly or committed after use.
+type TempManager struct {
+	mu        sync.Mutex
+	dir       string      // Namespace directory.
+	config    TempConfig  // Configuration.
+	lastClean time.Time   // Last time of automatic cleaning.
+	cleaning  *gtype.Bool // Whether it's cleaning automatically.
+	prefix    string      // Name prefix of the entries created by current process.
+}
+
+// tempEntry is a temp file or directory in namespace directory.
+type tempEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+var (
+	// Temp managers indexed by namespace.
+	tempManagers   = make(map[string]*TempManager)
+	tempManagersMu sync.Mutex
+)
+
+// Temp returns the temp manager of <namespace>, which is created with optional <config> at the first call,
+// and reclaims the entries left by the processes which no longer exist.
+func Temp(namespace string, config ...TempConfig) *TempManager {
+	tempManagersMu.Lock()
+	defer tempManagersMu.Unlock()
+	if m, ok := tempManagers[namespace]; ok {
+		return m
+	}
+	m := &TempManager{
+		cleaning: gtype.NewBool(),
+		prefix:   strconv.Itoa(os.Getpid()) + "-",
+	}
+	if len(config) > 0 {
+		m.config = config[0]
+	}
+	if m.config.Dir == "" {
+		m.config.Dir = TempDir()
+	}
+	if m.config.MaxAge == 0 {
+		m.config.MaxAge = gDEFAULT_TEMP_MAX_AGE
+	}
+	m.dir = filepath.Join(m.config.Dir, namespace)
+	m.reclaim()
+	tempManagers[namespace] = m
+	return m
+}
+
+// Dir returns the namespace directory of the temp entries.
+func (m *TempManager) Dir() string {
+	return m.dir
+}
+
+// CreateFile creates and opens a new temp file for reading and writing. The file name is generated by
+// replacing the last "*" in <pattern> with a random string, or appending it if there's no "*",
+// eg: "upload-*.tmp". The caller should close the file, and remove it by Remove if it's not used any more.
+func (m *TempManager) CreateFile(pattern ...string) (*os.File, error) {
+	path, err := m.newPath(pattern...)
+	if err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+}
+
+// CreateDir creates a new temp directory and returns its path, see CreateFile for <pattern>.
+func (m *TempManager) CreateDir(pattern ...string) (string, error) {
+	path, err := m.newPath(pattern...)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Mkdir(path, 0700); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Remove removes the temp file or directory <path>.
+func (m *TempManager) Remove(path string) error {
+	if !m.owns(path) {
+		return errors.New("not a temp entry of the manager: " + path)
+	}
+	return os.RemoveAll(path)
+}
+
+// Commit moves the temp file or directory <path> to <dst>, which replaces <dst> if it exists,
+// and its permission is changed to 0644 for file or 0755 for directory.
+// It copies and removes the temp file if it cannot be renamed, eg: across file systems,
+// in which case the file is copied to a temporary name in the directory of <dst> first,
+// so <dst> is never partially written.
+func (m *TempManager) Commit(path string, dst string) error {
+	if !m.owns(path) {
+		return errors.New("not a temp entry of the manager: " + path)
+	}
+	perm := os.FileMode(0644)
+	if IsDir(path) {
+		perm = 0755
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(path, dst); err == nil {
+		return nil
+	}
+	if IsDir(path) {
+		return errors.New("cannot move temp directory across file systems: " + path)
+	}
+	tmp := dst + ".tmp" + grand.Digits(6)
+	if err := Copy(path, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Size returns the total size in bytes of the temp entries.
+func (m *TempManager) Size() int64 {
+	size := int64(0)
+	for _, entry := range m.entries() {
+		size += entry.size
+	}
+	return size
+}
+
+// Clean removes the entries older than MaxAge, and then removes the oldest entries
+// until the total size is not greater than MaxSize.
+func (m *TempManager) Clean() error {
+	m.mu.Lock()
+	m.lastClean = time.Now()
+	m.mu.Unlock()
+	var (
+		firstErr error
+		entries  = m.entries()
+		total    = int64(0)
+	)
+	for _, entry := range entries {
+		total += entry.size
+	}
+	for _, entry := range entries {
+		expired := m.config.MaxAge > 0 && time.Since(entry.modTime) > m.config.MaxAge
+		exceeded := m.config.MaxSize > 0 && total > m.config.MaxSize
+		if !expired && !exceeded {
+			continue
+		}
+		if err := os.RemoveAll(entry.path); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		total -= entry.size
+	}
+	return firstErr
+}
+
+// newPath returns a new entry path using <pattern>, and cleans the entries automatically in background.
+func (m *TempManager) newPath(pattern ...string) (string, error) {
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	clean := time.Since(m.lastClean) >= gTEMP_CLEAN_INTERVAL
+	if clean {
+		m.lastClean = time.Now()
+	}
+	m.mu.Unlock()
+	if clean && !m.cleaning.Set(true) {
+		go func() {
+			defer m.cleaning.Set(false)
+			m.Clean()
+		}()
+	}
+	name := ""
+	if len(pattern) > 0 {
+		name = filepath.Base(pattern[0])
+	}
+	random := strconv.FormatInt(time.Now().UnixNano(), 36) + grand.Str(6)
+	if pos := strings.LastIndex(name, "*"); pos >= 0 {
+		name = name[:pos] + random + name[pos+1:]
+	} else {
+		name += random
+	}
+	return filepath.Join(m.dir, m.prefix+name), nil
+}
+
+// owns checks whether <path> is an entry in the namespace directory.
+func (m *TempManager) owns(path string) bool {
+	return filepath.Dir(filepath.Clean(path)) == filepath.Clean(m.dir)
+}
+
+// entries returns the temp entries sorted by modification time, the oldest first.
+// The size and modification time of a directory are the total size and the latest time of its files.
+func (m *TempManager) entries() []tempEntry {
+	names, err := DirNames(m.dir)
+	if err != nil {
+		return nil
+	}
+	entries := make([]tempEntry, 0, len(names))
+	for _, name := range names {
+		entry := tempEntry{path: filepath.Join(m.dir, name)}
+		filepath.Walk(entry.path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !info.IsDir() {
+				entry.size += info.Size()
+			}
+			if info.ModTime().After(entry.modTime) {
+				entry.modTime = info.ModTime()
+			}
+			return nil
+		})
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+	return entries
+}
+
+// reclaim removes the entries created by the processes which no longer exist, and the entries
+// named with the pid of current process, which are left by a previous process with the same pid.
+func (m *TempManager) reclaim() {
+	names, err := DirNames(m.dir)
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		pos := strings.IndexByte(name, '-')
+		if pos <= 0 {
+			continue
+		}
+		pid, err := strconv.Atoi(name[:pos])
+		if err != nil {
+			continue
+		}
+		if pid == os.Getpid() || !processExists(pid) {
+			os.RemoveAll(filepath.Join(m.dir, name))
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gfile_test
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func TestParseMode(t *testing.T) {
+	gtest.Case(t, func() {
+		mode, err := gfile.ParseMode("u+rw", 0)
+		gtest.Assert(err, nil)
+		gtest.Assert(mode, os.FileMode(0600))
+
+		mode, err = gfile.ParseMode("go-w,+x", 0666)
+		gtest.Assert(err, nil)
+		gtest.Assert(mode, os.FileMode(0755))
+
+		mode, err = gfile.ParseMode("a=r,u+w", 0777)
+		gtest.Assert(err, nil)
+		gtest.Assert(mode, os.FileMode(0644))
+
+		mode, err = gfile.ParseMode("a+X", 0644)
+		gtest.Assert(err, nil)
+		gtest.Assert(mode, os.FileMode(0644))
+
+		mode, err = gfile.ParseMode("a+X", os.ModeDir|0644)
+		gtest.Assert(err, nil)
+		gtest.Assert(mode, os.ModeDir|0755)
+
+		mode, err = gfile.ParseMode("u+s,o+t", 0755)
+		gtest.Assert(err, nil)
+		gtest.Assert(mode, os.ModeSetuid|os.ModeSticky|0755)
+
+		mode, err = gfile.ParseMode("4750", os.ModeDir|0777)
+		gtest.Assert(err, nil)
+		gtest.Assert(mode, os.ModeDir|os.ModeSetuid|0750)
+
+		_, err = gfile.ParseMode("u", 0)
+		gtest.AssertNE(err, nil)
+		_, err = gfile.ParseMode("u+z", 0)
+		gtest.AssertNE(err, nil)
+		_, err = gfile.ParseMode("10000", 0)
+		gtest.AssertNE(err, nil)
+	})
+}
+
+func TestChmodSymbolic(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	gtest.Case(t, func() {
+		path := fmt.Sprintf("%s/gfile_meta_%d", gfile.TempDir(), time.Now().UnixNano())
+		gtest.Assert(gfile.PutContents(path, "test"), nil)
+		defer gfile.Remove(path)
+
+		gtest.Assert(gfile.Chmod(path, 0600), nil)
+		gtest.Assert(gfile.ChmodSymbolic(path, "g+r,o+r"), nil)
+		info, err := os.Stat(path)
+		gtest.Assert(err, nil)
+		gtest.Assert(info.Mode().Perm(), os.FileMode(0644))
+		gtest.AssertNE(gfile.ChmodSymbolic(path, "x"), nil)
+	})
+}
+
+func TestOwner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	gtest.Case(t, func() {
+		path := fmt.Sprintf("%s/gfile_meta_%d", gfile.TempDir(), time.Now().UnixNano())
+		gtest.Assert(gfile.PutContents(path, "test"), nil)
+		defer gfile.Remove(path)
+
+		owner, err := gfile.Owner(path)
+		gtest.Assert(err, nil)
+		gtest.Assert(owner.Uid, os.Getuid())
+		gtest.Assert(owner.Gid, os.Getegid())
+		// 不修改所有者
+		gtest.Assert(gfile.Chown(path, "", ""), nil)
+		gtest.Assert(gfile.Chown(path, fmt.Sprint(os.Getuid()), ""), nil)
+
+		_, err = gfile.Owner(path + ".none")
+		gtest.AssertNE(err, nil)
+	})
+}
+
+func TestTimes(t *testing.T) {
+	gtest.Case(t, func() {
+		path := fmt.Sprintf("%s/gfile_meta_%d", gfile.TempDir(), time.Now().UnixNano())
+		gtest.Assert(gfile.PutContents(path, "test"), nil)
+		defer gfile.Remove(path)
+
+		atime := time.Date(2019, 1, 2, 3, 4, 5, 123456789, time.Local)
+		mtime := time.Date(2019, 6, 7, 8, 9, 10, 987654321, time.Local)
+		gtest.Assert(gfile.SetTimes(path, atime, mtime), nil)
+		times, err := gfile.Times(path)
+		gtest.Assert(err, nil)
+		gtest.Assert(times.Modify.Equal(mtime), true)
+		if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
+			gtest.Assert(times.Access.Equal(atime), true)
+			gtest.Assert(times.Change.IsZero(), false)
+		}
+	})
+}
+
+func TestXattr(t *testing.T) {
+	gtest.Case(t, func() {
+		path := fmt.Sprintf("%s/gfile_meta_%d", gfile.TempDir(), time.Now().UnixNano())
+		gtest.Assert(gfile.PutContents(path, "test"), nil)
+		defer gfile.Remove(path)
+
+		err := gfile.SetXattr(path, "user.checksum", []byte("123"))
+		if err == gfile.ErrNotSupported {
+			return
+		}
+		gtest.Assert(err, nil)
+		value, err := gfile.GetXattr(path, "user.checksum")
+		gtest.Assert(err, nil)
+		gtest.Assert(string(value), "123")
+		if runtime.GOOS != "windows" {
+			names, err := gfile.ListXattrs(path)
+			gtest.Assert(err, nil)
+			gtest.AssertIN("user.checksum", names)
+		}
+		gtest.Assert(gfile.RemoveXattr(path, "user.checksum"), nil)
+		_, err = gfile.GetXattr(path, "user.checksum")
+		gtest.AssertNE(err, nil)
+	})
+}
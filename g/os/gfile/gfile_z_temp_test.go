@@ -0,0 +1,75 @@
+package gfile_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func TestTemp(t *testing.T) {
+	parent, _ := ioutil.TempDir("", "gfile-temp")
+	defer os.RemoveAll(parent)
+	namespace := fmt.Sprintf("test-%d", time.Now().UnixNano())
+
+	// Entries left by a process which no longer exists are reclaimed.
+	os.MkdirAll(filepath.Join(parent, namespace), 0700)
+	dead := filepath.Join(parent, namespace, "999999999-left.tmp")
+	ioutil.WriteFile(dead, []byte("left"), 0600)
+
+	gtest.Case(t, func() {
+		temp := gfile.Temp(namespace, gfile.TempConfig{
+			Dir:     parent,
+			MaxAge:  time.Hour,
+			MaxSize: 10,
+		})
+		gtest.Assert(gfile.Temp(namespace) == temp, true)
+		gtest.Assert(temp.Dir(), filepath.Join(parent, namespace))
+		gtest.Assert(gfile.Exists(dead), false)
+
+		file, err := temp.CreateFile("upload-*.tmp")
+		gtest.Assert(err, nil)
+		gtest.Assert(strings.HasPrefix(filepath.Base(file.Name()), fmt.Sprintf("%d-upload-", os.Getpid())), true)
+		gtest.Assert(strings.HasSuffix(file.Name(), ".tmp"), true)
+		file.WriteString("123456")
+		file.Close()
+
+		dir, err := temp.CreateDir()
+		gtest.Assert(err, nil)
+		ioutil.WriteFile(filepath.Join(dir, "a"), []byte("123456"), 0600)
+		gtest.Assert(temp.Size(), 12)
+
+		// The oldest entry is removed as the size budget is exceeded.
+		old := time.Now().Add(-time.Minute)
+		os.Chtimes(file.Name(), old, old)
+		gtest.Assert(temp.Clean(), nil)
+		gtest.Assert(gfile.Exists(file.Name()), false)
+		gtest.Assert(gfile.Exists(dir), true)
+
+		// The expired entry is removed.
+		old = time.Now().Add(-2 * time.Hour)
+		os.Chtimes(filepath.Join(dir, "a"), old, old)
+		os.Chtimes(dir, old, old)
+		gtest.Assert(temp.Clean(), nil)
+		gtest.Assert(gfile.Exists(dir), false)
+
+		// Committing moves the temp file to the destination.
+		file, err = temp.CreateFile()
+		gtest.Assert(err, nil)
+		file.WriteString("content")
+		file.Close()
+		dst := filepath.Join(parent, "dst.txt")
+		gtest.Assert(temp.Commit(file.Name(), dst), nil)
+		gtest.Assert(gfile.GetContents(dst), "content")
+		gtest.Assert(gfile.Exists(file.Name()), false)
+
+		gtest.AssertNE(temp.Remove(dst), nil)
+		gtest.AssertNE(temp.Commit(dst, dst), nil)
+	})
+}
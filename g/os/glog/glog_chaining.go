@@ -84,3 +84,9 @@ func Line(long ...bool) *Logger {
 func Async(enabled ...bool) *Logger {
 	return logger.Async(enabled...)
 }
+
+// Buffered is a chaining function,
+// which holds the logging content of the levels of <buffer> for the current logging content output.
+func Buffered(buffer *Buffer) *Logger {
+	return logger.Buffered(buffer)
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package glog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	// Sequence number of the last added async logging content.
+	asyncSeq uint64
+	// All the async logging content with sequence number not greater than it has been written.
+	asyncWritten uint64
+	// Sequence numbers of the written async logging content, which are greater than asyncWritten + 1,
+	// as the content may be written out of order.
+	asyncWrittenSet = make(map[uint64]struct{})
+	// Mutex and condition for the sequence numbers.
+	asyncMu   sync.Mutex
+	asyncCond = sync.NewCond(&asyncMu)
+	// Exit hooks called before exiting the process in Fatal.
+	exitHooks   = make([]func(), 0)
+	exitHooksMu sync.Mutex
+)
+
+// Flush blocks until all the async logging content printed before the call is written, which is
+// printed by any logger with F_ASYNC flag. The content printed after the call is not waited for,
+// so it returns even if other goroutines keep printing.
+// It's called automatically before exiting in Fatal, and after printing in Critical and Panic,
+// so that the most important logging content is not lost on crash.
+func Flush() {
+	asyncMu.Lock()
+	target := asyncSeq
+	for asyncWritten < target {
+		asyncCond.Wait()
+	}
+	asyncMu.Unlock()
+}
+
+// Flush blocks until all the async logging content printed before the call is written, see glog.Flush.
+func (l *Logger) Flush() {
+	Flush()
+}
+
+// AddExitHook registers function <f>, which is called before exiting the current process in Fatal.
+// The hooks are called in the reverse order of registration like defer, after the logging content is flushed.
+// A panic in the hook is recovered and printed to stderr, which does not stop the other hooks.
+func AddExitHook(f func()) {
+	exitHooksMu.Lock()
+	exitHooks = append(exitHooks, f)
+	exitHooksMu.Unlock()
+}
+
+// addAsync adds the async logging <f> to the goroutine pool, which is counted for Flush.
+func addAsync(f func()) {
+	asyncMu.Lock()
+	asyncSeq++
+	seq := asyncSeq
+	asyncMu.Unlock()
+	done := func() {
+		asyncMu.Lock()
+		defer asyncMu.Unlock()
+		if seq != asyncWritten+1 {
+			asyncWrittenSet[seq] = struct{}{}
+			return
+		}
+		asyncWritten = seq
+		for {
+			if _, ok := asyncWrittenSet[asyncWritten+1]; !ok {
+				break
+			}
+			delete(asyncWrittenSet, asyncWritten+1)
+			asyncWritten++
+		}
+		asyncCond.Broadcast()
+	}
+	err := asyncPool.Add(func() {
+		defer done()
+		f()
+	})
+	// Writing synchronously if the pool is not available.
+	if err != nil {
+		f()
+		done()
+	}
+}
+
+// exit flushes the async logging content, calls the exit hooks and exits the current process with <code>.
+func exit(code int) {
+	Flush()
+	exitHooksMu.Lock()
+	hooks := exitHooks
+	exitHooks = make([]func(), 0)
+	exitHooksMu.Unlock()
+	for i := len(hooks) - 1; i >= 0; i-- {
+		callExitHook(hooks[i])
+	}
+	os.Exit(code)
+}
+
+// callExitHook calls exit hook <f> and recovers its panic.
+func callExitHook(f func()) {
+	defer func() {
+		if e := recover(); e != nil {
+			fmt.Fprintln(os.Stderr, "glog exit hook panic:", e)
+		}
+	}()
+	f()
+}
@@ -36,6 +36,7 @@ type Logger struct {
 	btStatus    int       // Backtrace status(1: enabled - default; 0: disabled)
 	headerPrint bool      // Print header or not(true in default).
 	stdoutPrint bool      // Output to stdout or not(true in default).
+	buffer      *Buffer   // Logging buffer holding content of specified levels.
 }
 
 const (
@@ -149,6 +150,12 @@ func (l *Logger) SetWriter(writer io.Writer) {
 	l.writer = writer
 }
 
+// SetBuffer sets the logging buffer, which holds the logging content of its levels.
+// See Buffer.
+func (l *Logger) SetBuffer(buffer *Buffer) {
+	l.buffer = buffer
+}
+
 // GetWriter returns the customized writer object, which implements the io.Writer interface.
 // It returns nil if no writer previously set.
 func (l *Logger) GetWriter() io.Writer {
@@ -275,8 +282,11 @@ func (l *Logger) print(std io.Writer, lead string, value ...interface{}) {
 		buffer.WriteString(gconv.String(v))
 	}
 	buffer.WriteString(ln)
+	if l.buffer != nil && l.buffer.hold(l, std, lead, buffer) {
+		return
+	}
 	if l.flags&F_ASYNC > 0 {
-		asyncPool.Add(func() {
+		addAsync(func() {
 			l.printToWriter(std, buffer)
 		})
 	} else {
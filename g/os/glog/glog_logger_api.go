@@ -8,7 +8,6 @@ package glog
 
 import (
 	"fmt"
-	"os"
 )
 
 // Print prints <v> with newline using fmt.Sprintln.
@@ -35,33 +34,38 @@ func (l *Logger) Printfln(format string, v ...interface{}) {
 }
 
 // Fatal prints the logging content with [FATA] header and newline, then exit the current process.
+// The async logging content is flushed and the exit hooks are called before exiting, see AddExitHook.
 func (l *Logger) Fatal(v ...interface{}) {
 	l.printErr("[FATA]", v...)
-	os.Exit(1)
+	exit(1)
 }
 
 // Fatalf prints the logging content with [FATA] header, custom format and newline, then exit the current process.
+// The async logging content is flushed and the exit hooks are called before exiting, see AddExitHook.
 func (l *Logger) Fatalf(format string, v ...interface{}) {
 	l.printErr("[FATA]", l.format(format, v...))
-	os.Exit(1)
+	exit(1)
 }
 
 // Deprecated.
 // Use Fatalf instead.
 func (l *Logger) Fatalfln(format string, v ...interface{}) {
 	l.Fatalf(format, v...)
-	os.Exit(1)
 }
 
 // Panic prints the logging content with [PANI] header and newline, then panics.
+// The async logging content is flushed before panicking.
 func (l *Logger) Panic(v ...interface{}) {
 	l.printErr("[PANI]", v...)
+	Flush()
 	panic(fmt.Sprint(v...))
 }
 
 // Panicf prints the logging content with [PANI] header, custom format and newline, then panics.
+// The async logging content is flushed before panicking.
 func (l *Logger) Panicf(format string, v ...interface{}) {
 	l.printErr("[PANI]", l.format(format, v...))
+	Flush()
 	panic(l.format(format, v...))
 }
 
@@ -189,17 +193,21 @@ func (l *Logger) Errorfln(format string, v ...interface{}) {
 
 // Critical prints the logging content with [CRIT] header and newline.
 // It also prints caller backtrace info if backtrace feature is enabled.
+// The async logging content is flushed after printing.
 func (l *Logger) Critical(v ...interface{}) {
 	if l.checkLevel(LEVEL_CRIT) {
 		l.printErr("[CRIT]", v...)
+		Flush()
 	}
 }
 
 // Criticalf prints the logging content with [CRIT] header, custom format and newline.
 // It also prints caller backtrace info if backtrace feature is enabled.
+// The async logging content is flushed after printing.
 func (l *Logger) Criticalf(format string, v ...interface{}) {
 	if l.checkLevel(LEVEL_CRIT) {
 		l.printErr("[CRIT]", l.format(format, v...))
+		Flush()
 	}
 }
 
@@ -0,0 +1,136 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+package glog
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// Buffer holds the logging content of specified levels in memory, for a request or a task,
+// which is emitted only if the request/task fails or is slow, see Done.
+// The logging content with error levels(ERRO/CRIT/PANI/FATA) marks the buffer failed,
+// which flushes the held content immediately, and the following content is not held any more.
+// It is concurrent-safe.
+type Buffer struct {
+	mu     sync.Mutex
+	levels int           // Logging levels to be held.
+	start  time.Time     // Creation time of the buffer.
+	failed bool          // Whether the buffer is marked failed.
+	items  []*bufferItem // Held logging content.
+}
+
+// bufferItem is a held logging content.
+type bufferItem struct {
+	logger  *Logger       // The logger that prints the content.
+	std     io.Writer     // The std writer of the content.
+	content *bytes.Buffer // The formatted logging content.
+}
+
+var (
+	// Mapping from logging header to level.
+	leadLevels = map[string]int{
+		"[DEBU]": LEVEL_DEBU,
+		"[INFO]": LEVEL_INFO,
+		"[NOTI]": LEVEL_NOTI,
+		"[WARN]": LEVEL_WARN,
+		"[ERRO]": LEVEL_ERRO,
+		"[CRIT]": LEVEL_CRIT,
+		"[PANI]": LEVEL_CRIT,
+		"[FATA]": LEVEL_CRIT,
+	}
+)
+
+// NewBuffer creates and returns a logging buffer, which holds the logging content of <levels>.
+// The parameter <levels> is LEVEL_DEBU in default.
+func NewBuffer(levels ...int) *Buffer {
+	b := &Buffer{
+		levels: LEVEL_DEBU,
+		start:  time.Now(),
+		items:  make([]*bufferItem, 0),
+	}
+	if len(levels) > 0 {
+		b.levels = levels[0]
+	}
+	return b
+}
+
+// Len returns the count of held logging content.
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.items)
+}
+
+// IsFailed checks whether the buffer is marked failed.
+func (b *Buffer) IsFailed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failed
+}
+
+// Fail marks the buffer failed and flushes the held logging content.
+func (b *Buffer) Fail() {
+	b.mu.Lock()
+	b.failed = true
+	b.mu.Unlock()
+	b.Flush()
+}
+
+// Flush emits all the held logging content in order.
+func (b *Buffer) Flush() {
+	b.mu.Lock()
+	items := b.items
+	b.items = make([]*bufferItem, 0)
+	b.mu.Unlock()
+	for _, item := range items {
+		item.logger.printToWriter(item.std, item.content)
+	}
+}
+
+// Discard drops all the held logging content.
+func (b *Buffer) Discard() {
+	b.mu.Lock()
+	b.items = make([]*bufferItem, 0)
+	b.mu.Unlock()
+}
+
+// Done ends the buffering, it flushes the held logging content if the buffer is marked failed,
+// or the elapsed time since the creation exceeds <threshold>, or else discards them.
+// The <threshold> being not greater than 0 means no latency checking.
+// It returns true if the logging content is flushed.
+func (b *Buffer) Done(threshold time.Duration) bool {
+	if b.IsFailed() || (threshold > 0 && time.Since(b.start) >= threshold) {
+		b.Flush()
+		return true
+	}
+	b.Discard()
+	return false
+}
+
+// hold holds the logging <content> of <lead> if its level matches the buffer,
+// it returns false if the content is not held and should be printed directly.
+func (b *Buffer) hold(logger *Logger, std io.Writer, lead string, content *bytes.Buffer) bool {
+	level := leadLevels[lead]
+	if level&(LEVEL_ERRO|LEVEL_CRIT) > 0 {
+		b.Fail()
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failed || level&b.levels == 0 {
+		return false
+	}
+	b.items = append(b.items, &bufferItem{
+		logger:  logger,
+		std:     std,
+		content: content,
+	})
+	return true
+}
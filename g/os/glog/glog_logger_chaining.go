@@ -192,3 +192,17 @@ func (l *Logger) Async(enabled ...bool) *Logger {
 	}
 	return logger
 }
+
+// Buffered is a chaining function,
+// which holds the logging content of the levels of <buffer> for the current logging content output.
+// See Buffer.
+func (l *Logger) Buffered(buffer *Buffer) *Logger {
+	logger := (*Logger)(nil)
+	if l.parent == nil {
+		logger = l.Clone()
+	} else {
+		logger = l
+	}
+	logger.SetBuffer(buffer)
+	return logger
+}
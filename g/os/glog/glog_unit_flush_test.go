@@ -0,0 +1,144 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+// blockingWriter is a writer which blocks writing the content containing the keys of <blocks>
+// until the corresponding channel is closed.
+type blockingWriter struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+	blocks map[string]chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	for key, ch := range w.blocks {
+		if bytes.Contains(p, []byte(key)) {
+			<-ch
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buffer.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buffer.String()
+}
+
+func Test_Flush(t *testing.T) {
+	gtest.Case(t, func() {
+		writer := &blockingWriter{}
+		logger := glog.New()
+		logger.SetWriter(writer)
+		logger.SetStdoutPrint(false)
+		logger.SetAsync(true)
+		for i := 0; i < 100; i++ {
+			logger.Print(i)
+		}
+		logger.Flush()
+		content := writer.String()
+		for i := 0; i < 100; i++ {
+			gtest.Assert(strings.Contains(content, fmt.Sprintf(" %d\n", i)), true)
+		}
+	})
+
+	// Flush does not wait for the content printed after the call.
+	gtest.Case(t, func() {
+		early, late := make(chan struct{}), make(chan struct{})
+		writer := &blockingWriter{blocks: map[string]chan struct{}{"early": early, "late": late}}
+		logger := glog.New()
+		logger.SetWriter(writer)
+		logger.SetStdoutPrint(false)
+		logger.SetAsync(true)
+		logger.Print("early")
+		flushed := make(chan struct{})
+		go func() {
+			glog.Flush()
+			close(flushed)
+		}()
+		time.Sleep(100 * time.Millisecond)
+		logger.Print("late")
+		select {
+		case <-flushed:
+			t.Fatal("flushed before the content is written")
+		case <-time.After(100 * time.Millisecond):
+		}
+		close(early)
+		select {
+		case <-flushed:
+		case <-time.After(time.Second):
+			t.Fatal("flush waits for the content printed after the call")
+		}
+		gtest.Assert(strings.Contains(writer.String(), "early"), true)
+		close(late)
+		glog.Flush()
+		gtest.Assert(strings.Contains(writer.String(), "late"), true)
+	})
+}
+
+func Test_ExitHook(t *testing.T) {
+	// The child process prints async content and exits in Fatal.
+	if os.Getenv("GLOG_TEST_EXIT") == "1" {
+		logger := glog.New()
+		logger.SetStdoutPrint(false)
+		logger.SetWriter(os.Stdout)
+		logger.SetAsync(true)
+		glog.AddExitHook(func() {
+			fmt.Println("hook 1")
+		})
+		glog.AddExitHook(func() {
+			panic("hook 2")
+		})
+		glog.AddExitHook(func() {
+			fmt.Println("hook 3")
+		})
+		for i := 0; i < 10; i++ {
+			logger.Print("async", i)
+		}
+		logger.Fatal("fatal")
+		return
+	}
+	gtest.Case(t, func() {
+		cmd := exec.Command(os.Args[0], "-test.run=^Test_ExitHook$")
+		cmd.Env = append(os.Environ(), "GLOG_TEST_EXIT=1")
+		stdout, stderr := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+		err := cmd.Run()
+		exitErr, ok := err.(*exec.ExitError)
+		gtest.Assert(ok, true)
+		gtest.Assert(exitErr.ExitCode(), 1)
+
+		// The async content is flushed before the hooks, which are called in reverse order.
+		content := stdout.String()
+		lines := strings.Split(strings.TrimSpace(content), "\n")
+		gtest.Assert(len(lines), 13)
+		for i := 0; i < 10; i++ {
+			gtest.Assert(strings.HasSuffix(lines[i], fmt.Sprintf("async %d", i)), true)
+		}
+		gtest.Assert(strings.HasSuffix(lines[10], "[FATA] fatal"), true)
+		gtest.Assert(lines[11], "hook 3")
+		gtest.Assert(lines[12], "hook 1")
+		// The panic of hook is recovered and printed to stderr.
+		gtest.Assert(strings.TrimSpace(stderr.String()), "glog exit hook panic: hook 2")
+	})
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 gf Author(https://github.com/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gf.
+
+// Package gmetrics provides a simple in-process registry of named counters and gauges,
+// which can be published through expvar or collected by any monitoring system using Snapshot.
+package gmetrics
+
+import (
+	"expvar"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	TYPE_COUNTER = "counter" // Monotonically increasing value, eg: count of operations.
+	TYPE_GAUGE   = "gauge"   // Value that can go up and down, eg: size of a container.
+
+	// Default expvar name of the published metrics.
+	gDEFAULT_EXPVAR_NAME = "gmetrics"
+)
+
+// Metric is the snapshot of a registered metric.
+type Metric struct {
+	Name  string  `json:"name"`
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+}
+
+// Counter is a concurrent-safe monotonically increasing counter.
+type Counter struct {
+	value int64
+}
+
+// metric is a registered metric with its value function.
+type metric struct {
+	kind  string
+	value func() float64
+}
+
+var (
+	// Registered metrics, name to *metric.
+	metrics   = make(map[string]*metric)
+	metricsMu sync.RWMutex
+	// Mutex for publishing expvar.
+	publishMu sync.Mutex
+)
+
+// NewCounter creates, registers and returns a counter with <name>,
+// which replaces the registered metric with the same name.
+func NewCounter(name string) *Counter {
+	c := &Counter{}
+	AddCounterFunc(name, func() float64 {
+		return float64(c.Val())
+	})
+	return c
+}
+
+// Add adds <delta> to the counter, which should not be negative.
+func (c *Counter) Add(delta int64) {
+	atomic.AddInt64(&c.value, delta)
+}
+
+// Inc increases the counter by 1.
+func (c *Counter) Inc() {
+	atomic.AddInt64(&c.value, 1)
+}
+
+// Val returns the current value of the counter.
+func (c *Counter) Val() int64 {
+	return atomic.LoadInt64(&c.value)
+}
+
+// AddCounterFunc registers a counter with <name>, whose value is returned by <f> on collecting.
+// It replaces the registered metric with the same name.
+func AddCounterFunc(name string, f func() float64) {
+	add(name, TYPE_COUNTER, f)
+}
+
+// AddGauge registers a gauge with <name>, whose value is returned by <f> on collecting.
+// It replaces the registered metric with the same name.
+func AddGauge(name string, f func() float64) {
+	add(name, TYPE_GAUGE, f)
+}
+
+// add registers metric <name> of type <kind>.
+func add(name string, kind string, f func() float64) {
+	metricsMu.Lock()
+	metrics[name] = &metric{kind: kind, value: f}
+	metricsMu.Unlock()
+}
+
+// Remove removes the metrics of <names>.
+func Remove(names ...string) {
+	metricsMu.Lock()
+	for _, name := range names {
+		delete(metrics, name)
+	}
+	metricsMu.Unlock()
+}
+
+// RemovePrefix removes the metrics whose names start with <prefix>,
+// eg: RemovePrefix("user_cache.") removes all the metrics of an instrumented container named "user_cache".
+func RemovePrefix(prefix string) {
+	metricsMu.Lock()
+	for name := range metrics {
+		if strings.HasPrefix(name, prefix) {
+			delete(metrics, name)
+		}
+	}
+	metricsMu.Unlock()
+}
+
+// Get returns the current value of metric <name>, the returned <found> is false if it's not registered.
+func Get(name string) (value float64, found bool) {
+	metricsMu.RLock()
+	m, ok := metrics[name]
+	metricsMu.RUnlock()
+	if !ok {
+		return 0, false
+	}
+	return m.value(), true
+}
+
+// Snapshot returns the current values of all the registered metrics, ordered by name.
+func Snapshot() []Metric {
+	metricsMu.RLock()
+	list := make([]Metric, 0, len(metrics))
+	funcs := make([]func() float64, 0, len(metrics))
+	for name, m := range metrics {
+		list = append(list, Metric{Name: name, Type: m.kind})
+		funcs = append(funcs, m.value)
+	}
+	metricsMu.RUnlock()
+	// The value functions are called outside the lock as they may be slow or register metrics.
+	for i, f := range funcs {
+		list[i].Value = f()
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Map returns the current values of all the registered metrics as a map of name to value.
+func Map() map[string]float64 {
+	snapshot := Snapshot()
+	m := make(map[string]float64, len(snapshot))
+	for _, item := range snapshot {
+		m[item.Name] = item.Value
+	}
+	return m
+}
+
+// PublishExpvar publishes all the registered metrics through expvar with <name>, which is "gmetrics" in default,
+// so they are served in the "/debug/vars" endpoint of expvar as a JSON object of metric name to value.
+// The metrics are collected on every request of the endpoint. It's safe to call it multiple times.
+func PublishExpvar(name ...string) {
+	key := gDEFAULT_EXPVAR_NAME
+	if len(name) > 0 && name[0] != "" {
+		key = name[0]
+	}
+	publishMu.Lock()
+	defer publishMu.Unlock()
+	if expvar.Get(key) != nil {
+		return
+	}
+	expvar.Publish(key, expvar.Func(func() interface{} {
+		return Map()
+	}))
+}
+
+// Wrapper wraps a value, eg: a long-lived named container, counting the read and write operations on it.
+// The counting is opt-in by accessing the value through the wrapper, which costs nothing to the others.
+type Wrapper[T any] struct {
+	value  T
+	reads  *Counter
+	writes *Counter
+}
+
+// Wrap creates and returns a wrapper of <value>, which registers the "<name>.reads" and "<name>.writes"
+// counters of the operations, eg:
+//
+//	users := gmetrics.Wrap("users", gmap.NewStrAnyMap())
+//	users.Write(func(m *gmap.StrAnyMap) { m.Set("john", user) })
+func Wrap[T any](name string, value T) *Wrapper[T] {
+	return &Wrapper[T]{
+		value:  value,
+		reads:  NewCounter(name + ".reads"),
+		writes: NewCounter(name + ".writes"),
+	}
+}
+
+// Read calls <f> with the wrapped value for a read operation, and increases the "<name>.reads" counter.
+func (w *Wrapper[T]) Read(f func(value T)) {
+	w.reads.Inc()
+	f(w.value)
+}
+
+// Write calls <f> with the wrapped value for a write operation, and increases the "<name>.writes" counter.
+func (w *Wrapper[T]) Write(f func(value T)) {
+	w.writes.Inc()
+	f(w.value)
+}
+
+// Val returns the wrapped value, the operations on which are not counted.
+func (w *Wrapper[T]) Val() T {
+	return w.value
+}
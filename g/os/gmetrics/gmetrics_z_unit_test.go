@@ -0,0 +1,99 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmetrics_test
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/gogf/gf/g/container/gmap"
+	"github.com/gogf/gf/g/container/gset"
+	"github.com/gogf/gf/g/os/gmetrics"
+	"github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Counter_Gauge(t *testing.T) {
+	gtest.Case(t, func() {
+		c := gmetrics.NewCounter("test.counter")
+		c.Inc()
+		c.Add(2)
+		gtest.Assert(c.Val(), 3)
+		size := 5
+		gmetrics.AddGauge("test.gauge", func() float64 {
+			return float64(size)
+		})
+		v, ok := gmetrics.Get("test.counter")
+		gtest.Assert(ok, true)
+		gtest.Assert(v, 3)
+		size = 6
+		v, ok = gmetrics.Get("test.gauge")
+		gtest.Assert(ok, true)
+		gtest.Assert(v, 6)
+
+		snapshot := gmetrics.Snapshot()
+		names := make([]string, 0)
+		for _, m := range snapshot {
+			names = append(names, m.Name)
+		}
+		gtest.AssertIN("test.counter", names)
+		gtest.AssertIN("test.gauge", names)
+
+		gmetrics.PublishExpvar("test_metrics")
+		gmetrics.PublishExpvar("test_metrics")
+		gtest.AssertNE(expvar.Get("test_metrics"), nil)
+
+		gmetrics.RemovePrefix("test.")
+		_, ok = gmetrics.Get("test.counter")
+		gtest.Assert(ok, false)
+		_, ok = gmetrics.Get("test.gauge")
+		gtest.Assert(ok, false)
+	})
+}
+
+func Test_Container_Instrument(t *testing.T) {
+	gtest.Case(t, func() {
+		m := gmap.NewStrIntMap()
+		m.Instrument("test.map")
+		m.Set("a", 1)
+		m.Set("b", 2)
+		m.Remove("b")
+		gtest.Assert(gmetrics.Map()["test.map.size"], 1)
+
+		s := gset.NewIntSet()
+		s.Instrument("test.set")
+		s.Add(1, 2, 3)
+		gtest.Assert(gmetrics.Map()["test.set.size"], 3)
+
+		// The size of unsafe containers is not sampled.
+		unsafe := gset.NewIntSet(true)
+		unsafe.Instrument("test.unsafe")
+		_, ok := gmetrics.Get("test.unsafe.size")
+		gtest.Assert(ok, false)
+		gmetrics.RemovePrefix("test.")
+	})
+}
+
+func Test_Wrap(t *testing.T) {
+	gtest.Case(t, func() {
+		w := gmetrics.Wrap("test.wrap", gmap.NewStrIntMap())
+		w.Write(func(m *gmap.StrIntMap) {
+			m.Set("a", 1)
+		})
+		w.Write(func(m *gmap.StrIntMap) {
+			m.Set("b", 2)
+		})
+		value := 0
+		w.Read(func(m *gmap.StrIntMap) {
+			value = m.Get("a")
+		})
+		gtest.Assert(value, 1)
+		gtest.Assert(w.Val().Size(), 2)
+		gtest.Assert(gmetrics.Map()["test.wrap.reads"], 1)
+		gtest.Assert(gmetrics.Map()["test.wrap.writes"], 2)
+		gmetrics.RemovePrefix("test.")
+	})
+}
@@ -81,6 +81,11 @@ var (
 
 // 使用自定义日期格式格式化输出日期。
 func (t *Time) Format(format string) string {
+	return t.format(format, nil)
+}
+
+// 使用自定义日期格式及本地化文本格式化输出日期，本地化文本为nil时输出英文。
+func (t *Time) format(format string, locale *Locale) string {
 	runes := []rune(format)
 	buffer := bytes.NewBuffer(nil)
 	for i := 0; i < len(runes); {
@@ -106,6 +111,12 @@ func (t *Time) Format(format string) string {
 				buffer.WriteRune(runes[i])
 				break
 			}
+			if locale != nil {
+				if text, ok := t.localeText(byte(runes[i]), locale); ok {
+					buffer.WriteString(text)
+					break
+				}
+			}
 			if f, ok := formats[byte(runes[i])]; ok {
 				result := t.Time.Format(f)
 				// 有几个转换的符号需要特殊处理
@@ -134,6 +145,34 @@ func (t *Time) Format(format string) string {
 	return buffer.String()
 }
 
+// 返回本地化格式对应的文本，非本地化格式返回false。
+func (t *Time) localeText(c byte, locale *Locale) (string, bool) {
+	switch c {
+	case 'F':
+		return locale.Months[t.Month()-1], true
+	case 'M':
+		return locale.ShortMonths[t.Month()-1], true
+	case 'l':
+		return locale.Weekdays[t.Weekday()], true
+	case 'D':
+		return locale.ShortWeekdays[t.Weekday()], true
+	case 'A', 'a':
+		text := locale.PM
+		if t.Hour() < 12 {
+			text = locale.AM
+		}
+		if c == 'a' {
+			text = strings.ToLower(text)
+		}
+		return text, true
+	case 'S':
+		if !locale.DaySuffix {
+			return "", true
+		}
+	}
+	return "", false
+}
+
 // 通过自定义格式转换当前日期为新的日期。
 func (t *Time) FormatTo(format string) *Time {
 	t.Time = NewFromStr(t.Format(format)).Time
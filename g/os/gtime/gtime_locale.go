@@ -0,0 +1,145 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtime
+
+import (
+	"strings"
+	"sync"
+)
+
+// 日期格式化的本地化文本，月份从一月开始，星期从星期天开始。
+type Locale struct {
+	Months        [12]string // 月份完整名称，对应格式 F
+	ShortMonths   [12]string // 月份缩写名称，对应格式 M
+	Weekdays      [7]string  // 星期完整名称，对应格式 l
+	ShortWeekdays [7]string  // 星期缩写名称，对应格式 D
+	AM            string     // 上午，对应格式 A，格式 a 使用其小写
+	PM            string     // 下午，对应格式 A，格式 a 使用其小写
+	DaySuffix     bool       // 是否输出英文的天数后缀，对应格式 S
+}
+
+const (
+	// 默认的英文本地化名称
+	LOCALE_EN = "en"
+)
+
+var (
+	// 已注册的本地化文本，键名为小写的本地化名称
+	locales = map[string]*Locale{
+		LOCALE_EN: {
+			Months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+			ShortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+			Weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			ShortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+			AM:            "AM",
+			PM:            "PM",
+			DaySuffix:     true,
+		},
+		"zh-cn": {
+			Months:        [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+			ShortMonths:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			Weekdays:      [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+			ShortWeekdays: [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+			AM:            "上午",
+			PM:            "下午",
+		},
+		"zh-tw": {
+			Months:        [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+			ShortMonths:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			Weekdays:      [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+			ShortWeekdays: [7]string{"週日", "週一", "週二", "週三", "週四", "週五", "週六"},
+			AM:            "上午",
+			PM:            "下午",
+		},
+		"ja": {
+			Months:        [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			ShortMonths:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			Weekdays:      [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+			ShortWeekdays: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+			AM:            "午前",
+			PM:            "午後",
+		},
+		"ko": {
+			Months:        [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+			ShortMonths:   [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+			Weekdays:      [7]string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"},
+			ShortWeekdays: [7]string{"일", "월", "화", "수", "목", "금", "토"},
+			AM:            "오전",
+			PM:            "오후",
+		},
+		"de": {
+			Months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			ShortMonths:   [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+			Weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			ShortWeekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+			AM:            "AM",
+			PM:            "PM",
+		},
+		"fr": {
+			Months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			ShortMonths:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			Weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			ShortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+			AM:            "AM",
+			PM:            "PM",
+		},
+		"es": {
+			Months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			ShortMonths:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+			Weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			ShortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+			AM:            "a. m.",
+			PM:            "p. m.",
+		},
+		"ru": {
+			Months:        [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+			ShortMonths:   [12]string{"янв", "фев", "мар", "апр", "мая", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"},
+			Weekdays:      [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+			ShortWeekdays: [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+			AM:            "AM",
+			PM:            "PM",
+		},
+	}
+	// 本地化文本读写锁
+	localesMu sync.RWMutex
+)
+
+// 注册本地化文本，本地化名称不区分大小写，且"_"与"-"等价，例如：zh-CN、zh_cn，
+// 已存在的本地化文本将会被覆盖，可用于国际化模块注册更多的语言。
+func RegisterLocale(name string, locale *Locale) {
+	localesMu.Lock()
+	locales[normalizeLocaleName(name)] = locale
+	localesMu.Unlock()
+}
+
+// 获取本地化文本，当完整名称(例如：de-AT)不存在时使用其语言名称(例如：de)查找，
+// 都不存在时返回英文的本地化文本。
+func GetLocale(name string) *Locale {
+	name = normalizeLocaleName(name)
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	if locale, ok := locales[name]; ok {
+		return locale
+	}
+	if pos := strings.IndexByte(name, '-'); pos > 0 {
+		if locale, ok := locales[name[:pos]]; ok {
+			return locale
+		}
+	}
+	return locales[LOCALE_EN]
+}
+
+// 使用自定义日期格式及指定的本地化名称格式化输出日期，
+// 格式 F、M、l、D、a、A、S 将会输出本地化的文本，其他格式与 Format 相同。
+func (t *Time) FormatLocale(format string, locale string) string {
+	return t.format(format, GetLocale(locale))
+}
+
+// 标准化本地化名称
+func normalizeLocaleName(name string) string {
+	return strings.Replace(strings.ToLower(name), "_", "-", -1)
+}
@@ -107,3 +107,24 @@ func Test_LayoutTo(t *testing.T) {
 		gtest.Assert(timeTemp.LayoutTo("2006-01-02 00:00:00"), timeTemp.Time.Format("2006-01-02 00:00:00"))
 	})
 }
+
+func Test_FormatLocale(t *testing.T) {
+	gtest.Case(t, func() {
+		timeTemp, err := gtime.StrToTime("2006-01-11 09:04:05", "Y-m-d H:i:s")
+		gtest.Assert(err, nil)
+		gtest.Assert(timeTemp.FormatLocale("l, F jS Y A", "en"), "Wednesday, January 11th 2006 AM")
+		gtest.Assert(timeTemp.FormatLocale("Y年n月j日 l a g:i", "zh-CN"), "2006年1月11日 星期三 上午 9:04")
+		gtest.Assert(timeTemp.FormatLocale("D, j. F Y", "de_AT"), "Mi, 11. Januar 2006")
+		gtest.Assert(timeTemp.FormatLocale("l j M Y", "fr"), "mercredi 11 janv. 2006")
+		gtest.Assert(timeTemp.FormatLocale("jS", "ja"), "11")
+		// Unknown locale falls back to English.
+		gtest.Assert(timeTemp.FormatLocale("D M", "xx"), "Wed Jan")
+		gtest.Assert(timeTemp.FormatLocale("Y-m-d H:i:s", "zh-CN"), timeTemp.Format("Y-m-d H:i:s"))
+
+		gtime.RegisterLocale("it", &gtime.Locale{
+			Months:   [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+			Weekdays: [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		})
+		gtest.Assert(timeTemp.FormatLocale("l j F", "it-IT"), "mercoledì 11 gennaio")
+	})
+}
@@ -58,6 +58,7 @@ func (w *wheel) addEntry(interval time.Duration, job JobFunc, singleton bool, ti
 	}
 	// 安装任务
 	w.slots[(ticks+num)%w.number].PushBack(entry)
+	w.timer.wake()
 	return entry
 }
 
@@ -82,6 +83,7 @@ func (w *wheel) addEntryByParent(interval int64, parent *Entry) *Entry {
 		rawIntervalMs: parent.rawIntervalMs,
 	}
 	w.slots[(ticks+num)%w.number].PushBack(entry)
+	w.timer.wake()
 	return entry
 }
 
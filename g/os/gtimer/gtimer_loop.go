@@ -12,16 +12,27 @@ import (
 	"github.com/gf/g/container/glist"
 )
 
-// 开始循环
+// 开始循环。
+// 定时器空闲(没有任何任务)时停止转动，直到添加任务时被唤醒后继续转动，以减少空闲时的CPU占用。
+// 停止转动期间时间轮的刻度不会增加，新添加的任务从唤醒时开始计时，因此不影响任务的执行时间。
 func (w *wheel) start() {
 	go func() {
-		ticker := time.NewTicker(time.Duration(w.intervalMs) * time.Millisecond)
+		interval := time.Duration(w.intervalMs) * time.Millisecond
+		ticker := time.NewTicker(interval)
 		for {
 			select {
 			case <-ticker.C:
 				switch w.timer.status.Val() {
 				case STATUS_RUNNING:
 					w.proceed()
+					if w.timer.isIdle() {
+						ticker.Stop()
+						<-w.timer.wakeChan
+						if w.timer.status.Val() == STATUS_CLOSED {
+							return
+						}
+						ticker = time.NewTicker(interval)
+					}
 
 				case STATUS_STOPPED:
 				case STATUS_CLOSED:
@@ -20,6 +20,7 @@ type Timer struct {
 	length     int        // 分层层数
 	number     int        // 每一层Slot Number
 	intervalMs int64      // 最小时间刻度(毫秒)
+	wakeChan   chan bool  // 唤醒空闲定时器的通道(缓冲大小为1)
 }
 
 // 单层时间轮
@@ -46,6 +47,7 @@ func New(slot int, interval time.Duration, level ...int) *Timer {
 		length:     length,
 		number:     slot,
 		intervalMs: interval.Nanoseconds() / 1e6,
+		wakeChan:   make(chan bool, 1),
 	}
 	for i := 0; i < length; i++ {
 		if i > 0 {
@@ -152,6 +154,26 @@ func (t *Timer) Stop() {
 // 关闭定时器
 func (t *Timer) Close() {
 	t.status.Set(STATUS_CLOSED)
+	t.wake()
+}
+
+// 唤醒空闲状态下停止转动的定时器，定时器非空闲时不产生影响
+func (t *Timer) wake() {
+	select {
+	case t.wakeChan <- true:
+	default:
+	}
+}
+
+// 判断定时器是否空闲，即时间轮中除了驱动上层时间轮转动的内部任务之外没有其他任务
+func (t *Timer) isIdle() bool {
+	count := 0
+	for _, w := range t.wheels {
+		for _, l := range w.slots {
+			count += l.Len()
+		}
+	}
+	return count <= t.length-1
 }
 
 // 添加定时任务
@@ -110,6 +110,34 @@ func TestTimer_AddOnce(t *testing.T) {
 	})
 }
 
+func TestTimer_Idle(t *testing.T) {
+	gtest.Case(t, func() {
+		timer := New()
+		array := garray.New()
+		timer.AddOnce(50*time.Millisecond, func() {
+			array.Append(1)
+		})
+		time.Sleep(100 * time.Millisecond)
+		gtest.Assert(array.Len(), 1)
+		// 空闲后添加的任务从添加时开始计时
+		time.Sleep(200 * time.Millisecond)
+		timer.AddOnce(100*time.Millisecond, func() {
+			array.Append(1)
+		})
+		time.Sleep(50 * time.Millisecond)
+		gtest.Assert(array.Len(), 1)
+		time.Sleep(100 * time.Millisecond)
+		gtest.Assert(array.Len(), 2)
+		// 空闲后添加的循环任务
+		timer.Add(100*time.Millisecond, func() {
+			array.Append(1)
+		})
+		time.Sleep(250 * time.Millisecond)
+		gtest.Assert(array.Len(), 4)
+		timer.Close()
+	})
+}
+
 func TestTimer_AddTimes(t *testing.T) {
 	gtest.Case(t, func() {
 		timer := New()
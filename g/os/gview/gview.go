@@ -30,6 +30,12 @@ type View struct {
 	delimiters []string               // Customized template delimiters.
 }
 
+const (
+	// Default template delimiters.
+	gDEFAULT_LEFT_DELIMITER  = "{{"
+	gDEFAULT_RIGHT_DELIMITER = "}}"
+)
+
 // Params is type for template params.
 type Params = map[string]interface{}
 
@@ -88,7 +94,7 @@ func New(path ...string) *View {
 			}
 		}
 	}
-	view.SetDelimiters("{{", "}}")
+	view.SetDelimiters(gDEFAULT_LEFT_DELIMITER, gDEFAULT_RIGHT_DELIMITER)
 	// default build-in variables.
 	view.data["GF"] = map[string]interface{}{
 		"version": gf.VERSION,
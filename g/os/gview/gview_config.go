@@ -6,6 +6,14 @@
 
 package gview
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gf/g/util/gconv"
+)
+
 // Assign binds multiple template variables to current view object.
 // Each goroutine will take effect after the call, so it is concurrent-safe.
 func (view *View) Assigns(data Params) {
@@ -32,6 +40,41 @@ func (view *View) SetDelimiters(left, right string) {
 	view.mu.Unlock()
 }
 
+// SetConfigWithMap sets the configurations of the view with map, which is usually the "viewer" node
+// of the configuration file. The supported keys are:
+// paths:      Template directory paths, the first one of which replaces the current paths.
+// delimiters: Template delimiters, like ["[[", "]]"] or "[[ ]]".
+// data:       Global template variables.
+func (view *View) SetConfigWithMap(m map[string]interface{}) error {
+	if v, ok := m["paths"]; ok {
+		for i, path := range gconv.Strings(v) {
+			var err error
+			if i == 0 {
+				err = view.SetPath(path)
+			} else {
+				err = view.AddPath(path)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if v, ok := m["delimiters"]; ok {
+		delimiters := gconv.Strings(v)
+		if s, ok := v.(string); ok {
+			delimiters = strings.Fields(s)
+		}
+		if len(delimiters) != 2 || delimiters[0] == "" || delimiters[1] == "" {
+			return errors.New(fmt.Sprintf(`[gview] invalid delimiters "%v", which should be a pair of left and right delimiters`, v))
+		}
+		view.SetDelimiters(delimiters[0], delimiters[1])
+	}
+	if v, ok := m["data"]; ok {
+		view.Assigns(gconv.Map(v))
+	}
+	return nil
+}
+
 // BindFunc registers customized template function named <name>
 // with given function <function> to current view object.
 // The <name> is the function name which can be called in template content.
@@ -37,10 +37,11 @@ var (
 
 // getTemplate returns the template object associated with given template folder <path>.
 // It uses template cache to enhance performance, that is, it will return the same template object
-// with the same given <path>. It will also refresh the template cache
+// with the same given <path> and delimiters. It will also refresh the template cache
 // if the template files under <path> changes (recursively).
 func (view *View) getTemplate(path string, pattern string) (tpl *template.Template, err error) {
-	r := templates.GetOrSetFuncLock(path, func() interface{} {
+	key := view.templateKey(path)
+	r := templates.GetOrSetFuncLock(key, func() interface{} {
 		files := ([]string)(nil)
 		files, err = gfile.ScanDir(path, pattern, true)
 		if err != nil {
@@ -51,7 +52,7 @@ func (view *View) getTemplate(path string, pattern string) (tpl *template.Templa
 			return nil
 		}
 		_, _ = gfsnotify.Add(path, func(event *gfsnotify.Event) {
-			templates.Remove(path)
+			templates.Remove(key)
 			gfsnotify.Exit()
 		})
 		return tpl
@@ -62,6 +63,15 @@ func (view *View) getTemplate(path string, pattern string) (tpl *template.Templa
 	return
 }
 
+// templateKey returns the cache key of template <name>, which contains the delimiters,
+// so that the views with different delimiters never share the parsed templates.
+func (view *View) templateKey(name string) string {
+	if view.delimiters[0] == gDEFAULT_LEFT_DELIMITER && view.delimiters[1] == gDEFAULT_RIGHT_DELIMITER {
+		return name
+	}
+	return fmt.Sprintf("%s(%s %s)", name, view.delimiters[0], view.delimiters[1])
+}
+
 // searchFile returns the found absolute path for <file>, and its template folder path.
 func (view *View) searchFile(file string) (path string, folder string, err error) {
 	view.paths.RLockFunc(func(array []string) {
@@ -162,7 +172,7 @@ func (view *View) ParseContent(content string, params ...Params) (string, error)
 	view.mu.RLock()
 	defer view.mu.RUnlock()
 	err := (error)(nil)
-	tpl := templates.GetOrSetFuncLock(gCONTENT_TEMPLATE_NAME, func() interface{} {
+	tpl := templates.GetOrSetFuncLock(view.templateKey(gCONTENT_TEMPLATE_NAME), func() interface{} {
 		return template.New(gCONTENT_TEMPLATE_NAME).Delims(view.delimiters[0], view.delimiters[1]).Funcs(view.funcMap)
 	}).(*template.Template)
 	// Using memory lock to ensure concurrent safety for content parsing.
@@ -238,3 +238,40 @@ func TestView_ParseContent(t *testing.T) {
 		gtest.Assert(result, ``)
 	})
 }
+
+func TestView_Delimiters(t *testing.T) {
+	gtest.Case(t, func() {
+		templatePath := os.TempDir() + gfile.Separator + "gview_delimiters"
+		gfile.Mkdir(templatePath)
+		defer gfile.Remove(templatePath)
+		ioutil.WriteFile(templatePath+gfile.Separator+"layout.html", []byte(`[[include "main.html" .]]<div id="app">{{ message }}</div>[[template "footer.html" .]]`), 0644)
+		ioutil.WriteFile(templatePath+gfile.Separator+"main.html", []byte(`<h1>[[.name]]</h1>`), 0644)
+		ioutil.WriteFile(templatePath+gfile.Separator+"footer.html", []byte(`<p>{{ footer }}</p>`), 0644)
+
+		view := gview.New(templatePath)
+		err := view.SetConfigWithMap(g.Map{
+			"delimiters": "[[ ]]",
+			"data":       g.Map{"name": "gf"},
+		})
+		gtest.Assert(err, nil)
+		result, err := view.Parse("layout.html")
+		gtest.Assert(err, nil)
+		gtest.Assert(result, `<h1>gf</h1><div id="app">{{ message }}</div><p>{{ footer }}</p>`)
+		result, err = view.ParseContent(`[[.name]] {{ name }}`)
+		gtest.Assert(err, nil)
+		gtest.Assert(result, `gf {{ name }}`)
+
+		// The templates parsed with different delimiters are not shared.
+		view2 := gview.New(templatePath)
+		view2.SetDelimiters("<%", "%>")
+		result, err = view2.Parse("main.html", g.Map{"name": "gf"})
+		gtest.Assert(err, nil)
+		gtest.Assert(result, `<h1>[[.name]]</h1>`)
+		result, err = gview.New().ParseContent(`[[.name]] {{.name}}`, g.Map{"name": "gf"})
+		gtest.Assert(err, nil)
+		gtest.Assert(result, `[[.name]] gf`)
+
+		err = view.SetConfigWithMap(g.Map{"delimiters": g.Slice{"<%"}})
+		gtest.AssertNE(err, nil)
+	})
+}
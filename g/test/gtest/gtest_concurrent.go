@@ -0,0 +1,75 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Concurrent spawns <workers> goroutines executing <f> at the same time,
+// and marks <t> failed with all the errors returned or panicked by <f>.
+// The parameter <index> of <f> is the index of the worker, which is in [0, workers).
+//
+// Assertions like Assert can be used in <f>, their failures are collected as errors.
+// It is recommended running the test with the "-race" option.
+func Concurrent(t *testing.T, workers int, f func(index int) error) {
+	for _, err := range RunConcurrent(workers, f) {
+		t.Error(err)
+	}
+}
+
+// ConcurrentLoop is like Concurrent, but each worker executes <f> <loops> times.
+// The parameter <loop> of <f> is the loop index of the worker, which is in [0, loops).
+// A worker stops looping at the first error.
+func ConcurrentLoop(t *testing.T, workers int, loops int, f func(index int, loop int) error) {
+	Concurrent(t, workers, func(index int) error {
+		for i := 0; i < loops; i++ {
+			if err := f(index, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RunConcurrent spawns <workers> goroutines executing <f> at the same time,
+// waits for all of them done, and returns the errors returned or panicked by <f>
+// in worker index order. It returns nil if all workers succeed.
+func RunConcurrent(workers int, f func(index int) error) []error {
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, workers)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			defer func() {
+				if e := recover(); e != nil {
+					errs[index] = fmt.Errorf("worker %d panics: %v\n%s", index, e, getBacktrace())
+				}
+			}()
+			// Waiting for all workers ready, to make them run as concurrently as possible.
+			<-start
+			if err := f(index); err != nil {
+				errs[index] = fmt.Errorf("worker %d: %v", index, err)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	result := ([]error)(nil)
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+)
+
+const (
+	dataLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	dataDigits  = "0123456789"
+)
+
+// DataItem is the struct type of the generated benchmark data.
+type DataItem struct {
+	Id     int               `json:"id"`
+	Name   string            `json:"name"`
+	Score  float64           `json:"score"`
+	Passed bool              `json:"passed"`
+	Tags   []string          `json:"tags"`
+	Extra  map[string]string `json:"extra"`
+}
+
+// DataIntMap generates and returns a map with <n> random int values,
+// the keys of which are [0, n).
+func DataIntMap(n int) map[int]int {
+	m := make(map[int]int, n)
+	for i := 0; i < n; i++ {
+		m[i] = rand.Intn(n + 1)
+	}
+	return m
+}
+
+// DataStrMap generates and returns a map with <n> random string values,
+// the keys of which are "key_0", "key_1", ..., see DataKey.
+func DataStrMap(n int) map[string]string {
+	m := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		m[DataKey(i)] = dataString(16, dataLetters+dataDigits)
+	}
+	return m
+}
+
+// DataMap generates and returns a map with <n> random values of mixed types,
+// the keys of which are "key_0", "key_1", ..., see DataKey.
+func DataMap(n int) map[string]interface{} {
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		switch i % 4 {
+		case 0:
+			m[DataKey(i)] = rand.Intn(n + 1)
+		case 1:
+			m[DataKey(i)] = dataString(16, dataLetters+dataDigits)
+		case 2:
+			m[DataKey(i)] = float64(rand.Intn(10000)) / 100
+		default:
+			m[DataKey(i)] = rand.Intn(2) == 0
+		}
+	}
+	return m
+}
+
+// DataKey returns the key of index <i> used by the generated maps.
+func DataKey(i int) string {
+	return fmt.Sprintf("key_%d", i)
+}
+
+// DataStructs generates and returns <n> random DataItem, the ids of which are [1, n].
+func DataStructs(n int) []*DataItem {
+	items := make([]*DataItem, n)
+	for i := 0; i < n; i++ {
+		items[i] = &DataItem{
+			Id:     i + 1,
+			Name:   dataString(8, dataLetters),
+			Score:  float64(rand.Intn(10000)) / 100,
+			Passed: rand.Intn(2) == 0,
+			Tags:   []string{dataString(4, dataLetters), dataString(4, dataLetters)},
+			Extra:  map[string]string{"code": dataString(6, dataDigits)},
+		}
+	}
+	return items
+}
+
+// DataJson generates and returns JSON content of a array with <n> random DataItem, see DataStructs.
+func DataJson(n int) []byte {
+	b, err := json.Marshal(DataStructs(n))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// dataString returns a random string of length <n> with characters in <chars>.
+func dataString(n int, chars string) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = chars[rand.Intn(len(chars))]
+	}
+	return string(b)
+}
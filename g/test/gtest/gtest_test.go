@@ -7,6 +7,10 @@
 package gtest_test
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gogf/gf/g/test/gtest"
@@ -18,3 +22,64 @@ func TestCase(t *testing.T) {
 		gtest.AssertNE(1, 0)
 	})
 }
+
+func TestConcurrent(t *testing.T) {
+	gtest.Case(t, func() {
+		var (
+			mu    sync.Mutex
+			count = 0
+		)
+		gtest.ConcurrentLoop(t, 10, 100, func(index int, loop int) error {
+			mu.Lock()
+			count++
+			mu.Unlock()
+			return nil
+		})
+		gtest.Assert(count, 1000)
+	})
+	gtest.Case(t, func() {
+		errs := gtest.RunConcurrent(10, func(index int) error {
+			switch index {
+			case 1:
+				return errors.New("error")
+			case 2:
+				gtest.Assert(index, 0)
+			case 3:
+				panic("panic")
+			}
+			return nil
+		})
+		gtest.Assert(len(errs), 3)
+		gtest.Assert(strings.HasPrefix(errs[0].Error(), "worker 1: error"), true)
+		gtest.Assert(strings.Contains(errs[1].Error(), "[ASSERT]"), true)
+		gtest.Assert(strings.HasPrefix(errs[2].Error(), "worker 3 panics: panic"), true)
+
+		gtest.Assert(gtest.RunConcurrent(10, func(index int) error { return nil }), nil)
+	})
+}
+
+func TestData(t *testing.T) {
+	gtest.Case(t, func() {
+		gtest.Assert(len(gtest.DataIntMap(100)), 100)
+		gtest.Assert(len(gtest.DataStrMap(100)), 100)
+		m := gtest.DataMap(100)
+		gtest.Assert(len(m), 100)
+		_, ok := m[gtest.DataKey(99)]
+		gtest.Assert(ok, true)
+
+		items := gtest.DataStructs(10)
+		gtest.Assert(len(items), 10)
+		gtest.Assert(items[9].Id, 10)
+
+		var decoded []*gtest.DataItem
+		gtest.Assert(json.Unmarshal(gtest.DataJson(10), &decoded), nil)
+		gtest.Assert(len(decoded), 10)
+		gtest.Assert(decoded[0].Id, 1)
+	})
+}
+
+func BenchmarkDataJson(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gtest.DataJson(100)
+	}
+}
@@ -8,81 +8,155 @@ package gconv
 
 import (
 	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
 
 	"github.com/gf/g/text/gstr"
 )
 
+// parallelThreshold is the minimum length of the slice which is converted in parallel chunks,
+// which is 0 in default that means parallel conversion is disabled.
+var parallelThreshold int64
+
+// SetParallelThreshold enables parallel conversion for slices with length not less than <n>,
+// which splits the slice into chunks and converts them in multiple goroutines.
+// It only affects conversions of which each element needs parsing, eg: []string or []interface{},
+// and it's disabled if <n> <= 0. Note that it only benefits very large slices,
+// as there's cost for goroutine scheduling.
+func SetParallelThreshold(n int) {
+	if n < 0 {
+		n = 0
+	}
+	atomic.StoreInt64(&parallelThreshold, int64(n))
+}
+
+// convertInParallel calls <f> with chunks of [0, length) in parallel goroutines if <length> reaches
+// the parallel threshold, or else it calls <f> with the whole range in current goroutine.
+func convertInParallel(length int, f func(start, end int)) {
+	threshold := int(atomic.LoadInt64(&parallelThreshold))
+	chunks := runtime.GOMAXPROCS(0)
+	if threshold <= 0 || length < threshold || chunks < 2 {
+		f(0, length)
+		return
+	}
+	size := (length + chunks - 1) / chunks
+	wg := sync.WaitGroup{}
+	for start := 0; start < length; start += size {
+		end := start + size
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			f(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// stringToInt converts decimal string <s> to int without interface boxing,
+// and it falls back to Int for other formats, eg: hexadecimal, octal or float strings.
+func stringToInt(s string) int {
+	if len(s) > 0 && (s[0] != '0' || len(s) == 1) {
+		if v, err := strconv.Atoi(s); err == nil {
+			return v
+		}
+	}
+	return Int(s)
+}
+
 // Ints converts <i> to []int.
 func Ints(i interface{}) []int {
 	if i == nil {
 		return nil
 	}
-	if r, ok := i.([]int); ok {
-		return r
-	} else {
-		array := make([]int, 0)
-		switch i.(type) {
-		case []string:
-			for _, v := range i.([]string) {
-				array = append(array, Int(v))
-			}
-		case []int8:
-			for _, v := range i.([]int8) {
-				array = append(array, Int(v))
-			}
-		case []int16:
-			for _, v := range i.([]int16) {
-				array = append(array, Int(v))
-			}
-		case []int32:
-			for _, v := range i.([]int32) {
-				array = append(array, Int(v))
-			}
-		case []int64:
-			for _, v := range i.([]int64) {
-				array = append(array, Int(v))
-			}
-		case []uint:
-			for _, v := range i.([]uint) {
-				array = append(array, Int(v))
-			}
-		case []uint8:
-			for _, v := range i.([]uint8) {
-				array = append(array, Int(v))
-			}
-		case []uint16:
-			for _, v := range i.([]uint16) {
-				array = append(array, Int(v))
-			}
-		case []uint32:
-			for _, v := range i.([]uint32) {
-				array = append(array, Int(v))
-			}
-		case []uint64:
-			for _, v := range i.([]uint64) {
-				array = append(array, Int(v))
-			}
-		case []bool:
-			for _, v := range i.([]bool) {
-				array = append(array, Int(v))
-			}
-		case []float32:
-			for _, v := range i.([]float32) {
-				array = append(array, Int(v))
-			}
-		case []float64:
-			for _, v := range i.([]float64) {
-				array = append(array, Int(v))
-			}
-		case []interface{}:
-			for _, v := range i.([]interface{}) {
-				array = append(array, Int(v))
-			}
-		default:
-			return []int{Int(i)}
+	var array []int
+	switch value := i.(type) {
+	case []int:
+		return value
+	case []string:
+		array = make([]int, len(value))
+		convertInParallel(len(value), func(start, end int) {
+			for k := start; k < end; k++ {
+				v := value[k]
+				array[k] = stringToInt(v)
+			}
+		})
+	case []int8:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []int16:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []int32:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []int64:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []uint:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
 		}
-		return array
+	case []uint8:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []uint16:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []uint32:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []uint64:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []float32:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []float64:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = int(v)
+		}
+	case []bool:
+		array = make([]int, len(value))
+		for k, v := range value {
+			array[k] = Int(v)
+		}
+	case []interface{}:
+		array = make([]int, len(value))
+		convertInParallel(len(value), func(start, end int) {
+			for k := start; k < end; k++ {
+				v := value[k]
+				array[k] = Int(v)
+			}
+		})
+	default:
+		return []int{Int(i)}
 	}
+	return array
 }
 
 // Strings converts <i> to []string.
@@ -90,145 +164,178 @@ func Strings(i interface{}) []string {
 	if i == nil {
 		return nil
 	}
-	if r, ok := i.([]string); ok {
-		return r
-	} else {
-		array := make([]string, 0)
-		switch i.(type) {
-		case []int:
-			for _, v := range i.([]int) {
-				array = append(array, String(v))
-			}
-		case []int8:
-			for _, v := range i.([]int8) {
-				array = append(array, String(v))
-			}
-		case []int16:
-			for _, v := range i.([]int16) {
-				array = append(array, String(v))
-			}
-		case []int32:
-			for _, v := range i.([]int32) {
-				array = append(array, String(v))
-			}
-		case []int64:
-			for _, v := range i.([]int64) {
-				array = append(array, String(v))
-			}
-		case []uint:
-			for _, v := range i.([]uint) {
-				array = append(array, String(v))
-			}
-		case []uint8:
-			for _, v := range i.([]uint8) {
-				array = append(array, String(v))
-			}
-		case []uint16:
-			for _, v := range i.([]uint16) {
-				array = append(array, String(v))
-			}
-		case []uint32:
-			for _, v := range i.([]uint32) {
-				array = append(array, String(v))
-			}
-		case []uint64:
-			for _, v := range i.([]uint64) {
-				array = append(array, String(v))
-			}
-		case []bool:
-			for _, v := range i.([]bool) {
-				array = append(array, String(v))
-			}
-		case []float32:
-			for _, v := range i.([]float32) {
-				array = append(array, String(v))
-			}
-		case []float64:
-			for _, v := range i.([]float64) {
-				array = append(array, String(v))
-			}
-		case []interface{}:
-			for _, v := range i.([]interface{}) {
-				array = append(array, String(v))
-			}
-		default:
-			return []string{String(i)}
+	var array []string
+	switch value := i.(type) {
+	case []string:
+		return value
+	case []int:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.Itoa(int(v))
+		}
+	case []int8:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.Itoa(int(v))
+		}
+	case []int16:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.Itoa(int(v))
+		}
+	case []int32:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.Itoa(int(v))
+		}
+	case []int64:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatInt(v, 10)
+		}
+	case []uint:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatUint(uint64(v), 10)
+		}
+	case []uint8:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatUint(uint64(v), 10)
+		}
+	case []uint16:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatUint(uint64(v), 10)
+		}
+	case []uint32:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatUint(uint64(v), 10)
+		}
+	case []uint64:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatUint(v, 10)
 		}
-		return array
+	case []bool:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatBool(v)
+		}
+	case []float32:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatFloat(float64(v), 'f', -1, 32)
+		}
+	case []float64:
+		array = make([]string, len(value))
+		for k, v := range value {
+			array[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	case []interface{}:
+		array = make([]string, len(value))
+		convertInParallel(len(value), func(start, end int) {
+			for k := start; k < end; k++ {
+				v := value[k]
+				array[k] = String(v)
+			}
+		})
+	default:
+		return []string{String(i)}
 	}
+	return array
 }
 
-// Strings converts <i> to []float64.
+// Floats converts <i> to []float64.
 func Floats(i interface{}) []float64 {
 	if i == nil {
 		return nil
 	}
-	if r, ok := i.([]float64); ok {
-		return r
-	} else {
-		array := make([]float64, 0)
-		switch i.(type) {
-		case []string:
-			for _, v := range i.([]string) {
-				array = append(array, Float64(v))
-			}
-		case []int:
-			for _, v := range i.([]int) {
-				array = append(array, Float64(v))
-			}
-		case []int8:
-			for _, v := range i.([]int8) {
-				array = append(array, Float64(v))
-			}
-		case []int16:
-			for _, v := range i.([]int16) {
-				array = append(array, Float64(v))
-			}
-		case []int32:
-			for _, v := range i.([]int32) {
-				array = append(array, Float64(v))
-			}
-		case []int64:
-			for _, v := range i.([]int64) {
-				array = append(array, Float64(v))
-			}
-		case []uint:
-			for _, v := range i.([]uint) {
-				array = append(array, Float64(v))
-			}
-		case []uint8:
-			for _, v := range i.([]uint8) {
-				array = append(array, Float64(v))
-			}
-		case []uint16:
-			for _, v := range i.([]uint16) {
-				array = append(array, Float64(v))
-			}
-		case []uint32:
-			for _, v := range i.([]uint32) {
-				array = append(array, Float64(v))
-			}
-		case []uint64:
-			for _, v := range i.([]uint64) {
-				array = append(array, Float64(v))
-			}
-		case []bool:
-			for _, v := range i.([]bool) {
-				array = append(array, Float64(v))
-			}
-		case []float32:
-			for _, v := range i.([]float32) {
-				array = append(array, Float64(v))
-			}
-		case []interface{}:
-			for _, v := range i.([]interface{}) {
-				array = append(array, Float64(v))
-			}
-		default:
-			return []float64{Float64(i)}
+	var array []float64
+	switch value := i.(type) {
+	case []float64:
+		return value
+	case []string:
+		array = make([]float64, len(value))
+		convertInParallel(len(value), func(start, end int) {
+			for k := start; k < end; k++ {
+				v := value[k]
+				array[k] = Float64(v)
+			}
+		})
+	case []int:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []int8:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []int16:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []int32:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []int64:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []uint:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
 		}
-		return array
+	case []uint8:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []uint16:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []uint32:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []uint64:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = float64(v)
+		}
+	case []bool:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = Float64(v)
+		}
+	case []float32:
+		array = make([]float64, len(value))
+		for k, v := range value {
+			array[k] = Float64(v)
+		}
+	case []interface{}:
+		array = make([]float64, len(value))
+		convertInParallel(len(value), func(start, end int) {
+			for k := start; k < end; k++ {
+				v := value[k]
+				array[k] = Float64(v)
+			}
+		})
+	default:
+		return []float64{Float64(i)}
 	}
+	return array
 }
 
 // Interfaces converts <i> to []interface{}.
@@ -236,98 +343,112 @@ func Interfaces(i interface{}) []interface{} {
 	if i == nil {
 		return nil
 	}
-	if r, ok := i.([]interface{}); ok {
-		return r
-	} else {
-		array := make([]interface{}, 0)
-		switch i.(type) {
-		case []string:
-			for _, v := range i.([]string) {
-				array = append(array, v)
-			}
-		case []int:
-			for _, v := range i.([]int) {
-				array = append(array, v)
-			}
-		case []int8:
-			for _, v := range i.([]int8) {
-				array = append(array, v)
-			}
-		case []int16:
-			for _, v := range i.([]int16) {
-				array = append(array, v)
-			}
-		case []int32:
-			for _, v := range i.([]int32) {
-				array = append(array, v)
-			}
-		case []int64:
-			for _, v := range i.([]int64) {
-				array = append(array, v)
-			}
-		case []uint:
-			for _, v := range i.([]uint) {
-				array = append(array, v)
-			}
-		case []uint8:
-			for _, v := range i.([]uint8) {
-				array = append(array, v)
-			}
-		case []uint16:
-			for _, v := range i.([]uint16) {
-				array = append(array, v)
-			}
-		case []uint32:
-			for _, v := range i.([]uint32) {
-				array = append(array, v)
-			}
-		case []uint64:
-			for _, v := range i.([]uint64) {
-				array = append(array, v)
-			}
-		case []bool:
-			for _, v := range i.([]bool) {
-				array = append(array, v)
-			}
-		case []float32:
-			for _, v := range i.([]float32) {
-				array = append(array, v)
-			}
-		case []float64:
-			for _, v := range i.([]float64) {
-				array = append(array, v)
-			}
-		default:
-			// Finally we use reflection.
-			rv := reflect.ValueOf(i)
-			kind := rv.Kind()
-			// If it's pointer, find the real type.
-			if kind == reflect.Ptr {
-				rv = rv.Elem()
-				kind = rv.Kind()
-			}
-			switch kind {
-			case reflect.Slice:
-				fallthrough
-			case reflect.Array:
-				for i := 0; i < rv.Len(); i++ {
-					array = append(array, rv.Index(i).Interface())
-				}
-			case reflect.Struct:
-				rt := rv.Type()
-				for i := 0; i < rv.NumField(); i++ {
-					// Only public attributes.
-					if !gstr.IsLetterUpper(rt.Field(i).Name[0]) {
-						continue
-					}
-					array = append(array, rv.Field(i).Interface())
+	var array []interface{}
+	switch value := i.(type) {
+	case []interface{}:
+		return value
+	case []string:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []int:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []int8:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []int16:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []int32:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []int64:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []uint:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []uint8:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []uint16:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []uint32:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []uint64:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []bool:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []float32:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	case []float64:
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
+		}
+	default:
+		// Finally we use reflection.
+		rv := reflect.ValueOf(i)
+		kind := rv.Kind()
+		// If it's pointer, find the real type.
+		if kind == reflect.Ptr {
+			rv = rv.Elem()
+			kind = rv.Kind()
+		}
+		switch kind {
+		case reflect.Slice:
+			fallthrough
+		case reflect.Array:
+			array = make([]interface{}, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				array[i] = rv.Index(i).Interface()
+			}
+		case reflect.Struct:
+			rt := rv.Type()
+			array = make([]interface{}, 0, rv.NumField())
+			for i := 0; i < rv.NumField(); i++ {
+				// Only public attributes.
+				if !gstr.IsLetterUpper(rt.Field(i).Name[0]) {
+					continue
 				}
-			default:
-				return []interface{}{i}
+				array = append(array, rv.Field(i).Interface())
 			}
+		default:
+			return []interface{}{i}
 		}
-		return array
 	}
+	return array
 }
 
 // Maps converts <i> to []map[string]interface{}.
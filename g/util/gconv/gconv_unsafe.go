@@ -0,0 +1,56 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gconv
+
+import "unsafe"
+
+// The String and Bytes functions always copy the content for []byte<->string conversions,
+// which is safe but allocates. The Unsafe* functions below are the opt-in zero-copy conversions
+// for hot paths, the caller must make sure of the lifetime of the shared memory as documented.
+
+// stringHeader is the runtime representation of string,
+// which keeps the data as unsafe.Pointer instead of uintptr for garbage collector.
+type stringHeader struct {
+	data unsafe.Pointer
+	len  int
+}
+
+// sliceHeader is the runtime representation of slice.
+type sliceHeader struct {
+	data unsafe.Pointer
+	len  int
+	cap  int
+}
+
+// UnsafeStrToBytes converts string <s> to []byte without memory copy.
+// The returned bytes share the memory with <s>, so they MUST NOT be modified,
+// or else it panics or breaks the immutability of the string.
+// It's safe only if the returned bytes are used as read-only, eg: writing to io.Writer or hashing.
+func UnsafeStrToBytes(s string) []byte {
+	if s == "" {
+		return nil
+	}
+	header := (*stringHeader)(unsafe.Pointer(&s))
+	// The capacity is the same as the length, so appending to the result always reallocates.
+	return *(*[]byte)(unsafe.Pointer(&sliceHeader{
+		data: header.data,
+		len:  header.len,
+		cap:  header.len,
+	}))
+}
+
+// UnsafeBytesToStr converts []byte <b> to string without memory copy.
+// The returned string shares the memory with <b>, so <b> MUST NOT be modified any more
+// after the conversion, or else the content of the string changes.
+// It's safe if <b> is exclusively owned by the caller and discarded after the conversion,
+// eg: the result of json.Marshal or bytes.Buffer that is not reused.
+func UnsafeBytesToStr(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return *(*string)(unsafe.Pointer(&b))
+}
@@ -133,3 +133,70 @@ func BenchmarkInterfaces(b *testing.B) {
 		Interfaces(value)
 	}
 }
+
+var (
+	benchBytes  = []byte("The quick brown fox jumps over the lazy dog")
+	benchString = "The quick brown fox jumps over the lazy dog"
+)
+
+func BenchmarkBytesToString(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		String(benchBytes)
+	}
+}
+
+func BenchmarkUnsafeBytesToStr(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		UnsafeBytesToStr(benchBytes)
+	}
+}
+
+func BenchmarkStringToBytes(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Bytes(benchString)
+	}
+}
+
+func BenchmarkUnsafeStrToBytes(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		UnsafeStrToBytes(benchString)
+	}
+}
+
+var (
+	benchInts    = make([]int, 100000)
+	benchStrings = make([]string, 100000)
+)
+
+func init() {
+	for i := range benchInts {
+		benchInts[i] = i
+		benchStrings[i] = String(i)
+	}
+}
+
+func BenchmarkIntsToStrings(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Strings(benchInts)
+	}
+}
+
+func BenchmarkStringsToInts(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Ints(benchStrings)
+	}
+}
+
+func BenchmarkStringsToIntsParallel(b *testing.B) {
+	SetParallelThreshold(10000)
+	defer SetParallelThreshold(0)
+	for i := 0; i < b.N; i++ {
+		Ints(benchStrings)
+	}
+}
+
+func BenchmarkIntsToInterfaces(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Interfaces(benchInts)
+	}
+}
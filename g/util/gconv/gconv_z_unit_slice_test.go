@@ -35,3 +35,45 @@ func Test_Slice_PrivateAttribute(t *testing.T) {
 		gtest.Assert(gconv.Interfaces(user), g.Slice{1})
 	})
 }
+
+func Test_Slice_Typed(t *testing.T) {
+	gtest.Case(t, func() {
+		gtest.AssertEQ(gconv.Ints([]int64{1, -2, 3}), []int{1, -2, 3})
+		gtest.AssertEQ(gconv.Ints([]float64{1.9, -2.5}), []int{1, -2})
+		gtest.AssertEQ(gconv.Ints([]bool{true, false}), []int{1, 0})
+		gtest.AssertEQ(gconv.Ints([]string{"1", "0x10", "010", "-10", " 1", "a"}), []int{1, 16, 8, -10, 1, 0})
+		gtest.AssertEQ(gconv.Ints([]string{}), []int{})
+		gtest.AssertEQ(gconv.Strings([]int{1, -2}), []string{"1", "-2"})
+		gtest.AssertEQ(gconv.Strings([]uint8{1, 2}), []string{"1", "2"})
+		gtest.AssertEQ(gconv.Strings([]float32{1.5, 0.1}), []string{"1.5", "0.1"})
+		gtest.AssertEQ(gconv.Strings([]bool{true, false}), []string{"true", "false"})
+		gtest.AssertEQ(gconv.Floats([]int{1, 2}), []float64{1, 2})
+		gtest.AssertEQ(gconv.Floats([]float32{0.1}), []float64{0.1})
+		gtest.AssertEQ(gconv.Floats([]interface{}{"1.5", 2}), []float64{1.5, 2})
+		gtest.AssertEQ(gconv.Interfaces([]int{1, 2}), []interface{}{1, 2})
+		gtest.AssertEQ(gconv.Interfaces([2]string{"a", "b"}), []interface{}{"a", "b"})
+	})
+}
+
+func Test_Slice_Parallel(t *testing.T) {
+	gconv.SetParallelThreshold(100)
+	defer gconv.SetParallelThreshold(0)
+	gtest.Case(t, func() {
+		strings := make([]string, 1001)
+		interfaces := make([]interface{}, 1001)
+		ints := make([]int, 1001)
+		for i := range strings {
+			strings[i] = gconv.String(i)
+			interfaces[i] = i
+			ints[i] = i
+		}
+		gtest.AssertEQ(gconv.Ints(strings), ints)
+		gtest.AssertEQ(gconv.Ints(interfaces), ints)
+		gtest.AssertEQ(gconv.Strings(interfaces), strings)
+		floats := gconv.Floats(strings)
+		gtest.Assert(len(floats), 1001)
+		gtest.Assert(floats[1000], 1000)
+		// Slices shorter than the threshold are converted in current goroutine.
+		gtest.AssertEQ(gconv.Ints([]string{"1", "2"}), []int{1, 2})
+	})
+}
@@ -62,3 +62,27 @@ func Test_String(t *testing.T) {
 		gtest.AssertEQ(gconv.String(&stringStruct2{"john"}), `{"Name":"john"}`)
 	})
 }
+
+func Test_Unsafe(t *testing.T) {
+	gtest.Case(t, func() {
+		s := "gf framework"
+		b := gconv.UnsafeStrToBytes(s)
+		gtest.Assert(string(b), s)
+		gtest.Assert(len(b), len(s))
+		gtest.Assert(cap(b), len(s))
+		// Appending reallocates, which does not affect the string.
+		b = append(b, '!')
+		gtest.Assert(string(b), "gf framework!")
+		gtest.Assert(s, "gf framework")
+		gtest.Assert(gconv.UnsafeStrToBytes(""), nil)
+	})
+	gtest.Case(t, func() {
+		b := []byte("gf framework")
+		s := gconv.UnsafeBytesToStr(b)
+		gtest.Assert(s, "gf framework")
+		// The string shares the memory with the bytes.
+		b[0] = 'G'
+		gtest.Assert(s, "Gf framework")
+		gtest.Assert(gconv.UnsafeBytesToStr(nil), "")
+	})
+}
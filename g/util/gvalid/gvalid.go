@@ -57,6 +57,8 @@ different            格式：different:field                       说明：参
 in                   格式：in:value1,value2,...                  说明：参数值应该在value1,value2,...中(字符串匹配)
 not-in               格式：not-in:value1,value2,...              说明：参数值不应该在value1,value2,...中(字符串匹配)
 regex                格式：regex:pattern                         说明：参数值应当满足正则匹配规则pattern
+max-size             格式：max-size:size                         说明：上传文件大小不能超过size(单位字节，支持K/M/G后缀，如：2M)，非文件参数时为字符串长度
+ext                  格式：ext:ext1,ext2,...                     说明：上传文件(或文件名)的扩展名应当在ext1,ext2,...中(不区分大小写)
 */
 
 // 自定义错误信息: map[键名] => 字符串|map[规则]错误信息
@@ -87,6 +87,8 @@ var (
 		"in":                   struct{}{},
 		"not-in":               struct{}{},
 		"regex":                struct{}{},
+		"max-size":             struct{}{},
+		"ext":                  struct{}{},
 	}
 	// 布尔Map
 	boolMap = map[string]struct{}{
@@ -201,6 +203,18 @@ func Check(value interface{}, rules string, msgs interface{}, params ...interfac
 				match = true
 			}
 
+		// 上传文件大小
+		case "max-size":
+			if msg := checkFileSize(value, val, ruleVal, customMsgMap); msg != "" {
+				errorMsgs[ruleKey] = msg
+			} else {
+				match = true
+			}
+
+		// 上传文件扩展名
+		case "ext":
+			match = checkFileExt(val, ruleVal)
+
 		// 自定义正则判断
 		case "regex":
 			// 需要判断是否被|符号截断，如果是，那么需要进行整合
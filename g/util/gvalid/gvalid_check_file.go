@@ -0,0 +1,78 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gvalid
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 上传文件接口，实现了该接口的参数(如：*ghttp.UploadFile)可以使用max-size、ext等文件校验规则。
+// 文件参数的字符串值应当为文件名称(通过String方法返回)。
+type apiFileSize interface {
+	GetSize() int64
+}
+
+// 对上传文件大小进行检测，非文件参数时使用字符串长度进行检测
+func checkFileSize(value interface{}, val string, ruleVal string, customMsgMap map[string]string) string {
+	max, err := parseSize(ruleVal)
+	if err != nil {
+		return "校验参数[" + ruleVal + "]应当为文件大小，如：1024、512K、2M"
+	}
+	size := int64(len(val))
+	if f, ok := value.(apiFileSize); ok {
+		size = f.GetSize()
+	}
+	if size <= max {
+		return ""
+	}
+	msg := customMsgMap["max-size"]
+	if msg == "" {
+		msg = errorMsgMap.Get("max-size")
+	}
+	return strings.Replace(msg, ":max", ruleVal, -1)
+}
+
+// 对文件扩展名进行检测，不区分大小写，扩展名可以带或者不带"."
+func checkFileExt(val string, ruleVal string) bool {
+	ext := strings.TrimPrefix(filepath.Ext(val), ".")
+	if ext == "" {
+		return false
+	}
+	for _, v := range strings.Split(ruleVal, ",") {
+		if strings.EqualFold(ext, strings.TrimPrefix(strings.TrimSpace(v), ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// 解析文件大小，支持K/M/G后缀(1024进制)，如：512K、2M、1G。
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimSuffix(s, "B")
+	unit := int64(1)
+	if len(s) > 0 {
+		switch s[len(s)-1] {
+		case 'K':
+			unit = 1024
+		case 'M':
+			unit = 1024 * 1024
+		case 'G':
+			unit = 1024 * 1024 * 1024
+		}
+		if unit > 1 {
+			s = s[:len(s)-1]
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * unit, nil
+}
@@ -52,6 +52,8 @@ var defaultMessages = map[string]string{
 	"in":                   "字段值不合法",
 	"not-in":               "字段值不合法",
 	"regex":                "字段值不合法",
+	"max-size":             "文件大小不能超过:max",
+	"ext":                  "文件类型不合法",
 }
 
 // 初始化错误消息管理对象
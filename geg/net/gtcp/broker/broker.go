@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gogf/gf/g/net/gtcp"
+)
+
+func main() {
+	// Broker
+	broker := gtcp.NewBroker("127.0.0.1:8999")
+	go broker.Run()
+	defer broker.Close()
+
+	time.Sleep(time.Second)
+
+	// Subscriber
+	subscriber, err := gtcp.NewBrokerClient("127.0.0.1:8999")
+	if err != nil {
+		panic(err)
+	}
+	defer subscriber.Close()
+	subscriber.Subscribe("news", func(topic string, data []byte) {
+		fmt.Println("receive:", topic, string(data))
+	})
+
+	// Publisher
+	publisher, err := gtcp.NewBrokerClient("127.0.0.1:8999")
+	if err != nil {
+		panic(err)
+	}
+	defer publisher.Close()
+	// Making sure the subscription is handled by the broker.
+	subscriber.Ping(time.Second)
+	for i := 0; i < 10; i++ {
+		publisher.Publish("news", []byte(fmt.Sprintf("message %d", i)))
+	}
+
+	time.Sleep(time.Second)
+}